@@ -0,0 +1,68 @@
+package httpcord
+
+// Allowed Mention Types
+
+const (
+	UsersAllowedMention    = "users"
+	RolesAllowedMention    = "roles"
+	EveryoneAllowedMention = "everyone"
+)
+
+// SuppressAll AllowedMentions pinging nobody, the mentions are still rendered
+func SuppressAll() *AllowedMentions {
+	return &AllowedMentions{Parse: []string{}}
+}
+
+// OnlyUsers AllowedMentions pinging the users only, @everyone and the roles are not pinged
+func OnlyUsers(ids ...Snowflake) *AllowedMentions {
+	return &AllowedMentions{Parse: []string{}, Users: ids}
+}
+
+// OnlyRoles AllowedMentions pinging the roles only
+func OnlyRoles(ids ...Snowflake) *AllowedMentions {
+	return &AllowedMentions{Parse: []string{}, Roles: ids}
+}
+
+// ParseMentions AllowedMentions pinging the mentions of the types found in the content, like UsersAllowedMention
+func ParseMentions(types ...string) *AllowedMentions {
+	return &AllowedMentions{Parse: append([]string{}, types...)}
+}
+
+// SetRepliedUser Ping the author of the message replied to
+func (a *AllowedMentions) SetRepliedUser(replied bool) *AllowedMentions {
+	a.RepliedUser = replied
+	return a
+}
+
+// responseMentions Response with ConnectionOptions.AllowedMentions when its message has none, the data of the
+// handler is copied
+func (o *ConnectionOptions) responseMentions(response *InteractionResponse) *InteractionResponse {
+	if o.AllowedMentions == nil || response == nil || response.Data == nil || response.Data.AllowedMentions != nil {
+		return response
+	}
+
+	switch response.Type {
+	case ChannelMessageWithSourceResponse, UpdateMessageResponse:
+	default:
+		return response
+	}
+
+	data := *response.Data
+	data.AllowedMentions = o.AllowedMentions
+
+	copied := *response
+	copied.Data = &data
+	return &copied
+}
+
+// webhookMentions Edit or follow-up with ConnectionOptions.AllowedMentions when it has none, the data of the handler
+// is copied
+func (o *ConnectionOptions) webhookMentions(data *WebhookEdit) *WebhookEdit {
+	if o.AllowedMentions == nil || data == nil || data.AllowedMentions != nil {
+		return data
+	}
+
+	copied := *data
+	copied.AllowedMentions = o.AllowedMentions
+	return &copied
+}
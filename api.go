@@ -1,19 +1,26 @@
 package httpcord
 
+import "encoding/json"
+
 type APIInteraction struct {
 	ID             string          `json:"id"`
 	ApplicationID  string          `json:"application_id"`
 	Type           InteractionType `json:"type"`
-	Data           interface{}     `json:"data"`
+	Data           json.RawMessage `json:"data"`
 	GuildID        string          `json:"guild_id,omitempty"`
 	ChannelID      string          `json:"channel_id,omitempty"`
 	Member         *APIMember      `json:"member,omitempty"`
 	User           *APIUser        `json:"user,omitempty"`
 	Token          string          `json:"token"`
 	Version        int             `json:"version"`
-	Message        *APIMessage     `json:"message,omitempty"`
+	Message        *Message        `json:"message,omitempty"`
 	AppPermissions string          `json:"app_permissions,omitempty"`
 	Locale         string          `json:"locale,omitempty"`
+	GuildLocale    string          `json:"guild_locale,omitempty"`
+	Entitlements   []*Entitlement  `json:"entitlements,omitempty"`
+	// EntitlementSKUIDs Legacy list of the SKUs the user or guild is entitled to
+	EntitlementSKUIDs []Snowflake             `json:"entitlement_sku_ids,omitempty"`
+	Context           *InteractionContextType `json:"context,omitempty"`
 }
 
 type APIMember struct {
@@ -0,0 +1,146 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FieldError Validation error of a field of the request, from the errors tree of a DiscordAPIError
+type FieldError struct {
+	// Path is the field like "content" or "embeds[0].fields[3].value"
+	Path    string
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// parseFieldErrors Flatten the errors tree of Discord, the _errors of every object are reported under its path in
+// path order, indexes in numeric order
+func parseFieldErrors(tree json.RawMessage) []*FieldError {
+	if len(tree) == 0 {
+		return nil
+	}
+
+	var fields []*FieldError
+	walkFieldErrors(tree, "", &fields)
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return lessPath(fields[i].Path, fields[j].Path)
+	})
+
+	return fields
+}
+
+// lessPath Order of the paths with their indexes compared as numbers, "fields[3]" before "fields[10]"
+func lessPath(a, b string) bool {
+	for a != "" && b != "" {
+		na, nb := digitPrefix(a), digitPrefix(b)
+		if na > 0 && nb > 0 {
+			if na != nb {
+				return na < nb
+			}
+
+			if a[:na] != b[:nb] {
+				return a[:na] < b[:nb]
+			}
+
+			a, b = a[na:], b[nb:]
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+// digitPrefix Length of the digits starting s
+func digitPrefix(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+
+	return n
+}
+
+func walkFieldErrors(node json.RawMessage, path string, fields *[]*FieldError) {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(node, &object) != nil {
+		return
+	}
+
+	for key, value := range object {
+		if key == "_errors" {
+			var errs []*FieldError
+			if json.Unmarshal(value, &errs) == nil {
+				for _, err := range errs {
+					err.Path = path
+					*fields = append(*fields, err)
+				}
+			}
+
+			continue
+		}
+
+		switch {
+		case isNumeric(key):
+			walkFieldErrors(value, path+"["+key+"]", fields)
+		case path == "":
+			walkFieldErrors(value, key, fields)
+		default:
+			walkFieldErrors(value, path+"."+key, fields)
+		}
+	}
+}
+
+// fieldErrorsText Field errors joined for DiscordAPIError.Error
+func fieldErrorsText(fields []*FieldError) string {
+	texts := make([]string, len(fields))
+	for i, field := range fields {
+		texts[i] = field.String()
+	}
+
+	return strings.Join(texts, "; ")
+}
+
+// HasErrorCode Whether err is a DiscordAPIError with the JSON error code, like MissingPermissionsErrorCode
+func HasErrorCode(err error, code int) bool {
+	var apiErr *DiscordAPIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsUnknownInteraction Whether err is an Unknown Interaction error, the interaction expired or was already answered
+func IsUnknownInteraction(err error) bool {
+	return HasErrorCode(err, UnknownInteractionErrorCode)
+}
+
+// IsUnknownMessage Whether err is an Unknown Message error, the message was deleted
+func IsUnknownMessage(err error) bool {
+	return HasErrorCode(err, UnknownMessageErrorCode)
+}
+
+// IsMissingPermissions Whether err is a Missing Permissions or Missing Access error of the application
+func IsMissingPermissions(err error) bool {
+	return HasErrorCode(err, MissingPermissionsErrorCode) || HasErrorCode(err, MissingAccessErrorCode)
+}
+
+// IsRateLimited Whether err is a 429 returned once RestClient.MaxRetries ran out, see DiscordAPIError.RetryAfter
+func IsRateLimited(err error) bool {
+	var apiErr *DiscordAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
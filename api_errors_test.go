@@ -0,0 +1,126 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// invalidFormBody 50035 error of Discord for a message with invalid embeds and components
+const invalidFormBody = `{"code":50035,"message":"Invalid Form Body","errors":{
+	"embeds":{"0":{"fields":{
+		"10":{"value":{"_errors":[{"code":"BASE_TYPE_REQUIRED","message":"This field is required"}]}},
+		"3":{"name":{"_errors":[{"code":"BASE_TYPE_MAX_LENGTH","message":"Must be 256 or fewer in length."}]}}
+	}}},
+	"components":{"0":{"components":{"1":{"custom_id":{"_errors":[
+		{"code":"COMPONENT_CUSTOM_ID_DUPLICATED","message":"Component custom id cannot be duplicated"},
+		{"code":"BASE_TYPE_BAD_LENGTH","message":"Must be between 1 and 100 in length."}
+	]}}}}},
+	"content":{"_errors":[{"code":"BASE_TYPE_MAX_LENGTH","message":"Must be 2000 or fewer in length."}]},
+	"_errors":[{"code":"MESSAGE_EMPTY","message":"Cannot send an empty message"}]
+}}`
+
+func TestParseFieldErrors(t *testing.T) {
+	want := []*FieldError{
+		{Path: "", Code: "MESSAGE_EMPTY", Message: "Cannot send an empty message"},
+		{Path: "components[0].components[1].custom_id", Code: "COMPONENT_CUSTOM_ID_DUPLICATED", Message: "Component custom id cannot be duplicated"},
+		{Path: "components[0].components[1].custom_id", Code: "BASE_TYPE_BAD_LENGTH", Message: "Must be between 1 and 100 in length."},
+		{Path: "content", Code: "BASE_TYPE_MAX_LENGTH", Message: "Must be 2000 or fewer in length."},
+		{Path: "embeds[0].fields[3].name", Code: "BASE_TYPE_MAX_LENGTH", Message: "Must be 256 or fewer in length."},
+		{Path: "embeds[0].fields[10].value", Code: "BASE_TYPE_REQUIRED", Message: "This field is required"},
+	}
+
+	var apiErr DiscordAPIError
+	if err := DecodeJSON([]byte(invalidFormBody), &apiErr); err != nil {
+		t.Fatal(err)
+	}
+
+	// The order of the objects of Discord does not change the flattened errors
+	for i := 0; i < 10; i++ {
+		if got := parseFieldErrors(apiErr.Errors); !reflect.DeepEqual(got, want) {
+			t.Fatalf("parseFieldErrors() = %v, want %v", got, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		tree string
+		want []*FieldError
+	}{
+		{"no errors", ``, nil},
+		{"empty tree", `{}`, nil},
+		{"not an object", `["content"]`, nil},
+		{"malformed _errors skipped", `{"content":{"_errors":"too long"},"tts":{"_errors":[{"code":"BASE_TYPE_BOOLEAN","message":"Must be a boolean"}]}}`,
+			[]*FieldError{{Path: "tts", Code: "BASE_TYPE_BOOLEAN", Message: "Must be a boolean"}}},
+		{"index at the root", `{"1":{"name":{"_errors":[{"code":"BASE_TYPE_REQUIRED","message":"This field is required"}]}}}`,
+			[]*FieldError{{Path: "[1].name", Code: "BASE_TYPE_REQUIRED", Message: "This field is required"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseFieldErrors([]byte(test.tree)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseFieldErrors() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscordAPIErrorFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(invalidFormBody))
+	}))
+	defer server.Close()
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	_, err := client.CreateMessage(context.Background(), "3", &MessageCreate{Content: "hello"})
+
+	var apiErr *DiscordAPIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 50035 || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("CreateMessage() = %v, want the 50035 error", err)
+	}
+
+	if len(apiErr.Fields) != 6 || apiErr.Fields[5].Path != "embeds[0].fields[10].value" {
+		t.Errorf("Fields = %v, want the 6 flattened errors", apiErr.Fields)
+	}
+
+	want := "discord api error 50035 (status 400): Invalid Form Body: Cannot send an empty message; " +
+		"components[0].components[1].custom_id: Component custom id cannot be duplicated; " +
+		"components[0].components[1].custom_id: Must be between 1 and 100 in length.; " +
+		"content: Must be 2000 or fewer in length.; " +
+		"embeds[0].fields[3].name: Must be 256 or fewer in length.; " +
+		"embeds[0].fields[10].value: This field is required"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestLessPath(t *testing.T) {
+	paths := []string{"embeds[0].fields[10].value", "content", "embeds[0].fields[3].name", "", "embeds[0].fields[3]", "embeds[10]",
+		"embeds[2].title", "components[0].components[1].custom_id"}
+
+	want := []string{"", "components[0].components[1].custom_id", "content", "embeds[0].fields[3]", "embeds[0].fields[3].name",
+		"embeds[0].fields[10].value", "embeds[2].title", "embeds[10]"}
+
+	for i := range paths {
+		for j := range paths {
+			if lessPath(paths[i], paths[j]) && lessPath(paths[j], paths[i]) {
+				t.Fatalf("%q and %q are both lower", paths[i], paths[j])
+			}
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool { return lessPath(paths[i], paths[j]) })
+
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("sorted paths %q, want %q", paths, want)
+	}
+}
@@ -0,0 +1,120 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"httpcord/permissions"
+)
+
+// ErrMissingAppPermission The response needs a permission the application lacks in the interaction channel
+var ErrMissingAppPermission = errors.New("httpcord: missing application permission")
+
+// MissingAppPermissionError Returned when ConnectionOptions.ValidateResponses rejects a response
+type MissingAppPermissionError struct {
+	Permission permissions.PermissionBit
+	// Name is the permission name as shown in Discord
+	Name string
+}
+
+func (e *MissingAppPermissionError) Error() string {
+	return fmt.Sprintf("httpcord: the application needs the %s permission", e.Name)
+}
+
+func (e *MissingAppPermissionError) Is(target error) bool {
+	return target == ErrMissingAppPermission
+}
+
+var MissingAppPermissionMessages = Dictionary{
+	EnglishUSLocale:    "I need the **%s** permission in this channel, ask an admin to grant it.",
+	EnglishGBLocale:    "I need the **%s** permission in this channel, ask an admin to grant it.",
+	PortugueseBRLocale: "Preciso da permissão **%s** neste canal, peça a um admin para concedê-la.",
+	SpanishESLocale:    "Necesito el permiso **%s** en este canal, pide a un admin que lo conceda.",
+	FrenchLocale:       "J'ai besoin de la permission **%s** dans ce salon, demandez à un admin de l'accorder.",
+	GermanLocale:       "Ich brauche die Berechtigung **%s** in diesem Kanal, bitte einen Admin, sie zu erteilen.",
+}
+
+// AppPermissions Permissions of the application in the interaction channel, false when Discord did not send them
+func (ctx *ConnectionContext) AppPermissions() (permissions.PermissionBit, bool) {
+	if ctx.Interaction.AppPermissions == nil {
+		return 0, false
+	}
+
+	return *ctx.Interaction.AppPermissions, true
+}
+
+func parseAppPermissions(raw string) *permissions.PermissionBit {
+	bits, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	p := permissions.PermissionBit(bits)
+	return &p
+}
+
+// checkAppPermissions Compare the needs of the response against the application permissions: Attach Files for the
+// files, Use External Emojis for the custom emoji of the components and Create Polls for the poll.
+// Custom emoji are assumed external since the guild emoji are unknown
+func (ctx *ConnectionContext) checkAppPermissions(res *InteractionResponse) error {
+	granted, ok := ctx.AppPermissions()
+	if !ok || res.Data == nil {
+		return nil
+	}
+
+	if len(res.Data.Files) > 0 && !granted.Has(permissions.AttachFiles, true) {
+		return &MissingAppPermissionError{Permission: permissions.AttachFiles, Name: "Attach Files"}
+	}
+
+	if res.Data.Poll != nil && !granted.Has(permissions.SendPolls, true) {
+		return &MissingAppPermissionError{Permission: permissions.SendPolls, Name: "Create Polls"}
+	}
+
+	if !granted.Has(permissions.UseExternalEmojis, true) {
+		for _, row := range res.Data.Components {
+			if componentsUseCustomEmoji(row.Components) {
+				return &MissingAppPermissionError{Permission: permissions.UseExternalEmojis, Name: "Use External Emojis"}
+			}
+		}
+	}
+
+	return nil
+}
+
+func componentsUseCustomEmoji(components []AnyComponent) bool {
+	custom := func(emoji *Emoji) bool {
+		return emoji != nil && emoji.ID != ""
+	}
+
+	for _, component := range components {
+		switch c := component.(type) {
+		case *ButtonComponent:
+			if custom(c.Emoji) {
+				return true
+			}
+		case ButtonComponent:
+			if custom(c.Emoji) {
+				return true
+			}
+		case *SelectMenuComponent:
+			for _, option := range c.Options {
+				if custom(option.Emoji) {
+					return true
+				}
+			}
+		case SelectMenuComponent:
+			for _, option := range c.Options {
+				if custom(option.Emoji) {
+					return true
+				}
+			}
+		case *ActionRowComponent:
+			if componentsUseCustomEmoji(c.Components) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package httpcord
+
+import (
+	"errors"
+	"testing"
+
+	"httpcord/permissions"
+)
+
+func TestCheckAppPermissions(t *testing.T) {
+	poll := &InteractionCallbackData{Poll: NewPoll("Lunch?", 24)}
+	emoji := &InteractionCallbackData{Components: []*ActionRowComponent{{Components: []AnyComponent{&ButtonComponent{Emoji: &Emoji{ID: "1"}}}}}}
+
+	tests := []struct {
+		name    string
+		granted *permissions.PermissionBit
+		data    *InteractionCallbackData
+		missing permissions.PermissionBit
+	}{
+		{"permissions not sent", nil, poll, 0},
+		{"poll granted", newPermissionBit(permissions.SendMessages | permissions.SendPolls), poll, 0},
+		{"poll missing", newPermissionBit(permissions.SendMessages), poll, permissions.SendPolls},
+		{"poll as administrator", newPermissionBit(permissions.Administrator), poll, 0},
+		{"files granted", newPermissionBit(permissions.AttachFiles), &InteractionCallbackData{Files: []*DiscordFile{{Filename: "a.txt"}}}, 0},
+		{"external emoji granted", newPermissionBit(permissions.UseExternalEmojis), emoji, 0},
+		{"files missing", newPermissionBit(permissions.SendMessages), &InteractionCallbackData{Files: []*DiscordFile{{Filename: "a.txt"}}}, permissions.AttachFiles},
+		{"external emoji missing", newPermissionBit(permissions.SendMessages), emoji, permissions.UseExternalEmojis},
+		{"plain message", newPermissionBit(0), &InteractionCallbackData{Content: "hi"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := ConnectionContext{Interaction: Interaction{AppPermissions: test.granted}}
+			err := ctx.checkAppPermissions(&InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: test.data})
+
+			var missing *MissingAppPermissionError
+			switch {
+			case test.missing == 0 && err != nil:
+				t.Errorf("checkAppPermissions() = %v, want nil", err)
+			case test.missing != 0 && (!errors.As(err, &missing) || missing.Permission != test.missing):
+				t.Errorf("checkAppPermissions() = %v, want the missing %s", err, PermissionName(test.missing))
+			case test.missing != 0 && !errors.Is(err, ErrMissingAppPermission):
+				t.Errorf("checkAppPermissions() = %v, not an ErrMissingAppPermission", err)
+			}
+		})
+	}
+}
+
+func newPermissionBit(bits permissions.PermissionBit) *permissions.PermissionBit {
+	return &bits
+}
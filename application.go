@@ -0,0 +1,177 @@
+package httpcord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrApplicationMismatch The interaction belongs to another application than the configured ones
+var ErrApplicationMismatch = errors.New("httpcord: interaction application mismatch")
+
+// ApplicationMismatchError Interaction of an application not configured in the connection, usually an endpoint URL
+// set in the wrong application. The request is refused with a 401 and never dispatched
+type ApplicationMismatchError struct {
+	Expected []Snowflake
+	Got      Snowflake
+}
+
+func (e *ApplicationMismatchError) Error() string {
+	return fmt.Sprintf("httpcord: interaction of application %s, expected %v", e.Got, e.Expected)
+}
+
+func (e *ApplicationMismatchError) Is(target error) bool {
+	return target == ErrApplicationMismatch
+}
+
+// expectedApplications IDs set in ConnectionOptions.ApplicationID, ApplicationIDs and Applications, nil when the check
+// is disabled
+func (o *ConnectionOptions) expectedApplications() []Snowflake {
+	var expected []Snowflake
+
+	if o.ApplicationID != "" {
+		expected = append(expected, o.ApplicationID)
+	}
+
+	expected = append(expected, o.ApplicationIDs...)
+	for _, application := range o.Applications {
+		expected = append(expected, application.ID)
+	}
+
+	return expected
+}
+
+// checkApplication Whether the interaction belongs to one of the configured applications
+func (o *ConnectionOptions) checkApplication(applicationID Snowflake) error {
+	expected := o.expectedApplications()
+	if len(expected) == 0 {
+		return nil
+	}
+
+	for _, id := range expected {
+		if id == applicationID {
+			return nil
+		}
+	}
+
+	return &ApplicationMismatchError{Expected: expected, Got: applicationID}
+}
+
+// ApplicationCredentials Application served by the connection along the others, see ConnectionOptions.Applications
+type ApplicationCredentials struct {
+	ID Snowflake
+	// PublicKey Hex encoded key verifying the interactions of the application
+	PublicKey string
+	// Token Bot token of the REST calls of the application (Defaults to ConnectionOptions.Token)
+	Token string
+}
+
+// applicationTokens TokenProvider of the tokens set in ConnectionOptions.Applications, the other applications get
+// ConnectionOptions.Token
+func applicationTokens(applications []ApplicationCredentials, fallback string) TokenProvider {
+	tokens := make(map[Snowflake]string, len(applications))
+	for _, application := range applications {
+		if application.Token != "" {
+			tokens[application.ID] = application.Token
+		}
+	}
+
+	return TokenProviderFunc(func(_ context.Context, applicationID Snowflake) (string, error) {
+		if token, ok := tokens[applicationID]; ok {
+			return token, nil
+		}
+
+		return fallback, nil
+	})
+}
+
+// applicationKeys Public keys of ConnectionOptions.Applications by application ID
+func applicationKeys(applications []ApplicationCredentials) (map[Snowflake]ed25519.PublicKey, error) {
+	if len(applications) == 0 {
+		return nil, nil
+	}
+
+	keys := make(map[Snowflake]ed25519.PublicKey, len(applications))
+	for _, application := range applications {
+		key, err := parsePublicKey(application.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("httpcord: invalid public key of application %s", application.ID)
+		}
+
+		keys[application.ID] = key
+	}
+
+	return keys, nil
+}
+
+// payloadApplication Application ID of the unverified payload, picking the key verifying it
+func payloadApplication(body []byte) Snowflake {
+	var payload struct {
+		ApplicationID Snowflake `json:"application_id"`
+	}
+
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+
+	return payload.ApplicationID
+}
+
+// applicationRouters Routers of the applications with their own routes, shared by the copies of the connection
+type applicationRouters struct {
+	mu   sync.RWMutex
+	byID map[Snowflake]*commandRouter
+	// build creates the router of an application with the built-in components of the connection
+	build func() *commandRouter
+}
+
+// get Router of the application, created on first use
+func (a *applicationRouters) get(applicationID Snowflake) *commandRouter {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if router, ok := a.byID[applicationID]; ok {
+		return router
+	}
+
+	if a.byID == nil {
+		a.byID = make(map[Snowflake]*commandRouter)
+	}
+
+	router := a.build()
+	a.byID[applicationID] = router
+	return router
+}
+
+// pick Router of the application when it has one, fallback otherwise
+func (a *applicationRouters) pick(applicationID Snowflake, fallback *commandRouter) *commandRouter {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if router, ok := a.byID[applicationID]; ok {
+		return router
+	}
+
+	return fallback
+}
+
+// ForApplication Copy of the connection whose routes only handle the interactions of the application, for
+// connections serving several applications:
+//
+//	music := conn.ForApplication(musicID)
+//	music.Command("play", play)
+//	music.SyncCommands(ctx)
+//
+// Interactions of an application with routes of its own are never routed to the routes of the connection. The
+// interaction handlers, middlewares and lifecycle are shared, the Client and the command registration of the copy
+// target the application
+func (c *Connection) ForApplication(applicationID Snowflake) *Connection {
+	copied := *c
+	copied.router = c.applications.get(applicationID)
+	copied.applicationID = applicationID
+	copied.Client = c.Client.WithApplication(applicationID)
+	return &copied
+}
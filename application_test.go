@@ -0,0 +1,205 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ofApplication Body of the application instead of the application 1
+func ofApplication(body []byte, applicationID Snowflake) []byte {
+	return bytes.Replace(body, []byte(`"application_id":"1"`), []byte(`"application_id":"`+string(applicationID)+`"`), 1)
+}
+
+func TestApplicationMismatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     ConnectionOptions
+		application Snowflake
+		// expected Applications of the reported mismatch, nil when the interaction is dispatched
+		expected []Snowflake
+	}{
+		{"configured application", ConnectionOptions{ApplicationID: "1"}, "1", nil},
+		{"other application", ConnectionOptions{ApplicationID: "1"}, "2", []Snowflake{"1"}},
+		{"check disabled", ConnectionOptions{}, "2", nil},
+		{"one of the applications", ConnectionOptions{ApplicationID: "1", ApplicationIDs: []Snowflake{"3"}}, "3", nil},
+		{"none of the applications", ConnectionOptions{ApplicationID: "1", ApplicationIDs: []Snowflake{"3"}}, "2", []Snowflake{"1", "3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mismatch *ApplicationMismatchError
+
+			options := test.options
+			options.Logger = NopLogger
+			options.OnApplicationMismatch = func(err *ApplicationMismatchError) { mismatch = err }
+
+			conn, sign := signedConnection(t, options)
+
+			var ran bool
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(ofApplication(commandBody(), test.application)))
+
+			if test.expected == nil {
+				if w.Code != http.StatusOK || !ran || mismatch != nil {
+					t.Errorf("status %d, ran %v, mismatch %v, want the interaction dispatched", w.Code, ran, mismatch)
+				}
+
+				return
+			}
+
+			if w.Code != http.StatusUnauthorized || ran {
+				t.Errorf("status %d, ran %v, want a 401 before the dispatch", w.Code, ran)
+			}
+
+			if mismatch == nil || mismatch.Got != test.application || !reflect.DeepEqual(mismatch.Expected, test.expected) {
+				t.Fatalf("OnApplicationMismatch got %+v, want %s instead of %v", mismatch, test.application, test.expected)
+			}
+
+			if !errors.Is(mismatch, ErrApplicationMismatch) {
+				t.Errorf("%v is not ErrApplicationMismatch", mismatch)
+			}
+		})
+	}
+}
+
+// applicationKey Hex public key of a new key pair with the signer of its requests
+func applicationKey(t *testing.T) (string, func(body []byte) *http.Request) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hex.EncodeToString(public), func(body []byte) *http.Request {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(private, append([]byte(timestamp), body...))))
+		r.Header.Set("X-Signature-Timestamp", timestamp)
+
+		return r
+	}
+}
+
+func TestApplicationKeys(t *testing.T) {
+	music, signMusic := applicationKey(t)
+	moderation, signModeration := applicationKey(t)
+	fallback, signFallback := applicationKey(t)
+
+	tests := []struct {
+		name string
+		// publicKey ConnectionOptions.PublicKey next to the applications
+		publicKey   string
+		application Snowflake
+		sign        func(body []byte) *http.Request
+		// want Reply of the routes of the application, empty when the request is refused with a 401
+		want string
+	}{
+		{"first application", "", "1", signMusic, "music"},
+		{"second application", "", "7", signModeration, "moderation"},
+		{"key of the other application", "", "1", signModeration, ""},
+		{"key of the first application for the second", "", "7", signMusic, ""},
+		{"unknown application signed with an application key", "", "9", signMusic, ""},
+		{"fallback key for an application", fallback, "1", signFallback, ""},
+		{"fallback key for an unknown application", fallback, "9", signFallback, ""},
+		{"application key next to the fallback key", fallback, "7", signModeration, "moderation"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, err := NewConnection(ConnectionOptions{
+				Logger:    NopLogger,
+				PublicKey: test.publicKey,
+				Applications: []ApplicationCredentials{
+					{ID: "1", PublicKey: music},
+					{ID: "7", PublicKey: moderation},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ran bool
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "music"})
+			})
+
+			conn.ForApplication("7").Command("ban", func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "moderation"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, test.sign(ofApplication(commandBody(), test.application)))
+
+			if test.want == "" {
+				if w.Code != http.StatusUnauthorized || ran {
+					t.Errorf("status %d, ran %v, want a 401 before the dispatch", w.Code, ran)
+				}
+
+				return
+			}
+
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"`+test.want+`"`) {
+				t.Errorf("status %d: %s, want the %s reply", w.Code, w.Body, test.want)
+			}
+		})
+	}
+}
+
+func TestApplicationKeysInvalid(t *testing.T) {
+	valid, _ := applicationKey(t)
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"not hex", "zz"},
+		{"too short", valid[:32]},
+		{"missing", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewConnection(ConnectionOptions{
+				Logger: NopLogger,
+				Applications: []ApplicationCredentials{
+					{ID: "1", PublicKey: valid},
+					{ID: "7", PublicKey: test.key},
+				},
+			})
+
+			if err == nil || !strings.Contains(err.Error(), "application 7") {
+				t.Errorf("NewConnection() = %v, want the invalid key of the application 7", err)
+			}
+		})
+	}
+}
+
+func TestApplicationTokens(t *testing.T) {
+	tokens := applicationTokens([]ApplicationCredentials{{ID: "1", Token: "music"}, {ID: "7"}}, "fallback")
+
+	for application, want := range map[Snowflake]string{"1": "music", "7": "fallback", "9": "fallback"} {
+		if token, err := tokens.Token(context.Background(), application); token != want || err != nil {
+			t.Errorf("Token(%s) = %q, %v, want %q", application, token, err, want)
+		}
+	}
+}
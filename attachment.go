@@ -6,6 +6,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/textproto"
+	"strconv"
 	"strings"
 )
 
@@ -19,29 +20,42 @@ type DiscordFile struct {
 	Spoiler     bool
 }
 
-func (f *DiscordFile) MakeAttach(ID Snowflake, m *multipart.Writer) (*Attachment, error) {
+// AttachmentURL Reference of the file in the embeds of its message, like the URL of EmbedImage.
+// Spoiler files are referenced with their SPOILER_ prefix
+func (f *DiscordFile) AttachmentURL() string {
+	filename := f.Filename
+	if f.Spoiler && !strings.HasPrefix(filename, "SPOILER_") {
+		filename = "SPOILER_" + filename
+	}
+
+	return "attachment://" + filename
+}
+
+// attachment Attachment metadata of the file sent as files[index], spoiler files get their SPOILER_ prefix.
+// Discord matches the attachment to the part by its ID, the index of the file in the files array
+func (f *DiscordFile) attachment(index int) *Attachment {
 	if f.Spoiler && !strings.HasPrefix(f.Filename, "SPOILER_") {
 		f.Filename = "SPOILER_" + f.Filename
 	}
 
-	attach := &Attachment{
-		ID:          ID,
+	return &Attachment{
+		ID:          Snowflake(strconv.Itoa(index)),
 		Filename:    f.Filename,
 		Description: f.Description,
 	}
+}
 
-	contentType := "application/octet-stream"
-
-	if f.ContentType != "" {
-		contentType = f.ContentType
-	}
+// MakeAttach Write the file as the files[index] part, the returned attachment must be listed in the payload with the
+// same index as ID
+func (f *DiscordFile) MakeAttach(index int, m *multipart.Writer) (*Attachment, error) {
+	attach := f.attachment(index)
 
 	headers := make(textproto.MIMEHeader)
 	headers.Set("Content-Disposition", fmt.Sprintf("form-data; name=\"%s\"; filename=\"%s\"",
-		QuoteEscaper.Replace(fmt.Sprintf("files[%d]", ID.Uint64())),
+		QuoteEscaper.Replace(fmt.Sprintf("files[%d]", index)),
 		QuoteEscaper.Replace(f.Filename),
 	))
-	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Type", fileContentType(f))
 
 	w, err := m.CreatePart(headers)
 
@@ -57,3 +71,22 @@ func (f *DiscordFile) MakeAttach(ID Snowflake, m *multipart.Writer) (*Attachment
 
 	return attach, nil
 }
+
+// listAttachments Attachments of the payload: the listed ones, like the kept attachments of an edit, followed by the
+// attachments of the files whose files[index] is not listed yet
+func listAttachments(listed []*Attachment, files []*DiscordFile) []*Attachment {
+	attachments := append([]*Attachment(nil), listed...)
+
+	indexes := make(map[Snowflake]bool, len(listed))
+	for _, attachment := range listed {
+		indexes[attachment.ID] = true
+	}
+
+	for i, file := range files {
+		if !indexes[Snowflake(strconv.Itoa(i))] {
+			attachments = append(attachments, file.attachment(i))
+		}
+	}
+
+	return attachments
+}
@@ -0,0 +1,212 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxDownloadSize Largest attachment read by Download without MaxDownloadSize, the upload limit of the
+// servers without boosts
+const DefaultMaxDownloadSize = 25 << 20
+
+var (
+	// ErrAttachmentTooLarge The attachment is larger than MaxDownloadSize, declared or while reading
+	ErrAttachmentTooLarge = errors.New("httpcord: attachment too large")
+	// ErrAttachmentContentType The content type of the attachment is not allowed by AllowContentTypes
+	ErrAttachmentContentType = errors.New("httpcord: attachment content type not allowed")
+	// ErrAttachmentDownload The CDN did not send the attachment
+	ErrAttachmentDownload = errors.New("httpcord: attachment download failed")
+	// ErrAttachmentHost The URL of the attachment is not an https URL of the Discord CDN or of AllowDownloadHosts
+	ErrAttachmentHost = errors.New("httpcord: attachment not hosted on the Discord CDN")
+)
+
+// cdnHosts Hosts of the attachment URLs and proxy URLs sent by Discord
+var cdnHosts = []string{"cdn.discordapp.com", "media.discordapp.net"}
+
+type DownloadOption func(o *downloadOptions)
+
+type downloadOptions struct {
+	client       *http.Client
+	maxSize      int64
+	contentTypes []string
+	hosts        []string
+}
+
+// MaxDownloadSize Refuse the attachments larger than size bytes with ErrAttachmentTooLarge, 0 to read any size
+// (Defaults to DefaultMaxDownloadSize)
+func MaxDownloadSize(size int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.maxSize = size
+	}
+}
+
+// AllowContentTypes Refuse the attachments of other content types with ErrAttachmentContentType. Types ending with
+// a slash match their subtypes, like "image/" (Defaults to every type)
+func AllowContentTypes(types ...string) DownloadOption {
+	return func(o *downloadOptions) {
+		o.contentTypes = append(o.contentTypes, types...)
+	}
+}
+
+// DownloadClient HTTP client fetching the file (Defaults to http.DefaultClient)
+func DownloadClient(client *http.Client) DownloadOption {
+	return func(o *downloadOptions) {
+		o.client = client
+	}
+}
+
+// AllowDownloadHosts Download from the hosts next to the Discord CDN, like a caching proxy. Only https URLs are fetched
+func AllowDownloadHosts(hosts ...string) DownloadOption {
+	return func(o *downloadOptions) {
+		o.hosts = append(o.hosts, hosts...)
+	}
+}
+
+// allowedHost Whether the URL is an https URL of the CDN or of the allowed hosts
+func (o *downloadOptions) allowedHost(u *url.URL) bool {
+	if u.Scheme != "https" {
+		return false
+	}
+
+	for _, hosts := range [][]string{cdnHosts, o.hosts} {
+		for _, host := range hosts {
+			if strings.EqualFold(host, u.Hostname()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// allowed Whether the content type, without its parameters, is allowed
+func (o *downloadOptions) allowed(contentType string) bool {
+	if len(o.contentTypes) == 0 {
+		return true
+	}
+
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = parsed
+	}
+
+	for _, allowed := range o.contentTypes {
+		if strings.HasSuffix(allowed, "/") && strings.HasPrefix(contentType, allowed) || strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Download Stream the file from the CDN, the caller closes the reader. The size and content type declared by
+// Discord are checked before the request and the ones of the CDN response once it is received, reading past
+// MaxDownloadSize fails with ErrAttachmentTooLarge. URLs outside the CDN, redirects included, fail with ErrAttachmentHost
+func (a *Attachment) Download(ctx context.Context, opts ...DownloadOption) (io.ReadCloser, error) {
+	o := downloadOptions{client: http.DefaultClient, maxSize: DefaultMaxDownloadSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.maxSize > 0 && int64(a.Size) > o.maxSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrAttachmentTooLarge, a.Size)
+	}
+
+	if a.ContentType != "" && !o.allowed(a.ContentType) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentContentType, a.ContentType)
+	}
+
+	location := a.URL
+	if location == "" {
+		location = a.ProxyURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAttachmentDownload, err)
+	}
+
+	if !o.allowedHost(req.URL) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentHost, req.URL.Redacted())
+	}
+
+	req.Header.Set(UserAgentHeaderKey, DefaultUserAgent)
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAttachmentDownload, err)
+	}
+
+	fail := func(err error) (io.ReadCloser, error) {
+		res.Body.Close()
+		return nil, err
+	}
+
+	if res.Request != nil && !o.allowedHost(res.Request.URL) {
+		return fail(fmt.Errorf("%w: redirected to %s", ErrAttachmentHost, res.Request.URL.Redacted()))
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fail(fmt.Errorf("%w: %s", ErrAttachmentDownload, res.Status))
+	}
+
+	if contentType := res.Header.Get("Content-Type"); a.ContentType == "" && !o.allowed(contentType) {
+		return fail(fmt.Errorf("%w: %s", ErrAttachmentContentType, contentType))
+	}
+
+	if o.maxSize > 0 && res.ContentLength > o.maxSize {
+		return fail(fmt.Errorf("%w: %d bytes", ErrAttachmentTooLarge, res.ContentLength))
+	}
+
+	if o.maxSize == 0 {
+		return res.Body, nil
+	}
+
+	return &limitedBody{body: res.Body, remaining: o.maxSize}, nil
+}
+
+// limitedBody Response body failing with ErrAttachmentTooLarge past its remaining bytes
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	// One more byte than allowed tells a file of the exact limit from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.body.Read(p)
+	if int64(n) > b.remaining {
+		return int(b.remaining), ErrAttachmentTooLarge
+	}
+
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// DownloadAttachment Stream the file of the attachment option through the HTTP client of the connection, bound to
+// the handler context. ErrMissingOption when the option is missing or not resolved
+func (ctx *ConnectionContext) DownloadAttachment(name string, opts ...DownloadOption) (io.ReadCloser, *Attachment, error) {
+	attachment, ok := ctx.AttachmentOption(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMissingOption, name)
+	}
+
+	if ctx.client != nil && ctx.client.HTTPClient != nil {
+		opts = append([]DownloadOption{DownloadClient(ctx.client.HTTPClient)}, opts...)
+	}
+
+	body, err := attachment.Download(ctx.Context(), opts...)
+	return body, attachment, err
+}
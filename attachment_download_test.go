@@ -0,0 +1,139 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// cdnTransport Transport answering for any host with the file of the path, recording the URLs requested
+type cdnTransport struct {
+	files map[string]string
+	// undeclared Send the files without Content-Length
+	undeclared bool
+	requested  []string
+}
+
+func (c *cdnTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, r.URL.String())
+
+	res := &http.Response{StatusCode: http.StatusNotFound, Header: make(http.Header), Body: io.NopCloser(strings.NewReader("")), Request: r}
+
+	if r.URL.Path == "/moved" {
+		res.StatusCode = http.StatusFound
+		res.Header.Set("Location", "https://files.example.com/log.txt")
+		return res, nil
+	}
+
+	if file, ok := c.files[r.URL.Path]; ok {
+		res.StatusCode = http.StatusOK
+		res.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		res.Body = io.NopCloser(strings.NewReader(file))
+		res.ContentLength = int64(len(file))
+
+		if c.undeclared {
+			res.ContentLength = -1
+		}
+	}
+
+	return res, nil
+}
+
+func TestAttachmentDownload(t *testing.T) {
+	file := strings.Repeat("x", 64)
+	cdn := "https://cdn.discordapp.com/attachments/3/12/log.txt"
+
+	tests := []struct {
+		name       string
+		attachment Attachment
+		opts       []DownloadOption
+		undeclared bool
+		err        error
+		// requests Requests sent to the CDN
+		requests int
+	}{
+		{"whole file", Attachment{URL: cdn, Size: 64}, nil, false, nil, 1},
+		{"at the limit", Attachment{URL: cdn, Size: 64}, []DownloadOption{MaxDownloadSize(64)}, false, nil, 1},
+		{"undeclared at the limit", Attachment{URL: cdn}, []DownloadOption{MaxDownloadSize(64)}, true, nil, 1},
+		{"declared over the limit", Attachment{URL: cdn, Size: 65}, []DownloadOption{MaxDownloadSize(64)}, false, ErrAttachmentTooLarge, 0},
+		{"CDN length over the limit", Attachment{URL: cdn, Size: 10}, []DownloadOption{MaxDownloadSize(63)}, false, ErrAttachmentTooLarge, 1},
+		{"read over the limit", Attachment{URL: cdn, Size: 10}, []DownloadOption{MaxDownloadSize(63)}, true, ErrAttachmentTooLarge, 1},
+		{"limit disabled", Attachment{URL: cdn, Size: DefaultMaxDownloadSize + 1}, []DownloadOption{MaxDownloadSize(0)}, true, nil, 1},
+		{"proxy URL", Attachment{ProxyURL: "https://media.discordapp.net/attachments/3/12/log.txt"}, nil, false, nil, 1},
+		{"content type allowed", Attachment{URL: cdn, ContentType: "text/plain"}, []DownloadOption{AllowContentTypes("image/", "text/plain")},
+			false, nil, 1},
+		{"content type refused", Attachment{URL: cdn, ContentType: "application/zip"}, []DownloadOption{AllowContentTypes("image/")}, false,
+			ErrAttachmentContentType, 0},
+		{"CDN content type refused", Attachment{URL: cdn}, []DownloadOption{AllowContentTypes("image/")}, false, ErrAttachmentContentType, 1},
+		{"missing file", Attachment{URL: "https://cdn.discordapp.com/attachments/3/12/gone.txt"}, nil, false, ErrAttachmentDownload, 1},
+		{"other host", Attachment{URL: "https://files.example.com/log.txt"}, nil, false, ErrAttachmentHost, 0},
+		{"host under the CDN name", Attachment{URL: "https://cdn.discordapp.com.example.com/log.txt"}, nil, false, ErrAttachmentHost, 0},
+		{"internal address", Attachment{URL: "https://169.254.169.254/latest/meta-data"}, nil, false, ErrAttachmentHost, 0},
+		{"plain http CDN", Attachment{URL: "http://cdn.discordapp.com/attachments/3/12/log.txt"}, nil, false, ErrAttachmentHost, 0},
+		{"no URL", Attachment{}, nil, false, ErrAttachmentHost, 0},
+		{"redirect off the CDN", Attachment{URL: "https://cdn.discordapp.com/moved"}, nil, false, ErrAttachmentHost, 2},
+		{"allowed host", Attachment{URL: "https://files.example.com/log.txt"}, []DownloadOption{AllowDownloadHosts("FILES.example.com")}, false,
+			nil, 1},
+		{"allowed host over http", Attachment{URL: "http://files.example.com/log.txt"}, []DownloadOption{AllowDownloadHosts("files.example.com")},
+			false, ErrAttachmentHost, 0},
+		{"redirect to an allowed host", Attachment{URL: "https://cdn.discordapp.com/moved"},
+			[]DownloadOption{AllowDownloadHosts("files.example.com")}, false, nil, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := &cdnTransport{files: map[string]string{"/attachments/3/12/log.txt": file, "/log.txt": file}, undeclared: test.undeclared}
+			opts := append([]DownloadOption{DownloadClient(&http.Client{Transport: transport})}, test.opts...)
+
+			body, err := test.attachment.Download(context.Background(), opts...)
+
+			var read []byte
+			if err == nil {
+				read, err = io.ReadAll(body)
+				body.Close()
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Errorf("Download() = %v, want %v", err, test.err)
+			}
+
+			if test.err == nil && string(read) != file {
+				t.Errorf("read %d bytes, want the %d bytes of the file", len(read), len(file))
+			}
+
+			if len(transport.requested) != test.requests {
+				t.Errorf("requested %v, want %d requests", transport.requested, test.requests)
+			}
+		})
+	}
+}
+
+func TestDownloadAttachmentOption(t *testing.T) {
+	body := `{"id":"5","name":"upload","type":1,"options":[{"type":11,"name":"file","value":"12"}],"resolved":{"attachments":{"12":` +
+		`{"id":"12","filename":"log.txt","size":64,"url":"https://cdn.discordapp.com/attachments/3/12/log.txt",` +
+		`"proxy_url":"https://media.discordapp.net/attachments/3/12/log.txt","content_type":"text/plain"}}}}`
+
+	ctx := bodyContext(t, interactionBody(ApplicationCommandInteraction, body))
+
+	transport := &cdnTransport{files: map[string]string{"/attachments/3/12/log.txt": strings.Repeat("x", 64)}}
+	ctx.client = NewRestClient(StaticToken("token"))
+	ctx.client.HTTPClient = &http.Client{Transport: transport}
+
+	file, attachment, err := ctx.DownloadAttachment("file", MaxDownloadSize(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if read, _ := io.ReadAll(file); len(read) != 64 || attachment.Filename != "log.txt" || len(transport.requested) != 1 {
+		t.Errorf("read %d bytes of %q with %v, want the file through the client of the connection", len(read), attachment.Filename,
+			transport.requested)
+	}
+
+	if _, _, err := ctx.DownloadAttachment("missing"); !errors.Is(err, ErrMissingOption) {
+		t.Errorf("DownloadAttachment(missing) = %v, want ErrMissingOption", err)
+	}
+}
@@ -0,0 +1,139 @@
+package httpcord
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxAutocompleteChoices Maximum choices accepted by Discord in an autocomplete result
+const MaxAutocompleteChoices = 25
+
+// ErrNotAutocomplete Choices can only answer autocomplete interactions
+var ErrNotAutocomplete = errors.New("httpcord: the interaction is not an autocomplete")
+
+type AutocompleteFilterOption func(f *autocompleteFilter)
+
+type autocompleteFilter struct {
+	locale Locale
+	limit  int
+	fuzzy  bool
+}
+
+// AutocompleteLocale Fold case and match localized choice names using the locale rules
+func AutocompleteLocale(locale Locale) AutocompleteFilterOption {
+	return func(f *autocompleteFilter) {
+		f.locale = locale
+	}
+}
+
+// AutocompleteLimit Return at most limit choices, capped to MaxAutocompleteChoices
+func AutocompleteLimit(limit int) AutocompleteFilterOption {
+	return func(f *autocompleteFilter) {
+		if limit > 0 && limit < MaxAutocompleteChoices {
+			f.limit = limit
+		}
+	}
+}
+
+// AutocompleteFuzzy Also return choices containing the query characters in order, ranked by score
+func AutocompleteFuzzy() AutocompleteFilterOption {
+	return func(f *autocompleteFilter) {
+		f.fuzzy = true
+	}
+}
+
+type rankedChoice struct {
+	index int
+	rank  int
+	score int
+}
+
+// AutocompleteFilter Filter candidates by the query, prefix matches first, then substring and fuzzy matches.
+// Ties keep the candidates order
+func AutocompleteFilter(candidates []ApplicationCommandOptionChoice, query string, opts ...AutocompleteFilterOption) []ApplicationCommandOptionChoice {
+	f := autocompleteFilter{limit: MaxAutocompleteChoices}
+
+	for _, opt := range opts {
+		opt(&f)
+	}
+
+	query = f.fold(strings.TrimSpace(query))
+	ranked := make([]rankedChoice, 0, f.limit)
+
+	for i := range candidates {
+		name := f.fold(candidates[i].NameLocalizations.Get(f.locale, candidates[i].Name))
+
+		switch {
+		case strings.HasPrefix(name, query):
+			ranked = append(ranked, rankedChoice{index: i, rank: 0})
+		case strings.Contains(name, query):
+			ranked = append(ranked, rankedChoice{index: i, rank: 1})
+		case f.fuzzy:
+			if score, ok := fuzzyScore(name, query); ok {
+				ranked = append(ranked, rankedChoice{index: i, rank: 2, score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].rank != ranked[j].rank {
+			return ranked[i].rank < ranked[j].rank
+		}
+
+		return ranked[i].score > ranked[j].score
+	})
+
+	if len(ranked) > f.limit {
+		ranked = ranked[:f.limit]
+	}
+
+	choices := make([]ApplicationCommandOptionChoice, len(ranked))
+	for i, r := range ranked {
+		choices[i] = candidates[r.index]
+	}
+
+	return choices
+}
+
+func (f *autocompleteFilter) fold(s string) string {
+	if f.locale == TurkishLocale {
+		return strings.ToLowerSpecial(unicode.TurkishCase, s)
+	}
+
+	return strings.ToLower(s)
+}
+
+// fuzzyScore Score the query characters found in order inside name, consecutive and early matches score higher
+func fuzzyScore(name, query string) (int, bool) {
+	score, streak, pos := 0, 0, 0
+
+	for _, q := range query {
+		found := false
+
+		for pos < len(name) {
+			r, size := utf8.DecodeRuneInString(name[pos:])
+			pos += size
+
+			if r == q {
+				streak++
+				score += streak * 2
+				if pos == size {
+					score += 3
+				}
+				found = true
+				break
+			}
+
+			streak = 0
+		}
+
+		if !found {
+			return 0, false
+		}
+	}
+
+	return score - pos/8, true
+}
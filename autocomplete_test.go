@@ -0,0 +1,168 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// choiceNames Names of the choices joined by commas
+func choiceNames(choices []ApplicationCommandOptionChoice) string {
+	names := make([]string, len(choices))
+	for i, choice := range choices {
+		names[i] = choice.Name
+	}
+
+	return strings.Join(names, ",")
+}
+
+// namedChoices Choices with the names as names and values
+func namedChoices(names ...string) []ApplicationCommandOptionChoice {
+	candidates := make([]ApplicationCommandOptionChoice, len(names))
+	for i, name := range names {
+		candidates[i] = ApplicationCommandOptionChoice{Name: name, Value: name}
+	}
+
+	return candidates
+}
+
+func TestAutocompleteFilter(t *testing.T) {
+	cities := namedChoices("Istanbul", "İzmir", "Iğdır", "ılgaz", "Antalya")
+
+	localized := []ApplicationCommandOptionChoice{
+		{Name: "Island", Value: "island", NameLocalizations: map[Locale]string{TurkishLocale: "Ada"}},
+		{Name: "Valley", Value: "valley", NameLocalizations: map[Locale]string{TurkishLocale: "Vadi"}},
+	}
+
+	tests := []struct {
+		name       string
+		candidates []ApplicationCommandOptionChoice
+		query      string
+		opts       []AutocompleteFilterOption
+		want       string
+	}{
+		{"prefix before substring", namedChoices("unban", "ban", "banner", "urban"), "ban", nil, "ban,banner,unban,urban"},
+		{"case folded", namedChoices("Ban", "KICK"), "kick", nil, "KICK"},
+		{"query trimmed", namedChoices("ban", "kick"), "  ki ", nil, "kick"},
+		{"empty query keeps every candidate", namedChoices("b", "a", "c"), "", nil, "b,a,c"},
+		{"no match", namedChoices("ban", "kick"), "mute", nil, ""},
+		{"dotless ı is not i", cities, "ı", nil, "ılgaz,Iğdır"},
+		{"Turkish I folds to ı", cities, "ı", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}, "Istanbul,Iğdır,ılgaz"},
+		{"Turkish İ folds to i", cities, "iz", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}, "İzmir"},
+		{"Turkish i is not I", cities, "is", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}, ""},
+		{"other locales fold I to i", cities, "is", []AutocompleteFilterOption{AutocompleteLocale(FrenchLocale)}, "Istanbul"},
+		{"localized names", localized, "va", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}, "Valley"},
+		{"localization fallback", localized, "va", []AutocompleteFilterOption{AutocompleteLocale(GermanLocale)}, "Valley"},
+		{"localized name replaces the name", localized, "is", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}, ""},
+		{"fuzzy after substring", namedChoices("timeout", "mute", "emote", "smt"), "mt", []AutocompleteFilterOption{AutocompleteFuzzy()}, "smt,mute,timeout,emote"},
+		{"fuzzy disabled", namedChoices("timeout", "mute"), "mt", nil, ""},
+		{"limit", namedChoices("a1", "a2", "a3"), "a", []AutocompleteFilterOption{AutocompleteLimit(2)}, "a1,a2"},
+		{"limit capped", namedChoices(numberedNames(30)...), "choice", []AutocompleteFilterOption{AutocompleteLimit(50)}, strings.Join(numberedNames(MaxAutocompleteChoices), ",")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := choiceNames(AutocompleteFilter(test.candidates, test.query, test.opts...)); got != test.want {
+				t.Errorf("AutocompleteFilter(%q) = %q, want %q", test.query, got, test.want)
+			}
+		})
+	}
+}
+
+// numberedNames Names "choice 0" to "choice n-1"
+func numberedNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("choice %d", i)
+	}
+
+	return names
+}
+
+func TestAutocompleteFilterStable(t *testing.T) {
+	candidates := namedChoices("kick b", "ban a", "kick a", "ban b", "mute", "ban c")
+
+	want := "ban a,ban b,ban c"
+	for i := 0; i < 100; i++ {
+		if got := choiceNames(AutocompleteFilter(candidates, "ban")); got != want {
+			t.Fatalf("run %d: AutocompleteFilter() = %q, want the candidates order %q", i, got, want)
+		}
+	}
+
+	fuzzy := namedChoices("a-x-b", "a-y-b", "a-z-b")
+	if got := choiceNames(AutocompleteFilter(fuzzy, "ab", AutocompleteFuzzy())); got != "a-x-b,a-y-b,a-z-b" {
+		t.Errorf("fuzzy ties = %q, want the candidates order", got)
+	}
+
+	if candidates[0].Name != "kick b" {
+		t.Error("the candidates were reordered")
+	}
+}
+
+func BenchmarkAutocompleteFilter(b *testing.B) {
+	candidates := namedChoices(numberedNames(10000)...)
+
+	tests := []struct {
+		name  string
+		query string
+		opts  []AutocompleteFilterOption
+	}{
+		{"prefix", "choice 99", nil},
+		{"fuzzy", "c99", []AutocompleteFilterOption{AutocompleteFuzzy()}},
+		{"turkish", "CHOİCE 1", []AutocompleteFilterOption{AutocompleteLocale(TurkishLocale)}},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				AutocompleteFilter(candidates, test.query, test.opts...)
+			}
+		})
+	}
+}
+
+func TestAutocompleteHandlerPanic(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+	}{
+		{"error reply", ConnectionOptions{}},
+		{"error report", ConnectionOptions{ErrorReport: &ErrorReportOptions{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var reported []error
+			var replied bool
+
+			options := test.options
+			options.Logger = NopLogger
+			options.OnError = func(ctx ConnectionContext, err error) { reported = append(reported, err) }
+			options.ErrorReply = func(ctx ConnectionContext, err error) *InteractionCallbackData {
+				replied = true
+				return &InteractionCallbackData{Content: "failed"}
+			}
+
+			conn, sign := signedConnection(t, options)
+			conn.Command("ban", func(ctx ConnectionContext) {}).SetAutocomplete(func(ctx ConnectionContext) {
+				panic("search failed")
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(interactionBody(AutoCompleteInteraction,
+				`{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"sp","focused":true}]}`)))
+
+			if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), `{"type":8,`) || strings.Contains(w.Body.String(), "failed") {
+				t.Errorf("response %d %s, want an autocomplete result without choices", w.Code, w.Body)
+			}
+
+			if replied || len(reported) != 1 {
+				t.Errorf("ErrorReply called %v and %d errors reported, want only the error reported", replied, len(reported))
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrOptionMissing A required option bound by BindOptions was not provided
+	ErrOptionMissing = errors.New("httpcord: required option missing")
+	// ErrOptionType The option value can not be converted to the field type
+	ErrOptionType = errors.New("httpcord: option value does not match the field type")
+)
+
+// FieldBindError Failure binding an option to a struct field
+type FieldBindError struct {
+	Field  string
+	Option string
+	Err    error
+}
+
+func (e *FieldBindError) Error() string {
+	return fmt.Sprintf("field %s (option %q): %v", e.Field, e.Option, e.Err)
+}
+
+func (e *FieldBindError) Unwrap() error {
+	return e.Err
+}
+
+// BindError Every field BindOptions failed to populate
+type BindError struct {
+	Fields []*FieldBindError
+}
+
+func (e *BindError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+
+	return "httpcord: binding options: " + strings.Join(messages, "; ")
+}
+
+type bindKind int
+
+const (
+	bindString bindKind = iota + 1
+	bindBool
+	bindInt
+	bindFloat
+	bindSnowflake
+	bindUser
+	bindMember
+	bindRole
+	bindChannel
+	bindAttachment
+)
+
+type boundField struct {
+	index  int
+	name   string
+	option string
+	kind   bindKind
+	// pointer scalars are optional and left nil when missing
+	pointer    bool
+	required   bool
+	defaultRaw *string
+	err        error
+}
+
+var (
+	bindCache sync.Map
+
+	snowflakeType  = reflect.TypeOf(Snowflake(""))
+	userType       = reflect.TypeOf(&User{})
+	memberType     = reflect.TypeOf(&Member{})
+	roleType       = reflect.TypeOf(&Role{})
+	channelType    = reflect.TypeOf(&Channel{})
+	attachmentType = reflect.TypeOf(&Attachment{})
+)
+
+// optionTag Option of the struct field read by BindOptions and CommandSchema, skip is set for the fields without
+// an option tag and by the "-" tag
+type optionTag struct {
+	name         string
+	required     bool
+	optional     bool
+	autocomplete bool
+	defaultRaw   *string
+	skip         bool
+	err          error
+}
+
+func parseOptionTag(field reflect.StructField) optionTag {
+	value, ok := field.Tag.Lookup("option")
+	if !ok || field.PkgPath != "" {
+		return optionTag{skip: true}
+	}
+
+	parts := strings.Split(value, ",")
+	if parts[0] == "-" {
+		return optionTag{skip: true}
+	}
+
+	tag := optionTag{name: parts[0]}
+	if tag.name == "" {
+		tag.name = snakeCase(field.Name)
+	}
+
+	for _, flag := range parts[1:] {
+		switch {
+		case flag == "required":
+			tag.required = true
+		case flag == "optional":
+			tag.optional = true
+		case flag == "autocomplete":
+			tag.autocomplete = true
+		case strings.HasPrefix(flag, "default="):
+			value := strings.TrimPrefix(flag, "default=")
+			tag.defaultRaw = &value
+		default:
+			tag.err = fmt.Errorf("httpcord: unknown option tag %q", flag)
+		}
+	}
+
+	return tag
+}
+
+// isRequired Whether the option must be sent: flagged required, or neither a pointer scalar, optional nor with a
+// default
+func (tag optionTag) isRequired(pointer bool) bool {
+	return tag.required || (!pointer && !tag.optional && tag.defaultRaw == nil)
+}
+
+// bindFields Fields of the struct type with an option tag, cached per type
+func bindFields(t reflect.Type) []boundField {
+	if cached, ok := bindCache.Load(t); ok {
+		return cached.([]boundField)
+	}
+
+	var fields []boundField
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		tag := parseOptionTag(sf)
+		if tag.skip {
+			continue
+		}
+
+		field := boundField{index: i, name: sf.Name, option: tag.name, defaultRaw: tag.defaultRaw, err: tag.err}
+
+		ft := sf.Type
+
+		switch ft {
+		case userType:
+			field.kind = bindUser
+		case memberType:
+			field.kind = bindMember
+		case roleType:
+			field.kind = bindRole
+		case channelType:
+			field.kind = bindChannel
+		case attachmentType:
+			field.kind = bindAttachment
+		default:
+			if ft.Kind() == reflect.Ptr {
+				field.pointer = true
+				ft = ft.Elem()
+			}
+
+			field.kind = scalarKind(ft)
+		}
+
+		if field.kind == 0 {
+			field.err = fmt.Errorf("httpcord: unsupported field type %s", sf.Type)
+		}
+
+		field.required = tag.isRequired(field.pointer)
+
+		fields = append(fields, field)
+	}
+
+	bindCache.Store(t, fields)
+	return fields
+}
+
+func scalarKind(t reflect.Type) bindKind {
+	if t == snowflakeType {
+		return bindSnowflake
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return bindString
+	case reflect.Bool:
+		return bindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return bindInt
+	case reflect.Float32, reflect.Float64:
+		return bindFloat
+	}
+
+	return 0
+}
+
+// BindOptions Populate the struct pointed by v from the options of the invoked subcommand and the resolved data.
+// Fields are bound with the `option:"name"` tag, an empty name is the snake_case field name. `option:"name,default=value"`
+// sets the value used when missing and `option:"name,optional"` allows entity fields to be missing. Pointer scalars
+// are optional and left nil when missing, other fields without default are required, like the fields flagged
+// `option:"name,required"`. The autocomplete flag is read by CommandSchema, which defines the command of the same
+// struct. Every failing field is listed in a *BindError
+func (ctx *ConnectionContext) BindOptions(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("httpcord: BindOptions needs a pointer to a struct")
+	}
+
+	if ctx.Interaction.Type != ApplicationCommandInteraction && ctx.Interaction.Type != AutoCompleteInteraction {
+		return errors.New("httpcord: BindOptions needs an application command interaction")
+	}
+
+	resolved, resolvedErr := ctx.Resolved()
+	if resolved == nil {
+		resolved = &ResolvedData{}
+	}
+
+	var bindErr BindError
+	target := rv.Elem()
+
+	for _, field := range bindFields(target.Type()) {
+		fail := func(err error) {
+			bindErr.Fields = append(bindErr.Fields, &FieldBindError{Field: field.name, Option: field.option, Err: err})
+		}
+
+		if field.err != nil {
+			fail(field.err)
+			continue
+		}
+
+		var value interface{}
+		option, ok := ctx.Option(field.option)
+		if ok {
+			value = option.Value
+		} else if field.defaultRaw != nil {
+			value, ok = *field.defaultRaw, true
+		}
+
+		if !ok {
+			if field.required {
+				fail(ErrOptionMissing)
+			}
+
+			continue
+		}
+
+		if field.kind >= bindUser {
+			if resolvedErr != nil {
+				fail(resolvedErr)
+				continue
+			}
+
+			entity, err := resolveEntity(resolved, field.kind, value)
+			if err != nil {
+				fail(err)
+				continue
+			}
+
+			target.Field(field.index).Set(entity)
+			continue
+		}
+
+		fv := target.Field(field.index)
+		if field.pointer {
+			ptr := reflect.New(fv.Type().Elem())
+			fv.Set(ptr)
+			fv = ptr.Elem()
+		}
+
+		if err := setScalar(fv, field.kind, value); err != nil {
+			fail(err)
+		}
+	}
+
+	if len(bindErr.Fields) > 0 {
+		return &bindErr
+	}
+
+	return nil
+}
+
+// setScalar Convert the option value, defaults are always strings
+func setScalar(fv reflect.Value, kind bindKind, value interface{}) error {
+	switch kind {
+	case bindString, bindSnowflake:
+		s, ok := value.(string)
+		if !ok {
+			return ErrOptionType
+		}
+
+		fv.SetString(s)
+	case bindBool:
+		switch b := value.(type) {
+		case bool:
+			fv.SetBool(b)
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return ErrOptionType
+			}
+
+			fv.SetBool(parsed)
+		default:
+			return ErrOptionType
+		}
+	case bindInt:
+		var n int64
+
+		switch number := value.(type) {
+		case float64:
+			if number != math.Trunc(number) {
+				return ErrOptionType
+			}
+
+			n = int64(number)
+		case string:
+			parsed, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return ErrOptionType
+			}
+
+			n = parsed
+		default:
+			return ErrOptionType
+		}
+
+		if fv.OverflowInt(n) {
+			return ErrOptionType
+		}
+
+		fv.SetInt(n)
+	case bindFloat:
+		switch number := value.(type) {
+		case float64:
+			fv.SetFloat(number)
+		case string:
+			parsed, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return ErrOptionType
+			}
+
+			fv.SetFloat(parsed)
+		default:
+			return ErrOptionType
+		}
+	}
+
+	return nil
+}
+
+// resolveEntity Look the option ID up in the resolved data
+func resolveEntity(resolved *ResolvedData, kind bindKind, value interface{}) (reflect.Value, error) {
+	s, ok := value.(string)
+	if !ok {
+		return reflect.Value{}, ErrOptionType
+	}
+
+	id := Snowflake(s)
+	var entity interface{}
+
+	switch kind {
+	case bindUser:
+		if user, ok := resolved.Users[id]; ok {
+			entity = user
+		}
+	case bindMember:
+		// A copy with its user, the resolved member is shared by the copies of the context
+		if member, ok := resolvedMember(resolved, id); ok {
+			entity = member
+		}
+	case bindRole:
+		if role, ok := resolved.Roles[id]; ok {
+			entity = role
+		}
+	case bindChannel:
+		if channel, ok := resolved.Channels[id]; ok {
+			entity = channel
+		}
+	case bindAttachment:
+		if attachment, ok := resolved.Attachments[id]; ok {
+			entity = attachment
+		}
+	}
+
+	if entity == nil {
+		return reflect.Value{}, fmt.Errorf("%w: %s not resolved", ErrOptionType, id)
+	}
+
+	return reflect.ValueOf(entity), nil
+}
@@ -0,0 +1,313 @@
+package httpcord
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BindingKind Kind of route bound to a method by RegisterHandlers
+type BindingKind string
+
+// Binding Kinds
+
+const (
+	CommandBinding      BindingKind = "command"
+	AutocompleteBinding BindingKind = "autocomplete"
+	ComponentBinding    BindingKind = "component"
+)
+
+// HandlerRoutes Implemented by the services of RegisterHandlers to replace the route derived from a method name,
+// keyed by the method name like {"CommandConfigSet": "config set", "ComponentPage": "page:*"}
+type HandlerRoutes interface {
+	HandlerRoutes() map[string]string
+}
+
+// Binding Method of a service bound by RegisterHandlers
+type Binding struct {
+	Method string
+	Kind   BindingKind
+	// Route is the command name or the component pattern
+	Route string
+}
+
+// BindingReport Methods bound by RegisterHandlers, Skipped are the methods without a handler prefix
+type BindingReport struct {
+	Bindings []Binding
+	Skipped  []string
+}
+
+// BindingError Methods RegisterHandlers could not bind keyed by method name, nothing is registered when returned
+type BindingError struct {
+	Methods map[string]string
+}
+
+func (e *BindingError) Error() string {
+	names := make([]string, 0, len(e.Methods))
+	for name := range e.Methods {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = name + ": " + e.Methods[name]
+	}
+
+	return "httpcord: could not bind the handlers " + strings.Join(problems, "; ")
+}
+
+// bindingPrefixes Method name prefixes and the kind of route they bind
+var bindingPrefixes = []struct {
+	prefix string
+	kind   BindingKind
+}{
+	{"Autocomplete", AutocompleteBinding},
+	{"Command", CommandBinding},
+	{"Component", ComponentBinding},
+}
+
+var (
+	handlerType      = reflect.TypeOf(Handler(nil))
+	errorHandlerType = reflect.TypeOf((func(ctx ConnectionContext) error)(nil))
+)
+
+// methodBinding Method of a service type matching a handler prefix
+type methodBinding struct {
+	index int
+	name  string
+	kind  BindingKind
+	route string
+	// problem is why the method can not be bound
+	problem string
+}
+
+type typeBindings struct {
+	methods []methodBinding
+	skipped []string
+}
+
+// bindingCache Methods of the service types already scanned
+var bindingCache sync.Map
+
+func scanBindings(t reflect.Type) *typeBindings {
+	if cached, ok := bindingCache.Load(t); ok {
+		return cached.(*typeBindings)
+	}
+
+	bindings := &typeBindings{}
+
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+
+		kind, rest := bindingKind(method.Name)
+		if kind == "" {
+			if method.Name != "HandlerRoutes" {
+				bindings.skipped = append(bindings.skipped, method.Name)
+			}
+
+			continue
+		}
+
+		binding := methodBinding{index: i, name: method.Name, kind: kind, route: snakeCase(rest)}
+		if kind == ComponentBinding {
+			binding.route += ":*"
+		}
+
+		// The receiver is already bound in method values
+		if fn := method.Func.Type(); fn.NumIn() != 2 || fn.In(1) != handlerType.In(0) || fn.NumOut() > 1 ||
+			(fn.NumOut() == 1 && fn.Out(0) != errorHandlerType.Out(0)) {
+			binding.problem = "must be func(ctx ConnectionContext) or func(ctx ConnectionContext) error"
+		}
+
+		bindings.methods = append(bindings.methods, binding)
+	}
+
+	cached, _ := bindingCache.LoadOrStore(t, bindings)
+	return cached.(*typeBindings)
+}
+
+// bindingKind Kind of route bound by the method name and the name without its prefix
+func bindingKind(name string) (kind BindingKind, rest string) {
+	for _, p := range bindingPrefixes {
+		rest := strings.TrimPrefix(name, p.prefix)
+
+		if rest != name && rest != "" && unicode.IsUpper(rune(rest[0])) {
+			return p.kind, rest
+		}
+	}
+
+	return "", ""
+}
+
+// snakeCase "ConfirmBan" becomes "confirm_ban" and "BanURL" becomes "ban_url"
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// RegisterHandlers Bind the methods of svc named after their route: CommandBan handles the "ban" command,
+// AutocompleteBan its autocomplete and ComponentConfirmBan the "confirm_ban:*" components (See HandlerRoutes).
+// Methods are func(ctx ConnectionContext) or func(ctx ConnectionContext) error, returned errors are sent with ErrorReply.
+// Services passed by value are copied so the methods with a pointer receiver are bound too.
+// When a method can not be bound a BindingError is returned and nothing is registered
+func (c *Connection) RegisterHandlers(svc interface{}) (*BindingReport, error) {
+	value := reflect.ValueOf(svc)
+	if !value.IsValid() {
+		return nil, &BindingError{Methods: map[string]string{"": "nil service"}}
+	}
+
+	if value.Kind() != reflect.Ptr {
+		copied := reflect.New(value.Type())
+		copied.Elem().Set(value)
+		value = copied
+	}
+
+	var overrides map[string]string
+	if routes, ok := value.Interface().(HandlerRoutes); ok {
+		overrides = routes.HandlerRoutes()
+	}
+
+	scanned := scanBindings(value.Type())
+	source := callerSite(1)
+	problems := make(map[string]string)
+	routes := make(map[string]string)
+	report := &BindingReport{Skipped: append([]string(nil), scanned.skipped...)}
+
+	bound := make(map[string]bool)
+	for _, m := range scanned.methods {
+		bound[m.name] = true
+	}
+
+	for name := range overrides {
+		if !bound[name] {
+			problems[name] = "HandlerRoutes names a method without a handler prefix"
+		}
+	}
+
+	handlers := make(map[string]Handler, len(scanned.methods))
+
+	for _, m := range scanned.methods {
+		if m.problem != "" {
+			problems[m.name] = m.problem
+			continue
+		}
+
+		route := m.route
+		if override, ok := overrides[m.name]; ok {
+			route = override
+		}
+
+		if m.kind != ComponentBinding {
+			route = strings.Join(strings.Fields(route), " ")
+		}
+
+		key := string(m.kind) + " " + route
+		if other, ok := routes[key]; ok {
+			problems[m.name] = fmt.Sprintf("%s %q is also bound by %s", m.kind, route, other)
+			problems[other] = fmt.Sprintf("%s %q is also bound by %s", m.kind, route, m.name)
+			continue
+		}
+
+		routes[key] = m.name
+
+		if problem := c.bindingConflict(m.kind, route); problem != "" {
+			problems[m.name] = problem
+			continue
+		}
+
+		handlers[m.name] = methodHandler(value.Method(m.index))
+		report.Bindings = append(report.Bindings, Binding{Method: m.name, Kind: m.kind, Route: route})
+	}
+
+	// Autocompletes are attached to the commands, registered ones or bound by this service
+	for _, b := range report.Bindings {
+		if b.Kind != AutocompleteBinding {
+			continue
+		}
+
+		if _, ok := routes[string(CommandBinding)+" "+b.Route]; !ok && c.router.get(b.Route) == nil {
+			problems[b.Method] = fmt.Sprintf("no command %q to autocomplete", b.Route)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &BindingError{Methods: problems}
+	}
+
+	for _, b := range report.Bindings {
+		switch b.Kind {
+		case CommandBinding:
+			c.router.add(&CommandRoute{Name: b.Route, Handler: handlers[b.Method], Source: source})
+		case ComponentBinding:
+			c.router.addUserComponent(&ComponentRoute{Pattern: b.Route, Handler: handlers[b.Method], Source: source})
+		}
+	}
+
+	for _, b := range report.Bindings {
+		if b.Kind == AutocompleteBinding {
+			c.router.get(b.Route).setAutocomplete(handlers[b.Method], source)
+		}
+	}
+
+	return report, nil
+}
+
+// bindingConflict Why the route can not be registered on the connection, empty when it can
+func (c *Connection) bindingConflict(kind BindingKind, route string) string {
+	switch kind {
+	case CommandBinding:
+		if existing := c.router.get(route); existing != nil {
+			return fmt.Sprintf("command %q is already registered at %s", route, existing.Source)
+		}
+	case AutocompleteBinding:
+		if existing := c.router.get(route); existing != nil && existing.Autocomplete != nil {
+			return fmt.Sprintf("command %q already has an autocomplete handler set at %s", route, existing.autocompleteSource(""))
+		}
+	case ComponentBinding:
+		c.router.mu.RLock()
+		existing := c.router.userComponents.find(route)
+		c.router.mu.RUnlock()
+
+		if existing != nil {
+			return fmt.Sprintf("component %q is already registered at %s", route, existing.Source)
+		}
+	}
+
+	return ""
+}
+
+// methodHandler Handler calling the bound method, errors are handled like the errors raised during dispatch
+func methodHandler(method reflect.Value) Handler {
+	switch fn := method.Interface().(type) {
+	case func(ctx ConnectionContext):
+		return fn
+	case func(ctx ConnectionContext) error:
+		return func(ctx ConnectionContext) {
+			if err := fn(ctx); err != nil {
+				ctx.handleError(err)
+			}
+		}
+	}
+
+	return nil
+}
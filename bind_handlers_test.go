@@ -0,0 +1,280 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// moderationService Service bound by RegisterHandlers, the replies name the method that ran
+type moderationService struct {
+	warned int
+}
+
+func (s moderationService) HandlerRoutes() map[string]string {
+	return map[string]string{"CommandConfigSet": "config  set", "ComponentPageTurn": "page:*"}
+}
+
+func (s moderationService) CommandBan(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "CommandBan"})
+}
+
+func (s moderationService) AutocompleteBan(ctx ConnectionContext) {
+	ctx.RespondAutocomplete([]ApplicationCommandOptionChoice{{Name: "AutocompleteBan", Value: "1"}})
+}
+
+func (s moderationService) CommandKick(ctx ConnectionContext) error {
+	return ctx.ReplyInteraction(&InteractionCallbackData{Content: "CommandKick"})
+}
+
+func (s moderationService) CommandMute(ctx ConnectionContext) error {
+	return errors.New("muting is disabled")
+}
+
+func (s moderationService) CommandBanURL(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "CommandBanURL"})
+}
+
+func (s moderationService) CommandConfigSet(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "CommandConfigSet"})
+}
+
+func (s *moderationService) CommandWarn(ctx ConnectionContext) {
+	s.warned++
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: fmt.Sprintf("CommandWarn %d", s.warned)})
+}
+
+func (s moderationService) ComponentConfirmBan(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "ComponentConfirmBan " + ctx.CustomIDParam()})
+}
+
+func (s moderationService) ComponentPageTurn(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "ComponentPageTurn " + ctx.CustomIDParam()})
+}
+
+func (s moderationService) ComponentCancel(ctx ConnectionContext) {
+	ctx.ReplyInteraction(&InteractionCallbackData{Content: "ComponentCancel " + ctx.CustomIDParam()})
+}
+
+// Describe Not a handler, skipped
+func (s moderationService) Describe() string {
+	return "moderation"
+}
+
+// Commander Prefix without an uppercase letter after it, skipped
+func (s moderationService) Commander() {}
+
+func TestRegisterHandlers(t *testing.T) {
+	conn, sign := signedConnection(t, ConnectionOptions{})
+
+	report, err := conn.RegisterHandlers(moderationService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bound := make(map[string]string)
+	for _, binding := range report.Bindings {
+		bound[binding.Method] = string(binding.Kind) + " " + binding.Route
+	}
+
+	want := map[string]string{
+		"AutocompleteBan":     "autocomplete ban",
+		"CommandBan":          "command ban",
+		"CommandBanURL":       "command ban_url",
+		"CommandConfigSet":    "command config set",
+		"CommandKick":         "command kick",
+		"CommandMute":         "command mute",
+		"CommandWarn":         "command warn",
+		"ComponentCancel":     "component cancel:*",
+		"ComponentConfirmBan": "component confirm_ban:*",
+		"ComponentPageTurn":   "component page:*",
+	}
+
+	for method, route := range want {
+		if bound[method] != route {
+			t.Errorf("%s bound to %q, want %q", method, bound[method], route)
+		}
+	}
+
+	if len(bound) != len(want) {
+		t.Errorf("bound %v, want %d methods", bound, len(want))
+	}
+
+	if strings.Join(report.Skipped, ",") != "Commander,Describe" {
+		t.Errorf("skipped %q, want Commander and Describe", report.Skipped)
+	}
+
+	command := func(name string) []byte {
+		return interactionBody(ApplicationCommandInteraction, fmt.Sprintf(`{"id":"5","name":%q,"type":1}`, name))
+	}
+
+	component := func(customID string) []byte {
+		return interactionBody(MessageComponentInteraction, fmt.Sprintf(`{"custom_id":%q,"component_type":2}`, customID))
+	}
+
+	tests := []struct {
+		name string
+		body []byte
+		// reply Content of the message or name of the autocomplete choice
+		reply string
+	}{
+		{"command", command("ban"), "CommandBan"},
+		{"command returning nil", command("kick"), "CommandKick"},
+		{"command returning an error", command("mute"), GenericErrorMessages[EnglishUSLocale]},
+		{"initialism", command("ban_url"), "CommandBanURL"},
+		{"overridden subcommand route", []byte(strings.Replace(string(command("config")), `"type":1}`,
+			`"type":1,"options":[{"type":1,"name":"set"}]}`, 1)), "CommandConfigSet"},
+		{"pointer receiver", command("warn"), "CommandWarn 1"},
+		{"pointer receiver on the same copy", command("warn"), "CommandWarn 2"},
+		{"autocomplete", interactionBody(AutoCompleteInteraction, `{"id":"5","name":"ban","type":1,`+
+			`"options":[{"type":3,"name":"reason","value":"sp","focused":true}]}`), "AutocompleteBan"},
+		{"component", component("confirm_ban:6"), "ComponentConfirmBan 6"},
+		{"overridden component route", component("page:3"), "ComponentPageTurn 3"},
+		{"component without param", component("cancel:"), "ComponentCancel "},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			var response struct {
+				Data struct {
+					Content string                           `json:"content"`
+					Choices []ApplicationCommandOptionChoice `json:"choices"`
+				} `json:"data"`
+			}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%d %s: %v", w.Code, w.Body, err)
+			}
+
+			reply := response.Data.Content
+			if len(response.Data.Choices) > 0 {
+				reply = response.Data.Choices[0].Name
+			}
+
+			if reply != test.reply {
+				t.Errorf("replied %q, want %q", reply, test.reply)
+			}
+		})
+	}
+}
+
+type wrongArgumentService struct{}
+
+func (wrongArgumentService) CommandBan(ctx ConnectionContext, reason string) {}
+
+type pointerContextService struct{}
+
+func (pointerContextService) CommandBan(ctx *ConnectionContext) {}
+
+type wrongResultService struct{}
+
+func (wrongResultService) CommandBan(ctx ConnectionContext) string { return "" }
+
+type twoResultsService struct{}
+
+func (twoResultsService) CommandBan(ctx ConnectionContext) (bool, error) { return false, nil }
+
+type duplicateRouteService struct{}
+
+func (duplicateRouteService) HandlerRoutes() map[string]string {
+	return map[string]string{"CommandRemove": "ban"}
+}
+
+func (duplicateRouteService) CommandBan(ctx ConnectionContext)    {}
+func (duplicateRouteService) CommandRemove(ctx ConnectionContext) {}
+
+type unknownOverrideService struct{}
+
+func (unknownOverrideService) HandlerRoutes() map[string]string {
+	return map[string]string{"Ban": "ban"}
+}
+
+func (unknownOverrideService) CommandKick(ctx ConnectionContext) {}
+
+type orphanAutocompleteService struct{}
+
+func (orphanAutocompleteService) AutocompleteBan(ctx ConnectionContext) {}
+
+type validAndInvalidService struct{}
+
+func (validAndInvalidService) CommandKick(ctx ConnectionContext)       {}
+func (validAndInvalidService) CommandBan(ctx ConnectionContext, n int) {}
+
+func TestRegisterHandlersErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     interface{}
+		setup   func(c *Connection)
+		methods []string
+	}{
+		{"extra argument", wrongArgumentService{}, nil, []string{"CommandBan"}},
+		{"context pointer", pointerContextService{}, nil, []string{"CommandBan"}},
+		{"non error result", wrongResultService{}, nil, []string{"CommandBan"}},
+		{"two results", twoResultsService{}, nil, []string{"CommandBan"}},
+		{"two methods for a route", duplicateRouteService{}, nil, []string{"CommandBan", "CommandRemove"}},
+		{"override of an unknown method", unknownOverrideService{}, nil, []string{"Ban"}},
+		{"autocomplete without command", orphanAutocompleteService{}, nil, []string{"AutocompleteBan"}},
+		{"already registered command", validAndInvalidService{}, func(c *Connection) {
+			c.Command("kick", func(ConnectionContext) {})
+		}, []string{"CommandBan", "CommandKick"}},
+		{"nil service", nil, nil, []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{})
+			if test.setup != nil {
+				test.setup(conn)
+			}
+
+			commands := len(conn.router.routes())
+
+			report, err := conn.RegisterHandlers(test.svc)
+
+			var bindingErr *BindingError
+			if !errors.As(err, &bindingErr) || report != nil {
+				t.Fatalf("RegisterHandlers() = %v, %v, want a BindingError", report, err)
+			}
+
+			if len(bindingErr.Methods) != len(test.methods) {
+				t.Errorf("problems %v, want the methods %q", bindingErr.Methods, test.methods)
+			}
+
+			for _, method := range test.methods {
+				if _, ok := bindingErr.Methods[method]; !ok {
+					t.Errorf("%s missing from %v", method, bindingErr.Methods)
+				}
+			}
+
+			if got := len(conn.router.routes()); got != commands {
+				t.Errorf("%d commands registered, want %d, nothing is registered on errors", got, commands)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Ban", "ban"},
+		{"ConfirmBan", "confirm_ban"},
+		{"BanURL", "ban_url"},
+		{"URLBan", "url_ban"},
+		{"Page2", "page2"},
+		{"Top10Users", "top10_users"},
+	}
+
+	for _, test := range tests {
+		if got := snakeCase(test.name); got != test.want {
+			t.Errorf("snakeCase(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package httpcord
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// bindContext Context of the "ban" command invoked with the options and resolved data
+func bindContext(t testing.TB, options []ApplicationCommandOption, resolved ResolvedData) *ConnectionContext {
+	t.Helper()
+
+	interaction := Interaction{
+		ID:   nowSnowflake(),
+		Type: ApplicationCommandInteraction,
+		Data: ApplicationCommandInteractionData{ID: "5", Name: "ban", Options: options, Resolved: resolved},
+	}
+
+	ctx, finish := NewContext(interaction, ContextConfig{Respond: func(*InteractionResponse) error { return nil }, Webhooks: &countingWebhooks{}})
+	t.Cleanup(finish)
+
+	return ctx
+}
+
+// fieldBindErrors Field names and errors of the BindError
+func fieldBindErrors(t *testing.T, err error) map[string]error {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("BindOptions() = %v, want a BindError", err)
+	}
+
+	fields := make(map[string]error, len(bindErr.Fields))
+	for _, field := range bindErr.Fields {
+		fields[field.Field] = field
+	}
+
+	return fields
+}
+
+type resolvedArgs struct {
+	User    *User     `option:"user"`
+	Member  *Member   `option:"user,optional"`
+	Channel *Channel  `option:"channel,optional"`
+	Role    *Role     `option:"role,optional"`
+	Target  Snowflake `option:",optional"`
+	Reason  string    `option:",default=No reason"`
+	Days    *int      `option:"delete_days"`
+	Notify  *bool     `option:"notify,required"`
+	Ignored string
+}
+
+func TestBindOptions(t *testing.T) {
+	user := &User{ID: "6", Username: "target"}
+	member := &Member{Nick: "troublemaker"}
+	channel := &Channel{ID: "7", Name: "general"}
+	role := &Role{ID: "8", Name: "muted"}
+
+	resolved := ResolvedData{
+		Users:    map[Snowflake]*User{"6": user},
+		Members:  map[Snowflake]*Member{"6": member},
+		Channels: map[Snowflake]*Channel{"7": channel},
+		Roles:    map[Snowflake]*Role{"8": role},
+	}
+
+	days := 7
+	notify := true
+
+	userOption := ApplicationCommandOption{Type: UserApplicationCommandOptionType, Name: "user", Value: "6"}
+	notifyOption := ApplicationCommandOption{Type: BoolApplicationCommandOptionType, Name: "notify", Value: true}
+
+	tests := []struct {
+		name    string
+		options []ApplicationCommandOption
+		want    resolvedArgs
+		// errs Errors of the fields that could not be bound
+		errs map[string]error
+	}{
+		{"resolved user and member", []ApplicationCommandOption{userOption, notifyOption},
+			resolvedArgs{User: user, Member: &Member{Nick: "troublemaker", User: user}, Reason: "No reason", Notify: &notify}, nil},
+		{"resolved channel and role", []ApplicationCommandOption{userOption, notifyOption,
+			{Type: ChannelApplicationCommandOptionType, Name: "channel", Value: "7"},
+			{Type: RoleApplicationCommandOptionType, Name: "role", Value: "8"},
+		}, resolvedArgs{User: user, Member: &Member{Nick: "troublemaker", User: user}, Channel: channel, Role: role, Reason: "No reason", Notify: &notify}, nil},
+		{"snake_case names", []ApplicationCommandOption{userOption, notifyOption,
+			{Type: StringApplicationCommandOptionType, Name: "reason", Value: "spam"},
+			{Type: MentionableApplicationCommandOptionType, Name: "target", Value: "8"},
+			{Type: IntApplicationCommandOptionType, Name: "delete_days", Value: float64(7)},
+		}, resolvedArgs{User: user, Member: &Member{Nick: "troublemaker", User: user}, Target: "8", Reason: "spam", Days: &days, Notify: &notify}, nil},
+		{"options of the invoked subcommand", []ApplicationCommandOption{{Type: SubCommandApplicationCommandOptionType, Name: "member",
+			Options: []ApplicationCommandOption{userOption, notifyOption}}},
+			resolvedArgs{User: user, Member: &Member{Nick: "troublemaker", User: user}, Reason: "No reason", Notify: &notify}, nil},
+		{"missing required options", nil, resolvedArgs{Reason: "No reason"},
+			map[string]error{"User": ErrOptionMissing, "Notify": ErrOptionMissing}},
+		{"wrong types", []ApplicationCommandOption{userOption,
+			{Type: StringApplicationCommandOptionType, Name: "notify", Value: "maybe"},
+			{Type: StringApplicationCommandOptionType, Name: "delete_days", Value: "a week"},
+			{Type: IntApplicationCommandOptionType, Name: "reason", Value: float64(3)},
+		}, resolvedArgs{User: user, Member: &Member{Nick: "troublemaker", User: user}, Days: new(int), Notify: new(bool)},
+			map[string]error{"Notify": ErrOptionType, "Days": ErrOptionType, "Reason": ErrOptionType}},
+		{"not resolved", []ApplicationCommandOption{notifyOption,
+			{Type: UserApplicationCommandOptionType, Name: "user", Value: "9"},
+			{Type: RoleApplicationCommandOptionType, Name: "role", Value: "7"},
+		}, resolvedArgs{Reason: "No reason", Notify: &notify},
+			map[string]error{"User": ErrOptionType, "Member": ErrOptionType, "Role": ErrOptionType}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := bindContext(t, test.options, resolved)
+
+			var args resolvedArgs
+			errs := fieldBindErrors(t, ctx.BindOptions(&args))
+
+			if len(errs) != len(test.errs) {
+				t.Errorf("errors %v, want %v", errs, test.errs)
+			}
+
+			for field, want := range test.errs {
+				if !errors.Is(errs[field], want) {
+					t.Errorf("field %s error %v, want %v", field, errs[field], want)
+				}
+			}
+
+			if !reflect.DeepEqual(args, test.want) {
+				t.Errorf("bound %+v, want %+v", args, test.want)
+			}
+		})
+	}
+
+	if member.User != nil {
+		t.Error("the user was set on the resolved member")
+	}
+}
+
+type scalarArgs struct {
+	Name    string    `option:"name,optional"`
+	Silent  bool      `option:"silent,default=true"`
+	Days    int64     `option:"days,default=0"`
+	Level   int8      `option:"level,optional"`
+	Ratio   float64   `option:"ratio,default=1.5"`
+	Channel Snowflake `option:"channel,optional"`
+	Limit   *int      `option:"limit"`
+}
+
+func TestBindOptionsCoercion(t *testing.T) {
+	limit := 3
+
+	option := func(name string, value interface{}) ApplicationCommandOption {
+		return ApplicationCommandOption{Name: name, Value: value}
+	}
+
+	tests := []struct {
+		name    string
+		options []ApplicationCommandOption
+		want    scalarArgs
+		errs    map[string]error
+	}{
+		{"defaults from the tags", nil, scalarArgs{Silent: true, Ratio: 1.5}, nil},
+		{"sent values win over defaults", []ApplicationCommandOption{option("silent", false), option("days", float64(7)), option("ratio", 0.25)},
+			scalarArgs{Days: 7, Ratio: 0.25}, nil},
+		{"every scalar", []ApplicationCommandOption{option("name", "spam"), option("level", float64(-3)), option("channel", "7"), option("limit", float64(3))},
+			scalarArgs{Name: "spam", Silent: true, Level: -3, Ratio: 1.5, Channel: "7", Limit: &limit}, nil},
+		{"integer given as a number", []ApplicationCommandOption{option("ratio", float64(2))}, scalarArgs{Silent: true, Ratio: 2}, nil},
+		{"fraction in an integer", []ApplicationCommandOption{option("days", 1.5)}, scalarArgs{Silent: true, Ratio: 1.5},
+			map[string]error{"Days": ErrOptionType}},
+		{"overflow", []ApplicationCommandOption{option("level", float64(300))}, scalarArgs{Silent: true, Ratio: 1.5},
+			map[string]error{"Level": ErrOptionType}},
+		{"every failing field", []ApplicationCommandOption{option("name", true), option("silent", "yes"), option("channel", float64(7))},
+			scalarArgs{Ratio: 1.5}, map[string]error{"Name": ErrOptionType, "Silent": ErrOptionType, "Channel": ErrOptionType}},
+		{"pointer left nil when missing", []ApplicationCommandOption{option("name", "spam")}, scalarArgs{Name: "spam", Silent: true, Ratio: 1.5}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := bindContext(t, test.options, ResolvedData{})
+
+			var args scalarArgs
+			errs := fieldBindErrors(t, ctx.BindOptions(&args))
+
+			if len(errs) != len(test.errs) {
+				t.Errorf("errors %v, want %v", errs, test.errs)
+			}
+
+			for field, want := range test.errs {
+				if !errors.Is(errs[field], want) {
+					t.Errorf("field %s error %v, want %v", field, errs[field], want)
+				}
+			}
+
+			if !reflect.DeepEqual(args, test.want) {
+				t.Errorf("bound %+v, want %+v", args, test.want)
+			}
+		})
+	}
+}
+
+func TestBindOptionsInvalidTargets(t *testing.T) {
+	var badDefault struct {
+		Days int `option:"days,default=week"`
+	}
+
+	var unknownFlag struct {
+		Days int `option:"days,sometimes"`
+	}
+
+	var unsupported struct {
+		Tags []string `option:"tags"`
+	}
+
+	var args scalarArgs
+
+	tests := []struct {
+		name   string
+		target interface{}
+		// field Field of the BindError, empty when BindOptions refuses the target
+		field string
+	}{
+		{"default of another type", &badDefault, "Days"},
+		{"unknown tag flag", &unknownFlag, "Days"},
+		{"unsupported field type", &unsupported, "Tags"},
+		{"struct passed by value", args, ""},
+		{"pointer to a scalar", new(int), ""},
+		{"nil", nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := bindContext(t, nil, ResolvedData{})
+
+			err := ctx.BindOptions(test.target)
+			if err == nil {
+				t.Fatal("BindOptions() = nil, want an error")
+			}
+
+			var bindErr *BindError
+			if isBindErr := errors.As(err, &bindErr); isBindErr != (test.field != "") {
+				t.Fatalf("BindOptions() = %v, want a BindError = %v", err, test.field != "")
+			}
+
+			if bindErr != nil && (len(bindErr.Fields) != 1 || bindErr.Fields[0].Field != test.field) {
+				t.Errorf("BindOptions() = %v, want an error for %s", err, test.field)
+			}
+		})
+	}
+
+	t.Run("not a command", func(t *testing.T) {
+		ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: MessageComponentInteraction, Data: ComponentInteractionData{CustomID: "page:1"}},
+			ContextConfig{Respond: func(*InteractionResponse) error { return nil }, Webhooks: &countingWebhooks{}})
+		defer finish()
+
+		if err := ctx.BindOptions(&args); err == nil {
+			t.Error("BindOptions() = nil for a component interaction")
+		}
+	})
+}
+
+func BenchmarkBindOptions(b *testing.B) {
+	ctx := bindContext(b, []ApplicationCommandOption{
+		{Name: "name", Value: "spam"}, {Name: "days", Value: float64(7)}, {Name: "channel", Value: "7"}, {Name: "limit", Value: float64(3)},
+	}, ResolvedData{})
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var args scalarArgs
+		if err := ctx.BindOptions(&args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
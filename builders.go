@@ -0,0 +1,597 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxRowButtons Maximum buttons of an action row, select menus and text inputs are alone in their row
+	MaxRowButtons = 5
+	// MaxSelectOptions Maximum options and values of a select menu
+	MaxSelectOptions = 25
+	// MaxEmbedFields Maximum fields of an embed
+	MaxEmbedFields = 25
+	// MaxModalComponents Maximum action rows of a modal, each holding one text input
+	MaxModalComponents = 5
+	// MaxTextInputLength Maximum length of the text input values
+	MaxTextInputLength = 4000
+
+	maxButtonLabelLength       = 80
+	maxSelectOptionLength      = 100
+	maxSelectPlaceholderLength = 150
+	maxEmbedTitleLength        = 256
+	maxEmbedDescriptionLength  = 4096
+	maxEmbedFieldNameLength    = 256
+	maxEmbedFieldValueLength   = 1024
+	maxEmbedFooterLength       = 2048
+	maxEmbedAuthorLength       = 256
+	maxModalTitleLength        = 45
+	maxTextInputLabelLength    = 45
+	maxTextInputPlaceholder    = 100
+)
+
+var (
+	// ErrInvalidComponent The component would be refused by Discord (See ComponentValidationError)
+	ErrInvalidComponent = errors.New("httpcord: invalid message component")
+	// ErrInvalidEmbed The embed would be refused by Discord (See EmbedValidationError)
+	ErrInvalidEmbed = errors.New("httpcord: invalid embed")
+)
+
+// ComponentValidationError Field of a message component breaking the limits of Discord, see ActionRowComponent.Build
+type ComponentValidationError struct {
+	// Field is the path of the field like "components", "components.1.url" or "components.0.options.3.label"
+	Field    string
+	CustomID string
+	Reason   string
+}
+
+func (e *ComponentValidationError) Error() string {
+	if e.CustomID == "" {
+		return fmt.Sprintf("httpcord: component %s %s", e.Field, e.Reason)
+	}
+
+	return fmt.Sprintf("httpcord: component %q %s %s", e.CustomID, e.Field, e.Reason)
+}
+
+func (e *ComponentValidationError) Is(target error) bool {
+	return target == ErrInvalidComponent
+}
+
+// EmbedValidationError Field of an embed breaking the limits of Discord, see Embed.Build
+type EmbedValidationError struct {
+	// Field is the path of the field like "title", "fields" or "fields.2.value"
+	Field  string
+	Reason string
+}
+
+func (e *EmbedValidationError) Error() string {
+	return fmt.Sprintf("httpcord: embed %s %s", e.Field, e.Reason)
+}
+
+func (e *EmbedValidationError) Is(target error) bool {
+	return target == ErrInvalidEmbed
+}
+
+// NewActionRow Action row builder, see Build for the limits checked
+func NewActionRow() *ActionRowComponent {
+	return NewActionRowComponentBuilder()
+}
+
+// AddButton Add a button answering to customID, use AddLinkButton for LinkButtonStyle
+func (a *ActionRowComponent) AddButton(style ButtonStyle, label, customID string) *ActionRowComponent {
+	return a.AddComponent(NewButtonComponentBuilder().SetStyle(style).SetLabel(label).SetCustomID(customID))
+}
+
+// AddEmojiButton Add a button showing the emoji without a label answering to customID
+func (a *ActionRowComponent) AddEmojiButton(style ButtonStyle, emoji *Emoji, customID string) *ActionRowComponent {
+	return a.AddComponent(NewButtonComponentBuilder().SetStyle(style).SetEmoji(emoji).SetCustomID(customID))
+}
+
+// AddLinkButton Add a LinkButtonStyle button opening the URL
+func (a *ActionRowComponent) AddLinkButton(label, URL string) *ActionRowComponent {
+	return a.AddComponent(NewButtonComponentBuilder().SetStyle(LinkButtonStyle).SetLabel(label).SetURL(URL))
+}
+
+// Build Check the row like Discord does: 1 to MaxRowButtons buttons or a single select menu or text input,
+// link buttons with a URL and no custom_id, the other buttons with a custom_id and no URL, and the labels, options
+// and custom_id lengths. Returns the row, ready for InteractionCallbackData.Components or WebhookEdit.Components,
+// or the first ComponentValidationError
+func (a *ActionRowComponent) Build() (*ActionRowComponent, error) {
+	if len(a.Components) == 0 {
+		return nil, &ComponentValidationError{Field: "components", Reason: "must have at least 1 component"}
+	}
+
+	for i, component := range a.Components {
+		field := "components." + strconv.Itoa(i) + "."
+
+		var err error
+		switch c := component.(type) {
+		case *ButtonComponent:
+			err = c.build(field)
+		case *SelectMenuComponent:
+			if len(a.Components) > 1 {
+				return nil, &ComponentValidationError{Field: strings.TrimSuffix(field, "."), CustomID: c.CustomID, Reason: "must be alone in its action row"}
+			}
+
+			err = c.build(field)
+		case *TextInputComponent:
+			if len(a.Components) > 1 {
+				return nil, &ComponentValidationError{Field: strings.TrimSuffix(field, "."), CustomID: c.CustomID, Reason: "must be alone in its action row"}
+			}
+
+			err = c.build(field)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(a.Components) > MaxRowButtons {
+		return nil, &ComponentValidationError{
+			Field:  "components",
+			Reason: "has " + strconv.Itoa(len(a.Components)) + " buttons, the limit is " + strconv.Itoa(MaxRowButtons),
+		}
+	}
+
+	return a, nil
+}
+
+// build Check the button, field is the path prefix of its fields
+func (b *ButtonComponent) build(field string) error {
+	fail := func(subfield, reason string) error {
+		return &ComponentValidationError{Field: field + subfield, CustomID: b.CustomID, Reason: reason}
+	}
+
+	switch b.Style {
+	case LinkButtonStyle:
+		if b.URL == "" {
+			return fail("url", "is required for link buttons")
+		}
+
+		if b.CustomID != "" {
+			return fail("custom_id", "must be empty for link buttons, they do not send interactions")
+		}
+	case PremiumButtonStyle:
+		if b.SKUID == "" {
+			return fail("sku_id", "is required for premium buttons")
+		}
+
+		if b.CustomID != "" || b.URL != "" || b.Label != "" || b.Emoji != nil {
+			return fail("sku_id", "must be the only field of premium buttons")
+		}
+
+		return nil
+	case PrimaryButtonStyle, SecondaryButtonStyle, SuccessButtonStyle, DangerButtonStyle:
+		if b.CustomID == "" {
+			return fail("custom_id", "is required, only link buttons have no custom_id")
+		}
+
+		if b.URL != "" {
+			return fail("url", "must be empty, only link buttons have a URL")
+		}
+
+		if err := checkCustomID(b.CustomID); err != nil {
+			return err
+		}
+	default:
+		return fail("style", "is not a ButtonStyle")
+	}
+
+	if b.Label == "" && b.Emoji == nil {
+		return fail("label", "or an emoji is required")
+	}
+
+	if length := utf8.RuneCountInString(b.Label); length > maxButtonLabelLength {
+		return fail("label", "has "+strconv.Itoa(length)+" characters, the limit is "+strconv.Itoa(maxButtonLabelLength))
+	}
+
+	return nil
+}
+
+// NewStringSelect String select menu builder answering to customID, see Build for the limits checked
+func NewStringSelect(customID string) *SelectMenuComponent {
+	return NewSelectMenuComponentBuilder().SetCustomID(customID)
+}
+
+// NewUserSelect User select menu builder answering to customID
+func NewUserSelect(customID string) *SelectMenuComponent {
+	return NewStringSelect(customID).SetType(UserSelectMenuComponentType)
+}
+
+// NewRoleSelect Role select menu builder answering to customID
+func NewRoleSelect(customID string) *SelectMenuComponent {
+	return NewStringSelect(customID).SetType(RoleSelectMenuComponentType)
+}
+
+// NewMentionableSelect User and role select menu builder answering to customID
+func NewMentionableSelect(customID string) *SelectMenuComponent {
+	return NewStringSelect(customID).SetType(MentionableSelectMenuComponentType)
+}
+
+// NewChannelSelect Channel select menu builder answering to customID, restricted to the types when there are some
+func NewChannelSelect(customID string, types ...ChannelType) *SelectMenuComponent {
+	return NewStringSelect(customID).SetType(ChannelSelectMenuComponentType).SetChannelTypes(types...)
+}
+
+// AddOptionValue Add the option of the label selecting the value
+func (s *SelectMenuComponent) AddOptionValue(label, value string) *SelectMenuComponent {
+	return s.AddOption(&ComponentOption{Label: label, Value: value})
+}
+
+// SetMinMax Number of values the user must select, between 0 and MaxSelectOptions
+func (s *SelectMenuComponent) SetMinMax(min, max int) *SelectMenuComponent {
+	s.MinValues = &min
+	s.MaxValues = &max
+	return s
+}
+
+// Build Check the select menu like Discord does: a custom_id, 1 to MaxSelectOptions options with unique values for
+// string selects, options for no other menu, default values of the entities of the menu, and min and max values the
+// options can satisfy. Returns the menu, to add to an action row, or the first ComponentValidationError
+// (See ActionRowComponent.Build)
+func (s *SelectMenuComponent) Build() (*SelectMenuComponent, error) {
+	if err := s.build(""); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// build Check the select menu, field is the path prefix of its fields
+func (s *SelectMenuComponent) build(field string) error {
+	fail := func(subfield, reason string) error {
+		return &ComponentValidationError{Field: field + subfield, CustomID: s.CustomID, Reason: reason}
+	}
+
+	if s.CustomID == "" {
+		return fail("custom_id", "is required")
+	}
+
+	if err := checkCustomID(s.CustomID); err != nil {
+		return err
+	}
+
+	if length := utf8.RuneCountInString(s.Placeholder); length > maxSelectPlaceholderLength {
+		return fail("placeholder", "has "+strconv.Itoa(length)+" characters, the limit is "+strconv.Itoa(maxSelectPlaceholderLength))
+	}
+
+	limit := MaxSelectOptions
+
+	if s.Type == SelectMenuComponentType {
+		if len(s.Options) == 0 || len(s.Options) > MaxSelectOptions {
+			return fail("options", "has "+strconv.Itoa(len(s.Options))+" options, string selects have 1 to "+strconv.Itoa(MaxSelectOptions))
+		}
+
+		values := make(map[string]bool, len(s.Options))
+		for i, option := range s.Options {
+			optionField := "options." + strconv.Itoa(i)
+
+			if err := selectOptionText(optionField+".label", option.Label, fail); err != nil {
+				return err
+			}
+
+			if err := selectOptionText(optionField+".value", option.Value, fail); err != nil {
+				return err
+			}
+
+			if length := utf8.RuneCountInString(option.Description); length > maxSelectOptionLength {
+				return fail(optionField+".description", "has "+strconv.Itoa(length)+" characters, the limit is "+strconv.Itoa(maxSelectOptionLength))
+			}
+
+			if values[option.Value] {
+				return fail(optionField+".value", "duplicates the value "+strconv.Quote(option.Value))
+			}
+
+			values[option.Value] = true
+		}
+
+		limit = len(s.Options)
+	} else if len(s.Options) > 0 {
+		return fail("options", "are only allowed in string selects")
+	}
+
+	if len(s.ChannelTypes) > 0 && s.Type != ChannelSelectMenuComponentType {
+		return fail("channel_types", "are only allowed in channel selects")
+	}
+
+	for i, value := range s.DefaultValues {
+		if s.Type == SelectMenuComponentType {
+			return fail("default_values", "are not allowed in string selects, use the default of the options")
+		}
+
+		if value == nil || !selectDefaultAllowed(s.Type, value.Type) {
+			return fail("default_values."+strconv.Itoa(i)+".type", "is not an entity of the select menu")
+		}
+	}
+
+	min, max := 1, 1
+	if s.MinValues != nil {
+		min = *s.MinValues
+	}
+
+	if s.MaxValues != nil {
+		max = *s.MaxValues
+	}
+
+	if min < 0 || min > MaxSelectOptions {
+		return fail("min_values", "is "+strconv.Itoa(min)+", it must be between 0 and "+strconv.Itoa(MaxSelectOptions))
+	}
+
+	if max < 1 || max > limit {
+		return fail("max_values", "is "+strconv.Itoa(max)+", it must be between 1 and "+strconv.Itoa(limit))
+	}
+
+	if min > max {
+		return fail("min_values", "is "+strconv.Itoa(min)+", more than max_values "+strconv.Itoa(max))
+	}
+
+	if len(s.DefaultValues) > max {
+		return fail("default_values", "has "+strconv.Itoa(len(s.DefaultValues))+" values, more than max_values "+strconv.Itoa(max))
+	}
+
+	return nil
+}
+
+// selectDefaultAllowed Whether the default value type is an entity of the select menu type
+func selectDefaultAllowed(menu ComponentType, kind string) bool {
+	switch menu {
+	case UserSelectMenuComponentType:
+		return kind == "user"
+	case RoleSelectMenuComponentType:
+		return kind == "role"
+	case MentionableSelectMenuComponentType:
+		return kind == "user" || kind == "role"
+	case ChannelSelectMenuComponentType:
+		return kind == "channel"
+	}
+
+	return false
+}
+
+// selectOptionText Check the label or value of a select option, 1 to maxSelectOptionLength characters
+func selectOptionText(field, text string, fail func(field, reason string) error) error {
+	if length := utf8.RuneCountInString(text); length < 1 || length > maxSelectOptionLength {
+		return fail(field, "has "+strconv.Itoa(length)+" characters, it must have 1 to "+strconv.Itoa(maxSelectOptionLength))
+	}
+
+	return nil
+}
+
+// NewEmbed Embed builder, see Build for the limits checked
+func NewEmbed() *Embed {
+	return NewEmbedBuilder()
+}
+
+// Build Check the embed like Discord does: the title, description, footer, author and field lengths, at most
+// MaxEmbedFields fields and MaxEmbedsCharacters characters (See Length). Returns the embed, ready for
+// InteractionCallbackData.Embeds or WebhookEdit.Embeds, or the first EmbedValidationError.
+// The characters of all the embeds of a message are checked together by ValidateEmbeds
+func (e *Embed) Build() (*Embed, error) {
+	text := func(field, value string, min, max int) error {
+		if length := utf8.RuneCountInString(value); length < min || length > max {
+			return &EmbedValidationError{
+				Field:  field,
+				Reason: "has " + strconv.Itoa(length) + " characters, it must have " + strconv.Itoa(min) + " to " + strconv.Itoa(max),
+			}
+		}
+
+		return nil
+	}
+
+	if err := text("title", e.Title, 0, maxEmbedTitleLength); err != nil {
+		return nil, err
+	}
+
+	if err := text("description", e.Description, 0, maxEmbedDescriptionLength); err != nil {
+		return nil, err
+	}
+
+	if e.Footer != nil {
+		if err := text("footer.text", e.Footer.Text, 1, maxEmbedFooterLength); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.Author != nil {
+		if err := text("author.name", e.Author.Name, 1, maxEmbedAuthorLength); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(e.Fields) > MaxEmbedFields {
+		return nil, &EmbedValidationError{
+			Field:  "fields",
+			Reason: "has " + strconv.Itoa(len(e.Fields)) + " fields, the limit is " + strconv.Itoa(MaxEmbedFields),
+		}
+	}
+
+	for i, field := range e.Fields {
+		path := "fields." + strconv.Itoa(i)
+
+		if field == nil {
+			return nil, &EmbedValidationError{Field: path, Reason: "is nil"}
+		}
+
+		if err := text(path+".name", field.Name, 1, maxEmbedFieldNameLength); err != nil {
+			return nil, err
+		}
+
+		if err := text(path+".value", field.Value, 1, maxEmbedFieldValueLength); err != nil {
+			return nil, err
+		}
+	}
+
+	if length := e.Length(); length > MaxEmbedsCharacters {
+		return nil, &EmbedValidationError{
+			Field:  "length",
+			Reason: "is " + strconv.Itoa(length) + " characters, the limit is " + strconv.Itoa(MaxEmbedsCharacters),
+		}
+	}
+
+	return e, nil
+}
+
+// ValidateEmbeds Build every embed of a message and check they are at most MaxEmbedsPerMessage with
+// MaxEmbedsCharacters characters combined. Returns the first EmbedValidationError, the indexed ones have an
+// "embeds.N." field prefix
+func ValidateEmbeds(embeds ...*Embed) error {
+	if len(embeds) > MaxEmbedsPerMessage {
+		return &EmbedValidationError{
+			Field:  "embeds",
+			Reason: "has " + strconv.Itoa(len(embeds)) + " embeds, the limit is " + strconv.Itoa(MaxEmbedsPerMessage),
+		}
+	}
+
+	characters := 0
+	for i, embed := range embeds {
+		if embed == nil {
+			continue
+		}
+
+		if _, err := embed.Build(); err != nil {
+			var embedErr *EmbedValidationError
+			if errors.As(err, &embedErr) {
+				return &EmbedValidationError{Field: "embeds." + strconv.Itoa(i) + "." + embedErr.Field, Reason: embedErr.Reason}
+			}
+
+			return err
+		}
+
+		characters += embed.Length()
+	}
+
+	if characters > MaxEmbedsCharacters {
+		return &EmbedValidationError{
+			Field:  "embeds",
+			Reason: "have " + strconv.Itoa(characters) + " characters combined, the limit is " + strconv.Itoa(MaxEmbedsCharacters),
+		}
+	}
+
+	return nil
+}
+
+// NewTextInput Text input builder answering to customID, see Modal.Build for the limits checked
+func NewTextInput(customID, label string, style TextStyle) *TextInputComponent {
+	return NewTextInputComponentBuilder().SetCustomID(customID).SetLabel(label).SetStyle(style)
+}
+
+// SetLength Length the value must have, between 0 and MaxTextInputLength
+func (t *TextInputComponent) SetLength(min, max int) *TextInputComponent {
+	t.MinLength = &min
+	t.MaxLength = &max
+	return t
+}
+
+// build Check the text input, field is the path prefix of its fields
+func (t *TextInputComponent) build(field string) error {
+	fail := func(subfield, reason string) error {
+		return &ComponentValidationError{Field: field + subfield, CustomID: t.CustomID, Reason: reason}
+	}
+
+	if t.CustomID == "" {
+		return fail("custom_id", "is required")
+	}
+
+	if err := checkCustomID(t.CustomID); err != nil {
+		return err
+	}
+
+	if t.Style != ShortTextStyle && t.Style != ParagraphTextStyle {
+		return fail("style", "is not a TextStyle")
+	}
+
+	if length := utf8.RuneCountInString(t.Label); length == 0 || length > maxTextInputLabelLength {
+		return fail("label", "has "+strconv.Itoa(length)+" characters, text inputs have 1 to "+strconv.Itoa(maxTextInputLabelLength))
+	}
+
+	if length := utf8.RuneCountInString(t.Placeholder); length > maxTextInputPlaceholder {
+		return fail("placeholder", "has "+strconv.Itoa(length)+" characters, the limit is "+strconv.Itoa(maxTextInputPlaceholder))
+	}
+
+	min, max := 0, MaxTextInputLength
+	if t.MinLength != nil {
+		min = *t.MinLength
+	}
+
+	if t.MaxLength != nil {
+		max = *t.MaxLength
+	}
+
+	if min < 0 || min > MaxTextInputLength {
+		return fail("min_length", "is "+strconv.Itoa(min)+", it must be between 0 and "+strconv.Itoa(MaxTextInputLength))
+	}
+
+	if max < 1 || max > MaxTextInputLength {
+		return fail("max_length", "is "+strconv.Itoa(max)+", it must be between 1 and "+strconv.Itoa(MaxTextInputLength))
+	}
+
+	if min > max {
+		return fail("min_length", "is over max_length")
+	}
+
+	if length := utf8.RuneCountInString(t.Value); length > max {
+		return fail("value", "has "+strconv.Itoa(length)+" characters, max_length is "+strconv.Itoa(max))
+	}
+
+	return nil
+}
+
+// NewModal Modal builder answering to customID, see Build for the limits checked
+func NewModal(customID, title string) *Modal {
+	return &Modal{CustomID: customID, Title: title}
+}
+
+// AddTextInput Add the text input in a row of its own
+func (m *Modal) AddTextInput(input *TextInputComponent) *Modal {
+	m.Components = append(m.Components, NewActionRowComponentBuilder().AddComponent(input))
+	return m
+}
+
+// Build Check the modal like Discord does: a custom_id, a title, 1 to MaxModalComponents rows of a single text input
+// with a label, a style and lengths between 0 and MaxTextInputLength. Returns the modal, ready for ShowModal, or the
+// first ComponentValidationError
+func (m *Modal) Build() (*Modal, error) {
+	fail := func(field, reason string) error {
+		return &ComponentValidationError{Field: field, CustomID: m.CustomID, Reason: reason}
+	}
+
+	if m.CustomID == "" {
+		return nil, fail("custom_id", "is required")
+	}
+
+	if err := checkCustomID(m.CustomID); err != nil {
+		return nil, err
+	}
+
+	if length := utf8.RuneCountInString(m.Title); length == 0 || length > maxModalTitleLength {
+		return nil, fail("title", "has "+strconv.Itoa(length)+" characters, modals have 1 to "+strconv.Itoa(maxModalTitleLength))
+	}
+
+	if len(m.Components) == 0 || len(m.Components) > MaxModalComponents {
+		return nil, fail("components", "has "+strconv.Itoa(len(m.Components))+" rows, modals have 1 to "+strconv.Itoa(MaxModalComponents))
+	}
+
+	for i, row := range m.Components {
+		field := "components." + strconv.Itoa(i)
+
+		if row == nil || len(row.Components) != 1 {
+			return nil, fail(field, "must hold a single text input")
+		}
+
+		input, ok := row.Components[0].(*TextInputComponent)
+		if !ok {
+			return nil, fail(field+".components.0", "must be a text input, modals hold no other component")
+		}
+
+		if err := input.build(field + ".components.0."); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,158 @@
+package httpcord
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuilderLimits(t *testing.T) {
+	buttons := func(n int) func() error {
+		return func() error {
+			row := NewActionRow()
+			for i := 0; i < n; i++ {
+				row.AddButton(PrimaryButtonStyle, "Page "+strconv.Itoa(i), "page:"+strconv.Itoa(i))
+			}
+
+			_, err := row.Build()
+			return err
+		}
+	}
+
+	label := func(length int) func() error {
+		return func() error {
+			_, err := NewActionRow().AddButton(PrimaryButtonStyle, strings.Repeat("a", length), "confirm").Build()
+			return err
+		}
+	}
+
+	customID := func(length int) func() error {
+		return func() error {
+			_, err := NewActionRow().AddButton(PrimaryButtonStyle, "Confirm", strings.Repeat("c", length)).Build()
+			return err
+		}
+	}
+
+	options := func(n int) func() error {
+		return func() error {
+			menu := NewStringSelect("color")
+			for i := 0; i < n; i++ {
+				menu.AddOptionValue("Color "+strconv.Itoa(i), strconv.Itoa(i))
+			}
+
+			_, err := NewActionRow().AddComponent(menu).Build()
+			return err
+		}
+	}
+
+	optionLabel := func(length int) func() error {
+		return func() error {
+			_, err := NewStringSelect("color").AddOptionValue(strings.Repeat("é", length), "red").Build()
+			return err
+		}
+	}
+
+	placeholder := func(length int) func() error {
+		return func() error {
+			menu := NewStringSelect("color").AddOptionValue("Red", "red")
+			menu.Placeholder = strings.Repeat("p", length)
+
+			_, err := menu.Build()
+			return err
+		}
+	}
+
+	maxValues := func(max int) func() error {
+		return func() error {
+			menu := NewStringSelect("color")
+			for i := 0; i < MaxSelectOptions; i++ {
+				menu.AddOptionValue("Color "+strconv.Itoa(i), strconv.Itoa(i))
+			}
+
+			_, err := menu.SetMinMax(1, max).Build()
+			return err
+		}
+	}
+
+	embeds := func(n int) func() error {
+		return func() error {
+			list := make([]*Embed, n)
+			for i := range list {
+				list[i] = &Embed{Title: "Page " + strconv.Itoa(i)}
+			}
+
+			return ValidateEmbeds(list...)
+		}
+	}
+
+	title := func(length int) func() error {
+		return func() error {
+			return ValidateEmbeds(&Embed{Title: strings.Repeat("t", length)})
+		}
+	}
+
+	fields := func(n int) func() error {
+		return func() error {
+			embed := &Embed{}
+			for i := 0; i < n; i++ {
+				embed.Fields = append(embed.Fields, &EmbedField{Name: "Field", Value: strconv.Itoa(i)})
+			}
+
+			return ValidateEmbeds(embed)
+		}
+	}
+
+	// combined Characters of two embeds, each one under the limit alone
+	combined := func(characters int) func() error {
+		return func() error {
+			half := characters / 2
+			return ValidateEmbeds(&Embed{Description: strings.Repeat("d", half)}, &Embed{Description: strings.Repeat("d", characters-half)})
+		}
+	}
+
+	tests := []struct {
+		name string
+		// build Builds at the limit with limit and one past it with limit+1
+		build func(n int) func() error
+		limit int
+		// field Field of the error past the limit
+		field string
+		want  error
+	}{
+		{"row buttons", buttons, MaxRowButtons, "components", ErrInvalidComponent},
+		{"button label", label, maxButtonLabelLength, "components.0.label", ErrInvalidComponent},
+		{"button custom_id", customID, MaxCustomIDLength, "", ErrCustomIDTooLong},
+		{"select options", options, MaxSelectOptions, "components.0.options", ErrInvalidComponent},
+		{"select option label", optionLabel, maxSelectOptionLength, "options.0.label", ErrInvalidComponent},
+		{"select placeholder", placeholder, maxSelectPlaceholderLength, "placeholder", ErrInvalidComponent},
+		{"select max values", maxValues, MaxSelectOptions, "max_values", ErrInvalidComponent},
+		{"message embeds", embeds, MaxEmbedsPerMessage, "embeds", ErrInvalidEmbed},
+		{"embed title", title, maxEmbedTitleLength, "embeds.0.title", ErrInvalidEmbed},
+		{"embed fields", fields, MaxEmbedFields, "embeds.0.fields", ErrInvalidEmbed},
+		{"embeds characters", combined, MaxEmbedsCharacters, "embeds", ErrInvalidEmbed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.build(test.limit)(); err != nil {
+				t.Errorf("at the limit %d: %v", test.limit, err)
+			}
+
+			err := test.build(test.limit + 1)()
+			if !errors.Is(err, test.want) {
+				t.Fatalf("past the limit %d: %v, want %v", test.limit, err, test.want)
+			}
+
+			var componentErr *ComponentValidationError
+			var embedErr *EmbedValidationError
+
+			switch {
+			case errors.As(err, &componentErr) && componentErr.Field != test.field:
+				t.Errorf("error of the field %q, want %q", componentErr.Field, test.field)
+			case errors.As(err, &embedErr) && embedErr.Field != test.field:
+				t.Errorf("error of the field %q, want %q", embedErr.Field, test.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package httpcord
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"httpcord/endpoints"
+	"httpcord/permissions"
+)
+
+// DefaultCacheTTL Lifetime of the cached entities unless EntityCacheTTL is used
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultCacheSize Entries kept by the memory store of NewEntityCache
+const DefaultCacheSize = 10000
+
+// EntityStore Storage of an EntityCache, implementations must be safe for concurrent use
+type EntityStore interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+}
+
+type memoryStoreEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// MemoryStore EntityStore keeping at most size entries in memory, the least recently used entry is evicted first
+type MemoryStore struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+func NewMemoryStore(size int) *MemoryStore {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	return &MemoryStore{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryStoreEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	s.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (s *MemoryStore) Set(key string, value interface{}, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := s.now().Add(ttl)
+
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*memoryStoreEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryStoreEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryStoreEntry).key)
+	}
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+type EntityCacheOption func(c *EntityCache)
+
+// EntityCacheTTL Lifetime of the cached entities
+func EntityCacheTTL(ttl time.Duration) EntityCacheOption {
+	return func(c *EntityCache) {
+		c.ttl = ttl
+	}
+}
+
+// EntityCacheStore Keep the entities in store instead of a MemoryStore
+func EntityCacheStore(store EntityStore) EntityCacheOption {
+	return func(c *EntityCache) {
+		c.store = store
+	}
+}
+
+// EntityCacheMetrics Receive the httpcord_cache_lookups_total counter
+func EntityCacheMetrics(metrics MetricsCollector) EntityCacheOption {
+	return func(c *EntityCache) {
+		c.metrics = metrics
+	}
+}
+
+// CacheStats Lookups answered by an EntityCache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate Share of the lookups answered by the cache, 0 without lookups
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// EntityCache Read-through cache of channels and roles used by GetChannel and GetRole and of the DM channels
+// used by CreateDM (See RestClient.Cache).
+// It is fed by the resolved data of every interaction, resolved channels are partial and only carry
+// the ID, name, type, permissions and thread fields. Edits made through the client invalidate the entity
+type EntityCache struct {
+	store   EntityStore
+	ttl     time.Duration
+	metrics MetricsCollector
+	hits    uint64
+	misses  uint64
+}
+
+func NewEntityCache(opts ...EntityCacheOption) *EntityCache {
+	c := &EntityCache{ttl: DefaultCacheTTL, metrics: NopMetrics}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.store == nil {
+		c.store = NewMemoryStore(DefaultCacheSize)
+	}
+
+	return c
+}
+
+func channelCacheKey(channelID Snowflake) string {
+	return "channel:" + channelID.String()
+}
+
+func dmChannelCacheKey(userID Snowflake) string {
+	return "dm:" + userID.String()
+}
+
+func roleCacheKey(guildID, roleID Snowflake) string {
+	return "role:" + guildID.String() + ":" + roleID.String()
+}
+
+// Stats Hits and misses of the lookups made so far
+func (c *EntityCache) Stats() CacheStats {
+	return CacheStats{Hits: atomic.LoadUint64(&c.hits), Misses: atomic.LoadUint64(&c.misses)}
+}
+
+func (c *EntityCache) lookup(kind, key string) (interface{}, bool) {
+	value, ok := c.store.Get(key)
+
+	result := "miss"
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+		result = "hit"
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+
+	c.metrics.IncCounter("httpcord_cache_lookups_total", map[string]string{"kind": kind, "result": result})
+	return value, ok
+}
+
+// Channel Cached channel, the returned value must not be modified
+func (c *EntityCache) Channel(channelID Snowflake) (*Channel, bool) {
+	value, ok := c.lookup("channel", channelCacheKey(channelID))
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*Channel), true
+}
+
+// Role Cached role of the guild, the returned value must not be modified
+func (c *EntityCache) Role(guildID, roleID Snowflake) (*Role, bool) {
+	value, ok := c.lookup("role", roleCacheKey(guildID, roleID))
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*Role), true
+}
+
+// DMChannel Cached DM channel with the user, the returned value must not be modified
+func (c *EntityCache) DMChannel(userID Snowflake) (*Channel, bool) {
+	value, ok := c.lookup("dm_channel", dmChannelCacheKey(userID))
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*Channel), true
+}
+
+func (c *EntityCache) SetDMChannel(userID Snowflake, channel *Channel) {
+	c.store.Set(dmChannelCacheKey(userID), channel, c.ttl)
+}
+
+func (c *EntityCache) InvalidateDMChannel(userID Snowflake) {
+	c.store.Delete(dmChannelCacheKey(userID))
+}
+
+func (c *EntityCache) SetChannel(channel *Channel) {
+	c.store.Set(channelCacheKey(channel.ID), channel, c.ttl)
+}
+
+func (c *EntityCache) SetRole(guildID Snowflake, role *Role) {
+	c.store.Set(roleCacheKey(guildID, role.ID), role, c.ttl)
+}
+
+func (c *EntityCache) InvalidateChannel(channelID Snowflake) {
+	c.store.Delete(channelCacheKey(channelID))
+}
+
+func (c *EntityCache) InvalidateRole(guildID, roleID Snowflake) {
+	c.store.Delete(roleCacheKey(guildID, roleID))
+}
+
+// observe Store the channels and roles resolved in the interaction, roles are only cached for guild interactions
+func (c *EntityCache) observe(interaction *Interaction) {
+	if c == nil {
+		return
+	}
+
+	data, ok := interaction.Data.(ApplicationCommandInteractionData)
+	if !ok {
+		return
+	}
+
+	for _, channel := range data.Resolved.Channels {
+		if channel != nil && channel.ID != "" {
+			c.SetChannel(channel)
+		}
+	}
+
+	if interaction.GuildID == "" {
+		return
+	}
+
+	for _, role := range data.Resolved.Roles {
+		if role != nil && role.ID != "" {
+			c.SetRole(interaction.GuildID, role)
+		}
+	}
+}
+
+// cached The entities are looked up unless WithFresh is used
+func (c *RestClient) cached(opts []CallOption) *EntityCache {
+	if c.Cache == nil {
+		return nil
+	}
+
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.fresh {
+		return nil
+	}
+
+	return c.Cache
+}
+
+// GetChannel Channel by ID, answered by the Cache when it has the channel (See WithFresh)
+func (c *RestClient) GetChannel(ctx context.Context, channelID Snowflake, opts ...CallOption) (*Channel, error) {
+	if cache := c.cached(opts); cache != nil {
+		if channel, ok := cache.Channel(channelID); ok {
+			return channel, nil
+		}
+	}
+
+	var channel Channel
+	if err := c.Do(ctx, http.MethodGet, endpoints.Channel(channelID.String()), nil, &channel, opts...); err != nil {
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		c.Cache.SetChannel(&channel)
+	}
+
+	return &channel, nil
+}
+
+// GetRole Role of the guild by ID, answered by the Cache when it has the role (See WithFresh)
+func (c *RestClient) GetRole(ctx context.Context, guildID, roleID Snowflake, opts ...CallOption) (*Role, error) {
+	if cache := c.cached(opts); cache != nil {
+		if role, ok := cache.Role(guildID, roleID); ok {
+			return role, nil
+		}
+	}
+
+	var role Role
+	if err := c.Do(ctx, http.MethodGet, endpoints.GuildRole(guildID.String(), roleID.String()), nil, &role, opts...); err != nil {
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		c.Cache.SetRole(guildID, &role)
+	}
+
+	return &role, nil
+}
+
+// ChannelModify Fields of the channel to update, nil fields are left unchanged
+type ChannelModify struct {
+	Name             *string                `json:"name,omitempty"`
+	Topic            *string                `json:"topic,omitempty"`
+	NSFW             *bool                  `json:"nsfw,omitempty"`
+	RateLimitPerUser *int                   `json:"rate_limit_per_user,omitempty"`
+	Position         *int                   `json:"position,omitempty"`
+	ParentID         *Snowflake             `json:"parent_id,omitempty"`
+	Overwrites       *[]PermissionOverwrite `json:"permission_overwrites,omitempty"`
+}
+
+// ModifyChannel Update the channel, the cached channel is invalidated even when the request fails
+func (c *RestClient) ModifyChannel(ctx context.Context, channelID Snowflake, data *ChannelModify, opts ...CallOption) (*Channel, error) {
+	if c.Cache != nil {
+		defer c.Cache.InvalidateChannel(channelID)
+	}
+
+	var channel Channel
+	if err := c.Do(ctx, http.MethodPatch, endpoints.Channel(channelID.String()), data, &channel, opts...); err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// RoleModify Fields of the role to update, nil fields are left unchanged
+type RoleModify struct {
+	Name        *string                    `json:"name,omitempty"`
+	Permissions *permissions.PermissionBit `json:"permissions,omitempty"`
+	Color       *int                       `json:"color,omitempty"`
+	Hoist       *bool                      `json:"hoist,omitempty"`
+	Mentionable *bool                      `json:"mentionable,omitempty"`
+}
+
+// ModifyRole Update the role, the cached role is invalidated even when the request fails
+func (c *RestClient) ModifyRole(ctx context.Context, guildID, roleID Snowflake, data *RoleModify, opts ...CallOption) (*Role, error) {
+	if c.Cache != nil {
+		defer c.Cache.InvalidateRole(guildID, roleID)
+	}
+
+	var role Role
+	if err := c.Do(ctx, http.MethodPatch, endpoints.GuildRole(guildID.String(), roleID.String()), data, &role, opts...); err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
@@ -0,0 +1,190 @@
+package httpcord
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// inDM Body of the interaction sent in a DM by the user 4 instead of the guild 2
+func inDM(body []byte) []byte {
+	body = bytes.Replace(body, []byte(`"guild_id":"2",`), nil, 1)
+	return bytes.Replace(body, []byte(`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"8"}`),
+		[]byte(`"user":{"id":"4","username":"mod"}`), 1)
+}
+
+func TestEntityCacheObserve(t *testing.T) {
+	resolved := `{"id":"5","name":"lock","type":1,"options":[{"type":7,"name":"channel","value":"10"},{"type":8,"name":"role","value":"11"}],` +
+		`"resolved":{"channels":{"10":{"id":"10","name":"general","type":0,"permissions":"8"}},"roles":{"11":{"id":"11","name":"mods"}}}}`
+
+	tests := []struct {
+		name string
+		body []byte
+		// channel The resolved channel is cached
+		channel bool
+		// role The resolved role is cached for the guild 2
+		role bool
+	}{
+		{"guild command", interactionBody(ApplicationCommandInteraction, resolved), true, true},
+		{"dm command", inDM(interactionBody(ApplicationCommandInteraction, resolved)), true, false},
+		{"autocomplete", interactionBody(AutoCompleteInteraction, resolved), true, true},
+		{"component", componentBody(), false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+			conn.Client.Cache = NewEntityCache()
+
+			conn.AddInteractionHandler(func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "ok"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+
+			channel, ok := conn.Client.Cache.Channel("10")
+			if ok != test.channel || ok && (channel.Name != "general" || channel.Permissions == nil || *channel.Permissions != "8") {
+				t.Errorf("Channel(10) = %+v, %v, want cached %v", channel, ok, test.channel)
+			}
+
+			role, ok := conn.Client.Cache.Role("2", "11")
+			if ok != test.role || ok && role.Name != "mods" {
+				t.Errorf("Role(2, 11) = %+v, %v, want cached %v", role, ok, test.role)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore(2)
+	store.now = func() time.Time { return now }
+
+	store.Set("a", 1, time.Minute)
+	store.Set("b", 2, time.Minute)
+
+	// a is now the most recently used, c evicts b
+	if _, ok := store.Get("a"); !ok {
+		t.Fatal("a missing before the store is full")
+	}
+
+	store.Set("c", 3, time.Minute)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := store.Get(key); ok != want {
+			t.Errorf("Get(%s) found %v, want %v", key, ok, want)
+		}
+	}
+
+	// Setting an existing key refreshes it without evicting
+	store.Set("a", 10, 2*time.Minute)
+	if value, ok := store.Get("a"); !ok || value != 10 {
+		t.Errorf("Get(a) = %v, %v, want the updated 10", value, ok)
+	}
+
+	if _, ok := store.Get("c"); !ok {
+		t.Error("c evicted by the update of a")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := store.Get("c"); ok {
+		t.Error("c found once its ttl passed")
+	}
+
+	if _, ok := store.Get("a"); !ok {
+		t.Error("a expired before its refreshed ttl")
+	}
+
+	store.Delete("a")
+	if _, ok := store.Get("a"); ok {
+		t.Error("a found after Delete")
+	}
+
+	if len(store.entries) != 0 || store.order.Len() != 0 {
+		t.Errorf("%d entries and %d ordered left, want the store empty", len(store.entries), store.order.Len())
+	}
+}
+
+func TestEntityCacheStats(t *testing.T) {
+	cache := NewEntityCache(EntityCacheStore(NewMemoryStore(1)))
+	cache.SetChannel(&Channel{ID: "10"})
+	cache.SetRole("2", &Role{ID: "11"})
+
+	// The role evicted the channel from the single entry store
+	if _, ok := cache.Channel("10"); ok {
+		t.Error("channel found after its eviction")
+	}
+
+	if _, ok := cache.Role("2", "11"); !ok {
+		t.Error("role missing")
+	}
+
+	if _, ok := cache.Role("3", "11"); ok {
+		t.Error("role of the guild 2 found in the guild 3")
+	}
+
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 2 || stats.HitRate() != 1.0/3 {
+		t.Errorf("Stats() = %+v with a hit rate of %v, want 1 hit and 2 misses", stats, stats.HitRate())
+	}
+}
+
+// TestEntityCacheConcurrent Run with -race, interactions fill the cache while handlers read and invalidate it
+func TestEntityCacheConcurrent(t *testing.T) {
+	const goroutines, rounds = 8, 200
+
+	cache := NewEntityCache(EntityCacheStore(NewMemoryStore(16)))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		g := g
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < rounds; i++ {
+				id := Snowflake(strconv.Itoa((g*rounds + i) % 32))
+
+				interaction := Interaction{
+					Type:    ApplicationCommandInteraction,
+					GuildID: "2",
+					Data: ApplicationCommandInteractionData{Resolved: ResolvedData{
+						Channels: map[Snowflake]*Channel{id: {ID: id}},
+						Roles:    map[Snowflake]*Role{id: {ID: id}},
+					}},
+				}
+
+				cache.observe(&interaction)
+				cache.Channel(id)
+				cache.Role("2", id)
+				cache.SetDMChannel(id, &Channel{ID: id})
+				cache.DMChannel(id)
+
+				if i%3 == 0 {
+					cache.InvalidateChannel(id)
+					cache.InvalidateRole("2", id)
+					cache.InvalidateDMChannel(id)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Hits+stats.Misses != goroutines*rounds*3 {
+		t.Errorf("Stats() = %+v, want %d lookups", stats, goroutines*rounds*3)
+	}
+
+	store := cache.store.(*MemoryStore)
+	if len(store.entries) > 16 || store.order.Len() != len(store.entries) {
+		t.Errorf("%d entries and %d ordered, want at most 16 of each", len(store.entries), store.order.Len())
+	}
+}
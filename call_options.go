@@ -0,0 +1,69 @@
+package httpcord
+
+import "net/url"
+
+// CallOption Customize a single RestClient request
+type CallOption func(o *callOptions)
+
+type callOptions struct {
+	auth   bool
+	bearer string
+	reason string
+	query  url.Values
+	params map[string]string
+	files  []*DiscordFile
+	fresh  bool
+}
+
+// WithReason Audit log reason of the request
+func WithReason(reason string) CallOption {
+	return func(o *callOptions) {
+		o.reason = reason
+	}
+}
+
+// WithQuery Query parameters appended to the route
+func WithQuery(query url.Values) CallOption {
+	return func(o *callOptions) {
+		o.query = query
+	}
+}
+
+// WithBearer Authenticate with an OAuth2 access token instead of the bot token
+func WithBearer(token string) CallOption {
+	return func(o *callOptions) {
+		o.bearer = token
+	}
+}
+
+// WithFiles Send the body as payload_json of a multipart request with the files
+func WithFiles(files ...*DiscordFile) CallOption {
+	return func(o *callOptions) {
+		o.files = append(o.files, files...)
+	}
+}
+
+// WithFresh Skip the EntityCache of the client and fetch from the API, the result is still cached
+func WithFresh() CallOption {
+	return func(o *callOptions) {
+		o.fresh = true
+	}
+}
+
+// RouteParam Fill the {name} placeholder of the route, like RouteParam("channel.id", id)
+func RouteParam(name, value string) CallOption {
+	return func(o *callOptions) {
+		if o.params == nil {
+			o.params = make(map[string]string)
+		}
+
+		o.params[name] = value
+	}
+}
+
+// withoutAuth Interaction webhooks are authenticated by their token
+func withoutAuth() CallOption {
+	return func(o *callOptions) {
+		o.auth = false
+	}
+}
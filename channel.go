@@ -64,7 +64,7 @@ type Channel struct {
 	OwnerID                    Snowflake             `json:"owner_id,omitempty"`
 	ApplicationID              Snowflake             `json:"application_id,omitempty"`
 	ParentID                   Snowflake             `json:"parent_id,omitempty"`
-	LastPinTimestamp           Time                  `json:"last_pin_timestamp,omitempty"`
+	LastPinTimestamp           Time                  `json:"last_pin_timestamp"`
 	RtcRegion                  *string               `json:"rtc_region,omitempty"`
 	VideoQualityMode           *int                  `json:"video_quality_mode,omitempty"`
 	MessageCount               *int                  `json:"message_count,omitempty"`
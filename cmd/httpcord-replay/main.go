@@ -0,0 +1,71 @@
+// Command httpcord-replay Send a request dumped by ConnectionOptions.DebugDumpDir to a local endpoint.
+//
+//	httpcord-replay -url http://localhost:8080/interactions -key <hex seed> 0001-request.json
+//
+// The endpoint must use the public key printed by -genkey, or of the seed given with -key
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"httpcord/httpcordtest"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8080/", "endpoint receiving the interactions")
+	seed := flag.String("key", os.Getenv("HTTPCORD_REPLAY_KEY"), "hex encoded ed25519 seed signing the request")
+	genkey := flag.Bool("genkey", false, "print a new seed and its public key")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	flag.Parse()
+
+	if *genkey {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			fail(err)
+		}
+
+		fmt.Printf("seed:       %s\npublic key: %s\n", hex.EncodeToString(priv.Seed()), hex.EncodeToString(pub))
+		return
+	}
+
+	if flag.NArg() != 1 || *seed == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	b, err := hex.DecodeString(*seed)
+	if err != nil || len(b) != ed25519.SeedSize {
+		fail(fmt.Errorf("invalid seed, expected %d hex encoded bytes", ed25519.SeedSize))
+	}
+
+	res, err := httpcordtest.Replay(&http.Client{Timeout: *timeout}, *url, flag.Arg(0), ed25519.NewKeyFromSeed(b))
+	if err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("status: %d\n", res.Status)
+
+	for _, file := range res.Files {
+		fmt.Printf("file %s: %s (%s, %d bytes)\n", file.Field, file.Filename, file.ContentType, len(file.Data))
+	}
+
+	if res.Response != nil && len(res.Files) > 0 {
+		payload, _ := json.MarshalIndent(res.Response, "", "  ")
+		fmt.Printf("%s\n", payload)
+		return
+	}
+
+	fmt.Printf("%s\n", res.Body)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "httpcord-replay:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,52 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec JSON encoding of the interactions, the responses and the REST payloads, for faster libraries like
+// json-iterator or sonic. Unmarshal must keep the numbers of interface{} values as json.Number like DecodeJSON, as
+// jsoniter.Config{UseNumber: true} and sonic.Config{UseNumber: true} do
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StreamEncoder Optional Codec extension writing the payloads without the intermediate slice of Marshal, used for
+// the pooled response buffers and the payload_json of the multipart bodies
+type StreamEncoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return DecodeJSON(data, v)
+}
+
+func (stdCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// StdCodec Default Codec of encoding/json
+var StdCodec Codec = stdCodec{}
+
+// encodeTo Write v with the codec, through StreamEncoder when the codec implements it
+func encodeTo(codec Codec, w io.Writer, v interface{}) error {
+	if encoder, ok := codec.(StreamEncoder); ok {
+		return encoder.Encode(w, v)
+	}
+
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
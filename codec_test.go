@@ -0,0 +1,111 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+type recordingCodec struct {
+	stdCodec
+	decoded []interface{}
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.decoded = append(c.decoded, v)
+	return c.stdCodec.Unmarshal(data, v)
+}
+
+func TestCodecDecodesInteractionData(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		data func(v interface{}) bool
+	}{
+		{
+			name: "command",
+			body: `{"id":"1","application_id":"2","type":2,"token":"t","version":1,"user":{"id":"3"},"data":{"id":"4","name":"ban","type":1}}`,
+			data: func(v interface{}) bool { _, ok := v.(*ApplicationCommandInteractionData); return ok },
+		},
+		{
+			name: "autocomplete",
+			body: `{"id":"1","application_id":"2","type":4,"token":"t","version":1,"user":{"id":"3"},"data":{"id":"4","name":"ban","type":1}}`,
+			data: func(v interface{}) bool { _, ok := v.(*ApplicationCommandInteractionData); return ok },
+		},
+		{
+			name: "component",
+			body: `{"id":"1","application_id":"2","type":3,"token":"t","version":1,"user":{"id":"3"},"data":{"custom_id":"confirm","component_type":2}}`,
+			data: func(v interface{}) bool { _, ok := v.(*ComponentInteractionData); return ok },
+		},
+		{
+			name: "modal",
+			body: `{"id":"1","application_id":"2","type":5,"token":"t","version":1,"user":{"id":"3"},"data":{"custom_id":"form","components":[]}}`,
+			data: func(v interface{}) bool { _, ok := v.(*ModalSubmitInteractionData); return ok },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			codec := &recordingCodec{}
+
+			var raw APIInteraction
+			if err := codec.Unmarshal([]byte(test.body), &raw); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := resolveInteraction(codec, &raw); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(codec.decoded) != 2 || !test.data(codec.decoded[1]) {
+				t.Fatalf("codec decoded %d values, want the interaction then its data", len(codec.decoded))
+			}
+		})
+	}
+}
+
+// marshalCodec Codec counting the payloads it encodes, without StreamEncoder
+type marshalCodec struct {
+	Codec
+	marshaled int32
+}
+
+func (c *marshalCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt32(&c.marshaled, 1)
+	return c.Codec.Marshal(v)
+}
+
+func TestCodecEncodesResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		response InteractionCallbackType
+	}{
+		{"ping", []byte(`{"id":"1","application_id":"1","type":1,"token":"token","version":1}`), PongResponse},
+		{"command", commandBody(), ChannelMessageWithSourceResponse},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			codec := &marshalCodec{Codec: StdCodec}
+			conn, sign := signedConnection(t, ConnectionOptions{Codec: codec})
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Type != test.response {
+				t.Fatalf("%d %s, want the response type %d", w.Code, w.Body, test.response)
+			}
+
+			if atomic.LoadInt32(&codec.marshaled) == 0 {
+				t.Error("the response was not encoded with the Codec")
+			}
+		})
+	}
+}
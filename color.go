@@ -0,0 +1,136 @@
+package httpcord
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color RGB color of embeds, sent as the integer 0xRRGGBB
+type Color int
+
+// Colors of the Discord brand palette
+
+const (
+	BlurpleColor Color = 0x5865F2
+	GreenColor   Color = 0x57F287
+	YellowColor  Color = 0xFEE75C
+	FuchsiaColor Color = 0xEB459E
+	RedColor     Color = 0xED4245
+	WhiteColor   Color = 0xFFFFFF
+	BlackColor   Color = 0x000000
+)
+
+// ErrInvalidColor The hex color is not #RGB or #RRGGBB
+var ErrInvalidColor = errors.New("httpcord: invalid hex color")
+
+// ColorFromHex Parse "#5865F2", "5865F2" or the short form "#58F", returns ErrInvalidColor for other strings
+func ColorFromHex(hex string) (Color, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return 0, ErrInvalidColor
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, ErrInvalidColor
+	}
+
+	return Color(value), nil
+}
+
+// ColorFromRGB Color of the red, green and blue components
+func ColorFromRGB(r, g, b uint8) Color {
+	return Color(int(r)<<16 | int(g)<<8 | int(b))
+}
+
+// RGB Red, green and blue components of the color
+func (c Color) RGB() (r, g, b uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+// Hex Color formatted like "#5865F2"
+func (c Color) Hex() string {
+	hex := strings.ToUpper(strconv.FormatInt(int64(c&0xFFFFFF), 16))
+	return "#" + strings.Repeat("0", 6-len(hex)) + hex
+}
+
+// Luminance Relative luminance of the color between 0 (black) and 1 (white) as defined by WCAG
+func (c Color) Luminance() float64 {
+	linear := func(component uint8) float64 {
+		v := float64(component) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	r, g, b := c.RGB()
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// ContrastRatio WCAG contrast ratio of the colors, from 1 for the same luminance to 21 for black on white
+func (c Color) ContrastRatio(other Color) float64 {
+	a, b := c.Luminance(), other.Luminance()
+	if a < b {
+		a, b = b, a
+	}
+
+	return (a + 0.05) / (b + 0.05)
+}
+
+// ContrastText BlackColor or WhiteColor, whichever is the most readable on the color
+func (c Color) ContrastText() Color {
+	if c.ContrastRatio(BlackColor) >= c.ContrastRatio(WhiteColor) {
+		return BlackColor
+	}
+
+	return WhiteColor
+}
+
+const (
+	progressBarFull  = '█'
+	progressBarEmpty = '░'
+)
+
+// progressBarPartial Blocks of one to seven eighths of a character
+var progressBarPartial = []rune("▏▎▍▌▋▊▉")
+
+// RenderBar Progress bar of width characters filled at fraction (clamped between 0 and 1),
+// like "█████▌░░░░" for 0.55 on 10 characters. Empty when width is not positive
+func RenderBar(fraction float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	if math.IsNaN(fraction) || fraction < 0 {
+		fraction = 0
+	} else if fraction > 1 {
+		fraction = 1
+	}
+
+	eighths := int(math.Round(fraction * float64(width*8)))
+	full, partial := eighths/8, eighths%8
+
+	bar := make([]rune, 0, width)
+	for i := 0; i < full; i++ {
+		bar = append(bar, progressBarFull)
+	}
+
+	if partial > 0 {
+		bar = append(bar, progressBarPartial[partial-1])
+	}
+
+	for len(bar) < width {
+		bar = append(bar, progressBarEmpty)
+	}
+
+	return string(bar)
+}
@@ -0,0 +1,97 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+
+	"httpcord/endpoints"
+)
+
+type ApplicationCommandPermissionType int
+
+// Application Command Permission Types
+
+const (
+	RoleCommandPermission ApplicationCommandPermissionType = iota + 1
+	UserCommandPermission
+	ChannelCommandPermission
+)
+
+// ApplicationCommandPermission Allow or deny a command to a role, a user or in a channel. The guild ID is the
+// @everyone role, see AllChannelsPermissionID for every channel
+type ApplicationCommandPermission struct {
+	ID         Snowflake                        `json:"id"`
+	Type       ApplicationCommandPermissionType `json:"type"`
+	Permission bool                             `json:"permission"`
+}
+
+// GuildApplicationCommandPermissions Permissions of a command in a guild, ID is the application ID for the
+// permissions of every command of the application
+type GuildApplicationCommandPermissions struct {
+	ID            Snowflake                       `json:"id"`
+	ApplicationID Snowflake                       `json:"application_id"`
+	GuildID       Snowflake                       `json:"guild_id"`
+	Permissions   []*ApplicationCommandPermission `json:"permissions"`
+}
+
+// AllChannelsPermissionID ID of the ChannelCommandPermission applying to every channel of the guild
+func AllChannelsPermissionID(guildID Snowflake) Snowflake {
+	return SnowflakeFromUint64(guildID.Uint64() - 1)
+}
+
+// GetGuildCommandPermissions Permissions of the commands of the application in the guild, the commands without
+// overrides are not listed
+func (c *RestClient) GetGuildCommandPermissions(ctx context.Context, guildID Snowflake) ([]*GuildApplicationCommandPermissions, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	var permissions []*GuildApplicationCommandPermissions
+	err := c.Do(ctx, http.MethodGet, endpoints.GuildCommandPermissions(c.ApplicationID.String(), guildID.String()), nil, &permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetCommandPermissions Permissions of the command in the guild, a 404 when it has no overrides
+func (c *RestClient) GetCommandPermissions(ctx context.Context, guildID, commandID Snowflake) (*GuildApplicationCommandPermissions, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	var permissions GuildApplicationCommandPermissions
+	err := c.Do(ctx, http.MethodGet, endpoints.CommandPermissions(c.ApplicationID.String(), guildID.String(), commandID.String()), nil, &permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &permissions, nil
+}
+
+// EditCommandPermissions Replace the permissions of the command in the guild, or of every command with the
+// application ID as commandID. Discord refuses the bot token, accessToken is the OAuth2 token of a member allowed to
+// manage the guild and its roles, authorized with the applications.commands.permissions.update scope
+func (c *RestClient) EditCommandPermissions(ctx context.Context, accessToken string, guildID, commandID Snowflake, permissions []*ApplicationCommandPermission) (*GuildApplicationCommandPermissions, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	if permissions == nil {
+		permissions = []*ApplicationCommandPermission{}
+	}
+
+	body := struct {
+		Permissions []*ApplicationCommandPermission `json:"permissions"`
+	}{permissions}
+
+	var edited GuildApplicationCommandPermissions
+	err := c.Do(ctx, http.MethodPut, endpoints.CommandPermissions(c.ApplicationID.String(), guildID.String(), commandID.String()), body, &edited,
+		WithBearer(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
@@ -0,0 +1,128 @@
+package httpcord_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestGetCommandPermissions(t *testing.T) {
+	moderators := &httpcord.GuildApplicationCommandPermissions{ID: "10", ApplicationID: "1", GuildID: "2", Permissions: []*httpcord.ApplicationCommandPermission{
+		{ID: "11", Type: httpcord.RoleCommandPermission, Permission: true},
+		{ID: "2", Type: httpcord.RoleCommandPermission, Permission: false},
+		{ID: httpcord.AllChannelsPermissionID("2"), Type: httpcord.ChannelCommandPermission, Permission: false},
+	}}
+
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodGet, "/applications/1/guilds/2/commands/permissions").RespondWith([]*httpcord.GuildApplicationCommandPermissions{moderators})
+	fake.Expect(http.MethodGet, "/applications/1/guilds/2/commands/10/permissions").RespondWith(moderators)
+	fake.Expect(http.MethodGet, "/applications/1/guilds/2/commands/12/permissions").RespondError(http.StatusNotFound, 10066, "Unknown application command permissions")
+
+	client := fake.Client().WithApplication("1")
+
+	guild, err := client.GetGuildCommandPermissions(context.Background(), "2")
+	if err != nil || !reflect.DeepEqual(guild, []*httpcord.GuildApplicationCommandPermissions{moderators}) {
+		t.Errorf("GetGuildCommandPermissions() = %+v, %v, want the overrides of the guild", guild, err)
+	}
+
+	command, err := client.GetCommandPermissions(context.Background(), "2", "10")
+	if err != nil || !reflect.DeepEqual(command, moderators) {
+		t.Errorf("GetCommandPermissions() = %+v, %v, want the overrides of the command", command, err)
+	}
+
+	// Commands without overrides are a 404
+	if _, err := client.GetCommandPermissions(context.Background(), "2", "12"); !httpcord.HasErrorCode(err, 10066) {
+		t.Errorf("GetCommandPermissions() of a command without overrides = %v, want the 404", err)
+	}
+
+	for _, request := range fake.Requests() {
+		if auth := request.Header.Get("Authorization"); auth != "Bot fake-token" {
+			t.Errorf("%s Authorization %q, want the bot token", request.Path, auth)
+		}
+	}
+}
+
+func TestEditCommandPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandID   httpcord.Snowflake
+		permissions []*httpcord.ApplicationCommandPermission
+		// body Body of the request
+		body string
+	}{
+		{"command", "10", []*httpcord.ApplicationCommandPermission{
+			{ID: "11", Type: httpcord.RoleCommandPermission, Permission: true},
+			{ID: "4", Type: httpcord.UserCommandPermission, Permission: false},
+			{ID: "1", Type: httpcord.ChannelCommandPermission, Permission: false},
+		}, `{"permissions":[{"id":"11","type":1,"permission":true},{"id":"4","type":2,"permission":false},{"id":"1","type":3,"permission":false}]}`},
+		{"every command of the application", "1", []*httpcord.ApplicationCommandPermission{
+			{ID: "2", Type: httpcord.RoleCommandPermission, Permission: false},
+		}, `{"permissions":[{"id":"2","type":1,"permission":false}]}`},
+		{"overrides removed", "10", nil, `{"permissions":[]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			fake.Expect(http.MethodPut, "/applications/1/guilds/2/commands/"+test.commandID.String()+"/permissions").
+				RespondWith(httpcord.GuildApplicationCommandPermissions{ID: test.commandID, ApplicationID: "1", GuildID: "2", Permissions: test.permissions})
+
+			edited, err := fake.Client().WithApplication("1").EditCommandPermissions(context.Background(), "member-token", "2", test.commandID, test.permissions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if edited.ID != test.commandID || len(edited.Permissions) != len(test.permissions) {
+				t.Errorf("EditCommandPermissions() = %+v, want the edited permissions", edited)
+			}
+
+			request := fake.Requests()[0]
+			if string(request.Payload) != test.body {
+				t.Errorf("body %s, want %s", request.Payload, test.body)
+			}
+
+			// Discord refuses the bot token for the edits
+			if auth := request.Header.Get("Authorization"); auth != "Bearer member-token" {
+				t.Errorf("Authorization %q, want the token of the member", auth)
+			}
+		})
+	}
+}
+
+func TestCommandPermissionsApplication(t *testing.T) {
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	client := fake.Client()
+
+	if _, err := client.GetGuildCommandPermissions(context.Background(), "2"); !errors.Is(err, httpcord.ErrSyncApplication) {
+		t.Errorf("GetGuildCommandPermissions() = %v, want ErrSyncApplication", err)
+	}
+
+	if _, err := client.GetCommandPermissions(context.Background(), "2", "10"); !errors.Is(err, httpcord.ErrSyncApplication) {
+		t.Errorf("GetCommandPermissions() = %v, want ErrSyncApplication", err)
+	}
+
+	if _, err := client.EditCommandPermissions(context.Background(), "member-token", "2", "10", nil); !errors.Is(err, httpcord.ErrSyncApplication) {
+		t.Errorf("EditCommandPermissions() = %v, want ErrSyncApplication", err)
+	}
+}
+
+func TestAllChannelsPermissionID(t *testing.T) {
+	tests := []struct {
+		guildID httpcord.Snowflake
+		want    httpcord.Snowflake
+	}{
+		{"41771983423143937", "41771983423143936"},
+		{"18446744073709551615", "18446744073709551614"},
+	}
+
+	for _, test := range tests {
+		if got := httpcord.AllChannelsPermissionID(test.guildID); got != test.want {
+			t.Errorf("AllChannelsPermissionID(%s) = %q, want %q", test.guildID, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package httpcord
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type CommandSchemaOption func(o *commandSchemaOptions)
+
+type commandSchemaOptions struct {
+	translator *Translator
+}
+
+// SchemaTranslator Localize the command and its options with the messages of the translator, "<command>.name" and
+// "<command>.description" for the command and "<key>.name" and "<key>.description" for the options where the key is
+// the localize tag (Defaults to "<command>.<option>")
+func SchemaTranslator(t *Translator) CommandSchemaOption {
+	return func(o *commandSchemaOptions) {
+		o.translator = t
+	}
+}
+
+// snowflakeOptionTypes Values of the type tag of the Snowflake fields
+var snowflakeOptionTypes = map[string]ApplicationCommandOptionType{
+	"user":        UserApplicationCommandOptionType,
+	"channel":     ChannelApplicationCommandOptionType,
+	"role":        RoleApplicationCommandOptionType,
+	"mentionable": MentionableApplicationCommandOptionType,
+	"attachment":  AttachmentApplicationCommandOptionType,
+}
+
+// CommandSchema Chat input command with the options of the struct args, which BindOptions fills back so the
+// definition and the parsing can not drift apart:
+//
+//	type banArgs struct {
+//		Target *httpcord.Member `option:"user" description:"Member to ban"`
+//		Reason string           `option:",default=No reason" description:"Shown in the audit log"`
+//		Days   *int             `option:"delete_days" description:"Days of messages to delete" min:"0" max:"7"`
+//		Period string           `option:"period" description:"Ban duration" choices:"Day=day,Week=week,Forever=forever"`
+//	}
+//
+//	command, err := httpcord.CommandSchema("ban", "Ban a member", banArgs{})
+//
+// The option tag names the option like for BindOptions, an empty name is the snake_case field name, and the
+// autocomplete flag enables its autocomplete. The options are required when BindOptions requires them, the field
+// type sets the option type and Snowflake fields need a type tag of user, channel, role, mentionable or attachment.
+// The choices tag lists values or Name=value pairs, min and max bound the integer and number options. Required
+// options are moved before the others.
+// Returns the CommandValidationError of the tags or of ApplicationCommand.Validate
+func CommandSchema(name, description string, args interface{}, opts ...CommandSchemaOption) (*ApplicationCommand, error) {
+	o := commandSchemaOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	command := NewCommandBuilder().SetName(name).SetDescription(description)
+	command.Options = []ApplicationCommandOption{}
+
+	if o.translator != nil {
+		command.NameLocalizations = o.translator.Messages[name+".name"]
+		command.DescriptionLocalizations = o.translator.Messages[name+".description"]
+	}
+
+	t := reflect.TypeOf(args)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, &CommandValidationError{Command: name, Field: "options", Value: reflect.TypeOf(args).String(), Reason: "is not a struct"}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := parseOptionTag(field)
+		if tag.skip {
+			continue
+		}
+
+		if tag.err != nil {
+			return nil, &CommandValidationError{Command: name, Field: "options." + tag.name, Value: field.Tag.Get("option"), Reason: tag.err.Error()}
+		}
+
+		option, err := schemaOption(name, field, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.translator != nil {
+			key := name + "." + tag.name
+			if localize, ok := field.Tag.Lookup("localize"); ok {
+				key = localize
+			}
+
+			option.NameLocalizations = o.translator.Messages[key+".name"]
+			option.DescriptionLocalizations = o.translator.Messages[key+".description"]
+		}
+
+		command.Options = append(command.Options, *option)
+	}
+
+	// Discord refuses required options after optional ones
+	sort.SliceStable(command.Options, func(i, j int) bool {
+		return command.Options[i].Required && !command.Options[j].Required
+	})
+
+	if err := command.Validate(); err != nil {
+		return nil, err
+	}
+
+	return command, nil
+}
+
+// schemaOption Option of the struct field
+func schemaOption(command string, field reflect.StructField, tag optionTag) (*ApplicationCommandOption, error) {
+	fail := func(value, reason string) error {
+		return &CommandValidationError{Command: command, Field: "options." + tag.name, Value: value, Reason: reason}
+	}
+
+	option := &ApplicationCommandOption{
+		Name:         tag.name,
+		Description:  field.Tag.Get("description"),
+		Required:     tag.isRequired(field.Type.Kind() == reflect.Ptr && scalarKind(field.Type.Elem()) != 0),
+		Autocomplete: tag.autocomplete,
+	}
+
+	switch field.Type {
+	case userType, memberType:
+		option.Type = UserApplicationCommandOptionType
+	case channelType:
+		option.Type = ChannelApplicationCommandOptionType
+	case roleType:
+		option.Type = RoleApplicationCommandOptionType
+	case attachmentType:
+		option.Type = AttachmentApplicationCommandOptionType
+	case snowflakeType:
+		optionType, ok := snowflakeOptionTypes[field.Tag.Get("type")]
+		if !ok {
+			return nil, fail(field.Tag.Get("type"), "type of a Snowflake field is not user, channel, role, mentionable or attachment")
+		}
+
+		option.Type = optionType
+	default:
+		kind := field.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = field.Type.Elem().Kind()
+		}
+
+		switch kind {
+		case reflect.String:
+			option.Type = StringApplicationCommandOptionType
+		case reflect.Bool:
+			option.Type = BoolApplicationCommandOptionType
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			option.Type = IntApplicationCommandOptionType
+		case reflect.Float32, reflect.Float64:
+			option.Type = NumberApplicationCommandOptionType
+		default:
+			return nil, fail(field.Type.String(), "is not a supported option field type")
+		}
+	}
+
+	numeric := option.Type == IntApplicationCommandOptionType || option.Type == NumberApplicationCommandOptionType
+
+	for _, bound := range []string{"min", "max"} {
+		raw, ok := field.Tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || !numeric {
+			return nil, fail(raw, bound+" is only a number of the integer and number options")
+		}
+
+		if bound == "min" {
+			option.MinValue = &value
+		} else {
+			option.MaxValue = &value
+		}
+	}
+
+	if raw, ok := field.Tag.Lookup("choices"); ok {
+		for _, choice := range strings.Split(raw, ",") {
+			label, value := choice, choice
+			if i := strings.IndexByte(choice, '='); i >= 0 {
+				label, value = choice[:i], choice[i+1:]
+			}
+
+			var parsed interface{} = value
+
+			switch option.Type {
+			case StringApplicationCommandOptionType:
+			case IntApplicationCommandOptionType:
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fail(value, "choice is not an integer")
+				}
+
+				parsed = n
+			case NumberApplicationCommandOptionType:
+				n, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fail(value, "choice is not a number")
+				}
+
+				parsed = n
+			default:
+				return nil, fail(raw, "choices are only for the string, integer and number options")
+			}
+
+			option.Choices = append(option.Choices, ApplicationCommandOptionChoice{Name: label, Value: parsed})
+		}
+	}
+
+	return option, nil
+}
@@ -0,0 +1,79 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCommandSchemaBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    interface{}
+		present []string
+		absent  []string
+		error   string
+	}{
+		{"zero minimum", struct {
+			Days *int `option:"days" description:"Days" min:"0" max:"7"`
+		}{}, []string{`"min_value":0`, `"max_value":7`}, nil, ""},
+		{"zero maximum", struct {
+			Offset *float64 `option:"offset" description:"Offset" min:"-5" max:"0"`
+		}{}, []string{`"min_value":-5`, `"max_value":0`}, nil, ""},
+		{"no bounds", struct {
+			Days *int `option:"days" description:"Days"`
+		}{}, nil, []string{"min_value", "max_value"}, ""},
+		{"bound of a string option", struct {
+			Reason string `option:"reason" description:"Reason" min:"0"`
+		}{}, nil, nil, "min is only a number"},
+		{"invalid bound", struct {
+			Days *int `option:"days" description:"Days" max:"week"`
+		}{}, nil, nil, "max is only a number"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			command, err := CommandSchema("ban", "Ban a member", test.args)
+			if test.error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.error) {
+					t.Fatalf("CommandSchema() = %v, want %q", err, test.error)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := json.Marshal(command)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, present := range test.present {
+				if !strings.Contains(string(b), present) {
+					t.Errorf("%s is missing %s", b, present)
+				}
+			}
+
+			for _, absent := range test.absent {
+				if strings.Contains(string(b), absent) {
+					t.Errorf("%s contains %s", b, absent)
+				}
+			}
+		})
+	}
+}
+
+func TestSetMinValue(t *testing.T) {
+	option := (&ApplicationCommandOption{Name: "days", Type: IntApplicationCommandOptionType}).SetMinValue(0)
+
+	if b, _ := json.Marshal(option); !strings.Contains(string(b), `"min_value":0`) {
+		t.Errorf("%s, want the explicit 0 minimum", b)
+	}
+
+	if b, _ := json.Marshal(option.ClearMinValue()); strings.Contains(string(b), "min_value") {
+		t.Errorf("%s, want the minimum removed", b)
+	}
+}
@@ -0,0 +1,506 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"httpcord/endpoints"
+)
+
+// DefaultSyncMaxBulkSize Bulk overwrite bodies larger than this are synced command by command
+const DefaultSyncMaxBulkSize = 1 << 20
+
+// ErrSyncApplication The client has no application ID, see RestClient.WithApplication
+var ErrSyncApplication = errors.New("httpcord: syncing commands needs the application id")
+
+type SyncStrategy int
+
+// Sync Strategies
+
+const (
+	// SyncUnchanged The registered commands already matched, nothing was written
+	SyncUnchanged SyncStrategy = iota
+	// SyncBulkOverwrite Every command was written in one bulk overwrite request
+	SyncBulkOverwrite
+	// SyncIncremental Only the commands that changed were created, edited or deleted
+	SyncIncremental
+)
+
+func (s SyncStrategy) String() string {
+	switch s {
+	case SyncBulkOverwrite:
+		return "bulk overwrite"
+	case SyncIncremental:
+		return "incremental"
+	default:
+		return "unchanged"
+	}
+}
+
+// SyncResult Strategy used by SyncCommands and the names of the commands it wrote
+type SyncResult struct {
+	Strategy SyncStrategy
+	// BulkSize is the estimated size of the bulk overwrite body in bytes
+	BulkSize int
+	Created  []string
+	Updated  []string
+	Deleted  []string
+}
+
+type SyncOption func(o *syncOptions)
+
+type syncOptions struct {
+	maxBulkSize int
+	dropLocales map[Locale]bool
+	incremental bool
+}
+
+// SyncMaxBulkSize Bulk overwrite bodies over size bytes fall back to the incremental sync (Defaults to DefaultSyncMaxBulkSize)
+func SyncMaxBulkSize(size int) SyncOption {
+	return func(o *syncOptions) {
+		o.maxBulkSize = size
+	}
+}
+
+// SyncIncrementalOnly Never write a bulk overwrite, only the commands that changed are created, edited and deleted
+func SyncIncrementalOnly() SyncOption {
+	return func(o *syncOptions) {
+		o.incremental = true
+	}
+}
+
+// SyncDropLocales Strip the localizations of the locales before syncing, to shrink the payload of development syncs
+func SyncDropLocales(locales ...Locale) SyncOption {
+	return func(o *syncOptions) {
+		for _, locale := range locales {
+			o.dropLocales[locale] = true
+		}
+	}
+}
+
+// CommandRegistrationError Discord refused to register commands, like the 50035 validation errors.
+// Commands are the names of the commands the errors point to, every written command when Discord did not tell
+type CommandRegistrationError struct {
+	Commands []string
+	Err      *DiscordAPIError
+}
+
+func (e *CommandRegistrationError) Error() string {
+	return "httpcord: could not register the commands " + strings.Join(e.Commands, ", ") + ": " + e.Err.Error()
+}
+
+func (e *CommandRegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// commandsError Name the commands of the Discord error, bulk errors are keyed by the index of the command
+func commandsError(err error, commands ...*ApplicationCommand) error {
+	var apiErr *DiscordAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+
+	registration := &CommandRegistrationError{Err: apiErr}
+
+	var indexed map[string]json.RawMessage
+	if len(commands) > 1 && json.Unmarshal(apiErr.Errors, &indexed) == nil {
+		for key := range indexed {
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(commands) {
+				registration.Commands = append(registration.Commands, commands[i].Name)
+			}
+		}
+
+		sort.Strings(registration.Commands)
+	}
+
+	if len(registration.Commands) == 0 {
+		for _, command := range commands {
+			registration.Commands = append(registration.Commands, command.Name)
+		}
+	}
+
+	return registration
+}
+
+// GetCommands Registered application commands, global when guildID is empty
+func (c *RestClient) GetCommands(ctx context.Context, guildID Snowflake) ([]*ApplicationCommand, error) {
+	var commands []*ApplicationCommand
+	if err := c.Do(ctx, http.MethodGet, c.commandsRoute(guildID), nil, &commands); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+// GetCommand Registered application command by ID, global when guildID is empty
+func (c *RestClient) GetCommand(ctx context.Context, guildID, commandID Snowflake) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	if err := c.Do(ctx, http.MethodGet, c.commandRoute(guildID, commandID), nil, &command); err != nil {
+		return nil, err
+	}
+
+	return &command, nil
+}
+
+// CreateCommand Register the command, global when guildID is empty. A command with the same name and type is
+// replaced. Refused commands are reported with a CommandRegistrationError
+func (c *RestClient) CreateCommand(ctx context.Context, guildID Snowflake, command *ApplicationCommand) (*ApplicationCommand, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	if err := command.Validate(); err != nil {
+		return nil, err
+	}
+
+	var created ApplicationCommand
+	if err := c.Do(ctx, http.MethodPost, c.commandsRoute(guildID), command, &created); err != nil {
+		return nil, commandsError(err, command)
+	}
+
+	return &created, nil
+}
+
+// EditCommand Replace the definition of the registered command, global when guildID is empty.
+// Refused commands are reported with a CommandRegistrationError
+func (c *RestClient) EditCommand(ctx context.Context, guildID, commandID Snowflake, command *ApplicationCommand) (*ApplicationCommand, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	if err := command.Validate(); err != nil {
+		return nil, err
+	}
+
+	var edited ApplicationCommand
+	if err := c.Do(ctx, http.MethodPatch, c.commandRoute(guildID, commandID), command, &edited); err != nil {
+		return nil, commandsError(err, command)
+	}
+
+	return &edited, nil
+}
+
+// DeleteCommand Unregister the command, global when guildID is empty
+func (c *RestClient) DeleteCommand(ctx context.Context, guildID, commandID Snowflake) error {
+	if c.ApplicationID == "" {
+		return ErrSyncApplication
+	}
+
+	return c.Do(ctx, http.MethodDelete, c.commandRoute(guildID, commandID), nil, nil)
+}
+
+func (c *RestClient) commandsRoute(guildID Snowflake) string {
+	if guildID != "" {
+		return endpoints.ApplicationCommandsGuild(c.ApplicationID.String(), guildID.String())
+	}
+
+	return endpoints.ApplicationCommandsGlobal(c.ApplicationID.String())
+}
+
+func (c *RestClient) commandRoute(guildID, commandID Snowflake) string {
+	if guildID != "" {
+		return endpoints.ApplicationCommandGuild(c.ApplicationID.String(), guildID.String(), commandID.String())
+	}
+
+	return endpoints.ApplicationCommandGlobal(c.ApplicationID.String(), commandID.String())
+}
+
+// RegisterCommands Replace the registered commands with commands in a bulk overwrite, global when guildID is empty.
+// Every call counts against the daily command creation limits, see SyncCommands to only write the changes.
+// Refused commands are reported with a CommandRegistrationError, definitions failing ApplicationCommand.Validate are
+// refused before any request
+func (c *RestClient) RegisterCommands(ctx context.Context, guildID Snowflake, commands []*ApplicationCommand) ([]*ApplicationCommand, error) {
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	if commands == nil {
+		commands = []*ApplicationCommand{}
+	}
+
+	if err := validateCommands(commands); err != nil {
+		return nil, err
+	}
+
+	var registered []*ApplicationCommand
+	if err := c.Do(ctx, http.MethodPut, c.commandsRoute(guildID), commands, &registered); err != nil {
+		return nil, commandsError(err, commands...)
+	}
+
+	return registered, nil
+}
+
+// SyncGuild Make the commands of the guild match commands with SyncIncrementalOnly, so a deploy only writes the
+// commands that changed and the unchanged ones never count against the daily command creation limit
+func (c *RestClient) SyncGuild(ctx context.Context, guildID Snowflake, commands []*ApplicationCommand, opts ...SyncOption) (*SyncResult, error) {
+	return c.SyncCommands(ctx, guildID, commands, append([]SyncOption{SyncIncrementalOnly()}, opts...)...)
+}
+
+// SyncCommands Make the registered commands match commands, global when guildID is empty. Nothing is written when they
+// already match. The commands are written with a bulk overwrite unless its body exceeds SyncMaxBulkSize or Discord refuses
+// its size, then only the changed commands are created, edited and deleted. Definitions failing ApplicationCommand.Validate
+// are refused before any request
+func (c *RestClient) SyncCommands(ctx context.Context, guildID Snowflake, commands []*ApplicationCommand, opts ...SyncOption) (*SyncResult, error) {
+	o := syncOptions{maxBulkSize: DefaultSyncMaxBulkSize, dropLocales: make(map[Locale]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if c.ApplicationID == "" {
+		return nil, ErrSyncApplication
+	}
+
+	if commands == nil {
+		commands = []*ApplicationCommand{}
+	}
+
+	if err := validateCommands(commands); err != nil {
+		return nil, err
+	}
+
+	if len(o.dropLocales) > 0 {
+		commands = dropCommandLocales(commands, o.dropLocales)
+	}
+
+	existing, err := c.GetCommands(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := diffCommands(existing, commands)
+	if len(result.created)+len(result.updated)+len(result.deleted) == 0 {
+		return &SyncResult{Strategy: SyncUnchanged}, nil
+	}
+
+	body, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	sync := &SyncResult{BulkSize: len(body)}
+
+	if !o.incremental && len(body) <= o.maxBulkSize {
+		err := c.Do(ctx, http.MethodPut, c.commandsRoute(guildID), json.RawMessage(body), nil)
+
+		var apiErr *DiscordAPIError
+		if err == nil {
+			sync.Strategy = SyncBulkOverwrite
+			sync.Created, sync.Updated, sync.Deleted = result.names()
+			return sync, nil
+		} else if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusRequestEntityTooLarge {
+			return nil, commandsError(err, commands...)
+		}
+	}
+
+	sync.Strategy = SyncIncremental
+
+	for _, command := range result.created {
+		if err := c.Do(ctx, http.MethodPost, c.commandsRoute(guildID), command, nil); err != nil {
+			return sync, commandsError(err, command)
+		}
+
+		sync.Created = append(sync.Created, command.Name)
+	}
+
+	for _, update := range result.updated {
+		if err := c.Do(ctx, http.MethodPatch, c.commandRoute(guildID, update.id), update.command, nil); err != nil {
+			return sync, commandsError(err, update.command)
+		}
+
+		sync.Updated = append(sync.Updated, update.command.Name)
+	}
+
+	for _, command := range result.deleted {
+		if err := c.Do(ctx, http.MethodDelete, c.commandRoute(guildID, command.ID), nil, nil); err != nil {
+			return sync, err
+		}
+
+		sync.Deleted = append(sync.Deleted, command.Name)
+	}
+
+	return sync, nil
+}
+
+type commandUpdate struct {
+	id      Snowflake
+	command *ApplicationCommand
+}
+
+type commandDiff struct {
+	created []*ApplicationCommand
+	updated []commandUpdate
+	deleted []*ApplicationCommand
+}
+
+func (d *commandDiff) names() (created, updated, deleted []string) {
+	for _, command := range d.created {
+		created = append(created, command.Name)
+	}
+
+	for _, update := range d.updated {
+		updated = append(updated, update.command.Name)
+	}
+
+	for _, command := range d.deleted {
+		deleted = append(deleted, command.Name)
+	}
+
+	return
+}
+
+// commandKey Commands are unique by type and name
+func commandKey(command *ApplicationCommand) string {
+	t := ChatInputApplicationCommandType
+	if command.Type != nil {
+		t = *command.Type
+	}
+
+	return strconv.Itoa(int(t)) + ":" + command.Name
+}
+
+func diffCommands(existing, desired []*ApplicationCommand) *commandDiff {
+	diff := &commandDiff{}
+	registered := make(map[string]*ApplicationCommand, len(existing))
+
+	for _, command := range existing {
+		registered[commandKey(command)] = command
+	}
+
+	for _, command := range desired {
+		key := commandKey(command)
+		current, ok := registered[key]
+
+		switch {
+		case !ok:
+			diff.created = append(diff.created, command)
+		case !bytes.Equal(canonicalCommand(current), canonicalCommand(command)):
+			diff.updated = append(diff.updated, commandUpdate{id: current.ID, command: command})
+		}
+
+		delete(registered, key)
+	}
+
+	for _, command := range existing {
+		if _, ok := registered[commandKey(command)]; ok {
+			diff.deleted = append(diff.deleted, command)
+		}
+	}
+
+	return diff
+}
+
+// canonicalCommand JSON of the fields users define, with the defaults Discord fills in
+func canonicalCommand(command *ApplicationCommand) []byte {
+	c := *command
+	c.ID, c.ApplicationID, c.GuildID, c.Version, c.DefaultPermission = "", "", nil, "", nil
+
+	if c.Type == nil {
+		t := ChatInputApplicationCommandType
+		c.Type = &t
+	}
+
+	if c.AllowUseInDMs == nil {
+		allowed := true
+		c.AllowUseInDMs = &allowed
+	}
+
+	if c.Options == nil {
+		c.Options = []ApplicationCommandOption{}
+	}
+
+	if c.IntegrationTypes == nil {
+		c.IntegrationTypes = []ApplicationIntegrationType{GuildInstallIntegration}
+	}
+
+	b, _ := json.Marshal(c)
+	return b
+}
+
+// commandsClient Client of the connection bound to ConnectionOptions.ApplicationID when it has no application
+func (c *Connection) commandsClient() *RestClient {
+	if c.Client.ApplicationID == "" && c.applicationID != "" {
+		return c.Client.WithApplication(c.applicationID)
+	}
+
+	return c.Client
+}
+
+// RegisterGlobalCommands Replace the global commands of the application (See RestClient.RegisterCommands)
+func (c *Connection) RegisterGlobalCommands(ctx context.Context, commands []*ApplicationCommand) ([]*ApplicationCommand, error) {
+	return c.commandsClient().RegisterCommands(ctx, "", commands)
+}
+
+// RegisterGuildCommands Replace the commands of the application in the guild (See RestClient.RegisterCommands)
+func (c *Connection) RegisterGuildCommands(ctx context.Context, guildID Snowflake, commands []*ApplicationCommand) ([]*ApplicationCommand, error) {
+	return c.commandsClient().RegisterCommands(ctx, guildID, commands)
+}
+
+// SyncCommands Write the commands only when they differ from the registered ones, global when guildID is empty
+// (See RestClient.SyncCommands)
+func (c *Connection) SyncCommands(ctx context.Context, guildID Snowflake, commands []*ApplicationCommand, opts ...SyncOption) (*SyncResult, error) {
+	return c.commandsClient().SyncCommands(ctx, guildID, commands, opts...)
+}
+
+// dropCommandLocales Copy of the commands without the localizations of the locales
+func dropCommandLocales(commands []*ApplicationCommand, drop map[Locale]bool) []*ApplicationCommand {
+	stripped := make([]*ApplicationCommand, len(commands))
+
+	for i, command := range commands {
+		c := *command
+		c.NameLocalizations = dropLocales(c.NameLocalizations, drop)
+		c.DescriptionLocalizations = dropLocales(c.DescriptionLocalizations, drop)
+		c.Options = dropOptionLocales(c.Options, drop)
+		stripped[i] = &c
+	}
+
+	return stripped
+}
+
+func dropOptionLocales(options []ApplicationCommandOption, drop map[Locale]bool) []ApplicationCommandOption {
+	if options == nil {
+		return nil
+	}
+
+	stripped := make([]ApplicationCommandOption, len(options))
+
+	for i, option := range options {
+		option.NameLocalizations = dropLocales(option.NameLocalizations, drop)
+		option.DescriptionLocalizations = dropLocales(option.DescriptionLocalizations, drop)
+		option.Options = dropOptionLocales(option.Options, drop)
+
+		if option.Choices != nil {
+			choices := make([]ApplicationCommandOptionChoice, len(option.Choices))
+			for j, choice := range option.Choices {
+				choice.NameLocalizations = dropLocales(choice.NameLocalizations, drop)
+				choices[j] = choice
+			}
+
+			option.Choices = choices
+		}
+
+		stripped[i] = option
+	}
+
+	return stripped
+}
+
+func dropLocales(localizations Dictionary, drop map[Locale]bool) Dictionary {
+	if localizations == nil {
+		return nil
+	}
+
+	kept := make(Dictionary, len(localizations))
+	for locale, value := range localizations {
+		if !drop[locale] {
+			kept[locale] = value
+		}
+	}
+
+	return kept
+}
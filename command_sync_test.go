@@ -0,0 +1,249 @@
+package httpcord_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+// localizedManifest Commands described in every locale, large enough to exceed the bulk limits
+func localizedManifest(commands int) []*httpcord.ApplicationCommand {
+	locales := []httpcord.Locale{
+		httpcord.EnglishGBLocale, httpcord.BulgarianLocale, httpcord.ChineseCNLocale, httpcord.ChineseTWLocale, httpcord.CroatianLocale,
+		httpcord.CzechLocale, httpcord.DanishLocale, httpcord.DutchLocale, httpcord.FinnishLocale, httpcord.FrenchLocale,
+		httpcord.GermanLocale, httpcord.GreekLocale, httpcord.HindiLocale, httpcord.HungarianLocale, httpcord.IndonesianLocale,
+		httpcord.ItalianLocale, httpcord.JapaneseLocale, httpcord.KoreanLocale, httpcord.LithuanianLocale, httpcord.NorwegianLocale,
+		httpcord.PolishLocale, httpcord.PortugueseBRLocale, httpcord.RomanianLocale, httpcord.RussianLocale, httpcord.SpanishESLocale,
+		httpcord.SpanishLATAMLocale, httpcord.SwedishLocale, httpcord.ThaiLocale, httpcord.TurkishLocale, httpcord.EnglishUSLocale,
+	}
+
+	manifest := make([]*httpcord.ApplicationCommand, commands)
+	for i := range manifest {
+		descriptions := make(httpcord.Dictionary, len(locales))
+		for _, locale := range locales {
+			descriptions[locale] = fmt.Sprintf("Description %d of the command in %s", i, locale)
+		}
+
+		manifest[i] = &httpcord.ApplicationCommand{
+			Name:                     fmt.Sprintf("command-%02d", i),
+			Description:              fmt.Sprintf("Description %d of the command", i),
+			DescriptionLocalizations: descriptions,
+		}
+	}
+
+	return manifest
+}
+
+func TestSyncCommandsFallback(t *testing.T) {
+	manifest := localizedManifest(80)
+
+	bulk, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// registered command-00 is up to date, command-01 changed and legacy is gone from the manifest
+	unchanged := *manifest[0]
+	unchanged.ID = "100"
+	changed := *manifest[1]
+	changed.ID, changed.Description = "101", "Outdated description"
+	registered := []*httpcord.ApplicationCommand{&unchanged, &changed, {ID: "7", Name: "legacy", Description: "Removed command"}}
+
+	tests := []struct {
+		name   string
+		script func(f *httpcordtest.FakeDiscord)
+		opts   []httpcord.SyncOption
+		// bulk Bulk overwrites sent
+		bulk     int
+		strategy httpcord.SyncStrategy
+		// created Commands created before the sync stopped, the other writes only happen when it completes
+		created int
+		// err Code of the Discord error returned, 0 when the sync completes
+		err int
+	}{
+		{"bulk body over the limit", func(f *httpcordtest.FakeDiscord) {}, []httpcord.SyncOption{httpcord.SyncMaxBulkSize(len(bulk) - 1)},
+			0, httpcord.SyncIncremental, 78, 0},
+		{"bulk body refused as too large", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodPut, "/applications/1/commands").RespondError(http.StatusRequestEntityTooLarge, 40005, "Request entity too large")
+		}, nil, 1, httpcord.SyncIncremental, 78, 0},
+		{"bulk overwrite failing", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodPut, "/applications/1/commands").RespondError(http.StatusForbidden, 50001, "Missing Access")
+		}, nil, 1, httpcord.SyncBulkOverwrite, 0, 50001},
+		{"incremental write refused", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodPost, "/applications/1/commands").Times(2)
+			f.Expect(http.MethodPost, "/applications/1/commands").RespondError(http.StatusForbidden, 50001, "Missing Access").Times(1)
+		}, []httpcord.SyncOption{httpcord.SyncIncrementalOnly()}, 0, httpcord.SyncIncremental, 2, 50001},
+		{"bulk body within the limit", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodPut, "/applications/1/commands")
+		}, []httpcord.SyncOption{httpcord.SyncMaxBulkSize(len(bulk))}, 1, httpcord.SyncBulkOverwrite, 78, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			fake.Expect(http.MethodGet, "/applications/1/commands").RespondWith(registered)
+			test.script(fake)
+
+			fake.Allow(http.MethodPost, "/applications/1/commands")
+			fake.Allow(http.MethodPatch, "/applications/1/commands/101")
+			fake.Allow(http.MethodDelete, "/applications/1/commands/7")
+
+			result, err := fake.Client().WithApplication("1").SyncCommands(context.Background(), "", manifest, test.opts...)
+
+			if puts := fake.RequestsTo(http.MethodPut, "/applications/1/commands"); len(puts) != test.bulk {
+				t.Errorf("%d bulk overwrites sent, want %d", len(puts), test.bulk)
+			} else if test.bulk == 1 && !bytes.Equal(puts[0].Payload, bulk) {
+				t.Error("bulk overwrite body is not the manifest")
+			}
+
+			posts := len(fake.RequestsTo(http.MethodPost, "/applications/1/commands"))
+			edits := len(fake.RequestsTo(http.MethodPatch, "/applications/1/commands/*"))
+			deletes := len(fake.RequestsTo(http.MethodDelete, "/applications/1/commands/*"))
+
+			if test.strategy == httpcord.SyncBulkOverwrite && posts+edits+deletes != 0 {
+				t.Errorf("%d creates, %d edits and %d deletes sent alongside the bulk overwrite", posts, edits, deletes)
+			}
+
+			if test.err != 0 {
+				var apiErr *httpcord.DiscordAPIError
+				if !errors.As(err, &apiErr) || apiErr.Code != test.err {
+					t.Fatalf("error %v, want the code %d", err, test.err)
+				}
+
+				// The failed bulk overwrite reports nothing, the incremental sync what it wrote before failing
+				if test.strategy == httpcord.SyncBulkOverwrite && result != nil {
+					t.Errorf("result %+v of a failed bulk overwrite", result)
+				}
+
+				if test.strategy == httpcord.SyncIncremental && (result == nil || len(result.Created) != test.created || edits+deletes != 0) {
+					t.Errorf("result %+v with %d edits and %d deletes, want %d created and nothing else", result, edits, deletes, test.created)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Strategy != test.strategy || result.BulkSize != len(bulk) {
+				t.Errorf("%s with a bulk size of %d, want %s with %d", result.Strategy, result.BulkSize, test.strategy, len(bulk))
+			}
+
+			if len(result.Created) != test.created || len(result.Updated) != 1 || result.Updated[0] != "command-01" ||
+				len(result.Deleted) != 1 || result.Deleted[0] != "legacy" {
+				t.Errorf("created %d, updated %v and deleted %v, want %d created, command-01 updated and legacy deleted",
+					len(result.Created), result.Updated, result.Deleted, test.created)
+			}
+
+			if test.strategy == httpcord.SyncIncremental && (posts != test.created || edits != 1 || deletes != 1) {
+				t.Errorf("%d creates, %d edits and %d deletes sent, want %d, 1 and 1", posts, edits, deletes, test.created)
+			}
+		})
+	}
+}
+
+func TestSyncDropLocales(t *testing.T) {
+	manifest := localizedManifest(80)
+
+	bulk, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodGet, "/applications/1/guilds/2/commands").RespondWith([]*httpcord.ApplicationCommand{})
+	fake.Expect(http.MethodPut, "/applications/1/guilds/2/commands")
+
+	result, err := fake.Client().WithApplication("1").SyncCommands(context.Background(), "2", manifest,
+		httpcord.SyncMaxBulkSize(len(bulk)-1), httpcord.SyncDropLocales(httpcord.FrenchLocale, httpcord.GermanLocale))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Strategy != httpcord.SyncBulkOverwrite || result.BulkSize >= len(bulk) {
+		t.Errorf("%s with a bulk size of %d, want a bulk overwrite smaller than %d", result.Strategy, result.BulkSize, len(bulk))
+	}
+
+	var written []*httpcord.ApplicationCommand
+	if err := fake.RequestsTo(http.MethodPut, "/applications/1/guilds/2/commands")[0].Decode(&written); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range written {
+		_, french := command.DescriptionLocalizations[httpcord.FrenchLocale]
+		_, german := command.DescriptionLocalizations[httpcord.GermanLocale]
+		if french || german || len(command.DescriptionLocalizations) != 28 {
+			t.Fatalf("%s written with the localizations %v, want every locale but fr and de", command.Name, command.DescriptionLocalizations)
+		}
+	}
+
+	// The manifest of the caller keeps its localizations
+	if len(manifest[0].DescriptionLocalizations) != 30 {
+		t.Errorf("manifest localizations %v, want the 30 locales", manifest[0].DescriptionLocalizations)
+	}
+}
+
+func TestSyncGuild(t *testing.T) {
+	manifest := []*httpcord.ApplicationCommand{
+		{Name: "ban", Description: "Ban a member"},
+		{Name: "kick", Description: "Kick a member"},
+		{Name: "warn", Description: "Warn a member"},
+	}
+
+	registered := []*httpcord.ApplicationCommand{
+		{ID: "100", Name: "ban", Description: "Ban a member"},
+		{ID: "101", Name: "kick", Description: "Outdated description"},
+		{ID: "7", Name: "legacy", Description: "Removed command"},
+	}
+
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodGet, "/applications/1/guilds/2/commands").RespondWith(registered)
+	fake.Expect(http.MethodPost, "/applications/1/guilds/2/commands").Times(1)
+	fake.Expect(http.MethodPatch, "/applications/1/guilds/2/commands/101").Times(1)
+	fake.Expect(http.MethodDelete, "/applications/1/guilds/2/commands/7").RespondStatus(http.StatusNoContent, nil).Times(1)
+
+	// The small manifest would be a bulk overwrite with SyncCommands
+	result, err := fake.Client().WithApplication("1").SyncGuild(context.Background(), "2", manifest, httpcord.SyncMaxBulkSize(1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Strategy != httpcord.SyncIncremental || len(result.Created) != 1 || result.Created[0] != "warn" ||
+		len(result.Updated) != 1 || result.Updated[0] != "kick" || len(result.Deleted) != 1 || result.Deleted[0] != "legacy" {
+		t.Errorf("result %+v, want warn created, kick updated and legacy deleted incrementally", result)
+	}
+
+	if puts := fake.RequestsTo(http.MethodPut, "/applications/1/guilds/2/commands"); len(puts) != 0 {
+		t.Errorf("%d bulk overwrites sent, want none", len(puts))
+	}
+
+	var created httpcord.ApplicationCommand
+	if err := fake.RequestsTo(http.MethodPost, "/applications/1/guilds/2/commands")[0].Decode(&created); err != nil || created.Name != "warn" {
+		t.Errorf("created %+v, %v, want only warn", created, err)
+	}
+
+	// Nothing is written once the guild matches the manifest
+	synced := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	synced.Expect(http.MethodGet, "/applications/1/guilds/2/commands").RespondWith([]*httpcord.ApplicationCommand{
+		{ID: "100", Name: "ban", Description: "Ban a member"},
+		{ID: "101", Name: "kick", Description: "Kick a member"},
+		{ID: "102", Name: "warn", Description: "Warn a member"},
+	})
+
+	result, err = synced.Client().WithApplication("1").SyncGuild(context.Background(), "2", manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(synced.Requests()) != 1 || len(result.Created)+len(result.Updated)+len(result.Deleted) != 0 {
+		t.Errorf("result %+v after %d requests, want only the registered commands read", result, len(synced.Requests()))
+	}
+}
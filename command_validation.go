@@ -0,0 +1,200 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidCommand The command definition would be refused by Discord
+var ErrInvalidCommand = errors.New("httpcord: invalid command definition")
+
+// CommandValidationError Field of a command definition breaking the rules of Discord, see ApplicationCommand.Validate
+type CommandValidationError struct {
+	Command string
+	// Field is the path of the field like "name", "options.language.description" or "options.language.choices.0.name"
+	Field string
+	// Locale is set for the localizations, like "name_localizations" with the "fr" Locale
+	Locale Locale
+	Value  string
+	Reason string
+}
+
+func (e *CommandValidationError) Error() string {
+	field := e.Field
+	if e.Locale != "" {
+		field += "[" + string(e.Locale) + "]"
+	}
+
+	return fmt.Sprintf("httpcord: command %q %s %q %s", e.Command, field, e.Value, e.Reason)
+}
+
+func (e *CommandValidationError) Is(target error) bool {
+	return target == ErrInvalidCommand
+}
+
+// chatInputNamePattern Names of the chat input commands and of the options
+var chatInputNamePattern = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+// commandValidator Validate the fields of one command, errors name the command
+type commandValidator struct {
+	command string
+}
+
+func (v *commandValidator) fail(field string, locale Locale, value, reason string) error {
+	return &CommandValidationError{Command: v.command, Field: field, Locale: locale, Value: value, Reason: reason}
+}
+
+// name 1-32 characters, lowercase letters, numbers, - and _ for chat input names
+func (v *commandValidator) name(field string, locale Locale, name string, chatInput bool) error {
+	if !chatInput {
+		if length := utf8.RuneCountInString(name); length < 1 || length > 32 {
+			return v.fail(field, locale, name, "must have 1 to 32 characters")
+		}
+
+		return nil
+	}
+
+	if !chatInputNamePattern.MatchString(name) {
+		return v.fail(field, locale, name, "must have 1 to 32 letters, numbers, - or _")
+	}
+
+	if strings.ToLower(name) != name {
+		return v.fail(field, locale, name, "must be lowercase")
+	}
+
+	return nil
+}
+
+// text 1 to max characters, like the descriptions and the choice names
+func (v *commandValidator) text(field string, locale Locale, text string, max int) error {
+	if length := utf8.RuneCountInString(text); length < 1 || length > max {
+		return v.fail(field, locale, text, "must have 1 to "+strconv.Itoa(max)+" characters")
+	}
+
+	return nil
+}
+
+// localizations Check the locales of the dictionary and its values with check
+func (v *commandValidator) localizations(field string, dictionary Dictionary, check func(field string, locale Locale, value string) error) error {
+	for _, locale := range sortedLocales(dictionary) {
+		if !locale.Known() {
+			return v.fail(field, locale, dictionary[locale], "is not a Discord locale")
+		}
+
+		if err := check(field, locale, dictionary[locale]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *commandValidator) option(path string, option *ApplicationCommandOption) error {
+	name := func(field string, locale Locale, value string) error {
+		return v.name(field, locale, value, true)
+	}
+
+	description := func(field string, locale Locale, value string) error {
+		return v.text(field, locale, value, 100)
+	}
+
+	if err := name(path+".name", "", option.Name); err != nil {
+		return err
+	}
+
+	if err := v.localizations(path+".name_localizations", option.NameLocalizations, name); err != nil {
+		return err
+	}
+
+	if err := description(path+".description", "", option.Description); err != nil {
+		return err
+	}
+
+	if err := v.localizations(path+".description_localizations", option.DescriptionLocalizations, description); err != nil {
+		return err
+	}
+
+	choiceName := func(field string, locale Locale, value string) error {
+		return v.text(field, locale, value, 100)
+	}
+
+	for i := range option.Choices {
+		choice := &option.Choices[i]
+		choicePath := path + ".choices." + strconv.Itoa(i)
+
+		if err := choiceName(choicePath+".name", "", choice.Name); err != nil {
+			return err
+		}
+
+		if err := v.localizations(choicePath+".name_localizations", choice.NameLocalizations, choiceName); err != nil {
+			return err
+		}
+	}
+
+	for i := range option.Options {
+		if err := v.option(path+".options."+option.Options[i].Name, &option.Options[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate Check the names, descriptions and their localizations like Discord does before registering the command.
+// Chat input names and option names are 1-32 lowercase letters, numbers, - or _, descriptions have 1 to 100 characters
+// and localizations only use the known Locale keys. Returns the first CommandValidationError
+func (c *ApplicationCommand) Validate() error {
+	v := &commandValidator{command: c.Name}
+	chatInput := c.Type == nil || *c.Type == ChatInputApplicationCommandType
+
+	name := func(field string, locale Locale, value string) error {
+		return v.name(field, locale, value, chatInput)
+	}
+
+	if err := name("name", "", c.Name); err != nil {
+		return err
+	}
+
+	if err := v.localizations("name_localizations", c.NameLocalizations, name); err != nil {
+		return err
+	}
+
+	if !chatInput {
+		return nil
+	}
+
+	description := func(field string, locale Locale, value string) error {
+		return v.text(field, locale, value, 100)
+	}
+
+	if err := description("description", "", c.Description); err != nil {
+		return err
+	}
+
+	if err := v.localizations("description_localizations", c.DescriptionLocalizations, description); err != nil {
+		return err
+	}
+
+	for i := range c.Options {
+		if err := v.option("options."+c.Options[i].Name, &c.Options[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateCommands First CommandValidationError of the commands
+func validateCommands(commands []*ApplicationCommand) error {
+	for _, command := range commands {
+		if err := command.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// languageCommand Valid chat input command with localized names, descriptions and choices
+func languageCommand() *ApplicationCommand {
+	return &ApplicationCommand{
+		Name:                     "language",
+		NameLocalizations:        Dictionary{FrenchLocale: "langue", HindiLocale: "भाषा", SpanishLATAMLocale: "idioma"},
+		Description:              "Set the language",
+		DescriptionLocalizations: Dictionary{FrenchLocale: "Choisir la langue", JapaneseLocale: "言語を設定"},
+		Options: []ApplicationCommandOption{{
+			Type:                     SubCommandApplicationCommandOptionType,
+			Name:                     "set",
+			Description:              "Set it",
+			DescriptionLocalizations: Dictionary{FrenchLocale: "Le choisir"},
+			Options: []ApplicationCommandOption{{
+				Type:              StringApplicationCommandOptionType,
+				Name:              "value",
+				NameLocalizations: Dictionary{FrenchLocale: "valeur", ThaiLocale: "ค่า"},
+				Description:       "Language",
+				Choices: []ApplicationCommandOptionChoice{
+					{Name: "English", Value: "en"},
+					{Name: "French", NameLocalizations: Dictionary{FrenchLocale: "Français"}, Value: "fr"},
+				},
+			}},
+		}},
+	}
+}
+
+func TestCommandValidation(t *testing.T) {
+	long := strings.Repeat("d", 101)
+
+	tests := []struct {
+		name   string
+		modify func(c *ApplicationCommand)
+		// field Field and locale of the error, empty when the command is valid
+		field  string
+		locale Locale
+		reason string
+	}{
+		{"valid", func(c *ApplicationCommand) {}, "", "", ""},
+		{"uppercase name", func(c *ApplicationCommand) { c.Name = "Language" }, "name", "", "must be lowercase"},
+		{"name with a space", func(c *ApplicationCommand) { c.Name = "set language" }, "name", "", "letters, numbers, - or _"},
+		{"name too long", func(c *ApplicationCommand) { c.Name = strings.Repeat("l", 33) }, "name", "", "letters, numbers, - or _"},
+		{"unknown name locale", func(c *ApplicationCommand) { c.NameLocalizations["fr-FR"] = "langue" }, "name_localizations", "fr-FR",
+			"is not a Discord locale"},
+		{"uppercase localized name", func(c *ApplicationCommand) { c.NameLocalizations[GermanLocale] = "Sprache" }, "name_localizations",
+			GermanLocale, "must be lowercase"},
+		{"empty localized name", func(c *ApplicationCommand) { c.NameLocalizations[GermanLocale] = "" }, "name_localizations", GermanLocale,
+			"letters, numbers, - or _"},
+		{"empty description", func(c *ApplicationCommand) { c.Description = "" }, "description", "", "1 to 100 characters"},
+		{"description too long", func(c *ApplicationCommand) { c.Description = long }, "description", "", "1 to 100 characters"},
+		{"localized description too long", func(c *ApplicationCommand) { c.DescriptionLocalizations[FrenchLocale] = long },
+			"description_localizations", FrenchLocale, "1 to 100 characters"},
+		{"localized description at the limit in runes", func(c *ApplicationCommand) {
+			c.DescriptionLocalizations[FrenchLocale] = strings.Repeat("é", 100)
+		}, "", "", ""},
+		{"unknown description locale", func(c *ApplicationCommand) { c.DescriptionLocalizations["xx"] = "?" }, "description_localizations", "xx",
+			"is not a Discord locale"},
+		{"subcommand description locale", func(c *ApplicationCommand) { c.Options[0].DescriptionLocalizations["en"] = "Set it" },
+			"options.set.description_localizations", "en", "is not a Discord locale"},
+		{"nested option localized name", func(c *ApplicationCommand) { c.Options[0].Options[0].NameLocalizations[FrenchLocale] = "la valeur" },
+			"options.set.options.value.name_localizations", FrenchLocale, "letters, numbers, - or _"},
+		{"choice localized name too long", func(c *ApplicationCommand) {
+			c.Options[0].Options[0].Choices[1].NameLocalizations[FrenchLocale] = long
+		}, "options.set.options.value.choices.1.name_localizations", FrenchLocale, "1 to 100 characters"},
+		{"choice name locale", func(c *ApplicationCommand) {
+			c.Options[0].Options[0].Choices[0].NameLocalizations = Dictionary{"pt-PT": "Inglês"}
+		}, "options.set.options.value.choices.0.name_localizations", "pt-PT", "is not a Discord locale"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			command := languageCommand()
+			test.modify(command)
+
+			err := command.Validate()
+			if test.field == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var validationErr *CommandValidationError
+			if !errors.Is(err, ErrInvalidCommand) || !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want a CommandValidationError", err)
+			}
+
+			if validationErr.Command != command.Name || validationErr.Field != test.field || validationErr.Locale != test.locale ||
+				!strings.Contains(validationErr.Reason, test.reason) {
+				t.Errorf("Validate() = %+v, want the field %s[%s] that %s", validationErr, test.field, test.locale, test.reason)
+			}
+		})
+	}
+}
+
+func TestContextMenuCommandValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		command *ApplicationCommand
+		field   string
+	}{
+		{"name with spaces and capitals", NewUserCommand("User Info").SetNameLocalizations(Dictionary{FrenchLocale: "Infos Membre"}), ""},
+		{"name too long", NewMessageCommand(strings.Repeat("R", 33)), "name"},
+		{"unknown locale", NewMessageCommand("Report Message").SetNameLocalizations(Dictionary{"fr-CA": "Signaler"}), "name_localizations"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.command.Validate()
+
+			var validationErr *CommandValidationError
+			if test.field == "" && err != nil || test.field != "" && (!errors.As(err, &validationErr) || validationErr.Field != test.field) {
+				t.Errorf("Validate() = %v, want the field %q", err, test.field)
+			}
+		})
+	}
+}
+
+func TestRegisterCommandsValidation(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.ApplicationID = "1"
+
+	invalid := languageCommand()
+	invalid.DescriptionLocalizations["en"] = "Set the language"
+
+	if _, err := client.RegisterCommands(context.Background(), "", []*ApplicationCommand{languageCommand(), invalid}); !errors.Is(err, ErrInvalidCommand) {
+		t.Errorf("RegisterCommands() = %v, want ErrInvalidCommand", err)
+	}
+
+	if _, err := client.SyncCommands(context.Background(), "2", []*ApplicationCommand{invalid}); !errors.Is(err, ErrInvalidCommand) {
+		t.Errorf("SyncCommands() = %v, want ErrInvalidCommand", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("%d requests sent, want the commands refused before", requests)
+	}
+}
+
+func TestLocaleKnown(t *testing.T) {
+	for locale, known := range map[Locale]bool{
+		EnglishUSLocale: true, SpanishLATAMLocale: true, IndonesianLocale: true, ChineseTWLocale: true,
+		"en": false, "fr-FR": false, "EN-US": false, "": false,
+	} {
+		if locale.Known() != known {
+			t.Errorf("Locale(%q).Known() = %v, want %v", locale, !known, known)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package httpcord
+
+import "strings"
+
+// ComponentRoute Handler of the components whose custom_id matches Pattern
+type ComponentRoute struct {
+	// Pattern is a custom_id, a prefix followed by "*" like "page:*" or segments with placeholders like
+	// "confirm:{userID}:{action}"
+	Pattern string
+	Handler Handler
+	// Source is the file:line the route was registered at
+	Source string
+}
+
+// componentRoutes Component routes registered with Connection.Component
+type componentRoutes struct {
+	exact     map[string]*ComponentRoute
+	templates []*customIDTemplate
+	prefixes  []*ComponentRoute
+	fallback  Handler
+	// fallbackSource is the file:line the fallback was registered at
+	fallbackSource string
+	// rejectForeign refuses the components of messages sent by other applications
+	rejectForeign bool
+}
+
+// Component Register a handler for the components with the custom_id, a trailing "*" matches any suffix
+// like "page:*" for "page:3" (See ConnectionContext.CustomIDParam) and "{name}" segments match one value of
+// EncodeCustomID like "confirm:{userID}:{action}" (See ConnectionContext.CustomIDVar). Exact custom_ids win over
+// placeholders, the patterns with the most literal segments first, then the longest prefix. Panics when the pattern
+// is already registered
+func (c *Connection) Component(pattern string, handler Handler) *ComponentRoute {
+	return c.router.addUserComponent(&ComponentRoute{Pattern: pattern, Handler: handler, Source: callerSite(1)})
+}
+
+func (r *commandRouter) addUserComponent(route *ComponentRoute) *ComponentRoute {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.userComponents.add("component", route)
+	return route
+}
+
+// add Register the route, panics with a RouteConflictError of the kind when the pattern is already registered
+func (r *componentRoutes) add(kind string, route *ComponentRoute) {
+	pattern := route.Pattern
+
+	if existing := r.find(pattern); existing != nil {
+		panic(&RouteConflictError{Kind: kind, Key: pattern, Source: route.Source, Existing: existing.Source})
+	}
+
+	if strings.Contains(pattern, "{") {
+		templates := append(r.templates, parseCustomIDTemplate(route))
+
+		// Most literal segments first
+		for i := len(templates) - 1; i > 0 && templates[i].literals > templates[i-1].literals; i-- {
+			templates[i], templates[i-1] = templates[i-1], templates[i]
+		}
+
+		r.templates = templates
+		return
+	}
+
+	if !strings.HasSuffix(pattern, "*") {
+		r.exact[pattern] = route
+		return
+	}
+
+	prefixes := append(r.prefixes, route)
+
+	// Longest prefix first
+	for i := len(prefixes) - 1; i > 0 && len(prefixes[i].Pattern) > len(prefixes[i-1].Pattern); i-- {
+		prefixes[i], prefixes[i-1] = prefixes[i-1], prefixes[i]
+	}
+
+	r.prefixes = prefixes
+}
+
+// FallbackComponent Handle the component interactions without a route, before the FallbackProxy. Panics when a
+// fallback is already registered
+func (c *Connection) FallbackComponent(handler Handler) {
+	c.router.mu.Lock()
+	defer c.router.mu.Unlock()
+
+	routes, source := &c.router.userComponents, callerSite(1)
+	if routes.fallback != nil {
+		panic(&RouteConflictError{Kind: "fallback component", Key: "*", Source: source, Existing: routes.fallbackSource})
+	}
+
+	routes.fallback, routes.fallbackSource = handler, source
+}
+
+// find Route registered with the pattern
+func (r *componentRoutes) find(pattern string) *ComponentRoute {
+	if route, ok := r.exact[pattern]; ok {
+		return route
+	}
+
+	for _, template := range r.templates {
+		if template.route.Pattern == pattern {
+			return template.route
+		}
+	}
+
+	for _, route := range r.prefixes {
+		if route.Pattern == pattern {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// match Route of the custom_id, the part matched by the "*" and the values of the placeholders
+func (r *componentRoutes) match(customID string) (*ComponentRoute, string, map[string]string) {
+	if route, ok := r.exact[customID]; ok {
+		return route, "", nil
+	}
+
+	if len(r.templates) > 0 {
+		if segments, err := DecodeCustomID(customID); err == nil {
+			for _, template := range r.templates {
+				if vars, ok := template.match(segments); ok {
+					return template.route, "", vars
+				}
+			}
+		}
+	}
+
+	for _, route := range r.prefixes {
+		prefix := strings.TrimSuffix(route.Pattern, "*")
+
+		if strings.HasPrefix(customID, prefix) {
+			return route, customID[len(prefix):], nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// dispatchComponent Run the internal handler, the matching route or the fallback of the component interaction
+func (r *commandRouter) dispatchComponent(ctx ConnectionContext) bool {
+	customID := ctx.Interaction.ComponentData().CustomID
+
+	if handler := r.component(customID); handler != nil {
+		handler(ctx)
+		return true
+	}
+
+	r.mu.RLock()
+	route, param, vars := r.userComponents.match(customID)
+	fallback := r.userComponents.fallback
+	rejectForeign := r.userComponents.rejectForeign
+	r.mu.RUnlock()
+
+	if rejectForeign && (route != nil || fallback != nil) && ctx.MessageProvenance() == ForeignProvenance {
+		ctx.replyForeignComponent()
+		return true
+	}
+
+	switch {
+	case route != nil:
+		ctx.componentParam, ctx.componentVars = param, vars
+		route.Handler(ctx)
+	case fallback != nil:
+		fallback(ctx)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// CustomID custom_id of the component or modal interaction, empty for other interactions
+func (ctx *ConnectionContext) CustomID() string {
+	switch data := ctx.Interaction.Data.(type) {
+	case ComponentInteractionData:
+		return data.CustomID
+	case ModalSubmitInteractionData:
+		return data.CustomID
+	}
+
+	return ""
+}
+
+// CustomIDParam Part of the custom_id matched by the "*" of the component route, "3" for "page:3" routed by "page:*"
+func (ctx *ConnectionContext) CustomIDParam() string {
+	return ctx.componentParam
+}
+
+// ComponentType Type of the component interaction, 0 for other interactions
+func (ctx *ConnectionContext) ComponentType() ComponentType {
+	if data, ok := ctx.Interaction.Data.(ComponentInteractionData); ok {
+		return data.ComponentType
+	}
+
+	return 0
+}
+
+// SelectedValues Values chosen in the select menu, nil for buttons and other interactions
+func (ctx *ConnectionContext) SelectedValues() []string {
+	if data, ok := ctx.Interaction.Data.(ComponentInteractionData); ok {
+		return data.Values
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package httpcord_test
+
+import (
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+// componentRouting Route, "*" param and placeholders the component interaction was dispatched with
+type componentRouting struct {
+	route string
+	param string
+	vars  map[string]string
+}
+
+func routeComponent(t *testing.T, patterns []string, fallback bool, customID string) componentRouting {
+	t.Helper()
+
+	conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+
+	var got componentRouting
+	handler := func(route string) httpcord.Handler {
+		return func(ctx httpcord.ConnectionContext) {
+			got = componentRouting{route: route, param: ctx.CustomIDParam()}
+			for _, name := range []string{"userID", "action"} {
+				if value, ok := ctx.CustomIDVar(name); ok {
+					if got.vars == nil {
+						got.vars = make(map[string]string)
+					}
+
+					got.vars[name] = value
+				}
+			}
+
+			ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: route})
+		}
+	}
+
+	for _, pattern := range patterns {
+		conn.Component(pattern, handler(pattern))
+	}
+
+	if fallback {
+		conn.FallbackComponent(handler("fallback"))
+	}
+
+	httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, httpcordtest.NewComponentInteraction(customID)))
+	return got
+}
+
+func TestComponentRoutePrecedence(t *testing.T) {
+	patterns := []string{"*", "page:*", "page:admin:*", "page:last", "confirm:*", "confirm:{userID}:{action}", "confirm:{userID}:delete"}
+
+	escaped, err := httpcord.EncodeCustomID("confirm", "a:b", "ban")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		customID string
+		want     componentRouting
+	}{
+		{"exact over prefixes", "page:last", componentRouting{route: "page:last"}},
+		{"prefix", "page:3", componentRouting{route: "page:*", param: "3"}},
+		{"empty param", "page:", componentRouting{route: "page:*"}},
+		{"longest prefix", "page:admin:7", componentRouting{route: "page:admin:*", param: "7"}},
+		{"template over prefixes", "confirm:4:ban", componentRouting{route: "confirm:{userID}:{action}",
+			vars: map[string]string{"userID": "4", "action": "ban"}}},
+		{"most literal template", "confirm:4:delete", componentRouting{route: "confirm:{userID}:delete",
+			vars: map[string]string{"userID": "4"}}},
+		{"escaped template value", escaped, componentRouting{route: "confirm:{userID}:{action}",
+			vars: map[string]string{"userID": "a:b", "action": "ban"}}},
+		{"prefix when the segments differ", "confirm:4", componentRouting{route: "confirm:*", param: "4"}},
+		{"catch-all", "vote:up", componentRouting{route: "*", param: "vote:up"}},
+	}
+
+	reversed := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		reversed[len(patterns)-1-i] = pattern
+	}
+
+	// The precedence does not depend on the registration order
+	orders := []struct {
+		name     string
+		patterns []string
+	}{
+		{"registered in order", patterns},
+		{"registered in reverse", reversed},
+	}
+
+	for _, order := range orders {
+		for _, test := range tests {
+			t.Run(order.name+"/"+test.name, func(t *testing.T) {
+				if got := routeComponent(t, order.patterns, true, test.customID); !reflect.DeepEqual(got, test.want) {
+					t.Errorf("%q routed to %+v, want %+v", test.customID, got, test.want)
+				}
+			})
+		}
+	}
+}
+
+func TestComponentFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		customID string
+		want     string
+	}{
+		{"fallback without a match", []string{"page:*"}, "vote:up", "fallback"},
+		{"catch-all over the fallback", []string{"page:*", "*"}, "vote:up", "*"},
+		{"route over the fallback", []string{"page:*"}, "page:2", "page:*"},
+		{"template not matching", []string{"confirm:{userID}:{action}"}, "confirm:4", "fallback"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := routeComponent(t, test.patterns, true, test.customID); got.route != test.want {
+				t.Errorf("%q routed to %q, want %q", test.customID, got.route, test.want)
+			}
+		})
+	}
+}
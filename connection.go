@@ -1,12 +1,19 @@
 package httpcord
 
 import (
+	"context"
 	"crypto/ed25519"
 	"encoding/hex"
-	"encoding/json"
-	"io/ioutil"
-	"mime/multipart"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
@@ -19,10 +26,62 @@ const (
 	FastHttpConnection
 )
 
+// DefaultMaxTimestampSkew Age of the signed requests accepted unless ConnectionOptions.MaxTimestampSkew is set
+const DefaultMaxTimestampSkew = 5 * time.Minute
+
+// DefaultMaxBodySize Largest request body read unless ConnectionOptions.MaxBodySize is set, far above the largest
+// interactions of Discord
+const DefaultMaxBodySize = 1 << 20
+
+// ErrBodyTooLarge The request body is larger than ConnectionOptions.MaxBodySize, declared or while reading
+var ErrBodyTooLarge = errors.New("httpcord: request body too large")
+
 type ConnectionContext struct {
-	SendRes     func(res *InteractionResponse) bool
+	// SendRes writes the initial response, only the first call is sent and the others return ErrAlreadyResponded
+	SendRes     func(res *InteractionResponse) error
 	Interaction Interaction
 	clientToken string
+	options     *ConnectionOptions
+	client      *RestClient
+	// webhooks sends the calls of the interaction token, the client outside unit tests
+	webhooks  InteractionWebhooks
+	state     *interactionState
+	requestID string
+	rawBody   []byte
+	life      *lifecycle
+	context   context.Context
+	// routed the handler was matched by the command router, options are scoped to the invoked subcommand
+	routed bool
+	// componentParam is the custom_id part matched by the component route wildcard
+	componentParam string
+	// componentVars are the values of the component route placeholders
+	componentVars map[string]string
+	// objects are the pooled request objects held by the RunDeferred work, nil without ConnectionOptions.PoolObjects
+	objects *requestObjects
+}
+
+// interactionState Per interaction state shared by the copies of a ConnectionContext
+type interactionState struct {
+	mu    sync.Mutex
+	after []func()
+	// afterTaken the handlers returned and after was taken, later functions run right away
+	afterTaken bool
+	deferredAt time.Time
+	responded  bool
+	// clientGone the inbound request was aborted, nothing else can be written to it
+	clientGone bool
+	// autoDeferred the response was deferred after HandlerTimeout or AutoDefer, later replies edit it
+	autoDeferred bool
+	// restCallback the initial response was sent through the REST callback endpoint, the request is answered with a 202
+	restCallback bool
+	// primaryResponse is the initial response, compared with the ShadowDispatcher one
+	primaryResponse *InteractionResponse
+	progress        *ProgressTicker
+	// editMu serializes the edits of the original response
+	editMu  sync.Mutex
+	options lazyOptionIndex
+	// followUps created through the context, see DeleteAllFollowUps
+	followUps []trackedFollowUp
 }
 
 type ConnectionOptions struct {
@@ -32,19 +91,320 @@ type ConnectionOptions struct {
 	PublicKey string
 	// Discord token (Necessary for external requests)
 	Token string
+	// TokenProvider Resolve the token per application for REST calls (Defaults to StaticToken(Token), with the tokens of
+	// Applications first)
+	TokenProvider TokenProvider
+	// Logger Receive the library logs (Defaults to DefaultLogger)
+	Logger Logger
+	// Retention Interaction data kept after parsing, dropping categories reduces memory per request (Defaults to DefaultRetention)
+	Retention RetentionFlag
+	// FeatureGate Decide where the commands with a feature run, see CommandRoute.Feature (Defaults to AllowAllFeatures)
+	FeatureGate FeatureGate
+	// Metrics Receive the library metrics (Defaults to NopMetrics). The interaction counters are labeled by command and
+	// guild, see NewMetricsGuard to bound their cardinality. A HistogramCollector also receives
+	// httpcord_handler_duration_seconds
+	Metrics MetricsCollector
+	// Tracer Start the spans of the signature verification, the dispatch and the REST calls (Defaults to NopTracer).
+	// The handlers get the dispatch span through ConnectionContext.Context
+	Tracer Tracer
+	// PremiumBypass User or guild IDs never upsold, useful for testers (See ConnectionContext.ShouldUpsell)
+	PremiumBypass []Snowflake
+	// AllowedMentions Mentions pinged by the responses, edits and follow-ups of the handlers without their own
+	// AllowedMentions, like SuppressAll so echoed user input never pings @everyone (Defaults to nil, Discord parses
+	// every mention)
+	AllowedMentions *AllowedMentions
+	// EditAfterDefer Send the replies following DeferReplyInteraction or DeferUpdateInteraction as edits of the original
+	// response instead of failing with ErrAlreadyResponded
+	EditAfterDefer bool
+	// NormalizeStrings Cleanup of the string options of every command without its own CommandRoute.NormalizeStrings
+	// (Disabled when nil)
+	NormalizeStrings *StringNormalization
+	// ErrorReply Build the reply sent when an error happens before the handler runs or when it panics (Defaults to DefaultErrorReply)
+	ErrorReply ErrorReplyFunc
+	// ErrorTraceTemplate Footer of DefaultErrorReply embeds, {trace} is replaced by the interaction trace code (Disabled when empty)
+	ErrorTraceTemplate string
+	// TraceCodeRenderer Format the trace code shown to users from the request ID (Defaults to ShortTraceCode)
+	TraceCodeRenderer func(requestID string) string
+	// OnError Called for every error raised while dispatching an interaction, ctx.TraceCode() matches the code shown to the user
+	OnError func(ctx ConnectionContext, err error)
+	// ErrorReport Attach a report button to the ErrorReply messages forwarding the error context (Disabled when nil)
+	ErrorReport *ErrorReportOptions
+	// StateStore Store of the component and modal state (Defaults to NewMemoryStateStore())
+	StateStore ComponentStateStore
+	// StateKeys Encrypt the StateStore values with AES-GCM under the keys, see NewSealedStateStore to compress them too
+	// (Disabled when nil)
+	StateKeys KeyProvider
+	// ValidateResponses Check the files, custom emoji and polls of the responses against the application permissions
+	// before sending them, failing with ErrMissingAppPermission.
+	// Dead ends like ephemeral components without a handler are logged as warnings (See Connection.LintResponse)
+	ValidateResponses bool
+	// MaxWorkers Goroutines running the RunDeferred work, the rest is queued (Unbounded when 0)
+	MaxWorkers int
+	// MaxQueueDepth RunDeferred work queued while the workers are busy, then it fails with ErrBackpressure (Unbounded when 0)
+	MaxQueueDepth int
+	// DispatchWorkers Interactions dispatched at once, the others wait for a worker (Unbounded when 0)
+	DispatchWorkers int
+	// DispatchQueueDepth Interactions waiting for a dispatch worker, then they are refused with a DispatchBusyError
+	// answered by ErrorReply (Unbounded when 0)
+	DispatchQueueDepth int
+	// DispatchQueueTimeout Time an interaction waits for a dispatch worker before it is refused like a full queue
+	// (Defaults to DefaultDispatchQueueTimeout)
+	DispatchQueueTimeout time.Duration
+	// MaxHandlerDuration Maximum run time of the work started with RunDeferred, the interaction token lifetime always caps it (Unlimited when zero)
+	MaxHandlerDuration time.Duration
+	// DeferEditRetryWindow After a defer, edits of the original response failing with Unknown Webhook/Interaction are retried during this window (Defaults to 3 seconds, negative disables)
+	DeferEditRetryWindow time.Duration
+	// FallbackProxy Endpoint receiving the interactions no route or interaction handler answered, its response is relayed to Discord (Disabled when nil)
+	FallbackProxy *url.URL
+	// Sharding Dispatch every interaction on the replica owning it, the others forward it (Disabled when nil)
+	Sharding *ShardingOptions
+	// FallbackProxyTimeout Time the FallbackProxy has to answer before ErrorReply is sent (Defaults to DefaultFallbackProxyTimeout)
+	FallbackProxyTimeout time.Duration
+	// DebugDumpDir Directory where every interaction request/response pair is written as "0001-request.json" and
+	// "0001-response.json", "0001-response-multipart.json" for the responses with files. Tokens are redacted (Disabled when empty)
+	DebugDumpDir string
+	// DebugDumpMaxFiles Maximum dump files kept in DebugDumpDir, the oldest are removed first (Defaults to DefaultDebugDumpMaxFiles)
+	DebugDumpMaxFiles int
+	// MaxTimestampSkew Reject with a 401 the requests signed further than this from now, against replayed requests
+	// (Defaults to DefaultMaxTimestampSkew, negative disables it for canned payloads)
+	MaxTimestampSkew time.Duration
+	// MaxBodySize Reject with a 413 the requests with a larger body, before reading more than this
+	// (Defaults to DefaultMaxBodySize, negative disables it)
+	MaxBodySize int64
+	// TimestampTolerance Used as MaxTimestampSkew when it is not set
+	//
+	// Deprecated: use MaxTimestampSkew
+	TimestampTolerance time.Duration
+	// MultipartBoundary Boundary of the multipart bodies with files, for proxies filtering boundaries (Random when nil)
+	MultipartBoundary BoundaryFunc
+	// RequestPredicates Checks run in order before reading the body and verifying the signature, the first error refuses the request with a 403.
+	// FastHttpConnection requests are checked after their conversion to net/http (See RequiredHeader and ForbiddenHeader)
+	RequestPredicates []func(r *http.Request) error
+	// OnVerificationFailure Called with the request refused by a RequestPredicates error, ErrMissingSignature,
+	// ErrStaleTimestamp or ErrInvalidSignature
+	OnVerificationFailure func(r *http.Request, err error)
+	// ApplicationID Refuse with a 401 the interactions of other applications, see ApplicationMismatchError (Disabled when empty)
+	ApplicationID Snowflake
+	// ApplicationIDs More applications accepted along ApplicationID, for connections serving several applications
+	ApplicationIDs []Snowflake
+	// Applications Applications served by the connection with their own public key, picked by the application_id of
+	// the payload, and accepted like ApplicationIDs. Their tokens resolve the REST calls unless TokenProvider is set,
+	// see Connection.ForApplication for their routes (PublicKey is only required for the other applications)
+	Applications []ApplicationCredentials
+	// OnApplicationMismatch Called when an interaction of another application is refused
+	OnApplicationMismatch func(err *ApplicationMismatchError)
+	// Translator Resolve the keys of LocalizedCallbackData replies (Keys are sent as they are when nil)
+	Translator *Translator
+	// LocaleChain Locales tried in order by ConnectionContext.T and the LocalizedCallbackData replies before
+	// Translator.Fallback (Defaults to the user locale then the guild locale)
+	LocaleChain func(ctx *ConnectionContext) []Locale
+	// RequestErrorHandler Called with the requests failing before dispatch and the responses that could not be written,
+	// the error status is already sent (See MalformedInteractionError and ErrResponseEncoding). RequestInteraction
+	// returns the interaction of the request when it was decoded
+	RequestErrorHandler func(err error, r *http.Request)
+	// ErrorHandler Called like RequestErrorHandler, after it when both are set. The status is already sent, w can only
+	// add to the body
+	//
+	// Deprecated: use RequestErrorHandler
+	ErrorHandler func(err error, w http.ResponseWriter, r *http.Request)
+	// HandlerTimeout Cancel the context of the handlers after the duration (Disabled when zero, Discord waits 3 seconds
+	// for the response). The response is deferred at the same time when AutoDefer is zero or longer, the handlers can
+	// not respond once cancelled
+	HandlerTimeout time.Duration
+	// AutoDefer Defer the response when the handlers did not respond after the delay and keep them running with their
+	// context, later replies edit the deferred response (Defaults to HandlerTimeout, disabled when both are zero).
+	// Set it shorter than HandlerTimeout to defer before cancelling the handlers
+	AutoDefer time.Duration
+	// AutoDeferEphemeral Send the defer of AutoDefer or HandlerTimeout as ephemeral, the edits replacing it stay
+	// ephemeral. Component updates are not affected
+	AutoDeferEphemeral bool
+	// ShadowDispatcher Receive a copy of every interaction after the handlers returned, its initial response is compared
+	// with the one sent and never sent itself. Used to validate a refactor against real traffic (Disabled when nil)
+	ShadowDispatcher ShadowDispatcher
+	// ShadowIgnore Paths of the responses left out of the shadow comparison like "data.embeds.*.timestamp" (See DiffJSON)
+	ShadowIgnore []string
+	// OnShadowMismatch Called when the shadow response differs or the shadow failed (Defaults to a warning log)
+	OnShadowMismatch func(ctx ConnectionContext, mismatch *ShadowMismatch)
+	// PoolObjects Reuse the request body, its decode target and the response buffers across requests, they are
+	// returned once the handlers, the dump and the shadow dispatch are done. RawBody returns a copy of the body then.
+	// Handlers keeping the body or DecodeJSON results of the RawBody past dispatch must copy them
+	PoolObjects bool
+	// PoolPoison Overwrite the pooled objects with poison when released instead of reusing them, a use after release
+	// reads 0xDB bytes or PoisonedToken, in the members, messages and options of the Interaction too. For tests along
+	// PoolObjects, pooling is ineffective with it
+	PoolPoison bool
+	// Codec Decode the interactions and encode the responses and the REST payloads, the REST client of the connection
+	// uses it too (Defaults to StdCodec)
+	Codec Codec
+	// ScheduleStore Store of the follow-ups scheduled with ScheduleFollowUp, loaded and resumed by NewConnection
+	// (Defaults to NewMemoryScheduleStore(), see FileScheduleStore to survive restarts)
+	ScheduleStore ScheduleStore
+	// Clock Time source of the scheduled follow-ups and the progress tickers (Defaults to SystemClock)
+	Clock Clock
+	// OnScheduleError Called when a scheduled follow-up fails or its token expired before it could fire (Logged when nil)
+	OnScheduleError func(entry ScheduledFollowUp, err error)
+	// ReadinessPath Path answering 200 while the connection takes traffic and 503 once it drains, for the readiness
+	// probes of load balancers and orchestrators (Disabled when empty, see Connection.StartDrain)
+	ReadinessPath string
+	// DrainDelay Time RunUntilSignal keeps serving with the readiness failing before Shutdown, while the load balancers
+	// stop routing traffic (Defaults to DefaultDrainDelay, negative disables)
+	DrainDelay time.Duration
+	// ShutdownTimeout Deadline of the Shutdown called by RunUntilSignal (Defaults to DefaultShutdownTimeout)
+	ShutdownTimeout time.Duration
+	// DisablePanicRecovery Let the panics of the handlers unwind the request instead of logging them with their stack
+	// and answering with ErrorReply, for the recovery middlewares of the host server
+	DisablePanicRecovery bool
 }
 
 type Connection struct {
 	FastHandler    fasthttp.RequestHandler
 	DefaultHandler http.HandlerFunc
+	// Client REST client of the connection, interactions use a copy bound to their application
+	Client   *RestClient
+	router   *commandRouter
+	handlers *interactionHandlers
+	events   *webhookEventHandlers
+	life     *lifecycle
+	handler  http.HandlerFunc
+	verifier requestVerifier
+	// applications routers of ForApplication, the interactions of the others are routed by router
+	applications *applicationRouters
+	// applicationID binds the command registration to ConnectionOptions.ApplicationID
+	applicationID Snowflake
+	// expected applications of ConnectionOptions, the ping printed by ConnectDev claims the first one
+	expected []Snowflake
+	logger   Logger
+	// proxied is set with a FallbackProxy, it may answer any component
+	proxied bool
+	// verifying builds the handler checking the signatures with another verifier
+	verifying func(verifier requestVerifier) http.HandlerFunc
 }
 
+// InteractionHandlers Handlers run for the interactions of every connection of the process
+//
+// Deprecated: use Connection.AddInteractionHandler, its handlers only run for the interactions of the connection
 var InteractionHandlers = make([]func(ctx ConnectionContext), 0, 10)
 
+// interactionHandlers Handlers added with AddInteractionHandler, shared by the copies of the connection
+type interactionHandlers struct {
+	mu   sync.RWMutex
+	list []func(ctx ConnectionContext)
+	// middlewares wrap the dispatch of every interaction, see Connection.Use
+	middlewares []Middleware
+}
+
+func (h *interactionHandlers) add(handler func(ctx ConnectionContext)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.list = append(h.list, handler)
+}
+
+func (h *interactionHandlers) all() []func(ctx ConnectionContext) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.list
+}
+
+func (h *interactionHandlers) use(middlewares ...Middleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.middlewares = append(append([]Middleware(nil), h.middlewares...), middlewares...)
+}
+
+// wrap Handler run through the middlewares, the first one added is the outermost
+func (h *interactionHandlers) wrap(handler Handler) Handler {
+	h.mu.RLock()
+	middlewares := h.middlewares
+	h.mu.RUnlock()
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
 func parsePublicKey(key string) (ed25519.PublicKey, error) {
 	return hex.DecodeString(key)
 }
 
+// requestVerifier Signature checks of the inbound requests
+type requestVerifier struct {
+	keys []ed25519.PublicKey
+	// applications keys of ConnectionOptions.Applications, preferred over keys for the payloads of their application
+	applications map[Snowflake]ed25519.PublicKey
+	// tolerance rejects timestamps further from now (Disabled when not positive)
+	tolerance time.Duration
+	// devKeys keys of ConnectDev, accepted for the payloads of every application
+	devKeys []ed25519.PublicKey
+	// insecure skips the checks, only enabled by ConnectDev
+	insecure bool
+	logger   Logger
+}
+
+// verify Returns ErrMissingSignature, ErrStaleTimestamp or ErrInvalidSignature when the request is refused
+func (v requestVerifier) verify(r *http.Request, body []byte) error {
+	if v.insecure {
+		v.logger.Warn("SIGNATURE VERIFICATION DISABLED, accepting an unverified request", "remote", r.RemoteAddr, "path", r.URL.Path)
+		return nil
+	}
+
+	timestamp := v.header(r, "X-Signature-Timestamp")
+	signature := v.header(r, "X-Signature-Ed25519")
+
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	if v.tolerance > 0 {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrStaleTimestamp
+		}
+
+		if skew := time.Since(time.Unix(seconds, 0)); skew > v.tolerance || skew < -v.tolerance {
+			return ErrStaleTimestamp
+		}
+	}
+
+	signed := append([]byte(timestamp), body...)
+
+	keys := v.keys
+	if len(v.applications) > 0 {
+		if key, ok := v.applications[payloadApplication(body)]; ok {
+			keys = []ed25519.PublicKey{key}
+		}
+	}
+
+	keys = append(keys[:len(keys):len(keys)], v.devKeys...)
+
+	for _, key := range keys {
+		if verifyKey(signed, signature, key) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// header First value of the signature header, FastHttpConnection requests are converted to net/http so names are
+// matched case-insensitively on both transports. Repeated headers are logged
+func (v requestVerifier) header(r *http.Request, name string) string {
+	values := r.Header.Values(name)
+	if len(values) > 1 {
+		v.logger.Warn("repeated signature header, using the first value", "header", name, "count", len(values), "remote", r.RemoteAddr)
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
 func verifyKey(body []byte, signature string, publicKey ed25519.PublicKey) bool {
 	sig, err := hex.DecodeString(signature)
 	if err != nil {
@@ -54,155 +414,920 @@ func verifyKey(body []byte, signature string, publicKey ed25519.PublicKey) bool
 	return ed25519.Verify(publicKey, body, sig)
 }
 
-func NewConnection(options ConnectionOptions) Connection {
+// NewConnection Fails when the public key is not valid hex or the DebugDumpDir can not be created
+func NewConnection(options ConnectionOptions) (*Connection, error) {
 	publicKey, err := parsePublicKey(options.PublicKey)
 
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("httpcord: invalid public key: %w", err)
 	}
 
-	handler := httpHandler(publicKey, options.Token)
-	if options.HttpConnection == DefaultHttpConnection {
-		return Connection{
-			DefaultHandler: handler,
+	keys, err := applicationKeys(options.Applications)
+	if err != nil {
+		return nil, err
+	}
+
+	var dumper *debugDumper
+
+	if options.DebugDumpDir != "" {
+		dumper, err = newDebugDumper(options.DebugDumpDir, options.DebugDumpMaxFiles)
+
+		if err != nil {
+			return nil, err
 		}
-	} else if options.HttpConnection == FastHttpConnection {
-		return Connection{
-			FastHandler: fasthttpadaptor.NewFastHTTPHandler(handler),
+	}
+
+	if options.Sharding != nil {
+		if err := options.Sharding.validate(); err != nil {
+			return nil, err
 		}
 	}
 
-	return Connection{
+	options.setDefaults()
+
+	client := NewRestClient(options.TokenProvider)
+	client.MultipartBoundary = options.MultipartBoundary
+	client.Logger = options.Logger
+	client.Metrics = options.Metrics
+	client.Tracer = options.Tracer
+	client.Codec = options.Codec
+	life := &lifecycle{
+		pool:            newWorkerPool(options.MaxWorkers, options.MaxQueueDepth, options.Metrics),
+		dispatch:        newDispatchPool(options.DispatchWorkers, options.DispatchQueueDepth, options.DispatchQueueTimeout),
+		drainDelay:      options.DrainDelay,
+		shutdownTimeout: options.ShutdownTimeout,
+	}
+	life.scheduler = newScheduler(&options, client, life)
+	verifier := requestVerifier{applications: keys, tolerance: options.MaxTimestampSkew, logger: options.Logger}
+	if options.PublicKey != "" || len(keys) == 0 {
+		verifier.keys = []ed25519.PublicKey{publicKey}
+	}
+
+	handlers := &interactionHandlers{}
+
+	// Built-in components are answered by the routers of every application
+	applications := &applicationRouters{build: func() *commandRouter {
+		router := newCommandRouter()
+		if options.ErrorReport != nil {
+			router.addComponent(reportComponentPrefix, handleReport)
+		}
+
+		router.addComponent(publishComponentPrefix, handlePublish)
+		return router
+	}}
+
+	router := applications.build()
+	handler := httpHandler(verifier, options, dumper, router, applications, handlers, client, life)
+
+	if err := life.scheduler.resume(); err != nil {
+		return nil, fmt.Errorf("httpcord: could not resume the scheduled follow-ups: %w", err)
+	}
+
+	// Requests checked by another verifier are replays or development requests, they are never dumped
+	verifying := func(verifier requestVerifier) http.HandlerFunc {
+		return httpHandler(verifier, options, nil, router, applications, handlers, client, life)
+	}
+
+	if options.HttpConnection == FastHttpConnection {
+		return &Connection{
+			FastHandler:   fastHTTPHandler(handler),
+			Client:        client,
+			router:        router,
+			handlers:      handlers,
+			applications:  applications,
+			events:        &webhookEventHandlers{},
+			life:          life,
+			handler:       handler,
+			verifier:      verifier,
+			verifying:     verifying,
+			applicationID: options.ApplicationID,
+			expected:      options.expectedApplications(),
+			logger:        options.Logger,
+			proxied:       options.FallbackProxy != nil,
+		}, nil
+	}
+
+	return &Connection{
 		DefaultHandler: handler,
+		Client:         client,
+		router:         router,
+		handlers:       handlers,
+		applications:   applications,
+		events:         &webhookEventHandlers{},
+		life:           life,
+		handler:        handler,
+		verifier:       verifier,
+		verifying:      verifying,
+		applicationID:  options.ApplicationID,
+		expected:       options.expectedApplications(),
+		logger:         options.Logger,
+		proxied:        options.FallbackProxy != nil,
+	}, nil
+}
+
+// setDefaults Fill the options left unset with their defaults
+func (o *ConnectionOptions) setDefaults() {
+	if o.ErrorReply == nil {
+		o.ErrorReply = DefaultErrorReply
+	}
+
+	if o.Logger == nil {
+		o.Logger = DefaultLogger
+	}
+
+	if o.FeatureGate == nil {
+		o.FeatureGate = AllowAllFeatures
+	}
+
+	if o.Metrics == nil {
+		o.Metrics = NopMetrics
+	}
+
+	if o.Tracer == nil {
+		o.Tracer = NopTracer
+	}
+
+	if o.Codec == nil {
+		o.Codec = StdCodec
+	}
+
+	deprecations.setLogger(o.Logger)
+
+	if o.Retention == 0 {
+		o.Retention = DefaultRetention
+	}
+
+	if o.TraceCodeRenderer == nil {
+		o.TraceCodeRenderer = ShortTraceCode
+	}
+
+	if o.DeferEditRetryWindow == 0 {
+		o.DeferEditRetryWindow = 3 * time.Second
+	}
+
+	if o.ErrorHandler != nil {
+		deprecated("ConnectionOptions.ErrorHandler", "ConnectionOptions.RequestErrorHandler", 0)
+	}
+
+	if o.MaxTimestampSkew == 0 && o.TimestampTolerance > 0 {
+		deprecated("ConnectionOptions.TimestampTolerance", "ConnectionOptions.MaxTimestampSkew", 0)
+		o.MaxTimestampSkew = o.TimestampTolerance
+	}
+
+	if o.MaxTimestampSkew == 0 {
+		o.MaxTimestampSkew = DefaultMaxTimestampSkew
+	}
+
+	if o.DispatchQueueTimeout == 0 {
+		o.DispatchQueueTimeout = DefaultDispatchQueueTimeout
+	}
+
+	if o.MaxBodySize == 0 {
+		o.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if o.DrainDelay == 0 {
+		o.DrainDelay = DefaultDrainDelay
+	}
+
+	if o.ShutdownTimeout == 0 {
+		o.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	if o.Sharding != nil {
+		sharding := *o.Sharding
+		sharding.setDefaults()
+		o.Sharding = &sharding
+	}
+
+	if o.FallbackProxyTimeout == 0 {
+		o.FallbackProxyTimeout = DefaultFallbackProxyTimeout
+	}
+
+	if o.StateStore == nil {
+		o.StateStore = NewMemoryStateStore()
+	}
+
+	if o.StateKeys != nil {
+		o.StateStore = NewSealedStateStore(o.StateStore, SealKeys(o.StateKeys))
+	}
+
+	if o.ErrorReport != nil && o.ErrorReport.TTL == 0 {
+		report := *o.ErrorReport
+		report.TTL = DefaultErrorReportTTL
+		o.ErrorReport = &report
+	}
+
+	if o.TokenProvider == nil && len(o.Applications) > 0 {
+		o.TokenProvider = applicationTokens(o.Applications, o.Token)
+	}
+
+	if o.TokenProvider == nil {
+		o.TokenProvider = StaticToken(o.Token)
 	}
 
+	if o.ScheduleStore == nil {
+		o.ScheduleStore = NewMemoryScheduleStore()
+	}
+
+	if o.Clock == nil {
+		o.Clock = SystemClock
+	}
+
+	if o.ShadowDispatcher != nil && o.OnShadowMismatch == nil {
+		logger := o.Logger
+		o.OnShadowMismatch = func(ctx ConnectionContext, mismatch *ShadowMismatch) {
+			logger.Warn("shadow response mismatch", "request", ctx.RequestID(), "differences", len(mismatch.Differences), "error", mismatch.Err)
+		}
+	}
 }
 
-func (c Connection) Connect(address string) error {
-	if c.FastHandler != nil {
-		return fasthttp.ListenAndServe(address, c.FastHandler)
+// autoDeferDelay Delay of the automatic defer, AutoDefer capped to HandlerTimeout. Defers at the HandlerTimeout are
+// logged as warnings, the handlers ran out of time
+func (o *ConnectionOptions) autoDeferDelay() (time.Duration, func(msg string, fields ...interface{})) {
+	if o.HandlerTimeout > 0 && (o.AutoDefer <= 0 || o.HandlerTimeout < o.AutoDefer) {
+		return o.HandlerTimeout, o.Logger.Warn
 	}
 
-	return http.ListenAndServe(address, c.DefaultHandler)
+	return o.AutoDefer, o.Logger.Debug
 }
 
-func httpHandler(publicKey ed25519.PublicKey, token string) http.HandlerFunc {
-	var res InteractionResponse
+// WithPublicKey Copy of the connection verifying the requests with another hex encoded public key,
+// sharing the handlers, client and lifecycle. Used to replay dumped requests signed with a local key
+func (c *Connection) WithPublicKey(publicKey string) (*Connection, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := c.verifier
+	verifier.keys, verifier.applications = []ed25519.PublicKey{key}, nil
+
+	return c.withVerifier(verifier), nil
+}
 
+// withVerifier Copy of the connection checking the requests with verifier
+func (c *Connection) withVerifier(verifier requestVerifier) *Connection {
+	copied := *c
+	copied.verifier = verifier
+	copied.handler = c.verifying(verifier)
+
+	if copied.FastHandler != nil {
+		copied.FastHandler = fastHTTPHandler(copied.handler)
+	} else {
+		copied.DefaultHandler = copied.handler
+	}
+
+	return &copied
+}
+
+// requestFailed Answer with the status and report the error to the error handlers, status 0 leaves the response as it is
+func (o *ConnectionOptions) requestFailed(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if status != 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+	}
+
+	if o.RequestErrorHandler != nil {
+		o.RequestErrorHandler(err, r)
+	}
+
+	if o.ErrorHandler != nil {
+		o.ErrorHandler(err, w, r)
+	}
+}
+
+type requestInteractionKey struct{}
+
+// withInteraction Request carrying the decoded interaction for RequestInteraction
+func withInteraction(r *http.Request, interaction *Interaction) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestInteractionKey{}, interaction))
+}
+
+// RequestInteraction Interaction of the request passed to ConnectionOptions.RequestErrorHandler, false when the request
+// failed before the interaction was decoded
+func RequestInteraction(r *http.Request) (*Interaction, bool) {
+	interaction, ok := r.Context().Value(requestInteractionKey{}).(*Interaction)
+	return interaction, ok
+}
+
+// signatureHeaders Headers checked by requestVerifier
+var signatureHeaders = []string{"X-Signature-Ed25519", "X-Signature-Timestamp"}
+
+// fastHTTPHandler Run handler on the net/http conversion of the fasthttp requests. The conversion keeps the last
+// value of repeated headers, the signature headers get all their values back in order like net/http
+func fastHTTPHandler(handler http.HandlerFunc) fasthttp.RequestHandler {
+	return fasthttpadaptor.NewFastHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ctx, ok := r.Context().(*fasthttp.RequestCtx); ok {
+			for _, name := range signatureHeaders {
+				var values []string
+
+				ctx.Request.Header.VisitAll(func(key, value []byte) {
+					if strings.EqualFold(string(key), name) {
+						values = append(values, string(value))
+					}
+				})
+
+				if len(values) > 0 {
+					r.Header[name] = values
+				}
+			}
+		}
+
+		handler(w, r)
+	}))
+}
+
+func httpHandler(verifier requestVerifier, options ConnectionOptions, dumper *debugDumper, fallback *commandRouter, applications *applicationRouters, handlers *interactionHandlers, client *RestClient, life *lifecycle) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		je := json.NewEncoder(w)
-		signature := r.Header.Get("X-Signature-Ed25519")
-		timestamp := r.Header.Get("X-Signature-Timestamp")
+		if options.ReadinessPath != "" && r.URL.Path == options.ReadinessPath {
+			life.readiness(w)
+			return
+		}
 
-		bodyBytes, err := ioutil.ReadAll(r.Body)
+		if !life.begin() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		defer life.end()
+
+		if err := options.checkRequest(r); err != nil {
+			options.verificationFailed(r, err)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body := io.Reader(r.Body)
+		if options.MaxBodySize > 0 {
+			if r.ContentLength > options.MaxBodySize {
+				options.requestFailed(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, r.ContentLength))
+				return
+			}
+
+			// One more byte than allowed tells a body of the exact limit from a larger one
+			body = io.LimitReader(r.Body, options.MaxBodySize+1)
+		}
+
+		// Released after the dump, the handlers and the background continuations
+		objects := acquireRequestObjects(options.PoolObjects, options.PoolPoison)
+		defer objects.release()
+
+		bodyBytes, err := objects.readBody(body)
 
 		if err != nil {
-			panic(err)
+			options.requestFailed(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if options.MaxBodySize > 0 && int64(len(bodyBytes)) > options.MaxBodySize {
+			options.requestFailed(w, r, http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+			return
 		}
 
-		body := append([]byte(timestamp), bodyBytes...)
+		_, verifySpan := options.Tracer.Start(r.Context(), VerifySpan, map[string]string{"http.path": r.URL.Path})
+		err = verifier.verify(r, bodyBytes)
+		endSpan(verifySpan, err)
 
-		if !verifyKey(body, signature, publicKey) {
+		if err != nil {
+			options.verificationFailed(r, err)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		var rawInteraction APIInteraction
-		err = json.Unmarshal(bodyBytes, &rawInteraction)
+		// Only verified requests are dumped, unsigned traffic never rotates out the real interactions
+		if dumper != nil {
+			dw := &dumpResponseWriter{ResponseWriter: w}
+			w = dw
+			defer dumper.Dump(r, bodyBytes, dw)
+		}
+
+		if err := checkInteractionBody(bodyBytes); err != nil {
+			options.malformedRequest(w, r, err)
+			return
+		}
+
+		rawInteraction := objects.decodeTarget()
+		err = options.Codec.Unmarshal(bodyBytes, rawInteraction)
+
+		if err != nil {
+			options.malformedRequest(w, r, &MalformedInteractionError{Err: err})
+			return
+		}
+
+		if err := options.checkApplication(Snowflake(rawInteraction.ApplicationID)); err != nil {
+			mismatch := err.(*ApplicationMismatchError)
+			options.Logger.Error("interaction refused", "application", mismatch.Got, "expected", mismatch.Expected)
 
+			if options.OnApplicationMismatch != nil {
+				options.OnApplicationMismatch(mismatch)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		interaction, err := resolveInteraction(options.Codec, rawInteraction)
 		if err != nil {
-			panic("Error on get interaction: " + err.Error())
+			options.malformedRequest(w, r, err)
+			return
 		}
 
-		interaction := ResolveInteraction(&rawInteraction)
+		client.Cache.observe(&interaction)
+		router := applications.pick(interaction.ApplicationID, fallback)
+		options.Retention.retain(&interaction)
+		objects.track(interaction)
 
 		if interaction.Type == PingInteraction {
 			w.Header().Set("Content-Type", "application/json")
-			err := je.Encode(InteractionResponse{
+			err := encodeTo(options.Codec, w, InteractionResponse{
 				Type: PongResponse,
 			})
 
 			if err != nil {
-				panic("Error writing response")
+				options.requestFailed(w, withInteraction(r, &interaction), 0, fmt.Errorf("%w: %v", ErrResponseEncoding, err))
 			}
 			return
 		}
 
-		if (res.Type == ChannelMessageWithSourceResponse || res.Type == UpdateMessageResponse) && len(res.Data.Files) > 0 {
-			m := multipart.NewWriter(w)
-			w.Header().Set("Content-Type", m.FormDataContentType())
+		if sharding := options.Sharding; sharding != nil {
+			if owner := sharding.owner(r, &interaction); owner != sharding.Self {
+				err := sharding.forward(w, r, bodyBytes, owner)
+				if err == nil {
+					return
+				}
+
+				options.Logger.Warn("could not forward the interaction to its replica, dispatching it here", "replica", owner, "error", err.Error())
+			}
+		}
+
+		labels := interactionLabels(&interaction)
+		options.Metrics.IncCounter("httpcord_interactions_total", labels)
+
+		spanCtx, dispatchSpan := options.Tracer.Start(r.Context(), DispatchSpan, map[string]string{
+			"interaction.id":   interaction.ID.String(),
+			"interaction.kind": labels["kind"],
+			"command":          labels["command"],
+		})
+		defer dispatchSpan.End()
+
+		var handlerCtx context.Context
+		var cancel context.CancelFunc
+
+		if options.HandlerTimeout > 0 {
+			handlerCtx, cancel = context.WithTimeout(spanCtx, options.HandlerTimeout)
+		} else {
+			handlerCtx, cancel = context.WithCancel(spanCtx)
+		}
+		defer cancel()
+
+		bound := client.WithApplication(interaction.ApplicationID)
+		ctx := ConnectionContext{
+			Interaction: interaction,
+			clientToken: options.Token,
+			options:     &options,
+			client:      bound,
+			webhooks:    bound,
+			state:       &interactionState{},
+			requestID:   newRequestID(),
+			life:        life,
+			context:     handlerCtx,
+			objects:     objects,
+		}
+
+		if options.Retention.Has(KeepRawBody) {
+			ctx.rawBody = bodyBytes
+		}
+
+		write := func(response *InteractionResponse) error {
+			// Nothing is written once the request is aborted, Discord no longer waits for it
+			if err := r.Context().Err(); err != nil {
+				ctx.clientGone(err)
+				return fmt.Errorf("%w: %v", ErrClientGone, err)
+			}
 
-			for id, file := range res.Data.Files {
-				attach, err := file.MakeAttach(Snowflake(rune(id+1)), m)
+			// Messages with files are sent as multipart with the payload in payload_json
+			var (
+				body        []byte
+				contentType string
+				err         error
+			)
 
-				if err != nil {
-					panic("Error creating attachment: " + err.Error())
+			if options.PoolObjects {
+				var done func()
+				body, contentType, done, err = encodePooledResponse(options.Codec, response, options.MultipartBoundary, options.PoolPoison)
+				if err == nil {
+					defer done()
 				}
+			} else {
+				body, contentType, err = encodeInteractionResponse(options.Codec, response, options.MultipartBoundary)
+			}
 
-				res.Data.Attachments = append(res.Data.Attachments, attach)
+			if err != nil {
+				err = fmt.Errorf("%w: %v", ErrResponseEncoding, err)
+				options.requestFailed(w, withInteraction(r, &ctx.Interaction), http.StatusInternalServerError, err)
+				return err
 			}
 
-			if field, err := m.CreateFormField("payload_json"); err != nil {
-				panic("Error creating payload_json form field")
-			} else if err := json.NewEncoder(field).Encode(res); err != nil {
-				panic("Error encoding payload_json")
+			// The response is complete for Discord once flushed, so a deferred handler can keep working
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			_, err = w.Write(body)
+
+			if f, ok := w.(http.Flusher); ok && err == nil {
+				f.Flush()
 			}
 
-			if err := m.Close(); err != nil {
-				panic("Error on close multipart writer")
+			if err == nil {
+				err = r.Context().Err()
 			}
 
-			return
+			if err != nil {
+				ctx.clientGone(err)
+				return fmt.Errorf("%w: %v", ErrClientGone, err)
+			}
+
+			return nil
 		}
 
-		ctx := ConnectionContext{
-			Interaction: interaction,
-			SendRes: func(r *InteractionResponse) bool {
-				w.Header().Set("Content-Type", "application/json")
-				err = je.Encode(r)
-				return err != nil
-			},
-			clientToken: token,
+		ctx.SendRes = func(response *InteractionResponse) error {
+			response = options.responseMentions(response)
+
+			if options.ValidateResponses {
+				if err := ctx.checkAppPermissions(response); err != nil {
+					ctx.handleError(err)
+					return err
+				}
+
+				handled := func(customID string) bool {
+					return componentHandled(router, handlers, options.FallbackProxy != nil, customID)
+				}
+
+				for _, warning := range lintResponse(response, handled) {
+					options.Logger.Warn(warning.String(), "request", ctx.requestID)
+				}
+			}
+
+			claimed, edit, gone := ctx.state.claim(response, options.EditAfterDefer)
+			switch {
+			case edit:
+				return ctx.editDeferred(response)
+			case !claimed:
+				return ErrAlreadyResponded
+			case gone:
+				return ErrClientGone
+			}
+
+			return write(response)
 		}
 
-		for _, h := range InteractionHandlers {
+		if delay, log := options.autoDeferDelay(); delay > 0 {
+			var flags MessageFlag
+			if options.AutoDeferEphemeral {
+				flags = EphemeralMessageFlag
+			}
+
+			defer ctx.deferAfter(delay, flags, log, write)()
+		}
+
+		var matched bool
+
+		started := time.Now()
+		if !life.dispatch.run(r.Context(), func() { matched = dispatch(ctx, router, handlers) }) {
+			matched = true
+			options.Metrics.IncCounter("httpcord_dispatch_rejected_total", map[string]string{"command": ""})
+			ctx.handleError(&DispatchBusyError{})
+		}
+		elapsed := time.Since(started)
+		options.Logger.Debug("interaction dispatched", "request", ctx.requestID, "interaction", ctx.Interaction.ID, "type", ctx.Interaction.Type, "matched", matched, "responded", ctx.Responded(), "duration", elapsed)
+
+		observeHistogram(options.Metrics, "httpcord_handler_duration_seconds", map[string]string{"kind": labels["kind"], "command": labels["command"]}, elapsed.Seconds())
+		dispatchSpan.SetAttribute("request.id", ctx.requestID)
+		dispatchSpan.SetAttribute("matched", strconv.FormatBool(matched))
+
+		if !matched && options.FallbackProxy != nil && !ctx.Responded() && r.Context().Err() == nil {
+			ctx.forward(w, r, bodyBytes)
+		}
+
+		if ctx.state.answeredByCallback() {
+			w.WriteHeader(http.StatusAccepted)
+		}
+
+		if options.ShadowDispatcher != nil {
+			objects.hold()
+			life.background(func() {
+				defer objects.release()
+				ctx.shadowDispatch(bodyBytes)
+			})
+		}
+
+		if after := ctx.state.takeAfter(); len(after) > 0 {
+			objects.hold()
+			life.background(func() {
+				defer objects.release()
+
+				for _, fn := range after {
+					fn()
+				}
+			})
+		}
+	}
+}
+
+// dispatch Run the matching route and the interaction handlers through the middlewares, panics are logged and
+// reported as PanicError unless DisablePanicRecovery is set. Returns whether a route matched
+func dispatch(ctx ConnectionContext, router *commandRouter, handlers *interactionHandlers) (matched bool) {
+	if !ctx.options.DisablePanicRecovery {
+		defer func() {
+			if v := recover(); v != nil {
+				matched = true
+				err := &PanicError{Value: v, Stack: debug.Stack()}
+				ctx.options.Logger.Error("handler panicked", "request", ctx.requestID, "interaction", ctx.Interaction.ID, "panic", fmt.Sprint(v), "stack", string(err.Stack))
+				ctx.handleError(err)
+			}
+		}()
+	}
+
+	handlers.wrap(func(ctx ConnectionContext) {
+		matched = router.dispatch(ctx)
+
+		for _, h := range handlers.all() {
 			h(ctx)
 		}
+
+		if len(InteractionHandlers) > 0 {
+			deprecated("InteractionHandlers", "Connection.AddInteractionHandler", 0)
+
+			for _, h := range InteractionHandlers {
+				h(ctx)
+			}
+		}
+	})(ctx)
+
+	return
+}
+
+// handleError Report the error to OnError and reply with ErrorReply when nothing was sent yet, or with no choices
+// to an autocomplete
+func (ctx *ConnectionContext) handleError(err error) {
+	if ctx.options.OnError != nil {
+		ctx.options.OnError(*ctx, err)
+	}
+
+	if ctx.Responded() || errors.Is(err, ErrClientGone) {
+		return
+	}
+
+	// The deadline is HandlerTimeout, the request is still there
+	if cause := ctx.Context().Err(); cause != nil && !errors.Is(cause, context.DeadlineExceeded) {
+		ctx.clientGone(cause)
+		return
+	}
+
+	// Autocomplete only accepts choices, the member sees no suggestions
+	if ctx.Interaction.Type == AutoCompleteInteraction {
+		ctx.RespondAutocomplete(nil)
+		return
+	}
+
+	data := ctx.options.ErrorReply(*ctx, err)
+
+	if ctx.options.ErrorReport != nil && data != nil {
+		ctx.attachReport(data, err)
+	}
+
+	ctx.ReplyInteraction(data)
+}
+
+// ErrClientGone The inbound request was aborted before the response could be written
+var ErrClientGone = errors.New("httpcord: client closed the request")
+
+// ErrAlreadyResponded The interaction already has an initial response, only the first one is sent
+var ErrAlreadyResponded = errors.New("httpcord: interaction already responded")
+
+// clientGone Report the aborted request once and cancel the handler context.
+// Webhook calls of the interaction token keep working, so background work is not stopped
+func (ctx *ConnectionContext) clientGone(cause error) {
+	ctx.state.mu.Lock()
+	reported := ctx.state.clientGone
+	ctx.state.clientGone = true
+	ctx.state.mu.Unlock()
+
+	if reported {
+		return
+	}
+
+	if ctx.options.OnError != nil {
+		ctx.options.OnError(*ctx, fmt.Errorf("%w: %v", ErrClientGone, cause))
 	}
 }
 
-func (c Connection) AddInteractionHandler(handler func(ctx ConnectionContext)) {
-	InteractionHandlers = append(InteractionHandlers, handler)
+// Context Cancelled when the inbound request is aborted, the handlers returned or ConnectionOptions.HandlerTimeout elapsed
+func (ctx *ConnectionContext) Context() context.Context {
+	if ctx.context == nil {
+		return context.Background()
+	}
+
+	return ctx.context
 }
 
-func (ctx *ConnectionContext) ReplyInteraction(data *InteractionCallbackData) {
-	ctx.SendRes(&InteractionResponse{
+// Responded Whether an initial response was sent or claimed, deferred responses included
+func (ctx *ConnectionContext) Responded() bool {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	return ctx.state.responded
+}
+
+// RequestID Unique ID of the request, shared by every log and error of the interaction
+func (ctx *ConnectionContext) RequestID() string {
+	return ctx.requestID
+}
+
+// TraceCode Short code users can report, rendered by ConnectionOptions.TraceCodeRenderer
+func (ctx *ConnectionContext) TraceCode() string {
+	return ctx.options.TraceCodeRenderer(ctx.requestID)
+}
+
+// AddInteractionHandler Run handler for every interaction of the connection, after the matching route
+func (c *Connection) AddInteractionHandler(handler func(ctx ConnectionContext)) {
+	c.handlers.add(handler)
+}
+
+// Use Wrap the dispatch of every interaction of the connection with the middlewares, routes and interaction handlers
+// included, for logging, metrics or checks refusing the interaction by not calling next. Middlewares run in the order
+// they were added, before the ones of InteractionRouter.Use, and apply to the interactions received after the call.
+// Panics of a middleware are recovered like the ones of the handlers
+func (c *Connection) Use(middlewares ...Middleware) {
+	c.handlers.use(middlewares...)
+}
+
+// afterResponse Run fn in background once the handlers returned and the response is written, right away when
+// called after the handlers returned like from a goroutine of the handler
+func (ctx *ConnectionContext) afterResponse(fn func()) {
+	ctx.state.mu.Lock()
+
+	if !ctx.state.afterTaken {
+		ctx.state.after = append(ctx.state.after, fn)
+		ctx.state.mu.Unlock()
+		return
+	}
+
+	ctx.state.mu.Unlock()
+	ctx.life.background(fn)
+}
+
+// takeAfter Functions of afterResponse to run now the handlers returned
+func (s *interactionState) takeAfter() []func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	after := s.after
+	s.after, s.afterTaken = nil, true
+	return after
+}
+
+// Client REST client resolving tokens for the interaction application
+func (ctx *ConnectionContext) Client() *RestClient {
+	return ctx.client
+}
+
+// ReplyInteraction Reply with a message, returns ErrAlreadyResponded when the interaction already has its initial response
+func (ctx *ConnectionContext) ReplyInteraction(data *InteractionCallbackData) error {
+	return ctx.SendRes(&InteractionResponse{
 		Type: ChannelMessageWithSourceResponse,
 		Data: data,
 	})
 }
 
-func (ctx *ConnectionContext) DeferReplyInteraction() {
-	ctx.SendRes(&InteractionResponse{
+// ReplyEphemeral Reply with a message only the user of the interaction sees, data is not modified
+func (ctx *ConnectionContext) ReplyEphemeral(data *InteractionCallbackData) error {
+	reply := InteractionCallbackData{}
+	if data != nil {
+		reply = *data
+	}
+
+	reply.Flags |= EphemeralMessageFlag
+	return ctx.ReplyInteraction(&reply)
+}
+
+// UpdateMessage Edit the message the component is attached to
+func (ctx *ConnectionContext) UpdateMessage(data *InteractionCallbackData) error {
+	return ctx.SendRes(&InteractionResponse{
+		Type: UpdateMessageResponse,
+		Data: data,
+	})
+}
+
+// RespondAutocomplete Send the choices of an autocomplete interaction, only the first MaxAutocompleteChoices are sent.
+// Choices are shown with their NameLocalizations in the user locale. Returns ErrNotAutocomplete for other interactions
+func (ctx *ConnectionContext) RespondAutocomplete(choices []ApplicationCommandOptionChoice) error {
+	if ctx.Interaction.Type != AutoCompleteInteraction {
+		return ErrNotAutocomplete
+	}
+
+	if len(choices) > MaxAutocompleteChoices {
+		choices = choices[:MaxAutocompleteChoices]
+	}
+
+	data := &InteractionCallbackData{Choices: make([]*ApplicationCommandOptionChoice, len(choices))}
+
+	for i := range choices {
+		data.Choices[i] = &choices[i]
+	}
+
+	return ctx.SendRes(&InteractionResponse{
+		Type: ApplicationCommandAutoCompleteResultResponse,
+		Data: data,
+	})
+}
+
+func (ctx *ConnectionContext) DeferReplyInteraction() error {
+	ctx.markDeferred()
+	return ctx.SendRes(&InteractionResponse{
+		Type: DeferredChannelMessageWithSourceResponse,
+	})
+}
+
+// DeferReplyWithFlags Defer the reply like DeferReplyInteraction, with EphemeralMessageFlag the "thinking" state and the
+// reply edited later are only shown to the user
+func (ctx *ConnectionContext) DeferReplyWithFlags(flags MessageFlag) error {
+	ctx.markDeferred()
+	return ctx.SendRes(&InteractionResponse{
 		Type: DeferredChannelMessageWithSourceResponse,
+		Data: &InteractionCallbackData{Flags: flags},
 	})
 }
 
-func (ctx *ConnectionContext) DeferUpdateInteraction() {
-	ctx.SendRes(&InteractionResponse{
+func (ctx *ConnectionContext) DeferUpdateInteraction() error {
+	ctx.markDeferred()
+	return ctx.SendRes(&InteractionResponse{
 		Type: DeferredUpdateResponse,
 	})
 }
 
-func (ctx *ConnectionContext) EditReply(data *WebhookEdit) {
-	EditOriginalInteractionResponse(ctx.Interaction.ApplicationID.String(), ctx.Interaction.Token, data)
+func (ctx *ConnectionContext) markDeferred() {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	ctx.state.deferredAt = time.Now()
+}
+
+// EditReply Edit the original response, right after a defer the edit is retried while Discord processes the callback.
+// The call is not bound to Context so edits made after the handlers returned are not cancelled, see EditReplyContext
+func (ctx *ConnectionContext) EditReply(data *WebhookEdit, opts ...EditOption) (*Message, error) {
+	return ctx.editOriginal(context.Background(), data, opts...)
+}
+
+// EditReplyContext Like EditReply cancelled with c, like Context for edits made while the handlers run
+func (ctx *ConnectionContext) EditReplyContext(c context.Context, data *WebhookEdit, opts ...EditOption) (*Message, error) {
+	return ctx.editOriginal(c, data, opts...)
+}
+
+func (ctx *ConnectionContext) editOriginal(c context.Context, data *WebhookEdit, opts ...EditOption) (*Message, error) {
+	data = ctx.options.webhookMentions(data)
+
+	ctx.state.editMu.Lock()
+	defer ctx.state.editMu.Unlock()
+
+	ctx.state.mu.Lock()
+	deferredAt := ctx.state.deferredAt
+	ctx.state.mu.Unlock()
+
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		message, err := ctx.webhooks.EditOriginalInteractionResponse(c, ctx.Interaction.ApplicationID, ctx.Interaction.Token, data, opts...)
+
+		if err == nil || attempt == 3 || deferredAt.IsZero() || time.Since(deferredAt) > ctx.options.DeferEditRetryWindow || !isUnknownWebhook(err) {
+			return message, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-c.Done():
+			return nil, c.Err()
+		}
+
+		backoff *= 2
+	}
+}
+
+func (ctx *ConnectionContext) DeleteReply() error {
+	return ctx.DeleteReplyContext(context.Background())
+}
+
+// DeleteReplyContext Like DeleteReply cancelled with c
+func (ctx *ConnectionContext) DeleteReplyContext(c context.Context) error {
+	return ctx.webhooks.DeleteOriginalInteractionResponse(c, ctx.Interaction.ApplicationID, ctx.Interaction.Token)
 }
 
-func (ctx *ConnectionContext) DeleteReply() {
-	DeleteOriginalInteractionResponse(ctx.Interaction.ApplicationID.String(), ctx.Interaction.Token)
+func (ctx *ConnectionContext) FollowUp(data *WebhookEdit) (*Message, error) {
+	return ctx.createFollowUp(context.Background(), data)
 }
 
-func (ctx *ConnectionContext) FollowUp(data *WebhookEdit) {
-	FollowUpInteractionResponse(ctx.Interaction.ApplicationID.String(), ctx.Interaction.Token, data)
+// FollowUpContext Like FollowUp cancelled with c
+func (ctx *ConnectionContext) FollowUpContext(c context.Context, data *WebhookEdit) (*Message, error) {
+	return ctx.createFollowUp(c, data)
 }
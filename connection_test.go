@@ -0,0 +1,238 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentResponses(t *testing.T) {
+	const racers = 8
+
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+		// deferFirst The handler defers before the racers reply
+		deferFirst bool
+		response   InteractionCallbackType
+		// accepted Replies returning nil, the others return ErrAlreadyResponded
+		accepted int
+		edits    int
+	}{
+		{"racing replies", ConnectionOptions{}, false, ChannelMessageWithSourceResponse, 1, 0},
+		{"replies after a defer", ConnectionOptions{}, true, DeferredChannelMessageWithSourceResponse, 0, 0},
+		{"replies editing a defer", ConnectionOptions{EditAfterDefer: true}, true, DeferredChannelMessageWithSourceResponse, racers, racers},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, edits := followUpServer(t)
+
+			options := test.options
+			options.Logger = NopLogger
+
+			conn, sign := signedConnection(t, options)
+			conn.Client.BaseURL = client.BaseURL
+
+			var accepted int32
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				if test.deferFirst {
+					if err := ctx.DeferReplyInteraction(); err != nil {
+						t.Errorf("defer: %v", err)
+					}
+				}
+
+				var wg sync.WaitGroup
+				for i := 0; i < racers; i++ {
+					wg.Add(1)
+
+					go func(i int) {
+						defer wg.Done()
+
+						err := ctx.ReplyInteraction(&InteractionCallbackData{Content: strconv.Itoa(i)})
+						if err != nil && !errors.Is(err, ErrAlreadyResponded) {
+							t.Errorf("reply %d: %v", i, err)
+						}
+
+						if err == nil {
+							atomic.AddInt32(&accepted, 1)
+						}
+					}(i)
+				}
+
+				wg.Wait()
+
+				if !ctx.Responded() {
+					t.Error("Responded() = false after the replies")
+				}
+			})
+
+			// Parallel requests share the router, the pools and the connection state
+			const requests = 16
+
+			var wg sync.WaitGroup
+			for i := 0; i < requests; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					w := httptest.NewRecorder()
+					conn.ServeHTTP(w, sign(commandBody()))
+
+					var response InteractionResponse
+					if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+						t.Errorf("%d %s is not a single response: %v", w.Code, w.Body, err)
+						return
+					}
+
+					if response.Type != test.response {
+						t.Errorf("response type %d, want %d", response.Type, test.response)
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			if int(accepted) != requests*test.accepted {
+				t.Errorf("%d replies accepted, want %d per interaction", accepted, test.accepted)
+			}
+
+			if got := len(edits()); got != requests*test.edits {
+				t.Errorf("%d edits, want %d", got, requests*test.edits)
+			}
+		})
+	}
+}
+
+func TestConnectionsInOneProcess(t *testing.T) {
+	type bot struct {
+		conn   *Connection
+		sign   func(body []byte) *http.Request
+		server *httptest.Server
+		// seen Tokens of the handler calls of the connection
+		seen []string
+	}
+
+	bots := make([]*bot, 2)
+	for i, token := range []string{"production", "staging"} {
+		conn, sign := signedConnection(t, ConnectionOptions{Token: token, Logger: NopLogger})
+		b := &bot{conn: conn, sign: sign, server: httptest.NewServer(conn)}
+		t.Cleanup(b.server.Close)
+
+		conn.AddInteractionHandler(func(ctx ConnectionContext) {
+			b.seen = append(b.seen, ctx.clientToken)
+		})
+
+		bots[i] = b
+	}
+
+	// post Send the request signed for from to the server of to
+	post := func(from, to *bot) int {
+		r := from.sign(commandBody())
+
+		req, err := http.NewRequest(http.MethodPost, to.server.URL, r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header = r.Header
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	for _, b := range bots {
+		if status := post(b, b); status != http.StatusOK {
+			t.Errorf("status %d, want 200", status)
+		}
+	}
+
+	if status := post(bots[0], bots[1]); status != http.StatusUnauthorized {
+		t.Errorf("request of the other key answered %d, want 401", status)
+	}
+
+	for i, want := range []string{"production", "staging"} {
+		if len(bots[i].seen) != 1 || bots[i].seen[0] != want {
+			t.Errorf("handlers of %s ran with the tokens %v, want once with its own", want, bots[i].seen)
+		}
+	}
+}
+
+func TestClientGone(t *testing.T) {
+	tests := []struct {
+		name string
+		// handler Returns the error of its response
+		handler func(ctx ConnectionContext) error
+	}{
+		{"reply", func(ctx ConnectionContext) error {
+			return ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+		}},
+		{"defer", func(ctx ConnectionContext) error {
+			return ctx.DeferReplyInteraction()
+		}},
+		{"error reply", func(ctx ConnectionContext) error {
+			panic("ban failed")
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var reported []error
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				Logger:  NopLogger,
+				OnError: func(ctx ConnectionContext, err error) { reported = append(reported, err) },
+			})
+
+			r := sign(commandBody())
+			c, cancel := context.WithCancel(r.Context())
+			r = r.WithContext(c)
+
+			// Kept by the handlers panicking before they return
+			err := ErrClientGone
+			conn.Command("ban", func(ctx ConnectionContext) {
+				cancel()
+
+				err = test.handler(ctx)
+
+				if ctx.Context().Err() == nil {
+					t.Error("the handler context is not cancelled with the request")
+				}
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, r)
+
+			if !errors.Is(err, ErrClientGone) {
+				t.Errorf("handler got %v, want ErrClientGone", err)
+			}
+
+			if w.Body.Len() != 0 || w.Flushed || len(w.Header()) != 0 {
+				t.Errorf("response %d %v %s written for an aborted request", w.Code, w.Header(), w.Body)
+			}
+
+			gone := 0
+			for _, err := range reported {
+				if errors.Is(err, ErrClientGone) {
+					gone++
+				}
+			}
+
+			if gone != 1 {
+				t.Errorf("OnError got %v, want ErrClientGone once", reported)
+			}
+		})
+	}
+}
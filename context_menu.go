@@ -0,0 +1,93 @@
+package httpcord
+
+import "strings"
+
+// contextMenuKey User and message commands may share a name, unlike the chat input commands
+type contextMenuKey struct {
+	Type ApplicationCommandType
+	Name string
+}
+
+func (r *commandRouter) addContextMenu(route *CommandRoute) *CommandRoute {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := contextMenuKey{Type: route.Type, Name: route.Name}
+	if existing, ok := r.contextMenus[key]; ok {
+		panic(&RouteConflictError{Kind: "context menu command", Key: route.Name, Source: route.Source, Existing: existing.Source})
+	}
+
+	r.contextMenus[key] = route
+	return route
+}
+
+func (r *commandRouter) contextMenu(kind ApplicationCommandType, name string) *CommandRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.contextMenus[contextMenuKey{Type: kind, Name: name}]
+}
+
+// UserCommand Register a handler for the user context menu command with the given name, like "User Info".
+// Panics when the name is already registered, the target is available with TargetUser and TargetMember
+func (c *Connection) UserCommand(name string, handler Handler) *CommandRoute {
+	return c.router.addContextMenu(&CommandRoute{Name: strings.TrimSpace(name), Type: UserApplicationCommandType, Handler: handler, Source: callerSite(1)})
+}
+
+// MessageCommand Register a handler for the message context menu command with the given name, like "Report Message".
+// Panics when the name is already registered, the target is available with TargetMessage
+func (c *Connection) MessageCommand(name string, handler Handler) *CommandRoute {
+	return c.router.addContextMenu(&CommandRoute{Name: strings.TrimSpace(name), Type: MessageApplicationCommandType, Handler: handler, Source: callerSite(1)})
+}
+
+// NewUserCommand Definition of the user context menu command, registered with its type and without description
+func NewUserCommand(name string) *ApplicationCommand {
+	return NewCommandBuilder().SetType(UserApplicationCommandType).SetName(name)
+}
+
+// NewMessageCommand Definition of the message context menu command, registered with its type and without description
+func NewMessageCommand(name string) *ApplicationCommand {
+	return NewCommandBuilder().SetType(MessageApplicationCommandType).SetName(name)
+}
+
+// targetData Data of the context menu command of the type, false for other interactions
+func (ctx *ConnectionContext) targetData(kind ApplicationCommandType) (*ApplicationCommandInteractionData, bool) {
+	if ctx.Interaction.Type != ApplicationCommandInteraction {
+		return nil, false
+	}
+
+	data := ctx.Interaction.ApplicationCommandData()
+	return &data, data.Type == kind && data.TargetID != ""
+}
+
+// TargetUser User the user command was used on, false for other interactions
+func (ctx *ConnectionContext) TargetUser() (*User, bool) {
+	data, ok := ctx.targetData(UserApplicationCommandType)
+	if !ok {
+		return nil, false
+	}
+
+	user, ok := data.Resolved.Users[data.TargetID]
+	return user, ok && user != nil
+}
+
+// TargetMember Member the user command was used on with its User, false outside guilds or for other interactions
+func (ctx *ConnectionContext) TargetMember() (*Member, bool) {
+	data, ok := ctx.targetData(UserApplicationCommandType)
+	if !ok {
+		return nil, false
+	}
+
+	return resolvedMember(&data.Resolved, data.TargetID)
+}
+
+// TargetMessage Message the message command was used on, false for other interactions
+func (ctx *ConnectionContext) TargetMessage() (*Message, bool) {
+	data, ok := ctx.targetData(MessageApplicationCommandType)
+	if !ok {
+		return nil, false
+	}
+
+	message, ok := data.Resolved.Messages[data.TargetID]
+	return message, ok && message != nil
+}
@@ -0,0 +1,139 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// userCommandData "User Info" used on the member 6, as Discord sends it
+const userCommandData = `{"id":"8","name":"User Info","type":2,"target_id":"6","resolved":{` +
+	`"users":{"6":{"id":"6","username":"target","avatar":"a1b2"}},` +
+	`"members":{"6":{"nick":"suspect","roles":["11"],"joined_at":"2024-01-02T03:04:05+00:00","permissions":"1024"}}}}`
+
+// messageCommandData "Report Message" used on the message 7 of the user 6, as Discord sends it
+const messageCommandData = `{"id":"9","name":"Report Message","type":3,"target_id":"7","resolved":{"messages":{"7":{` +
+	`"id":"7","channel_id":"3","author":{"id":"6","username":"target"},"content":"buy cheap nitro",` +
+	`"timestamp":"2024-01-02T03:04:05+00:00","edited_timestamp":null,"tts":false,"mention_everyone":false,"mentions":[],` +
+	`"mention_roles":[],"attachments":[],"embeds":[],"pinned":false,"type":0}}}}`
+
+func TestContextMenuCommands(t *testing.T) {
+	conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+
+	var ran string
+	var user *User
+	var member *Member
+	var message *Message
+	var targetsOK [3]bool
+
+	handler := func(name string) Handler {
+		return func(ctx ConnectionContext) {
+			ran = name
+			user, targetsOK[0] = ctx.TargetUser()
+			member, targetsOK[1] = ctx.TargetMember()
+			message, targetsOK[2] = ctx.TargetMessage()
+			ctx.ReplyInteraction(&InteractionCallbackData{Content: name})
+		}
+	}
+
+	// A user and a message command may share a name, the chat input command of the name stays apart
+	conn.UserCommand("User Info", handler("user"))
+	conn.MessageCommand("User Info", handler("message of the user name"))
+	conn.MessageCommand("Report Message", handler("message"))
+	conn.Command("report", handler("chat input"))
+
+	// Discord resolves no member in DMs
+	dm := inDM(interactionBody(ApplicationCommandInteraction, `{"id":"8","name":"User Info","type":2,"target_id":"6",`+
+		`"resolved":{"users":{"6":{"id":"6","username":"target"}}}}`))
+
+	tests := []struct {
+		name    string
+		body    []byte
+		ran     string
+		targets [3]bool
+		check   func(t *testing.T)
+	}{
+		{"user command", interactionBody(ApplicationCommandInteraction, userCommandData), "user", [3]bool{true, true, false}, func(t *testing.T) {
+			if user.ID != "6" || user.Username != "target" || user.Avatar != "a1b2" {
+				t.Errorf("TargetUser() = %+v, want the resolved user 6", user)
+			}
+
+			if member.Nick != "suspect" || member.User == nil || member.User.ID != "6" || len(member.Roles) != 1 || *member.Roles[0] != "11" ||
+				member.JoinedAt.IsZero() {
+				t.Errorf("TargetMember() = %+v, want the resolved member 6 with its user", member)
+			}
+		}},
+		{"user command in DM", dm, "user", [3]bool{true, false, false}, func(t *testing.T) {
+			if user.ID != "6" || member != nil {
+				t.Errorf("TargetUser() = %+v, TargetMember() = %+v, want the user without member", user, member)
+			}
+		}},
+		{"message command", interactionBody(ApplicationCommandInteraction, messageCommandData), "message", [3]bool{false, false, true}, func(t *testing.T) {
+			if message.ID != "7" || message.ChannelID != "3" || message.Content != "buy cheap nitro" || message.Author == nil ||
+				message.Author.ID != "6" || message.Timestamp.IsZero() {
+				t.Errorf("TargetMessage() = %+v, want the resolved message 7", message)
+			}
+		}},
+		{"message command named like a user command", interactionBody(ApplicationCommandInteraction,
+			`{"id":"10","name":"User Info","type":3,"target_id":"7","resolved":{"messages":{"7":{"id":"7","channel_id":"3","content":"hi"}}}}`),
+			"message of the user name", [3]bool{false, false, true}, func(t *testing.T) {}},
+		{"chat input command", interactionBody(ApplicationCommandInteraction, `{"id":"5","name":"report","type":1}`), "chat input",
+			[3]bool{}, func(t *testing.T) {}},
+		{"target not resolved", interactionBody(ApplicationCommandInteraction, `{"id":"8","name":"User Info","type":2,"target_id":"6"}`),
+			"user", [3]bool{}, func(t *testing.T) {}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ran, user, member, message, targetsOK = "", nil, nil, nil, [3]bool{}
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			if w.Code != http.StatusOK || ran != test.ran {
+				t.Fatalf("status %d, ran %q, want %q: %s", w.Code, ran, test.ran, w.Body)
+			}
+
+			if targetsOK != test.targets {
+				t.Fatalf("TargetUser, TargetMember, TargetMessage found %v, want %v", targetsOK, test.targets)
+			}
+
+			test.check(t)
+		})
+	}
+}
+
+func TestContextMenuDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		command *ApplicationCommand
+		want    string
+	}{
+		{"user command", NewUserCommand("User Info"), `{"name":"User Info","type":2}`},
+		{"message command", NewMessageCommand("Report Message"), `{"name":"Report Message","type":3}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := json.Marshal(test.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got, want map[string]interface{}
+			json.Unmarshal(body, &got)
+			json.Unmarshal([]byte(test.want), &want)
+
+			for key, value := range want {
+				if got[key] != value {
+					t.Errorf("%s = %v, want %v", key, got[key], value)
+				}
+			}
+
+			if description, ok := got["description"]; ok && description != "" {
+				t.Errorf("description %q, want none for a context menu command", description)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Package discordgo Runs the handlers written against httpcord.InteractionEvent on the InteractionCreate events of a
+// discordgo gateway session, to move a bot to HTTP interactions one handler at a time:
+//
+//	adapter := &discordgo.Adapter{}
+//	session.AddHandler(adapter.Handler(ping))
+//	connection.Command("ping", httpcord.EventHandler(ping))
+//
+// The event is decoded into the httpcord types and handled by a httpcord.ConnectionContext, the responses and the
+// webhook calls go through the REST client of the adapter so both transports behave the same. The module is separate
+// from httpcord so its users do not depend on discordgo
+package discordgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	dgo "github.com/bwmarrin/discordgo"
+
+	"httpcord"
+)
+
+// ErrNoInteraction The InteractionCreate event has no interaction
+var ErrNoInteraction = errors.New("httpcord/contrib/discordgo: event without interaction")
+
+// Adapter Run the InteractionEvent handlers on the discordgo InteractionCreate events
+type Adapter struct {
+	// Options of the ConnectionContext of the events, like the ErrorReply or the Translator of the bot
+	Options httpcord.ConnectionOptions
+	// Client sends the responses and the webhook calls (Defaults to a client of the session token)
+	Client *httpcord.RestClient
+	// OnError receives the events that could not be decoded (Defaults to logging them with Options.Logger)
+	OnError func(err error)
+
+	once sync.Once
+}
+
+// Handler discordgo event handler running the handler with the interaction as its InteractionEvent, the background
+// work of the handler is awaited like a request does once its handlers returned
+func (a *Adapter) Handler(handler func(event httpcord.InteractionEvent)) func(s *dgo.Session, i *dgo.InteractionCreate) {
+	return func(s *dgo.Session, i *dgo.InteractionCreate) {
+		event, finish, err := a.Event(s, i)
+		if err != nil {
+			a.handleError(err)
+			return
+		}
+
+		defer finish()
+		handler(event)
+	}
+}
+
+// Event InteractionEvent of the discordgo event, finish runs the work started after the response and waits for the
+// background work
+func (a *Adapter) Event(s *dgo.Session, i *dgo.InteractionCreate) (event httpcord.InteractionEvent, finish func(), err error) {
+	if i == nil || i.Interaction == nil {
+		return nil, nil, ErrNoInteraction
+	}
+
+	interaction, err := decodeInteraction(i.Interaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := a.client(s)
+	ctx, finish := httpcord.NewContext(interaction, httpcord.ContextConfig{
+		Options: a.Options,
+		Client:  client,
+		Respond: func(response *httpcord.InteractionResponse) error {
+			_, err := client.CreateInteractionResponse(context.Background(), interaction.ID, interaction.Token, response, false)
+			return err
+		},
+	})
+
+	return ctx, finish, nil
+}
+
+func (a *Adapter) client(s *dgo.Session) *httpcord.RestClient {
+	a.once.Do(func() {
+		if a.Client == nil {
+			a.Client = httpcord.NewRestClient(httpcord.StaticToken(strings.TrimPrefix(s.Token, "Bot ")))
+		}
+	})
+
+	return a.Client
+}
+
+func (a *Adapter) handleError(err error) {
+	if a.OnError != nil {
+		a.OnError(err)
+		return
+	}
+
+	logger := a.Options.Logger
+	if logger == nil {
+		logger = httpcord.DefaultLogger
+	}
+
+	logger.Error("discordgo interaction refused", "error", err)
+}
+
+// decodeInteraction Interaction of the discordgo event, its JSON has the shape of the interaction Discord sent
+func decodeInteraction(event *dgo.Interaction) (interaction httpcord.Interaction, err error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return httpcord.Interaction{}, fmt.Errorf("httpcord/contrib/discordgo: %w", err)
+	}
+
+	b, err = apiShape(b)
+	if err != nil {
+		return httpcord.Interaction{}, fmt.Errorf("httpcord/contrib/discordgo: %w", err)
+	}
+
+	var raw httpcord.APIInteraction
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return httpcord.Interaction{}, &httpcord.MalformedInteractionError{Err: err}
+	}
+
+	// ResolveInteraction panics on the interactions it can not resolve
+	defer func() {
+		if v := recover(); v != nil {
+			err, _ = v.(error)
+			if err == nil {
+				err = fmt.Errorf("httpcord/contrib/discordgo: %v", v)
+			}
+		}
+	}()
+
+	return httpcord.ResolveInteraction(&raw), nil
+}
+
+// apiShape Convert the fields discordgo encodes differently from httpcord.APIInteraction: the accent color of the
+// invoking user is a string, the users of the message and the resolved data keep the integer of httpcord.User
+func apiShape(b []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	user, err := stringAccentColor(fields["user"])
+	if err != nil {
+		return nil, err
+	}
+
+	fields["user"] = user
+
+	if member := fields["member"]; len(member) > 0 && string(member) != "null" {
+		var memberFields map[string]json.RawMessage
+		if err := json.Unmarshal(member, &memberFields); err != nil {
+			return nil, err
+		}
+
+		if memberFields["user"], err = stringAccentColor(memberFields["user"]); err != nil {
+			return nil, err
+		}
+
+		if fields["member"], err = json.Marshal(memberFields); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// stringAccentColor User object with its accent_color as a string like httpcord.APIUser
+func stringAccentColor(user json.RawMessage) (json.RawMessage, error) {
+	if len(user) == 0 || string(user) == "null" {
+		return user, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(user, &fields); err != nil {
+		return nil, err
+	}
+
+	if color, ok := fields["accent_color"]; ok && string(color) != "null" {
+		fields["accent_color"], _ = json.Marshal(string(color))
+	}
+
+	return json.Marshal(fields)
+}
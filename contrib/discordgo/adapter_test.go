@@ -0,0 +1,187 @@
+package discordgo
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dgo "github.com/bwmarrin/discordgo"
+
+	"httpcord"
+)
+
+// interactionJSON Guild command interaction created now so its token is valid
+func interactionJSON() []byte {
+	id := (uint64(time.Now().UnixMilli()) - 1420070400000) << 22
+
+	return []byte(`{"id":"` + strconv.FormatUint(id, 10) + `","application_id":"1","type":2,"token":"token","version":1,` +
+		`"guild_id":"2","channel_id":"3","app_permissions":"2048","locale":"fr",` +
+		`"member":{"user":{"id":"4","username":"mod"},"nick":"moderator","roles":["5"],"permissions":"8","joined_at":"2024-01-02T03:04:05Z"},` +
+		`"data":{"id":"6","name":"warn","type":1,"options":[{"type":3,"name":"reason","value":"spam"}]}}`)
+}
+
+type recordedCall struct {
+	method string
+	path   string
+	body   string
+}
+
+func TestAdapterHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(event httpcord.InteractionEvent)
+		calls   []string
+		body    string
+	}{
+		{
+			name: "reply",
+			handler: func(event httpcord.InteractionEvent) {
+				reason, _ := event.StringOption("reason")
+				event.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "warned for " + reason})
+			},
+			calls: []string{"POST /interactions/{id}/token/callback"},
+			body:  "warned for spam",
+		},
+		{
+			name: "deferred edit",
+			handler: func(event httpcord.InteractionEvent) {
+				event.DeferReplyInteraction()
+				event.EditReply(&httpcord.WebhookEdit{Content: "done"})
+			},
+			calls: []string{"POST /interactions/{id}/token/callback", "PATCH /webhooks/1/token/messages/@original"},
+			body:  "done",
+		},
+		{
+			name: "follow-up",
+			handler: func(event httpcord.InteractionEvent) {
+				event.ReplyEphemeral(&httpcord.InteractionCallbackData{Content: "first"})
+				event.FollowUp(&httpcord.WebhookEdit{Content: "second"})
+			},
+			calls: []string{"POST /interactions/{id}/token/callback", "POST /webhooks/1/token"},
+			body:  "second",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				calls []recordedCall
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				mu.Lock()
+				calls = append(calls, recordedCall{method: r.Method, path: r.URL.Path, body: string(body)})
+				mu.Unlock()
+
+				if strings.HasSuffix(r.URL.Path, "/callback") {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":"7","channel_id":"3"}`))
+			}))
+			defer server.Close()
+
+			client := httpcord.NewRestClient(httpcord.StaticToken("token"))
+			client.BaseURL = server.URL
+			client.MaxRetries = 0
+
+			var event dgo.InteractionCreate
+			if err := json.Unmarshal(interactionJSON(), &event); err != nil {
+				t.Fatal(err)
+			}
+
+			adapter := &Adapter{Client: client, OnError: func(err error) { t.Errorf("OnError(%v)", err) }}
+			adapter.Handler(test.handler)(&dgo.Session{Token: "Bot token"}, &event)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if len(calls) != len(test.calls) {
+				t.Fatalf("calls = %v, want %v", calls, test.calls)
+			}
+
+			for i, want := range test.calls {
+				want = strings.Replace(want, "{id}", event.ID, 1)
+				if got := calls[i].method + " " + calls[i].path; got != want {
+					t.Errorf("call %d = %s, want %s", i, got, want)
+				}
+			}
+
+			if last := calls[len(calls)-1].body; !strings.Contains(last, test.body) {
+				t.Errorf("last call sent %s, want %q", last, test.body)
+			}
+		})
+	}
+}
+
+func TestAdapterEvent(t *testing.T) {
+	var created dgo.InteractionCreate
+	if err := json.Unmarshal(interactionJSON(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	var component dgo.InteractionCreate
+	err := json.Unmarshal([]byte(`{"id":"8","application_id":"1","type":3,"token":"token","version":1,"channel_id":"3",`+
+		`"user":{"id":"4","username":"mod","accent_color":16711680},`+
+		`"message":{"id":"9","channel_id":"3","content":"pick","author":{"id":"1","username":"bot","bot":true,"accent_color":255}},`+
+		`"data":{"custom_id":"pick","component_type":3,"values":["a"]}}`), &component)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		event *dgo.InteractionCreate
+		err   error
+		check func(event httpcord.InteractionEvent) bool
+	}{
+		{"decoded", &created, nil, func(event httpcord.InteractionEvent) bool {
+			member := event.Event().Member
+			permissions := event.Event().AppPermissions
+
+			return member != nil && member.User.ID == "4" && member.Nick == "moderator" && permissions != nil && *permissions == 2048 &&
+				event.Locale() == httpcord.FrenchLocale
+		}},
+		{"component in a DM", &component, nil, func(event httpcord.InteractionEvent) bool {
+			interaction := event.Event()
+
+			return interaction.User != nil && interaction.User.AccentColor == 16711680 && interaction.Message != nil &&
+				interaction.Message.Author.AccentColor == 255 && event.CustomID() == "pick" && event.SelectedValues()[0] == "a"
+		}},
+		{"no interaction", &dgo.InteractionCreate{}, ErrNoInteraction, nil},
+		{"nil event", nil, ErrNoInteraction, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			adapter := &Adapter{Client: httpcord.NewRestClient(httpcord.StaticToken("token"))}
+
+			event, finish, err := adapter.Event(&dgo.Session{Token: "Bot token"}, test.event)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Event() = %v, want %v", err, test.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			defer finish()
+
+			if !test.check(event) {
+				t.Errorf("the event was decoded as %+v", event.Event())
+			}
+		})
+	}
+}
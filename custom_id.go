@@ -0,0 +1,209 @@
+package httpcord
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CustomIDSeparator Separator of the key and the values of EncodeCustomID
+	CustomIDSeparator = ":"
+
+	// customIDCompressed First character of the values deflated by EncodeCompressedCustomID
+	customIDCompressed = "~"
+)
+
+// ErrInvalidCustomID A custom_id value can not be encoded, or the custom_id can not be decoded
+var ErrInvalidCustomID = errors.New("httpcord: invalid custom id")
+
+var (
+	customIDEscaper   = strings.NewReplacer("%", "%25", CustomIDSeparator, "%3A", customIDCompressed, "%7E")
+	customIDUnescaper = strings.NewReplacer("%25", "%", "%3A", CustomIDSeparator, "%7E", customIDCompressed)
+)
+
+// EncodeCustomID custom_id of the key followed by the values separated by ":", like "confirm:80351110224678912:ban"
+// routed by "confirm:{userID}:{action}" (See ConnectionContext.CustomIDVar). Values are strings, Snowflakes, integers,
+// floats and booleans, the separators in them are escaped. Fails with a CustomIDError when over MaxCustomIDLength
+func EncodeCustomID(key string, values ...interface{}) (string, error) {
+	encoded, err := encodeCustomIDValues(values)
+	if err != nil {
+		return "", err
+	}
+
+	customID := strings.Join(append([]string{key}, encoded...), CustomIDSeparator)
+	return customID, checkCustomID(customID)
+}
+
+// EncodeCompressedCustomID Like EncodeCustomID with the values deflated into one segment when it makes the custom_id
+// shorter, the routes and DecodeCustomID inflate them
+func EncodeCompressedCustomID(key string, values ...interface{}) (string, error) {
+	encoded, err := encodeCustomIDValues(values)
+	if err != nil {
+		return "", err
+	}
+
+	plain := strings.Join(encoded, CustomIDSeparator)
+
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write([]byte(plain))
+	w.Close()
+
+	customID := key + CustomIDSeparator + plain
+	if compressed := customIDCompressed + base64.RawURLEncoding.EncodeToString(buf.Bytes()); len(compressed) < len(plain) {
+		customID = key + CustomIDSeparator + compressed
+	}
+
+	return customID, checkCustomID(customID)
+}
+
+// DecodeCustomID Segments of the custom_id split on ":" with the values unescaped and inflated, the key is the
+// first segments. Fails with ErrInvalidCustomID when the compressed values are corrupt
+func DecodeCustomID(customID string) ([]string, error) {
+	segments := strings.Split(customID, CustomIDSeparator)
+
+	last := segments[len(segments)-1]
+	if len(segments) > 1 && strings.HasPrefix(last, customIDCompressed) {
+		compressed, err := base64.RawURLEncoding.DecodeString(last[len(customIDCompressed):])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCustomID, err)
+		}
+
+		plain, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), 64*MaxCustomIDLength))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCustomID, err)
+		}
+
+		segments = append(segments[:len(segments)-1], strings.Split(string(plain), CustomIDSeparator)...)
+	}
+
+	for i, segment := range segments {
+		segments[i] = customIDUnescaper.Replace(segment)
+	}
+
+	return segments, nil
+}
+
+func encodeCustomIDValues(values []interface{}) ([]string, error) {
+	encoded := make([]string, len(values))
+
+	for i, value := range values {
+		v := reflect.ValueOf(value)
+
+		switch v.Kind() {
+		case reflect.String:
+			encoded[i] = customIDEscaper.Replace(v.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			encoded[i] = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			encoded[i] = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			encoded[i] = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		case reflect.Bool:
+			encoded[i] = strconv.FormatBool(v.Bool())
+		default:
+			return nil, fmt.Errorf("%w: value %d of type %T", ErrInvalidCustomID, i, value)
+		}
+	}
+
+	return encoded, nil
+}
+
+// customIDTemplate Component route pattern with placeholders like "confirm:{userID}:{action}"
+type customIDTemplate struct {
+	route *ComponentRoute
+	// segments are the literal segments, empty for the placeholders
+	segments []string
+	names    []string
+	literals int
+}
+
+func parseCustomIDTemplate(route *ComponentRoute) *customIDTemplate {
+	parts := strings.Split(route.Pattern, CustomIDSeparator)
+	t := &customIDTemplate{route: route, segments: make([]string, len(parts)), names: make([]string, len(parts))}
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && len(part) > 2 {
+			t.names[i] = part[1 : len(part)-1]
+			continue
+		}
+
+		if strings.ContainsAny(part, "{}") {
+			panic(fmt.Sprintf("httpcord: component pattern %q: placeholders are whole segments like {name}", route.Pattern))
+		}
+
+		t.segments[i] = part
+		t.literals++
+	}
+
+	return t
+}
+
+// match Values of the placeholders when the decoded segments match the template
+func (t *customIDTemplate) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(t.segments) {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(t.segments)-t.literals)
+
+	for i, segment := range segments {
+		if t.names[i] == "" {
+			if segment != t.segments[i] {
+				return nil, false
+			}
+
+			continue
+		}
+
+		vars[t.names[i]] = segment
+	}
+
+	return vars, true
+}
+
+// CustomIDVar Value of the placeholder of the component or modal route, "ban" for "action" with "confirm:{userID}:{action}"
+func (ctx *ConnectionContext) CustomIDVar(name string) (string, bool) {
+	value, ok := ctx.componentVars[name]
+	return value, ok
+}
+
+// CustomIDInt Value of the placeholder as an integer, false when missing or not an integer
+func (ctx *ConnectionContext) CustomIDInt(name string) (int, bool) {
+	value, ok := ctx.componentVars[name]
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// CustomIDBool Value of the placeholder as a boolean, false when missing or not a boolean
+func (ctx *ConnectionContext) CustomIDBool(name string) (value bool, ok bool) {
+	raw, ok := ctx.componentVars[name]
+	if !ok {
+		return false, false
+	}
+
+	value, err := strconv.ParseBool(raw)
+	return value, err == nil
+}
+
+// CustomIDSnowflake Value of the placeholder as a Snowflake, false when missing or not numeric
+func (ctx *ConnectionContext) CustomIDSnowflake(name string) (Snowflake, bool) {
+	value, ok := ctx.componentVars[name]
+	if !ok {
+		return "", false
+	}
+
+	id, err := ParseSnowflake(value)
+	return id, err == nil
+}
@@ -0,0 +1,106 @@
+package httpcord
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCustomIDRoundTrip(t *testing.T) {
+	// repeated Values deflated well below their plain size
+	repeated := []interface{}{strings.Repeat("role-", 12), strings.Repeat("role-", 12), Snowflake("80351110224678912")}
+
+	tests := []struct {
+		name       string
+		key        string
+		values     []interface{}
+		compressed bool
+		// customID Expected custom_id, empty when only the round trip is checked
+		customID string
+		decoded  []string
+	}{
+		{"values of every type", "confirm", []interface{}{Snowflake("80351110224678912"), "ban", 7, uint8(3), -2, 1.5, true},
+			false, "confirm:80351110224678912:ban:7:3:-2:1.5:true", []string{"confirm", "80351110224678912", "ban", "7", "3", "-2", "1.5", "true"}},
+		{"key alone", "refresh", nil, false, "refresh", []string{"refresh"}},
+		{"separator in a value", "tag", []interface{}{"a:b", "c"}, false, "tag:a%3Ab:c", []string{"tag", "a:b", "c"}},
+		{"escapes in a value", "tag", []interface{}{"100%", "%3A", "~x"}, false, "tag:100%25:%253A:%7Ex", []string{"tag", "100%", "%3A", "~x"}},
+		{"empty value", "tag", []interface{}{"", "x"}, false, "tag::x", []string{"tag", "", "x"}},
+		{"multi-segment key", "poll:vote", []interface{}{2}, false, "poll:vote:2", []string{"poll", "vote", "2"}},
+		{"compressed", "roles", repeated, true, "", []string{"roles", strings.Repeat("role-", 12), strings.Repeat("role-", 12), "80351110224678912"}},
+		{"compressed with escapes", "roles", []interface{}{strings.Repeat("a:b%~", 10)}, true, "", []string{"roles", strings.Repeat("a:b%~", 10)}},
+		{"short values left plain", "confirm", []interface{}{"80351110224678912", "ban"}, true, "confirm:80351110224678912:ban",
+			[]string{"confirm", "80351110224678912", "ban"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encode := EncodeCustomID
+			if test.compressed {
+				encode = EncodeCompressedCustomID
+			}
+
+			customID, err := encode(test.key, test.values...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.customID != "" && customID != test.customID {
+				t.Errorf("custom_id %q, want %q", customID, test.customID)
+			}
+
+			if test.customID == "" && !strings.HasPrefix(customID, test.key+CustomIDSeparator+customIDCompressed) {
+				t.Errorf("custom_id %q, want the values deflated", customID)
+			}
+
+			decoded, err := DecodeCustomID(customID)
+			if err != nil || !reflect.DeepEqual(decoded, test.decoded) {
+				t.Errorf("DecodeCustomID(%q) = %q, %v, want %q", customID, decoded, err, test.decoded)
+			}
+		})
+	}
+}
+
+func TestCustomIDErrors(t *testing.T) {
+	long := strings.Repeat("x", MaxCustomIDLength)
+
+	// random Values deflate poorly, they are left plain
+	var random []interface{}
+	for i := 0; i < 8; i++ {
+		random = append(random, SnowflakeFromUint64(uint64(i)*7919*104729*1299709+80351110224678912))
+	}
+
+	tests := []struct {
+		name   string
+		encode func() (string, error)
+		err    error
+	}{
+		{"at the limit", func() (string, error) { return EncodeCustomID(long[:MaxCustomIDLength-2], "y") }, nil},
+		{"over the limit", func() (string, error) { return EncodeCustomID(long[:MaxCustomIDLength-2], "yz") }, ErrCustomIDTooLong},
+		{"over the limit once escaped", func() (string, error) { return EncodeCustomID(long[:MaxCustomIDLength-4], "::") }, ErrCustomIDTooLong},
+		{"compressed values fitting", func() (string, error) { return EncodeCompressedCustomID("k", strings.Repeat("ab", 200)) }, nil},
+		{"compressed values over the limit", func() (string, error) { return EncodeCompressedCustomID("k", random...) }, ErrCustomIDTooLong},
+		{"unsupported value", func() (string, error) { return EncodeCustomID("k", []string{"a"}) }, ErrInvalidCustomID},
+		{"nil value", func() (string, error) { return EncodeCompressedCustomID("k", nil) }, ErrInvalidCustomID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			customID, err := test.encode()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("custom_id %q, error %v, want %v", customID, err, test.err)
+			}
+
+			var customIDErr *CustomIDError
+			if test.err == ErrCustomIDTooLong && (!errors.As(err, &customIDErr) || customIDErr.CustomID != customID || customIDErr.Length <= MaxCustomIDLength) {
+				t.Errorf("error %+v, want a CustomIDError of %q", customIDErr, customID)
+			}
+		})
+	}
+
+	for _, corrupt := range []string{"k:~!!", "k:~" + strings.Repeat("A", 12)} {
+		if segments, err := DecodeCustomID(corrupt); !errors.Is(err, ErrInvalidCustomID) {
+			t.Errorf("DecodeCustomID(%q) = %q, %v, want ErrInvalidCustomID", corrupt, segments, err)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package httpcord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	DefaultDebugDumpMaxFiles = 200
+	redactedValue            = "[REDACTED]"
+)
+
+type debugDumper struct {
+	dir      string
+	maxFiles int
+	seq      uint64
+	mu       sync.Mutex
+}
+
+type debugDumpRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Body    json.RawMessage     `json:"body"`
+}
+
+// debugDumpPart Part of a multipart body, the content of the files is elided
+type debugDumpPart struct {
+	Name        string          `json:"name"`
+	Filename    string          `json:"filename,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Size        int             `json:"size"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+type debugDumpResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers"`
+	Body    json.RawMessage     `json:"body,omitempty"`
+}
+
+// dumpResponseWriter Records everything written to the underlying ResponseWriter
+type dumpResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *dumpResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *dumpResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush Send the buffered response, the handlers flush the deferred responses to keep working after them
+func (w *dumpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap ResponseWriter recorded, for http.ResponseController
+func (w *dumpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func newDebugDumper(dir string, maxFiles int) (*debugDumper, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	if maxFiles <= 0 {
+		maxFiles = DefaultDebugDumpMaxFiles
+	}
+
+	d := &debugDumper{dir: dir, maxFiles: maxFiles}
+
+	// Continue numbering after any dump left by a previous run
+	for _, name := range d.files() {
+		if n, err := strconv.ParseUint(strings.SplitN(name, "-", 2)[0], 10, 64); err == nil && n > d.seq {
+			d.seq = n
+		}
+	}
+
+	return d, nil
+}
+
+// files Dump file names in the directory, oldest first
+func (d *debugDumper) files() []string {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.Contains(name, "-re") || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, _ := strconv.ParseUint(strings.SplitN(names[i], "-", 2)[0], 10, 64)
+		b, _ := strconv.ParseUint(strings.SplitN(names[j], "-", 2)[0], 10, 64)
+
+		if a == b {
+			return names[i] < names[j]
+		}
+
+		return a < b
+	})
+
+	return names
+}
+
+// Dump Write the request/response pair of one interaction and rotate old dumps
+func (d *debugDumper) Dump(r *http.Request, body []byte, w *dumpResponseWriter) error {
+	n := atomic.AddUint64(&d.seq, 1)
+	prefix := fmt.Sprintf("%04d", n)
+
+	req := debugDumpRequest{
+		Method:  r.Method,
+		URL:     redactURL(r.URL.String()),
+		Headers: redactHeaders(r.Header),
+		Body:    redactBody(body),
+	}
+
+	if err := d.writeFile(prefix+"-request.json", req); err != nil {
+		return err
+	}
+
+	res := debugDumpResponse{
+		Status:  w.status,
+		Headers: redactHeaders(w.Header()),
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/") {
+		res.Body = redactMultipart(contentType, w.body.Bytes())
+	} else {
+		res.Body = redactBody(w.body.Bytes())
+	}
+
+	if err := d.writeFile(prefix+"-response"+dumpKind(contentType)+".json", res); err != nil {
+		return err
+	}
+
+	d.rotate()
+	return nil
+}
+
+// dumpKind Suffix naming the dumps of the responses other than JSON after their content type, like
+// "-multipart" for the responses with files
+func dumpKind(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/json" {
+		return ""
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return "-multipart"
+	}
+
+	kind := mediaType[strings.LastIndexByte(mediaType, '/')+1:]
+	return "-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+
+		return '-'
+	}, kind)
+}
+
+func (d *debugDumper) writeFile(name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return d.writeRaw(name, b)
+}
+
+// writeRaw Write to a temporary file first so readers never observe partial dumps
+func (d *debugDumper) writeRaw(name string, b []byte) error {
+	tmp, err := os.CreateTemp(d.dir, ".dump-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(d.dir, name))
+}
+
+func (d *debugDumper) rotate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files := d.files()
+	for len(files) > d.maxFiles {
+		os.Remove(filepath.Join(d.dir, files[0]))
+		files = files[1:]
+	}
+}
+
+func redactHeaders(headers http.Header) map[string][]string {
+	redacted := make(map[string][]string, len(headers))
+
+	for key, values := range headers {
+		if http.CanonicalHeaderKey(key) == AuthorizationHeaderKey {
+			values = []string{redactedValue}
+		}
+
+		redacted[key] = values
+	}
+
+	return redacted
+}
+
+// redactBody Replace every "token" field and the tokens of the webhook URLs of a JSON body, non JSON bodies are
+// kept as a string
+func redactBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		b, _ := json.Marshal(string(body))
+		return b
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+// redactMultipart Parts of a multipart body with the JSON parts redacted like redactBody and the files elided
+func redactMultipart(contentType string, body []byte) json.RawMessage {
+	elided, _ := json.Marshal(fmt.Sprintf("[%d bytes of multipart body elided]", len(body)))
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["boundary"] == "" {
+		return elided
+	}
+
+	var parts []debugDumpPart
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return elided
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return elided
+		}
+
+		dumped := debugDumpPart{
+			Name:        part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Size:        len(content),
+		}
+
+		if dumped.Filename == "" {
+			dumped.Body = redactBody(content)
+		}
+
+		parts = append(parts, dumped)
+	}
+
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return elided
+	}
+
+	return b
+}
+
+// redactURL Replace the tokens of the webhook and interaction callback paths in the URL, see logRoute
+func redactURL(raw string) string {
+	if !strings.Contains(raw, "/webhooks/") && !strings.Contains(raw, "/interactions/") {
+		return raw
+	}
+
+	var query string
+	if i := strings.IndexByte(raw, '?'); i >= 0 {
+		query = raw[i:]
+	}
+
+	return logRoute(raw) + query
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return redactURL(value)
+	case map[string]interface{}:
+		for key, child := range value {
+			if key == "token" {
+				value[key] = redactedValue
+				continue
+			}
+
+			value[key] = redactValue(child)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactValue(child)
+		}
+	}
+
+	return v
+}
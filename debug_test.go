@@ -0,0 +1,210 @@
+package httpcord
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDumpToken = "c2VjcmV0LWludGVyYWN0aW9uLXRva2Vu"
+
+func multipartResponse(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	payload, err := writer.CreateFormField("payload_json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload.Write([]byte(`{"type":4,"data":{"content":"https://discord.com/api/v10/webhooks/1/` + testDumpToken + `/messages/@original","token":"` + testDumpToken + `"}}`))
+
+	file, err := writer.CreateFormFile("files[0]", "report.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Write([]byte("PNG binary content"))
+	writer.Close()
+
+	return writer.FormDataContentType(), buf.Bytes()
+}
+
+func TestDebugDumpRedacts(t *testing.T) {
+	multipartType, multipartBody := multipartResponse(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		response    []byte
+		absent      []string
+		present     []string
+		// file Name of the response dump
+		file string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			response:    []byte(`{"type":4,"data":{"token":"` + testDumpToken + `"}}`),
+			absent:      []string{testDumpToken, "Bot secret"},
+			present:     []string{redactedValue},
+			file:        "0001-response.json",
+		},
+		{
+			name:        "multipart",
+			contentType: multipartType,
+			response:    multipartBody,
+			absent:      []string{testDumpToken, "Bot secret", "PNG binary content"},
+			present:     []string{redactedValue, "report.png", "/webhooks/1/{token}/messages/@original"},
+			file:        "0001-response-multipart.json",
+		},
+		{
+			name:        "malformed multipart",
+			contentType: "multipart/form-data; boundary=missing",
+			response:    []byte("--other\r\n" + testDumpToken),
+			absent:      []string{testDumpToken},
+			present:     []string{"multipart body elided"},
+			file:        "0001-response-multipart.json",
+		},
+		{
+			name:        "plain text",
+			contentType: "text/plain; charset=utf-8",
+			response:    []byte("Service Unavailable"),
+			present:     []string{"Service Unavailable"},
+			file:        "0001-response-plain.json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dumper, err := newDebugDumper(dir, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/interactions/1/"+testDumpToken+"/callback?wait=true", nil)
+			r.Header.Set(AuthorizationHeaderKey, "Bot secret")
+
+			w := &dumpResponseWriter{ResponseWriter: httptest.NewRecorder()}
+			w.Header().Set("Content-Type", test.contentType)
+			w.Write(test.response)
+
+			if err := dumper.Dump(r, []byte(`{"type":1,"token":"`+testDumpToken+`"}`), w); err != nil {
+				t.Fatal(err)
+			}
+
+			var dumped strings.Builder
+			for _, name := range dumper.files() {
+				b, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				dumped.Write(b)
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, test.file)); err != nil {
+				t.Errorf("response dump %s: %v, dumped %q", test.file, err, dumper.files())
+			}
+
+			for _, absent := range test.absent {
+				if strings.Contains(dumped.String(), absent) {
+					t.Errorf("the dump contains %q:\n%s", absent, dumped.String())
+				}
+			}
+
+			for _, present := range test.present {
+				if !strings.Contains(dumped.String(), present) {
+					t.Errorf("the dump is missing %q:\n%s", present, dumped.String())
+				}
+			}
+		})
+	}
+}
+
+// plainResponseWriter ResponseWriter without Flush
+type plainResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestDumpResponseWriterFlush(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapped http.ResponseWriter
+		flushed bool
+	}{
+		{"flusher", httptest.NewRecorder(), true},
+		{"not a flusher", plainResponseWriter{httptest.NewRecorder()}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var w http.ResponseWriter = &dumpResponseWriter{ResponseWriter: test.wrapped}
+
+			// The deferred responses are flushed through this assertion
+			f, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("the dump writer is not a Flusher")
+			}
+
+			w.Write([]byte(`{"type":5}`))
+			f.Flush()
+
+			recorder, _ := test.wrapped.(*httptest.ResponseRecorder)
+			if flushed := recorder != nil && recorder.Flushed; flushed != test.flushed {
+				t.Errorf("flushed = %v, want %v", flushed, test.flushed)
+			}
+
+			if unwrapped := w.(interface{ Unwrap() http.ResponseWriter }).Unwrap(); unwrapped != test.wrapped {
+				t.Errorf("Unwrap() = %T, want the wrapped writer", unwrapped)
+			}
+		})
+	}
+}
+
+func TestDebugDumpVerifiedOnly(t *testing.T) {
+	dir := t.TempDir()
+	conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, DebugDumpDir: dir, DebugDumpMaxFiles: 2})
+
+	conn.Command("ban", func(ctx ConnectionContext) {
+		ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+	})
+
+	serve := func(r *http.Request) {
+		conn.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve(sign(commandBody()))
+
+	// Unsigned, forged and garbage requests are refused without a dump, the dump of the interaction is kept
+	unsigned := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(commandBody()))
+	serve(unsigned)
+
+	forged := sign(commandBody())
+	forged.Header.Set("X-Signature-Ed25519", strings.Repeat("0", 128))
+	serve(forged)
+
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("garbage")))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	if strings.Join(names, ",") != "0001-request.json,0001-response.json" {
+		t.Errorf("dumped %q, want only the verified interaction", names)
+	}
+}
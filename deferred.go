@@ -0,0 +1,192 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// ErrLateResponse The response was sent after a deferred one and can not be an edit, like a modal
+// (See ConnectionOptions.HandlerTimeout, ConnectionOptions.AutoDefer and ConnectionOptions.EditAfterDefer)
+var ErrLateResponse = errors.New("httpcord: response sent after the automatic defer")
+
+// ErrHandlerTimeout Deferred work exceeded ConnectionOptions.MaxHandlerDuration or the interaction token lifetime
+var ErrHandlerTimeout = errors.New("httpcord: handler timed out")
+
+// HandlerTimeoutError Reported to OnError when deferred work is cut off
+type HandlerTimeoutError struct {
+	// Command is the command path like "mod ban", or the custom_id for components
+	Command string
+	Elapsed time.Duration
+}
+
+func (e *HandlerTimeoutError) Error() string {
+	return fmt.Sprintf("httpcord: handler %q timed out after %s", e.Command, e.Elapsed.Round(time.Millisecond))
+}
+
+func (e *HandlerTimeoutError) Is(target error) bool {
+	return target == ErrHandlerTimeout
+}
+
+// commandPath Command, subcommand group and subcommand names of the interaction
+func commandPath(interaction *Interaction) string {
+	switch interaction.Type {
+	case ApplicationCommandInteraction, AutoCompleteInteraction:
+		data := interaction.ApplicationCommandData()
+		return strings.Join(routePath(&data), " ")
+	case MessageComponentInteraction:
+		return interaction.ComponentData().CustomID
+	case ModalSubmitInteraction:
+		return interaction.ModalSubmitData().CustomID
+	}
+
+	return ""
+}
+
+// RunDeferred Defer the reply when nothing was sent yet and run fn in background.
+// With ConnectionOptions.MaxWorkers the work is queued while the workers are busy and ErrBackpressure is returned
+// without deferring when the queue is full or fn would start after the interaction token expires.
+// The context of fn is cancelled after ConnectionOptions.MaxHandlerDuration or when the interaction token expires,
+// then OnError receives a HandlerTimeoutError. fn must honour its context: past the deadline it releases its worker
+// and stops being tracked by Shutdown, work ignoring the context keeps running and is counted in ConnectionStats.Overdue
+func (ctx *ConnectionContext) RunDeferred(fn func(c context.Context)) error {
+	expiry := ctx.Interaction.ID.CreatedAt().Add(InteractionTokenLifetime)
+
+	deadline := expiry
+	if max := ctx.options.MaxHandlerDuration; max > 0 && time.Now().Add(max).Before(deadline) {
+		deadline = time.Now().Add(max)
+	}
+
+	c, cancel := context.WithDeadline(context.Background(), deadline)
+	started := time.Now()
+	done := make(chan struct{})
+
+	ctx.objects.hold()
+
+	err := ctx.life.pool.submit(func() {
+		defer close(done)
+		defer ctx.objects.release()
+		defer func() {
+			if v := recover(); v != nil {
+				ctx.handleError(&PanicError{Value: v, Stack: debug.Stack()})
+			}
+		}()
+
+		fn(c)
+	}, deadline, expiry)
+
+	if err != nil {
+		ctx.objects.release()
+		cancel()
+		return err
+	}
+
+	if !ctx.Responded() {
+		ctx.DeferReplyInteraction()
+	}
+
+	ctx.life.background(func() {
+		defer cancel()
+
+		select {
+		case <-done:
+		case <-c.Done():
+			ctx.options.Logger.Warn("deferred handler timed out", "request", ctx.requestID, "command", commandPath(&ctx.Interaction))
+			ctx.handleError(&HandlerTimeoutError{Command: commandPath(&ctx.Interaction), Elapsed: time.Since(started)})
+		}
+	})
+
+	return nil
+}
+
+// deferAfter Defer the response with write when nothing was sent before the timeout, the deferred command replies carry
+// the flags and log reports the defer. stop waits for the defer being written
+func (ctx *ConnectionContext) deferAfter(timeout time.Duration, flags MessageFlag, log func(msg string, fields ...interface{}),
+	write func(response *InteractionResponse) error) (stop func()) {
+	timer := time.NewTimer(timeout)
+	done, finished := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+		}
+
+		response := &InteractionResponse{Type: DeferredChannelMessageWithSourceResponse}
+		if flags != 0 {
+			response.Data = &InteractionCallbackData{Flags: flags}
+		}
+
+		switch ctx.Interaction.Type {
+		case MessageComponentInteraction:
+			response.Type, response.Data = DeferredUpdateResponse, nil
+		case AutoCompleteInteraction:
+			// Autocompletes can not be deferred
+			return
+		}
+
+		ctx.state.mu.Lock()
+		if ctx.state.responded || ctx.state.clientGone {
+			ctx.state.mu.Unlock()
+			return
+		}
+
+		ctx.state.responded, ctx.state.autoDeferred, ctx.state.deferredAt = true, true, time.Now()
+		ctx.state.primaryResponse = response
+		ctx.state.mu.Unlock()
+
+		log("response deferred", "request", ctx.requestID, "command", commandPath(&ctx.Interaction), "after", timeout)
+		write(response)
+	}()
+
+	return func() {
+		timer.Stop()
+		close(done)
+		<-finished
+	}
+}
+
+// claim Record the response as the initial one when nothing was sent, gone reports the client left. Later responses
+// are edits of a deferred response when it was automatically deferred or with editAfterDefer
+func (s *interactionState) claim(response *InteractionResponse, editAfterDefer bool) (claimed, edit, gone bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.responded {
+		s.responded, s.primaryResponse = true, response
+		return true, false, s.clientGone
+	}
+
+	primary := s.primaryResponse
+	deferred := primary != nil && (primary.Type == DeferredChannelMessageWithSourceResponse || primary.Type == DeferredUpdateResponse)
+
+	return false, deferred && (s.autoDeferred || editAfterDefer), false
+}
+
+// editDeferred Send a response following a deferred one as an edit of the original response
+func (ctx *ConnectionContext) editDeferred(response *InteractionResponse) error {
+	switch response.Type {
+	case DeferredChannelMessageWithSourceResponse, DeferredUpdateResponse:
+		return nil
+	case ChannelMessageWithSourceResponse, UpdateMessageResponse:
+		if response.Data == nil {
+			return nil
+		}
+
+		if _, err := ctx.editOriginal(context.Background(), response.Data.WebhookEdit()); err != nil {
+			ctx.handleError(err)
+			return err
+		}
+
+		return nil
+	}
+
+	ctx.handleError(ErrLateResponse)
+	return ErrLateResponse
+}
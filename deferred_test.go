@@ -0,0 +1,318 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlerTimeout(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+
+	command := commandBody()
+	component := interactionBody(MessageComponentInteraction, `{"custom_id":"confirm","component_type":2}`)
+	autocomplete := interactionBody(AutoCompleteInteraction, `{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"sp","focused":true}]}`)
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		body    []byte
+		// slow The handler replies once its context is done
+		slow bool
+		// response Type of the initial response written to the request
+		response InteractionCallbackType
+		// handlerErr Error of the handler context while it runs
+		handlerErr error
+		// edits Replies sent as edits of the deferred response
+		edits []string
+	}{
+		{"fast command", timeout, command, false, ChannelMessageWithSourceResponse, nil, nil},
+		{"slow command", timeout, command, true, DeferredChannelMessageWithSourceResponse, context.DeadlineExceeded, []string{"done"}},
+		{"slow component", timeout, component, true, DeferredUpdateResponse, context.DeadlineExceeded, []string{"done"}},
+		{"slow autocomplete is not deferred", timeout, autocomplete, true, ApplicationCommandAutoCompleteResultResponse, context.DeadlineExceeded, nil},
+		{"disabled", 0, command, false, ChannelMessageWithSourceResponse, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, edits := followUpServer(t)
+
+			conn, sign := signedConnection(t, ConnectionOptions{HandlerTimeout: test.timeout, Logger: NopLogger})
+			conn.Client.BaseURL = client.BaseURL
+
+			var handlerCtx context.Context
+			var handlerErr error
+
+			handler := func(ctx ConnectionContext) {
+				handlerCtx = ctx.Context()
+
+				if test.slow {
+					select {
+					case <-handlerCtx.Done():
+					case <-time.After(time.Second):
+					}
+
+					// The context and the defer share the deadline, the defer goes out right after
+					for deadline := time.Now().Add(time.Second); !ctx.Responded() && ctx.Interaction.Type != AutoCompleteInteraction && time.Now().Before(deadline); {
+						time.Sleep(time.Millisecond)
+					}
+				}
+
+				handlerErr = handlerCtx.Err()
+
+				if ctx.Interaction.Type == AutoCompleteInteraction {
+					ctx.RespondAutocomplete([]ApplicationCommandOptionChoice{{Name: "spam", Value: "spam"}})
+					return
+				}
+
+				if err := ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"}); err != nil {
+					t.Errorf("reply after the defer: %v", err)
+				}
+			}
+
+			conn.Command("ban", handler).SetAutocomplete(handler)
+			conn.Component("confirm", handler)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%d %s: %v", w.Code, w.Body, err)
+			}
+
+			if response.Type != test.response {
+				t.Errorf("response type %d, want %d", response.Type, test.response)
+			}
+
+			if !errors.Is(handlerErr, test.handlerErr) {
+				t.Errorf("handler context error %v, want %v", handlerErr, test.handlerErr)
+			}
+
+			if handlerCtx.Err() == nil {
+				t.Error("the handler context is not cancelled after the handler returned")
+			}
+
+			if got := edits(); fmt.Sprint(got) != fmt.Sprint(test.edits) {
+				t.Errorf("edits %q, want %q", got, test.edits)
+			}
+		})
+	}
+}
+
+func TestAutoDefer(t *testing.T) {
+	const delay = 20 * time.Millisecond
+
+	command := commandBody()
+	component := interactionBody(MessageComponentInteraction, `{"custom_id":"confirm","component_type":2}`)
+
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		ephemeral bool
+		body      []byte
+		// slow The handler replies after the delay
+		slow     bool
+		response InteractionCallbackType
+		// flags Flags of the deferred response
+		flags MessageFlag
+		edits []string
+	}{
+		{"fast command", delay, false, command, false, ChannelMessageWithSourceResponse, 0, nil},
+		{"slow command", delay, false, command, true, DeferredChannelMessageWithSourceResponse, 0, []string{"done"}},
+		{"slow ephemeral command", delay, true, command, true, DeferredChannelMessageWithSourceResponse, EphemeralMessageFlag, []string{"done"}},
+		{"slow component", delay, true, component, true, DeferredUpdateResponse, 0, []string{"done"}},
+		{"disabled", 0, false, command, false, ChannelMessageWithSourceResponse, 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, edits := followUpServer(t)
+
+			conn, sign := signedConnection(t, ConnectionOptions{AutoDefer: test.delay, AutoDeferEphemeral: test.ephemeral, Logger: NopLogger})
+			conn.Client.BaseURL = client.BaseURL
+
+			var handlerErr error
+
+			handler := func(ctx ConnectionContext) {
+				if test.slow {
+					for deadline := time.Now().Add(time.Second); !ctx.Responded() && time.Now().Before(deadline); {
+						time.Sleep(time.Millisecond)
+					}
+				}
+
+				// The handler keeps running with its context after the defer
+				handlerErr = ctx.Context().Err()
+
+				if err := ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"}); err != nil {
+					t.Errorf("reply after the defer: %v", err)
+				}
+			}
+
+			conn.Command("ban", handler)
+			conn.Component("confirm", handler)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%d %s: %v", w.Code, w.Body, err)
+			}
+
+			if response.Type != test.response {
+				t.Errorf("response type %d, want %d", response.Type, test.response)
+			}
+
+			if test.slow && (response.Data != nil) != (test.flags != 0) {
+				t.Errorf("deferred response data %+v, want the flags %d", response.Data, test.flags)
+			} else if test.slow && response.Data != nil && response.Data.Flags != test.flags {
+				t.Errorf("deferred response flags %d, want %d", response.Data.Flags, test.flags)
+			}
+
+			if handlerErr != nil {
+				t.Errorf("handler context error %v, want it running", handlerErr)
+			}
+
+			if got := edits(); fmt.Sprint(got) != fmt.Sprint(test.edits) {
+				t.Errorf("edits %q, want %q", got, test.edits)
+			}
+		})
+	}
+}
+
+func TestMaxHandlerDuration(t *testing.T) {
+	const max = 20 * time.Millisecond
+
+	tests := []struct {
+		name string
+		max  time.Duration
+		// expiresIn Time left before the interaction token expires, a fresh token when zero
+		expiresIn time.Duration
+		// work How the deferred work runs: "fast" returns, "honours" waits for its context, "ignores" blocks past it
+		work string
+		// timeout The hook receives a HandlerTimeoutError
+		timeout bool
+		overdue int
+	}{
+		{"finished in time", max, 0, "fast", false, 0},
+		{"honours its context", max, 0, "honours", true, 0},
+		{"ignores its context", max, 0, "ignores", true, 1},
+		{"capped by the token expiry", 0, 30 * time.Millisecond, "honours", true, 0},
+		{"token expiry before the maximum", time.Hour, 30 * time.Millisecond, "honours", true, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := make(chan error, 4)
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				Logger:             NopLogger,
+				MaxWorkers:         1,
+				MaxHandlerDuration: test.max,
+				OnError:            func(ctx ConnectionContext, err error) { errs <- err },
+			})
+
+			stuck := make(chan struct{})
+			defer close(stuck)
+
+			// The work ignoring its context outlives the subtest and the loop variable
+			work := test.work
+			conn.Command("ban", func(ctx ConnectionContext) {
+				err := ctx.RunDeferred(func(c context.Context) {
+					switch work {
+					case "honours":
+						<-c.Done()
+					case "ignores":
+						<-stuck
+					}
+				})
+				if err != nil {
+					t.Errorf("RunDeferred() = %v", err)
+				}
+			})
+
+			body := commandBody()
+			if test.expiresIn > 0 {
+				created := time.Now().Add(test.expiresIn - InteractionTokenLifetime)
+
+				var fields map[string]json.RawMessage
+				json.Unmarshal(body, &fields)
+				fields["id"], _ = json.Marshal(SnowflakeFromUint64(uint64(created.UnixMilli()-DiscordEpoch) << 22))
+				body, _ = json.Marshal(fields)
+			}
+
+			started := time.Now()
+			conn.ServeHTTP(httptest.NewRecorder(), sign(body))
+
+			select {
+			case err := <-errs:
+				var timeoutErr *HandlerTimeoutError
+				if !test.timeout || !errors.As(err, &timeoutErr) || !errors.Is(err, ErrHandlerTimeout) {
+					t.Fatalf("OnError got %v, want a timeout = %v", err, test.timeout)
+				}
+
+				if timeoutErr.Command != "ban" || timeoutErr.Elapsed < 15*time.Millisecond || timeoutErr.Elapsed > time.Since(started) {
+					t.Errorf("HandlerTimeoutError %+v, want the command and the time it ran", timeoutErr)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if test.timeout {
+					t.Fatal("OnError was not called")
+				}
+			}
+
+			// The worker is free again, even when the work ignores its context
+			stats := conn.Stats()
+			for deadline := time.Now().Add(time.Second); (stats.Running != 0 || stats.Overdue != test.overdue) && time.Now().Before(deadline); stats = conn.Stats() {
+				time.Sleep(time.Millisecond)
+			}
+
+			if stats.Running != 0 || stats.Overdue != test.overdue {
+				t.Errorf("Running = %d, Overdue = %d, want the worker released and %d overdue", stats.Running, stats.Overdue, test.overdue)
+			}
+		})
+	}
+}
+
+func TestAutoDeferDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		autoDefer time.Duration
+		timeout   time.Duration
+		delay     time.Duration
+		// warn The defer is logged as a warning, the handlers ran out of time
+		warn bool
+	}{
+		{"disabled", 0, 0, 0, false},
+		{"auto defer only", time.Second, 0, time.Second, false},
+		{"handler timeout only", 0, 2 * time.Second, 2 * time.Second, true},
+		{"auto defer before the timeout", time.Second, 2 * time.Second, time.Second, false},
+		{"auto defer capped to the timeout", 3 * time.Second, 2 * time.Second, 2 * time.Second, true},
+		{"same duration", 2 * time.Second, 2 * time.Second, 2 * time.Second, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			options := ConnectionOptions{AutoDefer: test.autoDefer, HandlerTimeout: test.timeout, Logger: NewLogger(&logs, LogLevelDebug)}
+
+			delay, log := options.autoDeferDelay()
+			log("deferred")
+
+			if delay != test.delay {
+				t.Errorf("delay %s, want %s", delay, test.delay)
+			}
+
+			if warned := strings.Contains(logs.String(), "WARN deferred"); warned != test.warn {
+				t.Errorf("logged %q, want a warning %v", logs.String(), test.warn)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package httpcord
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DeprecationNotice First use of a deprecated symbol in the process
+type DeprecationNotice struct {
+	Symbol      string
+	Replacement string
+	// File and Line are the call site of the first use
+	File string
+	Line int
+}
+
+// deprecationRegistry Deprecated symbols used by the process, each one is logged once
+type deprecationRegistry struct {
+	mu      sync.Mutex
+	notices map[string]DeprecationNotice
+	logger  Logger
+}
+
+var deprecations = &deprecationRegistry{notices: make(map[string]DeprecationNotice), logger: DefaultLogger}
+
+// setLogger Log the notices through the logger of the last Connection created
+func (r *deprecationRegistry) setLogger(logger Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger = logger
+}
+
+// deprecated Record the use of symbol, skip is the number of frames above the deprecated function
+func deprecated(symbol, replacement string, skip int) {
+	r := deprecations
+
+	r.mu.Lock()
+	if _, ok := r.notices[symbol]; ok {
+		r.mu.Unlock()
+		return
+	}
+
+	notice := DeprecationNotice{Symbol: symbol, Replacement: replacement, File: "unknown"}
+	if _, file, line, ok := runtime.Caller(skip + 2); ok {
+		notice.File, notice.Line = file, line
+	}
+
+	r.notices[symbol] = notice
+	logger := r.logger
+	r.mu.Unlock()
+
+	logger.Warn("deprecated API used", "symbol", symbol, "replacement", replacement, "file", notice.File, "line", notice.Line)
+}
+
+// DeprecationReport Deprecated symbols used by the process so far, sorted by symbol
+func (c *Connection) DeprecationReport() []DeprecationNotice {
+	deprecations.mu.Lock()
+	defer deprecations.mu.Unlock()
+
+	notices := make([]DeprecationNotice, 0, len(deprecations.notices))
+	for _, notice := range deprecations.notices {
+		notices = append(notices, notice)
+	}
+
+	sort.Slice(notices, func(i, j int) bool {
+		return notices[i].Symbol < notices[j].Symbol
+	})
+
+	return notices
+}
@@ -0,0 +1,85 @@
+package httpcord
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// callerLine Line of the call to the function calling callerLine
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestDeprecationReport(t *testing.T) {
+	// The registry is process-global, the notices recorded before are restored after the test
+	deprecations.mu.Lock()
+	saved := deprecations.notices
+	deprecations.notices = make(map[string]DeprecationNotice)
+	deprecations.mu.Unlock()
+
+	defer func() {
+		deprecations.mu.Lock()
+		deprecations.notices = saved
+		deprecations.mu.Unlock()
+	}()
+
+	data := &ApplicationCommandInteractionData{Options: []ApplicationCommandOption{
+		{Name: "user", Type: UserApplicationCommandOptionType, Value: &User{ID: "6"}},
+	}}
+
+	ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction}, ContextConfig{
+		Respond:  func(*InteractionResponse) error { return nil },
+		Webhooks: &countingWebhooks{},
+	})
+	defer finish()
+
+	// Created last, the notices are logged through the logger of the last Connection
+	var logs bytes.Buffer
+	conn := newTestConnection(t, ConnectionOptions{Logger: NewLogger(&logs, LogLevelDebug)})
+
+	tests := []struct {
+		symbol      string
+		replacement string
+		// use Use the symbol and return the line of the use
+		use func() int
+	}{
+		{"ApplicationCommandInteractionData.GetOption", "ConnectionContext.BindOptions", func() int { data.GetOption("user", UserApplicationCommandOptionType, true); return callerLine() }},
+		{"ApplicationCommandInteractionData.UserValue", "ConnectionContext.BindOptions", func() int { data.UserValue("user", true); return callerLine() }},
+		{"ConnectionContext.ReplyPremiumRequired", "ConnectionContext.ReplyPremiumUpsell", func() int { ctx.ReplyPremiumRequired(); return callerLine() }},
+	}
+
+	lines := make(map[string]int)
+	for _, test := range tests {
+		lines[test.symbol] = test.use()
+
+		// Later uses are not reported again, even from another call site
+		for i := 0; i < 3; i++ {
+			test.use()
+		}
+		data.GetOption("user", UserApplicationCommandOptionType, false)
+	}
+
+	report := conn.DeprecationReport()
+	if len(report) != len(tests) {
+		t.Fatalf("DeprecationReport() = %+v, want %d notices", report, len(tests))
+	}
+
+	for i, test := range tests {
+		notice := report[i]
+
+		if notice.Symbol != test.symbol || notice.Replacement != test.replacement {
+			t.Errorf("notice %+v, want %s replaced by %s", notice, test.symbol, test.replacement)
+		}
+
+		if !strings.HasSuffix(notice.File, "deprecation_test.go") || notice.Line != lines[test.symbol] {
+			t.Errorf("%s attributed to %s:%d, want deprecation_test.go:%d", test.symbol, notice.File, notice.Line, lines[test.symbol])
+		}
+
+		if count := strings.Count(logs.String(), "symbol="+test.symbol+" "); count != 1 {
+			t.Errorf("%s logged %d times, want once:\n%s", test.symbol, count, logs.String())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package httpcord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// InsecureSkipVerifyEnv Environment variable that must be "1" for DevInsecureSkipVerify to take effect
+const InsecureSkipVerifyEnv = "HTTPCORD_INSECURE_SKIP_VERIFY"
+
+// ErrInsecureNotConfirmed DevInsecureSkipVerify was used without setting InsecureSkipVerifyEnv to "1"
+var ErrInsecureNotConfirmed = errors.New("httpcord: DevInsecureSkipVerify needs " + InsecureSkipVerifyEnv + "=1")
+
+type DevOption func(o *devOptions)
+
+type devOptions struct {
+	output    io.Writer
+	tolerance *time.Duration
+	insecure  bool
+}
+
+// DevOutput Where ConnectDev prints the test command (Defaults to stdout)
+func DevOutput(w io.Writer) DevOption {
+	return func(o *devOptions) {
+		o.output = w
+	}
+}
+
+// DevTimestampTolerance Replace ConnectionOptions.MaxTimestampSkew, 0 disables the timestamp check
+func DevTimestampTolerance(tolerance time.Duration) DevOption {
+	return func(o *devOptions) {
+		o.tolerance = &tolerance
+	}
+}
+
+// DevInsecureSkipVerify Accept requests without checking their signature. Only takes effect when the
+// InsecureSkipVerifyEnv environment variable is "1" too, otherwise ConnectDev fails with ErrInsecureNotConfirmed.
+// Every accepted request is logged as a warning
+func DevInsecureSkipVerify() DevOption {
+	return func(o *devOptions) {
+		o.insecure = true
+	}
+}
+
+// ConnectDev Serve like Connect for local development behind tunnels like ngrok or Cloudflare Tunnel.
+// Requests signed with a key generated on start are accepted besides the Discord ones, for every application,
+// a curl command sending a signed ping with it is printed for manual testing
+func (c *Connection) ConnectDev(address string, opts ...DevOption) error {
+	o := devOptions{output: os.Stdout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.insecure && os.Getenv(InsecureSkipVerifyEnv) != "1" {
+		return ErrInsecureNotConfirmed
+	}
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+
+	verifier := c.verifier
+	verifier.devKeys = append(append([]ed25519.PublicKey{}, verifier.devKeys...), pub)
+	verifier.insecure = o.insecure
+
+	if o.tolerance != nil {
+		verifier.tolerance = *o.tolerance
+	}
+
+	if o.insecure {
+		verifier.logger.Warn("SIGNATURE VERIFICATION DISABLED, never expose this endpoint outside development", "address", address)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	application := Snowflake("0")
+	if len(c.expected) > 0 {
+		application = c.expected[0]
+	}
+
+	fmt.Fprintln(o.output, devCurlCommand(listener.Addr(), priv, application))
+
+	return c.withVerifier(verifier).Serve(listener)
+}
+
+// devCurlCommand Command sending a ping of the application signed with key, valid while the timestamp is within
+// the tolerance
+func devCurlCommand(addr net.Addr, key ed25519.PrivateKey, application Snowflake) string {
+	host := addr.String()
+	if tcp, ok := addr.(*net.TCPAddr); ok && tcp.IP.IsUnspecified() {
+		host = net.JoinHostPort("localhost", strconv.Itoa(tcp.Port))
+	}
+
+	now := time.Now()
+	id := SnowflakeFromUint64(uint64(now.UnixMilli()-DiscordEpoch) << 22)
+	body := fmt.Sprintf(`{"id":"%s","application_id":"%s","type":1,"token":"dev","version":1}`, id, application)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := hex.EncodeToString(ed25519.Sign(key, []byte(timestamp+body)))
+
+	return fmt.Sprintf("curl -X POST http://%s/ -H 'Content-Type: application/json' -H 'X-Signature-Timestamp: %s' -H 'X-Signature-Ed25519: %s' -d '%s'",
+		host, timestamp, signature, body)
+}
@@ -0,0 +1,187 @@
+package httpcord
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer Buffer of the logs written by the server goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+var devCurlPattern = regexp.MustCompile(`^curl -X POST (\S+) -H 'Content-Type: application/json' -H 'X-Signature-Timestamp: (\d+)' -H 'X-Signature-Ed25519: ([0-9a-f]+)' -d '(.*)'$`)
+
+// devPing Request of the printed curl command
+type devPing struct {
+	url, timestamp, signature, body string
+}
+
+func (p devPing) send(t *testing.T) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, p.url, strings.NewReader(p.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Timestamp", p.timestamp)
+	req.Header.Set("X-Signature-Ed25519", p.signature)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { res.Body.Close() })
+
+	return res
+}
+
+// startDev Run ConnectDev on a free port until the end of the test and parse the printed command
+func startDev(t *testing.T, conn *Connection, opts ...DevOption) devPing {
+	t.Helper()
+
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- conn.ConnectDev("127.0.0.1:0", append(opts, DevOutput(w))...)
+		w.Close()
+	}()
+
+	t.Cleanup(func() {
+		conn.Shutdown(context.Background())
+
+		if err := <-done; err != nil {
+			t.Errorf("ConnectDev() = %v", err)
+		}
+	})
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil {
+		t.Fatalf("no command printed: %v", err)
+	}
+
+	go io.Copy(io.Discard, r)
+
+	match := devCurlPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		t.Fatalf("printed %q, want a curl command", line)
+	}
+
+	return devPing{url: match[1], timestamp: match[2], signature: match[3], body: match[4]}
+}
+
+func TestConnectDevInsecureNeedsConfirmation(t *testing.T) {
+	for _, value := range []string{"", "0", "true"} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv(InsecureSkipVerifyEnv, value)
+
+			conn := newTestConnection(t, ConnectionOptions{Logger: NopLogger})
+
+			var out bytes.Buffer
+			if err := conn.ConnectDev("127.0.0.1:0", DevInsecureSkipVerify(), DevOutput(&out)); !errors.Is(err, ErrInsecureNotConfirmed) {
+				t.Errorf("ConnectDev() = %v, want ErrInsecureNotConfirmed", err)
+			}
+
+			if out.Len() != 0 {
+				t.Errorf("printed %q before refusing", out.String())
+			}
+		})
+	}
+}
+
+func TestConnectDevInsecureLogsEveryRequest(t *testing.T) {
+	t.Setenv(InsecureSkipVerifyEnv, "1")
+
+	var logs syncBuffer
+	conn := newTestConnection(t, ConnectionOptions{Logger: NewLogger(&logs, LogLevelWarn)})
+
+	ping := startDev(t, conn, DevInsecureSkipVerify())
+
+	if !strings.Contains(logs.String(), "SIGNATURE VERIFICATION DISABLED, never expose") {
+		t.Errorf("logs %q, want the start warning", logs.String())
+	}
+
+	unsigned := devPing{url: ping.url, body: ping.body}
+	forged := devPing{url: ping.url, timestamp: ping.timestamp, signature: strings.Repeat("00", ed25519.SignatureSize), body: ping.body}
+
+	for _, p := range []devPing{unsigned, forged} {
+		if res := p.send(t); res.StatusCode != http.StatusOK {
+			t.Errorf("status %d, want the unverified request accepted", res.StatusCode)
+		}
+	}
+
+	if n := strings.Count(logs.String(), "SIGNATURE VERIFICATION DISABLED, accepting an unverified request"); n != 2 {
+		t.Errorf("%d warnings for 2 requests, logs %q", n, logs.String())
+	}
+}
+
+func TestConnectDevPrintedPing(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey := hex.EncodeToString(public)
+
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+	}{
+		{"no application check", ConnectionOptions{}},
+		{"application ID", ConnectionOptions{ApplicationID: "1"}},
+		{"application IDs", ConnectionOptions{ApplicationIDs: []Snowflake{"1", "2"}}},
+		{"applications with their own key", ConnectionOptions{Applications: []ApplicationCredentials{{ID: "1", PublicKey: otherKey}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.Logger = NopLogger
+			conn := newTestConnection(t, test.options)
+
+			ping := startDev(t, conn, DevTimestampTolerance(time.Minute))
+
+			res := ping.send(t)
+			body, _ := io.ReadAll(res.Body)
+
+			if res.StatusCode != http.StatusOK || !strings.Contains(string(body), `"type":1`) {
+				t.Errorf("ping answered %d %s, want a pong", res.StatusCode, body)
+			}
+
+			ping.signature = strings.Repeat("00", ed25519.SignatureSize)
+
+			if res := ping.send(t); res.StatusCode != http.StatusUnauthorized {
+				t.Errorf("forged ping answered %d, want 401", res.StatusCode)
+			}
+		})
+	}
+}
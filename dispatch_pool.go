@@ -0,0 +1,157 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDispatchQueueTimeout Time an interaction waits for a dispatch worker unless
+// ConnectionOptions.DispatchQueueTimeout is set, within the 3 seconds Discord gives to respond
+const DefaultDispatchQueueTimeout = 2 * time.Second
+
+// ErrDispatchBusy The interaction was refused because the handlers are saturated (See DispatchBusyError)
+var ErrDispatchBusy = errors.New("httpcord: interaction dispatch saturated")
+
+// DispatchBusyError Returned when no dispatch worker was free in time or the command already runs
+// CommandRoute.MaxConcurrency times, the handler did not run
+type DispatchBusyError struct {
+	// Command is the route at its MaxConcurrency, empty when every dispatch worker was busy
+	Command string
+}
+
+func (e *DispatchBusyError) Error() string {
+	if e.Command == "" {
+		return "httpcord: every dispatch worker is busy"
+	}
+
+	return "httpcord: command " + e.Command + " is at its concurrency limit"
+}
+
+func (e *DispatchBusyError) Is(target error) bool {
+	return target == ErrDispatchBusy
+}
+
+var DispatchBusyMessages = Dictionary{
+	EnglishUSLocale:    "The bot is busy right now, please try again in a moment.",
+	EnglishGBLocale:    "The bot is busy right now, please try again in a moment.",
+	PortugueseBRLocale: "O bot está ocupado agora, tente novamente em instantes.",
+	SpanishESLocale:    "El bot está ocupado ahora, inténtalo de nuevo en un momento.",
+	FrenchLocale:       "Le bot est occupé pour le moment, réessayez dans un instant.",
+	GermanLocale:       "Der Bot ist gerade beschäftigt, bitte versuche es gleich noch einmal.",
+}
+
+// dispatchPool Run at most workers handlers at once, the other interactions wait in a bounded queue
+type dispatchPool struct {
+	slots    chan struct{}
+	mu       sync.Mutex
+	waiting  int
+	maxQueue int
+	timeout  time.Duration
+}
+
+// newDispatchPool Pool of the workers, nil when the dispatch is not bounded
+func newDispatchPool(workers, maxQueue int, timeout time.Duration) *dispatchPool {
+	if workers <= 0 {
+		return nil
+	}
+
+	return &dispatchPool{slots: make(chan struct{}, workers), maxQueue: maxQueue, timeout: timeout}
+}
+
+// acquire Take a worker, waiting at most the queue timeout. The release function is nil when the interaction is
+// refused with a DispatchBusyError
+func (p *dispatchPool) acquire(ctx context.Context) func() {
+	if p == nil {
+		return func() {}
+	}
+
+	release := func() { <-p.slots }
+
+	select {
+	case p.slots <- struct{}{}:
+		return release
+	default:
+	}
+
+	p.mu.Lock()
+	if p.maxQueue > 0 && p.waiting >= p.maxQueue {
+		p.mu.Unlock()
+		return nil
+	}
+
+	p.waiting++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case p.slots <- struct{}{}:
+		return release
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// run Run fn with a worker, false when the interaction is refused. The worker is released even when fn panics,
+// like the handlers do with DisablePanicRecovery
+func (p *dispatchPool) run(ctx context.Context, fn func()) bool {
+	release := p.acquire(ctx)
+	if release == nil {
+		return false
+	}
+	defer release()
+
+	fn()
+	return true
+}
+
+// stats Busy workers and waiting interactions
+func (p *dispatchPool) stats() (running, waiting int) {
+	if p == nil {
+		return 0, 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.slots), p.waiting
+}
+
+// Concurrency Refuse the interactions of the command with a DispatchBusyError while it already runs n times, 0
+// removes the limit
+func (r *CommandRoute) Concurrency(n int) *CommandRoute {
+	r.MaxConcurrency = n
+	return r
+}
+
+// acquire Count a run of the route, false when it is at its MaxConcurrency
+func (r *CommandRoute) acquire() bool {
+	if r.MaxConcurrency <= 0 {
+		return true
+	}
+
+	if atomic.AddInt32(&r.running, 1) > int32(r.MaxConcurrency) {
+		atomic.AddInt32(&r.running, -1)
+		return false
+	}
+
+	return true
+}
+
+func (r *CommandRoute) release() {
+	if r.MaxConcurrency > 0 {
+		atomic.AddInt32(&r.running, -1)
+	}
+}
@@ -0,0 +1,80 @@
+package httpcord
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDispatchPoolReleasesOnPanic(t *testing.T) {
+	pool := newDispatchPool(1, 0, 10*time.Millisecond)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("the panic was not propagated")
+			}
+		}()
+
+		pool.run(context.Background(), func() { panic("handler") })
+	}()
+
+	if running, _ := pool.stats(); running != 0 {
+		t.Fatalf("%d workers still busy after the panic", running)
+	}
+
+	ran := false
+	if !pool.run(context.Background(), func() { ran = true }) || !ran {
+		t.Fatal("the worker leaked by the panic refused the next interaction")
+	}
+}
+
+func TestDispatchPoolRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		busy     int
+		freed    bool
+		ctx      func() context.Context
+		admitted bool
+	}{
+		{"unbounded", 0, 0, false, context.Background, true},
+		{"free worker", 2, 1, false, context.Background, true},
+		{"queue timeout", 1, 1, false, context.Background, false},
+		{"worker freed while waiting", 1, 1, true, context.Background, true},
+		{"request canceled", 1, 1, false, func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool := newDispatchPool(test.workers, 0, 10*time.Millisecond)
+
+			release := make(chan struct{})
+			started := make(chan struct{}, test.busy)
+			for i := 0; i < test.busy; i++ {
+				go pool.run(context.Background(), func() {
+					started <- struct{}{}
+					<-release
+				})
+				<-started
+			}
+
+			if test.freed {
+				go func() {
+					time.Sleep(2 * time.Millisecond)
+					close(release)
+				}()
+			} else {
+				defer close(release)
+			}
+
+			if admitted := pool.run(test.ctx(), func() {}); admitted != test.admitted {
+				t.Errorf("admitted = %v, want %v", admitted, test.admitted)
+			}
+		})
+	}
+}
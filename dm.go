@@ -0,0 +1,97 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"httpcord/endpoints"
+)
+
+// ErrDMsClosed The user does not accept DMs from the application, they closed their DMs or share no guild with it
+var ErrDMsClosed = errors.New("httpcord: the user does not accept direct messages")
+
+// DMsClosedError Returned by DMUser when Discord refuses the message with CannotSendToUserErrorCode
+type DMsClosedError struct {
+	UserID Snowflake
+	Err    *DiscordAPIError
+}
+
+func (e *DMsClosedError) Error() string {
+	return fmt.Sprintf("httpcord: user %s does not accept direct messages", e.UserID)
+}
+
+func (e *DMsClosedError) Is(target error) bool {
+	return target == ErrDMsClosed
+}
+
+func (e *DMsClosedError) Unwrap() error {
+	return e.Err
+}
+
+type dmCreate struct {
+	RecipientID Snowflake `json:"recipient_id"`
+}
+
+// CreateDM DM channel with the user, Discord returns the existing channel when there is one.
+// The channel is kept in the Cache per user (See WithFresh)
+func (c *RestClient) CreateDM(ctx context.Context, userID Snowflake, opts ...CallOption) (*Channel, error) {
+	if cache := c.cached(opts); cache != nil {
+		if channel, ok := cache.DMChannel(userID); ok {
+			return channel, nil
+		}
+	}
+
+	var channel Channel
+	if err := c.Do(ctx, http.MethodPost, endpoints.UserChannels(), &dmCreate{RecipientID: userID}, &channel, opts...); err != nil {
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		c.Cache.SetDMChannel(userID, &channel)
+	}
+
+	return &channel, nil
+}
+
+// SendDM Send the message in the DM channel with the user, returns a DMsClosedError when the user does not accept it
+func (c *RestClient) SendDM(ctx context.Context, userID Snowflake, data *MessageCreate) (*Message, error) {
+	channel, err := c.CreateDM(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := c.CreateMessage(ctx, channel.ID, data)
+	if err != nil {
+		var apiErr *DiscordAPIError
+		if errors.As(err, &apiErr) && apiErr.Code == CannotSendToUserErrorCode {
+			return nil, &DMsClosedError{UserID: userID, Err: apiErr}
+		}
+
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && c.Cache != nil {
+			// The cached channel is gone, the next DM creates it again
+			c.Cache.InvalidateDMChannel(userID)
+		}
+
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// DMUser Send the message in the DM channel with the user of the interaction, created or reused from the Cache of
+// the client. Like EditReply the call is not bound to Context, it works after the response and from deferred work.
+// Returns ErrDMsClosed when the user does not accept it, handlers may fall back to an ephemeral reply:
+//
+//	if _, err := ctx.DMUser(data); errors.Is(err, httpcord.ErrDMsClosed) {
+//		ctx.FollowUp(&httpcord.WebhookEdit{Content: data.Content, Flags: httpcord.EphemeralMessageFlag})
+//	}
+func (ctx *ConnectionContext) DMUser(data *MessageCreate) (*Message, error) {
+	user := ctx.User()
+	if user == nil {
+		return nil, errors.New("httpcord: the interaction has no user")
+	}
+
+	return ctx.client.SendDM(context.Background(), user.ID, data)
+}
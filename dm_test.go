@@ -0,0 +1,62 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDMUserAfterHandlerReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/@me/channels"):
+			w.Write([]byte(`{"id":"10","type":1}`))
+		case strings.HasSuffix(r.URL.Path, "/channels/10/messages"):
+			w.Write([]byte(`{"id":"11","channel_id":"10","content":"hi"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":10003,"message":"Unknown Channel"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	tests := []struct {
+		name     string
+		returned bool
+	}{
+		{"during the handler", false},
+		{"after the handler returned", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token", User: &User{ID: "4"}}
+			ctx, finish := NewContext(interaction, ContextConfig{Client: client, Webhooks: &countingWebhooks{}, Context: request})
+			defer finish()
+
+			if test.returned {
+				cancel()
+			}
+
+			message, err := ctx.DMUser(&MessageCreate{Content: "hi"})
+			if err != nil {
+				t.Fatalf("DMUser() = %v", err)
+			}
+
+			if message.ChannelID != "10" {
+				t.Errorf("sent in %s, want the DM channel 10", message.ChannelID)
+			}
+		})
+	}
+}
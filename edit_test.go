@@ -0,0 +1,166 @@
+package httpcord_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestDeferredEditRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		options httpcord.ConnectionOptions
+		// deferred The edit follows a defer
+		deferred bool
+		// script Codes of the 404s answered before the edit succeeds
+		script   []int
+		attempts int
+		// err Code of the error returned, 0 when the edit succeeds
+		err int
+	}{
+		{"unknown webhook twice", httpcord.ConnectionOptions{}, true, []int{httpcord.UnknownWebhookErrorCode, httpcord.UnknownWebhookErrorCode}, 3, 0},
+		{"unknown interaction twice", httpcord.ConnectionOptions{}, true,
+			[]int{httpcord.UnknownInteractionErrorCode, httpcord.UnknownInteractionErrorCode}, 3, 0},
+		{"retry budget spent", httpcord.ConnectionOptions{}, true, []int{httpcord.UnknownWebhookErrorCode, httpcord.UnknownWebhookErrorCode,
+			httpcord.UnknownWebhookErrorCode, httpcord.UnknownWebhookErrorCode}, 4, httpcord.UnknownWebhookErrorCode},
+		{"not deferred", httpcord.ConnectionOptions{}, false, []int{httpcord.UnknownWebhookErrorCode}, 1, httpcord.UnknownWebhookErrorCode},
+		{"other 404", httpcord.ConnectionOptions{}, true, []int{10008}, 1, 10008},
+		{"retry disabled", httpcord.ConnectionOptions{DeferEditRetryWindow: -1}, true, []int{httpcord.UnknownWebhookErrorCode}, 1,
+			httpcord.UnknownWebhookErrorCode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t)
+			for _, code := range test.script {
+				fake.ExpectEditOriginal(httpcordtest.TestApplicationID, httpcordtest.TestToken).RespondError(http.StatusNotFound, code, "Unknown").Times(1)
+			}
+
+			fake.Allow(http.MethodPatch, "/webhooks/*/*/messages/@original").RespondWith(httpcord.Message{ID: "9", Content: "done"})
+
+			ctx, finish := httpcord.NewContext(*httpcordtest.NewCommandInteraction("ban"), httpcord.ContextConfig{
+				Options: test.options,
+				Respond: func(*httpcord.InteractionResponse) error { return nil },
+				Client:  fake.Client(),
+			})
+			defer finish()
+
+			if test.deferred {
+				if err := ctx.DeferReplyInteraction(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			message, err := ctx.EditReply(&httpcord.WebhookEdit{Content: "done"})
+
+			if edits := len(fake.RequestsTo(http.MethodPatch, "/webhooks/*/*/messages/@original")); edits != test.attempts {
+				t.Errorf("%d edits sent, want %d", edits, test.attempts)
+			}
+
+			var apiErr *httpcord.DiscordAPIError
+			switch {
+			case test.err == 0 && (err != nil || message == nil || message.ID != "9"):
+				t.Errorf("EditReply() = %+v, %v, want the edited message", message, err)
+			case test.err != 0 && (!errors.As(err, &apiErr) || apiErr.Code != test.err):
+				t.Errorf("EditReply() error %v, want the code %d", err, test.err)
+			}
+		})
+	}
+}
+
+func TestEditKeepsAttachments(t *testing.T) {
+	original := httpcord.Message{ID: "9", Attachments: []*httpcord.Attachment{
+		{ID: "1100000000000000001", Filename: "chart.png", URL: "https://cdn.discordapp.com/attachments/3/1100000000000000001/chart.png"},
+		{ID: "1100000000000000002", Filename: "data.json", URL: "https://cdn.discordapp.com/attachments/3/1100000000000000002/data.json"},
+	}}
+
+	newFile := func() *httpcord.DiscordFile {
+		return &httpcord.DiscordFile{Buffer: bytes.NewBufferString("PNG"), Filename: "week.png", Description: "This week"}
+	}
+
+	tests := []struct {
+		name string
+		edit func(kept []*httpcord.Attachment) *httpcord.WebhookEdit
+		// attachments Attachments array of the payload as id/filename/description, nil when the field is absent
+		attachments []map[string]interface{}
+		files       []string
+	}{
+		{"kept attachments and a new file", func(kept []*httpcord.Attachment) *httpcord.WebhookEdit {
+			return (&httpcord.WebhookEdit{Content: "updated", Files: []*httpcord.DiscordFile{newFile()}}).KeepAttachments(kept...)
+		}, []map[string]interface{}{
+			{"id": "1100000000000000001"},
+			{"id": "1100000000000000002"},
+			{"id": "0", "filename": "week.png", "description": "This week"},
+		}, []string{"files[0]"}},
+		{"one kept attachment without files", func(kept []*httpcord.Attachment) *httpcord.WebhookEdit {
+			return (&httpcord.WebhookEdit{Content: "updated"}).KeepAttachments(kept[1])
+		}, []map[string]interface{}{{"id": "1100000000000000002"}}, nil},
+		{"new file replacing the attachments", func([]*httpcord.Attachment) *httpcord.WebhookEdit {
+			return &httpcord.WebhookEdit{Content: "updated", Files: []*httpcord.DiscordFile{newFile()}}
+		}, []map[string]interface{}{{"id": "0", "filename": "week.png", "description": "This week"}}, []string{"files[0]"}},
+		{"attachments untouched", func([]*httpcord.Attachment) *httpcord.WebhookEdit {
+			return &httpcord.WebhookEdit{Content: "updated"}
+		}, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t)
+			fake.Allow(http.MethodGet, "/webhooks/*/*/messages/@original").RespondWith(original)
+			fake.Allow(http.MethodPatch, "/webhooks/*/*/messages/@original").RespondWith(httpcord.Message{ID: "9"})
+
+			ctx, finish := httpcord.NewContext(*httpcordtest.NewCommandInteraction("report"), httpcord.ContextConfig{
+				Respond: func(*httpcord.InteractionResponse) error { return nil },
+				Client:  fake.Client(),
+			})
+			defer finish()
+
+			message, err := ctx.FetchOriginalReply()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			edit := test.edit(message.Attachments)
+			listed := len(edit.Attachments)
+
+			if _, err := ctx.EditReply(edit); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(edit.Attachments) != listed {
+				t.Errorf("EditReply() changed the Attachments of the caller to %d, want %d", len(edit.Attachments), listed)
+			}
+
+			edits := fake.RequestsTo(http.MethodPatch, "/webhooks/*/*/messages/@original")
+			if len(edits) != 1 {
+				t.Fatalf("%d edits sent, want 1", len(edits))
+			}
+
+			var payload struct {
+				Attachments []map[string]interface{} `json:"attachments"`
+			}
+
+			if err := edits[0].Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(payload.Attachments, test.attachments) {
+				t.Errorf("attachments %v, want %v", payload.Attachments, test.attachments)
+			}
+
+			var files []string
+			for _, file := range edits[0].Files {
+				files = append(files, file.Field)
+			}
+
+			if !reflect.DeepEqual(files, test.files) {
+				t.Errorf("file parts %v, want %v", files, test.files)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package httpcord
 
+import "unicode/utf8"
+
+const (
+	MaxEmbedsPerMessage = 10
+	// MaxEmbedsCharacters Maximum characters of all the embeds of a message combined
+	MaxEmbedsCharacters = 6000
+	MaxContentLength    = 2000
+)
+
 type Embed struct {
 	Title string `json:"title,omitempty"`
 	// Always "rich" for webhook embeds
@@ -7,7 +16,7 @@ type Embed struct {
 	Description string          `json:"description,omitempty"`
 	URL         string          `json:"url,omitempty"`
 	Timestamp   Time            `json:"timestamp"`
-	Color       int             `json:"color,omitempty"`
+	Color       *Color          `json:"color,omitempty"`
 	Footer      *EmbedFooter    `json:"footer,omitempty"`
 	Image       *EmbedImage     `json:"image,omitempty"`
 	Thumbnail   *EmbedThumbnail `json:"thumbnail,omitempty"`
@@ -85,8 +94,10 @@ func (e *Embed) SetTimestamp(timestamp Time) *Embed {
 	return e
 }
 
-func (e *Embed) SetColor(color int) *Embed {
-	e.Color = color
+// SetColor Color of the embed, integer constants like 0x5865F2 are accepted as they are (See ColorFromHex). Black is
+// sent as 0, embeds without SetColor have no color
+func (e *Embed) SetColor(color Color) *Embed {
+	e.Color = &color
 	return e
 }
 
@@ -114,3 +125,22 @@ func (e *Embed) AddField(field *EmbedField) *Embed {
 	e.Fields = append(e.Fields, field)
 	return e
 }
+
+// Length Characters counted by Discord against MaxEmbedsCharacters
+func (e *Embed) Length() int {
+	length := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+
+	if e.Footer != nil {
+		length += utf8.RuneCountInString(e.Footer.Text)
+	}
+
+	if e.Author != nil {
+		length += utf8.RuneCountInString(e.Author.Name)
+	}
+
+	for _, field := range e.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+
+	return length
+}
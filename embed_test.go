@@ -0,0 +1,39 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEmbedSetColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		embed *Embed
+		json  string
+	}{
+		{"unset", NewEmbedBuilder(), ""},
+		{"color", NewEmbedBuilder().SetColor(BlurpleColor), `"color":5793266`},
+		{"int", NewEmbedBuilder().SetColor(0xED4245), `"color":15548997`},
+		{"color variable", NewEmbedBuilder().SetColor(Color(0x57F287)), `"color":5763719`},
+		{"black", NewEmbedBuilder().SetColor(BlackColor), `"color":0`},
+		{"black int", NewEmbedBuilder().SetColor(0), `"color":0`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.embed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.json == "" && strings.Contains(string(b), `"color"`) {
+				t.Errorf("Marshal = %s, want no color", b)
+			}
+
+			if test.json != "" && !strings.Contains(string(b), test.json) {
+				t.Errorf("Marshal = %s, want %s", b, test.json)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package httpcord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PlainText Render the embed as message content: author, title, description, fields as "name: value" lines,
+// footer and URL. With maxLen over 0 the text is cut to maxLen characters keeping the URL,
+// only the description and field values are truncated and fields that do not fit with their name are dropped
+func (e *Embed) PlainText(maxLen int) string {
+	var lines []string
+
+	if e.Author != nil && e.Author.Name != "" {
+		lines = append(lines, e.Author.Name)
+	}
+
+	if e.Title != "" {
+		lines = append(lines, "**"+e.Title+"**")
+	}
+
+	url := ""
+	if e.URL != "" {
+		url = "<" + e.URL + ">"
+	}
+
+	if maxLen <= 0 {
+		if e.Description != "" {
+			lines = append(lines, e.Description)
+		}
+
+		for _, field := range e.Fields {
+			lines = append(lines, field.Name+": "+field.Value)
+		}
+
+		if e.Footer != nil && e.Footer.Text != "" {
+			lines = append(lines, e.Footer.Text)
+		}
+
+		if url != "" {
+			lines = append(lines, url)
+		}
+
+		return strings.Join(lines, "\n")
+	}
+
+	budget := maxLen
+	if url != "" && utf8.RuneCountInString(url) < budget {
+		budget -= utf8.RuneCountInString(url) + 1
+	} else {
+		url = ""
+	}
+
+	text := &plainTextBuilder{budget: budget}
+
+	for _, line := range lines {
+		if !text.line(line) {
+			text.truncated(line)
+		}
+	}
+
+	if e.Description != "" && !text.line(e.Description) {
+		text.truncated(e.Description)
+	}
+
+	for _, field := range e.Fields {
+		if text.line(field.Name + ": " + field.Value) {
+			continue
+		}
+
+		// The name is never cut, the field is dropped when it does not fit with part of its value
+		if text.fits(field.Name + ": " + "x…") {
+			text.truncated(field.Name + ": " + field.Value)
+		}
+
+		text.full = true
+	}
+
+	if e.Footer != nil && e.Footer.Text != "" {
+		text.line(e.Footer.Text)
+	}
+
+	if url != "" {
+		text.lines = append(text.lines, url)
+	}
+
+	return strings.Join(text.lines, "\n")
+}
+
+// plainTextBuilder Lines of PlainText within the characters budget
+type plainTextBuilder struct {
+	lines  []string
+	length int
+	budget int
+	// full stops adding lines once something was cut to keep the order
+	full bool
+}
+
+func (b *plainTextBuilder) cost(line string) int {
+	cost := utf8.RuneCountInString(line)
+	if len(b.lines) > 0 {
+		cost++
+	}
+
+	return cost
+}
+
+func (b *plainTextBuilder) fits(line string) bool {
+	return !b.full && b.length+b.cost(line) <= b.budget
+}
+
+func (b *plainTextBuilder) line(line string) bool {
+	if !b.fits(line) {
+		return false
+	}
+
+	b.length += b.cost(line)
+	b.lines = append(b.lines, line)
+	return true
+}
+
+// truncated Add the start of the line ending with an ellipsis, then stop adding lines
+func (b *plainTextBuilder) truncated(line string) {
+	if b.full {
+		return
+	}
+
+	b.full = true
+	available := b.budget - b.length
+	if len(b.lines) > 0 {
+		available--
+	}
+
+	if available < 2 {
+		return
+	}
+
+	runes := []rune(line)
+	cut := strings.TrimRight(string(runes[:available-1]), " ")
+
+	b.lines = append(b.lines, cut+"…")
+	b.length += b.cost(cut + "…")
+}
+
+// plainTextFallback Replace the embeds with their PlainText after the content
+func plainTextFallback(data *InteractionCallbackData) *InteractionCallbackData {
+	if len(data.Embeds) == 0 {
+		return data
+	}
+
+	fallback := *data
+	parts := []string{}
+
+	if data.Content != "" {
+		parts = append(parts, data.Content)
+	}
+
+	remaining := MaxContentLength - utf8.RuneCountInString(data.Content)
+
+	for _, embed := range data.Embeds {
+		// Parts are separated by a blank line
+		remaining -= 2
+		if remaining <= 0 {
+			break
+		}
+
+		text := embed.PlainText(remaining)
+		remaining -= utf8.RuneCountInString(text)
+		parts = append(parts, text)
+	}
+
+	fallback.Content = strings.Join(parts, "\n\n")
+	fallback.Embeds = nil
+
+	return &fallback
+}
@@ -0,0 +1,56 @@
+package httpcord
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEmbedPlainText(t *testing.T) {
+	release := &Embed{
+		Author:      &EmbedAuthor{Name: "Ada"},
+		Title:       "Release",
+		URL:         "https://example.com/r",
+		Description: "All systems go",
+		Fields:      []*EmbedField{{Name: "Version", Value: "1.2"}, {Name: "Notes", Value: "Faster sync"}},
+		Footer:      &EmbedFooter{Text: "httpcord"},
+	}
+
+	lines := func(lines ...string) string {
+		return strings.Join(lines, "\n")
+	}
+
+	tests := []struct {
+		name   string
+		embed  *Embed
+		maxLen int
+		want   string
+	}{
+		{"empty", &Embed{}, 0, ""},
+		{"unlimited", release, 0, lines("Ada", "**Release**", "All systems go", "Version: 1.2", "Notes: Faster sync", "httpcord", "<https://example.com/r>")},
+		{"negative length is unlimited", release, -1,
+			lines("Ada", "**Release**", "All systems go", "Version: 1.2", "Notes: Faster sync", "httpcord", "<https://example.com/r>")},
+		{"exactly the length", release, 95,
+			lines("Ada", "**Release**", "All systems go", "Version: 1.2", "Notes: Faster sync", "httpcord", "<https://example.com/r>")},
+		{"footer dropped", release, 94, lines("Ada", "**Release**", "All systems go", "Version: 1.2", "Notes: Faster sync", "<https://example.com/r>")},
+		{"field value truncated", release, 79, lines("Ada", "**Release**", "All systems go", "Version: 1.2", "Notes: Fas…", "<https://example.com/r>")},
+		{"field dropped with its name", release, 74, lines("Ada", "**Release**", "All systems go", "Version: 1.2", "<https://example.com/r>")},
+		{"description truncated", release, 49, lines("Ada", "**Release**", "All syst…", "<https://example.com/r>")},
+		{"url longer than the length", release, 20, lines("Ada", "**Release**", "All…")},
+		{"fields without a title", &Embed{Fields: []*EmbedField{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}}, 0, lines("a: 1", "b: 2")},
+		{"characters counted as runes", &Embed{Description: "Café ☕ ouvert"}, 8, "Café ☕…"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.embed.PlainText(test.maxLen)
+			if got != test.want {
+				t.Errorf("PlainText(%d) = %q, want %q", test.maxLen, got, test.want)
+			}
+
+			if test.maxLen > 0 && utf8.RuneCountInString(got) > test.maxLen {
+				t.Errorf("PlainText(%d) is %d characters long", test.maxLen, utf8.RuneCountInString(got))
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package httpcord
 
+import "net/url"
+
 type Emoji struct {
 	ID            Snowflake    `json:"id,omitempty"`
 	Name          string       `json:"name,omitempty"`
@@ -26,6 +28,15 @@ func (e *Emoji) Mention() string {
 	return e.Name
 }
 
+// URLEncoded Emoji as used in the reaction routes, name:id for custom emoji and percent-encoded unicode otherwise
+func (e *Emoji) URLEncoded() string {
+	if e.ID.String() != "" {
+		return url.PathEscape(e.Name + ":" + e.ID.String())
+	}
+
+	return url.PathEscape(e.Name)
+}
+
 func (e *Emoji) String() string {
 	return e.Mention()
 }
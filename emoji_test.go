@@ -0,0 +1,28 @@
+package httpcord
+
+import "testing"
+
+func TestEmojiURLEncoded(t *testing.T) {
+	tests := []struct {
+		name  string
+		emoji Emoji
+		want  string
+	}{
+		{"unicode", Emoji{Name: "👍"}, "%F0%9F%91%8D"},
+		{"variation selector", Emoji{Name: "\u2764\ufe0f"}, "%E2%9D%A4%EF%B8%8F"},
+		{"skin tone", Emoji{Name: "👋🏽"}, "%F0%9F%91%8B%F0%9F%8F%BD"},
+		{"zero width joiner sequence", Emoji{Name: "\U0001F469\u200d\U0001F4BB"}, "%F0%9F%91%A9%E2%80%8D%F0%9F%92%BB"},
+		{"keycap", Emoji{Name: "1\ufe0f\u20e3"}, "1%EF%B8%8F%E2%83%A3"},
+		{"custom", Emoji{ID: "41771983429993937", Name: "LUL"}, "LUL:41771983429993937"},
+		{"animated custom", Emoji{ID: "41771983429993937", Name: "blobdance", Animated: true}, "blobdance:41771983429993937"},
+		{"custom with an underscore", Emoji{ID: "1", Name: "pepe_hands"}, "pepe_hands:1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.emoji.URLEncoded(); got != test.want {
+				t.Errorf("URLEncoded() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -51,6 +51,10 @@ func WebhookExecute(ID, token string) string {
 	return fmt.Sprintf("/webhooks/%s/%s", ID, token)
 }
 
+func InteractionCallback(interactionID, token string) string {
+	return fmt.Sprintf("/interactions/%s/%s/callback", interactionID, token)
+}
+
 func ApplicationCommandsGlobal(applicationID string) string {
 	return fmt.Sprintf("/applications/%s/commands", applicationID)
 }
@@ -74,3 +78,91 @@ func FormatImage(URL, format, size string) string {
 func FormatAPIURI(URI string) string {
 	return fmt.Sprintf("%s%s%s", DiscordURL, DiscordAPI, URI)
 }
+
+func GuildScheduledEvents(guildID string) string {
+	return fmt.Sprintf("/guilds/%s/scheduled-events", guildID)
+}
+
+func GuildScheduledEvent(guildID, eventID string) string {
+	return fmt.Sprintf("/guilds/%s/scheduled-events/%s", guildID, eventID)
+}
+
+func GuildMembers(guildID string) string {
+	return fmt.Sprintf("/guilds/%s/members", guildID)
+}
+
+func GuildMembersSearch(guildID string) string {
+	return fmt.Sprintf("/guilds/%s/members/search", guildID)
+}
+
+func ApplicationCommandGlobal(applicationID, commandID string) string {
+	return fmt.Sprintf("/applications/%s/commands/%s", applicationID, commandID)
+}
+
+func ApplicationCommandGuild(applicationID, guildID, commandID string) string {
+	return fmt.Sprintf("/applications/%s/guilds/%s/commands/%s", applicationID, guildID, commandID)
+}
+
+func MessageThreads(channelID, messageID string) string {
+	return fmt.Sprintf("/channels/%s/messages/%s/threads", channelID, messageID)
+}
+
+func GuildCommandPermissions(applicationID, guildID string) string {
+	return fmt.Sprintf("/applications/%s/guilds/%s/commands/permissions", applicationID, guildID)
+}
+
+func CommandPermissions(applicationID, guildID, commandID string) string {
+	return fmt.Sprintf("/applications/%s/guilds/%s/commands/%s/permissions", applicationID, guildID, commandID)
+}
+
+func ChannelPin(channelID, messageID string) string {
+	return fmt.Sprintf("/channels/%s/pins/%s", channelID, messageID)
+}
+
+func Crosspost(channelID, messageID string) string {
+	return fmt.Sprintf("/channels/%s/messages/%s/crosspost", channelID, messageID)
+}
+
+func PollAnswerVoters(channelID, messageID, answerID string) string {
+	return fmt.Sprintf("/channels/%s/polls/%s/answers/%s", channelID, messageID, answerID)
+}
+
+func ExpirePoll(channelID, messageID string) string {
+	return fmt.Sprintf("/channels/%s/polls/%s/expire", channelID, messageID)
+}
+
+func Channel(channelID string) string {
+	return fmt.Sprintf("/channels/%s", channelID)
+}
+
+func GuildRole(guildID, roleID string) string {
+	return fmt.Sprintf("/guilds/%s/roles/%s", guildID, roleID)
+}
+
+func ConsumeEntitlement(applicationID, entitlementID string) string {
+	return fmt.Sprintf("/applications/%s/entitlements/%s/consume", applicationID, entitlementID)
+}
+
+func SKUs(applicationID string) string {
+	return fmt.Sprintf("/applications/%s/skus", applicationID)
+}
+
+func UserChannels() string {
+	return "/users/@me/channels"
+}
+
+func Entitlements(applicationID string) string {
+	return fmt.Sprintf("/applications/%s/entitlements", applicationID)
+}
+
+func Entitlement(applicationID, entitlementID string) string {
+	return fmt.Sprintf("/applications/%s/entitlements/%s", applicationID, entitlementID)
+}
+
+func RoleConnectionMetadata(applicationID string) string {
+	return fmt.Sprintf("/applications/%s/role-connections/metadata", applicationID)
+}
+
+func UserRoleConnection(applicationID string) string {
+	return fmt.Sprintf("/users/@me/applications/%s/role-connection", applicationID)
+}
@@ -0,0 +1,138 @@
+package httpcord
+
+import "time"
+
+type EntitlementType int
+
+// Entitlement Types
+
+const (
+	PurchaseEntitlementType EntitlementType = iota + 1
+	PremiumSubscriptionEntitlementType
+	DeveloperGiftEntitlementType
+	TestModePurchaseEntitlementType
+	FreePurchaseEntitlementType
+	UserGiftEntitlementType
+	PremiumPurchaseEntitlementType
+	ApplicationSubscriptionEntitlementType
+)
+
+type Entitlement struct {
+	ID            Snowflake       `json:"id"`
+	SkuID         Snowflake       `json:"sku_id"`
+	ApplicationID Snowflake       `json:"application_id"`
+	UserID        Snowflake       `json:"user_id,omitempty"`
+	GuildID       Snowflake       `json:"guild_id,omitempty"`
+	Type          EntitlementType `json:"type"`
+	Deleted       bool            `json:"deleted"`
+	StartsAt      Time            `json:"starts_at"`
+	EndsAt        Time            `json:"ends_at"`
+	// Consumed is set once a consumable SKU entitlement is consumed (See RestClient.ConsumeEntitlement)
+	Consumed       bool       `json:"consumed,omitempty"`
+	SubscriptionID *Snowflake `json:"subscription_id,omitempty"`
+}
+
+// IsTest Whether the entitlement was created in test mode. Test entitlements have the test mode purchase type,
+// or for subscriptions neither a subscription nor a validity period. This is a heuristic, Discord has no test flag
+func (e *Entitlement) IsTest() bool {
+	if e.Type == TestModePurchaseEntitlementType {
+		return true
+	}
+
+	return e.Type == ApplicationSubscriptionEntitlementType && e.SubscriptionID == nil && e.StartsAt.IsZero() && e.EndsAt.IsZero()
+}
+
+// Active Whether the entitlement currently grants access to its SKU
+func (e *Entitlement) Active() bool {
+	now := time.Now()
+
+	if e.Deleted || (!e.StartsAt.IsZero() && e.StartsAt.After(now)) {
+		return false
+	}
+
+	return e.EndsAt.IsZero() || e.EndsAt.After(now)
+}
+
+var PremiumUpsellMessages = Dictionary{
+	EnglishUSLocale:    "This feature requires a premium subscription.",
+	EnglishGBLocale:    "This feature requires a premium subscription.",
+	PortugueseBRLocale: "Este recurso requer uma assinatura premium.",
+	SpanishESLocale:    "Esta función requiere una suscripción premium.",
+	FrenchLocale:       "Cette fonctionnalité nécessite un abonnement premium.",
+	GermanLocale:       "Diese Funktion erfordert ein Premium-Abonnement.",
+}
+
+// PremiumUpsellResponse Ephemeral message with a premium button for the SKU, replaces the PremiumRequired callback
+func PremiumUpsellResponse(skuID Snowflake, locale Locale) *InteractionCallbackData {
+	return &InteractionCallbackData{
+		Content: PremiumUpsellMessages.Get(locale, PremiumUpsellMessages[EnglishUSLocale]),
+		Flags:   EphemeralMessageFlag,
+		Components: []*ActionRowComponent{
+			NewActionRowComponentBuilder().AddComponent(
+				NewButtonComponentBuilder().SetStyle(PremiumButtonStyle).SetSKUID(skuID),
+			),
+		},
+	}
+}
+
+type EntitlementOption func(o *entitlementOptions)
+
+type entitlementOptions struct {
+	ignoreConsumed bool
+}
+
+// IgnoreConsumed Consumed entitlements of consumable SKUs do not grant access
+func IgnoreConsumed() EntitlementOption {
+	return func(o *entitlementOptions) {
+		o.ignoreConsumed = true
+	}
+}
+
+// HasEntitlement Whether the interaction carries an active entitlement for the SKU, or lists it in the legacy EntitlementSKUIDs
+func (ctx *ConnectionContext) HasEntitlement(skuID Snowflake, opts ...EntitlementOption) bool {
+	var o entitlementOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, entitlement := range ctx.Interaction.Entitlements {
+		if entitlement.SkuID == skuID && entitlement.Active() && !(o.ignoreConsumed && entitlement.Consumed) {
+			return true
+		}
+	}
+
+	for _, id := range ctx.Interaction.EntitlementSKUIDs {
+		if id == skuID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldUpsell Whether the user lacks the SKU and is not in ConnectionOptions.PremiumBypass
+func (ctx *ConnectionContext) ShouldUpsell(skuID Snowflake) bool {
+	for _, id := range ctx.options.PremiumBypass {
+		if (ctx.Interaction.User != nil && id == ctx.Interaction.User.ID) || id == ctx.Interaction.GuildID {
+			return false
+		}
+	}
+
+	return !ctx.HasEntitlement(skuID)
+}
+
+// ReplyPremiumUpsell Reply with PremiumUpsellResponse in the interaction locale
+func (ctx *ConnectionContext) ReplyPremiumUpsell(skuID Snowflake) {
+	ctx.ReplyInteraction(PremiumUpsellResponse(skuID, Locale(ctx.Interaction.Locale)))
+}
+
+// ReplyPremiumRequired Reply with the PremiumRequired callback
+//
+// Deprecated: Discord deprecated this callback, use ReplyPremiumUpsell instead
+func (ctx *ConnectionContext) ReplyPremiumRequired() {
+	deprecated("ConnectionContext.ReplyPremiumRequired", "ConnectionContext.ReplyPremiumUpsell", 0)
+
+	ctx.SendRes(&InteractionResponse{
+		Type: PremiumRequiredResponse,
+	})
+}
@@ -0,0 +1,113 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPremiumUpsellResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale Locale
+		want   string
+	}{
+		{"english", EnglishUSLocale, `{"content":"This feature requires a premium subscription.","flags":64,` +
+			`"components":[{"type":1,"components":[{"type":2,"style":6,"sku_id":"1088510058284990888"}]}]}`},
+		{"localized", FrenchLocale, `{"content":"Cette fonctionnalité nécessite un abonnement premium.","flags":64,` +
+			`"components":[{"type":1,"components":[{"type":2,"style":6,"sku_id":"1088510058284990888"}]}]}`},
+		{"unknown locale", Locale("xx"), `{"content":"This feature requires a premium subscription.","flags":64,` +
+			`"components":[{"type":1,"components":[{"type":2,"style":6,"sku_id":"1088510058284990888"}]}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(PremiumUpsellResponse("1088510058284990888", test.locale))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != test.want {
+				t.Errorf("PremiumUpsellResponse() = %s, want %s", b, test.want)
+			}
+		})
+	}
+}
+
+func TestShouldUpsell(t *testing.T) {
+	const sku Snowflake = "10"
+
+	past := Time{Time: time.Now().Add(-time.Hour)}
+	future := Time{Time: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		entitlements []*Entitlement
+		legacy       []Snowflake
+		bypass       []Snowflake
+		want         bool
+	}{
+		{"no entitlement", nil, nil, nil, true},
+		{"active entitlement", []*Entitlement{{SkuID: sku}}, nil, nil, false},
+		{"entitlement of another SKU", []*Entitlement{{SkuID: "11"}}, nil, nil, true},
+		{"expired entitlement", []*Entitlement{{SkuID: sku, EndsAt: past}}, nil, nil, true},
+		{"entitlement not started", []*Entitlement{{SkuID: sku, StartsAt: future}}, nil, nil, true},
+		{"entitlement in its period", []*Entitlement{{SkuID: sku, StartsAt: past, EndsAt: future}}, nil, nil, false},
+		{"deleted entitlement", []*Entitlement{{SkuID: sku, Deleted: true}}, nil, nil, true},
+		{"legacy SKU IDs", nil, []Snowflake{sku}, nil, false},
+		{"bypassed user", nil, nil, []Snowflake{"4"}, false},
+		{"bypassed guild", nil, nil, []Snowflake{"2"}, false},
+		{"other bypassed IDs", nil, nil, []Snowflake{"40", "20"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interaction := Interaction{
+				ID:                nowSnowflake(),
+				Type:              ApplicationCommandInteraction,
+				GuildID:           "2",
+				User:              &User{ID: "4"},
+				Entitlements:      test.entitlements,
+				EntitlementSKUIDs: test.legacy,
+			}
+
+			ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{PremiumBypass: test.bypass},
+				Respond: func(*InteractionResponse) error { return nil }, Webhooks: &countingWebhooks{}})
+			defer finish()
+
+			if got := ctx.ShouldUpsell(sku); got != test.want {
+				t.Errorf("ShouldUpsell() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReplyPremiumRequired(t *testing.T) {
+	var responses []*InteractionResponse
+
+	ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction}, ContextConfig{
+		Respond: func(response *InteractionResponse) error {
+			responses = append(responses, response)
+			return nil
+		},
+		Webhooks: &countingWebhooks{},
+	})
+	defer finish()
+
+	ctx.ReplyPremiumRequired()
+
+	if len(responses) != 1 || responses[0].Type != PremiumRequiredResponse {
+		t.Fatalf("responses %+v, want the PremiumRequired callback", responses)
+	}
+
+	conn := newTestConnection(t, ConnectionOptions{Logger: NopLogger})
+
+	found := false
+	for _, notice := range conn.DeprecationReport() {
+		found = found || (notice.Symbol == "ConnectionContext.ReplyPremiumRequired" && notice.Replacement == "ConnectionContext.ReplyPremiumUpsell")
+	}
+
+	if !found {
+		t.Errorf("DeprecationReport() = %+v, missing ReplyPremiumRequired", conn.DeprecationReport())
+	}
+}
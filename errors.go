@@ -0,0 +1,159 @@
+package httpcord
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PanicError A handler panicked while dispatching the interaction
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("httpcord: handler panicked: %v", e.Value)
+}
+
+var (
+	// ErrMalformedInteraction The request body is not a valid interaction payload
+	ErrMalformedInteraction = errors.New("httpcord: malformed interaction")
+	// ErrResponseEncoding The interaction response could not be written to the request
+	ErrResponseEncoding = errors.New("httpcord: could not encode the interaction response")
+)
+
+// MalformedInteractionError Interaction payload refused before dispatch with the status of its Kind
+type MalformedInteractionError struct {
+	// Kind is the class of the malformed input, 0 for the payloads failing to decode past the body checks
+	Kind MalformedKind
+	Err  error
+}
+
+func (e *MalformedInteractionError) Error() string {
+	return "httpcord: malformed interaction: " + e.Err.Error()
+}
+
+func (e *MalformedInteractionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *MalformedInteractionError) Is(target error) bool {
+	return target == ErrMalformedInteraction
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// ShortTraceCode Default TraceCodeRenderer, "7f3a22c1..." becomes "7f3a-22"
+func ShortTraceCode(requestID string) string {
+	if len(requestID) < 6 {
+		return requestID
+	}
+
+	return requestID[:4] + "-" + requestID[4:6]
+}
+
+// ErrorReplyFunc Map an error raised before or during dispatch to the reply sent to the user
+type ErrorReplyFunc func(ctx ConnectionContext, err error) *InteractionCallbackData
+
+var (
+	MutuallyExclusiveMessages = Dictionary{
+		EnglishUSLocale:    "The options %s cannot be used together.",
+		EnglishGBLocale:    "The options %s cannot be used together.",
+		PortugueseBRLocale: "As opções %s não podem ser usadas juntas.",
+		SpanishESLocale:    "Las opciones %s no se pueden usar juntas.",
+		FrenchLocale:       "Les options %s ne peuvent pas être utilisées ensemble.",
+		GermanLocale:       "Die Optionen %s können nicht zusammen verwendet werden.",
+	}
+	RequireOneOfMessages = Dictionary{
+		EnglishUSLocale:    "You must provide one of the options %s.",
+		EnglishGBLocale:    "You must provide one of the options %s.",
+		PortugueseBRLocale: "Você precisa informar uma das opções %s.",
+		SpanishESLocale:    "Debes indicar una de las opciones %s.",
+		FrenchLocale:       "Vous devez indiquer l'une des options %s.",
+		GermanLocale:       "Du musst eine der Optionen %s angeben.",
+	}
+	GenericErrorMessages = Dictionary{
+		EnglishUSLocale:    "Something went wrong while running this command.",
+		EnglishGBLocale:    "Something went wrong while running this command.",
+		PortugueseBRLocale: "Algo deu errado ao executar este comando.",
+		SpanishESLocale:    "Algo salió mal al ejecutar este comando.",
+		FrenchLocale:       "Une erreur est survenue lors de l'exécution de cette commande.",
+		GermanLocale:       "Beim Ausführen dieses Befehls ist etwas schiefgelaufen.",
+	}
+)
+
+// DefaultErrorReply Ephemeral message in the interaction locale describing the error.
+// With ConnectionOptions.ErrorTraceTemplate the message is sent as an embed with the trace code in the footer
+func DefaultErrorReply(ctx ConnectionContext, err error) *InteractionCallbackData {
+	locale := Locale(ctx.Interaction.Locale)
+	content := GenericErrorMessages.Get(locale, GenericErrorMessages[EnglishUSLocale])
+
+	var constraintErr *OptionConstraintError
+	if errors.As(err, &constraintErr) {
+		messages, names := RequireOneOfMessages, constraintErr.Constraint.Options
+
+		if constraintErr.Constraint.Kind == MutuallyExclusiveConstraint {
+			messages, names = MutuallyExclusiveMessages, constraintErr.Provided
+		}
+
+		content = fmt.Sprintf(messages.Get(locale, messages[EnglishUSLocale]), quoteOptions(names))
+	}
+
+	var lengthErr *StringLengthError
+	if errors.As(err, &lengthErr) {
+		content = fmt.Sprintf(StringTooLongMessages.Get(locale, StringTooLongMessages[EnglishUSLocale]), quoteOptions([]string{lengthErr.Option}), lengthErr.Max)
+	}
+
+	var permissionErr *MissingAppPermissionError
+	if errors.As(err, &permissionErr) {
+		content = fmt.Sprintf(MissingAppPermissionMessages.Get(locale, MissingAppPermissionMessages[EnglishUSLocale]), permissionErr.Name)
+	}
+
+	var guardErr *GuardError
+	if errors.As(err, &guardErr) {
+		switch guardErr.Kind {
+		case GuildOnlyGuard:
+			content = GuildOnlyMessages.Get(locale, GuildOnlyMessages[EnglishUSLocale])
+		case UserPermissionsGuard:
+			content = fmt.Sprintf(MissingUserPermissionMessages.Get(locale, MissingUserPermissionMessages[EnglishUSLocale]), guardErr.Name)
+		default:
+			content = WrongContextMessages.Get(locale, WrongContextMessages[EnglishUSLocale])
+		}
+	}
+
+	if errors.Is(err, ErrDispatchBusy) {
+		content = DispatchBusyMessages.Get(locale, DispatchBusyMessages[EnglishUSLocale])
+	}
+
+	if ctx.options != nil && ctx.options.ErrorTraceTemplate != "" {
+		return &InteractionCallbackData{
+			Embeds: []*Embed{NewEmbedBuilder().SetDescription(content).SetFooter(&EmbedFooter{
+				Text: strings.ReplaceAll(ctx.options.ErrorTraceTemplate, "{trace}", ctx.TraceCode()),
+			})},
+			Flags: EphemeralMessageFlag,
+		}
+	}
+
+	return &InteractionCallbackData{
+		Content: content,
+		Flags:   EphemeralMessageFlag,
+	}
+}
+
+func quoteOptions(names []string) string {
+	quoted := make([]string, len(names))
+
+	for i, name := range names {
+		quoted[i] = "`" + name + "`"
+	}
+
+	return strings.Join(quoted, ", ")
+}
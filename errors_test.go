@@ -0,0 +1,123 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorTraceCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		renderer func(requestID string) string
+		// replied The handler replies before panicking
+		replied bool
+		// footer Expected footer with {trace} standing for the code passed to OnError, empty when no embed is sent
+		footer string
+	}{
+		{"default renderer", "error code {trace}", nil, false, "error code {trace}"},
+		{"custom renderer", "ref {trace} ({trace})", func(requestID string) string { return "T-" + strings.ToUpper(requestID[:3]) }, false, "ref {trace} ({trace})"},
+		{"no template", "", nil, false, ""},
+		{"already replied", "error code {trace}", nil, true, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var codes []string
+			var reported error
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				Logger:             NopLogger,
+				ErrorTraceTemplate: test.template,
+				TraceCodeRenderer:  test.renderer,
+				OnError: func(ctx ConnectionContext, err error) {
+					codes = append(codes, ctx.TraceCode())
+					reported = err
+				},
+			})
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				if test.replied {
+					ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+				}
+
+				panic("boom")
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(commandBody()))
+
+			var panicErr *PanicError
+			if !errors.As(reported, &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+				t.Fatalf("OnError got %v, want the PanicError with its stack", reported)
+			}
+
+			if len(codes) != 1 || codes[0] == "" {
+				t.Fatalf("OnError trace codes %q, want one", codes)
+			}
+
+			if test.renderer != nil && !strings.HasPrefix(codes[0], "T-") {
+				t.Errorf("trace code %q not rendered by TraceCodeRenderer", codes[0])
+			}
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%v: %s", err, w.Body)
+			}
+
+			if test.replied {
+				if response.Data.Content != "banned" || len(response.Data.Embeds) != 0 {
+					t.Errorf("response %s, want the handler reply only", w.Body)
+				}
+
+				return
+			}
+
+			if test.footer == "" {
+				if len(response.Data.Embeds) != 0 || response.Data.Content != GenericErrorMessages[EnglishUSLocale] {
+					t.Errorf("response %s, want the error message without an embed", w.Body)
+				}
+
+				return
+			}
+
+			if len(response.Data.Embeds) != 1 || response.Data.Embeds[0].Footer == nil {
+				t.Fatalf("response %s, want an embed with a footer", w.Body)
+			}
+
+			want := strings.ReplaceAll(test.footer, "{trace}", codes[0])
+			if footer := response.Data.Embeds[0].Footer.Text; footer != want {
+				t.Errorf("footer %q, want %q, the code passed to OnError", footer, want)
+			}
+
+			if response.Data.Flags != EphemeralMessageFlag {
+				t.Errorf("flags %d, want ephemeral", response.Data.Flags)
+			}
+		})
+	}
+}
+
+func TestShortTraceCode(t *testing.T) {
+	tests := []struct {
+		requestID string
+		want      string
+	}{
+		{"7f3a22c1d4e5f607", "7f3a-22"},
+		{"7f3a22", "7f3a-22"},
+		{"7f3a2", "7f3a2"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := ShortTraceCode(test.requestID); got != test.want {
+			t.Errorf("ShortTraceCode(%q) = %q, want %q", test.requestID, got, test.want)
+		}
+	}
+
+	if a, b := newRequestID(), newRequestID(); a == b || len(a) != 16 {
+		t.Errorf("newRequestID() = %q, %q, want distinct 16 hex digit IDs", a, b)
+	}
+}
@@ -0,0 +1,47 @@
+package httpcord
+
+import "strings"
+
+// zeroWidthSpace Breaks the mentions of the user content without changing how it reads
+const zeroWidthSpace = "\u200b"
+
+// EscapeMarkdown Escape the Discord markdown of the user content so it shows as typed: emphasis, spoilers, code
+// blocks, masked links, timestamps and mentions tags, and the headings, quotes and lists starting a line
+func EscapeMarkdown(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	lineStart := true
+	for _, r := range s {
+		switch r {
+		case '\\', '*', '_', '~', '`', '|', '[', ']', '(', ')', '<':
+			b.WriteByte('\\')
+		case '#', '-', '>':
+			if lineStart {
+				b.WriteByte('\\')
+			}
+		}
+
+		b.WriteRune(r)
+
+		if r == '\n' {
+			lineStart = true
+		} else if r != ' ' && r != '\t' {
+			lineStart = false
+		}
+	}
+
+	return b.String()
+}
+
+// EscapeMentions Break @everyone, @here and the user, role and channel mentions of the user content, they show as
+// text and do not ping even when AllowedMentions permits it
+func EscapeMentions(s string) string {
+	s = strings.ReplaceAll(s, "@", "@"+zeroWidthSpace)
+	return strings.ReplaceAll(s, "<#", "<"+zeroWidthSpace+"#")
+}
+
+// Sanitize EscapeMarkdown and EscapeMentions, for user content shown in messages and embeds
+func Sanitize(s string) string {
+	return EscapeMentions(EscapeMarkdown(s))
+}
@@ -0,0 +1,125 @@
+package httpcord
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// transportOutcome Status and body of a request answered by one of the transports
+type transportOutcome struct {
+	status int
+	body   string
+}
+
+func TestSignatureHeadersConformance(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := commandBody()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := hex.EncodeToString(ed25519.Sign(private, append([]byte(timestamp), body...)))
+	forged := strings.Repeat("00", ed25519.SignatureSize)
+
+	tests := []struct {
+		name string
+		// headers Raw header lines of the request, sent as they are
+		headers []string
+		status  int
+		// repeated The repeated signature header is logged
+		repeated bool
+	}{
+		{"canonical", []string{"X-Signature-Ed25519: " + signature, "X-Signature-Timestamp: " + timestamp}, http.StatusOK, false},
+		{"lowercase", []string{"x-signature-ed25519: " + signature, "x-signature-timestamp: " + timestamp}, http.StatusOK, false},
+		{"mixed case", []string{"X-SIGNATURE-ed25519: " + signature, "x-Signature-TIMESTAMP: " + timestamp}, http.StatusOK, false},
+		{"duplicated, valid first", []string{"X-Signature-Ed25519: " + signature, "x-signature-ed25519: " + forged,
+			"X-Signature-Timestamp: " + timestamp}, http.StatusOK, true},
+		{"duplicated, forged first", []string{"X-Signature-Ed25519: " + forged, "X-Signature-Ed25519: " + signature,
+			"X-Signature-Timestamp: " + timestamp}, http.StatusUnauthorized, true},
+		{"duplicated timestamp", []string{"X-Signature-Ed25519: " + signature, "X-Signature-Timestamp: " + timestamp,
+			"X-Signature-Timestamp: 1"}, http.StatusOK, true},
+		{"missing signature", []string{"X-Signature-Timestamp: " + timestamp}, http.StatusUnauthorized, false},
+		{"forged", []string{"X-Signature-Ed25519: " + forged, "X-Signature-Timestamp: " + timestamp}, http.StatusUnauthorized, false},
+	}
+
+	connection := func(kind HttpConnection, logs *bytes.Buffer) *Connection {
+		conn, err := NewConnection(ConnectionOptions{HttpConnection: kind, PublicKey: hex.EncodeToString(public), Logger: NewLogger(logs, LogLevelDebug)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn.Command("ban", func(ctx ConnectionContext) {
+			ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+		})
+
+		return conn
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw := fmt.Sprintf("POST / HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: %d\r\n%s\r\n\r\n%s",
+				len(body), strings.Join(test.headers, "\r\n"), body)
+
+			outcomes := make(map[string]transportOutcome)
+
+			for _, transport := range []string{"net/http", "fasthttp", "fasthttp without normalizing"} {
+				var logs bytes.Buffer
+
+				var outcome transportOutcome
+				if transport == "net/http" {
+					r, err := http.ReadRequest(bufio.NewReader(strings.NewReader(raw)))
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					w := httptest.NewRecorder()
+					connection(DefaultHttpConnection, &logs).ServeHTTP(w, r)
+					outcome = transportOutcome{w.Code, w.Body.String()}
+				} else {
+					var req fasthttp.Request
+					if transport == "fasthttp without normalizing" {
+						req.Header.DisableNormalizing()
+					}
+
+					if err := req.Read(bufio.NewReader(strings.NewReader(raw))); err != nil {
+						t.Fatal(err)
+					}
+
+					var ctx fasthttp.RequestCtx
+					ctx.Init(&req, nil, nil)
+
+					connection(FastHttpConnection, &logs).FastHandler(&ctx)
+					outcome = transportOutcome{ctx.Response.StatusCode(), string(ctx.Response.Body())}
+				}
+
+				if outcome.status != test.status {
+					t.Errorf("%s: status %d, want %d: %s", transport, outcome.status, test.status, outcome.body)
+				}
+
+				if logged := strings.Contains(logs.String(), "repeated signature header"); logged != test.repeated {
+					t.Errorf("%s: repeated header logged = %v, want %v", transport, logged, test.repeated)
+				}
+
+				outcomes[transport] = outcome
+			}
+
+			for transport, outcome := range outcomes {
+				if outcome != outcomes["net/http"] {
+					t.Errorf("%s answered %+v, net/http answered %+v", transport, outcome, outcomes["net/http"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package httpcord
+
+// FeatureGate Decide whether a feature is enabled for the guild and user of an interaction
+type FeatureGate interface {
+	Enabled(feature string, guildID, userID Snowflake) bool
+}
+
+// FeatureGateFunc Use a function as FeatureGate
+type FeatureGateFunc func(feature string, guildID, userID Snowflake) bool
+
+func (f FeatureGateFunc) Enabled(feature string, guildID, userID Snowflake) bool {
+	return f(feature, guildID, userID)
+}
+
+// AllowAllFeatures Default FeatureGate, every feature is enabled
+var AllowAllFeatures FeatureGate = FeatureGateFunc(func(string, Snowflake, Snowflake) bool {
+	return true
+})
+
+// StaticFeatureGate Features enabled for the listed guild or user IDs, features missing from the map are disabled
+type StaticFeatureGate map[string][]Snowflake
+
+func (g StaticFeatureGate) Enabled(feature string, guildID, userID Snowflake) bool {
+	for _, id := range g[feature] {
+		if (guildID != "" && id == guildID) || (userID != "" && id == userID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// featureEnabled Evaluate the route feature with the connection gate and report the decision
+func (ctx *ConnectionContext) featureEnabled(route *CommandRoute) bool {
+	if route.FeatureName == "" {
+		return true
+	}
+
+	var userID Snowflake
+	if ctx.Interaction.User != nil {
+		userID = ctx.Interaction.User.ID
+	}
+
+	enabled := ctx.options.FeatureGate.Enabled(route.FeatureName, ctx.Interaction.GuildID, userID)
+
+	decision := "deny"
+	if enabled {
+		decision = "allow"
+	}
+
+	ctx.options.Metrics.IncCounter("httpcord_feature_gate_decisions_total", map[string]string{
+		"feature":  route.FeatureName,
+		"command":  route.Name,
+		"decision": decision,
+	})
+
+	return enabled
+}
@@ -0,0 +1,102 @@
+package httpcord
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// countersRecorder MetricsCollector keeping the labels of every counter increment
+type countersRecorder struct {
+	mu       sync.Mutex
+	counters map[string][]map[string]string
+}
+
+func (r *countersRecorder) IncCounter(name string, labels map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.counters == nil {
+		r.counters = make(map[string][]map[string]string)
+	}
+
+	r.counters[name] = append(r.counters[name], labels)
+}
+
+func (r *countersRecorder) get(name string) []map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.counters[name]
+}
+
+func TestFeatureGate(t *testing.T) {
+	tests := []struct {
+		name   string
+		gate   FeatureGate
+		denied *InteractionCallbackData
+		// ran The command handler ran
+		ran bool
+		// contains Part of the response, empty when nothing must be sent
+		contains string
+		decision string
+	}{
+		{"default gate allows", nil, nil, true, "searched", "allow"},
+		{"guild allowed", StaticFeatureGate{"new-search": {"2"}}, nil, true, "searched", "allow"},
+		{"user allowed", StaticFeatureGate{"new-search": {"4"}}, nil, true, "searched", "allow"},
+		{"denied left unhandled", StaticFeatureGate{"new-search": {"20"}}, nil, false, "", "deny"},
+		{"feature missing from the map", StaticFeatureGate{"other": {"2"}}, nil, false, "", "deny"},
+		{"denied with a custom message", StaticFeatureGate{}, &InteractionCallbackData{Content: "Coming soon", Flags: EphemeralMessageFlag}, false, `"content":"Coming soon"`, "deny"},
+		{"gate function", FeatureGateFunc(func(feature string, guildID, userID Snowflake) bool {
+			return feature == "new-search" && guildID == "2" && userID == "4"
+		}), nil, true, "searched", "allow"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := &countersRecorder{}
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, FeatureGate: test.gate, Metrics: metrics})
+
+			ran := false
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "searched"})
+			}).Feature("new-search").FeatureDenied(test.denied)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(commandBody()))
+
+			if ran != test.ran {
+				t.Errorf("handler ran = %v, want %v", ran, test.ran)
+			}
+
+			if test.contains == "" && strings.Contains(w.Body.String(), `"type":4`) {
+				t.Errorf("response %s, want the interaction left unhandled", w.Body)
+			} else if !strings.Contains(w.Body.String(), test.contains) {
+				t.Errorf("response %s, want %s", w.Body, test.contains)
+			}
+
+			decisions := metrics.get("httpcord_feature_gate_decisions_total")
+			if len(decisions) != 1 || decisions[0]["decision"] != test.decision || decisions[0]["feature"] != "new-search" || decisions[0]["command"] != "ban" {
+				t.Errorf("decisions %v, want one %s of new-search", decisions, test.decision)
+			}
+		})
+	}
+
+	t.Run("commands without a feature not gated", func(t *testing.T) {
+		metrics := &countersRecorder{}
+		conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, FeatureGate: StaticFeatureGate{}, Metrics: metrics})
+
+		conn.Command("ban", func(ctx ConnectionContext) {
+			ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+		})
+
+		w := httptest.NewRecorder()
+		conn.ServeHTTP(w, sign(commandBody()))
+
+		if !strings.Contains(w.Body.String(), "banned") || len(metrics.get("httpcord_feature_gate_decisions_total")) != 0 {
+			t.Errorf("response %s, want the command to run without a gate decision", w.Body)
+		}
+	})
+}
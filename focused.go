@@ -0,0 +1,97 @@
+package httpcord
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FocusedValue Value being typed in an autocomplete interaction, possibly partial like "12." or "-"
+type FocusedValue struct {
+	name       string
+	optionType ApplicationCommandOptionType
+	raw        string
+}
+
+// FocusedOption Option the user is typing in an autocomplete interaction, false when the payload has none
+func (ctx *ConnectionContext) FocusedOption() (FocusedValue, bool) {
+	if ctx.Interaction.Type != AutoCompleteInteraction {
+		return FocusedValue{}, false
+	}
+
+	index := ctx.optionIndex()
+
+	for _, option := range index.leaf {
+		if option.Focused {
+			return newFocusedValue(option), true
+		}
+	}
+
+	for _, option := range index.tree {
+		if option.Focused {
+			return newFocusedValue(option), true
+		}
+	}
+
+	return FocusedValue{}, false
+}
+
+func newFocusedValue(option *ApplicationCommandOption) FocusedValue {
+	v := FocusedValue{name: option.Name, optionType: option.Type}
+
+	switch value := option.Value.(type) {
+	case string:
+		v.raw = value
+	case float64:
+		v.raw = strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		v.raw = strconv.FormatBool(value)
+	}
+
+	return v
+}
+
+// Name Name of the focused option
+func (v FocusedValue) Name() string {
+	return v.name
+}
+
+// Type Type of the focused option
+func (v FocusedValue) Type() ApplicationCommandOptionType {
+	return v.optionType
+}
+
+// Raw Text typed so far, empty when nothing was typed
+func (v FocusedValue) Raw() string {
+	return v.raw
+}
+
+// AsFloat Typed number, a trailing dot is accepted and incomplete input like "-" or "." is not ok
+func (v FocusedValue) AsFloat() (float64, bool) {
+	s := strings.TrimSuffix(strings.TrimSpace(v.raw), ".")
+
+	if s == "" || s == "-" || s == "+" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// AsInt Typed integer, "12." is 12 and fractions like "12.5" are not ok
+func (v FocusedValue) AsInt() (int64, bool) {
+	n, ok := v.AsFloat()
+	if !ok || n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+		return 0, false
+	}
+
+	if i, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(v.raw), "."), 10, 64); err == nil {
+		return i, true
+	}
+
+	return int64(n), true
+}
@@ -0,0 +1,75 @@
+package httpcord
+
+import (
+	"math"
+	"testing"
+)
+
+// focusedContext Context of an autocomplete interaction with the options of the price command
+func focusedContext(t *testing.T, options string) *ConnectionContext {
+	return bodyContext(t, interactionBody(AutoCompleteInteraction, `{"id":"5","name":"price","type":1,"options":`+options+`}`))
+}
+
+func TestFocusedOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		// focused Name of the focused option, empty when there is none
+		focused string
+		raw     string
+		float   float64
+		floatOk bool
+		int     int64
+		intOk   bool
+	}{
+		{"partial integer with a trailing dot", `[{"type":4,"name":"amount","value":"12.","focused":true}]`, "amount", "12.", 12, true, 12, true},
+		{"lone minus", `[{"type":4,"name":"amount","value":"-","focused":true}]`, "amount", "-", 0, false, 0, false},
+		{"lone plus", `[{"type":10,"name":"amount","value":"+","focused":true}]`, "amount", "+", 0, false, 0, false},
+		{"lone dot", `[{"type":10,"name":"amount","value":".","focused":true}]`, "amount", ".", 0, false, 0, false},
+		{"nothing typed", `[{"type":10,"name":"amount","value":"","focused":true}]`, "amount", "", 0, false, 0, false},
+		{"negative fraction", `[{"type":10,"name":"amount","value":"-2.5","focused":true}]`, "amount", "-2.5", -2.5, true, 0, false},
+		{"surrounding spaces", `[{"type":4,"name":"amount","value":" 42 ","focused":true}]`, "amount", " 42 ", 42, true, 42, true},
+		{"number sent as a number", `[{"type":10,"name":"amount","value":7.25,"focused":true}]`, "amount", "7.25", 7.25, true, 0, false},
+		{"letters", `[{"type":4,"name":"amount","value":"12a","focused":true}]`, "amount", "12a", 0, false, 0, false},
+		{"infinity", `[{"type":10,"name":"amount","value":"Inf","focused":true}]`, "amount", "Inf", 0, false, 0, false},
+		{"not a number", `[{"type":10,"name":"amount","value":"NaN","focused":true}]`, "amount", "NaN", 0, false, 0, false},
+		{"integer past float precision", `[{"type":4,"name":"amount","value":"9007199254740993","focused":true}]`, "amount",
+			"9007199254740993", 9007199254740992, true, 9007199254740993, true},
+		{"integer overflow", `[{"type":4,"name":"amount","value":"9223372036854775808","focused":true}]`, "amount",
+			"9223372036854775808", math.MaxInt64, true, 0, false},
+		{"partial string", `[{"type":3,"name":"item","value":"swo","focused":true}]`, "item", "swo", 0, false, 0, false},
+		{"string of digits", `[{"type":3,"name":"item","value":"12","focused":true}]`, "item", "12", 12, true, 12, true},
+		{"focused among other options", `[{"type":3,"name":"item","value":"sword"},{"type":4,"name":"amount","value":"3","focused":true}]`,
+			"amount", "3", 3, true, 3, true},
+		{"focused in a subcommand", `[{"type":1,"name":"buy","options":[{"type":3,"name":"item","value":"sh","focused":true}]}]`,
+			"item", "sh", 0, false, 0, false},
+		{"nothing focused", `[{"type":3,"name":"item","value":"sword"}]`, "", "", 0, false, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := focusedContext(t, test.options).FocusedOption()
+			if ok != (test.focused != "") || value.Name() != test.focused {
+				t.Fatalf("FocusedOption() = %q, %v, want %q", value.Name(), ok, test.focused)
+			}
+
+			if value.Raw() != test.raw {
+				t.Errorf("Raw() = %q, want %q", value.Raw(), test.raw)
+			}
+
+			if float, ok := value.AsFloat(); float != test.float || ok != test.floatOk {
+				t.Errorf("AsFloat() = %v, %v, want %v, %v", float, ok, test.float, test.floatOk)
+			}
+
+			if int, ok := value.AsInt(); int != test.int || ok != test.intOk {
+				t.Errorf("AsInt() = %v, %v, want %v, %v", int, ok, test.int, test.intOk)
+			}
+		})
+	}
+
+	t.Run("not an autocomplete", func(t *testing.T) {
+		if value, ok := bodyContext(t, commandBody()).FocusedOption(); ok {
+			t.Errorf("FocusedOption() = %q of a command, want none", value.Name())
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"httpcord/endpoints"
+)
+
+const followUpsStatePrefix = "httpcord:followups:"
+
+// trackedFollowUp Follow-up created through the ConnectionContext
+type trackedFollowUp struct {
+	ID        Snowflake `json:"id"`
+	Ephemeral bool      `json:"ephemeral,omitempty"`
+}
+
+// FollowUpCleanup Result of DeleteAllFollowUps
+type FollowUpCleanup struct {
+	Deleted int
+	// Skipped ephemeral follow-ups, they disappear on their own
+	Skipped int
+}
+
+// FollowUpCleanupError Follow-ups DeleteAllFollowUps failed to delete, keyed by message ID
+type FollowUpCleanupError struct {
+	Failed map[Snowflake]error
+}
+
+func (e *FollowUpCleanupError) Error() string {
+	messages := make([]string, 0, len(e.Failed))
+	for id, err := range e.Failed {
+		messages = append(messages, fmt.Sprintf("%s: %v", id, err))
+	}
+
+	sort.Strings(messages)
+	return "httpcord: deleting follow-ups: " + strings.Join(messages, "; ")
+}
+
+// GetFollowUpMessage Fetch a follow-up message of an interaction
+func (c *RestClient) GetFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake) (*Message, error) {
+	var message Message
+	err := c.Do(ctx, http.MethodGet, endpoints.WebhookMessage(applicationID.String(), token, messageID.String()), nil, &message, withoutAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// EditFollowUpMessage Edit a follow-up message of an interaction
+func (c *RestClient) EditFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake, data *WebhookEdit) (*Message, error) {
+	if data == nil {
+		return nil, ErrNilPayload
+	}
+
+	var message Message
+	err := c.Do(ctx, http.MethodPatch, endpoints.WebhookMessage(applicationID.String(), token, messageID.String()), data.withAttachments(), &message,
+		withoutAuth(), WithFiles(data.Files...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// DeleteFollowUpMessage Delete a follow-up message of an interaction
+func (c *RestClient) DeleteFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.WebhookMessage(applicationID.String(), token, messageID.String()), nil, nil, withoutAuth())
+}
+
+// createFollowUp Send the follow-up and remember it for DeleteAllFollowUps
+func (ctx *ConnectionContext) createFollowUp(c context.Context, data *WebhookEdit) (*Message, error) {
+	data = ctx.options.webhookMentions(data)
+
+	message, err := ctx.webhooks.CreateFollowUpMessage(c, ctx.Interaction.ApplicationID, ctx.Interaction.Token, data)
+	if err != nil {
+		return nil, err
+	}
+
+	followUp := trackedFollowUp{ID: message.ID, Ephemeral: (message.Flags|data.Flags)&EphemeralMessageFlag != 0}
+
+	ctx.state.mu.Lock()
+	ctx.state.followUps = append(ctx.state.followUps, followUp)
+	tracked := append([]trackedFollowUp(nil), ctx.state.followUps...)
+	ctx.state.mu.Unlock()
+
+	// Stored for the background work outliving the request, the store is the source of DeleteAllFollowUps when shared
+	if value, err := json.Marshal(tracked); err == nil {
+		if err := ctx.options.StateStore.Set(followUpsStatePrefix+ctx.Interaction.ID.String(), value, InteractionTokenLifetime); err != nil {
+			ctx.options.Logger.Warn("storing the follow-ups failed", "interaction", ctx.Interaction.ID, "error", err)
+		}
+	}
+
+	return message, nil
+}
+
+// FetchFollowUp Follow-up message of the interaction by ID, while the token is valid. Like EditReply the call is
+// not bound to Context, it works from deferred work
+func (ctx *ConnectionContext) FetchFollowUp(messageID Snowflake) (*Message, error) {
+	return ctx.webhooks.GetFollowUpMessage(context.Background(), ctx.Interaction.ApplicationID, ctx.Interaction.Token, messageID)
+}
+
+// EditFollowUp Edit the follow-up message of the interaction with the ID returned by FollowUp, while the token is
+// valid. Like EditReply the call is not bound to Context
+func (ctx *ConnectionContext) EditFollowUp(messageID Snowflake, data *WebhookEdit) (*Message, error) {
+	return ctx.webhooks.EditFollowUpMessage(context.Background(), ctx.Interaction.ApplicationID, ctx.Interaction.Token, messageID, ctx.options.webhookMentions(data))
+}
+
+// DeleteFollowUp Delete the follow-up message of the interaction, DeleteAllFollowUps does not try it again
+func (ctx *ConnectionContext) DeleteFollowUp(messageID Snowflake) error {
+	if err := ctx.webhooks.DeleteFollowUpMessage(context.Background(), ctx.Interaction.ApplicationID, ctx.Interaction.Token, messageID); err != nil {
+		return err
+	}
+
+	var remaining []trackedFollowUp
+	for _, followUp := range ctx.trackedFollowUps() {
+		if followUp.ID != messageID {
+			remaining = append(remaining, followUp)
+		}
+	}
+
+	ctx.state.mu.Lock()
+	ctx.state.followUps = remaining
+	ctx.state.mu.Unlock()
+
+	if value, err := json.Marshal(remaining); err == nil {
+		ctx.options.StateStore.Set(followUpsStatePrefix+ctx.Interaction.ID.String(), value, InteractionTokenLifetime)
+	}
+
+	return nil
+}
+
+// FetchOriginalReply Original response message of the interaction, retried while Discord has not processed it yet.
+// Like EditReply the call is not bound to Context
+func (ctx *ConnectionContext) FetchOriginalReply() (*Message, error) {
+	return ctx.originalResponse(context.Background())
+}
+
+// trackedFollowUps Follow-ups of the interaction, from the state store when it has them
+func (ctx *ConnectionContext) trackedFollowUps() []trackedFollowUp {
+	ctx.state.mu.Lock()
+	tracked := append([]trackedFollowUp(nil), ctx.state.followUps...)
+	ctx.state.mu.Unlock()
+
+	value, ok, err := ctx.options.StateStore.Get(followUpsStatePrefix + ctx.Interaction.ID.String())
+	if err != nil || !ok {
+		return tracked
+	}
+
+	var stored []trackedFollowUp
+	if json.Unmarshal(value, &stored) != nil || len(stored) < len(tracked) {
+		return tracked
+	}
+
+	return stored
+}
+
+// DeleteAllFollowUps Delete every follow-up created through the context, best effort.
+// Ephemeral follow-ups are skipped and the ones already deleted count as deleted, the failed deletions are returned in a
+// *FollowUpCleanupError
+func (ctx *ConnectionContext) DeleteAllFollowUps(c context.Context) (*FollowUpCleanup, error) {
+	result := &FollowUpCleanup{}
+	failed := make(map[Snowflake]error)
+
+	var remaining []trackedFollowUp
+
+	for _, followUp := range ctx.trackedFollowUps() {
+		if followUp.Ephemeral {
+			result.Skipped++
+			continue
+		}
+
+		err := ctx.webhooks.DeleteFollowUpMessage(c, ctx.Interaction.ApplicationID, ctx.Interaction.Token, followUp.ID)
+		if err != nil && !IsUnknownMessage(err) {
+			failed[followUp.ID] = err
+			remaining = append(remaining, followUp)
+			continue
+		}
+
+		result.Deleted++
+	}
+
+	ctx.state.mu.Lock()
+	ctx.state.followUps = remaining
+	ctx.state.mu.Unlock()
+
+	key := followUpsStatePrefix + ctx.Interaction.ID.String()
+	if len(remaining) == 0 {
+		ctx.options.StateStore.Delete(key)
+	} else if value, err := json.Marshal(remaining); err == nil {
+		ctx.options.StateStore.Set(key, value, InteractionTokenLifetime)
+	}
+
+	if len(failed) > 0 {
+		return result, &FollowUpCleanupError{Failed: failed}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package httpcord_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestDeleteAllFollowUps(t *testing.T) {
+	webhook := "/webhooks/" + httpcordtest.TestApplicationID.String() + "/" + httpcordtest.TestToken
+
+	tests := []struct {
+		name string
+		// script Answers of the deletions, the others succeed
+		script  func(f *httpcordtest.FakeDiscord)
+		deleted int
+		// failed Follow-ups left to delete by the next call
+		failed []httpcord.Snowflake
+	}{
+		{"every follow-up", func(f *httpcordtest.FakeDiscord) {}, 2, nil},
+		{"already deleted", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodDelete, webhook+"/messages/11").RespondError(http.StatusNotFound, httpcord.UnknownMessageErrorCode, "Unknown Message")
+		}, 2, nil},
+		{"deletion refused", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodDelete, webhook+"/messages/12").RespondError(http.StatusForbidden, httpcord.MissingAccessErrorCode, "Missing Access").Times(1)
+		}, 1, []httpcord.Snowflake{"12"}},
+		{"token expired", func(f *httpcordtest.FakeDiscord) {
+			f.Expect(http.MethodDelete, webhook+"/messages/*").RespondError(http.StatusNotFound, httpcord.UnknownWebhookErrorCode, "Unknown Webhook").Times(2)
+		}, 0, []httpcord.Snowflake{"11", "12"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			for _, id := range []httpcord.Snowflake{"10", "11", "12"} {
+				fake.ExpectFollowUp(httpcordtest.TestApplicationID, httpcordtest.TestToken).RespondWith(httpcord.Message{ID: id}).Times(1)
+			}
+
+			test.script(fake)
+			fake.Allow(http.MethodDelete, webhook+"/messages/*")
+
+			ctx, finish := httpcord.NewContext(*httpcordtest.NewCommandInteraction("session"), httpcord.ContextConfig{
+				Respond: func(*httpcord.InteractionResponse) error { return nil },
+				Client:  fake.Client(),
+			})
+			defer finish()
+
+			for _, flags := range []httpcord.MessageFlag{httpcord.EphemeralMessageFlag, 0, 0} {
+				if _, err := ctx.FollowUp(&httpcord.WebhookEdit{Content: "step", Flags: flags}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := ctx.DeleteAllFollowUps(context.Background())
+			if result.Deleted != test.deleted || result.Skipped != 1 {
+				t.Errorf("DeleteAllFollowUps() = %+v, want %d deleted and the ephemeral one skipped", result, test.deleted)
+			}
+
+			if deletes := fake.RequestsTo(http.MethodDelete, webhook+"/messages/10"); len(deletes) != 0 {
+				t.Error("ephemeral follow-up deleted")
+			}
+
+			var cleanup *httpcord.FollowUpCleanupError
+			if len(test.failed) == 0 {
+				if err != nil {
+					t.Errorf("DeleteAllFollowUps() error %v", err)
+				}
+			} else if !errors.As(err, &cleanup) || len(cleanup.Failed) != len(test.failed) {
+				t.Fatalf("DeleteAllFollowUps() error %v, want the failures of %v", err, test.failed)
+			}
+
+			for _, id := range test.failed {
+				if cleanup.Failed[id] == nil {
+					t.Errorf("failure of %s missing from %v", id, cleanup)
+				}
+			}
+
+			// The next call only retries the failed follow-ups, the ephemeral one is forgotten
+			before := len(fake.RequestsTo(http.MethodDelete, webhook+"/messages/*"))
+
+			result, err = ctx.DeleteAllFollowUps(context.Background())
+			if err != nil || result.Deleted != len(test.failed) || result.Skipped != 0 {
+				t.Errorf("second DeleteAllFollowUps() = %+v, %v, want the %d failed follow-ups deleted", result, err, len(test.failed))
+			}
+
+			if retried := len(fake.RequestsTo(http.MethodDelete, webhook+"/messages/*")) - before; retried != len(test.failed) {
+				t.Errorf("%d deletions retried, want %d", retried, len(test.failed))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package httpcord
+
+import (
+	"context"
+	"testing"
+)
+
+// contextWebhooks Interaction webhooks failing like the REST client once their context is cancelled
+type contextWebhooks struct {
+	InteractionWebhooks
+}
+
+func (contextWebhooks) GetOriginalInteractionResponse(c context.Context, _ Snowflake, _ string) (*Message, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Message{ID: "@original"}, nil
+}
+
+func (contextWebhooks) GetFollowUpMessage(c context.Context, _ Snowflake, _ string, messageID Snowflake) (*Message, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Message{ID: messageID}, nil
+}
+
+func TestFetchAfterHandlerReturned(t *testing.T) {
+	tests := []struct {
+		name  string
+		fetch func(ctx *ConnectionContext) (*Message, error)
+		want  Snowflake
+	}{
+		{"follow-up", func(ctx *ConnectionContext) (*Message, error) { return ctx.FetchFollowUp("5") }, "5"},
+		{"original reply", func(ctx *ConnectionContext) (*Message, error) { return ctx.FetchOriginalReply() }, "@original"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, cancel := context.WithCancel(context.Background())
+			ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}, ContextConfig{
+				Webhooks: contextWebhooks{},
+				Context:  request,
+			})
+			defer finish()
+
+			// The handler returned, its context is done
+			cancel()
+
+			message, err := test.fetch(ctx)
+			if err != nil {
+				t.Fatalf("fetch after the handler returned = %v", err)
+			}
+
+			if message.ID != test.want {
+				t.Errorf("fetched %s, want %s", message.ID, test.want)
+			}
+		})
+	}
+}
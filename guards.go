@@ -0,0 +1,242 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"httpcord/permissions"
+)
+
+// ErrGuardFailed The interaction was refused by a Guard of its route (See GuardError)
+var ErrGuardFailed = errors.New("httpcord: interaction refused by a guard")
+
+// GuardKind Precondition a GuardError failed
+type GuardKind int
+
+// Guard Kinds
+
+const (
+	// GuildOnlyGuard The interaction was not triggered in a guild
+	GuildOnlyGuard GuardKind = iota + 1
+	// UserPermissionsGuard The member lacks Permission in the interaction channel
+	UserPermissionsGuard
+	// ContextGuard The interaction was triggered in a context the route does not allow
+	ContextGuard
+)
+
+// GuardError Returned by the guards of this package, the handler of the route did not run
+type GuardError struct {
+	Kind GuardKind
+	// Permission is the first permission missing for UserPermissionsGuard
+	Permission permissions.PermissionBit
+	// Name is the permission name as shown in Discord
+	Name string
+}
+
+func (e *GuardError) Error() string {
+	switch e.Kind {
+	case GuildOnlyGuard:
+		return "httpcord: the command is only available in guilds"
+	case UserPermissionsGuard:
+		return fmt.Sprintf("httpcord: the member needs the %s permission", e.Name)
+	}
+
+	return "httpcord: the command is not available in this context"
+}
+
+func (e *GuardError) Is(target error) bool {
+	return target == ErrGuardFailed
+}
+
+var (
+	GuildOnlyMessages = Dictionary{
+		EnglishUSLocale:    "This command can only be used in a server.",
+		EnglishGBLocale:    "This command can only be used in a server.",
+		PortugueseBRLocale: "Este comando só pode ser usado em um servidor.",
+		SpanishESLocale:    "Este comando solo se puede usar en un servidor.",
+		FrenchLocale:       "Cette commande ne peut être utilisée que sur un serveur.",
+		GermanLocale:       "Dieser Befehl kann nur auf einem Server verwendet werden.",
+	}
+	MissingUserPermissionMessages = Dictionary{
+		EnglishUSLocale:    "You need the **%s** permission to use this command.",
+		EnglishGBLocale:    "You need the **%s** permission to use this command.",
+		PortugueseBRLocale: "Você precisa da permissão **%s** para usar este comando.",
+		SpanishESLocale:    "Necesitas el permiso **%s** para usar este comando.",
+		FrenchLocale:       "Vous avez besoin de la permission **%s** pour utiliser cette commande.",
+		GermanLocale:       "Du brauchst die Berechtigung **%s**, um diesen Befehl zu verwenden.",
+	}
+	WrongContextMessages = Dictionary{
+		EnglishUSLocale:    "This command can not be used here.",
+		EnglishGBLocale:    "This command can not be used here.",
+		PortugueseBRLocale: "Este comando não pode ser usado aqui.",
+		SpanishESLocale:    "Este comando no se puede usar aquí.",
+		FrenchLocale:       "Cette commande ne peut pas être utilisée ici.",
+		GermanLocale:       "Dieser Befehl kann hier nicht verwendet werden.",
+	}
+)
+
+// Guard Precondition of a route, the error refuses the interaction with ConnectionOptions.ErrorReply
+type Guard func(ctx *ConnectionContext) error
+
+// Guarded Middleware running the handler once every guard passed, in order. The error of the first failing guard is
+// handled like the errors of the handlers, DefaultErrorReply describes the errors of the guards of this package
+func Guarded(guards ...Guard) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx ConnectionContext) {
+			for _, guard := range guards {
+				if err := guard(&ctx); err != nil {
+					ctx.handleError(err)
+					return
+				}
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// guardHandler Handler running the guards first, the handler itself without guards
+func guardHandler(handler Handler, guards []Guard) Handler {
+	if len(guards) == 0 {
+		return handler
+	}
+
+	return Guarded(guards...)(handler)
+}
+
+// GuildOnly Guard refusing the interactions outside guilds
+func GuildOnly() Guard {
+	return func(ctx *ConnectionContext) error {
+		if !ctx.InGuild() {
+			return &GuardError{Kind: GuildOnlyGuard}
+		}
+
+		return nil
+	}
+}
+
+// RequireUserPermissions Guard refusing the members without every permission in the interaction channel, from
+// member.permissions with the administrators allowed. Interactions outside guilds are refused like GuildOnly
+func RequireUserPermissions(required permissions.PermissionBit) Guard {
+	return func(ctx *ConnectionContext) error {
+		granted, ok := ctx.MemberPermissions()
+		if !ok {
+			return &GuardError{Kind: GuildOnlyGuard}
+		}
+
+		if missing := missingPermission(granted, required); missing != 0 {
+			return &GuardError{Kind: UserPermissionsGuard, Permission: missing, Name: PermissionName(missing)}
+		}
+
+		return nil
+	}
+}
+
+// RequireBotPermissions Guard refusing the interactions where the application lacks a permission in the channel,
+// from app_permissions, with a MissingAppPermissionError. Passes when Discord did not send the permissions
+func RequireBotPermissions(required permissions.PermissionBit) Guard {
+	return func(ctx *ConnectionContext) error {
+		granted, ok := ctx.AppPermissions()
+		if !ok {
+			return nil
+		}
+
+		if missing := missingPermission(granted, required); missing != 0 {
+			return &MissingAppPermissionError{Permission: missing, Name: PermissionName(missing)}
+		}
+
+		return nil
+	}
+}
+
+// InContexts Guard refusing the interactions triggered outside the contexts, like BotDMInteractionContext. Uses the
+// context field of the interaction, or the guild of the interaction when Discord did not send it
+func InContexts(contexts ...InteractionContextType) Guard {
+	return func(ctx *ConnectionContext) error {
+		var current InteractionContextType
+
+		switch {
+		case ctx.Interaction.Context != nil:
+			current = *ctx.Interaction.Context
+		case ctx.InGuild():
+			current = GuildInteractionContext
+		default:
+			current = BotDMInteractionContext
+		}
+
+		for _, context := range contexts {
+			if context == current {
+				return nil
+			}
+		}
+
+		return &GuardError{Kind: ContextGuard}
+	}
+}
+
+// missingPermission Lowest permission of required not granted, 0 when every one is or with Administrator
+func missingPermission(granted, required permissions.PermissionBit) permissions.PermissionBit {
+	if granted.Has(required, true) {
+		return 0
+	}
+
+	missing := required &^ granted
+	return missing & -missing
+}
+
+// permissionNames Names of the permissions as shown in Discord
+var permissionNames = map[permissions.PermissionBit]string{
+	permissions.CreateInstantInvite:     "Create Invite",
+	permissions.KickMembers:             "Kick Members",
+	permissions.BanMembers:              "Ban Members",
+	permissions.Administrator:           "Administrator",
+	permissions.ManageChannels:          "Manage Channels",
+	permissions.ManageGuild:             "Manage Server",
+	permissions.AddReactions:            "Add Reactions",
+	permissions.ViewAuditLog:            "View Audit Log",
+	permissions.PrioritySpeaker:         "Priority Speaker",
+	permissions.Stream:                  "Video",
+	permissions.ViewChannel:             "View Channels",
+	permissions.SendMessages:            "Send Messages",
+	permissions.SendTTSMessages:         "Send Text-to-Speech Messages",
+	permissions.ManageMessages:          "Manage Messages",
+	permissions.EmbedLinks:              "Embed Links",
+	permissions.AttachFiles:             "Attach Files",
+	permissions.ReadMessageHistory:      "Read Message History",
+	permissions.MentionEveryone:         "Mention @everyone, @here, and All Roles",
+	permissions.UseExternalEmojis:       "Use External Emojis",
+	permissions.ViewGuildInsights:       "View Server Insights",
+	permissions.Connect:                 "Connect",
+	permissions.Speak:                   "Speak",
+	permissions.MuteMembers:             "Mute Members",
+	permissions.DeafenMembers:           "Deafen Members",
+	permissions.MoveMembers:             "Move Members",
+	permissions.UseVAD:                  "Use Voice Activity",
+	permissions.ChangeNickname:          "Change Nickname",
+	permissions.ManageNicknames:         "Manage Nicknames",
+	permissions.ManageRoles:             "Manage Roles",
+	permissions.ManageWebhooks:          "Manage Webhooks",
+	permissions.ManageEmojisAndStickers: "Manage Emojis and Stickers",
+	permissions.UseApplicationCommands:  "Use Application Commands",
+	permissions.RequestToSpeak:          "Request to Speak",
+	permissions.ManageEvents:            "Manage Events",
+	permissions.ManageThreads:           "Manage Threads",
+	permissions.CreatePublicThreads:     "Create Public Threads",
+	permissions.CreatePrivateThreads:    "Create Private Threads",
+	permissions.UseExternalStickers:     "Use External Stickers",
+	permissions.SendMessagesInThreads:   "Send Messages in Threads",
+	permissions.StartEmbeddedActivities: "Use Activities",
+	permissions.ModerateMembers:         "Timeout Members",
+	permissions.SendPolls:               "Create Polls",
+}
+
+// PermissionName Name of the permission as shown in Discord, the bit number for the permissions unknown to this
+// package
+func PermissionName(permission permissions.PermissionBit) string {
+	if name, ok := permissionNames[permission]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("1 << %d", bits.TrailingZeros64(uint64(permission)))
+}
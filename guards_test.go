@@ -0,0 +1,136 @@
+package httpcord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"httpcord/permissions"
+)
+
+func TestGuards(t *testing.T) {
+	guild := func(granted permissions.PermissionBit) Interaction {
+		return Interaction{GuildID: "2", Member: &Member{User: &User{ID: "4"}, Permissions: granted}}
+	}
+
+	dm := Interaction{User: &User{ID: "4"}}
+
+	inContext := func(interaction Interaction, context InteractionContextType) Interaction {
+		interaction.Context = &context
+		return interaction
+	}
+
+	withApp := func(interaction Interaction, granted permissions.PermissionBit) Interaction {
+		interaction.AppPermissions = &granted
+		return interaction
+	}
+
+	tests := []struct {
+		name        string
+		guard       Guard
+		interaction Interaction
+		// kind Kind of the GuardError, 0 when the guard passes or fails with another error
+		kind GuardKind
+		// missing Permission reported missing
+		missing permissions.PermissionBit
+		// app The guard fails with a MissingAppPermissionError
+		app bool
+	}{
+		{"guild only in a guild", GuildOnly(), guild(0), 0, 0, false},
+		{"guild only in DM", GuildOnly(), dm, GuildOnlyGuard, 0, false},
+		{"user permissions granted", RequireUserPermissions(permissions.BanMembers | permissions.KickMembers),
+			guild(permissions.BanMembers | permissions.KickMembers | permissions.SendMessages), 0, 0, false},
+		{"user permissions as administrator", RequireUserPermissions(permissions.BanMembers), guild(permissions.Administrator), 0, 0, false},
+		{"user permission missing", RequireUserPermissions(permissions.BanMembers | permissions.ManageRoles), guild(permissions.BanMembers),
+			UserPermissionsGuard, permissions.ManageRoles, false},
+		{"lowest user permission missing reported", RequireUserPermissions(permissions.ManageRoles | permissions.KickMembers), guild(0),
+			UserPermissionsGuard, permissions.KickMembers, false},
+		{"user permissions in DM", RequireUserPermissions(permissions.SendMessages), dm, GuildOnlyGuard, 0, false},
+		{"bot permissions granted", RequireBotPermissions(permissions.EmbedLinks), withApp(guild(0), permissions.EmbedLinks|permissions.SendMessages),
+			0, 0, false},
+		{"bot permissions as administrator", RequireBotPermissions(permissions.ManageRoles), withApp(guild(0), permissions.Administrator), 0, 0, false},
+		{"bot permission missing", RequireBotPermissions(permissions.EmbedLinks | permissions.AttachFiles), withApp(guild(0), permissions.EmbedLinks),
+			0, permissions.AttachFiles, true},
+		{"bot permissions not sent", RequireBotPermissions(permissions.EmbedLinks), guild(0), 0, 0, false},
+		{"context sent", InContexts(BotDMInteractionContext, PrivateChannelInteractionContext), inContext(dm, PrivateChannelInteractionContext),
+			0, 0, false},
+		{"context refused", InContexts(GuildInteractionContext), inContext(dm, BotDMInteractionContext), ContextGuard, 0, false},
+		{"guild context without the field", InContexts(GuildInteractionContext), guild(0), 0, 0, false},
+		{"bot DM context without the field", InContexts(BotDMInteractionContext), dm, 0, 0, false},
+		{"guild refused without the field", InContexts(BotDMInteractionContext, PrivateChannelInteractionContext), guild(0), ContextGuard, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := ConnectionContext{Interaction: test.interaction}
+			err := test.guard(&ctx)
+
+			var guardErr *GuardError
+			var appErr *MissingAppPermissionError
+
+			switch {
+			case test.kind == 0 && !test.app:
+				if err != nil {
+					t.Errorf("guard = %v, want nil", err)
+				}
+			case test.app:
+				if !errors.As(err, &appErr) || appErr.Permission != test.missing || appErr.Name != PermissionName(test.missing) {
+					t.Errorf("guard = %v, want the missing app permission %d", err, test.missing)
+				}
+			case !errors.Is(err, ErrGuardFailed) || !errors.As(err, &guardErr) || guardErr.Kind != test.kind || guardErr.Permission != test.missing:
+				t.Errorf("guard = %+v, want the kind %d missing %d", err, test.kind, test.missing)
+			case test.missing != 0 && guardErr.Name != PermissionName(test.missing):
+				t.Errorf("guard reported %q, want %q", guardErr.Name, PermissionName(test.missing))
+			}
+		})
+	}
+}
+
+func TestGuardedRoutes(t *testing.T) {
+	french := func(body []byte) []byte {
+		return bytes.Replace(body, []byte(`"version":1,`), []byte(`"version":1,"locale":"fr",`), 1)
+	}
+
+	tests := []struct {
+		name string
+		body []byte
+		// reply Content of the refusal, empty when the handler runs
+		reply string
+	}{
+		{"allowed member", memberBody(permissions.BanMembers), ""},
+		{"administrator", memberBody(permissions.Administrator), ""},
+		{"missing permission", memberBody(permissions.KickMembers), "You need the **Ban Members** permission to use this command."},
+		{"missing permission in French", french(memberBody(0)), "Vous avez besoin de la permission **Ban Members** pour utiliser cette commande."},
+		{"DM", inDM(memberBody(permissions.Administrator)), "This command can only be used in a server."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+
+			var ran bool
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			}, GuildOnly(), RequireUserPermissions(permissions.BanMembers))
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data == nil {
+				t.Fatalf("status %d %s, want a reply", w.Code, w.Body)
+			}
+
+			if ran != (test.reply == "") {
+				t.Errorf("handler ran = %v, want %v", ran, test.reply == "")
+			}
+
+			if test.reply != "" && (response.Data.Content != test.reply || !response.Data.Flags.Has(EphemeralMessageFlag)) {
+				t.Errorf("reply %q, want the ephemeral %q", response.Data.Content, test.reply)
+			}
+		})
+	}
+}
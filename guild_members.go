@@ -0,0 +1,83 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"httpcord/endpoints"
+)
+
+// MaxGuildMembersLimit Maximum members returned by a search or a members page
+const MaxGuildMembersLimit = 1000
+
+// ErrMembersLimit The limit is outside 1 to MaxGuildMembersLimit
+var ErrMembersLimit = errors.New("httpcord: members limit must be between 1 and 1000")
+
+// SearchGuildMembers Members whose username or nickname starts with the query, limit must be 1 to MaxGuildMembersLimit
+func (c *RestClient) SearchGuildMembers(ctx context.Context, guildID Snowflake, query string, limit int) ([]*Member, error) {
+	if limit < 1 || limit > MaxGuildMembersLimit {
+		return nil, ErrMembersLimit
+	}
+
+	var members []*Member
+	err := c.Do(ctx, http.MethodGet, endpoints.GuildMembersSearch(guildID.String()), nil, &members,
+		WithQuery(url.Values{"query": {query}, "limit": {strconv.Itoa(limit)}}))
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// MemberPaginator Pages of the guild members ordered by user ID, see ListGuildMembers
+type MemberPaginator = CursorPaginator[*Member]
+
+// ListGuildMembers Paginate the guild members, pageSize must be 1 to MaxGuildMembersLimit.
+// Listing members needs the GUILD_MEMBERS privileged intent
+func (c *RestClient) ListGuildMembers(guildID Snowflake, pageSize int) (*MemberPaginator, error) {
+	if pageSize < 1 || pageSize > MaxGuildMembersLimit {
+		return nil, ErrMembersLimit
+	}
+
+	fetch := func(ctx context.Context, after Snowflake, limit int) ([]*Member, error) {
+		var members []*Member
+		err := c.Do(ctx, http.MethodGet, endpoints.GuildMembers(guildID.String()), nil, &members,
+			WithQuery(url.Values{"limit": {strconv.Itoa(limit)}, "after": {after.String()}}))
+
+		return members, err
+	}
+
+	return newCursorPaginator(pageSize, fetch, memberID), nil
+}
+
+// memberID User ID of the member, the cursor of the members pages
+func memberID(member *Member) Snowflake {
+	if member == nil || member.User == nil {
+		return ""
+	}
+
+	return member.User.ID
+}
+
+// MemberChoices Autocomplete choices of the members, named by nickname or username with the user ID as value
+func MemberChoices(members []*Member) []ApplicationCommandOptionChoice {
+	choices := make([]ApplicationCommandOptionChoice, 0, len(members))
+
+	for _, member := range members {
+		if member.User == nil {
+			continue
+		}
+
+		name := member.User.Username
+		if member.Nick != "" {
+			name = member.Nick + " (" + member.User.Username + ")"
+		}
+
+		choices = append(choices, ApplicationCommandOptionChoice{Name: name, Value: member.User.ID.String()})
+	}
+
+	return choices
+}
@@ -0,0 +1,134 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// membersServer Guild members with the user IDs 1 to count, paginated like Discord
+func membersServer(t *testing.T, count int, queries *[]string) *RestClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		members := []*Member{}
+		for id := after + 1; id <= count && len(members) < limit; id++ {
+			members = append(members, &Member{User: &User{ID: Snowflake(strconv.Itoa(id))}})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(members)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	return client
+}
+
+func TestListGuildMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		pageSize int
+		after    Snowflake
+		err      error
+		members  int
+		requests int
+	}{
+		{"single page", 3, 10, "", nil, 3, 1},
+		{"several pages", 5, 2, "", nil, 5, 3},
+		{"exact pages", 4, 2, "", nil, 4, 3},
+		{"after a member", 5, 2, "3", nil, 2, 2},
+		{"no members", 0, 10, "", nil, 0, 1},
+		{"page size too small", 3, 0, "", ErrMembersLimit, 0, 0},
+		{"page size too large", 3, MaxGuildMembersLimit + 1, "", ErrMembersLimit, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var queries []string
+			client := membersServer(t, test.count, &queries)
+
+			paginator, err := client.ListGuildMembers("1", test.pageSize)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ListGuildMembers() = %v, want %v", err, test.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if test.after != "" {
+				paginator.After(test.after)
+			}
+
+			var p Paginator[*Member] = paginator
+			members, err := AllPages(context.Background(), p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(members) != test.members || len(queries) != test.requests {
+				t.Errorf("%d members in %d requests %v, want %d in %d", len(members), len(queries), queries, test.members, test.requests)
+			}
+
+			if !paginator.Done() {
+				t.Error("the paginator is not done after the last page")
+			}
+		})
+	}
+}
+
+func TestSearchGuildMembers(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		err   error
+	}{
+		{"ascii", "mod", 10, nil},
+		{"unicode", "ゆき & co/?", 1, nil},
+		{"upper bound", "a", MaxGuildMembersLimit, nil},
+		{"limit too small", "a", 0, ErrMembersLimit},
+		{"limit too large", "a", MaxGuildMembersLimit + 1, ErrMembersLimit},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query = ""
+
+			_, err := client.SearchGuildMembers(context.Background(), "1", test.query, test.limit)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("SearchGuildMembers() = %v, want %v", err, test.err)
+			}
+
+			if err == nil && query != test.query {
+				t.Errorf("query %q reached the server, want %q", query, test.query)
+			}
+		})
+	}
+}
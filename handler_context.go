@@ -0,0 +1,108 @@
+package httpcord
+
+import "context"
+
+// InteractionWebhooks Calls of the interaction token made by ConnectionContext, like EditReply and FollowUp.
+// Implemented by RestClient, replaced in unit tests to record the calls without HTTP (See NewContext)
+type InteractionWebhooks interface {
+	GetOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string) (*Message, error)
+	EditOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string, data *WebhookEdit, opts ...EditOption) (*Message, error)
+	DeleteOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string) error
+	CreateFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, data *WebhookEdit) (*Message, error)
+	GetFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake) (*Message, error)
+	EditFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake, data *WebhookEdit) (*Message, error)
+	DeleteFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, messageID Snowflake) error
+}
+
+// ContextConfig Dependencies of a ConnectionContext built by NewContext, unset fields get the defaults of NewConnection
+type ContextConfig struct {
+	Options ConnectionOptions
+	// Respond receives the initial response, its error is returned by SendRes
+	Respond func(response *InteractionResponse) error
+	// Webhooks receive the calls of the interaction token (Defaults to Client)
+	Webhooks InteractionWebhooks
+	// Client is the REST client of the other calls (Defaults to a client of Options.TokenProvider)
+	Client *RestClient
+	// Context is the parent of ConnectionContext.Context (Defaults to context.Background())
+	Context context.Context
+}
+
+// NewContext ConnectionContext of the interaction outside a request, to unit test the handlers without HTTP.
+// finish runs the work started after the response, like a request does once the handlers returned, and waits for the
+// background work. See httpcordtest.NewContext for a recorder of the responses and webhook calls
+func NewContext(interaction Interaction, config ContextConfig) (ctx *ConnectionContext, finish func()) {
+	options := config.Options
+	options.setDefaults()
+
+	client := config.Client
+	if client == nil {
+		client = NewRestClient(options.TokenProvider)
+	}
+
+	client = client.WithApplication(interaction.ApplicationID)
+
+	webhooks := config.Webhooks
+	if webhooks == nil {
+		webhooks = client
+	}
+
+	parent := config.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	handlerCtx, cancel := context.WithCancel(parent)
+
+	life := &lifecycle{pool: newWorkerPool(options.MaxWorkers, options.MaxQueueDepth, options.Metrics)}
+	life.scheduler = newScheduler(&options, client, life)
+
+	ctx = &ConnectionContext{
+		Interaction: interaction,
+		clientToken: options.Token,
+		options:     &options,
+		client:      client,
+		webhooks:    webhooks,
+		state:       &interactionState{},
+		requestID:   newRequestID(),
+		life:        life,
+		context:     handlerCtx,
+	}
+
+	respond := config.Respond
+	ctx.SendRes = func(response *InteractionResponse) error {
+		response = options.responseMentions(response)
+
+		if options.ValidateResponses {
+			if err := ctx.checkAppPermissions(response); err != nil {
+				ctx.handleError(err)
+				return err
+			}
+		}
+
+		claimed, edit, _ := ctx.state.claim(response, options.EditAfterDefer)
+		switch {
+		case edit:
+			return ctx.editDeferred(response)
+		case !claimed:
+			return ErrAlreadyResponded
+		case respond == nil:
+			return nil
+		}
+
+		return respond(response)
+	}
+
+	finish = func() {
+		cancel()
+
+		for _, fn := range ctx.state.takeAfter() {
+			fn()
+		}
+
+		life.drain(context.Background())
+	}
+
+	return ctx, finish
+}
+
+var _ InteractionWebhooks = (*RestClient)(nil)
@@ -0,0 +1,270 @@
+package httpcord_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+// newSignedConnection Connection verifying the requests of a new Signer
+func newSignedConnection(t *testing.T, options httpcord.ConnectionOptions) (*httpcord.Connection, *httpcordtest.Signer) {
+	t.Helper()
+
+	signer := httpcordtest.NewSigner(t)
+	options.PublicKey = signer.PublicKey()
+
+	conn, err := httpcord.NewConnection(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, signer
+}
+
+func TestRequestLimits(t *testing.T) {
+	ping := []byte(`{"id":"1","application_id":"1","type":1,"token":"token","version":1}`)
+	at := func(offset time.Duration) func() time.Time {
+		return func() time.Time { return time.Now().Add(offset) }
+	}
+
+	tests := []struct {
+		name    string
+		options httpcord.ConnectionOptions
+		body    []byte
+		now     func() time.Time
+		// undeclared Hide the Content-Length so the limit applies while reading
+		undeclared bool
+		status     int
+		err        error
+	}{
+		{"fresh ping", httpcord.ConnectionOptions{}, ping, at(0), false, http.StatusOK, nil},
+		{"body at the limit", httpcord.ConnectionOptions{MaxBodySize: int64(len(ping))}, ping, at(0), false, http.StatusOK, nil},
+		{"declared body over the limit", httpcord.ConnectionOptions{MaxBodySize: int64(len(ping)) - 1}, ping, at(0), false,
+			http.StatusRequestEntityTooLarge, httpcord.ErrBodyTooLarge},
+		{"undeclared body over the limit", httpcord.ConnectionOptions{MaxBodySize: int64(len(ping)) - 1}, ping, at(0), true,
+			http.StatusRequestEntityTooLarge, httpcord.ErrBodyTooLarge},
+		{"body over the default limit", httpcord.ConnectionOptions{}, []byte(strings.Repeat(" ", httpcord.DefaultMaxBodySize) + string(ping)), at(0), true,
+			http.StatusRequestEntityTooLarge, httpcord.ErrBodyTooLarge},
+		{"limit disabled", httpcord.ConnectionOptions{MaxBodySize: -1}, []byte(strings.Repeat(" ", httpcord.DefaultMaxBodySize) + string(ping)), at(0), false,
+			http.StatusOK, nil},
+		{"stale timestamp", httpcord.ConnectionOptions{}, ping, at(-httpcord.DefaultMaxTimestampSkew - time.Minute), false,
+			http.StatusUnauthorized, httpcord.ErrStaleTimestamp},
+		{"future timestamp", httpcord.ConnectionOptions{}, ping, at(httpcord.DefaultMaxTimestampSkew + time.Minute), false,
+			http.StatusUnauthorized, httpcord.ErrStaleTimestamp},
+		{"timestamp within the skew", httpcord.ConnectionOptions{MaxTimestampSkew: time.Hour}, ping, at(-30 * time.Minute), false,
+			http.StatusOK, nil},
+		{"skew disabled", httpcord.ConnectionOptions{MaxTimestampSkew: -1}, ping, at(-24 * time.Hour), false, http.StatusOK, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var failure error
+
+			options := test.options
+			options.RequestErrorHandler = func(err error, r *http.Request) { failure = err }
+			options.OnVerificationFailure = func(r *http.Request, err error) { failure = err }
+
+			conn, signer := newSignedConnection(t, options)
+			signer.Now = test.now
+
+			r := signer.NewRequest(test.body)
+			if test.undeclared {
+				r.ContentLength = -1
+				r.Body = io.NopCloser(io.MultiReader(r.Body))
+			}
+
+			if res := httpcordtest.Serve(t, conn, r); res.Status != test.status {
+				t.Errorf("status %d, want %d: %s", res.Status, test.status, res.Body)
+			}
+
+			if !errors.Is(failure, test.err) {
+				t.Errorf("error %v, want %v", failure, test.err)
+			}
+		})
+	}
+
+	t.Run("malformed timestamp", func(t *testing.T) {
+		var failure error
+
+		conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{
+			OnVerificationFailure: func(r *http.Request, err error) { failure = err },
+		})
+
+		r := signer.NewRequest(ping)
+		r.Header.Set("X-Signature-Timestamp", "yesterday")
+
+		if res := httpcordtest.Serve(t, conn, r); res.Status != http.StatusUnauthorized || !errors.Is(failure, httpcord.ErrStaleTimestamp) {
+			t.Errorf("status %d with %v, want 401 with ErrStaleTimestamp", res.Status, failure)
+		}
+	})
+}
+
+// failingCodec Codec decoding the requests but refusing to encode the responses
+type failingCodec struct {
+	httpcord.Codec
+}
+
+func (failingCodec) Marshal(v interface{}) ([]byte, error) {
+	return nil, errors.New("unsupported value")
+}
+
+func TestErrorHandler(t *testing.T) {
+	ping := []byte(`{"id":"1","application_id":"1","type":1,"token":"token","version":1}`)
+
+	tests := []struct {
+		name   string
+		codec  httpcord.Codec
+		req    func(signer *httpcordtest.Signer) *http.Request
+		status int
+		err    error
+		// interaction Type of the interaction RequestInteraction returns, 0 when it was not decoded
+		interaction httpcord.InteractionType
+	}{
+		{"malformed json", nil, func(s *httpcordtest.Signer) *http.Request { return s.NewRequest([]byte(`{"id":"1","type":`)) },
+			http.StatusBadRequest, nil, 0},
+		{"empty body", nil, func(s *httpcordtest.Signer) *http.Request { return s.NewRequest(nil) }, http.StatusBadRequest, nil, 0},
+		{"not an object", nil, func(s *httpcordtest.Signer) *http.Request { return s.NewRequest([]byte(`[1,2]`)) }, http.StatusBadRequest, nil, 0},
+		{"pong encoding failed", failingCodec{httpcord.StdCodec}, func(s *httpcordtest.Signer) *http.Request { return s.NewRequest(ping) },
+			http.StatusOK, httpcord.ErrResponseEncoding, httpcord.PingInteraction},
+		{"response encoding failed", failingCodec{httpcord.StdCodec}, func(s *httpcordtest.Signer) *http.Request {
+			return s.NewInteractionRequest(t, httpcordtest.NewCommandInteraction("ban"))
+		}, http.StatusInternalServerError, httpcord.ErrResponseEncoding, httpcord.ApplicationCommandInteraction},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var failure error
+			var interaction *httpcord.Interaction
+			calls := 0
+
+			conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{
+				Codec:  test.codec,
+				Logger: httpcord.NopLogger,
+				RequestErrorHandler: func(err error, r *http.Request) {
+					calls++
+					failure = err
+					interaction, _ = httpcord.RequestInteraction(r)
+				},
+			})
+
+			conn.Command("ban", func(ctx httpcord.ConnectionContext) {
+				ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "banned"})
+			})
+
+			if res := httpcordtest.Serve(t, conn, test.req(signer)); res.Status != test.status {
+				t.Errorf("status %d, want %d", res.Status, test.status)
+			}
+
+			if calls != 1 {
+				t.Fatalf("RequestErrorHandler called %d times, want once", calls)
+			}
+
+			var malformed *httpcord.MalformedInteractionError
+			if test.err == nil && !errors.As(failure, &malformed) {
+				t.Errorf("error %v, want a MalformedInteractionError", failure)
+			} else if test.err != nil && !errors.Is(failure, test.err) {
+				t.Errorf("error %v, want %v", failure, test.err)
+			}
+
+			if test.interaction == 0 && interaction != nil {
+				t.Errorf("RequestInteraction() = %+v for an undecoded request", interaction)
+			} else if test.interaction != 0 && (interaction == nil || interaction.Type != test.interaction) {
+				t.Errorf("RequestInteraction() = %+v, want the %d interaction", interaction, test.interaction)
+			} else if test.interaction == httpcord.ApplicationCommandInteraction && interaction.ApplicationCommandData().Name != "ban" {
+				t.Errorf("RequestInteraction() = %+v, want the ban command", interaction)
+			}
+		})
+	}
+}
+
+func TestDeprecatedErrorHandler(t *testing.T) {
+	var order []string
+
+	conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{
+		Logger:              httpcord.NopLogger,
+		RequestErrorHandler: func(err error, r *http.Request) { order = append(order, "request") },
+		ErrorHandler: func(err error, w http.ResponseWriter, r *http.Request) {
+			var malformed *httpcord.MalformedInteractionError
+			if w == nil || r == nil || !errors.As(err, &malformed) {
+				t.Errorf("ErrorHandler(%v, %v, %v), want the malformed error with the writer and the request", err, w, r)
+			}
+
+			order = append(order, "deprecated")
+		},
+	})
+
+	if res := httpcordtest.Serve(t, conn, signer.NewRequest([]byte(`{"id":`))); res.Status != http.StatusBadRequest {
+		t.Errorf("status %d, want 400", res.Status)
+	}
+
+	if strings.Join(order, ",") != "request,deprecated" {
+		t.Errorf("handlers called %v, want RequestErrorHandler then ErrorHandler", order)
+	}
+
+	reported := false
+	for _, notice := range conn.DeprecationReport() {
+		reported = reported || notice.Symbol == "ConnectionOptions.ErrorHandler" && notice.Replacement == "ConnectionOptions.RequestErrorHandler"
+	}
+
+	if !reported {
+		t.Errorf("DeprecationReport() = %+v, want ConnectionOptions.ErrorHandler", conn.DeprecationReport())
+	}
+}
+
+func TestOptionConstraints(t *testing.T) {
+	prune := func(locale string, options ...httpcordtest.CommandOption) *httpcord.Interaction {
+		interaction := httpcordtest.NewCommandInteraction("prune", options...)
+		interaction.Locale = locale
+		return interaction
+	}
+
+	count := httpcordtest.IntOption("count", 10)
+	before := httpcordtest.StringOption("before_id", "7")
+	user := httpcordtest.UserOption("user", &httpcord.User{ID: "6", Username: "target"})
+
+	tests := []struct {
+		name        string
+		interaction *httpcord.Interaction
+		// reply Content of the error reply, empty when the handler runs
+		reply string
+	}{
+		{"one of the exclusive options", prune("en-US", count), ""},
+		{"the other exclusive option", prune("en-US", before, user), ""},
+		{"exclusive options together", prune("en-US", count, before), "The options `count`, `before_id` cannot be used together."},
+		{"none of the required group", prune("en-US", user), "You must provide one of the options `count`, `before_id`."},
+		{"localized reply", prune("fr", count, before), "Les options `count`, `before_id` ne peuvent pas être utilisées ensemble."},
+		{"unknown locale falls back to English", prune("xx", user), "You must provide one of the options `count`, `before_id`."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+
+			var ran bool
+			conn.Command("prune", func(ctx httpcord.ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "pruned"})
+			}).MutuallyExclusive("count", "before_id").RequireOneOf("count", "before_id")
+
+			res := httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, test.interaction))
+			if res.Response == nil || res.Response.Data == nil {
+				t.Fatalf("%d %s, want a reply", res.Status, res.Body)
+			}
+
+			if ran != (test.reply == "") {
+				t.Errorf("handler ran = %v, want %v", ran, test.reply == "")
+			}
+
+			data := res.Response.Data
+			if test.reply != "" && (data.Content != test.reply || !data.Flags.Has(httpcord.EphemeralMessageFlag)) {
+				t.Errorf("reply %q with the flags %d, want the ephemeral %q", data.Content, data.Flags, test.reply)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package httpcord
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const helpComponentPrefix = "httpcord:help:"
+
+var (
+	HelpTitleMessages = Dictionary{
+		EnglishUSLocale:    "Commands",
+		EnglishGBLocale:    "Commands",
+		PortugueseBRLocale: "Comandos",
+		SpanishESLocale:    "Comandos",
+		FrenchLocale:       "Commandes",
+		GermanLocale:       "Befehle",
+	}
+	HelpPageMessages = Dictionary{
+		EnglishUSLocale:    "Page %d of %d",
+		EnglishGBLocale:    "Page %d of %d",
+		PortugueseBRLocale: "Página %d de %d",
+		SpanishESLocale:    "Página %d de %d",
+		FrenchLocale:       "Page %d sur %d",
+		GermanLocale:       "Seite %d von %d",
+	}
+	HelpRequiredMessages = Dictionary{
+		EnglishUSLocale:    "required",
+		EnglishGBLocale:    "required",
+		PortugueseBRLocale: "obrigatório",
+		SpanishESLocale:    "obligatorio",
+		FrenchLocale:       "obligatoire",
+		GermanLocale:       "erforderlich",
+	}
+	HelpUnknownMessages = Dictionary{
+		EnglishUSLocale:    "Unknown command `%s`.",
+		EnglishGBLocale:    "Unknown command `%s`.",
+		PortugueseBRLocale: "Comando `%s` desconhecido.",
+		SpanishESLocale:    "Comando `%s` desconocido.",
+		FrenchLocale:       "Commande `%s` inconnue.",
+		GermanLocale:       "Unbekannter Befehl `%s`.",
+	}
+)
+
+type HelpOption func(o *helpOptions)
+
+type helpOptions struct {
+	name          string
+	localizations Dictionary
+	description   string
+	pageSize      int
+	// color of the embeds, nil without HelpColor
+	color *Color
+}
+
+// HelpName Name of the help command and its localizations
+func HelpName(name string, localizations Dictionary) HelpOption {
+	return func(o *helpOptions) {
+		o.name = name
+		o.localizations = localizations
+	}
+}
+
+// HelpDescription Description of the help command
+func HelpDescription(description string) HelpOption {
+	return func(o *helpOptions) {
+		o.description = description
+	}
+}
+
+// HelpPageSize Commands listed per page
+func HelpPageSize(size int) HelpOption {
+	return func(o *helpOptions) {
+		if size > 0 {
+			o.pageSize = size
+		}
+	}
+}
+
+// HelpColor Color of the help embeds (Defaults to no color)
+func HelpColor(color Color) HelpOption {
+	return func(o *helpOptions) {
+		o.color = &color
+	}
+}
+
+type helpCommand struct {
+	helpOptions
+	router *commandRouter
+}
+
+// EnableHelpCommand Register a help command listing every route with a definition, grouped by CommandRoute.Group.
+// Commands whose DefaultPermissions the member lacks are hidden.
+// The returned route definition must still be registered in Discord with the other commands
+func (c *Connection) EnableHelpCommand(opts ...HelpOption) *CommandRoute {
+	h := &helpCommand{
+		helpOptions: helpOptions{name: "help", description: "List the commands", pageSize: 10},
+		router:      c.router,
+	}
+
+	for _, opt := range opts {
+		opt(&h.helpOptions)
+	}
+
+	definition := NewCommandBuilder().
+		SetName(h.name).
+		SetNameLocalizations(h.localizations).
+		SetDescription(h.description).
+		AddOption(ApplicationCommandOption{
+			Type:         StringApplicationCommandOptionType,
+			Name:         "command",
+			Description:  "Show the details of a command",
+			Autocomplete: true,
+		})
+
+	c.router.addComponent(helpComponentPrefix, h.page)
+
+	return c.router.add(&CommandRoute{Name: h.name, Handler: h.handle, Source: callerSite(1)}).
+		SetDefinition(definition).
+		SetAutocomplete(h.autocomplete)
+}
+
+// visible Routes the interaction member can use, sorted by group and name
+func (h *helpCommand) visible(ctx ConnectionContext) []*CommandRoute {
+	var routes []*CommandRoute
+
+	for _, route := range h.router.routes() {
+		if route.Definition == nil {
+			continue
+		}
+
+		if bits := route.Definition.DefaultPermissions; bits != nil && ctx.Interaction.Member != nil && !ctx.Interaction.Member.Permissions.Has(*bits, true) {
+			continue
+		}
+
+		routes = append(routes, route)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Group < routes[j].Group
+	})
+
+	return routes
+}
+
+func (h *helpCommand) handle(ctx ConnectionContext) {
+	data := ctx.Interaction.ApplicationCommandData()
+	locale := Locale(ctx.Interaction.Locale)
+
+	for _, option := range data.Options {
+		if name, ok := option.Value.(string); ok && option.Name == "command" && name != "" {
+			ctx.ReplyInteraction(h.renderCommand(ctx, name, locale))
+			return
+		}
+	}
+
+	ctx.ReplyInteraction(h.render(ctx, 0, locale))
+}
+
+func (h *helpCommand) page(ctx ConnectionContext) {
+	page, _ := strconv.Atoi(strings.TrimPrefix(ctx.Interaction.ComponentData().CustomID, helpComponentPrefix))
+	ctx.UpdateMessage(h.render(ctx, page, Locale(ctx.Interaction.Locale)))
+}
+
+func (h *helpCommand) autocomplete(ctx ConnectionContext) {
+	data := ctx.Interaction.ApplicationCommandData()
+	query := ""
+
+	for _, option := range leafOptions(data.Options) {
+		if option.Focused {
+			query, _ = option.Value.(string)
+		}
+	}
+
+	routes := h.visible(ctx)
+	candidates := make([]ApplicationCommandOptionChoice, len(routes))
+
+	for i, route := range routes {
+		candidates[i] = ApplicationCommandOptionChoice{
+			Name:              route.Definition.Name,
+			NameLocalizations: route.Definition.NameLocalizations,
+			Value:             route.Name,
+		}
+	}
+
+	ctx.RespondAutocomplete(AutocompleteFilter(candidates, query, AutocompleteLocale(Locale(ctx.Interaction.Locale))))
+}
+
+// render Embed listing the commands of the page
+func (h *helpCommand) render(ctx ConnectionContext, page int, locale Locale) *InteractionCallbackData {
+	routes := h.visible(ctx)
+	pages := (len(routes) + h.pageSize - 1) / h.pageSize
+
+	if pages == 0 {
+		pages = 1
+	}
+
+	if page < 0 || page >= pages {
+		page = 0
+	}
+
+	start := page * h.pageSize
+	end := start + h.pageSize
+
+	if end > len(routes) {
+		end = len(routes)
+	}
+
+	embed := NewEmbedBuilder().
+		SetTitle(HelpTitleMessages.Get(locale, HelpTitleMessages[EnglishUSLocale])).
+		SetFooter(&EmbedFooter{Text: fmt.Sprintf(HelpPageMessages.Get(locale, HelpPageMessages[EnglishUSLocale]), page+1, pages)})
+	if h.color != nil {
+		embed.SetColor(*h.color)
+	}
+
+	var field *EmbedField
+
+	for _, route := range routes[start:end] {
+		group := route.Group
+		if group == "" {
+			group = HelpTitleMessages.Get(locale, HelpTitleMessages[EnglishUSLocale])
+		}
+
+		if field == nil || field.Name != group {
+			field = &EmbedField{Name: group}
+			embed.AddField(field)
+		} else {
+			field.Value += "\n"
+		}
+
+		def := route.Definition
+		field.Value += fmt.Sprintf("`/%s` %s", def.NameLocalizations.Get(locale, def.Name), def.DescriptionLocalizations.Get(locale, def.Description))
+	}
+
+	data := &InteractionCallbackData{Embeds: []*Embed{embed}, Flags: EphemeralMessageFlag}
+
+	if pages > 1 {
+		data.Components = []*ActionRowComponent{
+			NewActionRowComponentBuilder().SetComponents(
+				NewButtonComponentBuilder().SetStyle(SecondaryButtonStyle).SetLabel("◀").
+					SetCustomID(helpComponentPrefix+strconv.Itoa(page-1)).IsDisabled(page == 0),
+				NewButtonComponentBuilder().SetStyle(SecondaryButtonStyle).SetLabel("▶").
+					SetCustomID(helpComponentPrefix+strconv.Itoa(page+1)).IsDisabled(page == pages-1),
+			),
+		}
+	}
+
+	return data
+}
+
+// renderCommand Embed with the details of a command
+func (h *helpCommand) renderCommand(ctx ConnectionContext, name string, locale Locale) *InteractionCallbackData {
+	var command *ApplicationCommand
+
+	for _, route := range h.visible(ctx) {
+		if route.Name == name {
+			command = route.Definition
+		}
+	}
+
+	if command == nil {
+		return &InteractionCallbackData{
+			Content: fmt.Sprintf(HelpUnknownMessages.Get(locale, HelpUnknownMessages[EnglishUSLocale]), name),
+			Flags:   EphemeralMessageFlag,
+		}
+	}
+
+	embed := NewEmbedBuilder().
+		SetTitle("/" + command.NameLocalizations.Get(locale, command.Name)).
+		SetDescription(command.DescriptionLocalizations.Get(locale, command.Description))
+	if h.color != nil {
+		embed.SetColor(*h.color)
+	}
+
+	required := HelpRequiredMessages.Get(locale, HelpRequiredMessages[EnglishUSLocale])
+
+	for _, option := range command.Options {
+		value := option.Description
+		if option.Required {
+			value += " (" + required + ")"
+		}
+
+		if value == "" {
+			value = "-"
+		}
+
+		embed.AddField(&EmbedField{Name: option.Name, Value: value})
+	}
+
+	return &InteractionCallbackData{Embeds: []*Embed{embed}, Flags: EphemeralMessageFlag}
+}
@@ -0,0 +1,118 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"httpcord/permissions"
+)
+
+// helpBody Interaction of a member with the permissions, viewing the help in the locale
+func helpBody(kind InteractionType, locale Locale, permissions, data string) []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":%d,"token":"token","version":1,"guild_id":"2","channel_id":"3","locale":"%s",`+
+		`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"%s"},"data":%s}`, nowSnowflake(), kind, locale, permissions, data))
+}
+
+func TestHelpCommand(t *testing.T) {
+	ban := permissions.BanMembers
+
+	const help = `{"id":"9","name":"help","type":1,"options":[%s]}`
+
+	tests := []struct {
+		name    string
+		options []HelpOption
+		body    []byte
+		// want Snapshot of the rendered response
+		want string
+	}{
+		{"grouped listing", nil, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, "")),
+			`{"type":4,"data":{"embeds":[{"title":"Commands","timestamp":null,"footer":{"text":"Page 1 of 1"},"fields":[` +
+				`{"name":"Commands","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/ban` Ban a member\\n`/kick` Kick a member" + `"},` +
+				`{"name":"Utility","value":"` + "`/ping` Check the latency" + `"}]}],"flags":64,"components":null}}`},
+		{"color", []HelpOption{HelpColor(0x5865f2)}, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, "")),
+			`{"type":4,"data":{"embeds":[{"title":"Commands","timestamp":null,"color":5793266,"footer":{"text":"Page 1 of 1"},"fields":[` +
+				`{"name":"Commands","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/ban` Ban a member\\n`/kick` Kick a member" + `"},` +
+				`{"name":"Utility","value":"` + "`/ping` Check the latency" + `"}]}],"flags":64,"components":null}}`},
+		{"black color", []HelpOption{HelpColor(BlackColor)}, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ban"}`)),
+			`{"type":4,"data":{"embeds":[{"title":"/ban","description":"Ban a member","timestamp":null,"color":0,"fields":[` +
+				`{"name":"user","value":"Member to ban (required)"},{"name":"reason","value":"-"}]}],"flags":64,"components":null}}`},
+		{"localized", nil, helpBody(ApplicationCommandInteraction, FrenchLocale, "8", fmt.Sprintf(help, "")),
+			`{"type":4,"data":{"embeds":[{"title":"Commandes","timestamp":null,"footer":{"text":"Page 1 sur 1"},"fields":[` +
+				`{"name":"Commandes","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/bannir` Bannir un membre\\n`/kick` Kick a member" + `"},` +
+				`{"name":"Utility","value":"` + "`/ping` Check the latency" + `"}]}],"flags":64,"components":null}}`},
+		{"commands the member lacks the permissions of hidden", nil, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "2", fmt.Sprintf(help, "")),
+			`{"type":4,"data":{"embeds":[{"title":"Commands","timestamp":null,"footer":{"text":"Page 1 of 1"},"fields":[` +
+				`{"name":"Commands","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/kick` Kick a member" + `"},` +
+				`{"name":"Utility","value":"` + "`/ping` Check the latency" + `"}]}],"flags":64,"components":null}}`},
+		{"first page", []HelpOption{HelpPageSize(2)}, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, "")),
+			`{"type":4,"data":{"embeds":[{"title":"Commands","timestamp":null,"footer":{"text":"Page 1 of 2"},"fields":[` +
+				`{"name":"Commands","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/ban` Ban a member" + `"}]}],"flags":64,` +
+				`"components":[{"type":1,"components":[{"type":2,"custom_id":"httpcord:help:-1","style":2,"label":"◀","disabled":true},` +
+				`{"type":2,"custom_id":"httpcord:help:1","style":2,"label":"▶"}]}]}}`},
+		{"next page", []HelpOption{HelpPageSize(2)}, helpBody(MessageComponentInteraction, EnglishUSLocale, "8", `{"custom_id":"httpcord:help:1","component_type":2}`),
+			`{"type":7,"data":{"embeds":[{"title":"Commands","timestamp":null,"footer":{"text":"Page 2 of 2"},"fields":[` +
+				`{"name":"Moderation","value":"` + "`/kick` Kick a member" + `"},` +
+				`{"name":"Utility","value":"` + "`/ping` Check the latency" + `"}]}],"flags":64,` +
+				`"components":[{"type":1,"components":[{"type":2,"custom_id":"httpcord:help:0","style":2,"label":"◀"},` +
+				`{"type":2,"custom_id":"httpcord:help:2","style":2,"label":"▶","disabled":true}]}]}}`},
+		{"page out of range", []HelpOption{HelpPageSize(2)}, helpBody(MessageComponentInteraction, EnglishUSLocale, "8", `{"custom_id":"httpcord:help:7","component_type":2}`),
+			`{"type":7,"data":{"embeds":[{"title":"Commands","timestamp":null,"footer":{"text":"Page 1 of 2"},"fields":[` +
+				`{"name":"Commands","value":"` + "`/help` List the commands" + `"},` +
+				`{"name":"Moderation","value":"` + "`/ban` Ban a member" + `"}]}],"flags":64,` +
+				`"components":[{"type":1,"components":[{"type":2,"custom_id":"httpcord:help:-1","style":2,"label":"◀","disabled":true},` +
+				`{"type":2,"custom_id":"httpcord:help:1","style":2,"label":"▶"}]}]}}`},
+		{"command details", nil, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ban"}`)),
+			`{"type":4,"data":{"embeds":[{"title":"/ban","description":"Ban a member","timestamp":null,"fields":[` +
+				`{"name":"user","value":"Member to ban (required)"},{"name":"reason","value":"-"}]}],"flags":64,"components":null}}`},
+		{"localized command details", nil, helpBody(ApplicationCommandInteraction, FrenchLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ban"}`)),
+			`{"type":4,"data":{"embeds":[{"title":"/bannir","description":"Bannir un membre","timestamp":null,"fields":[` +
+				`{"name":"user","value":"Member to ban (obligatoire)"},{"name":"reason","value":"-"}]}],"flags":64,"components":null}}`},
+		{"unknown command", nil, helpBody(ApplicationCommandInteraction, GermanLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"warn"}`)),
+			`{"type":4,"data":{"content":"Unbekannter Befehl ` + "`warn`" + `.","flags":64,"components":null}}`},
+		{"details of a hidden command", nil, helpBody(ApplicationCommandInteraction, EnglishUSLocale, "2", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ban"}`)),
+			`{"type":4,"data":{"content":"Unknown command ` + "`ban`" + `.","flags":64,"components":null}}`},
+		{"autocomplete", nil, helpBody(AutoCompleteInteraction, EnglishUSLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"k","focused":true}`)),
+			`{"type":8,"data":{"components":null,"choices":[{"name":"kick","value":"kick"}]}}`},
+		{"autocomplete over localized names", nil, helpBody(AutoCompleteInteraction, FrenchLocale, "8", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ba","focused":true}`)),
+			`{"type":8,"data":{"components":null,"choices":[{"name":"ban","name_localizations":{"fr":"bannir"},"value":"ban"}]}}`},
+		{"autocomplete hides commands", nil, helpBody(AutoCompleteInteraction, EnglishUSLocale, "2", fmt.Sprintf(help, `{"name":"command","type":3,"value":"ba","focused":true}`)),
+			`{"type":8,"data":{"components":null}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+
+			noop := func(ctx ConnectionContext) {}
+
+			conn.Command("ban", noop).SetGroup("Moderation").SetDefinition(NewCommandBuilder().
+				SetName("ban").
+				SetNameLocalizations(Dictionary{FrenchLocale: "bannir"}).
+				SetDescription("Ban a member").
+				SetDescriptionLocalizations(Dictionary{FrenchLocale: "Bannir un membre"}).
+				SetDefaultPermissions(&ban).
+				SetOptions(
+					ApplicationCommandOption{Type: UserApplicationCommandOptionType, Name: "user", Description: "Member to ban", Required: true},
+					ApplicationCommandOption{Type: StringApplicationCommandOptionType, Name: "reason"},
+				))
+			conn.Command("kick", noop).SetGroup("Moderation").SetDefinition(NewCommandBuilder().SetName("kick").SetDescription("Kick a member"))
+			conn.Command("ping", noop).SetGroup("Utility").SetDefinition(NewCommandBuilder().SetName("ping").SetDescription("Check the latency"))
+			conn.Command("hidden", noop)
+			conn.EnableHelpCommand(test.options...)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			if got := strings.TrimSpace(w.Body.String()); got != test.want {
+				t.Errorf("rendered\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
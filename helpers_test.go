@@ -0,0 +1,251 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signedConnection Connection verifying the requests of the returned signer
+func signedConnection(tb testing.TB, options ConnectionOptions) (*Connection, func(body []byte) *http.Request) {
+	tb.Helper()
+
+	conn, sign := timestampedConnection(tb, options)
+
+	return conn, func(body []byte) *http.Request {
+		return sign(body, strconv.FormatInt(time.Now().Unix(), 10))
+	}
+}
+
+// timestampedConnection Connection verifying the requests of the returned signer, signed with the given timestamp
+func timestampedConnection(tb testing.TB, options ConnectionOptions) (*Connection, func(body []byte, timestamp string) *http.Request) {
+	tb.Helper()
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	options.PublicKey = hex.EncodeToString(public)
+
+	conn, err := NewConnection(options)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return conn, func(body []byte, timestamp string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(private, append([]byte(timestamp), body...))))
+		r.Header.Set("X-Signature-Timestamp", timestamp)
+
+		return r
+	}
+}
+
+func newTestConnection(t *testing.T, options ConnectionOptions) *Connection {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options.PublicKey = hex.EncodeToString(public)
+
+	conn, err := NewConnection(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+// nowSnowflake Snowflake created now, the interaction token is not expired
+func nowSnowflake() Snowflake {
+	return SnowflakeFromUint64(uint64(time.Now().UnixMilli()-1420070400000) << 22)
+}
+
+// interactionBody Body of a guild interaction of the type with the data
+func interactionBody(kind InteractionType, data string) []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":%d,"token":"token","version":1,"guild_id":"2","channel_id":"3",`+
+		`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"8"},"data":%s}`, nowSnowflake(), kind, data))
+}
+
+func commandBody() []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":2,"token":"token","version":1,"guild_id":"2","channel_id":"3",`+
+		`"member":{"user":{"id":"4","username":"mod"},"nick":"moderator","roles":[],"permissions":"8"},`+
+		`"data":{"id":"5","name":"ban","type":1,"options":[{"type":6,"name":"user","value":"6"}],`+
+		`"resolved":{"users":{"6":{"id":"6","username":"target"}}}}}`, nowSnowflake()))
+}
+
+// componentBody Component interaction on a message of the bot
+func componentBody() []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":3,"token":"token","version":1,"guild_id":"2","channel_id":"3",`+
+		`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"8"},`+
+		`"message":{"id":"7","channel_id":"3","content":"pick one"},"data":{"custom_id":"pick","component_type":2}}`, nowSnowflake()))
+}
+
+type countingWebhooks struct {
+	InteractionWebhooks
+	edits int32
+}
+
+func (w *countingWebhooks) EditOriginalInteractionResponse(context.Context, Snowflake, string, *WebhookEdit, ...EditOption) (*Message, error) {
+	atomic.AddInt32(&w.edits, 1)
+	return &Message{}, nil
+}
+
+// fakeClock Clock firing its timers when advanced
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at   time.Time
+	fn   func()
+	done bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now()}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, fn func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{at: c.now.Add(d), fn: fn}
+	c.timers = append(c.timers, timer)
+
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		stopped := !timer.done
+		timer.done = true
+		return stopped
+	}
+}
+
+// waitTimers Wait for n timers armed and not fired, when they are armed from another goroutine
+func (c *fakeClock) waitTimers(t *testing.T, n int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		pending := 0
+		for _, timer := range c.timers {
+			if !timer.done {
+				pending++
+			}
+		}
+		c.mu.Unlock()
+
+		if pending >= n {
+			return
+		}
+	}
+
+	t.Fatalf("%d timers not armed in time", n)
+}
+
+// Advance Move the time forward and run the timers due, in the calling goroutine
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+
+	var due []*fakeTimer
+	for _, timer := range c.timers {
+		if !timer.done && !timer.at.After(c.now) {
+			timer.done = true
+			due = append(due, timer)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, timer := range due {
+		timer.fn()
+	}
+}
+
+// followUpServer Server recording the contents of the follow-ups, refusing the ones of the "refused" token
+func followUpServer(t *testing.T) (*RestClient, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var contents []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/webhooks/1/refused" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":10015,"message":"Unknown Webhook"}`))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		var edit WebhookEdit
+		json.Unmarshal(body, &edit)
+
+		mu.Lock()
+		contents = append(contents, edit.Content)
+		mu.Unlock()
+
+		w.Write([]byte(`{"id":"9"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string(nil), contents...)
+	}
+}
+
+// bodyContext Context of the interaction decoded from the body like a request
+func bodyContext(t *testing.T, body []byte) *ConnectionContext {
+	t.Helper()
+
+	var raw APIInteraction
+	if err := DecodeJSON(body, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	interaction, err := resolveInteraction(StdCodec, &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{Logger: NopLogger}})
+	t.Cleanup(finish)
+
+	return ctx
+}
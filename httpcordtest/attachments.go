@@ -0,0 +1,81 @@
+package httpcordtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// snowflakeMin Snowflakes of existing attachments are above it, smaller IDs reference the files[N] parts
+const snowflakeMin = 1 << 22
+
+// CheckAttachments Check the files of a multipart body against the attachments of its payload_json like Discord does:
+// every part is named files[N] and listed with the ID N, and every attachment ID that is not an existing
+// attachment has its part. FakeDiscord fails the test when a request breaks it
+func CheckAttachments(payload []byte, files []*RecordedFile) error {
+	var body struct {
+		Attachments []struct {
+			ID json.Number `json:"id"`
+		} `json:"attachments"`
+		// Interaction responses have the attachments in their data
+		Data *struct {
+			Attachments []struct {
+				ID json.Number `json:"id"`
+			} `json:"attachments"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return fmt.Errorf("httpcordtest: decoding payload_json: %w", err)
+	}
+
+	attachments := body.Attachments
+	if body.Data != nil && len(body.Data.Attachments) > 0 {
+		attachments = body.Data.Attachments
+	}
+
+	listed := make(map[uint64]bool, len(attachments))
+	for _, attachment := range attachments {
+		id, err := strconv.ParseUint(attachment.ID.String(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("httpcordtest: attachment ID %q is not a number", attachment.ID)
+		}
+
+		if id < snowflakeMin {
+			listed[id] = true
+		}
+	}
+
+	parts := make(map[uint64]bool, len(files))
+	for _, file := range files {
+		index, ok := fileIndex(file.Field)
+		if !ok {
+			return fmt.Errorf("httpcordtest: part %q is not named files[N]", file.Field)
+		}
+
+		if !listed[index] {
+			return fmt.Errorf("httpcordtest: part %q has no attachment with the ID %d", file.Field, index)
+		}
+
+		parts[index] = true
+	}
+
+	for id := range listed {
+		if !parts[id] {
+			return fmt.Errorf("httpcordtest: attachment %d has no files[%d] part", id, id)
+		}
+	}
+
+	return nil
+}
+
+// fileIndex N of a files[N] field
+func fileIndex(field string) (uint64, bool) {
+	if !strings.HasPrefix(field, "files[") || !strings.HasSuffix(field, "]") {
+		return 0, false
+	}
+
+	index, err := strconv.ParseUint(field[len("files["):len(field)-1], 10, 64)
+	return index, err == nil
+}
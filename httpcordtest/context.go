@@ -0,0 +1,291 @@
+package httpcordtest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"httpcord"
+)
+
+// ContextOption Configure the context built by NewContext
+type ContextOption func(config *httpcord.ContextConfig)
+
+// WithOptions Connection options of the context, like the ErrorReply or the Translator of the bot
+func WithOptions(options httpcord.ConnectionOptions) ContextOption {
+	return func(config *httpcord.ContextConfig) {
+		config.Options = options
+	}
+}
+
+// WithClient REST client of the calls other than the interaction webhooks, like FakeDiscord.Client
+func WithClient(client *httpcord.RestClient) ContextOption {
+	return func(config *httpcord.ContextConfig) {
+		config.Client = client
+	}
+}
+
+// WithContext Parent of ConnectionContext.Context, cancel it to simulate an aborted request
+func WithContext(ctx context.Context) ContextOption {
+	return func(config *httpcord.ContextConfig) {
+		config.Context = ctx
+	}
+}
+
+// RecordedFollowUpEdit Edit of a follow-up recorded by a ContextRecorder
+type RecordedFollowUpEdit struct {
+	MessageID httpcord.Snowflake
+	Data      *httpcord.WebhookEdit
+}
+
+// RecordedEdit Edit of the original response recorded by a ContextRecorder
+type RecordedEdit struct {
+	Data    *httpcord.WebhookEdit
+	Options []httpcord.EditOption
+}
+
+// ContextRecorder Responses and interaction webhook calls of a context built by NewContext, nothing is sent.
+// Follow-ups get the IDs "1", "2"... in creation order
+type ContextRecorder struct {
+	mu               sync.Mutex
+	interaction      *httpcord.Interaction
+	responses        []*httpcord.InteractionResponse
+	edits            []RecordedEdit
+	followUps        []*httpcord.WebhookEdit
+	followUpEdits    []RecordedFollowUpEdit
+	deletedFollowUps []httpcord.Snowflake
+	replyDeleted     bool
+	finish           func()
+}
+
+// NewContext ConnectionContext of the interaction recording what the handlers send, to call a handler directly:
+//
+//	ctx, recorder := httpcordtest.NewContext(&interaction)
+//	handler(*ctx)
+//	recorder.Finish()
+//	if recorder.LastResponse().Data.Content != "pong" { ... }
+func NewContext(interaction *httpcord.Interaction, opts ...ContextOption) (*httpcord.ConnectionContext, *ContextRecorder) {
+	recorder := &ContextRecorder{interaction: interaction}
+
+	config := httpcord.ContextConfig{Respond: recorder.respond, Webhooks: recorder}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	ctx, finish := httpcord.NewContext(*interaction, config)
+	recorder.finish = finish
+
+	return ctx, recorder
+}
+
+// Finish Run the work the handlers started after the response and wait for their background work, like a request
+// does once the handlers returned
+func (r *ContextRecorder) Finish() {
+	r.finish()
+}
+
+func (r *ContextRecorder) respond(response *httpcord.InteractionResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responses = append(r.responses, response)
+	return nil
+}
+
+// Responses Initial responses sent through SendRes, only the first one is accepted like in a request
+func (r *ContextRecorder) Responses() []*httpcord.InteractionResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*httpcord.InteractionResponse(nil), r.responses...)
+}
+
+// LastResponse Last initial response, nil when nothing was sent
+func (r *ContextRecorder) LastResponse() *httpcord.InteractionResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.responses) == 0 {
+		return nil
+	}
+
+	return r.responses[len(r.responses)-1]
+}
+
+// Edits Edits of the original response like EditReply, in order
+func (r *ContextRecorder) Edits() []RecordedEdit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RecordedEdit(nil), r.edits...)
+}
+
+// LastEdit Data of the last edit of the original response, nil without edits
+func (r *ContextRecorder) LastEdit() *httpcord.WebhookEdit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.edits) == 0 {
+		return nil
+	}
+
+	return r.edits[len(r.edits)-1].Data
+}
+
+// FollowUps Follow-ups created, in order
+func (r *ContextRecorder) FollowUps() []*httpcord.WebhookEdit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*httpcord.WebhookEdit(nil), r.followUps...)
+}
+
+// FollowUpEdits Edits of the follow-ups like EditFollowUp, in order
+func (r *ContextRecorder) FollowUpEdits() []RecordedFollowUpEdit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RecordedFollowUpEdit(nil), r.followUpEdits...)
+}
+
+// DeletedFollowUps IDs of the follow-ups deleted
+func (r *ContextRecorder) DeletedFollowUps() []httpcord.Snowflake {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]httpcord.Snowflake(nil), r.deletedFollowUps...)
+}
+
+// ReplyDeleted Whether the original response was deleted with DeleteReply
+func (r *ContextRecorder) ReplyDeleted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.replyDeleted
+}
+
+// original Original response as Discord would return it, built from the initial response and the edits
+func (r *ContextRecorder) original() *httpcord.Message {
+	message := &httpcord.Message{ID: "@original", ChannelID: r.interaction.ChannelID}
+
+	for _, response := range r.responses {
+		if response.Data != nil {
+			message.Content, message.Embeds, message.Flags = response.Data.Content, response.Data.Embeds, response.Data.Flags
+		}
+	}
+
+	for _, edit := range r.edits {
+		applyEdit(message, edit.Data)
+	}
+
+	return message
+}
+
+// applyEdit Change the fields the edit sets like Discord does, an empty content is left out of the edit
+func applyEdit(message *httpcord.Message, edit *httpcord.WebhookEdit) {
+	if edit == nil {
+		return
+	}
+
+	if edit.Content != "" {
+		message.Content = edit.Content
+	}
+
+	if edit.Embeds != nil {
+		message.Embeds = *edit.Embeds
+	}
+
+	if edit.Flags != 0 {
+		message.Flags = edit.Flags
+	}
+}
+
+func (r *ContextRecorder) GetOriginalInteractionResponse(context.Context, httpcord.Snowflake, string) (*httpcord.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.original(), nil
+}
+
+func (r *ContextRecorder) EditOriginalInteractionResponse(_ context.Context, _ httpcord.Snowflake, _ string, data *httpcord.WebhookEdit, opts ...httpcord.EditOption) (*httpcord.Message, error) {
+	if data == nil {
+		return nil, httpcord.ErrNilPayload
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.edits = append(r.edits, RecordedEdit{Data: data, Options: opts})
+	return r.original(), nil
+}
+
+func (r *ContextRecorder) DeleteOriginalInteractionResponse(context.Context, httpcord.Snowflake, string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.replyDeleted = true
+	return nil
+}
+
+func (r *ContextRecorder) CreateFollowUpMessage(_ context.Context, _ httpcord.Snowflake, _ string, data *httpcord.WebhookEdit) (*httpcord.Message, error) {
+	if data == nil {
+		return nil, httpcord.ErrNilPayload
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.followUps = append(r.followUps, data)
+	return r.followUp(httpcord.Snowflake(strconv.Itoa(len(r.followUps))))
+}
+
+// followUp Follow-up of the ID as Discord would return it, built from its creation and the edits
+func (r *ContextRecorder) followUp(messageID httpcord.Snowflake) (*httpcord.Message, error) {
+	index, err := strconv.Atoi(messageID.String())
+	if err != nil || index < 1 || index > len(r.followUps) {
+		return nil, &httpcord.DiscordAPIError{StatusCode: http.StatusNotFound, Code: httpcord.UnknownMessageErrorCode, Message: "Unknown Message"}
+	}
+
+	message := &httpcord.Message{ID: messageID, ChannelID: r.interaction.ChannelID}
+	applyEdit(message, r.followUps[index-1])
+
+	for _, edit := range r.followUpEdits {
+		if edit.MessageID == messageID {
+			applyEdit(message, edit.Data)
+		}
+	}
+
+	return message, nil
+}
+
+func (r *ContextRecorder) GetFollowUpMessage(_ context.Context, _ httpcord.Snowflake, _ string, messageID httpcord.Snowflake) (*httpcord.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.followUp(messageID)
+}
+
+func (r *ContextRecorder) EditFollowUpMessage(_ context.Context, _ httpcord.Snowflake, _ string, messageID httpcord.Snowflake, data *httpcord.WebhookEdit) (*httpcord.Message, error) {
+	if data == nil {
+		return nil, httpcord.ErrNilPayload
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.followUp(messageID); err != nil {
+		return nil, err
+	}
+
+	r.followUpEdits = append(r.followUpEdits, RecordedFollowUpEdit{MessageID: messageID, Data: data})
+	return r.followUp(messageID)
+}
+
+func (r *ContextRecorder) DeleteFollowUpMessage(_ context.Context, _ httpcord.Snowflake, _ string, messageID httpcord.Snowflake) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deletedFollowUps = append(r.deletedFollowUps, messageID)
+	return nil
+}
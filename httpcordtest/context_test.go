@@ -0,0 +1,84 @@
+package httpcordtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"httpcord"
+)
+
+func TestRecorderOriginalEdits(t *testing.T) {
+	embeds := []*httpcord.Embed{httpcord.NewEmbedBuilder().SetTitle("status")}
+
+	tests := []struct {
+		name    string
+		edit    *httpcord.WebhookEdit
+		err     error
+		content string
+		embeds  int
+	}{
+		{"content", &httpcord.WebhookEdit{Content: "edited"}, nil, "edited", 1},
+		{"embeds only", &httpcord.WebhookEdit{Embeds: &[]*httpcord.Embed{}}, nil, "hello", 0},
+		{"flags only", &httpcord.WebhookEdit{Flags: httpcord.SuppressEmbedsMessageFlag}, nil, "hello", 1},
+		{"nil edit", nil, httpcord.ErrNilPayload, "hello", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interaction := &httpcord.Interaction{Type: httpcord.ApplicationCommandInteraction, ChannelID: "1", Token: "token"}
+			ctx, recorder := NewContext(interaction)
+			ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "hello", Embeds: embeds})
+
+			_, err := recorder.EditOriginalInteractionResponse(context.Background(), "", "token", test.edit)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("EditOriginalInteractionResponse() = %v, want %v", err, test.err)
+			}
+
+			message, _ := recorder.GetOriginalInteractionResponse(context.Background(), "", "token")
+			if message.Content != test.content || len(message.Embeds) != test.embeds {
+				t.Errorf("original = %q with %d embeds, want %q with %d", message.Content, len(message.Embeds), test.content, test.embeds)
+			}
+		})
+	}
+}
+
+func TestRecorderFollowUpEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    *httpcord.WebhookEdit
+		err     error
+		content string
+		embeds  int
+	}{
+		{"content", &httpcord.WebhookEdit{Content: "edited"}, nil, "edited", 1},
+		{"embeds only", &httpcord.WebhookEdit{Embeds: &[]*httpcord.Embed{}}, nil, "hello", 0},
+		{"nil edit", nil, httpcord.ErrNilPayload, "hello", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, recorder := NewContext(&httpcord.Interaction{Type: httpcord.ApplicationCommandInteraction, ChannelID: "1", Token: "token"})
+
+			embeds := []*httpcord.Embed{httpcord.NewEmbedBuilder().SetTitle("status")}
+			created, err := recorder.CreateFollowUpMessage(context.Background(), "", "token", &httpcord.WebhookEdit{Content: "hello", Embeds: &embeds})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := recorder.EditFollowUpMessage(context.Background(), "", "token", created.ID, test.edit); !errors.Is(err, test.err) {
+				t.Fatalf("EditFollowUpMessage() = %v, want %v", err, test.err)
+			}
+
+			message, _ := recorder.GetFollowUpMessage(context.Background(), "", "token", created.ID)
+			if message.Content != test.content || len(message.Embeds) != test.embeds {
+				t.Errorf("follow-up = %q with %d embeds, want %q with %d", message.Content, len(message.Embeds), test.content, test.embeds)
+			}
+		})
+	}
+
+	_, recorder := NewContext(&httpcord.Interaction{Type: httpcord.ApplicationCommandInteraction, Token: "token"})
+	if _, err := recorder.CreateFollowUpMessage(context.Background(), "", "token", nil); !errors.Is(err, httpcord.ErrNilPayload) {
+		t.Errorf("CreateFollowUpMessage(nil) = %v, want ErrNilPayload", err)
+	}
+}
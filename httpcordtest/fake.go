@@ -0,0 +1,389 @@
+package httpcordtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"httpcord"
+)
+
+// FakeRequest Request received by a FakeDiscord
+type FakeRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Payload is the JSON body, or the payload_json of multipart bodies
+	Payload json.RawMessage
+	Files   []*RecordedFile
+}
+
+// Decode Decode the JSON payload into v
+func (r *FakeRequest) Decode(v interface{}) error {
+	return httpcord.DecodeJSON(r.Payload, v)
+}
+
+// FakeRoute Scripted answer of a FakeDiscord route, routes answer 204 until a response is set
+type FakeRoute struct {
+	method   string
+	segments []string
+	// bucket routes match every request of the rate limit bucket
+	bucket     string
+	status     int
+	header     http.Header
+	body       []byte
+	times      int
+	calls      int
+	expected   bool
+	fake       *FakeDiscord
+	registered string
+}
+
+// RespondWith Answer 200 with v encoded as JSON
+func (r *FakeRoute) RespondWith(v interface{}) *FakeRoute {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.fake.t.Fatalf("httpcordtest: encoding the response of %s: %v", r.registered, err)
+	}
+
+	r.fake.mu.Lock()
+	defer r.fake.mu.Unlock()
+
+	r.status, r.body = http.StatusOK, body
+	return r
+}
+
+// RespondStatus Answer with the status and the raw body
+func (r *FakeRoute) RespondStatus(status int, body []byte) *FakeRoute {
+	r.fake.mu.Lock()
+	defer r.fake.mu.Unlock()
+
+	r.status, r.body = status, body
+	return r
+}
+
+// RespondError Answer with a Discord API error
+func (r *FakeRoute) RespondError(status, code int, message string) *FakeRoute {
+	body, _ := json.Marshal(map[string]interface{}{"code": code, "message": message})
+	return r.RespondStatus(status, body)
+}
+
+// Header Header sent with the response
+func (r *FakeRoute) Header(name, value string) *FakeRoute {
+	r.fake.mu.Lock()
+	defer r.fake.mu.Unlock()
+
+	r.header.Set(name, value)
+	return r
+}
+
+// Times Answer at most n requests, the next routes answer the following ones
+func (r *FakeRoute) Times(n int) *FakeRoute {
+	r.fake.mu.Lock()
+	defer r.fake.mu.Unlock()
+
+	r.times = n
+	return r
+}
+
+// Calls Requests answered by the route
+func (r *FakeRoute) Calls() int {
+	r.fake.mu.Lock()
+	defer r.fake.mu.Unlock()
+
+	return r.calls
+}
+
+func (r *FakeRoute) match(method string, segments []string) bool {
+	if r.times > 0 && r.calls >= r.times {
+		return false
+	}
+
+	if r.bucket != "" {
+		return httpcord.BucketKey(method, "/"+strings.Join(segments, "/")) == r.bucket
+	}
+
+	if r.method != method || len(r.segments) != len(segments) {
+		return false
+	}
+
+	for i, segment := range r.segments {
+		if segment != "*" && segment != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type FakeOption func(f *FakeDiscord)
+
+// Strict Fail the test on requests without a route and on expected routes never called
+func Strict() FakeOption {
+	return func(f *FakeDiscord) {
+		f.strict = true
+	}
+}
+
+// FakeDiscord Scripted Discord API server recording the requests, closed with the test.
+// Routes are matched in registration order, a "*" segment matches any value
+type FakeDiscord struct {
+	t        testing.TB
+	server   *httptest.Server
+	strict   bool
+	mu       sync.Mutex
+	routes   []*FakeRoute
+	requests []*FakeRequest
+}
+
+func NewFakeDiscord(t testing.TB, opts ...FakeOption) *FakeDiscord {
+	f := &FakeDiscord{t: t}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+
+	t.Cleanup(func() {
+		f.server.Close()
+
+		if f.strict {
+			f.AssertExpectations()
+		}
+	})
+
+	return f
+}
+
+// URL Base URL of the fake API, used as RestClient.BaseURL
+func (f *FakeDiscord) URL() string {
+	return f.server.URL
+}
+
+// Client RestClient sending its requests to the fake API
+func (f *FakeDiscord) Client() *httpcord.RestClient {
+	client := httpcord.NewRestClient(httpcord.StaticToken("fake-token"))
+	client.BaseURL = f.server.URL
+	return client
+}
+
+// Use Send the requests of the connection client to the fake API
+func (f *FakeDiscord) Use(conn *httpcord.Connection) {
+	conn.Client.BaseURL = f.server.URL
+}
+
+func (f *FakeDiscord) add(route *FakeRoute) *FakeRoute {
+	route.fake = f
+	route.header = make(http.Header)
+	route.status = http.StatusNoContent
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.routes = append(f.routes, route)
+	return route
+}
+
+// Expect Route of the method and path like "/channels/*/messages", expected to be called
+func (f *FakeDiscord) Expect(method, path string) *FakeRoute {
+	return f.add(&FakeRoute{
+		method:     method,
+		segments:   splitPath(path),
+		expected:   true,
+		registered: method + " " + path,
+	})
+}
+
+// Allow Route of the method and path that may not be called
+func (f *FakeDiscord) Allow(method, path string) *FakeRoute {
+	route := f.Expect(method, path)
+	route.expected = false
+	return route
+}
+
+// ExpectEditOriginal Edit of the original interaction response
+func (f *FakeDiscord) ExpectEditOriginal(applicationID httpcord.Snowflake, token string) *FakeRoute {
+	return f.Expect(http.MethodPatch, "/webhooks/"+applicationID.String()+"/"+token+"/messages/@original")
+}
+
+// ExpectFollowUp Follow-up message of the interaction
+func (f *FakeDiscord) ExpectFollowUp(applicationID httpcord.Snowflake, token string) *FakeRoute {
+	return f.Expect(http.MethodPost, "/webhooks/"+applicationID.String()+"/"+token)
+}
+
+// ExpectRateLimit Answer the next request of the bucket (See httpcord.BucketKey) with a 429 asking to retry after retryAfter
+func (f *FakeDiscord) ExpectRateLimit(bucket string, retryAfter time.Duration) *FakeRoute {
+	seconds := retryAfter.Seconds()
+	body, _ := json.Marshal(map[string]interface{}{
+		"message":     "You are being rate limited.",
+		"retry_after": seconds,
+		"global":      false,
+	})
+
+	route := f.add(&FakeRoute{bucket: bucket, expected: true, registered: "rate limit " + bucket})
+	route.status, route.body, route.times = http.StatusTooManyRequests, body, 1
+	route.header.Set("X-RateLimit-Remaining", "0")
+	route.header.Set("X-RateLimit-Reset-After", strconv.FormatFloat(seconds, 'f', -1, 64))
+
+	return route
+}
+
+// Requests Every request received so far
+func (f *FakeDiscord) Requests() []*FakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*FakeRequest(nil), f.requests...)
+}
+
+// RequestsTo Requests received by the method and path, "*" segments match any value
+func (f *FakeDiscord) RequestsTo(method, path string) []*FakeRequest {
+	route := &FakeRoute{method: method, segments: splitPath(path)}
+
+	var matched []*FakeRequest
+	for _, req := range f.Requests() {
+		if route.match(req.Method, splitPath(req.Path)) {
+			matched = append(matched, req)
+		}
+	}
+
+	return matched
+}
+
+// AssertExpectations Fail the test for every expected route never called
+func (f *FakeDiscord) AssertExpectations() {
+	f.t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, route := range f.routes {
+		if route.expected && route.calls == 0 {
+			f.t.Errorf("httpcordtest: expected %s was not called", route.registered)
+		}
+	}
+}
+
+func (f *FakeDiscord) serve(w http.ResponseWriter, r *http.Request) {
+	req, err := capture(r)
+	if err != nil {
+		f.t.Errorf("httpcordtest: reading %s %s: %v", r.Method, r.URL.Path, err)
+	} else if len(req.Files) > 0 {
+		if err := CheckAttachments(req.Payload, req.Files); err != nil {
+			f.t.Errorf("httpcordtest: %s %s: %v", r.Method, r.URL.Path, err)
+		}
+	}
+
+	segments := splitPath(req.Path)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+
+	var route *FakeRoute
+	for _, candidate := range f.routes {
+		if candidate.match(req.Method, segments) {
+			route = candidate
+			break
+		}
+	}
+
+	if route == nil {
+		f.mu.Unlock()
+
+		if f.strict {
+			f.t.Errorf("httpcordtest: unexpected request %s %s", req.Method, req.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"code":0,"message":"404: Not Found"}`)
+		return
+	}
+
+	route.calls++
+	status, body := route.status, route.body
+
+	for name, values := range route.header {
+		w.Header()[name] = values
+	}
+	f.mu.Unlock()
+
+	if len(body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// capture Read the request, multipart bodies are split in their payload_json and files
+func capture(r *http.Request) (*FakeRequest, error) {
+	body, err := io.ReadAll(r.Body)
+
+	req := &FakeRequest{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.Query(),
+		Header:  r.Header,
+		Body:    body,
+		Payload: body,
+	}
+
+	if err != nil {
+		return req, err
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return req, nil
+	}
+
+	req.Payload = nil
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return req, nil
+		}
+
+		if err != nil {
+			return req, err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return req, err
+		}
+
+		if part.FormName() == "payload_json" {
+			req.Payload = data
+			continue
+		}
+
+		req.Files = append(req.Files, &RecordedFile{
+			Field:       part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
@@ -0,0 +1,294 @@
+package httpcordtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"httpcord"
+)
+
+// recordingTB Test of a FakeDiscord whose failures are recorded instead of failing the test
+type recordingTB struct {
+	testing.TB
+	mu       sync.Mutex
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func TestFakeDiscordFollowUps(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(f *FakeDiscord)
+		send   func(client *httpcord.RestClient) (*httpcord.Message, error)
+		want   httpcord.Snowflake
+		err    int
+		check  func(t *testing.T, f *FakeDiscord)
+	}{
+		{
+			name: "edit original",
+			script: func(f *FakeDiscord) {
+				f.ExpectEditOriginal("1", "token").RespondWith(httpcord.Message{ID: "9", Content: "done"})
+			},
+			send: func(client *httpcord.RestClient) (*httpcord.Message, error) {
+				return client.EditOriginalInteractionResponse(context.Background(), "1", "token", &httpcord.WebhookEdit{Content: "done"})
+			},
+			want: "9",
+			check: func(t *testing.T, f *FakeDiscord) {
+				var edit httpcord.WebhookEdit
+				if err := f.RequestsTo(http.MethodPatch, "/webhooks/1/token/messages/@original")[0].Decode(&edit); err != nil || edit.Content != "done" {
+					t.Errorf("edit %+v: %v", edit, err)
+				}
+			},
+		},
+		{
+			name: "follow-up with files",
+			script: func(f *FakeDiscord) {
+				f.ExpectFollowUp("1", "token").RespondWith(httpcord.Message{ID: "10"})
+			},
+			send: func(client *httpcord.RestClient) (*httpcord.Message, error) {
+				return client.CreateFollowUpMessage(context.Background(), "1", "token", &httpcord.WebhookEdit{
+					Content: "report",
+					Files:   []*httpcord.DiscordFile{{Buffer: bytes.NewBufferString("PNG"), Filename: "chart.png"}},
+				})
+			},
+			want: "10",
+			check: func(t *testing.T, f *FakeDiscord) {
+				files := f.Requests()[0].Files
+				if len(files) != 1 || files[0].Field != "files[0]" || files[0].Filename != "chart.png" || string(files[0].Data) != "PNG" {
+					t.Errorf("files %+v", files)
+				}
+			},
+		},
+		{
+			name: "routes answer in registration order",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodPost, "/webhooks/1/*").Times(1).RespondWith(httpcord.Message{ID: "11"})
+				f.Expect(http.MethodPost, "/webhooks/1/*").RespondWith(httpcord.Message{ID: "12"})
+			},
+			send: func(client *httpcord.RestClient) (*httpcord.Message, error) {
+				if _, err := client.CreateFollowUpMessage(context.Background(), "1", "first", &httpcord.WebhookEdit{Content: "a"}); err != nil {
+					return nil, err
+				}
+
+				return client.CreateFollowUpMessage(context.Background(), "1", "second", &httpcord.WebhookEdit{Content: "b"})
+			},
+			want: "12",
+			check: func(t *testing.T, f *FakeDiscord) {
+				if got := len(f.RequestsTo(http.MethodPost, "/webhooks/1/second")); got != 1 {
+					t.Errorf("%d requests to the second token, want 1", got)
+				}
+			},
+		},
+		{
+			name: "rate limited follow-up is retried",
+			script: func(f *FakeDiscord) {
+				f.ExpectRateLimit(httpcord.BucketKey(http.MethodPost, "/webhooks/1/token"), 10*time.Millisecond)
+				f.ExpectFollowUp("1", "token").RespondWith(httpcord.Message{ID: "13"})
+			},
+			send: func(client *httpcord.RestClient) (*httpcord.Message, error) {
+				return client.CreateFollowUpMessage(context.Background(), "1", "token", &httpcord.WebhookEdit{Content: "later"})
+			},
+			want: "13",
+			check: func(t *testing.T, f *FakeDiscord) {
+				if got := len(f.Requests()); got != 2 {
+					t.Errorf("%d requests, want the rate limited one and its retry", got)
+				}
+			},
+		},
+		{
+			name: "api error",
+			script: func(f *FakeDiscord) {
+				f.ExpectFollowUp("1", "token").RespondError(http.StatusBadRequest, 50035, "Invalid Form Body")
+			},
+			send: func(client *httpcord.RestClient) (*httpcord.Message, error) {
+				return client.CreateFollowUpMessage(context.Background(), "1", "token", &httpcord.WebhookEdit{Content: "refused"})
+			},
+			err: 50035,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := NewFakeDiscord(t, Strict())
+			test.script(fake)
+
+			message, err := test.send(fake.Client())
+
+			var apiErr *httpcord.DiscordAPIError
+			switch {
+			case test.err != 0:
+				if !errors.As(err, &apiErr) || apiErr.Code != test.err {
+					t.Fatalf("error %v, want the code %d", err, test.err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case message.ID != test.want:
+				t.Errorf("message %s, want %s", message.ID, test.want)
+			}
+
+			if test.check != nil {
+				test.check(t, fake)
+			}
+		})
+	}
+}
+
+func TestFakeDiscordCommandSync(t *testing.T) {
+	ban := &httpcord.ApplicationCommand{Name: "ban", Description: "Ban a member"}
+	warn := &httpcord.ApplicationCommand{Name: "warn", Description: "Warn a member"}
+
+	registered := []*httpcord.ApplicationCommand{
+		{ID: "7", Name: "kick", Description: "Kick a member"},
+		{ID: "8", Name: "ban", Description: "Ban someone"},
+	}
+
+	tests := []struct {
+		name     string
+		script   func(f *FakeDiscord)
+		opts     []httpcord.SyncOption
+		strategy httpcord.SyncStrategy
+		created  int
+		err      error
+	}{
+		{
+			name: "unchanged",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodGet, "/applications/1/commands").RespondWith([]*httpcord.ApplicationCommand{
+					{ID: "8", Name: "ban", Description: "Ban a member"},
+					{ID: "9", Name: "warn", Description: "Warn a member"},
+				})
+			},
+			strategy: httpcord.SyncUnchanged,
+		},
+		{
+			name: "bulk overwrite",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodGet, "/applications/1/commands").RespondWith([]*httpcord.ApplicationCommand{})
+				f.Expect(http.MethodPut, "/applications/1/commands").RespondWith([]*httpcord.ApplicationCommand{})
+			},
+			strategy: httpcord.SyncBulkOverwrite,
+			created:  2,
+		},
+		{
+			name: "incremental",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodGet, "/applications/1/commands").RespondWith(registered)
+				f.Expect(http.MethodPost, "/applications/1/commands").RespondWith(warn)
+				f.Expect(http.MethodPatch, "/applications/1/commands/8").RespondWith(ban)
+				f.Expect(http.MethodDelete, "/applications/1/commands/7")
+			},
+			opts:     []httpcord.SyncOption{httpcord.SyncIncrementalOnly()},
+			strategy: httpcord.SyncIncremental,
+			created:  1,
+		},
+		{
+			name: "bulk overwrite too large",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodGet, "/applications/1/commands").RespondWith(registered)
+				f.Expect(http.MethodPut, "/applications/1/commands").RespondError(http.StatusRequestEntityTooLarge, 40005, "Request entity too large")
+				f.Expect(http.MethodPost, "/applications/1/commands").RespondWith(warn)
+				f.Expect(http.MethodPatch, "/applications/1/commands/8").RespondWith(ban)
+				f.Expect(http.MethodDelete, "/applications/1/commands/7")
+			},
+			strategy: httpcord.SyncIncremental,
+			created:  1,
+		},
+		{
+			name: "refused",
+			script: func(f *FakeDiscord) {
+				f.Expect(http.MethodGet, "/applications/1/commands").RespondWith(registered)
+				f.Expect(http.MethodPut, "/applications/1/commands").RespondStatus(http.StatusBadRequest,
+					[]byte(`{"code":50035,"message":"Invalid Form Body","errors":{"1":{"description":{"_errors":[{"code":"BASE_TYPE_BAD_LENGTH","message":"Must be between 1 and 100 in length."}]}}}}`))
+			},
+			err: &httpcord.CommandRegistrationError{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := NewFakeDiscord(t, Strict())
+			test.script(fake)
+
+			result, err := fake.Client().WithApplication("1").SyncCommands(context.Background(), "", []*httpcord.ApplicationCommand{ban, warn}, test.opts...)
+
+			if test.err != nil {
+				var registration *httpcord.CommandRegistrationError
+				if !errors.As(err, &registration) || len(registration.Commands) != 1 || registration.Commands[0] != "warn" {
+					t.Fatalf("error %v, want a registration error of the warn command", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Strategy != test.strategy || len(result.Created) != test.created {
+				t.Errorf("result %+v, want %s with %d created", result, test.strategy, test.created)
+			}
+		})
+	}
+}
+
+func TestFakeDiscordStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   func(f *FakeDiscord)
+		strict   bool
+		failures int
+	}{
+		{"unexpected request", func(f *FakeDiscord) {}, true, 1},
+		{"unexpected request without strict mode", func(f *FakeDiscord) {}, false, 0},
+		{"expected route never called", func(f *FakeDiscord) {
+			f.ExpectFollowUp("1", "token")
+			f.ExpectEditOriginal("1", "other")
+		}, true, 1},
+		{"allowed route never called", func(f *FakeDiscord) {
+			f.ExpectFollowUp("1", "token")
+			f.Allow(http.MethodPatch, "/webhooks/1/*/messages/@original")
+		}, true, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &recordingTB{TB: t}
+
+			var opts []FakeOption
+			if test.strict {
+				opts = append(opts, Strict())
+			}
+
+			fake := NewFakeDiscord(recorder, opts...)
+			test.script(fake)
+
+			fake.Client().CreateFollowUpMessage(context.Background(), "1", "token", &httpcord.WebhookEdit{Content: "hello"})
+
+			if test.strict {
+				fake.AssertExpectations()
+			}
+
+			if len(recorder.failures) != test.failures {
+				t.Errorf("failures %q, want %d", recorder.failures, test.failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package httpcordtest
+
+import (
+	"sort"
+
+	"httpcord"
+)
+
+// Synthetic interaction defaults, the interactions are sent by TestUser in a DM
+const (
+	TestInteractionID httpcord.Snowflake = "100000000000000001"
+	TestApplicationID httpcord.Snowflake = "100000000000000002"
+	TestChannelID     httpcord.Snowflake = "100000000000000003"
+	TestToken                            = "httpcordtest-token"
+)
+
+// TestUser User of the synthetic interactions, replaced by InGuild
+var TestUser = httpcord.User{ID: "100000000000000004", Username: "tester"}
+
+// CommandOption Option of the synthetic command interactions, entity options add their resolved data
+type CommandOption func(data *httpcord.ApplicationCommandInteractionData)
+
+// NewCommandInteraction Chat input command interaction with the options, for NewContext or
+// Signer.NewInteractionRequest:
+//
+//	interaction := httpcordtest.NewCommandInteraction("ban",
+//		httpcordtest.UserOption("user", &target),
+//		httpcordtest.IntOption("days", 7),
+//	)
+func NewCommandInteraction(name string, options ...CommandOption) *httpcord.Interaction {
+	data := httpcord.ApplicationCommandInteractionData{ID: "100000000000000005", Name: name, Type: httpcord.ChatInputApplicationCommandType}
+	for _, option := range options {
+		option(&data)
+	}
+
+	return newInteraction(httpcord.ApplicationCommandInteraction, data)
+}
+
+// NewAutocompleteInteraction Autocomplete interaction of the command with the focused option, see Focused
+func NewAutocompleteInteraction(name string, options ...CommandOption) *httpcord.Interaction {
+	interaction := NewCommandInteraction(name, options...)
+	interaction.Type = httpcord.AutoCompleteInteraction
+	return interaction
+}
+
+// NewComponentInteraction Button interaction of the custom_id, or select interaction with the values
+func NewComponentInteraction(customID string, values ...string) *httpcord.Interaction {
+	data := httpcord.ComponentInteractionData{CustomID: customID, ComponentType: httpcord.ButtonComponentType}
+	if len(values) > 0 {
+		data.ComponentType, data.Values = httpcord.SelectMenuComponentType, values
+	}
+
+	interaction := newInteraction(httpcord.MessageComponentInteraction, data)
+	interaction.Message = &httpcord.Message{ID: "100000000000000006", ChannelID: TestChannelID}
+	return interaction
+}
+
+// NewModalInteraction Modal submit of the custom_id with the text inputs keyed by custom_id, one per row
+func NewModalInteraction(customID string, values map[string]string) *httpcord.Interaction {
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	data := httpcord.ModalSubmitInteractionData{CustomID: customID}
+	for _, id := range ids {
+		// Submits are decoded like Discord sends them, see ModalSubmitInteractionData.Values
+		input := map[string]interface{}{"type": float64(httpcord.InputTextComponentType), "custom_id": id, "value": values[id]}
+		data.Components = append(data.Components, &httpcord.ActionRowComponent{Type: httpcord.ActionRowComponentType, Components: []httpcord.AnyComponent{input}})
+	}
+
+	return newInteraction(httpcord.ModalSubmitInteraction, data)
+}
+
+func newInteraction(kind httpcord.InteractionType, data interface{}) *httpcord.Interaction {
+	user := TestUser
+
+	return &httpcord.Interaction{
+		ID:            TestInteractionID,
+		ApplicationID: TestApplicationID,
+		Type:          kind,
+		Data:          data,
+		ChannelID:     TestChannelID,
+		User:          &user,
+		Token:         TestToken,
+		Version:       1,
+		Locale:        string(httpcord.EnglishUSLocale),
+	}
+}
+
+// InGuild Move the interaction to the guild, sent by the member instead of TestUser
+func InGuild(interaction *httpcord.Interaction, guildID httpcord.Snowflake, member *httpcord.Member) *httpcord.Interaction {
+	interaction.GuildID, interaction.Member, interaction.User = guildID, member, nil
+	return interaction
+}
+
+func addOption(name string, kind httpcord.ApplicationCommandOptionType, value interface{}) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		data.Options = append(data.Options, httpcord.ApplicationCommandOption{Name: name, Type: kind, Value: value})
+	}
+}
+
+func StringOption(name, value string) CommandOption {
+	return addOption(name, httpcord.StringApplicationCommandOptionType, value)
+}
+
+// IntOption Integer option, decoded as a float64 like the JSON numbers Discord sends
+func IntOption(name string, value int) CommandOption {
+	return addOption(name, httpcord.IntApplicationCommandOptionType, float64(value))
+}
+
+func NumberOption(name string, value float64) CommandOption {
+	return addOption(name, httpcord.NumberApplicationCommandOptionType, value)
+}
+
+func BoolOption(name string, value bool) CommandOption {
+	return addOption(name, httpcord.BoolApplicationCommandOptionType, value)
+}
+
+// UserOption User option with the user resolved
+func UserOption(name string, user *httpcord.User) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		addOption(name, httpcord.UserApplicationCommandOptionType, user.ID.String())(data)
+
+		if data.Resolved.Users == nil {
+			data.Resolved.Users = make(map[httpcord.Snowflake]*httpcord.User)
+		}
+
+		data.Resolved.Users[user.ID] = user
+	}
+}
+
+// MemberOption User option with the member and its User resolved
+func MemberOption(name string, member *httpcord.Member) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		UserOption(name, member.User)(data)
+
+		if data.Resolved.Members == nil {
+			data.Resolved.Members = make(map[httpcord.Snowflake]*httpcord.Member)
+		}
+
+		data.Resolved.Members[member.User.ID] = member
+	}
+}
+
+// RoleOption Role option with the role resolved
+func RoleOption(name string, role *httpcord.Role) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		addOption(name, httpcord.RoleApplicationCommandOptionType, role.ID.String())(data)
+
+		if data.Resolved.Roles == nil {
+			data.Resolved.Roles = make(map[httpcord.Snowflake]*httpcord.Role)
+		}
+
+		data.Resolved.Roles[role.ID] = role
+	}
+}
+
+// ChannelOption Channel option with the channel resolved
+func ChannelOption(name string, channel *httpcord.Channel) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		addOption(name, httpcord.ChannelApplicationCommandOptionType, channel.ID.String())(data)
+
+		if data.Resolved.Channels == nil {
+			data.Resolved.Channels = make(map[httpcord.Snowflake]*httpcord.Channel)
+		}
+
+		data.Resolved.Channels[channel.ID] = channel
+	}
+}
+
+// Focused Mark the last option focused, for NewAutocompleteInteraction
+func Focused() CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		options := data.Options
+		for len(options) > 0 && len(options[len(options)-1].Options) > 0 {
+			options = options[len(options)-1].Options
+		}
+
+		if len(options) > 0 {
+			options[len(options)-1].Focused = true
+		}
+	}
+}
+
+// Subcommand Subcommand invoked with the options, nest it in SubcommandGroup for the groups
+func Subcommand(name string, options ...CommandOption) CommandOption {
+	return nested(name, httpcord.SubCommandApplicationCommandOptionType, options)
+}
+
+func SubcommandGroup(name string, subcommand CommandOption) CommandOption {
+	return nested(name, httpcord.SubCommandGroupApplicationCommandOptionType, []CommandOption{subcommand})
+}
+
+// nested Option of the kind holding the options, their resolved data is the one of the interaction
+func nested(name string, kind httpcord.ApplicationCommandOptionType, options []CommandOption) CommandOption {
+	return func(data *httpcord.ApplicationCommandInteractionData) {
+		inner := httpcord.ApplicationCommandInteractionData{Resolved: data.Resolved}
+		for _, option := range options {
+			option(&inner)
+		}
+
+		data.Resolved = inner.Resolved
+		data.Options = append(data.Options, httpcord.ApplicationCommandOption{Name: name, Type: kind, Options: inner.Options})
+	}
+}
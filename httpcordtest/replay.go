@@ -0,0 +1,279 @@
+// Package httpcordtest Helpers to exercise httpcord connections without Discord
+package httpcordtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"httpcord"
+)
+
+// Dump Request written to ConnectionOptions.DebugDumpDir
+type Dump struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	// Body is the JSON body as dumped, tokens are redacted
+	Body json.RawMessage `json:"body"`
+}
+
+// LoadDump Read a "-request.json" dump file
+func LoadDump(path string) (*Dump, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump Dump
+	if err := json.Unmarshal(b, &dump); err != nil {
+		return nil, err
+	}
+
+	if len(dump.Body) == 0 {
+		return nil, errors.New("httpcordtest: dump without body " + path)
+	}
+
+	// Bodies that were not JSON are dumped as a string
+	var raw string
+	if json.Unmarshal(dump.Body, &raw) == nil {
+		dump.Body = json.RawMessage(raw)
+	}
+
+	return &dump, nil
+}
+
+// Request Rebuild the request signed with key, the dumped signature headers are replaced
+func (d *Dump) Request(url string, key ed25519.PrivateKey, timestamp time.Time) (*http.Request, error) {
+	method := d.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(d.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range d.Headers {
+		switch http.CanonicalHeaderKey(name) {
+		case "X-Signature-Ed25519", "X-Signature-Timestamp", "Content-Length", "Authorization":
+			continue
+		}
+
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	req.Header.Set("X-Signature-Timestamp", ts)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, append([]byte(ts), d.Body...))))
+
+	return req, nil
+}
+
+// RecordedFile File of a multipart response
+type RecordedFile struct {
+	// Field is the form field like "files[0]"
+	Field       string
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// RecordedResponse Response of a replayed request
+type RecordedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Response is the decoded JSON body, or the payload_json of multipart bodies (nil when the body is empty)
+	Response *httpcord.InteractionResponse
+	Files    []*RecordedFile
+}
+
+type ReplayOption func(o *replayOptions)
+
+type replayOptions struct {
+	timestamp time.Time
+	headers   http.Header
+}
+
+// ReplayTimestamp Timestamp the request is signed with (Defaults to now)
+func ReplayTimestamp(t time.Time) ReplayOption {
+	return func(o *replayOptions) {
+		o.timestamp = t
+	}
+}
+
+// ReplayHeader Set a header of the replayed request
+func ReplayHeader(name, value string) ReplayOption {
+	return func(o *replayOptions) {
+		o.headers.Set(name, value)
+	}
+}
+
+func newReplayOptions(opts []ReplayOption) replayOptions {
+	o := replayOptions{timestamp: time.Now(), headers: make(http.Header)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// ReplayRequest Run a dumped request through the handlers of conn. The request is signed with a generated key
+// and verified by a copy of conn using it, so the dump does not need the Discord signature
+func ReplayRequest(conn *httpcord.Connection, dumpPath string, opts ...ReplayOption) (*RecordedResponse, error) {
+	o := newReplayOptions(opts)
+
+	dump, err := LoadDump(dumpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := conn.WithPublicKey(hex.EncodeToString(pub))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := dump.Request("/", priv, o.timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range o.headers {
+		req.Header[name] = values
+	}
+
+	status, contentType, body := replay.HandleRawRequest(flattenHeaders(req.Header), readBody(req))
+
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	return RecordResponse(status, header, body)
+}
+
+// Replay Send a dumped request to a running endpoint, signed with key.
+// The endpoint must verify the requests with the public key of key
+func Replay(client *http.Client, url, dumpPath string, key ed25519.PrivateKey, opts ...ReplayOption) (*RecordedResponse, error) {
+	o := newReplayOptions(opts)
+
+	dump, err := LoadDump(dumpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := dump.Request(url, key, o.timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range o.headers {
+		req.Header[name] = values
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return RecordResponse(res.StatusCode, res.Header, body)
+}
+
+// RecordResponse Decode an interaction response, JSON or multipart with payload_json and files
+func RecordResponse(status int, header http.Header, body []byte) (*RecordedResponse, error) {
+	recorded := &RecordedResponse{Status: status, Header: header, Body: body}
+
+	if len(body) == 0 {
+		return recorded, nil
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		if mediaType == "application/json" {
+			recorded.Response = &httpcord.InteractionResponse{}
+			if err := json.Unmarshal(body, recorded.Response); err != nil {
+				return recorded, err
+			}
+		}
+
+		return recorded, nil
+	}
+
+	r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return recorded, nil
+		}
+
+		if err != nil {
+			return recorded, err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return recorded, err
+		}
+
+		if part.FormName() == "payload_json" {
+			recorded.Response = &httpcord.InteractionResponse{}
+			if err := json.Unmarshal(data, recorded.Response); err != nil {
+				return recorded, err
+			}
+
+			continue
+		}
+
+		recorded.Files = append(recorded.Files, &RecordedFile{
+			Field:       part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+}
+
+func flattenHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name := range header {
+		flat[name] = header.Get(name)
+	}
+
+	return flat
+}
+
+func readBody(req *http.Request) []byte {
+	b, _ := io.ReadAll(req.Body)
+	return b
+}
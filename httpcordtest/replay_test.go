@@ -0,0 +1,79 @@
+package httpcordtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"httpcord"
+)
+
+func TestReplayRequest(t *testing.T) {
+	signer := NewSigner(t)
+	dir := t.TempDir()
+
+	conn, err := httpcord.NewConnection(httpcord.ConnectionOptions{PublicKey: signer.PublicKey(), Logger: httpcord.NopLogger, DebugDumpDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// dispatch What the handlers saw, the token excepted since the dumps redact it
+	type dispatch struct {
+		route   string
+		id      httpcord.Snowflake
+		guild   httpcord.Snowflake
+		user    httpcord.Snowflake
+		options interface{}
+	}
+
+	var dispatched []dispatch
+	record := func(route string, ctx httpcord.ConnectionContext) {
+		var options interface{}
+		if data, ok := ctx.Interaction.Data.(httpcord.ApplicationCommandInteractionData); ok {
+			options = data.Options
+		}
+
+		dispatched = append(dispatched, dispatch{route, ctx.Interaction.ID, ctx.Interaction.GuildID, ctx.Interaction.Member.User.ID, options})
+	}
+
+	conn.Command("ban", func(ctx httpcord.ConnectionContext) {
+		record("ban", ctx)
+
+		days, _ := ctx.IntOption("days")
+		ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "banned for " + strconv.Itoa(days) + " days"})
+	})
+
+	conn.Component("confirm", func(ctx httpcord.ConnectionContext) {
+		record("confirm", ctx)
+		ctx.UpdateMessage(&httpcord.InteractionCallbackData{Content: "confirmed"})
+	})
+
+	member := &httpcord.Member{User: &httpcord.User{ID: "300", Username: "mod"}, Permissions: 8}
+	interactions := []*httpcord.Interaction{
+		InGuild(NewCommandInteraction("ban", UserOption("user", &httpcord.User{ID: "200", Username: "target"}), IntOption("days", 7)), "400", member),
+		InGuild(NewComponentInteraction("confirm"), "400", member),
+	}
+
+	for i, interaction := range interactions {
+		original := Serve(t, conn, signer.NewInteractionRequest(t, interaction))
+		if original.Response == nil || original.Response.Data == nil {
+			t.Fatalf("original answered %d %s, want a reply", original.Status, original.Body)
+		}
+
+		// Replays are not dumped, the dumps are the original requests
+		replayed, err := ReplayRequest(conn, filepath.Join(dir, fmt.Sprintf("%04d-request.json", i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if replayed.Status != original.Status || !reflect.DeepEqual(replayed.Response, original.Response) {
+			t.Errorf("replay answered %d %s, want %d %s", replayed.Status, replayed.Body, original.Status, original.Body)
+		}
+
+		if len(dispatched) != 2*(i+1) || !reflect.DeepEqual(dispatched[2*i+1], dispatched[2*i]) {
+			t.Errorf("replay dispatched %+v, want %+v", dispatched[len(dispatched)-1], dispatched[2*i])
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package httpcordtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"httpcord"
+)
+
+// Signer Key pair signing interaction requests like Discord does, the connection under test verifies them with
+// PublicKey:
+//
+//	signer := httpcordtest.NewSigner(t)
+//	conn, _ := httpcord.NewConnection(httpcord.ConnectionOptions{PublicKey: signer.PublicKey()})
+//	res := httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, interaction))
+type Signer struct {
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+	// Now Time of the signatures (Defaults to time.Now), older times exercise ConnectionOptions.MaxTimestampSkew
+	Now func() time.Time
+}
+
+// NewSigner Signer with a generated key pair, fails the test when the key can not be generated
+func NewSigner(t testing.TB) *Signer {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("httpcordtest: could not generate the signing key: %v", err)
+	}
+
+	return &Signer{public: public, private: private, Now: time.Now}
+}
+
+// PublicKey Hex encoded public key, for ConnectionOptions.PublicKey
+func (s *Signer) PublicKey() string {
+	return hex.EncodeToString(s.public)
+}
+
+// PrivateKey Private key of the signatures, for Replay
+func (s *Signer) PrivateKey() ed25519.PrivateKey {
+	return s.private
+}
+
+// SignRequest Signature and timestamp headers of the body, X-Signature-Ed25519 and X-Signature-Timestamp
+func (s *Signer) SignRequest(body []byte) (signature, timestamp string) {
+	timestamp = strconv.FormatInt(s.Now().Unix(), 10)
+	signature = hex.EncodeToString(ed25519.Sign(s.private, append([]byte(timestamp), body...)))
+
+	return signature, timestamp
+}
+
+// NewRequest Signed POST request of the raw body
+func (s *Signer) NewRequest(body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	signature, timestamp := s.SignRequest(body)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Ed25519", signature)
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+
+	return req
+}
+
+// NewInteractionRequest Signed POST request of the interaction encoded like Discord sends it, fails the test when it
+// can not be encoded. The Data of the interaction is encoded as it is, like ApplicationCommandInteractionData
+func (s *Signer) NewInteractionRequest(t testing.TB, interaction *httpcord.Interaction) *http.Request {
+	t.Helper()
+
+	body, err := json.Marshal(wireInteraction(interaction))
+	if err != nil {
+		t.Fatalf("httpcordtest: could not encode the interaction: %v", err)
+	}
+
+	return s.NewRequest(body)
+}
+
+// Serve Run the request through the connection and record its response, fails the test when the response body
+// can not be decoded. Response is nil when the connection refused the request without a body
+func Serve(t testing.TB, conn *httpcord.Connection, req *http.Request) *RecordedResponse {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	conn.ServeHTTP(w, req)
+
+	recorded, err := RecordResponse(w.Code, w.Header(), w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("httpcordtest: could not decode the response: %v", err)
+	}
+
+	return recorded
+}
+
+// wireInteraction Interaction in the format Discord sends, the parsed users and members differ from it
+func wireInteraction(i *httpcord.Interaction) *httpcord.APIInteraction {
+	wire := &httpcord.APIInteraction{
+		ID:                i.ID.String(),
+		ApplicationID:     i.ApplicationID.String(),
+		Type:              i.Type,
+		GuildID:           i.GuildID.String(),
+		ChannelID:         i.ChannelID.String(),
+		Member:            wireMember(i.Member),
+		User:              wireUser(i.User),
+		Token:             i.Token,
+		Version:           i.Version,
+		Message:           i.Message,
+		Locale:            i.Locale,
+		GuildLocale:       i.GuildLocale,
+		Entitlements:      i.Entitlements,
+		EntitlementSKUIDs: i.EntitlementSKUIDs,
+		Context:           i.Context,
+	}
+
+	if wire.Version == 0 {
+		wire.Version = 1
+	}
+
+	if i.Data != nil {
+		wire.Data, _ = json.Marshal(i.Data)
+	}
+
+	if i.AppPermissions != nil {
+		wire.AppPermissions = strconv.FormatUint(uint64(*i.AppPermissions), 10)
+	}
+
+	return wire
+}
+
+func wireUser(user *httpcord.User) *httpcord.APIUser {
+	if user == nil {
+		return nil
+	}
+
+	wire := &httpcord.APIUser{
+		ID:            user.ID.String(),
+		Username:      user.Username,
+		Discriminator: user.Discriminator,
+		Avatar:        user.Avatar,
+		Bot:           user.Bot,
+		System:        user.System,
+		MfaEnabled:    user.MfaEnabled,
+		Banner:        user.Banner,
+		Locale:        user.Locale,
+		Verified:      user.Verified,
+		Email:         user.Email,
+		Flags:         user.Flags,
+		PremiumType:   user.PremiumType,
+		PublicFlags:   user.PublicFlags,
+	}
+
+	if user.AccentColor != 0 {
+		wire.AccentColor = strconv.Itoa(user.AccentColor)
+	}
+
+	return wire
+}
+
+func wireMember(member *httpcord.Member) *httpcord.APIMember {
+	if member == nil {
+		return nil
+	}
+
+	roles := make([]string, 0, len(member.Roles))
+	for _, role := range member.Roles {
+		if role != nil {
+			roles = append(roles, role.String())
+		}
+	}
+
+	joinedAt := member.JoinedAt
+	if joinedAt.IsZero() {
+		joinedAt = httpcord.Time{Time: time.Unix(0, 0)}
+	}
+
+	var disabledUntil httpcord.Time
+	if member.CommunicationDisabledUntil != nil {
+		disabledUntil = *member.CommunicationDisabledUntil
+	}
+
+	return &httpcord.APIMember{
+		User:                       wireUser(member.User),
+		Nick:                       member.Nick,
+		Avatar:                     member.Avatar,
+		Roles:                      roles,
+		JoinedAt:                   joinedAt,
+		PremiumSince:               member.PremiumSince,
+		Deaf:                       member.Deaf,
+		Mute:                       member.Mute,
+		Pending:                    member.Pending,
+		Permissions:                strconv.FormatUint(uint64(member.Permissions), 10),
+		CommunicationDisabledUntil: disabledUntil,
+	}
+}
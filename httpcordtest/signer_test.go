@@ -0,0 +1,151 @@
+package httpcordtest
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"httpcord"
+)
+
+func TestSignRequest(t *testing.T) {
+	signer := NewSigner(t)
+	signer.Now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	body := []byte(`{"type":1}`)
+	signature, timestamp := signer.SignRequest(body)
+
+	if timestamp != "1700000000" {
+		t.Errorf("timestamp %q, want the time of Now", timestamp)
+	}
+
+	public, _ := hex.DecodeString(signer.PublicKey())
+	decoded, _ := hex.DecodeString(signature)
+
+	if !ed25519.Verify(public, append([]byte(timestamp), body...), decoded) {
+		t.Error("signature does not verify the timestamp and body with PublicKey")
+	}
+
+	if other := NewSigner(t); other.PublicKey() == signer.PublicKey() {
+		t.Error("signers share their key")
+	}
+}
+
+func TestNewInteractionRequest(t *testing.T) {
+	signer := NewSigner(t)
+	target := httpcord.User{ID: "200", Username: "target"}
+	member := &httpcord.Member{User: &httpcord.User{ID: "300", Username: "mod"}, Permissions: 8}
+
+	interaction := InGuild(NewCommandInteraction("ban", UserOption("user", &target), IntOption("days", 7)), "400", member)
+	req := signer.NewInteractionRequest(t, interaction)
+
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("%s request of %q, want a JSON POST", req.Method, req.Header.Get("Content-Type"))
+	}
+
+	body, _ := io.ReadAll(req.Body)
+
+	public, _ := hex.DecodeString(signer.PublicKey())
+	signature, _ := hex.DecodeString(req.Header.Get("X-Signature-Ed25519"))
+
+	if !ed25519.Verify(public, append([]byte(req.Header.Get("X-Signature-Timestamp")), body...), signature) {
+		t.Error("the headers do not sign the body")
+	}
+
+	// Discord sends the IDs and permissions as strings
+	var wire map[string]interface{}
+	if err := json.Unmarshal(body, &wire); err != nil {
+		t.Fatal(err)
+	}
+
+	if wire["guild_id"] != "400" || wire["application_id"] != string(TestApplicationID) || wire["version"] != float64(1) {
+		t.Errorf("interaction %s, want the guild, the application and the version", body)
+	}
+
+	if wire["member"].(map[string]interface{})["permissions"] != "8" || wire["user"] != nil {
+		t.Errorf("member %v and user %v, want the member only with string permissions", wire["member"], wire["user"])
+	}
+}
+
+func TestServe(t *testing.T) {
+	signer := NewSigner(t)
+
+	conn, err := httpcord.NewConnection(httpcord.ConnectionOptions{PublicKey: signer.PublicKey(), Logger: httpcord.NopLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn.Command("ban", func(ctx httpcord.ConnectionContext) {
+		user, _ := ctx.UserOption("user")
+		days, _ := ctx.IntOption("days")
+		ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "banned " + user.Username + " for " + strconv.Itoa(days) + " days"})
+	})
+
+	conn.Component("confirm", func(ctx httpcord.ConnectionContext) {
+		ctx.UpdateMessage(&httpcord.InteractionCallbackData{Content: "confirmed " + ctx.CustomID()})
+	})
+
+	conn.Modal("report", func(ctx httpcord.ConnectionContext) {
+		reason, _ := ctx.ModalValue("reason")
+		ctx.ReplyEphemeral(&httpcord.InteractionCallbackData{Content: "reported: " + reason})
+	})
+
+	target := httpcord.User{ID: "200", Username: "target"}
+	stale := &Signer{public: signer.public, private: signer.private, Now: func() time.Time { return time.Now().Add(-time.Hour) }}
+
+	tests := []struct {
+		name     string
+		req      func() *http.Request
+		status   int
+		response httpcord.InteractionCallbackType
+		content  string
+	}{
+		{"ping", func() *http.Request {
+			return signer.NewRequest([]byte(`{"id":"1","application_id":"1","type":1,"token":"t","version":1}`))
+		},
+			http.StatusOK, httpcord.PongResponse, ""},
+		{"command", func() *http.Request {
+			return signer.NewInteractionRequest(t, NewCommandInteraction("ban", UserOption("user", &target), IntOption("days", 7)))
+		}, http.StatusOK, httpcord.ChannelMessageWithSourceResponse, "banned target for 7 days"},
+		{"component", func() *http.Request { return signer.NewInteractionRequest(t, NewComponentInteraction("confirm")) },
+			http.StatusOK, httpcord.UpdateMessageResponse, "confirmed confirm"},
+		{"modal", func() *http.Request {
+			return signer.NewInteractionRequest(t, NewModalInteraction("report", map[string]string{"reason": "spam"}))
+		}, http.StatusOK, httpcord.ChannelMessageWithSourceResponse, "reported: spam"},
+		{"other key", func() *http.Request { return NewSigner(t).NewInteractionRequest(t, NewCommandInteraction("ban")) },
+			http.StatusUnauthorized, 0, ""},
+		{"stale timestamp", func() *http.Request { return stale.NewInteractionRequest(t, NewCommandInteraction("ban")) },
+			http.StatusUnauthorized, 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := Serve(t, conn, test.req())
+
+			if res.Status != test.status {
+				t.Fatalf("status %d, want %d: %s", res.Status, test.status, res.Body)
+			}
+
+			if test.response == 0 {
+				if res.Response != nil && res.Response.Type != 0 {
+					t.Errorf("response %+v for a refused request", res.Response)
+				}
+
+				return
+			}
+
+			if res.Response == nil || res.Response.Type != test.response {
+				t.Fatalf("response %s, want the type %d", res.Body, test.response)
+			}
+
+			if test.content != "" && (res.Response.Data == nil || res.Response.Data.Content != test.content) {
+				t.Errorf("response %s, want %q", res.Body, test.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"httpcord/endpoints"
+)
+
+// InteractionCallbackResponse Resource created by an interaction callback sent with with_response
+type InteractionCallbackResponse struct {
+	Interaction InteractionCallback          `json:"interaction"`
+	Resource    *InteractionCallbackResource `json:"resource,omitempty"`
+}
+
+// InteractionCallback Interaction answered by the callback
+type InteractionCallback struct {
+	ID                       Snowflake       `json:"id"`
+	Type                     InteractionType `json:"type"`
+	ActivityInstanceID       string          `json:"activity_instance_id,omitempty"`
+	ResponseMessageID        Snowflake       `json:"response_message_id,omitempty"`
+	ResponseMessageLoading   bool            `json:"response_message_loading,omitempty"`
+	ResponseMessageEphemeral bool            `json:"response_message_ephemeral,omitempty"`
+}
+
+// InteractionCallbackResource Message created or updated by the callback, nil for the callbacks without one
+type InteractionCallbackResource struct {
+	Type    InteractionCallbackType `json:"type"`
+	Message *Message                `json:"message,omitempty"`
+}
+
+// InteractionCallbacks Optional InteractionWebhooks extension sending the initial responses through the REST API,
+// used by ReplyInteractionMessage. Implemented by RestClient
+type InteractionCallbacks interface {
+	CreateInteractionResponse(ctx context.Context, interactionID Snowflake, token string, response *InteractionResponse, withResponse bool) (*InteractionCallbackResponse, error)
+}
+
+var _ InteractionCallbacks = (*RestClient)(nil)
+
+// CreateInteractionResponse Send the initial response of the interaction, with withResponse the created resource is
+// returned, nil otherwise
+func (c *RestClient) CreateInteractionResponse(ctx context.Context, interactionID Snowflake, token string, response *InteractionResponse, withResponse bool) (*InteractionCallbackResponse, error) {
+	opts := []CallOption{withoutAuth()}
+
+	if response.Data != nil && len(response.Data.Files) > 0 {
+		data := *response.Data
+		data.Attachments = listAttachments(data.Attachments, data.Files)
+
+		copied := *response
+		copied.Data = &data
+		response = &copied
+
+		opts = append(opts, WithFiles(data.Files...))
+	}
+
+	if !withResponse {
+		return nil, c.Do(ctx, http.MethodPost, endpoints.InteractionCallback(interactionID.String(), token), response, nil, opts...)
+	}
+
+	var created InteractionCallbackResponse
+	opts = append(opts, WithQuery(url.Values{"with_response": {"true"}}))
+
+	if err := c.Do(ctx, http.MethodPost, endpoints.InteractionCallback(interactionID.String(), token), response, &created, opts...); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ReplyInteractionMessage Reply like ReplyInteraction and return the created message, so its components can be
+// edited without fetching it. The reply is sent through the REST callback endpoint and the request is answered with
+// a 202 once the handlers return. Once deferred with EditAfterDefer the original response is edited
+func (ctx *ConnectionContext) ReplyInteractionMessage(data *InteractionCallbackData) (*Message, error) {
+	return ctx.respondMessage(&InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: data})
+}
+
+// UpdateMessageWithResponse Update the message of the component like UpdateMessage and return it updated
+func (ctx *ConnectionContext) UpdateMessageWithResponse(data *InteractionCallbackData) (*Message, error) {
+	return ctx.respondMessage(&InteractionResponse{Type: UpdateMessageResponse, Data: data})
+}
+
+// respondMessage Send the response with with_response, webhooks without InteractionCallbacks get it through SendRes
+// and the message is fetched once delivered
+func (ctx *ConnectionContext) respondMessage(response *InteractionResponse) (*Message, error) {
+	callbacks, ok := ctx.webhooks.(InteractionCallbacks)
+	if !ok {
+		if err := ctx.SendRes(response); err != nil {
+			return nil, err
+		}
+
+		return ctx.originalResponse(ctx.Context())
+	}
+
+	response = ctx.options.responseMentions(response)
+
+	claimed, edit, gone := ctx.state.claim(response, ctx.options.EditAfterDefer)
+	switch {
+	case edit:
+		if response.Data == nil {
+			return nil, nil
+		}
+
+		return ctx.editOriginal(context.Background(), response.Data.WebhookEdit())
+	case !claimed:
+		return nil, ErrAlreadyResponded
+	case gone:
+		return nil, ErrClientGone
+	}
+
+	created, err := callbacks.CreateInteractionResponse(ctx.Context(), ctx.Interaction.ID, ctx.Interaction.Token, response, true)
+
+	ctx.state.mu.Lock()
+	if err != nil {
+		// The error reply can still be the initial response
+		ctx.state.responded, ctx.state.primaryResponse = false, nil
+	} else {
+		ctx.state.restCallback = true
+	}
+	ctx.state.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if created.Resource == nil {
+		return nil, nil
+	}
+
+	return created.Resource.Message, nil
+}
+
+// answeredByCallback Whether the initial response was sent through the REST callback endpoint
+func (s *interactionState) answeredByCallback() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.restCallback
+}
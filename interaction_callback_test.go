@@ -0,0 +1,159 @@
+package httpcord_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+// callbackResponse Body of Discord for a reply sent with with_response
+const callbackResponse = `{"interaction":{"id":"1100000000000000005","type":2,"response_message_id":"1100000000000000009",` +
+	`"response_message_loading":false,"response_message_ephemeral":true},` +
+	`"resource":{"type":4,"message":{"id":"1100000000000000009","channel_id":"3","content":"banned","flags":64,` +
+	`"components":[{"type":1,"components":[{"type":2,"style":1,"custom_id":"undo","label":"Undo"}]}]}}}`
+
+func TestCreateInteractionResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		withResponse bool
+		body         string
+		// query with_response query parameter of the request
+		query string
+		// message ID of the message of the decoded resource, empty when there is none
+		message httpcord.Snowflake
+	}{
+		{"with the response", true, callbackResponse, "true", "1100000000000000009"},
+		{"callback without a resource", true, `{"interaction":{"id":"1100000000000000005","type":3}}`, "true", ""},
+		{"without the response", false, "", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			route := fake.Expect(http.MethodPost, "/interactions/1100000000000000005/token/callback")
+			if test.body != "" {
+				route.RespondStatus(http.StatusOK, []byte(test.body))
+			} else {
+				route.RespondStatus(http.StatusNoContent, nil)
+			}
+
+			created, err := fake.Client().CreateInteractionResponse(context.Background(), "1100000000000000005", "token",
+				&httpcord.InteractionResponse{Type: httpcord.ChannelMessageWithSourceResponse, Data: &httpcord.InteractionCallbackData{Content: "banned"}},
+				test.withResponse)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requests := fake.Requests()
+			if len(requests) != 1 || requests[0].Query.Get("with_response") != test.query {
+				t.Fatalf("requests %+v, want one with with_response=%q", requests, test.query)
+			}
+
+			if !test.withResponse {
+				if created != nil {
+					t.Errorf("CreateInteractionResponse() = %+v, want nil without the response", created)
+				}
+
+				return
+			}
+
+			if created == nil || created.Interaction.ID != "1100000000000000005" {
+				t.Fatalf("CreateInteractionResponse() = %+v, want the callback of the interaction", created)
+			}
+
+			if test.message == "" {
+				if created.Resource != nil {
+					t.Errorf("Resource = %+v, want nil", created.Resource)
+				}
+
+				return
+			}
+
+			if created.Interaction.Type != httpcord.ApplicationCommandInteraction || created.Interaction.ResponseMessageID != test.message ||
+				!created.Interaction.ResponseMessageEphemeral || created.Interaction.ResponseMessageLoading {
+				t.Errorf("Interaction = %+v, want the ephemeral response message %s", created.Interaction, test.message)
+			}
+
+			resource := created.Resource
+			if resource == nil || resource.Type != httpcord.ChannelMessageWithSourceResponse || resource.Message == nil {
+				t.Fatalf("Resource = %+v, want the created message", resource)
+			}
+
+			if message := resource.Message; message.ID != test.message || message.ChannelID != "3" || message.Content != "banned" ||
+				message.Flags != httpcord.EphemeralMessageFlag || len(message.Components) != 1 {
+				t.Errorf("Message = %+v, want the created message with its components", message)
+			}
+		})
+	}
+}
+
+func TestReplyInteractionMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		// status Status of the callback, the reply fails unless 200
+		status int
+		body   string
+		// message ID of the message returned, empty when there is none
+		message httpcord.Snowflake
+	}{
+		{"created message", http.StatusOK, callbackResponse, "1100000000000000009"},
+		{"callback without a resource", http.StatusOK, `{"interaction":{"id":"1100000000000000005","type":2}}`, ""},
+		{"refused callback", http.StatusBadRequest, `{"code":50035,"message":"Invalid Form Body"}`, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			fake.Expect(http.MethodPost, "/interactions/*/*/callback").RespondStatus(test.status, []byte(test.body)).Times(1)
+
+			var responded bool
+			ctx, finish := httpcord.NewContext(*httpcordtest.NewCommandInteraction("ban"), httpcord.ContextConfig{
+				Respond: func(*httpcord.InteractionResponse) error { responded = true; return nil },
+				Client:  fake.Client(),
+			})
+			defer finish()
+
+			message, err := ctx.ReplyInteractionMessage(&httpcord.InteractionCallbackData{Content: "banned"})
+
+			if test.status != http.StatusOK {
+				var apiErr *httpcord.DiscordAPIError
+				if !errors.As(err, &apiErr) || apiErr.Code != 50035 {
+					t.Fatalf("ReplyInteractionMessage() error %v, want the error of Discord", err)
+				}
+
+				// The failed callback is not the initial response, the error reply can still be sent
+				if err := ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "failed"}); err != nil || !responded {
+					t.Errorf("ReplyInteraction() after the failed callback = %v, responded %v, want it sent", err, responded)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.message == "" && message != nil || test.message != "" && (message == nil || message.ID != test.message) {
+				t.Errorf("ReplyInteractionMessage() = %+v, want the message %q", message, test.message)
+			}
+
+			var sent httpcord.InteractionResponse
+			if err := fake.Requests()[0].Decode(&sent); err != nil || sent.Type != httpcord.ChannelMessageWithSourceResponse ||
+				sent.Data == nil || sent.Data.Content != "banned" {
+				t.Errorf("callback %+v, %v, want the reply", sent, err)
+			}
+
+			if _, err := ctx.ReplyInteractionMessage(&httpcord.InteractionCallbackData{Content: "again"}); !errors.Is(err, httpcord.ErrAlreadyResponded) {
+				t.Errorf("second ReplyInteractionMessage() = %v, want ErrAlreadyResponded", err)
+			}
+
+			if responded {
+				t.Error("the reply went through Respond, want the REST callback")
+			}
+		})
+	}
+}
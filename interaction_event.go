@@ -0,0 +1,70 @@
+package httpcord
+
+import "context"
+
+// InteractionEvent Operations of the interaction handlers independent of the transport, implemented by
+// *ConnectionContext. Handlers written against it run unchanged behind a gateway library during a migration to
+// HTTP interactions, see EventHandler.
+//
+// The httpcord/contrib/discordgo module runs them on discordgo InteractionCreate events, it is a separate module so
+// httpcord does not depend on discordgo. A shim over another library satisfies the contract when:
+//   - Event returns the interaction decoded into this package's types, the raw event JSON unmarshals into Interaction
+//   - the first of ReplyInteraction, ReplyEphemeral, UpdateMessage, the Defer methods, ShowModal and
+//     RespondAutocomplete sends the initial response, the later ones return ErrAlreadyResponded and Responded
+//     reports true from the first one
+//   - EditReply, DeleteReply and FollowUp call the webhook of the interaction token, EditOption values may be ignored
+//   - the accessors return the zero value and false when the interaction is not of their kind, like
+//     ConnectionContext does
+type InteractionEvent interface {
+	// Event Interaction being handled
+	Event() *Interaction
+	Context() context.Context
+	Locale() Locale
+	Responded() bool
+
+	ReplyInteraction(data *InteractionCallbackData) error
+	ReplyEphemeral(data *InteractionCallbackData) error
+	UpdateMessage(data *InteractionCallbackData) error
+	DeferReplyInteraction() error
+	DeferReplyWithFlags(flags MessageFlag) error
+	DeferUpdateInteraction() error
+	ShowModal(modal *Modal) error
+	RespondAutocomplete(choices []ApplicationCommandOptionChoice) error
+
+	EditReply(data *WebhookEdit, opts ...EditOption) (*Message, error)
+	DeleteReply() error
+	FollowUp(data *WebhookEdit) (*Message, error)
+
+	Option(name string) (*ApplicationCommandOption, bool)
+	StringOption(name string) (string, bool)
+	IntOption(name string) (int, bool)
+	NumberOption(name string) (float64, bool)
+	BoolOption(name string) (bool, bool)
+	UserOption(name string) (*User, bool)
+	MemberOption(name string) (*Member, bool)
+	ChannelOption(name string) (*Channel, bool)
+	RoleOption(name string) (*Role, bool)
+	AttachmentOption(name string) (*Attachment, bool)
+	FocusedOption() (FocusedValue, bool)
+
+	CustomID() string
+	SelectedValues() []string
+	ModalValues() map[string]string
+	TargetUser() (*User, bool)
+	TargetMember() (*Member, bool)
+	TargetMessage() (*Message, bool)
+}
+
+var _ InteractionEvent = (*ConnectionContext)(nil)
+
+// Event Interaction being handled, see InteractionEvent
+func (ctx *ConnectionContext) Event() *Interaction {
+	return &ctx.Interaction
+}
+
+// EventHandler Handler running the transport independent handler with the ConnectionContext as its InteractionEvent
+func EventHandler(handler func(event InteractionEvent)) Handler {
+	return func(ctx ConnectionContext) {
+		handler(&ctx)
+	}
+}
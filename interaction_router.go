@@ -0,0 +1,119 @@
+package httpcord
+
+import "strings"
+
+// Middleware Wrap the handlers of an InteractionRouter, like checking permissions before next runs
+type Middleware func(next Handler) Handler
+
+// InteractionRouter Builder registering the routes of Connection.Route, every route is added to the connection
+// as if registered with Connection.Command, Component and Modal. Registrations panic like theirs
+type InteractionRouter interface {
+	// Use Append middlewares to the handlers registered next, panics once the router has routes
+	Use(middlewares ...Middleware)
+	// With Router sharing the prefix with the middlewares appended, for the routes registered through it
+	With(middlewares ...Middleware) InteractionRouter
+	// Group Register the routes of fn under the prefix with the middlewares inherited (See Connection.Route)
+	Group(prefix string, fn func(r InteractionRouter))
+	// Command Register the command under the prefix, the guards run after the middlewares
+	Command(name string, handler Handler, guards ...Guard) *CommandRoute
+	// UserCommand Register the user context menu command with the middlewares, context menu names are not prefixed
+	UserCommand(name string, handler Handler) *CommandRoute
+	// MessageCommand Register the message context menu command with the middlewares, context menu names are not prefixed
+	MessageCommand(name string, handler Handler) *CommandRoute
+	Component(pattern string, handler Handler) *ComponentRoute
+	Modal(pattern string, handler Handler) *ComponentRoute
+}
+
+type interactionRouter struct {
+	router *commandRouter
+	// prefix are the path segments of the groups, "/admin/config" is ["admin", "config"]
+	prefix      []string
+	middlewares []Middleware
+	hasRoutes   bool
+}
+
+// Route Register routes with a builder mirroring HTTP routers, the groups prefix and inherit middlewares:
+//
+//	conn.Route(func(r httpcord.InteractionRouter) {
+//		r.Command("ping", ping)
+//		r.Group("/admin", func(r httpcord.InteractionRouter) {
+//			r.Use(adminOnly)
+//			r.Command("ban", ban)       // "admin ban" subcommand
+//			r.Component("cfg:*", cfg)   // custom_ids like "admin:cfg:3"
+//		})
+//	})
+//
+// Group prefixes are split on "/", commands get them as parent commands and the component and modal patterns as
+// custom_id segments joined with ":". Middlewares run in the order they were added, the outer groups first.
+// Packages can expose a func(r InteractionRouter) to be mounted at any prefix with Group
+func (c *Connection) Route(fn func(r InteractionRouter)) {
+	fn(&interactionRouter{router: c.router})
+}
+
+func (r *interactionRouter) Use(middlewares ...Middleware) {
+	if r.hasRoutes {
+		panic("httpcord: middlewares must be added before the routes of the router")
+	}
+
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+func (r *interactionRouter) With(middlewares ...Middleware) InteractionRouter {
+	return r.child(nil, middlewares)
+}
+
+func (r *interactionRouter) Group(prefix string, fn func(r InteractionRouter)) {
+	fn(r.child(strings.FieldsFunc(prefix, func(c rune) bool { return c == '/' || c == ' ' }), nil))
+}
+
+// child Router inheriting the prefix and middlewares, copied so the routers never share a backing array
+func (r *interactionRouter) child(prefix []string, middlewares []Middleware) *interactionRouter {
+	r.hasRoutes = true
+
+	return &interactionRouter{
+		router:      r.router,
+		prefix:      append(append([]string(nil), r.prefix...), prefix...),
+		middlewares: append(append([]Middleware(nil), r.middlewares...), middlewares...),
+	}
+}
+
+// wrap Handler run through the middlewares, the first one added is the outermost
+func (r *interactionRouter) wrap(handler Handler) Handler {
+	r.hasRoutes = true
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+func (r *interactionRouter) Command(name string, handler Handler, guards ...Guard) *CommandRoute {
+	path := append(append([]string(nil), r.prefix...), strings.Fields(name)...)
+	return r.router.add(&CommandRoute{Name: strings.Join(path, " "), Handler: r.wrap(guardHandler(handler, guards)), Source: callerSite(1)})
+}
+
+func (r *interactionRouter) UserCommand(name string, handler Handler) *CommandRoute {
+	return r.router.addContextMenu(&CommandRoute{Name: strings.TrimSpace(name), Type: UserApplicationCommandType, Handler: r.wrap(handler), Source: callerSite(1)})
+}
+
+func (r *interactionRouter) MessageCommand(name string, handler Handler) *CommandRoute {
+	return r.router.addContextMenu(&CommandRoute{Name: strings.TrimSpace(name), Type: MessageApplicationCommandType, Handler: r.wrap(handler), Source: callerSite(1)})
+}
+
+func (r *interactionRouter) Component(pattern string, handler Handler) *ComponentRoute {
+	return r.router.addUserComponent(&ComponentRoute{Pattern: r.customID(pattern), Handler: r.wrap(handler), Source: callerSite(1)})
+}
+
+func (r *interactionRouter) Modal(pattern string, handler Handler) *ComponentRoute {
+	return r.router.addModal(&ComponentRoute{Pattern: r.customID(pattern), Handler: r.wrap(handler), Source: callerSite(1)})
+}
+
+// customID Pattern with the group prefix, "cfg:*" in the "/admin" group becomes "admin:cfg:*"
+func (r *interactionRouter) customID(pattern string) string {
+	if len(r.prefix) == 0 {
+		return pattern
+	}
+
+	return strings.Join(r.prefix, ":") + ":" + pattern
+}
@@ -0,0 +1,144 @@
+package httpcord_test
+
+import (
+	"strings"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+// contextMenu Context menu interaction of the type on the name
+func contextMenu(name string, kind httpcord.ApplicationCommandType) *httpcord.Interaction {
+	interaction := httpcordtest.NewCommandInteraction(name)
+	data := interaction.ApplicationCommandData()
+	data.Type, data.TargetID = kind, httpcordtest.TestUser.ID
+	interaction.Data = data
+
+	return interaction
+}
+
+func TestInteractionRouterGroups(t *testing.T) {
+	var calls []string
+	trace := func(name string) httpcord.Middleware {
+		return func(next httpcord.Handler) httpcord.Handler {
+			return func(ctx httpcord.ConnectionContext) {
+				calls = append(calls, name)
+				next(ctx)
+			}
+		}
+	}
+
+	handler := func(name string) httpcord.Handler {
+		return func(ctx httpcord.ConnectionContext) {
+			calls = append(calls, name)
+			ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: name})
+		}
+	}
+
+	conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+	conn.FallbackComponent(handler("fallback"))
+
+	conn.Route(func(r httpcord.InteractionRouter) {
+		r.Use(trace("root"))
+		r.Command("ping", handler("ping"))
+
+		r.Group("/admin", func(r httpcord.InteractionRouter) {
+			r.Use(trace("admin"))
+			r.Command("ban", handler("ban"))
+			r.Component("cfg:*", handler("cfg"))
+			r.Modal("note", handler("note"))
+
+			r.Group("config", func(r httpcord.InteractionRouter) {
+				r.Use(trace("config"))
+				r.Command("set", handler("set"))
+				r.Component("reset", handler("reset"))
+			})
+
+			r.With(trace("audit"), trace("log")).Command("audit", handler("audit"))
+			r.Command("kick", handler("kick"))
+			r.UserCommand("Report", handler("report"))
+		})
+
+		r.Group("/tools/dev", func(r httpcord.InteractionRouter) {
+			r.Use(trace("dev"))
+			r.Command("reload", handler("reload"))
+			r.Component("flush", handler("flush"))
+		})
+
+		r.MessageCommand("Quote", handler("quote"))
+	})
+
+	tests := []struct {
+		name        string
+		interaction *httpcord.Interaction
+		// calls Middlewares and handler run, in their order
+		calls string
+	}{
+		{"root command", httpcordtest.NewCommandInteraction("ping"), "root,ping"},
+		{"group command", httpcordtest.NewCommandInteraction("admin", httpcordtest.Subcommand("ban")), "root,admin,ban"},
+		{"nested group command", httpcordtest.NewCommandInteraction("admin",
+			httpcordtest.SubcommandGroup("config", httpcordtest.Subcommand("set"))), "root,admin,config,set"},
+		{"With middlewares after the group ones", httpcordtest.NewCommandInteraction("admin", httpcordtest.Subcommand("audit")),
+			"root,admin,audit,log,audit"},
+		{"With middlewares kept off the siblings", httpcordtest.NewCommandInteraction("admin", httpcordtest.Subcommand("kick")),
+			"root,admin,kick"},
+		{"multi-segment prefix", httpcordtest.NewCommandInteraction("tools",
+			httpcordtest.SubcommandGroup("dev", httpcordtest.Subcommand("reload"))), "root,dev,reload"},
+		{"group component", httpcordtest.NewComponentInteraction("admin:cfg:3"), "root,admin,cfg"},
+		{"nested group component", httpcordtest.NewComponentInteraction("admin:config:reset"), "root,admin,config,reset"},
+		{"multi-segment component prefix", httpcordtest.NewComponentInteraction("tools:dev:flush"), "root,dev,flush"},
+		{"component without its prefix", httpcordtest.NewComponentInteraction("cfg:3"), "fallback"},
+		{"component of the parent prefix", httpcordtest.NewComponentInteraction("admin:reset"), "fallback"},
+		{"group modal", httpcordtest.NewModalInteraction("admin:note", map[string]string{"text": "hello"}), "root,admin,note"},
+		{"unprefixed user command", contextMenu("Report", httpcord.UserApplicationCommandType), "root,admin,report"},
+		{"unprefixed message command", contextMenu("Quote", httpcord.MessageApplicationCommandType), "root,quote"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls = nil
+
+			res := httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, test.interaction))
+			if got := strings.Join(calls, ","); got != test.calls {
+				t.Errorf("ran %q, want %q (%d %s)", got, test.calls, res.Status, res.Body)
+			}
+		})
+	}
+}
+
+func TestInteractionRouterUseAfterRoutes(t *testing.T) {
+	noop := func(next httpcord.Handler) httpcord.Handler { return next }
+
+	tests := []struct {
+		name  string
+		route func(r httpcord.InteractionRouter)
+	}{
+		{"after a route", func(r httpcord.InteractionRouter) {
+			r.Command("ping", func(httpcord.ConnectionContext) {})
+			r.Use(noop)
+		}},
+		{"after a group", func(r httpcord.InteractionRouter) {
+			r.Group("/admin", func(httpcord.InteractionRouter) {})
+			r.Use(noop)
+		}},
+		{"after With", func(r httpcord.InteractionRouter) {
+			r.With(noop)
+			r.Use(noop)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, _ := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+
+			defer func() {
+				if recover() == nil {
+					t.Error("Use() did not panic")
+				}
+			}()
+
+			conn.Route(test.route)
+		})
+	}
+}
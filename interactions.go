@@ -2,6 +2,7 @@ package httpcord
 
 import (
 	"encoding/json"
+	"errors"
 	"httpcord/permissions"
 )
 
@@ -13,6 +14,8 @@ type (
 	TextStyle                    int
 	ComponentType                int
 	ApplicationCommandOptionType int
+	InteractionContextType       int
+	ApplicationIntegrationType   int
 	AnyComponent                 interface{}
 )
 
@@ -38,6 +41,7 @@ const (
 	UpdateMessageResponse
 	ApplicationCommandAutoCompleteResultResponse
 	ModalResponse
+	PremiumRequiredResponse
 )
 
 // Commands Types
@@ -48,6 +52,24 @@ const (
 	MessageApplicationCommandType
 )
 
+// Interaction Context Types
+
+const (
+	// GuildInteractionContext Commands can be used in guilds
+	GuildInteractionContext InteractionContextType = iota
+	// BotDMInteractionContext Commands can be used in the DMs with the bot
+	BotDMInteractionContext
+	// PrivateChannelInteractionContext Commands can be used in group DMs and DMs other than the bot ones
+	PrivateChannelInteractionContext
+)
+
+// Application Integration Types
+
+const (
+	GuildInstallIntegration ApplicationIntegrationType = iota
+	UserInstallIntegration
+)
+
 // Button Styles
 
 const (
@@ -56,6 +78,7 @@ const (
 	SuccessButtonStyle
 	DangerButtonStyle
 	LinkButtonStyle
+	PremiumButtonStyle
 )
 
 // Text Styles
@@ -72,6 +95,10 @@ const (
 	ButtonComponentType
 	SelectMenuComponentType
 	InputTextComponentType
+	UserSelectMenuComponentType
+	RoleSelectMenuComponentType
+	MentionableSelectMenuComponentType
+	ChannelSelectMenuComponentType
 )
 
 // Application Command Option Type
@@ -104,25 +131,33 @@ type Interaction struct {
 	Version       int             `json:"version,omitempty"`
 	Locale        string          `json:"locale"`
 	GuildLocale   string          `json:"guild_locale"`
+	Entitlements  []*Entitlement  `json:"entitlements,omitempty"`
+	// EntitlementSKUIDs Legacy list of the entitled SKUs, sent by older API versions along Entitlements
+	EntitlementSKUIDs []Snowflake `json:"entitlement_sku_ids,omitempty"`
+	// AppPermissions are the permissions of the application in the channel, nil when not sent
+	AppPermissions *permissions.PermissionBit `json:"app_permissions,omitempty"`
+	// Context is where the interaction was triggered, nil when not sent
+	Context *InteractionContextType `json:"context,omitempty"`
 }
 
 type ApplicationCommandInteractionData struct {
-	ID       Snowflake                    `json:"id"`
-	Name     string                       `json:"name"`
-	Type     ApplicationCommandOptionType `json:"type"`
-	Resolved ResolvedData                 `json:"resolved,omitempty"`
-	Options  []ApplicationCommandOption   `json:"options,omitempty"`
-	GuildID  Snowflake                    `json:"guild_id,omitempty"`
-	TargetID Snowflake                    `json:"target_id,omitempty"`
+	ID       Snowflake                  `json:"id"`
+	Name     string                     `json:"name"`
+	Type     ApplicationCommandType     `json:"type"`
+	Resolved ResolvedData               `json:"resolved,omitempty"`
+	Options  []ApplicationCommandOption `json:"options,omitempty"`
+	GuildID  Snowflake                  `json:"guild_id,omitempty"`
+	TargetID Snowflake                  `json:"target_id,omitempty"`
 }
 
+// ResolvedData Entities referenced by the options, keyed by ID
 type ResolvedData struct {
-	Users       []*User       `json:"users,omitempty"`
-	Members     []*Member     `json:"members,omitempty"`
-	Roles       []*Role       `json:"roles,omitempty"`
-	Channels    []*Channel    `json:"channels,omitempty"`
-	Messages    []*Message    `json:"messages,omitempty"`
-	Attachments []*Attachment `json:"attachment,omitempty"`
+	Users       map[Snowflake]*User       `json:"users,omitempty"`
+	Members     map[Snowflake]*Member     `json:"members,omitempty"`
+	Roles       map[Snowflake]*Role       `json:"roles,omitempty"`
+	Channels    map[Snowflake]*Channel    `json:"channels,omitempty"`
+	Messages    map[Snowflake]*Message    `json:"messages,omitempty"`
+	Attachments map[Snowflake]*Attachment `json:"attachments,omitempty"`
 }
 
 type InteractionCallbackData struct {
@@ -137,6 +172,7 @@ type InteractionCallbackData struct {
 	Choices         []*ApplicationCommandOptionChoice `json:"choices,omitempty"`
 	CustomID        string                            `json:"custom_id,omitempty"`
 	Title           string                            `json:"title,omitempty"`
+	Poll            *PollCreate                       `json:"poll,omitempty"`
 }
 
 type InteractionResponse struct {
@@ -164,6 +200,7 @@ type ButtonComponent struct {
 	Emoji    *Emoji        `json:"emoji,omitempty"`
 	URL      string        `json:"url,omitempty"`
 	Disabled bool          `json:"disabled,omitempty"`
+	SKUID    Snowflake     `json:"sku_id,omitempty"`
 }
 
 type SelectMenuComponent struct {
@@ -174,6 +211,16 @@ type SelectMenuComponent struct {
 	MinValues   *int               `json:"min_values,omitempty"`
 	MaxValues   *int               `json:"max_values,omitempty"`
 	Disabled    bool               `json:"disabled,omitempty"`
+	// ChannelTypes restricts the channels of ChannelSelectMenuComponentType menus
+	ChannelTypes []ChannelType `json:"channel_types,omitempty"`
+	// DefaultValues are the entities selected by default in user, role, mentionable and channel menus
+	DefaultValues []*SelectDefaultValue `json:"default_values,omitempty"`
+}
+
+// SelectDefaultValue Entity selected by default, Type is "user", "role" or "channel"
+type SelectDefaultValue struct {
+	ID   Snowflake `json:"id"`
+	Type string    `json:"type"`
 }
 
 type ActionRowComponent struct {
@@ -202,17 +249,20 @@ type ApplicationCommandOptionChoice struct {
 }
 
 type ApplicationCommandOption struct {
-	Type         ApplicationCommandOptionType     `json:"type"`
-	Name         string                           `json:"name"`
-	Description  string                           `json:"description"`
-	Required     bool                             `json:"required,omitempty"`
-	Choices      []ApplicationCommandOptionChoice `json:"choices,omitempty"`
-	Options      []ApplicationCommandOption       `json:"options,omitempty"`
-	ChannelTypes []ChannelType                    `json:"channel_types,omitempty"`
-	MinValue     float64                          `json:"min_value,omitempty"`
-	MaxValue     *float64                         `json:"max_value,omitempty"`
-	Autocomplete bool                             `json:"autocomplete,omitempty"`
-	Value        interface{}
+	Type                     ApplicationCommandOptionType     `json:"type"`
+	Name                     string                           `json:"name"`
+	NameLocalizations        Dictionary                       `json:"name_localizations,omitempty"`
+	Description              string                           `json:"description"`
+	DescriptionLocalizations Dictionary                       `json:"description_localizations,omitempty"`
+	Required                 bool                             `json:"required,omitempty"`
+	Choices                  []ApplicationCommandOptionChoice `json:"choices,omitempty"`
+	Options                  []ApplicationCommandOption       `json:"options,omitempty"`
+	ChannelTypes             []ChannelType                    `json:"channel_types,omitempty"`
+	MinValue                 *float64                         `json:"min_value,omitempty"`
+	MaxValue                 *float64                         `json:"max_value,omitempty"`
+	Autocomplete             bool                             `json:"autocomplete,omitempty"`
+	Focused                  bool                             `json:"focused,omitempty"`
+	Value                    interface{}
 }
 
 type ApplicationCommand struct {
@@ -238,6 +288,10 @@ type ApplicationCommand struct {
 	DefaultPermissions *permissions.PermissionBit `json:"default_member_permissions,omitempty"`
 	// AllowUseInDMs Indicates whether the command is available in DMs with the app, only for globally-scoped commands. By default, commands are visible.
 	AllowUseInDMs *bool `json:"dm_permission,omitempty"`
+	// Contexts where the command can be used, every context when nil
+	Contexts []InteractionContextType `json:"contexts,omitempty"`
+	// IntegrationTypes installs the command is available to, defaults to GuildInstallIntegration
+	IntegrationTypes []ApplicationIntegrationType `json:"integration_types,omitempty"`
 	// DefaultPermission is whether the command is enabled by default when the app is added to a guild
 	DefaultPermission *bool `json:"default_permission,omitempty"`
 	// Version is an autoincrement version identifier updated during substantial record changes
@@ -253,6 +307,8 @@ type ComponentInteractionData struct {
 type ModalSubmitInteractionData struct {
 	CustomID   string                `json:"custom_id"`
 	Components []*ActionRowComponent `json:"components"`
+	// stateToken identifies the state of ShowModalWithState
+	stateToken string
 }
 
 func (i *Interaction) ModalSubmitData() ModalSubmitInteractionData {
@@ -279,45 +335,84 @@ func (i *Interaction) ComponentData() ComponentInteractionData {
 	return i.Data.(ComponentInteractionData)
 }
 
+// UserValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) UserValue(name string, required bool) *User {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(*User)
+	deprecated("ApplicationCommandInteractionData.UserValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(*User)
 }
 
+// StringValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) StringValue(name string, required bool) string {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(string)
+	deprecated("ApplicationCommandInteractionData.StringValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(string)
 }
 
+// BoolValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) BoolValue(name string, required bool) bool {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(bool)
+	deprecated("ApplicationCommandInteractionData.BoolValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(bool)
 }
 
+// MemberValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) MemberValue(name string, required bool) *Member {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(*Member)
+	deprecated("ApplicationCommandInteractionData.MemberValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(*Member)
 }
 
+// IntValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) IntValue(name string, required bool) int {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(int)
+	deprecated("ApplicationCommandInteractionData.IntValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(int)
 }
 
+// NumberValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) NumberValue(name string, required bool) float64 {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(float64)
+	deprecated("ApplicationCommandInteractionData.NumberValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(float64)
 }
 
+// ChannelValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) ChannelValue(name string, required bool) *Channel {
-	return c.GetOption(name, ChannelApplicationCommandOptionType, required).(*Channel)
+	deprecated("ApplicationCommandInteractionData.ChannelValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, ChannelApplicationCommandOptionType, required).(*Channel)
 }
 
+// AttachmentValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) AttachmentValue(name string, required bool) *Attachment {
-	return c.GetOption(name, AttachmentApplicationCommandOptionType, required).(*Attachment)
+	deprecated("ApplicationCommandInteractionData.AttachmentValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, AttachmentApplicationCommandOptionType, required).(*Attachment)
 }
 
 // MentionableValue MentionableValue Returns Member, User or Role
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) MentionableValue(name string, required bool) interface{} {
-	return c.GetOption(name, MentionableApplicationCommandOptionType, required)
+	deprecated("ApplicationCommandInteractionData.MentionableValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, MentionableApplicationCommandOptionType, required)
 }
 
+// RoleValue Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) RoleValue(name string, required bool) *Role {
-	return c.GetOption(name, UserApplicationCommandOptionType, required).(*Role)
+	deprecated("ApplicationCommandInteractionData.RoleValue", "ConnectionContext.BindOptions", 0)
+	return c.getOption(name, UserApplicationCommandOptionType, required).(*Role)
 }
 
 func (c *ApplicationCommandInteractionData) SubCommand() ApplicationCommandOption {
@@ -340,7 +435,16 @@ func (c *ApplicationCommandInteractionData) SubCommandGroup() ApplicationCommand
 	return option
 }
 
+// GetOption Value of the option
+//
+// Deprecated: panics on missing options, use ConnectionContext.BindOptions instead
 func (c *ApplicationCommandInteractionData) GetOption(name string, Type ApplicationCommandOptionType, required bool) interface{} {
+	deprecated("ApplicationCommandInteractionData.GetOption", "ConnectionContext.BindOptions", 0)
+
+	return c.getOption(name, Type, required)
+}
+
+func (c *ApplicationCommandInteractionData) getOption(name string, Type ApplicationCommandOptionType, required bool) interface{} {
 	for _, option := range c.Options {
 		if option.Name == name && option.Type == Type {
 			return option.Value
@@ -444,6 +548,21 @@ func (s *SelectMenuComponent) SetCustomID(customID string) *SelectMenuComponent
 	return s
 }
 
+func (s *SelectMenuComponent) SetType(Type ComponentType) *SelectMenuComponent {
+	s.Type = Type
+	return s
+}
+
+func (s *SelectMenuComponent) SetChannelTypes(types ...ChannelType) *SelectMenuComponent {
+	s.ChannelTypes = types
+	return s
+}
+
+func (s *SelectMenuComponent) SetDefaultValues(values ...*SelectDefaultValue) *SelectMenuComponent {
+	s.DefaultValues = values
+	return s
+}
+
 // ApplicationCommandOptionBuilder
 
 func ApplicationCommandOptionBuilder() *ApplicationCommandOption {
@@ -491,7 +610,13 @@ func (o *ApplicationCommandOption) IsRequired(required bool) *ApplicationCommand
 }
 
 func (o *ApplicationCommandOption) SetMinValue(MinValue float64) *ApplicationCommandOption {
-	o.MinValue = MinValue
+	o.MinValue = &MinValue
+	return o
+}
+
+// ClearMinValue Remove the minimum, SetMinValue(0) sends an explicit 0 bound
+func (o *ApplicationCommandOption) ClearMinValue() *ApplicationCommandOption {
+	o.MinValue = nil
 	return o
 }
 
@@ -577,6 +702,11 @@ func (b *ButtonComponent) IsDisabled(disabled bool) *ButtonComponent {
 	return b
 }
 
+func (b *ButtonComponent) SetSKUID(skuID Snowflake) *ButtonComponent {
+	b.SKUID = skuID
+	return b
+}
+
 // ActionRowComponentBuilder
 
 func NewActionRowComponentBuilder() *ActionRowComponent {
@@ -665,39 +795,90 @@ func (c *ApplicationCommand) SetDescriptionLocalizations(DescriptionLocalization
 	return c
 }
 
+// ResolveInteraction Panics on malformed payloads, see MalformedInteractionError
 func ResolveInteraction(rawInteraction *APIInteraction) Interaction {
+	interaction, err := resolveInteraction(StdCodec, rawInteraction)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return interaction
+}
+
+// decodeInteractionData Decode the data of the interaction once with the codec, the numbers of its interface{}
+// values are kept as json.Number like DecodeJSON does
+func decodeInteractionData(codec Codec, raw json.RawMessage, data interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	if err := codec.Unmarshal(raw, data); err != nil {
+		return &MalformedInteractionError{Err: err}
+	}
+
+	return nil
+}
+
+// floatOptionValues Values of the integer and number options as float64, like NumberOption and the validators
+// read them. Discord limits them to 2^53 so they are exact
+func floatOptionValues(options []ApplicationCommandOption) {
+	for i := range options {
+		if number, ok := options[i].Value.(json.Number); ok {
+			if value, err := number.Float64(); err == nil {
+				options[i].Value = value
+			}
+		}
+
+		floatOptionValues(options[i].Options)
+	}
+}
+
+func resolveInteraction(codec Codec, rawInteraction *APIInteraction) (Interaction, error) {
 	if rawInteraction.Type == PingInteraction {
-		return Interaction{Type: rawInteraction.Type}
+		return Interaction{Type: rawInteraction.Type}, nil
 	}
 
 	interaction := &Interaction{
-		ID:            Snowflake(rawInteraction.ID),
-		ApplicationID: Snowflake(rawInteraction.ApplicationID),
-		Type:          rawInteraction.Type,
-		GuildID:       Snowflake(rawInteraction.GuildID),
-		ChannelID:     Snowflake(rawInteraction.ChannelID),
+		ID:                Snowflake(rawInteraction.ID),
+		ApplicationID:     Snowflake(rawInteraction.ApplicationID),
+		Type:              rawInteraction.Type,
+		GuildID:           Snowflake(rawInteraction.GuildID),
+		ChannelID:         Snowflake(rawInteraction.ChannelID),
+		Token:             rawInteraction.Token,
+		Version:           rawInteraction.Version,
+		Locale:            rawInteraction.Locale,
+		GuildLocale:       rawInteraction.GuildLocale,
+		Entitlements:      rawInteraction.Entitlements,
+		EntitlementSKUIDs: rawInteraction.EntitlementSKUIDs,
+		Message:           rawInteraction.Message,
+		Context:           rawInteraction.Context,
 	}
 
-	if interaction.GuildID.String() != "" {
-		interaction.Member = ResolveMember(rawInteraction.Member)
-		interaction.User = ResolveUser(rawInteraction.Member.User)
-	} else {
-		interaction.User = ResolveUser(rawInteraction.User)
+	if rawInteraction.AppPermissions != "" {
+		interaction.AppPermissions = parseAppPermissions(rawInteraction.AppPermissions)
 	}
 
-	marshaledData, err := json.Marshal(rawInteraction.Data)
+	if interaction.GuildID.String() != "" {
+		member, err := resolveMember(rawInteraction.Member)
+		if err != nil {
+			return Interaction{}, err
+		}
 
-	if err != nil {
-		panic(err)
+		interaction.Member = member
+		interaction.User = member.User
+	} else if rawInteraction.User != nil {
+		interaction.User = ResolveUser(rawInteraction.User)
+	} else {
+		return Interaction{}, &MalformedInteractionError{Err: errors.New("missing user")}
 	}
 
 	switch interaction.Type {
 	case MessageComponentInteraction:
 		{
 			var data ComponentInteractionData
-			err = json.Unmarshal(marshaledData, &data)
-			if err != nil {
-				panic(err)
+			if err := decodeInteractionData(codec, rawInteraction.Data, &data); err != nil {
+				return Interaction{}, err
 			}
 
 			interaction.Data = data
@@ -705,24 +886,26 @@ func ResolveInteraction(rawInteraction *APIInteraction) Interaction {
 	case ModalSubmitInteraction:
 		{
 			var data ModalSubmitInteractionData
-			err = json.Unmarshal(marshaledData, &data)
-			if err != nil {
-				panic(err)
+			if err := decodeInteractionData(codec, rawInteraction.Data, &data); err != nil {
+				return Interaction{}, err
 			}
 
+			splitModalState(&data)
+
 			interaction.Data = data
 		}
-	case ApplicationCommandInteraction:
+	case ApplicationCommandInteraction, AutoCompleteInteraction:
 		{
 			var data ApplicationCommandInteractionData
-			err = json.Unmarshal(marshaledData, &data)
-			if err != nil {
-				panic(err)
+			if err := decodeInteractionData(codec, rawInteraction.Data, &data); err != nil {
+				return Interaction{}, err
 			}
 
+			floatOptionValues(data.Options)
+
 			interaction.Data = data
 		}
 	}
 
-	return *interaction
+	return *interaction, nil
 }
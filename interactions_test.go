@@ -0,0 +1,82 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestResolveInteractionKeepsNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		check func(t *testing.T, interaction Interaction)
+	}{
+		{
+			name: "modal component id above 2^53",
+			body: `{"id":"1","application_id":"2","type":5,"token":"t","version":1,"user":{"id":"3"},
+				"data":{"custom_id":"form","components":[{"type":1,"components":[{"type":99,"id":18446744073709551615}]}]}}`,
+			check: func(t *testing.T, interaction Interaction) {
+				data := interaction.ModalSubmitData()
+				component, ok := data.Components[0].Components[0].(map[string]interface{})
+				if !ok {
+					t.Fatalf("component is %T", data.Components[0].Components[0])
+				}
+
+				if id, ok := component["id"].(json.Number); !ok || id.String() != "18446744073709551615" {
+					t.Errorf("id = %#v, want json.Number 18446744073709551615", component["id"])
+				}
+			},
+		},
+		{
+			name: "integer and number options as float64",
+			body: `{"id":"1","application_id":"2","type":2,"token":"t","version":1,"user":{"id":"3"},
+				"data":{"id":"4","name":"config","type":1,"options":[{"type":1,"name":"set","options":[
+					{"type":4,"name":"days","value":7},{"type":10,"name":"ratio","value":0.5}]}]}}`,
+			check: func(t *testing.T, interaction Interaction) {
+				data := interaction.ApplicationCommandData()
+				options := data.Options[0].Options
+
+				if value, ok := options[0].Value.(float64); !ok || value != 7 {
+					t.Errorf("days = %#v, want 7", options[0].Value)
+				}
+
+				if value, ok := options[1].Value.(float64); !ok || value != 0.5 {
+					t.Errorf("ratio = %#v, want 0.5", options[1].Value)
+				}
+			},
+		},
+		{
+			name: "no data",
+			body: `{"id":"1","application_id":"2","type":3,"token":"t","version":1,"user":{"id":"3"}}`,
+			check: func(t *testing.T, interaction Interaction) {
+				if data := interaction.Data.(ComponentInteractionData); data.CustomID != "" {
+					t.Errorf("custom_id = %q", data.CustomID)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw APIInteraction
+			if err := DecodeJSON([]byte(test.body), &raw); err != nil {
+				t.Fatal(err)
+			}
+
+			interaction, err := resolveInteraction(StdCodec, &raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			test.check(t, interaction)
+		})
+	}
+}
+
+func TestResolveInteractionMalformedData(t *testing.T) {
+	raw := APIInteraction{Type: ApplicationCommandInteraction, User: &APIUser{ID: "3"}, Data: json.RawMessage(`{"options":"x"}`)}
+
+	if _, err := resolveInteraction(StdCodec, &raw); err == nil {
+		t.Fatal("malformed data was accepted")
+	}
+}
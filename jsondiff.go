@@ -0,0 +1,163 @@
+package httpcord
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONDifference Value differing between two JSON documents, a nil side is missing
+type JSONDifference struct {
+	// Path is the dotted path of the value like "data.embeds.0.title", empty for the whole document
+	Path  string
+	Left  json.RawMessage
+	Right json.RawMessage
+}
+
+// DiffJSON Structural differences of two JSON documents, object keys are compared in any order and numbers by value.
+// Ignored paths use the DiffJSON path format where "*" matches any key or index, like "data.embeds.*.timestamp",
+// and ignore the whole value under them
+func DiffJSON(left, right []byte, ignore ...string) ([]JSONDifference, error) {
+	a, err := decodeDiffJSON(left)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := decodeDiffJSON(right)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := make([][]string, len(ignore))
+	for i, pattern := range ignore {
+		patterns[i] = strings.Split(pattern, ".")
+	}
+
+	var differences []JSONDifference
+	diffJSONValues(nil, a, b, true, true, patterns, &differences)
+
+	return differences, nil
+}
+
+func decodeDiffJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func diffJSONValues(path []string, a, b interface{}, hasA, hasB bool, ignore [][]string, out *[]JSONDifference) {
+	if ignoredJSONPath(path, ignore) {
+		return
+	}
+
+	if hasA && hasB {
+		switch a := a.(type) {
+		case map[string]interface{}:
+			if b, ok := b.(map[string]interface{}); ok {
+				keys := make([]string, 0, len(a)+len(b))
+				for key := range a {
+					keys = append(keys, key)
+				}
+
+				for key := range b {
+					if _, ok := a[key]; !ok {
+						keys = append(keys, key)
+					}
+				}
+
+				sort.Strings(keys)
+
+				for _, key := range keys {
+					valueA, okA := a[key]
+					valueB, okB := b[key]
+					diffJSONValues(append(path, key), valueA, valueB, okA, okB, ignore, out)
+				}
+
+				return
+			}
+		case []interface{}:
+			if b, ok := b.([]interface{}); ok {
+				length := len(a)
+				if len(b) > length {
+					length = len(b)
+				}
+
+				for i := 0; i < length; i++ {
+					var valueA, valueB interface{}
+					if i < len(a) {
+						valueA = a[i]
+					}
+
+					if i < len(b) {
+						valueB = b[i]
+					}
+
+					diffJSONValues(append(path, strconv.Itoa(i)), valueA, valueB, i < len(a), i < len(b), ignore, out)
+				}
+
+				return
+			}
+		case json.Number:
+			if b, ok := b.(json.Number); ok && jsonNumbersEqual(a, b) {
+				return
+			}
+		default:
+			if a == b {
+				return
+			}
+		}
+	}
+
+	difference := JSONDifference{Path: strings.Join(path, ".")}
+	if hasA {
+		difference.Left, _ = json.Marshal(a)
+	}
+
+	if hasB {
+		difference.Right, _ = json.Marshal(b)
+	}
+
+	*out = append(*out, difference)
+}
+
+func jsonNumbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	x, errA := a.Float64()
+	y, errB := b.Float64()
+
+	return errA == nil && errB == nil && x == y
+}
+
+// ignoredJSONPath The path is one of the ignored paths or under them
+func ignoredJSONPath(path []string, ignore [][]string) bool {
+	for _, pattern := range ignore {
+		if len(pattern) > len(path) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
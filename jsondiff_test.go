@@ -0,0 +1,59 @@
+package httpcord
+
+import "testing"
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		left   string
+		right  string
+		ignore []string
+		paths  []string
+		fails  bool
+	}{
+		{"equal with reordered keys", `{"type":4,"data":{"content":"hi","flags":64}}`, `{"data":{"flags":64,"content":"hi"},"type":4}`, nil, nil, false},
+		{"numbers compared by value", `{"retry_after":1}`, `{"retry_after":1.0}`, nil, nil, false},
+		{"changed value", `{"data":{"content":"hi"}}`, `{"data":{"content":"hello"}}`, nil, []string{"data.content"}, false},
+		{"missing key", `{"data":{"content":"hi","flags":64}}`, `{"data":{"content":"hi"}}`, nil, []string{"data.flags"}, false},
+		{"added key", `{"data":{}}`, `{"data":{"tts":true}}`, nil, []string{"data.tts"}, false},
+		{"null is not missing", `{"data":null}`, `{}`, nil, []string{"data"}, false},
+		{"longer array", `{"embeds":[{"title":"a"}]}`, `{"embeds":[{"title":"a"},{"title":"b"}]}`, nil, []string{"embeds.1"}, false},
+		{"array element", `{"embeds":[{"title":"a"},{"title":"b"}]}`, `{"embeds":[{"title":"a"},{"title":"c"}]}`, nil, []string{"embeds.1.title"}, false},
+		{"type mismatch", `{"id":"1"}`, `{"id":1}`, nil, []string{"id"}, false},
+		{"different documents", `{"type":4}`, `null`, nil, []string{""}, false},
+		{"sorted paths", `{"b":1,"a":1}`, `{"b":2,"a":2}`, nil, []string{"a", "b"}, false},
+		{"ignored path", `{"data":{"content":"hi","nonce":"1"}}`, `{"data":{"content":"hi","nonce":"2"}}`, []string{"data.nonce"}, nil, false},
+		{"ignored wildcard", `{"embeds":[{"timestamp":"1","title":"a"},{"timestamp":"2"}]}`, `{"embeds":[{"timestamp":"3","title":"b"},{"timestamp":"4"}]}`,
+			[]string{"embeds.*.timestamp"}, []string{"embeds.0.title"}, false},
+		{"ignored subtree", `{"data":{"trace":{"id":"1","span":"2"}}}`, `{"data":{"trace":{"id":"3"}}}`, []string{"data.trace"}, nil, false},
+		{"invalid left", `{"type":`, `{}`, nil, nil, true},
+		{"invalid right", `{}`, `not json`, nil, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			differences, err := DiffJSON([]byte(test.left), []byte(test.right), test.ignore...)
+			if test.fails {
+				if err == nil {
+					t.Fatalf("DiffJSON() = %v, want an error", differences)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(differences) != len(test.paths) {
+				t.Fatalf("differences %+v, want the paths %q", differences, test.paths)
+			}
+
+			for i, difference := range differences {
+				if difference.Path != test.paths[i] {
+					t.Errorf("difference %d at %q, want %q", i, difference.Path, test.paths[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// lifecycle Servers and work of a Connection, shared by its copies
+type lifecycle struct {
+	mu         sync.Mutex
+	server     *http.Server
+	fastServer *fasthttp.Server
+	closing    bool
+	// unready fails the readiness checks before closing, see StartDrain
+	unready bool
+	// active counts the requests being dispatched and the background work they started
+	active     int
+	drained    chan struct{}
+	onShutdown []func(ctx context.Context)
+	pool       *workerPool
+	scheduler  *Scheduler
+	// dispatch bounds the interactions dispatched at once, nil when unbounded
+	dispatch *dispatchPool
+	// drainDelay and shutdownTimeout are the RunUntilSignal timings
+	drainDelay      time.Duration
+	shutdownTimeout time.Duration
+	// ctx is canceled when closing is set, see context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+const (
+	// DefaultDrainDelay Time RunUntilSignal serves with a failing readiness unless ConnectionOptions.DrainDelay is set
+	DefaultDrainDelay = 5 * time.Second
+	// DefaultShutdownTimeout Shutdown deadline of RunUntilSignal unless ConnectionOptions.ShutdownTimeout is set
+	DefaultShutdownTimeout = 20 * time.Second
+)
+
+// begin Track a request or background job, false once shutting down
+func (l *lifecycle) begin() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closing {
+		return false
+	}
+
+	l.active++
+	return true
+}
+
+func (l *lifecycle) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+
+	if l.active == 0 && l.drained != nil {
+		close(l.drained)
+		l.drained = nil
+	}
+}
+
+// readiness Answer the readiness check, 503 once draining or closing
+func (l *lifecycle) readiness(w http.ResponseWriter) {
+	l.mu.Lock()
+	ready := !l.unready && !l.closing
+	l.mu.Unlock()
+
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// context Context of the background jobs, canceled once Shutdown starts so they stop instead of holding the drain
+func (l *lifecycle) context() context.Context {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ensureContext()
+	return l.ctx
+}
+
+// done Closed once Shutdown starts
+func (l *lifecycle) done() <-chan struct{} {
+	return l.context().Done()
+}
+
+func (l *lifecycle) ensureContext() {
+	if l.ctx == nil {
+		l.ctx, l.cancel = context.WithCancel(context.Background())
+	}
+}
+
+// close Refuse the new work and signal the background jobs, called with mu held
+func (l *lifecycle) close() {
+	l.closing = true
+	l.ensureContext()
+	l.cancel()
+}
+
+// background Run fn in a goroutine tracked by the drain phase of Shutdown
+func (l *lifecycle) background(fn func()) {
+	l.mu.Lock()
+	l.active++
+	l.mu.Unlock()
+
+	go func() {
+		defer l.end()
+		fn()
+	}()
+}
+
+// drain Wait for the tracked work to finish or the context to be done
+func (l *lifecycle) drain(ctx context.Context) error {
+	l.mu.Lock()
+
+	if l.active == 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.drained == nil {
+		l.drained = make(chan struct{})
+	}
+
+	drained := l.drained
+	l.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OnShutdown Register a cleanup run by Shutdown after the drain, callbacks run in registration order.
+// The context is the one given to Shutdown and may already be expired when the drain timed out
+func (c *Connection) OnShutdown(fn func(ctx context.Context)) {
+	c.life.mu.Lock()
+	defer c.life.mu.Unlock()
+
+	c.life.onShutdown = append(c.life.onShutdown, fn)
+}
+
+// Connect Listen on the address and serve the interactions until Shutdown, returns nil when stopped by Shutdown
+func (c *Connection) Connect(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return c.Serve(listener)
+}
+
+// ConnectTLS Like Connect over HTTPS with the certificate and key files
+func (c *Connection) ConnectTLS(address, certFile, keyFile string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return c.ServeTLS(listener, certFile, keyFile)
+}
+
+// Serve Accept interactions on the listener until Shutdown, returns nil when stopped by Shutdown
+func (c *Connection) Serve(listener net.Listener) error {
+	return c.serve(listener, "", "")
+}
+
+// ServeTLS Like Serve over HTTPS with the certificate and key files
+func (c *Connection) ServeTLS(listener net.Listener, certFile, keyFile string) error {
+	return c.serve(listener, certFile, keyFile)
+}
+
+// serve Serve the listener with the server of the connection, over TLS when certFile is set
+func (c *Connection) serve(listener net.Listener, certFile, keyFile string) error {
+	c.life.mu.Lock()
+
+	if c.life.closing {
+		c.life.mu.Unlock()
+		listener.Close()
+		return nil
+	}
+
+	if c.FastHandler != nil {
+		server := &fasthttp.Server{Handler: c.FastHandler}
+		c.life.fastServer = server
+		c.life.mu.Unlock()
+
+		var err error
+		if certFile != "" {
+			err = server.ServeTLS(listener, certFile, keyFile)
+		} else {
+			err = server.Serve(listener)
+		}
+
+		if c.closing() {
+			return nil
+		}
+
+		return err
+	}
+
+	server := &http.Server{Handler: c.DefaultHandler}
+	c.life.server = server
+	c.life.mu.Unlock()
+
+	var err error
+	if certFile != "" {
+		err = server.ServeTLS(listener, certFile, keyFile)
+	} else {
+		err = server.Serve(listener)
+	}
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// ServeHTTP Handle the interaction requests of any path, to mount the connection in an existing router like
+// mux.Handle("/api/interactions", conn). Shutdown waits for the interactions it is dispatching
+func (c *Connection) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.handler(w, r)
+}
+
+func (c *Connection) closing() bool {
+	c.life.mu.Lock()
+	defer c.life.mu.Unlock()
+
+	return c.life.closing
+}
+
+// Shutdown Stop gracefully, in order:
+//  1. stop accepting requests, interactions arriving from now on get 503, and cancel the context of the background
+//     jobs like the webhook event handlers
+//  2. wait for the interactions being dispatched and their background work like follow-ups and progress edits
+//  3. run the OnShutdown callbacks in registration order
+//
+// Scheduled follow-ups stop firing and stay in the ScheduleStore for the next start.
+// The callbacks always run, with the expired context when the drain exceeded its deadline. Returns the first error
+func (c *Connection) Shutdown(ctx context.Context) error {
+	c.life.mu.Lock()
+	c.life.close()
+	server, fastServer := c.life.server, c.life.fastServer
+	callbacks := c.life.onShutdown
+	c.life.mu.Unlock()
+
+	c.life.scheduler.stop()
+
+	var err error
+
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+
+	if fastServer != nil {
+		done := make(chan error, 1)
+		go func() {
+			done <- fastServer.Shutdown()
+		}()
+
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	if drainErr := c.life.drain(ctx); err == nil {
+		err = drainErr
+	}
+
+	for _, fn := range callbacks {
+		fn(ctx)
+	}
+
+	return err
+}
+
+// ShutdownError Shutdown of RunUntilSignal failed or exceeded ConnectionOptions.ShutdownTimeout, the errors of the
+// server itself are returned as they are
+type ShutdownError struct {
+	Signal os.Signal
+	Err    error
+}
+
+func (e *ShutdownError) Error() string {
+	return "httpcord: shutdown after " + e.Signal.String() + ": " + e.Err.Error()
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// StartDrain Fail the checks of ConnectionOptions.ReadinessPath with 503 while still serving the interactions,
+// so the load balancers stop routing traffic before Shutdown
+func (c *Connection) StartDrain() {
+	c.life.mu.Lock()
+	defer c.life.mu.Unlock()
+
+	c.life.unready = true
+}
+
+// RunUntilSignal Serve like Connect until one of the signals (Defaults to SIGTERM and os.Interrupt), then in order:
+//  1. fail the readiness checks (See StartDrain)
+//  2. keep serving during ConnectionOptions.DrainDelay, a second signal ends the delay
+//  3. Shutdown with the ConnectionOptions.ShutdownTimeout deadline
+//
+// Returns nil after a clean shutdown, the serve error when the server failed on its own and a ShutdownError when
+// Shutdown failed
+func (c *Connection) RunUntilSignal(address string, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, signals...)
+	defer signal.Stop(received)
+
+	return c.runUntil(listener, received)
+}
+
+// runUntil Serve the listener until a signal is received, see RunUntilSignal
+func (c *Connection) runUntil(listener net.Listener, signals <-chan os.Signal) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- c.Serve(listener)
+	}()
+
+	var sig os.Signal
+	select {
+	case err := <-served:
+		return err
+	case sig = <-signals:
+	}
+
+	c.logger.Info("draining before shutdown", "signal", sig.String(), "delay", c.life.drainDelay.String())
+	c.StartDrain()
+
+	if c.life.drainDelay > 0 {
+		timer := time.NewTimer(c.life.drainDelay)
+
+		select {
+		case <-timer.C:
+		case <-signals:
+			timer.Stop()
+		case err := <-served:
+			timer.Stop()
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.life.shutdownTimeout)
+	defer cancel()
+
+	if err := c.Shutdown(ctx); err != nil {
+		return &ShutdownError{Signal: sig, Err: err}
+	}
+
+	return <-served
+}
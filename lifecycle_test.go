@@ -0,0 +1,347 @@
+package httpcord
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsBackgroundJobs(t *testing.T) {
+	tests := []struct {
+		name string
+		job  func(l *lifecycle) func()
+	}{
+		{"context", func(l *lifecycle) func() {
+			ctx := l.context()
+			return func() { <-ctx.Done() }
+		}},
+		{"done channel", func(l *lifecycle) func() {
+			return func() { <-l.done() }
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{})
+			conn.life.background(test.job(conn.life))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if err := conn.Shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown = %v, the job was not signalled", err)
+			}
+
+			if conn.life.context().Err() == nil {
+				t.Error("the lifecycle context is not canceled")
+			}
+		})
+	}
+}
+
+func TestLifecycleContextAfterShutdown(t *testing.T) {
+	conn := newTestConnection(t, ConnectionOptions{})
+
+	if err := conn.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-conn.life.done():
+	default:
+		t.Fatal("done is not closed after Shutdown")
+	}
+
+	if conn.life.begin() {
+		t.Error("work was accepted after Shutdown")
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	client, sent := followUpServer(t)
+	clock := newFakeClock()
+	store := NewMemoryScheduleStore()
+
+	conn, sign := signedConnection(t, ConnectionOptions{ScheduleStore: store, Clock: clock, Logger: NopLogger})
+	conn.Client.BaseURL = client.BaseURL
+
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, event)
+	}
+
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+
+	conn.Command("ban", func(ctx ConnectionContext) {
+		entered <- struct{}{}
+		<-release
+
+		if _, err := ctx.ScheduleFollowUp(10*time.Minute, &WebhookEdit{Content: "reminder"}); err != nil {
+			t.Errorf("ScheduleFollowUp() = %v", err)
+		}
+
+		ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+		record("handler returned")
+
+		// The edit queue of the interaction, drained before the callbacks
+		ctx.RunDeferred(func(context.Context) {
+			time.Sleep(20 * time.Millisecond)
+
+			if _, err := ctx.EditReply(&WebhookEdit{Content: "edited"}); err != nil {
+				t.Errorf("EditReply() = %v", err)
+			}
+
+			record("edit sent")
+		})
+	})
+
+	conn.OnShutdown(func(context.Context) {
+		record("callback")
+
+		if entries, _ := store.Load(); len(entries) != 1 {
+			t.Errorf("%d scheduled follow-ups stored for the next start, want 1", len(entries))
+		}
+	})
+
+	inFlight := httptest.NewRecorder()
+	served := make(chan struct{})
+
+	go func() {
+		defer close(served)
+		conn.ServeHTTP(inFlight, sign(commandBody()))
+	}()
+
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- conn.Shutdown(ctx)
+	}()
+
+	for !conn.closing() {
+		time.Sleep(time.Millisecond)
+	}
+
+	late := httptest.NewRecorder()
+	conn.ServeHTTP(late, sign(commandBody()))
+
+	if late.Code != http.StatusServiceUnavailable {
+		t.Errorf("request during the shutdown got %d, want 503", late.Code)
+	}
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() = %v before the in-flight request finished", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-served
+
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if inFlight.Code != http.StatusOK || !strings.Contains(inFlight.Body.String(), "banned") {
+		t.Errorf("in-flight request got %d %s, want its reply", inFlight.Code, inFlight.Body)
+	}
+
+	if got := strings.Join(events, ", "); got != "handler returned, edit sent, callback" {
+		t.Errorf("events %q, want the handler, then its edit, then the callback", got)
+	}
+
+	// The scheduler stopped with the shutdown, the follow-up waits for the next start
+	clock.Advance(10 * time.Minute)
+
+	if got := sent(); len(got) != 1 || got[0] != "edited" {
+		t.Errorf("sent %q, want only the edit", got)
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+		// second Send a second signal while draining
+		second bool
+	}{
+		{"drain delay", 200 * time.Millisecond, false},
+		{"second signal ends the delay", time.Hour, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, ReadinessPath: "/ready", DrainDelay: test.delay})
+
+			shutdown := make(chan time.Time, 1)
+			conn.OnShutdown(func(context.Context) { shutdown <- time.Now() })
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			url := "http://" + listener.Addr().String()
+			signals := make(chan os.Signal, 2)
+
+			done := make(chan error, 1)
+			go func() { done <- conn.runUntil(listener, signals) }()
+
+			// send Status of the request, 0 once the server is closed
+			send := func(r *http.Request) int {
+				req, _ := http.NewRequest(r.Method, url+r.URL.Path, r.Body)
+				req.Header = r.Header
+
+				res, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return 0
+				}
+				res.Body.Close()
+
+				return res.StatusCode
+			}
+
+			ready := func() int { return send(httptest.NewRequest(http.MethodGet, "/ready", nil)) }
+
+			for ready() != http.StatusOK {
+				time.Sleep(time.Millisecond)
+			}
+
+			signaled := time.Now()
+			signals <- syscall.SIGTERM
+
+			for ready() != http.StatusServiceUnavailable {
+				time.Sleep(time.Millisecond)
+			}
+
+			if conn.closing() {
+				t.Fatal("Shutdown started with the drain")
+			}
+
+			if status := send(sign(commandBody())); status != http.StatusOK {
+				t.Errorf("interaction during the drain got %d, want it served", status)
+			}
+
+			if test.second {
+				signals <- os.Interrupt
+			}
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("runUntil() = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("runUntil() did not return")
+			}
+
+			select {
+			case at := <-shutdown:
+				if !test.second && at.Sub(signaled) < test.delay {
+					t.Errorf("Shutdown %v after the signal, want the %v delay", at.Sub(signaled), test.delay)
+				}
+			default:
+				t.Error("runUntil() returned without Shutdown")
+			}
+		})
+	}
+}
+
+func TestServeHTTPMounted(t *testing.T) {
+	conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, ReadinessPath: "/readyz"})
+
+	var dispatched int
+	conn.Command("ban", func(ctx ConnectionContext) {
+		dispatched++
+		ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/interactions", conn)
+	mux.Handle("/bot/", http.StripPrefix("/bot", conn))
+	mux.Handle("/readyz", conn)
+	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	// at Request moved to the path, like the requests the mux receives
+	at := func(r *http.Request, path string) *http.Request {
+		r.URL.Path, r.RequestURI = path, path
+		return r
+	}
+
+	unsigned := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(commandBody())))
+	}
+
+	tests := []struct {
+		name       string
+		req        func() *http.Request
+		status     int
+		dispatched bool
+		// closed Status once the connection is shut down
+		closed int
+	}{
+		{"interaction at the mount path", func() *http.Request { return at(sign(commandBody()), "/api/interactions") }, http.StatusOK, true,
+			http.StatusServiceUnavailable},
+		{"interaction under a stripped prefix", func() *http.Request { return at(sign(commandBody()), "/bot/interactions") }, http.StatusOK, true,
+			http.StatusServiceUnavailable},
+		{"unsigned interaction at the mount path", func() *http.Request { return at(unsigned(), "/api/interactions") }, http.StatusUnauthorized,
+			false, http.StatusServiceUnavailable},
+		{"interaction beside the mount path", func() *http.Request { return at(sign(commandBody()), "/api/interactions/ban") },
+			http.StatusNotFound, false, http.StatusNotFound},
+		{"readiness", func() *http.Request { return httptest.NewRequest(http.MethodGet, "/readyz", nil) }, http.StatusOK, false,
+			http.StatusServiceUnavailable},
+		{"other route of the mux", func() *http.Request { return httptest.NewRequest(http.MethodGet, "/api/health", nil) }, http.StatusNoContent,
+			false, http.StatusNoContent},
+	}
+
+	serve := func(t *testing.T, r *http.Request, status int, dispatches bool) {
+		t.Helper()
+
+		before := dispatched
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		if w.Code != status {
+			t.Errorf("status %d, want %d: %s", w.Code, status, w.Body)
+		}
+
+		if (dispatched > before) != dispatches {
+			t.Errorf("dispatched %v, want %v", dispatched > before, dispatches)
+		}
+
+		if dispatches && !strings.Contains(w.Body.String(), `"banned"`) {
+			t.Errorf("response %s, want the reply", w.Body)
+		}
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			serve(t, test.req(), test.status, test.dispatched)
+		})
+	}
+
+	if err := conn.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+" after Shutdown", func(t *testing.T) {
+			serve(t, test.req(), test.closed, false)
+		})
+	}
+}
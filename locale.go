@@ -1,7 +1,9 @@
 package httpcord
 
+import "sort"
+
 type (
-	Locale string
+	Locale     string
 	Dictionary map[Locale]string
 )
 
@@ -21,6 +23,7 @@ const (
 	GreekLocale        Locale = "el"
 	HindiLocale        Locale = "hi"
 	HungarianLocale    Locale = "hu"
+	IndonesianLocale   Locale = "id"
 	ItalianLocale      Locale = "it"
 	JapaneseLocale     Locale = "ja"
 	KoreanLocale       Locale = "ko"
@@ -31,9 +34,74 @@ const (
 	RomanianLocale     Locale = "ro"
 	RussianLocale      Locale = "ru"
 	SpanishESLocale    Locale = "es-ES"
+	SpanishLATAMLocale Locale = "es-419"
 	SwedishLocale      Locale = "sv-SE"
 	ThaiLocale         Locale = "th"
 	TurkishLocale      Locale = "tr"
 	UkrainianLocale    Locale = "uk"
 	VietnameseLocale   Locale = "vi"
 )
+
+// Get Localized value for the locale, or fallback when missing
+func (d Dictionary) Get(locale Locale, fallback string) string {
+	if value, ok := d[locale]; ok {
+		return value
+	}
+
+	return fallback
+}
+
+// knownLocales Locales supported by Discord
+var knownLocales = map[Locale]bool{
+	EnglishUSLocale:    true,
+	EnglishGBLocale:    true,
+	BulgarianLocale:    true,
+	ChineseCNLocale:    true,
+	ChineseTWLocale:    true,
+	CroatianLocale:     true,
+	CzechLocale:        true,
+	DanishLocale:       true,
+	DutchLocale:        true,
+	FinnishLocale:      true,
+	FrenchLocale:       true,
+	GermanLocale:       true,
+	GreekLocale:        true,
+	HindiLocale:        true,
+	HungarianLocale:    true,
+	IndonesianLocale:   true,
+	ItalianLocale:      true,
+	JapaneseLocale:     true,
+	KoreanLocale:       true,
+	LithuanianLocale:   true,
+	NorwegianLocale:    true,
+	PolishLocale:       true,
+	PortugueseBRLocale: true,
+	RomanianLocale:     true,
+	RussianLocale:      true,
+	SpanishESLocale:    true,
+	SpanishLATAMLocale: true,
+	SwedishLocale:      true,
+	ThaiLocale:         true,
+	TurkishLocale:      true,
+	UkrainianLocale:    true,
+	VietnameseLocale:   true,
+}
+
+// Known Whether Discord supports the locale, localizations of other locales are refused
+func (l Locale) Known() bool {
+	return knownLocales[l]
+}
+
+// sortedLocales Locales of the dictionary in a stable order
+func sortedLocales(d Dictionary) []Locale {
+	locales := make([]Locale, 0, len(d))
+	for locale := range d {
+		locales = append(locales, locale)
+	}
+
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i] < locales[j]
+	})
+
+	return locales
+}
@@ -0,0 +1,167 @@
+package httpcord
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LocalizedEmbed Embed whose title and description are Translator keys, empty keys keep the embed values
+type LocalizedEmbed struct {
+	Embed          *Embed
+	TitleKey       string
+	DescriptionKey string
+}
+
+// LocalizedCallbackData Reply built with Translator keys resolved in the interaction locale when sent.
+// {name} placeholders of the messages are replaced by Args, the fields of Data are sent untouched
+type LocalizedCallbackData struct {
+	Data       *InteractionCallbackData
+	ContentKey string
+	Args       map[string]interface{}
+	// Embeds are sent after the embeds of Data
+	Embeds []*LocalizedEmbed
+}
+
+// Locale Locale of the user, the guild locale when the interaction has none
+func (ctx *ConnectionContext) Locale() Locale {
+	if ctx.Interaction.Locale != "" {
+		return Locale(ctx.Interaction.Locale)
+	}
+
+	return Locale(ctx.Interaction.GuildLocale)
+}
+
+// GuildLocale Preferred locale of the guild, empty outside guilds
+func (ctx *ConnectionContext) GuildLocale() Locale {
+	return Locale(ctx.Interaction.GuildLocale)
+}
+
+// Localize Resolve the keys with ConnectionOptions.Translator in ctx.Locale(), missing keys are kept as they are and logged
+func (ctx *ConnectionContext) Localize(data *LocalizedCallbackData) *InteractionCallbackData {
+	resolved := &InteractionCallbackData{}
+	if data.Data != nil {
+		*resolved = *data.Data
+	}
+
+	if data.ContentKey != "" {
+		resolved.Content = ctx.translate(data.ContentKey, data.Args)
+	}
+
+	if len(data.Embeds) > 0 {
+		resolved.Embeds = append([]*Embed(nil), resolved.Embeds...)
+
+		for _, localized := range data.Embeds {
+			embed := &Embed{}
+			if localized.Embed != nil {
+				*embed = *localized.Embed
+			}
+
+			if localized.TitleKey != "" {
+				embed.Title = ctx.translate(localized.TitleKey, data.Args)
+			}
+
+			if localized.DescriptionKey != "" {
+				embed.Description = ctx.translate(localized.DescriptionKey, data.Args)
+			}
+
+			resolved.Embeds = append(resolved.Embeds, embed)
+		}
+	}
+
+	return resolved
+}
+
+// LocaleChain Locales the translations are looked up in, see ConnectionOptions.LocaleChain
+func (ctx *ConnectionContext) LocaleChain() []Locale {
+	if ctx.options != nil && ctx.options.LocaleChain != nil {
+		return ctx.options.LocaleChain(ctx)
+	}
+
+	var chain []Locale
+	for _, locale := range []Locale{Locale(ctx.Interaction.Locale), ctx.GuildLocale()} {
+		if locale != "" {
+			chain = append(chain, locale)
+		}
+	}
+
+	return chain
+}
+
+// T Message of the key from ConnectionOptions.Translator in the first locale of LocaleChain providing it, formatted
+// like Translator.T: ctx.T("greeting", map[string]interface{}{"name": user.Username}) replaces {name}. Missing keys
+// are returned as they are and logged
+func (ctx *ConnectionContext) T(key string, args ...interface{}) string {
+	var (
+		message string
+		ok      bool
+	)
+
+	if ctx.options != nil && ctx.options.Translator != nil {
+		message, ok = ctx.options.Translator.lookupChain(key, ctx.LocaleChain())
+	}
+
+	if !ok {
+		ctx.missingTranslation(key)
+		return key
+	}
+
+	return formatMessage(message, args)
+}
+
+// TPlural Plural form of the key for n like T, with the plural rule of the locale providing it
+func (ctx *ConnectionContext) TPlural(key string, n int, args ...interface{}) string {
+	var (
+		message string
+		ok      bool
+	)
+
+	if ctx.options != nil && ctx.options.Translator != nil {
+		message, ok = ctx.options.Translator.pluralChain(key, n, ctx.LocaleChain())
+	}
+
+	if !ok {
+		ctx.missingTranslation(key)
+		return key
+	}
+
+	return formatMessage(message, args)
+}
+
+func (ctx *ConnectionContext) missingTranslation(key string) {
+	if ctx.options != nil {
+		ctx.options.Logger.Warn("missing translation", "key", key, "locale", ctx.Locale())
+	}
+}
+
+func (ctx *ConnectionContext) translate(key string, args map[string]interface{}) string {
+	return ctx.T(key, args)
+}
+
+// replaceArgs Replace the {name} placeholders, unknown placeholders are kept
+func replaceArgs(message string, args map[string]interface{}) string {
+	if len(args) == 0 {
+		return message
+	}
+
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// ReplyLocalized Reply with the keys resolved in the interaction locale
+func (ctx *ConnectionContext) ReplyLocalized(data *LocalizedCallbackData) {
+	ctx.ReplyInteraction(ctx.Localize(data))
+}
+
+// EditReplyLocalized Edit the original response with the keys resolved in the interaction locale
+func (ctx *ConnectionContext) EditReplyLocalized(data *LocalizedCallbackData, opts ...EditOption) (*Message, error) {
+	return ctx.EditReply(ctx.Localize(data).WebhookEdit(), opts...)
+}
+
+// FollowUpLocalized Send a follow-up with the keys resolved in the interaction locale
+func (ctx *ConnectionContext) FollowUpLocalized(data *LocalizedCallbackData) (*Message, error) {
+	return ctx.FollowUp(ctx.Localize(data).WebhookEdit())
+}
@@ -0,0 +1,106 @@
+package httpcord
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLocalizedCallbackData(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale).
+		Add("greeting", Dictionary{EnglishUSLocale: "Hello {name}", FrenchLocale: "Bonjour {name}", GermanLocale: "Hallo {name}"}).
+		Add("title", Dictionary{EnglishUSLocale: "Profile", FrenchLocale: "Profil"}).
+		Add("only_german", Dictionary{GermanLocale: "Nur Deutsch"})
+
+	tests := []struct {
+		name        string
+		translator  *Translator
+		chain       func(ctx *ConnectionContext) []Locale
+		locale      Locale
+		guildLocale Locale
+		key         string
+		content     string
+		// missing The key is logged as a missing translation
+		missing bool
+	}{
+		{"user locale", translator, nil, FrenchLocale, GermanLocale, "greeting", "Bonjour Ada", false},
+		{"guild locale when the user locale is missing", translator, nil, JapaneseLocale, GermanLocale, "greeting", "Hallo Ada", false},
+		{"guild locale without a user locale", translator, nil, "", FrenchLocale, "greeting", "Bonjour Ada", false},
+		{"fallback locale", translator, nil, JapaneseLocale, KoreanLocale, "greeting", "Hello Ada", false},
+		{"fallback locale outside guilds", translator, nil, JapaneseLocale, "", "greeting", "Hello Ada", false},
+		{"only in the guild locale", translator, nil, FrenchLocale, GermanLocale, "only_german", "Nur Deutsch", false},
+		{"missing in the chain and the fallback", translator, nil, FrenchLocale, "", "only_german", "only_german", true},
+		{"unknown key", translator, nil, FrenchLocale, GermanLocale, "farewell", "farewell", true},
+		{"locale chain of the options", translator, func(ctx *ConnectionContext) []Locale {
+			return []Locale{ctx.GuildLocale(), Locale(ctx.Interaction.Locale)}
+		}, FrenchLocale, GermanLocale, "greeting", "Hallo Ada", false},
+		{"empty locale chain", translator, func(ctx *ConnectionContext) []Locale { return nil }, FrenchLocale, GermanLocale, "greeting", "Hello Ada", false},
+		{"no translator", nil, nil, FrenchLocale, GermanLocale, "greeting", "greeting", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Locale: string(test.locale), GuildLocale: string(test.guildLocale)}
+			ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{
+				Translator:  test.translator,
+				LocaleChain: test.chain,
+				Logger:      NewLogger(&logs, LogLevelDebug),
+			}})
+			defer finish()
+
+			data := ctx.Localize(&LocalizedCallbackData{ContentKey: test.key, Args: map[string]interface{}{"name": "Ada"}})
+			if data.Content != test.content {
+				t.Errorf("content %q, want %q", data.Content, test.content)
+			}
+
+			if missing := strings.Contains(logs.String(), "missing translation"); missing != test.missing {
+				t.Errorf("missing translation logged = %v, want %v: %s", missing, test.missing, logs.String())
+			}
+		})
+	}
+}
+
+func TestLocalizeKeepsData(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale).
+		Add("greeting", Dictionary{FrenchLocale: "Bonjour {name}"}).
+		Add("title", Dictionary{FrenchLocale: "Profil de {name}"})
+
+	interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Locale: string(FrenchLocale)}
+	ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{Translator: translator, Logger: NopLogger}})
+	defer finish()
+
+	banner := &Embed{Title: "Banner"}
+	data := &InteractionCallbackData{Content: "untranslated", Flags: EphemeralMessageFlag, Embeds: []*Embed{banner}}
+
+	localized := ctx.Localize(&LocalizedCallbackData{
+		Data: data,
+		Args: map[string]interface{}{"name": "Ada"},
+		Embeds: []*LocalizedEmbed{
+			{Embed: &Embed{Title: "kept", Description: "Description"}, TitleKey: "title"},
+			{DescriptionKey: "greeting"},
+		},
+	})
+
+	if localized.Content != "untranslated" || localized.Flags != EphemeralMessageFlag {
+		t.Errorf("Localize() = %+v, want the content and flags of Data without a ContentKey", localized)
+	}
+
+	if len(localized.Embeds) != 3 || localized.Embeds[0] != banner {
+		t.Fatalf("embeds %+v, want the banner then the 2 localized embeds", localized.Embeds)
+	}
+
+	if embed := localized.Embeds[1]; embed.Title != "Profil de Ada" || embed.Description != "Description" {
+		t.Errorf("first localized embed %+v, want the translated title and the kept description", embed)
+	}
+
+	if embed := localized.Embeds[2]; embed.Title != "" || embed.Description != "Bonjour Ada" {
+		t.Errorf("second localized embed %+v, want only the translated description", embed)
+	}
+
+	// Data is copied, the caller can reuse it for the other locales
+	if data.Content != "untranslated" || len(data.Embeds) != 1 {
+		t.Errorf("Data changed to %+v", data)
+	}
+}
@@ -0,0 +1,92 @@
+package httpcord
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Logger Receive the library logs, fields are alternating key and value pairs
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+// LogLevel Lowest level a logger writes
+type LogLevel int
+
+// Log Levels
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+type stdLogger struct {
+	*log.Logger
+	level LogLevel
+}
+
+// NewLogger Logger writing the logs of the level and above to w with the standard log package
+func NewLogger(w io.Writer, level LogLevel) Logger {
+	return stdLogger{Logger: log.New(w, "httpcord ", log.LstdFlags), level: level}
+}
+
+// DefaultLogger Logger writing warnings and errors to stderr
+var DefaultLogger = NewLogger(os.Stderr, LogLevelWarn)
+
+// NopLogger Logger discarding everything
+var NopLogger Logger = nopLogger{}
+
+func (l stdLogger) Debug(msg string, fields ...interface{}) {
+	l.write(LogLevelDebug, "DEBUG ", msg, fields)
+}
+
+func (l stdLogger) Info(msg string, fields ...interface{}) {
+	l.write(LogLevelInfo, "INFO ", msg, fields)
+}
+
+func (l stdLogger) Warn(msg string, fields ...interface{}) {
+	l.write(LogLevelWarn, "WARN ", msg, fields)
+}
+
+func (l stdLogger) Error(msg string, fields ...interface{}) {
+	l.write(LogLevelError, "ERROR ", msg, fields)
+}
+
+func (l stdLogger) write(level LogLevel, prefix, msg string, fields []interface{}) {
+	if level >= l.level {
+		l.Print(prefix + formatLog(msg, fields))
+	}
+}
+
+func formatLog(msg string, fields []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 < len(fields) {
+			fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", fields[i])
+		}
+	}
+
+	return b.String()
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+
+func (nopLogger) Info(string, ...interface{}) {}
+
+func (nopLogger) Warn(string, ...interface{}) {}
+
+func (nopLogger) Error(string, ...interface{}) {}
@@ -0,0 +1,114 @@
+package httpcord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type MalformedKind int
+
+// Malformed Kinds
+
+const (
+	// EmptyBodyMalformed The body is empty or only whitespace, refused with a 400
+	EmptyBodyMalformed MalformedKind = iota + 1
+	// InvalidJSONMalformed The body is not a JSON object, like form values or truncated JSON, refused with a 400
+	InvalidJSONMalformed
+	// MissingTypeMalformed The JSON object has no type field, refused with a 422
+	MissingTypeMalformed
+	// TypeKindMalformed The type field is not an integer, like "2", 2.5 or null, refused with a 422
+	TypeKindMalformed
+	// UnknownTypeMalformed The type field is not an InteractionType, refused with a 422
+	UnknownTypeMalformed
+	// MissingDataMalformed An interaction other than a ping has no data object, refused with a 422
+	MissingDataMalformed
+)
+
+func (k MalformedKind) String() string {
+	switch k {
+	case EmptyBodyMalformed:
+		return "empty body"
+	case InvalidJSONMalformed:
+		return "invalid json"
+	case MissingTypeMalformed:
+		return "missing type"
+	case TypeKindMalformed:
+		return "type not an integer"
+	case UnknownTypeMalformed:
+		return "unknown type"
+	case MissingDataMalformed:
+		return "missing data"
+	default:
+		return "invalid payload"
+	}
+}
+
+// Status Status code answered to the request
+func (e *MalformedInteractionError) Status() int {
+	switch e.Kind {
+	case MissingTypeMalformed, TypeKindMalformed, UnknownTypeMalformed, MissingDataMalformed:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// checkInteractionBody Refuse the bodies that are not an interaction payload before decoding them, the signature is
+// already verified
+func checkInteractionBody(body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &MalformedInteractionError{Kind: EmptyBodyMalformed, Err: errors.New("empty body")}
+	}
+
+	var fields map[string]json.RawMessage
+	if !json.Valid(body) {
+		return &MalformedInteractionError{Kind: InvalidJSONMalformed, Err: errors.New("body is not valid JSON")}
+	}
+
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return &MalformedInteractionError{Kind: InvalidJSONMalformed, Err: errors.New("body is not a JSON object")}
+	}
+
+	rawType, ok := fields["type"]
+	if !ok {
+		return &MalformedInteractionError{Kind: MissingTypeMalformed, Err: errors.New("missing type")}
+	}
+
+	interactionType, err := strconv.ParseInt(string(rawType), 10, 64)
+	if err != nil {
+		return &MalformedInteractionError{Kind: TypeKindMalformed, Err: errors.New("type " + string(rawType) + " is not an integer")}
+	}
+
+	if interactionType < int64(PingInteraction) || interactionType > int64(ModalSubmitInteraction) {
+		return &MalformedInteractionError{Kind: UnknownTypeMalformed, Err: errors.New("unknown type " + string(rawType))}
+	}
+
+	if InteractionType(interactionType) == PingInteraction {
+		return nil
+	}
+
+	if data := bytes.TrimSpace(fields["data"]); len(data) == 0 || data[0] != '{' {
+		return &MalformedInteractionError{Kind: MissingDataMalformed, Err: errors.New("missing data object")}
+	}
+
+	return nil
+}
+
+// malformedRequest Refuse the request with the status of the MalformedInteractionError, 400 for the other errors,
+// and log it once without dispatching
+func (o *ConnectionOptions) malformedRequest(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadRequest
+	kind := MalformedKind(0)
+
+	var malformed *MalformedInteractionError
+	if errors.As(err, &malformed) {
+		status = malformed.Status()
+		kind = malformed.Kind
+	}
+
+	o.Logger.Warn("malformed interaction refused", "reason", kind.String(), "status", status, "remote", r.RemoteAddr, "error", err.Error())
+	o.requestFailed(w, r, status, err)
+}
@@ -0,0 +1,74 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMalformedInteractions(t *testing.T) {
+	nested := func(depth int) string {
+		options := `{"type":3,"name":"reason","value":"deep"}`
+		for i := 0; i < depth; i++ {
+			options = fmt.Sprintf(`{"type":2,"name":"group","options":[%s]}`, options)
+		}
+
+		return fmt.Sprintf(`{"id":"5","name":"config","type":1,"options":[%s]}`, options)
+	}
+
+	command := func(data string) []byte {
+		return interactionBody(ApplicationCommandInteraction, data)
+	}
+
+	reason := command(`{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"spam"}]}`)
+
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+		body    []byte
+		status  int
+		// ran The handler runs, the others are refused before the dispatch
+		ran bool
+	}{
+		{"truncated JSON", ConnectionOptions{}, reason[:60], http.StatusBadRequest, false},
+		{"oversized body", ConnectionOptions{MaxBodySize: 512}, command(`{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"` +
+			strings.Repeat("a", 1024) + `"}]}`), http.StatusRequestEntityTooLarge, false},
+		{"option value of the wrong type", ConnectionOptions{}, command(`{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":{"a":1}}]}`),
+			http.StatusOK, true},
+		{"options of the wrong type", ConnectionOptions{}, command(`{"id":"5","name":"ban","type":1,"options":"reason"}`), http.StatusBadRequest, false},
+		{"type of the wrong type", ConnectionOptions{}, command(`{"id":"5","name":"ban","type":"chat","options":[]}`), http.StatusBadRequest, false},
+		{"deeply nested options", ConnectionOptions{}, command(nested(100)), http.StatusOK, true},
+		{"nesting over the JSON depth", ConnectionOptions{}, command(nested(5000)), http.StatusBadRequest, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := test.options
+			options.Logger = NopLogger
+
+			conn, sign := signedConnection(t, options)
+
+			var ran bool
+			handler := func(ctx ConnectionContext) {
+				ran = true
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"})
+			}
+
+			conn.Command("ban", handler)
+			conn.Command("config group", handler)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			if w.Code != test.status {
+				t.Fatalf("status %d, want %d: %.200s", w.Code, test.status, w.Body)
+			}
+
+			if ran != test.ran {
+				t.Errorf("handler ran = %v, want %v", ran, test.ran)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package httpcord
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	"httpcord/permissions"
@@ -17,10 +19,25 @@ type Member struct {
 	Mute                       bool                      `json:"mute"`
 	Pending                    bool                      `json:"pending,omitempty"`
 	Permissions                permissions.PermissionBit `json:"permissions,omitempty"`
-	CommunicationDisabledUntil Time                      `json:"communication_disabled_until,omitempty"`
+	CommunicationDisabledUntil *Time                     `json:"communication_disabled_until,omitempty"`
 }
 
+// ResolveMember Panics on invalid permission bits
 func ResolveMember(member *APIMember) *Member {
+	resolved, err := resolveMember(member)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return resolved
+}
+
+func resolveMember(member *APIMember) (*Member, error) {
+	if member == nil || member.User == nil {
+		return nil, &MalformedInteractionError{Err: errors.New("missing member user")}
+	}
+
 	resolved := &Member{
 		Nick:                       member.Nick,
 		Avatar:                     member.Avatar,
@@ -29,18 +46,18 @@ func ResolveMember(member *APIMember) *Member {
 		Deaf:                       member.Deaf,
 		Mute:                       member.Mute,
 		Pending:                    member.Pending,
-		CommunicationDisabledUntil: member.CommunicationDisabledUntil,
+		CommunicationDisabledUntil: optionalTime(member.CommunicationDisabledUntil),
 		Roles:                      StringArrayToSnowflakeArray(member.Roles),
 	}
 
 	perms, err := strconv.ParseUint(member.Permissions, 10, 64)
 
 	if err != nil {
-		panic("Invalid permissions bits")
+		return nil, &MalformedInteractionError{Err: fmt.Errorf("invalid member permissions: %w", err)}
 	}
 
 	resolved.Permissions = permissions.PermissionBit(perms)
 	resolved.User = ResolveUser(member.User)
 
-	return resolved
+	return resolved, nil
 }
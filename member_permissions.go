@@ -0,0 +1,38 @@
+package httpcord
+
+import "httpcord/permissions"
+
+// InGuild Whether the interaction comes from a guild, Member is set
+func (ctx *ConnectionContext) InGuild() bool {
+	return ctx.Interaction.GuildID != ""
+}
+
+// InDM Whether the interaction comes from a DM or a group DM, there is no Member and no guild permission
+func (ctx *ConnectionContext) InDM() bool {
+	return !ctx.InGuild()
+}
+
+// User User of the interaction, the member user in guilds
+func (ctx *ConnectionContext) User() *User {
+	if ctx.Interaction.User == nil && ctx.Interaction.Member != nil {
+		return ctx.Interaction.Member.User
+	}
+
+	return ctx.Interaction.User
+}
+
+// MemberPermissions Permissions of the member in the interaction channel, overwrites included, false outside guilds
+func (ctx *ConnectionContext) MemberPermissions() (permissions.PermissionBit, bool) {
+	if !ctx.InGuild() || ctx.Interaction.Member == nil {
+		return 0, false
+	}
+
+	return ctx.Interaction.Member.Permissions, true
+}
+
+// HasPermission Whether the member has all the bits in the interaction channel, always true for administrators and
+// always false outside guilds
+func (ctx *ConnectionContext) HasPermission(bits permissions.PermissionBit) bool {
+	granted, ok := ctx.MemberPermissions()
+	return ok && granted.Has(bits, true)
+}
@@ -0,0 +1,64 @@
+package httpcord
+
+import (
+	"fmt"
+	"testing"
+
+	"httpcord/permissions"
+)
+
+// memberBody Guild interaction of a member with the permissions
+func memberBody(granted permissions.PermissionBit) []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":2,"token":"token","version":1,"guild_id":"2","channel_id":"3",`+
+		`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"%d"},"data":{"id":"5","name":"ban","type":1}}`,
+		nowSnowflake(), uint64(granted)))
+}
+
+func TestMemberPermissions(t *testing.T) {
+	dm := inDM(memberBody(permissions.Administrator))
+	groupDM := []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":2,"token":"token","version":1,"channel_id":"3","context":2,`+
+		`"user":{"id":"4","username":"mod"},"data":{"id":"5","name":"ban","type":1}}`, nowSnowflake()))
+
+	tests := []struct {
+		name    string
+		body    []byte
+		bits    permissions.PermissionBit
+		inGuild bool
+		has     bool
+	}{
+		{"granted bit", memberBody(permissions.BanMembers | permissions.SendMessages), permissions.BanMembers, true, true},
+		{"all the granted bits", memberBody(permissions.BanMembers | permissions.KickMembers), permissions.BanMembers | permissions.KickMembers,
+			true, true},
+		{"one of the bits missing", memberBody(permissions.BanMembers), permissions.BanMembers | permissions.KickMembers, true, false},
+		{"missing bit", memberBody(permissions.SendMessages), permissions.ManageGuild, true, false},
+		{"no permission", memberBody(0), permissions.SendMessages, true, false},
+		{"administrator", memberBody(permissions.Administrator), permissions.ManageGuild | permissions.ModerateMembers, true, true},
+		{"bit over 32", memberBody(permissions.ModerateMembers), permissions.ModerateMembers, true, true},
+		{"no bit asked", memberBody(0), 0, true, true},
+		{"DM", dm, permissions.SendMessages, false, false},
+		{"no bit asked in DM", dm, 0, false, false},
+		{"group DM", groupDM, permissions.SendMessages, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := bodyContext(t, test.body)
+
+			if ctx.InGuild() != test.inGuild || ctx.InDM() == test.inGuild {
+				t.Errorf("InGuild() = %v, InDM() = %v, want %v, %v", ctx.InGuild(), ctx.InDM(), test.inGuild, !test.inGuild)
+			}
+
+			if got := ctx.HasPermission(test.bits); got != test.has {
+				t.Errorf("HasPermission(%d) = %v, want %v", test.bits, got, test.has)
+			}
+
+			if _, ok := ctx.MemberPermissions(); ok != test.inGuild {
+				t.Errorf("MemberPermissions() found %v, want %v", ok, test.inGuild)
+			}
+
+			if user := ctx.User(); user == nil || user.ID != "4" {
+				t.Errorf("User() = %+v, want the user 4 of the member or the DM", user)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package httpcord
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type EditOption func(o *editOptions)
+
+type editOptions struct {
+	force bool
+}
+
+// ForceEdit Send the edit even when the WebhookEditMemo has the same payload
+func ForceEdit() EditOption {
+	return func(o *editOptions) {
+		o.force = true
+	}
+}
+
+type webhookEditMemoEntry struct {
+	hash      [sha256.Size]byte
+	message   *Message
+	expiresAt time.Time
+}
+
+// WebhookEditMemo Remember the last edit sent per interaction token so identical edits skip the API call, see RestClient.EditMemo.
+// Entries live as long as the interaction token
+type WebhookEditMemo struct {
+	mu        sync.Mutex
+	entries   map[string]webhookEditMemoEntry
+	lastSweep time.Time
+}
+
+func NewWebhookEditMemo() *WebhookEditMemo {
+	return &WebhookEditMemo{entries: make(map[string]webhookEditMemoEntry)}
+}
+
+// hashWebhookEdit Hash of the payload, files are identified by their declared metadata and not their content
+func hashWebhookEdit(data *WebhookEdit) ([sha256.Size]byte, error) {
+	h := sha256.New()
+
+	if err := json.NewEncoder(h).Encode(data); err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	for _, file := range data.Files {
+		h.Write([]byte(file.Filename + "\x00" + file.Description + "\x00" + file.ContentType + "\x00" + strconv.FormatBool(file.Spoiler) + "\x00"))
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+func (m *WebhookEditMemo) lookup(key string, hash [sha256.Size]byte) (*Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || entry.hash != hash || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.message, true
+}
+
+func (m *WebhookEditMemo) store(key string, hash [sha256.Size]byte, message *Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(m.lastSweep) > time.Minute {
+		for k, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+
+		m.lastSweep = now
+	}
+
+	m.entries[key] = webhookEditMemoEntry{hash: hash, message: message, expiresAt: now.Add(InteractionTokenLifetime)}
+}
+
+func (m *WebhookEditMemo) forget(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
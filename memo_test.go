@@ -0,0 +1,113 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWebhookEditMemo(t *testing.T) {
+	file := func(name, content string) []*DiscordFile {
+		return []*DiscordFile{{Buffer: bytes.NewBufferString(content), Filename: name}}
+	}
+
+	// edit One call of the sequence, deleting the original response when data is nil
+	type edit struct {
+		token string
+		data  *WebhookEdit
+		opts  []EditOption
+	}
+
+	tests := []struct {
+		name  string
+		memo  bool
+		edits []edit
+		// calls HTTP calls made by the edits
+		calls int32
+	}{
+		{"identical edit skipped", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", &WebhookEdit{Content: "50%"}, nil}}, 1},
+		{"changed edit sent", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", &WebhookEdit{Content: "60%"}, nil}}, 2},
+		{"back to a previous edit sent", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", &WebhookEdit{Content: "60%"}, nil},
+			{"a", &WebhookEdit{Content: "50%"}, nil}}, 3},
+		{"forced", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", &WebhookEdit{Content: "50%"}, []EditOption{ForceEdit()}}}, 2},
+		{"other token", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"b", &WebhookEdit{Content: "50%"}, nil}}, 2},
+		{"files by identity", true, []edit{{"a", &WebhookEdit{Files: file("chart.png", "v1")}, nil}, {"a", &WebhookEdit{Files: file("chart.png", "v2")}, nil}}, 1},
+		{"renamed file", true, []edit{{"a", &WebhookEdit{Files: file("chart.png", "v1")}, nil}, {"a", &WebhookEdit{Files: file("chart2.png", "v1")}, nil}}, 2},
+		{"forgotten on delete", true, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", nil, nil}, {"a", &WebhookEdit{Content: "50%"}, nil}}, 3},
+		{"failed edits not remembered", true, []edit{{"refused", &WebhookEdit{Content: "50%"}, nil}, {"refused", &WebhookEdit{Content: "50%"}, nil}}, 2},
+		{"disabled", false, []edit{{"a", &WebhookEdit{Content: "50%"}, nil}, {"a", &WebhookEdit{Content: "50%"}, nil}}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.URL.Path == "/webhooks/1/refused/messages/@original" {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"code":10015,"message":"Unknown Webhook"}`))
+					return
+				}
+
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				w.Write([]byte(`{"id":"` + strconv.Itoa(int(n)) + `"}`))
+			}))
+			defer server.Close()
+
+			client := NewRestClient(StaticToken("token"))
+			client.BaseURL = server.URL
+			client.MaxRetries = 0
+
+			if test.memo {
+				client.EditMemo = NewWebhookEditMemo()
+			}
+
+			var last *Message
+			for i, e := range test.edits {
+				if e.data == nil {
+					if err := client.DeleteOriginalInteractionResponse(context.Background(), "1", e.token); err != nil {
+						t.Fatal(err)
+					}
+
+					continue
+				}
+
+				message, err := client.EditOriginalInteractionResponse(context.Background(), "1", e.token, e.data, e.opts...)
+				if e.token == "refused" {
+					if err == nil {
+						t.Errorf("edit %d succeeded, want Unknown Webhook", i)
+					}
+
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("edit %d: %v", i, err)
+				}
+
+				last = message
+			}
+
+			if got := atomic.LoadInt32(&calls); got != test.calls {
+				t.Errorf("%d HTTP calls, want %d", got, test.calls)
+			}
+
+			// The skipped edits return the message of the last edit sent
+			if last != nil && last.ID != Snowflake(strconv.Itoa(int(test.calls))) {
+				t.Errorf("last edit returned message %s, want the one of call %d", last.ID, test.calls)
+			}
+		})
+	}
+}
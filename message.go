@@ -1,9 +1,10 @@
 package httpcord
 
 type (
-	MessageFlag         uint
-	MessageActivityType uint
-	MessageType         uint
+	MessageFlag          uint
+	MessageActivityType  uint
+	MessageType          uint
+	MessageReferenceType uint
 )
 
 type ChannelMention struct {
@@ -47,9 +48,12 @@ type MessageActivity struct {
 }
 
 type MessageReference struct {
-	MessageID Snowflake `json:"message_id,omitempty"`
-	ChannelID Snowflake `json:"channel_id,omitempty"`
-	GuildID   Snowflake `json:"guild_id,omitempty"`
+	Type      MessageReferenceType `json:"type,omitempty"`
+	MessageID Snowflake            `json:"message_id,omitempty"`
+	ChannelID Snowflake            `json:"channel_id,omitempty"`
+	GuildID   Snowflake            `json:"guild_id,omitempty"`
+	// FailIfNotExists Refuse the message when the referenced one was deleted, Discord defaults to true when not sent
+	FailIfNotExists bool `json:"fail_if_not_exists"`
 }
 
 type Message struct {
@@ -81,7 +85,78 @@ type Message struct {
 	ReferencedMessage *Message            `json:"referenced_message,omitempty"`
 	Interaction       *MessageInteraction `json:"interaction,omitempty"`
 	Thread            *Channel            `json:"thread,omitempty"`
-	Components        []*AnyComponent        `json:"components,omitempty"`
+	Components        []*AnyComponent     `json:"components,omitempty"`
 	StickerItems      []*StickerItem      `json:"sticker_items"`
 	Stickers          []*Sticker          `json:"stickers,omitempty"`
+	MessageSnapshots  []*MessageSnapshot  `json:"message_snapshots,omitempty"`
+	Poll              *Poll               `json:"poll,omitempty"`
+}
+
+// MessageSnapshot Copy of a forwarded message
+type MessageSnapshot struct {
+	Message *SnapshotMessage `json:"message"`
+}
+
+// SnapshotMessage Partial message of a MessageSnapshot, the author and the IDs are not included
+type SnapshotMessage struct {
+	Type            MessageType     `json:"type"`
+	Content         string          `json:"content"`
+	Embeds          []*Embed        `json:"embeds"`
+	Attachments     []*Attachment   `json:"attachments,omitempty"`
+	Timestamp       Time            `json:"timestamp"`
+	EditedTimestamp Time            `json:"edited_timestamp"`
+	Flags           MessageFlag     `json:"flags,omitempty"`
+	Mentions        []*User         `json:"mentions,omitempty"`
+	MentionRoles    []Snowflake     `json:"mention_roles,omitempty"`
+	StickerItems    []*StickerItem  `json:"sticker_items,omitempty"`
+	Components      []*AnyComponent `json:"components,omitempty"`
+}
+
+// IsForward Whether the message forwards another one, see MessageSnapshots
+func (m *Message) IsForward() bool {
+	return (m.MessageReference != nil && m.MessageReference.Type == ForwardMessageReference) || len(m.MessageSnapshots) > 0
+}
+
+// EffectiveContent Content of the message, the content of the first snapshot for forwards
+func (m *Message) EffectiveContent() string {
+	if m.Content != "" || len(m.MessageSnapshots) == 0 || m.MessageSnapshots[0].Message == nil {
+		return m.Content
+	}
+
+	return m.MessageSnapshots[0].Message.Content
+}
+
+// Message Reference Types
+
+const (
+	DefaultMessageReference MessageReferenceType = iota
+	ForwardMessageReference
+)
+
+// Message Flags
+
+const (
+	CrosspostedMessageFlag MessageFlag = 1 << iota
+	IsCrosspostMessageFlag
+	// SuppressEmbedsMessageFlag Do not include the embeds of the links in the content
+	SuppressEmbedsMessageFlag
+	SourceMessageDeletedMessageFlag
+	UrgentMessageFlag
+	HasThreadMessageFlag
+	// EphemeralMessageFlag Only the user of the interaction sees the message
+	EphemeralMessageFlag
+	// LoadingMessageFlag The deferred response is still "thinking"
+	LoadingMessageFlag
+	FailedToMentionSomeRolesInThreadMessageFlag
+	_
+	_
+	_
+	// SuppressNotificationsMessageFlag Send the message without push and desktop notifications
+	SuppressNotificationsMessageFlag
+	IsVoiceMessageMessageFlag
+)
+
+// Has Whether every flag of flags is set
+func (f MessageFlag) Has(flags MessageFlag) bool {
+	return f&flags == flags
 }
@@ -0,0 +1,115 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+
+	"httpcord/endpoints"
+)
+
+// MessageEdit Fields of the channel message to update, nil fields are left unchanged
+type MessageEdit struct {
+	Content         *string          `json:"content,omitempty"`
+	Embeds          *[]*Embed        `json:"embeds,omitempty"`
+	Flags           *MessageFlag     `json:"flags,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Components      *[]AnyComponent  `json:"components,omitempty"`
+	Files           []*DiscordFile   `json:"-"`
+	// Attachments are the existing attachments kept by the edit, the edit removes the others when set. The
+	// attachments of Files are added when sent
+	Attachments []*Attachment `json:"attachments,omitempty"`
+}
+
+// withAttachments Copy listing the attachments of the files, the data itself when there are no files
+func (d *MessageEdit) withAttachments() *MessageEdit {
+	if len(d.Files) == 0 {
+		return d
+	}
+
+	data := *d
+	data.Attachments = listAttachments(d.Attachments, d.Files)
+	return &data
+}
+
+// ThreadCreate Thread started from a message by StartThreadFromMessage
+type ThreadCreate struct {
+	Name string `json:"name"`
+	// AutoArchiveDuration is the minutes of inactivity before the thread is archived: 60, 1440, 4320 or 10080
+	// (Defaults to the default_auto_archive_duration of the channel)
+	AutoArchiveDuration int  `json:"auto_archive_duration,omitempty"`
+	RateLimitPerUser    *int `json:"rate_limit_per_user,omitempty"`
+}
+
+// GetMessage Fetch a message of the channel, reading the content of other users needs the MESSAGE_CONTENT intent
+func (c *RestClient) GetMessage(ctx context.Context, channelID, messageID Snowflake) (*Message, error) {
+	var message Message
+	if err := c.Do(ctx, http.MethodGet, endpoints.Message(channelID.String(), messageID.String()), nil, &message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// EditMessage Update a message of the channel, the other users' messages only accept a change of Flags. Returns
+// ErrNilPayload when data is nil
+func (c *RestClient) EditMessage(ctx context.Context, channelID, messageID Snowflake, data *MessageEdit) (*Message, error) {
+	if data == nil {
+		return nil, ErrNilPayload
+	}
+
+	var message Message
+	err := c.Do(ctx, http.MethodPatch, endpoints.Message(channelID.String(), messageID.String()), data.withAttachments(), &message,
+		WithFiles(data.Files...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// DeleteMessage Delete a message of the channel, reason goes to the audit log when not empty
+func (c *RestClient) DeleteMessage(ctx context.Context, channelID, messageID Snowflake, reason string) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.Message(channelID.String(), messageID.String()), nil, nil, WithReason(reason))
+}
+
+// StartThreadFromMessage Create a thread attached to the message, reason goes to the audit log when not empty.
+// Messages of announcement channels start announcement threads, the others public threads
+func (c *RestClient) StartThreadFromMessage(ctx context.Context, channelID, messageID Snowflake, data *ThreadCreate, reason string) (*Channel, error) {
+	var thread Channel
+	err := c.Do(ctx, http.MethodPost, endpoints.MessageThreads(channelID.String(), messageID.String()), data, &thread, WithReason(reason))
+	if err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+// PinMessage Pin the message in the channel, reason goes to the audit log when not empty
+func (c *RestClient) PinMessage(ctx context.Context, channelID, messageID Snowflake, reason string) error {
+	return c.Do(ctx, http.MethodPut, endpoints.ChannelPin(channelID.String(), messageID.String()), nil, nil, WithReason(reason))
+}
+
+// UnpinMessage Unpin the message in the channel, reason goes to the audit log when not empty
+func (c *RestClient) UnpinMessage(ctx context.Context, channelID, messageID Snowflake, reason string) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.ChannelPin(channelID.String(), messageID.String()), nil, nil, WithReason(reason))
+}
+
+// CrosspostMessage Publish the message of an announcement channel to the following channels
+func (c *RestClient) CrosspostMessage(ctx context.Context, channelID, messageID Snowflake) (*Message, error) {
+	var message Message
+	if err := c.Do(ctx, http.MethodPost, endpoints.Crosspost(channelID.String(), messageID.String()), nil, &message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// CreateReaction React to the message as the application
+func (c *RestClient) CreateReaction(ctx context.Context, channelID, messageID Snowflake, emoji Emoji) error {
+	return c.Do(ctx, http.MethodPut, endpoints.UserReaction(channelID.String(), messageID.String(), emoji.URLEncoded(), "@me"), nil, nil)
+}
+
+// DeleteOwnReaction Remove the reaction of the application from the message
+func (c *RestClient) DeleteOwnReaction(ctx context.Context, channelID, messageID Snowflake, emoji Emoji) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.UserReaction(channelID.String(), messageID.String(), emoji.URLEncoded(), "@me"), nil, nil)
+}
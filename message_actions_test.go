@@ -0,0 +1,80 @@
+package httpcord_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestMessageActionRoutes(t *testing.T) {
+	thumbsUp := httpcord.Emoji{Name: "👍"}
+	heart := httpcord.Emoji{Name: "\u2764\ufe0f"}
+	blob := httpcord.Emoji{ID: "41771983429993937", Name: "blobdance", Animated: true}
+
+	tests := []struct {
+		name   string
+		call   func(ctx context.Context, c *httpcord.RestClient) error
+		method string
+		// path Decoded path of the request, the emoji of the reaction routes are percent-encoded once on the wire
+		path   string
+		reason string
+	}{
+		{"pin", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.PinMessage(ctx, "3", "7", "pinned by /pin")
+		}, http.MethodPut, "/channels/3/pins/7", "pinned by /pin"},
+		{"pin without a reason", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.PinMessage(ctx, "3", "7", "")
+		}, http.MethodPut, "/channels/3/pins/7", ""},
+		{"unpin", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.UnpinMessage(ctx, "3", "7", "outdated")
+		}, http.MethodDelete, "/channels/3/pins/7", "outdated"},
+		{"crosspost", func(ctx context.Context, c *httpcord.RestClient) error {
+			message, err := c.CrosspostMessage(ctx, "3", "7")
+			if err == nil && message.ID != "7" {
+				t.Errorf("CrosspostMessage() = %+v, want the message 7", message)
+			}
+
+			return err
+		}, http.MethodPost, "/channels/3/messages/7/crosspost", ""},
+		{"unicode reaction", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.CreateReaction(ctx, "3", "7", thumbsUp)
+		}, http.MethodPut, "/channels/3/messages/7/reactions/👍/@me", ""},
+		{"reaction with a variation selector", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.CreateReaction(ctx, "3", "7", heart)
+		}, http.MethodPut, "/channels/3/messages/7/reactions/\u2764\ufe0f/@me", ""},
+		{"animated custom reaction", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.CreateReaction(ctx, "3", "7", blob)
+		}, http.MethodPut, "/channels/3/messages/7/reactions/blobdance:41771983429993937/@me", ""},
+		{"delete own reaction", func(ctx context.Context, c *httpcord.RestClient) error {
+			return c.DeleteOwnReaction(ctx, "3", "7", blob)
+		}, http.MethodDelete, "/channels/3/messages/7/reactions/blobdance:41771983429993937/@me", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			fake.Expect(test.method, test.path).RespondWith(httpcord.Message{ID: "7", ChannelID: "3"})
+
+			if err := test.call(context.Background(), fake.Client()); err != nil {
+				t.Fatal(err)
+			}
+
+			requests := fake.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("%d requests sent, want 1", len(requests))
+			}
+
+			if reason := requests[0].Header.Get(httpcord.ReasonHeaderKey); reason != url.PathEscape(test.reason) {
+				t.Errorf("audit log reason %q, want %q escaped", reason, test.reason)
+			}
+
+			if _, ok := requests[0].Header[http.CanonicalHeaderKey(httpcord.ReasonHeaderKey)]; ok == (test.reason == "") {
+				t.Errorf("audit log reason header sent = %v, want %v", ok, test.reason != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package httpcord
+
+// MessageCreate Message sent in a channel with RestClient.CreateMessage
+type MessageCreate struct {
+	Content          string            `json:"content,omitempty"`
+	TTS              bool              `json:"tts,omitempty"`
+	Embeds           []*Embed          `json:"embeds,omitempty"`
+	AllowedMentions  *AllowedMentions  `json:"allowed_mentions,omitempty"`
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+	Components       []AnyComponent    `json:"components,omitempty"`
+	Flags            MessageFlag       `json:"flags,omitempty"`
+	Files            []*DiscordFile    `json:"-"`
+	// Attachments describe the Files by index, the attachments of the files not listed are added when sent
+	Attachments []*Attachment `json:"attachments,omitempty"`
+	Poll        *PollCreate   `json:"poll,omitempty"`
+}
+
+// InReplyTo Reply to the message, the message is still sent when it was deleted
+func (m *MessageCreate) InReplyTo(message *Message) *MessageCreate {
+	m.MessageReference = &MessageReference{
+		MessageID: message.ID,
+		ChannelID: message.ChannelID,
+		GuildID:   message.GuildID,
+	}
+
+	return m
+}
+
+// Forward Forward the message, a forward can not have content, embeds or components
+func (m *MessageCreate) Forward(message *Message) *MessageCreate {
+	m.MessageReference = &MessageReference{
+		Type:            ForwardMessageReference,
+		MessageID:       message.ID,
+		ChannelID:       message.ChannelID,
+		GuildID:         message.GuildID,
+		FailIfNotExists: true,
+	}
+
+	return m
+}
+
+// MessageCreate Convert the callback data to a channel message payload
+func (d *InteractionCallbackData) MessageCreate() *MessageCreate {
+	message := &MessageCreate{
+		Content:         d.Content,
+		TTS:             d.TTS,
+		Embeds:          d.Embeds,
+		AllowedMentions: d.AllowedMentions,
+		Flags:           d.Flags,
+		Files:           d.Files,
+		Attachments:     d.Attachments,
+	}
+
+	for _, row := range d.Components {
+		message.Components = append(message.Components, row)
+	}
+
+	return message
+}
+
+// withAttachments Copy listing the attachments of the files, the message itself when there are no files
+func (m *MessageCreate) withAttachments() *MessageCreate {
+	if len(m.Files) == 0 {
+		return m
+	}
+
+	message := *m
+	message.Attachments = listAttachments(m.Attachments, m.Files)
+	return &message
+}
@@ -0,0 +1,91 @@
+package httpcord_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestMessageReference(t *testing.T) {
+	original := &httpcord.Message{ID: "7", ChannelID: "3", GuildID: "2", Content: "release notes"}
+	direct := &httpcord.Message{ID: "8", ChannelID: "9", Content: "hi"}
+
+	tests := []struct {
+		name    string
+		message *httpcord.MessageCreate
+		// reference message_reference of the request body
+		reference map[string]interface{}
+	}{
+		{"reply", (&httpcord.MessageCreate{Content: "thanks"}).InReplyTo(original),
+			map[string]interface{}{"message_id": "7", "channel_id": "3", "guild_id": "2", "fail_if_not_exists": false}},
+		{"reply in a dm", (&httpcord.MessageCreate{Content: "thanks"}).InReplyTo(direct),
+			map[string]interface{}{"message_id": "8", "channel_id": "9", "fail_if_not_exists": false}},
+		{"forward", (&httpcord.MessageCreate{}).Forward(original),
+			map[string]interface{}{"type": float64(httpcord.ForwardMessageReference), "message_id": "7", "channel_id": "3", "guild_id": "2",
+				"fail_if_not_exists": true}},
+		{"reply replaced by a forward", (&httpcord.MessageCreate{}).InReplyTo(direct).Forward(original),
+			map[string]interface{}{"type": float64(httpcord.ForwardMessageReference), "message_id": "7", "channel_id": "3", "guild_id": "2",
+				"fail_if_not_exists": true}},
+		{"no reference", &httpcord.MessageCreate{Content: "hello"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			fake.Expect(http.MethodPost, "/channels/5/messages").RespondWith(httpcord.Message{ID: "10", ChannelID: "5"})
+
+			if _, err := fake.Client().CreateMessage(context.Background(), "5", test.message); err != nil {
+				t.Fatal(err)
+			}
+
+			var body struct {
+				Reference map[string]interface{} `json:"message_reference"`
+			}
+
+			if err := json.Unmarshal(fake.Requests()[0].Payload, &body); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(body.Reference, test.reference) {
+				t.Errorf("message_reference %v, want %v", body.Reference, test.reference)
+			}
+		})
+	}
+}
+
+func TestMessageReferenceDecoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		reference *httpcord.MessageReference
+		forwarded bool
+	}{
+		{"reply", `{"id":"10","channel_id":"3","content":"ok","message_reference":{"message_id":"7","channel_id":"3","guild_id":"2"}}`,
+			&httpcord.MessageReference{Type: httpcord.DefaultMessageReference, MessageID: "7", ChannelID: "3", GuildID: "2"}, false},
+		{"forward", `{"id":"10","channel_id":"3","content":"","message_reference":{"type":1,"message_id":"7","channel_id":"4"}}`,
+			&httpcord.MessageReference{Type: httpcord.ForwardMessageReference, MessageID: "7", ChannelID: "4"}, true},
+		{"plain message", `{"id":"10","channel_id":"3","content":"hello"}`, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var message httpcord.Message
+			if err := json.Unmarshal([]byte(test.json), &message); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(message.MessageReference, test.reference) {
+				t.Errorf("MessageReference = %+v, want %+v", message.MessageReference, test.reference)
+			}
+
+			if message.IsForward() != test.forwarded {
+				t.Errorf("IsForward() = %v, want %v", message.IsForward(), test.forwarded)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"httpcord/endpoints"
+)
+
+// ErrInvalidMessageLink The string is not a Discord message link
+var ErrInvalidMessageLink = errors.New("httpcord: invalid message link")
+
+// MessageLinkError Segment of the message link that could not be parsed
+type MessageLinkError struct {
+	// Segment is one of "url", "host", "path", "guild", "channel" or "message"
+	Segment string
+	Value   string
+}
+
+func (e *MessageLinkError) Error() string {
+	return fmt.Sprintf("httpcord: invalid message link %s %q", e.Segment, e.Value)
+}
+
+func (e *MessageLinkError) Is(target error) bool {
+	return target == ErrInvalidMessageLink
+}
+
+// messageLinkHosts Hosts of the Discord clients, the ptb and canary subdomains included
+var messageLinkHosts = map[string]bool{
+	"discord.com":           true,
+	"ptb.discord.com":       true,
+	"canary.discord.com":    true,
+	"discordapp.com":        true,
+	"ptb.discordapp.com":    true,
+	"canary.discordapp.com": true,
+}
+
+// ParseMessageLink Extract the IDs of a link like https://discord.com/channels/{guild}/{channel}/{message}.
+// The guild ID is empty for DM links using @me, query strings and fragments are ignored
+func ParseMessageLink(s string) (guildID, channelID, messageID Snowflake, err error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", "", "", &MessageLinkError{Segment: "url", Value: s}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if !messageLinkHosts[host] {
+		return "", "", "", &MessageLinkError{Segment: "host", Value: u.Host}
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "channels" {
+		return "", "", "", &MessageLinkError{Segment: "path", Value: u.Path}
+	}
+
+	if parts[1] != "@me" {
+		if guildID = Snowflake(parts[1]); !validSnowflake(guildID) {
+			return "", "", "", &MessageLinkError{Segment: "guild", Value: parts[1]}
+		}
+	}
+
+	if channelID = Snowflake(parts[2]); !validSnowflake(channelID) {
+		return "", "", "", &MessageLinkError{Segment: "channel", Value: parts[2]}
+	}
+
+	if messageID = Snowflake(parts[3]); !validSnowflake(messageID) {
+		return "", "", "", &MessageLinkError{Segment: "message", Value: parts[3]}
+	}
+
+	return guildID, channelID, messageID, nil
+}
+
+// MessageLink Link to the message, an empty guild ID builds a DM link
+func MessageLink(guildID, channelID, messageID Snowflake) string {
+	guild := guildID.String()
+	if guild == "" {
+		guild = "@me"
+	}
+
+	return fmt.Sprintf("%s/channels/%s/%s/%s", endpoints.DiscordURL, guild, channelID, messageID)
+}
+
+// JumpURL Link to the message
+func (m *Message) JumpURL() string {
+	return MessageLink(m.GuildID, m.ChannelID, m.ID)
+}
@@ -0,0 +1,60 @@
+package httpcord
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMessageLink(t *testing.T) {
+	tests := []struct {
+		name                    string
+		link                    string
+		guild, channel, message Snowflake
+		// segment Segment of the MessageLinkError, empty when the link is valid
+		segment string
+	}{
+		{"guild message", "https://discord.com/channels/1/2/3", "1", "2", "3", ""},
+		{"canary", "https://canary.discord.com/channels/1/2/3", "1", "2", "3", ""},
+		{"ptb", "https://ptb.discord.com/channels/1/2/3", "1", "2", "3", ""},
+		{"legacy host", "https://discordapp.com/channels/1/2/3", "1", "2", "3", ""},
+		{"www and uppercase host", "https://www.Discord.com/channels/1/2/3", "1", "2", "3", ""},
+		{"direct message", "https://discord.com/channels/@me/2/3", "", "2", "3", ""},
+		{"query, fragment and spaces", "  https://discord.com/channels/1/2/3/?utm=share#top\n", "1", "2", "3", ""},
+		{"max snowflake", "https://discord.com/channels/18446744073709551615/2/3", "18446744073709551615", "2", "3", ""},
+		{"not a url", "%zz", "", "", "", "url"},
+		{"other scheme", "ftp://discord.com/channels/1/2/3", "", "", "", "url"},
+		{"other host", "https://discord.evil.com/channels/1/2/3", "", "", "", "host"},
+		{"channel link", "https://discord.com/channels/1/2", "", "", "", "path"},
+		{"extra segment", "https://discord.com/channels/1/2/3/4", "", "", "", "path"},
+		{"not channels", "https://discord.com/invite/1/2/3", "", "", "", "path"},
+		{"named guild", "https://discord.com/channels/guild/2/3", "", "", "", "guild"},
+		{"negative channel", "https://discord.com/channels/1/-2/3", "", "", "", "channel"},
+		{"overflowing message", "https://discord.com/channels/1/2/18446744073709551616", "", "", "", "message"},
+		{"empty message", "https://discord.com/channels/@me/2/", "", "", "", "path"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guild, channel, message, err := ParseMessageLink(test.link)
+
+			if test.segment != "" {
+				var linkErr *MessageLinkError
+				if !errors.As(err, &linkErr) || linkErr.Segment != test.segment || !errors.Is(err, ErrInvalidMessageLink) {
+					t.Errorf("ParseMessageLink(%q) = %v, want an error of the %s", test.link, err, test.segment)
+				}
+
+				return
+			}
+
+			if err != nil || guild != test.guild || channel != test.channel || message != test.message {
+				t.Errorf("ParseMessageLink(%q) = %q, %q, %q, %v, want %q, %q, %q", test.link, guild, channel, message, err,
+					test.guild, test.channel, test.message)
+			}
+
+			// The built link parses back to the same IDs
+			if g, c, m, err := ParseMessageLink(MessageLink(guild, channel, message)); err != nil || g != guild || c != channel || m != message {
+				t.Errorf("MessageLink() parsed back to %q, %q, %q, %v", g, c, m, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMessageFlagValues(t *testing.T) {
+	tests := []struct {
+		flag MessageFlag
+		want int
+	}{
+		{CrosspostedMessageFlag, 1},
+		{SuppressEmbedsMessageFlag, 4},
+		{HasThreadMessageFlag, 32},
+		{EphemeralMessageFlag, 64},
+		{LoadingMessageFlag, 128},
+		{FailedToMentionSomeRolesInThreadMessageFlag, 256},
+		{SuppressNotificationsMessageFlag, 4096},
+		{IsVoiceMessageMessageFlag, 8192},
+	}
+
+	for _, test := range tests {
+		if int(test.flag) != test.want {
+			t.Errorf("flag %d, Discord sends it as %d", test.flag, test.want)
+		}
+	}
+}
+
+func TestMessageFlagsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags MessageFlag
+		json  string
+	}{
+		{"none", 0, ""},
+		{"ephemeral", EphemeralMessageFlag, `"flags":64`},
+		{"suppress embeds", SuppressEmbedsMessageFlag, `"flags":4`},
+		{"suppress notifications", SuppressNotificationsMessageFlag, `"flags":4096`},
+		{"ephemeral without embeds", EphemeralMessageFlag | SuppressEmbedsMessageFlag, `"flags":68`},
+		{"ephemeral without notifications", EphemeralMessageFlag | SuppressNotificationsMessageFlag, `"flags":4160`},
+		{"silent without embeds", SuppressEmbedsMessageFlag | SuppressNotificationsMessageFlag, `"flags":4100`},
+		{"every flag", EphemeralMessageFlag | SuppressEmbedsMessageFlag | SuppressNotificationsMessageFlag, `"flags":4164`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(&InteractionCallbackData{Content: "hello", Flags: test.flags})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.json == "" && strings.Contains(string(b), "flags") {
+				t.Errorf("%s has flags, the zero flags must be omitted", b)
+			} else if test.json != "" && !strings.Contains(string(b), test.json) {
+				t.Errorf("%s is missing %s", b, test.json)
+			}
+
+			var decoded InteractionCallbackData
+			if err := json.Unmarshal(b, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			if decoded.Flags != test.flags || !decoded.Flags.Has(test.flags) {
+				t.Errorf("decoded flags %d, want %d", decoded.Flags, test.flags)
+			}
+		})
+	}
+}
+
+func TestEphemeralReplies(t *testing.T) {
+	tests := []struct {
+		name     string
+		respond  func(ctx *ConnectionContext) error
+		response InteractionCallbackType
+		flags    MessageFlag
+		// data The response carries data with the flags
+		data bool
+	}{
+		{"reply ephemeral", func(ctx *ConnectionContext) error {
+			return ctx.ReplyEphemeral(&InteractionCallbackData{Content: "only you"})
+		}, ChannelMessageWithSourceResponse, EphemeralMessageFlag, true},
+		{"reply ephemeral keeps the flags", func(ctx *ConnectionContext) error {
+			return ctx.ReplyEphemeral(&InteractionCallbackData{Content: "only you", Flags: SuppressEmbedsMessageFlag})
+		}, ChannelMessageWithSourceResponse, EphemeralMessageFlag | SuppressEmbedsMessageFlag, true},
+		{"reply ephemeral without data", func(ctx *ConnectionContext) error {
+			return ctx.ReplyEphemeral(nil)
+		}, ChannelMessageWithSourceResponse, EphemeralMessageFlag, true},
+		{"defer ephemeral", func(ctx *ConnectionContext) error {
+			return ctx.DeferReplyWithFlags(EphemeralMessageFlag)
+		}, DeferredChannelMessageWithSourceResponse, EphemeralMessageFlag, true},
+		{"defer", func(ctx *ConnectionContext) error {
+			return ctx.DeferReplyInteraction()
+		}, DeferredChannelMessageWithSourceResponse, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var responses []*InteractionResponse
+
+			ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}, ContextConfig{
+				Respond: func(response *InteractionResponse) error {
+					responses = append(responses, response)
+					return nil
+				},
+				Webhooks: &countingWebhooks{},
+			})
+			defer finish()
+
+			if err := test.respond(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ctx.ReplyEphemeral(&InteractionCallbackData{Content: "again"}); !errors.Is(err, ErrAlreadyResponded) {
+				t.Errorf("second response = %v, want ErrAlreadyResponded", err)
+			}
+
+			if len(responses) != 1 {
+				t.Fatalf("%d responses sent, want 1", len(responses))
+			}
+
+			response := responses[0]
+			if response.Type != test.response || (response.Data != nil) != test.data {
+				t.Fatalf("response %+v, want the type %d", response, test.response)
+			}
+
+			if response.Data != nil && response.Data.Flags != test.flags {
+				t.Errorf("flags %d, want %d", response.Data.Flags, test.flags)
+			}
+		})
+	}
+
+	t.Run("data is not modified", func(t *testing.T) {
+		ctx, finish := NewContext(Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}, ContextConfig{
+			Respond:  func(*InteractionResponse) error { return nil },
+			Webhooks: &countingWebhooks{},
+		})
+		defer finish()
+
+		data := &InteractionCallbackData{Content: "only you"}
+		ctx.ReplyEphemeral(data)
+
+		if data.Flags != 0 {
+			t.Errorf("the flags of data were set to %d", data.Flags)
+		}
+	})
+}
+
+func TestMessageSnapshots(t *testing.T) {
+	forward := `{"id":"7","channel_id":"3","content":"","message_reference":{"type":1,"message_id":"6","channel_id":"4"},` +
+		`"message_snapshots":[{"message":{"type":0,"content":"release notes","embeds":[{"title":"v2"}],` +
+		`"attachments":[{"id":"9","filename":"notes.txt"}],"timestamp":"2024-01-02T03:04:05+00:00","edited_timestamp":null}}]}`
+
+	tests := []struct {
+		name      string
+		json      string
+		forward   bool
+		content   string
+		snapshots int
+	}{
+		{"plain message", `{"id":"7","channel_id":"3","content":"hello"}`, false, "hello", 0},
+		{"reply", `{"id":"7","channel_id":"3","content":"agreed","message_reference":{"message_id":"6","channel_id":"3"}}`, false, "agreed", 0},
+		{"forward", forward, true, "release notes", 1},
+		{"forward of an attachment only", `{"id":"7","channel_id":"3","content":"","message_reference":{"type":1,"message_id":"6"},` +
+			`"message_snapshots":[{"message":{"type":0,"content":"","embeds":[],"attachments":[{"id":"9","filename":"cat.png"}]}}]}`,
+			true, "", 1},
+		{"snapshots without a forward reference", `{"id":"7","channel_id":"3","content":"",` +
+			`"message_snapshots":[{"message":{"type":0,"content":"copied","embeds":[]}}]}`, true, "copied", 1},
+		{"forward without its snapshot", `{"id":"7","channel_id":"3","content":"","message_reference":{"type":1,"message_id":"6"}}`,
+			true, "", 0},
+		{"null snapshot message", `{"id":"7","channel_id":"3","content":"","message_snapshots":[{"message":null}]}`, true, "", 1},
+		{"own content first", `{"id":"7","channel_id":"3","content":"look","message_snapshots":[{"message":{"type":0,"content":"copied","embeds":[]}}]}`,
+			true, "look", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var message Message
+			if err := json.Unmarshal([]byte(test.json), &message); err != nil {
+				t.Fatal(err)
+			}
+
+			if message.IsForward() != test.forward {
+				t.Errorf("IsForward() = %v, want %v", message.IsForward(), test.forward)
+			}
+
+			if content := message.EffectiveContent(); content != test.content {
+				t.Errorf("EffectiveContent() = %q, want %q", content, test.content)
+			}
+
+			if len(message.MessageSnapshots) != test.snapshots {
+				t.Errorf("%d snapshots, want %d", len(message.MessageSnapshots), test.snapshots)
+			}
+		})
+	}
+
+	t.Run("snapshot fields", func(t *testing.T) {
+		var message Message
+		if err := json.Unmarshal([]byte(forward), &message); err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := message.MessageSnapshots[0].Message
+		if len(snapshot.Embeds) != 1 || snapshot.Embeds[0].Title != "v2" || len(snapshot.Attachments) != 1 ||
+			snapshot.Attachments[0].Filename != "notes.txt" || snapshot.Timestamp.IsZero() {
+			t.Errorf("snapshot %+v, want its embed, attachment and timestamp", snapshot)
+		}
+	})
+
+	t.Run("target of a message command", func(t *testing.T) {
+		ctx := bodyContext(t, interactionBody(ApplicationCommandInteraction, `{"id":"5","name":"Quote","type":3,"target_id":"7",`+
+			`"resolved":{"messages":{"7":{"id":"7","channel_id":"3","content":"","message_reference":{"type":1,"message_id":"6"},`+
+			`"message_snapshots":[{"message":{"type":0,"content":"forwarded text","embeds":[]}}]}}}}`))
+
+		message, ok := ctx.TargetMessage()
+		if !ok || !message.IsForward() || message.EffectiveContent() != "forwarded text" {
+			t.Errorf("TargetMessage() = %+v, %v, want the forward of \"forwarded text\"", message, ok)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package httpcord
+
+// MetricsCollector Receive the library metrics, names follow the Prometheus conventions
+type MetricsCollector interface {
+	IncCounter(name string, labels map[string]string)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) IncCounter(string, map[string]string) {}
+
+// NopMetrics Default MetricsCollector discarding every metric
+var NopMetrics MetricsCollector = nopMetrics{}
+
+// HistogramCollector Optional MetricsCollector extension receiving the durations, in seconds, like
+// httpcord_handler_duration_seconds
+type HistogramCollector interface {
+	ObserveHistogram(name string, labels map[string]string, value float64)
+}
+
+// observeHistogram Send the observation to the metrics when they receive histograms
+func observeHistogram(metrics MetricsCollector, name string, labels map[string]string, value float64) {
+	if histograms, ok := metrics.(HistogramCollector); ok {
+		histograms.ObserveHistogram(name, labels, value)
+	}
+}
+
+// interactionKinds Values of the "kind" label of the interaction metrics
+var interactionKinds = map[InteractionType]string{
+	ApplicationCommandInteraction: "command",
+	MessageComponentInteraction:   "component",
+	AutoCompleteInteraction:       "autocomplete",
+	ModalSubmitInteraction:        "modal",
+}
+
+// interactionLabels Labels of httpcord_interactions_total, the command is empty for components and modals
+func interactionLabels(interaction *Interaction) map[string]string {
+	labels := map[string]string{
+		"kind":    interactionKinds[interaction.Type],
+		"command": "",
+		"guild":   interaction.GuildID.String(),
+	}
+
+	if interaction.Type == ApplicationCommandInteraction || interaction.Type == AutoCompleteInteraction {
+		labels["command"] = interaction.ApplicationCommandData().Name
+	}
+
+	return labels
+}
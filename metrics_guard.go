@@ -0,0 +1,193 @@
+package httpcord
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OtherCommandLabel Command label of the commands collapsed by MetricsGuard
+const OtherCommandLabel = "other"
+
+type MetricsGuardOption func(o *metricsGuardOptions)
+
+type metricsGuardOptions struct {
+	sampleRates  map[string]float64
+	commands     map[string]bool
+	topCommands  int
+	guildBuckets int
+	dropGuilds   bool
+	rand         *rand.Rand
+}
+
+// MetricsSampleRate Report the counters of the interaction kind, like "component", with the probability rate between
+// 0 and 1. Sampled counters are 1/rate times lower than the traffic
+func MetricsSampleRate(kind string, rate float64) MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		o.sampleRates[kind] = rate
+	}
+}
+
+// MetricsAllowCommands Keep the command label of the commands, the others are reported as OtherCommandLabel unless
+// tracked by MetricsTopCommands
+func MetricsAllowCommands(commands ...string) MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		for _, command := range commands {
+			o.commands[command] = true
+		}
+	}
+}
+
+// MetricsTopCommands Keep the command label of the k most used commands (See TopK), the others are reported as
+// OtherCommandLabel
+func MetricsTopCommands(k int) MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		o.topCommands = k
+	}
+}
+
+// MetricsGuildBuckets Replace the guild label by one of n buckets of the guild ID hash
+func MetricsGuildBuckets(n int) MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		o.guildBuckets = n
+	}
+}
+
+// MetricsDropGuilds Remove the guild label
+func MetricsDropGuilds() MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		o.dropGuilds = true
+	}
+}
+
+// MetricsRand Random source of the sampling, seeded sources make the sampling reproducible (Defaults to a time seed)
+func MetricsRand(source *rand.Rand) MetricsGuardOption {
+	return func(o *metricsGuardOptions) {
+		o.rand = source
+	}
+}
+
+// MetricsGuard MetricsCollector bounding the cardinality of the metrics sent to the next collector: counters are
+// sampled by their "kind" label, "command" labels are collapsed past the allowed or most used commands and "guild"
+// labels are hashed into buckets or dropped. Gauges are forwarded untouched when the next collector receives them
+type MetricsGuard struct {
+	next     MetricsCollector
+	options  metricsGuardOptions
+	commands *TopK
+	// mu guards the random source, rand.Rand is not safe for concurrent use
+	mu sync.Mutex
+}
+
+func NewMetricsGuard(next MetricsCollector, opts ...MetricsGuardOption) *MetricsGuard {
+	o := metricsGuardOptions{sampleRates: make(map[string]float64), commands: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.rand == nil {
+		o.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	g := &MetricsGuard{next: next, options: o}
+	if o.topCommands > 0 {
+		g.commands = NewTopK(o.topCommands)
+	}
+
+	return g
+}
+
+// sampled Whether the counter of the interaction kind is reported
+func (g *MetricsGuard) sampled(kind string) bool {
+	rate, ok := g.options.sampleRates[kind]
+	if !ok {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.options.rand.Float64() < rate
+}
+
+// command Command label of the command
+func (g *MetricsGuard) command(command string) string {
+	if command == "" || g.options.commands[command] {
+		return command
+	}
+
+	if g.commands != nil && g.commands.Add(command) {
+		return command
+	}
+
+	if g.commands == nil && len(g.options.commands) == 0 {
+		return command
+	}
+
+	return OtherCommandLabel
+}
+
+// guild Guild label of the guild ID, false when dropped
+func (g *MetricsGuard) guild(guild string) (string, bool) {
+	if g.options.dropGuilds {
+		return "", false
+	}
+
+	if g.options.guildBuckets <= 0 || guild == "" {
+		return guild, true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(guild))
+	return strconv.Itoa(int(h.Sum32() % uint32(g.options.guildBuckets))), true
+}
+
+// labels Copy of the labels with the command and guild labels limited
+func (g *MetricsGuard) labels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	limited := make(map[string]string, len(labels))
+	for name, value := range labels {
+		limited[name] = value
+	}
+
+	if command, ok := labels["command"]; ok {
+		limited["command"] = g.command(command)
+	}
+
+	if guild, ok := labels["guild"]; ok {
+		if value, keep := g.guild(guild); keep {
+			limited["guild"] = value
+		} else {
+			delete(limited, "guild")
+		}
+	}
+
+	return limited
+}
+
+func (g *MetricsGuard) IncCounter(name string, labels map[string]string) {
+	if !g.sampled(labels["kind"]) {
+		return
+	}
+
+	g.next.IncCounter(name, g.labels(labels))
+}
+
+func (g *MetricsGuard) SetGauge(name string, labels map[string]string, value float64) {
+	if gauges, ok := g.next.(GaugeCollector); ok {
+		gauges.SetGauge(name, labels, value)
+	}
+}
+
+// ObserveHistogram Forward the observation sampled and labeled like the counters
+func (g *MetricsGuard) ObserveHistogram(name string, labels map[string]string, value float64) {
+	if !g.sampled(labels["kind"]) {
+		return
+	}
+
+	observeHistogram(g.next, name, g.labels(labels), value)
+}
@@ -0,0 +1,151 @@
+package httpcord
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// gaugesRecorder countersRecorder receiving the gauges
+type gaugesRecorder struct {
+	countersRecorder
+	gauges map[string]float64
+}
+
+func (r *gaugesRecorder) SetGauge(name string, labels map[string]string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.gauges == nil {
+		r.gauges = make(map[string]float64)
+	}
+
+	r.gauges[name] = value
+}
+
+// distinct Values of the label in the counters, "" for the counters without it
+func distinct(counters []map[string]string, label string) map[string]int {
+	values := make(map[string]int)
+	for _, labels := range counters {
+		values[labels[label]]++
+	}
+
+	return values
+}
+
+func TestMetricsGuardSampling(t *testing.T) {
+	count := func(seed int64) (components, commands int) {
+		var next countersRecorder
+		guard := NewMetricsGuard(&next, MetricsSampleRate("component", 0.25), MetricsRand(rand.New(rand.NewSource(seed))))
+
+		for i := 0; i < 1000; i++ {
+			guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "component"})
+			guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "command"})
+		}
+
+		kinds := distinct(next.get("httpcord_interactions_total"), "kind")
+		return kinds["component"], kinds["command"]
+	}
+
+	components, commands := count(1)
+
+	if components < 200 || components > 300 {
+		t.Errorf("%d of 1000 components reported, want about 250", components)
+	}
+
+	if commands != 1000 {
+		t.Errorf("%d of 1000 commands reported, want every unsampled kind", commands)
+	}
+
+	if again, _ := count(1); again != components {
+		t.Errorf("%d components reported with the same seed, want %d", again, components)
+	}
+}
+
+func TestMetricsGuardCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []MetricsGuardOption
+		// commands Command labels sent, "hot" is sent before each of them
+		commands []string
+		want     map[string]int
+	}{
+		{"untouched", nil, []string{"kick"}, map[string]int{"hot": 1, "kick": 1}},
+		{"allowed commands", []MetricsGuardOption{MetricsAllowCommands("hot")}, []string{"kick", "warn"},
+			map[string]int{"hot": 2, OtherCommandLabel: 2}},
+		{"top commands", []MetricsGuardOption{MetricsTopCommands(2)}, []string{"kick", "warn", "mute", "kick"},
+			map[string]int{"hot": 4, "kick": 2, OtherCommandLabel: 2}},
+		{"allowed and top commands", []MetricsGuardOption{MetricsAllowCommands("warn"), MetricsTopCommands(1)}, []string{"kick", "warn"},
+			map[string]int{"hot": 2, "warn": 1, OtherCommandLabel: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var next countersRecorder
+			guard := NewMetricsGuard(&next, test.opts...)
+
+			for _, command := range test.commands {
+				guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "command", "command": "hot"})
+				guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "command", "command": command})
+			}
+
+			got := distinct(next.get("httpcord_interactions_total"), "command")
+			if len(got) != len(test.want) {
+				t.Fatalf("command labels %v, want %v", got, test.want)
+			}
+
+			for label, n := range test.want {
+				if got[label] != n {
+					t.Errorf("command labels %v, want %v", got, test.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsGuardCardinality(t *testing.T) {
+	var next countersRecorder
+	guard := NewMetricsGuard(&next, MetricsTopCommands(5), MetricsGuildBuckets(8))
+
+	for i := 0; i < 500; i++ {
+		labels := map[string]string{"kind": "command", "command": "command-" + strconv.Itoa(i), "guild": strconv.Itoa(1000 + i)}
+		guard.IncCounter("httpcord_interactions_total", labels)
+
+		if labels["command"] != "command-"+strconv.Itoa(i) || labels["guild"] != strconv.Itoa(1000+i) {
+			t.Fatalf("labels of the caller changed to %v", labels)
+		}
+	}
+
+	counters := next.get("httpcord_interactions_total")
+
+	// The 5 first commands are tracked, the later ones are never more frequent
+	if commands := distinct(counters, "command"); len(commands) != 6 || commands[OtherCommandLabel] != 495 {
+		t.Errorf("command labels %v, want 5 commands and %s", commands, OtherCommandLabel)
+	}
+
+	if guilds := distinct(counters, "guild"); len(guilds) > 8 {
+		t.Errorf("%d guild labels, want at most 8 buckets", len(guilds))
+	}
+
+	guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "command", "guild": "1000"})
+	if counters := next.get("httpcord_interactions_total"); counters[len(counters)-1]["guild"] != counters[0]["guild"] {
+		t.Error("the same guild is hashed in another bucket")
+	}
+}
+
+func TestMetricsGuardDropGuilds(t *testing.T) {
+	var next gaugesRecorder
+	guard := NewMetricsGuard(&next, MetricsDropGuilds())
+
+	guard.IncCounter("httpcord_interactions_total", map[string]string{"kind": "command", "guild": "1"})
+	guard.SetGauge("httpcord_workers_running", nil, 3)
+
+	if labels := next.get("httpcord_interactions_total")[0]; len(labels) != 1 || labels["kind"] != "command" {
+		t.Errorf("labels %v, want the guild dropped", labels)
+	}
+
+	if next.gauges["httpcord_workers_running"] != 3 {
+		t.Errorf("gauges %v, want the gauge forwarded", next.gauges)
+	}
+}
@@ -0,0 +1,106 @@
+package httpcord
+
+import "errors"
+
+// ErrModalNotAllowed Modals can not answer modal submit and ping interactions
+var ErrModalNotAllowed = errors.New("httpcord: modals can not answer modal submit and ping interactions")
+
+// ReplyModal Reply with a modal, components outside an action row like text inputs get a row each.
+// Returns ErrModalNotAllowed for modal submit and ping interactions and a CustomIDError for custom_ids over the limit
+func (ctx *ConnectionContext) ReplyModal(customID, title string, components ...AnyComponent) error {
+	switch ctx.Interaction.Type {
+	case ModalSubmitInteraction, PingInteraction:
+		return ErrModalNotAllowed
+	}
+
+	modal := &Modal{CustomID: customID, Title: title}
+
+	for _, component := range components {
+		switch row := component.(type) {
+		case *ActionRowComponent:
+			modal.Components = append(modal.Components, row)
+		case ActionRowComponent:
+			modal.Components = append(modal.Components, &row)
+		default:
+			modal.Components = append(modal.Components, NewActionRowComponentBuilder().AddComponent(component))
+		}
+	}
+
+	if err := modal.Validate(); err != nil {
+		return err
+	}
+
+	return ctx.ShowModal(modal)
+}
+
+// Values Submitted text inputs keyed by their custom_id
+func (d *ModalSubmitInteractionData) Values() map[string]string {
+	values := make(map[string]string)
+
+	for _, row := range d.Components {
+		if row == nil {
+			continue
+		}
+
+		for _, component := range row.Components {
+			input, ok := component.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			customID, _ := input["custom_id"].(string)
+			value, _ := input["value"].(string)
+
+			if customID != "" {
+				values[customID] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// ModalValues Text inputs of the modal submit keyed by their custom_id, nil for other interactions
+func (ctx *ConnectionContext) ModalValues() map[string]string {
+	data, ok := ctx.Interaction.Data.(ModalSubmitInteractionData)
+	if !ok {
+		return nil
+	}
+
+	return data.Values()
+}
+
+// ModalValue Text input of the modal submit with the custom_id, false when the submit has none
+func (ctx *ConnectionContext) ModalValue(customID string) (string, bool) {
+	value, ok := ctx.ModalValues()[customID]
+	return value, ok
+}
+
+// Modal Register a handler for the submits of the modals with the custom_id, a trailing "*" matches any suffix
+// like Component does (See ConnectionContext.CustomIDParam). Panics when the pattern is already registered
+func (c *Connection) Modal(pattern string, handler Handler) *ComponentRoute {
+	return c.router.addModal(&ComponentRoute{Pattern: pattern, Handler: handler, Source: callerSite(1)})
+}
+
+func (r *commandRouter) addModal(route *ComponentRoute) *ComponentRoute {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.modals.add("modal", route)
+	return route
+}
+
+// dispatchModal Run the route of the modal submit
+func (r *commandRouter) dispatchModal(ctx ConnectionContext) bool {
+	r.mu.RLock()
+	route, param, vars := r.modals.match(ctx.Interaction.ModalSubmitData().CustomID)
+	r.mu.RUnlock()
+
+	if route == nil {
+		return false
+	}
+
+	ctx.componentParam, ctx.componentVars = param, vars
+	route.Handler(ctx)
+	return true
+}
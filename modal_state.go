@@ -0,0 +1,121 @@
+package httpcord
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxCustomIDLength Maximum length of component and modal custom IDs
+	MaxCustomIDLength = 100
+
+	modalStateSeparator = "#st:"
+	modalStatePrefix    = "modal:"
+	// modalStateTokenLength Hex characters of the state token appended to the custom ID
+	modalStateTokenLength = 16
+)
+
+var (
+	// ErrCustomIDTooLong The custom ID exceeds MaxCustomIDLength or does not leave room for the state token (See CustomIDError)
+	ErrCustomIDTooLong = errors.New("httpcord: custom id exceeds the length limit")
+	// ErrModalStateMissing The modal was not shown with state, or the state expired
+	ErrModalStateMissing = errors.New("httpcord: modal state missing or expired")
+)
+
+type ModalStateOption func(o *modalStateOptions)
+
+type modalStateOptions struct {
+	ttl time.Duration
+}
+
+// ModalStateTTL Time the modal can be submitted with its state (Defaults to InteractionTokenLifetime)
+func ModalStateTTL(ttl time.Duration) ModalStateOption {
+	return func(o *modalStateOptions) {
+		o.ttl = ttl
+	}
+}
+
+// ShowModal Reply with a modal
+func (ctx *ConnectionContext) ShowModal(modal *Modal) error {
+	return ctx.SendRes(&InteractionResponse{
+		Type: ModalResponse,
+		Data: &InteractionCallbackData{
+			CustomID:   modal.CustomID,
+			Title:      modal.Title,
+			Components: modal.Components,
+		},
+	})
+}
+
+// ShowModalWithState Reply with a modal and keep state for its submit handler, see ModalState.
+// The state is encoded as JSON in the StateStore under a token appended to the modal custom ID,
+// submit interactions still see the original custom ID
+func (ctx *ConnectionContext) ShowModalWithState(modal *Modal, state interface{}, opts ...ModalStateOption) error {
+	o := modalStateOptions{ttl: InteractionTokenLifetime}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(modal.CustomID)+len(modalStateSeparator)+modalStateTokenLength > MaxCustomIDLength {
+		return ErrCustomIDTooLong
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	b := make([]byte, modalStateTokenLength/2)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	if err := ctx.options.StateStore.Set(modalStatePrefix+token, value, o.ttl); err != nil {
+		return err
+	}
+
+	stateful := *modal
+	stateful.CustomID += modalStateSeparator + token
+
+	ctx.ShowModal(&stateful)
+	return nil
+}
+
+// ModalState Decode the state stored by ShowModalWithState into v and delete it
+func (ctx *ConnectionContext) ModalState(v interface{}) error {
+	if ctx.Interaction.Type != ModalSubmitInteraction {
+		return ErrModalStateMissing
+	}
+
+	token := ctx.Interaction.ModalSubmitData().stateToken
+	if token == "" {
+		return ErrModalStateMissing
+	}
+
+	value, ok, err := ctx.options.StateStore.Get(modalStatePrefix + token)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrModalStateMissing
+	}
+
+	ctx.options.StateStore.Delete(modalStatePrefix + token)
+	return json.Unmarshal(value, v)
+}
+
+// splitModalState Remove the state token from the submitted custom ID
+func splitModalState(data *ModalSubmitInteractionData) {
+	i := strings.LastIndex(data.CustomID, modalStateSeparator)
+
+	if i < 0 || len(data.CustomID)-i-len(modalStateSeparator) != modalStateTokenLength {
+		return
+	}
+
+	data.stateToken = data.CustomID[i+len(modalStateSeparator):]
+	data.CustomID = data.CustomID[:i]
+}
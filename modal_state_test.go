@@ -0,0 +1,162 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// banState Options of the command kept for the modal submit handler
+type banState struct {
+	UserID Snowflake `json:"user_id"`
+	Days   int       `json:"days"`
+}
+
+func TestModalState(t *testing.T) {
+	tests := []struct {
+		name     string
+		customID string
+		opts     []ModalStateOption
+		// wait Time before the modal is submitted
+		wait time.Duration
+		// submits Number of submits of the modal
+		submits int
+		// showErr Error of ShowModalWithState
+		showErr error
+		// errs Error of ModalState for each submit
+		errs []error
+	}{
+		{"handed off", "ban", nil, 0, 1, nil, []error{nil}},
+		{"deleted once read", "ban", nil, 0, 2, nil, []error{nil, ErrModalStateMissing}},
+		{"expired", "ban", []ModalStateOption{ModalStateTTL(time.Millisecond)}, 5 * time.Millisecond, 1, nil, []error{ErrModalStateMissing}},
+		{"never submitted", "ban", []ModalStateOption{ModalStateTTL(time.Millisecond)}, 5 * time.Millisecond, 0, nil, nil},
+		{"longest custom ID", strings.Repeat("b", MaxCustomIDLength-len(modalStateSeparator)-modalStateTokenLength), nil, 0, 1, nil, []error{nil}},
+		{"no room for the token", strings.Repeat("b", MaxCustomIDLength-len(modalStateSeparator)-modalStateTokenLength+1), nil, 0, 0, ErrCustomIDTooLong, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStateStore()
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, StateStore: store})
+
+			state := banState{UserID: "6", Days: 7}
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				err := ctx.ShowModalWithState(&Modal{CustomID: test.customID, Title: "Ban"}, state, test.opts...)
+				if !errors.Is(err, test.showErr) {
+					t.Errorf("ShowModalWithState() = %v, want %v", err, test.showErr)
+				}
+
+				if err != nil {
+					ctx.ReplyInteraction(&InteractionCallbackData{Content: "refused"})
+				}
+			})
+
+			var submitted []string
+			var errs []error
+
+			conn.Modal(test.customID, func(ctx ConnectionContext) {
+				submitted = append(submitted, ctx.Interaction.ModalSubmitData().CustomID)
+
+				var got banState
+				err := ctx.ModalState(&got)
+				if err == nil && got != state {
+					t.Errorf("ModalState() = %+v, want %+v", got, state)
+				}
+
+				errs = append(errs, err)
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(commandBody()))
+
+			var response InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%v: %s", err, w.Body)
+			}
+
+			if test.showErr != nil {
+				if response.Type == ModalResponse || store.Len() != 0 {
+					t.Errorf("response %s with %d states stored, want no modal", w.Body, store.Len())
+				}
+
+				return
+			}
+
+			customID := response.Data.CustomID
+			if response.Type != ModalResponse || !strings.HasPrefix(customID, test.customID+modalStateSeparator) || len(customID) > MaxCustomIDLength {
+				t.Fatalf("response %s, want the modal with the state token in a custom ID within %d characters", w.Body, MaxCustomIDLength)
+			}
+
+			time.Sleep(test.wait)
+
+			for i := 0; i < test.submits; i++ {
+				body, _ := json.Marshal(customID)
+				conn.ServeHTTP(httptest.NewRecorder(), sign(interactionBody(ModalSubmitInteraction, `{"custom_id":`+string(body)+`,"components":[]}`)))
+			}
+
+			if len(errs) != len(test.errs) {
+				t.Fatalf("ModalState() ran %d times, want %d", len(errs), len(test.errs))
+			}
+
+			for i, err := range errs {
+				if !errors.Is(err, test.errs[i]) {
+					t.Errorf("submit %d: ModalState() = %v, want %v", i, err, test.errs[i])
+				}
+
+				if submitted[i] != test.customID {
+					t.Errorf("submit %d: custom ID %q, want the original %q", i, submitted[i], test.customID)
+				}
+			}
+
+			if test.submits == 0 {
+				token := customID[len(customID)-modalStateTokenLength:]
+				if _, ok, _ := store.Get(modalStatePrefix + token); ok {
+					t.Error("the state of the modal never submitted outlived its TTL")
+				}
+			}
+
+			// Read or expired, the state does not stay in the store
+			if store.Len() != 0 {
+				t.Errorf("%d states left in the store", store.Len())
+			}
+		})
+	}
+}
+
+func TestModalStateWithoutState(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{"modal without state", interactionBody(ModalSubmitInteraction, `{"custom_id":"ban","components":[]}`)},
+		{"unknown token", interactionBody(ModalSubmitInteraction, `{"custom_id":"ban#st:0123456789abcdef","components":[]}`)},
+		{"command", commandBody()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+
+			var err error
+			handler := func(ctx ConnectionContext) {
+				var state banState
+				err = ctx.ModalState(&state)
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"})
+			}
+
+			conn.Command("ban", handler)
+			conn.Modal("ban", handler)
+
+			conn.ServeHTTP(httptest.NewRecorder(), sign(test.body))
+
+			if !errors.Is(err, ErrModalStateMissing) {
+				t.Errorf("ModalState() = %v, want ErrModalStateMissing", err)
+			}
+		})
+	}
+}
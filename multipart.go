@@ -0,0 +1,99 @@
+package httpcord
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+)
+
+// BoundaryFunc Generate the boundary of a multipart body, random boundaries are used when nil
+type BoundaryFunc func() string
+
+// FixedBoundary Use the same boundary for every body, for golden tests and proxies filtering boundaries.
+// The boundary must be 1 to 70 characters of the RFC 2046 set
+func FixedBoundary(boundary string) BoundaryFunc {
+	return func() string {
+		return boundary
+	}
+}
+
+// SequentialBoundary Boundaries made of the prefix and a counter, like "httpcord-1", "httpcord-2"
+func SequentialBoundary(prefix string) BoundaryFunc {
+	var n uint64
+
+	return func() string {
+		return prefix + strconv.FormatUint(atomic.AddUint64(&n, 1), 10)
+	}
+}
+
+// writeMultipart Write payload_json as the first part followed by files[0..n] in index order.
+// Returns the content type of the body
+func writeMultipart(w io.Writer, codec Codec, boundary BoundaryFunc, payload interface{}, files []*DiscordFile) (string, error) {
+	m := multipart.NewWriter(w)
+
+	if boundary != nil {
+		if err := m.SetBoundary(boundary()); err != nil {
+			return "", err
+		}
+	}
+
+	if payload != nil {
+		headers := make(textproto.MIMEHeader)
+		headers.Set("Content-Disposition", `form-data; name="payload_json"`)
+		headers.Set("Content-Type", "application/json")
+
+		field, err := m.CreatePart(headers)
+		if err != nil {
+			return "", err
+		}
+
+		if err := encodeTo(codec, field, payload); err != nil {
+			return "", err
+		}
+	}
+
+	for i, file := range files {
+		if _, err := file.MakeAttach(i, m); err != nil {
+			return "", err
+		}
+	}
+
+	if err := m.Close(); err != nil {
+		return "", err
+	}
+
+	return m.FormDataContentType(), nil
+}
+
+// encodeInteractionResponse JSON body of the response, or a multipart body when its message has files.
+// The attachments of the files not listed in Attachments are added to a copy of the data
+func encodeInteractionResponse(codec Codec, response *InteractionResponse, boundary BoundaryFunc) ([]byte, string, error) {
+	if response.Data == nil || len(response.Data.Files) == 0 {
+		return encodeBody(codec, response, nil, boundary)
+	}
+
+	data := *response.Data
+	data.Attachments = listAttachments(data.Attachments, data.Files)
+
+	copied := *response
+	copied.Data = &data
+
+	return encodeBody(codec, &copied, data.Files, boundary)
+}
+
+// fileContentType ContentType of the file or the type of its extension
+func fileContentType(f *DiscordFile) string {
+	if f.ContentType != "" {
+		return f.ContentType
+	}
+
+	if contentType := mime.TypeByExtension(filepath.Ext(f.Filename)); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
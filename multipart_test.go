@@ -0,0 +1,271 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func goldenFiles() []*DiscordFile {
+	return []*DiscordFile{
+		{Buffer: bytes.NewBufferString("PNG"), Filename: "chart.png", Description: "Weekly chart"},
+		{Buffer: bytes.NewBufferString(`{"a":1}`), Filename: "data.json", Spoiler: true},
+		{Buffer: bytes.NewBufferString("raw"), Filename: "dump.png", ContentType: "text/plain"},
+		{Buffer: bytes.NewBufferString("trace"), Filename: "trace"},
+	}
+}
+
+func TestMultipartGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func() ([]byte, string, error)
+		golden string
+	}{
+		{
+			name: "interaction response",
+			encode: func() ([]byte, string, error) {
+				response := &InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: &InteractionCallbackData{Content: "report", Files: goldenFiles()}}
+				return encodeInteractionResponse(StdCodec, response, FixedBoundary("httpcord-golden"))
+			},
+			golden: "--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"payload_json\"\r\n" +
+				"Content-Type: application/json\r\n\r\n" +
+				`{"type":4,"data":{"content":"report","components":null,"attachments":[{"id":"0","filename":"chart.png","description":"Weekly chart"},` +
+				`{"id":"1","filename":"SPOILER_data.json"},{"id":"2","filename":"dump.png"},{"id":"3","filename":"trace"}]}}` + "\n\r\n" +
+				"--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"files[0]\"; filename=\"chart.png\"\r\n" +
+				"Content-Type: image/png\r\n\r\n" +
+				"PNG\r\n" +
+				"--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"files[1]\"; filename=\"SPOILER_data.json\"\r\n" +
+				"Content-Type: application/json\r\n\r\n" +
+				`{"a":1}` + "\r\n" +
+				"--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"files[2]\"; filename=\"dump.png\"\r\n" +
+				"Content-Type: text/plain\r\n\r\n" +
+				"raw\r\n" +
+				"--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"files[3]\"; filename=\"trace\"\r\n" +
+				"Content-Type: application/octet-stream\r\n\r\n" +
+				"trace\r\n" +
+				"--httpcord-golden--\r\n",
+		},
+		{
+			name: "files without payload",
+			encode: func() ([]byte, string, error) {
+				return encodeBody(StdCodec, nil, goldenFiles()[:1], FixedBoundary("httpcord-golden"))
+			},
+			golden: "--httpcord-golden\r\n" +
+				"Content-Disposition: form-data; name=\"files[0]\"; filename=\"chart.png\"\r\n" +
+				"Content-Type: image/png\r\n\r\n" +
+				"PNG\r\n" +
+				"--httpcord-golden--\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, contentType, err := test.encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if contentType != "multipart/form-data; boundary=httpcord-golden" {
+				t.Errorf("content type %q", contentType)
+			}
+
+			if string(body) != test.golden {
+				t.Errorf("body\n%q\nwant\n%q", body, test.golden)
+			}
+		})
+	}
+}
+
+func TestMultipartBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary BoundaryFunc
+		want     []string
+		fails    bool
+	}{
+		{"fixed", FixedBoundary("proxy-safe"), []string{"proxy-safe", "proxy-safe"}, false},
+		{"sequential", SequentialBoundary("httpcord-"), []string{"httpcord-1", "httpcord-2"}, false},
+		{"invalid character", FixedBoundary("bad\"boundary"), nil, true},
+		{"too long", FixedBoundary(string(bytes.Repeat([]byte("b"), 71))), nil, true},
+		{"empty", FixedBoundary(""), nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				contentType, err := writeMultipart(&buf, StdCodec, test.boundary, map[string]string{"content": "report"}, nil)
+
+				if test.fails {
+					if err == nil {
+						t.Fatalf("the boundary was accepted: %s", contentType)
+					}
+
+					return
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if want := "multipart/form-data; boundary=" + test.want[i]; contentType != want {
+					t.Errorf("content type %q, want %q", contentType, want)
+				}
+			}
+		})
+	}
+}
+
+// multipartAttachments Attachments of payload_json and filenames of the files[n] parts of the body, keyed by their ID
+func multipartAttachments(t *testing.T, body []byte, contentType string) (attachments, parts map[string]string) {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("content type %q: %v", contentType, err)
+	}
+
+	attachments, parts = make(map[string]string), make(map[string]string)
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return attachments, parts
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if part.FormName() != "payload_json" {
+			index := strings.TrimSuffix(strings.TrimPrefix(part.FormName(), "files["), "]")
+			parts[index] = part.FileName()
+			continue
+		}
+
+		// The interaction callbacks nest the message in data
+		var payload struct {
+			Attachments []*Attachment `json:"attachments"`
+			Data        *struct {
+				Attachments []*Attachment `json:"attachments"`
+			} `json:"data"`
+		}
+
+		if err := json.NewDecoder(part).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+
+		listed := payload.Attachments
+		if payload.Data != nil {
+			listed = payload.Data.Attachments
+		}
+
+		for _, attachment := range listed {
+			if _, ok := attachments[string(attachment.ID)]; ok {
+				t.Errorf("attachment %s listed twice", attachment.ID)
+			}
+
+			attachments[string(attachment.ID)] = attachment.Filename
+		}
+	}
+}
+
+func TestMultipartAttachmentIDs(t *testing.T) {
+	var body []byte
+	var contentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"9"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	// kept Existing attachment the edits keep next to the new files
+	kept := []*Attachment{{ID: "1100000000000000001", Filename: "old.png"}}
+	// described Attachment listing the file 1 with a description, replacing the generated one
+	described := []*Attachment{{ID: "1", Filename: "SPOILER_data.json", Description: "Raw data"}}
+
+	tests := []struct {
+		name string
+		send func(ctx context.Context) error
+		// kept IDs of the listed attachments without a file part
+		kept []string
+	}{
+		{"interaction response", func(ctx context.Context) error {
+			_, err := client.CreateInteractionResponse(ctx, "5", "token", &InteractionResponse{Type: ChannelMessageWithSourceResponse,
+				Data: &InteractionCallbackData{Content: "report", Files: goldenFiles(), Attachments: described}}, false)
+			return err
+		}, nil},
+		{"follow-up", func(ctx context.Context) error {
+			_, err := client.CreateFollowUpMessage(ctx, "1", "token", &WebhookEdit{Content: "report", Files: goldenFiles()})
+			return err
+		}, nil},
+		{"original edit keeping an attachment", func(ctx context.Context) error {
+			_, err := client.EditOriginalInteractionResponse(ctx, "1", "token", &WebhookEdit{Files: goldenFiles()[:2], Attachments: kept})
+			return err
+		}, []string{"1100000000000000001"}},
+		{"channel message", func(ctx context.Context) error {
+			_, err := client.CreateMessage(ctx, "3", &MessageCreate{Content: "report", Files: goldenFiles(), Attachments: described})
+			return err
+		}, nil},
+		{"message edit keeping an attachment", func(ctx context.Context) error {
+			_, err := client.EditMessage(ctx, "3", "7", &MessageEdit{Files: goldenFiles()[2:], Attachments: kept})
+			return err
+		}, []string{"1100000000000000001"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, contentType = nil, ""
+
+			if err := test.send(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			attachments, parts := multipartAttachments(t, body, contentType)
+			if len(parts) == 0 {
+				t.Fatalf("no file part in %q", body)
+			}
+
+			for index, filename := range parts {
+				if listed, ok := attachments[index]; !ok || listed != filename {
+					t.Errorf("part files[%s] of %q listed as %q, want the attachment %s of the same filename", index, filename, listed, index)
+				}
+			}
+
+			var unmatched []string
+			for id := range attachments {
+				if _, ok := parts[id]; !ok {
+					unmatched = append(unmatched, id)
+				}
+			}
+
+			sort.Strings(unmatched)
+			if !reflect.DeepEqual(unmatched, test.kept) {
+				t.Errorf("attachments %v without a file part, want %v", unmatched, test.kept)
+			}
+		})
+	}
+}
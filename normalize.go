@@ -0,0 +1,159 @@
+package httpcord
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringNormalization Cleanup of the string options applied before the handler runs, see CommandRoute.NormalizeStrings
+// and ConnectionOptions.NormalizeStrings. Invalid UTF-8 is always replaced by U+FFFD, then in order: Strip, Normalize
+// and the length limits
+type StringNormalization struct {
+	// Strip reports the characters to remove, like StripInvisible (Nothing is removed when nil)
+	Strip func(r rune) bool
+	// Normalize rewrites the stripped value, like norm.NFC.String of golang.org/x/text (Kept as is when nil)
+	Normalize func(s string) string
+	// MaxLength is the maximum number of characters of the string options not in MaxLengths (Unlimited when 0)
+	MaxLength int
+	// MaxLengths are the maximum number of characters per option name
+	MaxLengths map[string]int
+	// Truncate cuts the values over their maximum length, otherwise the interaction gets the ErrorReply of a
+	// StringLengthError and the handler does not run
+	Truncate bool
+}
+
+// StringLengthError A string option is over the maximum length of its StringNormalization
+type StringLengthError struct {
+	Option string
+	Length int
+	Max    int
+}
+
+func (e *StringLengthError) Error() string {
+	return fmt.Sprintf("option %s has %d characters, the limit is %d", e.Option, e.Length, e.Max)
+}
+
+var StringTooLongMessages = Dictionary{
+	EnglishUSLocale:    "The option %s can be at most %d characters long.",
+	EnglishGBLocale:    "The option %s can be at most %d characters long.",
+	PortugueseBRLocale: "A opção %s pode ter no máximo %d caracteres.",
+	SpanishESLocale:    "La opción %s puede tener como máximo %d caracteres.",
+	FrenchLocale:       "L'option %s peut contenir au maximum %d caractères.",
+	GermanLocale:       "Die Option %s darf höchstens %d Zeichen lang sein.",
+}
+
+// StripInvisible Control characters other than newlines and tabs, and the invisible format characters like the
+// zero-width spaces and the bidirectional overrides. The zero-width joiner of emoji sequences is removed too
+func StripInvisible(r rune) bool {
+	if r == '\n' || r == '\t' {
+		return false
+	}
+
+	return unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Cf, r)
+}
+
+// NormalizeStrings Clean the string options with the normalization before the handler runs,
+// replaces ConnectionOptions.NormalizeStrings for this command
+func (r *CommandRoute) NormalizeStrings(normalization *StringNormalization) *CommandRoute {
+	r.Normalization = normalization
+	return r
+}
+
+// maxLength Maximum number of characters of the option, 0 when unlimited
+func (n *StringNormalization) maxLength(option string) int {
+	if max, ok := n.MaxLengths[option]; ok {
+		return max
+	}
+
+	return n.MaxLength
+}
+
+// value Normalized value of the option, StringLengthError when too long and not truncated
+func (n *StringNormalization) value(option, value string) (string, error) {
+	value = strings.ToValidUTF8(value, string(utf8.RuneError))
+
+	if n.Strip != nil {
+		value = strings.Map(func(r rune) rune {
+			if n.Strip(r) {
+				return -1
+			}
+
+			return r
+		}, value)
+	}
+
+	if n.Normalize != nil {
+		value = n.Normalize(value)
+	}
+
+	max := n.maxLength(option)
+	if length := utf8.RuneCountInString(value); max > 0 && length > max {
+		if !n.Truncate {
+			return "", &StringLengthError{Option: option, Length: length, Max: max}
+		}
+
+		value = string([]rune(value)[:max])
+	}
+
+	return value, nil
+}
+
+// options Copy of the options with the string values normalized, subcommand options included
+func (n *StringNormalization) options(options []ApplicationCommandOption) ([]ApplicationCommandOption, error) {
+	if len(options) == 0 {
+		return options, nil
+	}
+
+	normalized := make([]ApplicationCommandOption, len(options))
+	copy(normalized, options)
+
+	for i := range normalized {
+		option := &normalized[i]
+
+		switch option.Type {
+		case SubCommandApplicationCommandOptionType, SubCommandGroupApplicationCommandOptionType:
+			nested, err := n.options(option.Options)
+			if err != nil {
+				return nil, err
+			}
+
+			option.Options = nested
+		case StringApplicationCommandOptionType:
+			value, ok := option.Value.(string)
+			if !ok {
+				continue
+			}
+
+			value, err := n.value(option.Name, value)
+			if err != nil {
+				return nil, err
+			}
+
+			option.Value = value
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeStrings Replace the string options of the interaction with their normalized values. The option index
+// is rebuilt since the middlewares may have built it with the raw values
+func (ctx *ConnectionContext) normalizeStrings(normalization *StringNormalization) error {
+	data := ctx.Interaction.ApplicationCommandData()
+
+	options, err := normalization.options(data.Options)
+	if err != nil {
+		return err
+	}
+
+	data.Options = options
+	ctx.Interaction.Data = data
+
+	lazy := &ctx.state.options
+	lazy.once.Do(func() {})
+	lazy.index = newCommandOptionIndex(options)
+
+	return nil
+}
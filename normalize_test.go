@@ -0,0 +1,150 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeStringsRebuildsOptionIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []ApplicationCommandOption
+		prebuilt bool
+		want     string
+	}{
+		{
+			name:    "top level option",
+			options: []ApplicationCommandOption{{Type: StringApplicationCommandOptionType, Name: "reason", Value: "  spam  "}},
+			want:    "spam",
+		},
+		{
+			name:     "index built by a middleware",
+			options:  []ApplicationCommandOption{{Type: StringApplicationCommandOptionType, Name: "reason", Value: "  spam  "}},
+			prebuilt: true,
+			want:     "spam",
+		},
+		{
+			name: "subcommand option built by a middleware",
+			options: []ApplicationCommandOption{{Type: SubCommandApplicationCommandOptionType, Name: "add", Options: []ApplicationCommandOption{
+				{Type: StringApplicationCommandOptionType, Name: "reason", Value: "\tspam\n"},
+			}}},
+			prebuilt: true,
+			want:     "spam",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newCommandRouter()
+			normalization := &StringNormalization{Normalize: strings.TrimSpace}
+
+			var got string
+			router.add(&CommandRoute{Name: "ban", Normalization: normalization, Handler: func(ctx ConnectionContext) {
+				got, _ = ctx.StringOption("reason")
+			}})
+
+			ctx := ConnectionContext{
+				Interaction: Interaction{Type: ApplicationCommandInteraction, Data: ApplicationCommandInteractionData{Name: "ban", Options: test.options}},
+				options:     &ConnectionOptions{},
+				state:       &interactionState{},
+			}
+
+			if test.prebuilt {
+				ctx.StringOption("reason")
+			}
+
+			if !router.dispatch(ctx) {
+				t.Fatal("the command was not dispatched")
+			}
+
+			if got != test.want {
+				t.Errorf("reason = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAdversarialStrings(t *testing.T) {
+	option := func(value string) string {
+		return fmt.Sprintf(`{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"%s"}]}`, value)
+	}
+
+	nested := func(depth int) string {
+		options := `{"type":3,"name":"reason","value":"deep"}`
+		for i := 0; i < depth; i++ {
+			options = fmt.Sprintf(`{"type":2,"name":"group","options":[%s]}`, options)
+		}
+
+		return fmt.Sprintf(`{"id":"5","name":"config","type":1,"options":[%s]}`, options)
+	}
+
+	command := func(data string) []byte {
+		return interactionBody(ApplicationCommandInteraction, data)
+	}
+
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+		body    []byte
+		// reason Value of the reason option read by the handler, none when the handler must not run
+		reason string
+		ran    bool
+		// reply Part of the reply content
+		reply string
+	}{
+		{"invalid UTF-8", ConnectionOptions{NormalizeStrings: &StringNormalization{}}, command(option("sp\xffam")), "sp\ufffdam", true, ""},
+		{"lone surrogate as WTF-8", ConnectionOptions{NormalizeStrings: &StringNormalization{}}, command(option("sp\xed\xa0\x80am")),
+			"sp\ufffd\ufffd\ufffdam", true, ""},
+		{"lone surrogate escaped", ConnectionOptions{NormalizeStrings: &StringNormalization{}}, command(option(`sp\ud800am`)), "sp\ufffdam", true, ""},
+		{"RTL override", ConnectionOptions{NormalizeStrings: &StringNormalization{Strip: StripInvisible}}, command(option("\u202espam")), "spam", true, ""},
+		{"zero-width characters", ConnectionOptions{NormalizeStrings: &StringNormalization{Strip: StripInvisible}}, command(option("s\u200bp\u200dam\ufeff")),
+			"spam", true, ""},
+		{"combining characters over the limit", ConnectionOptions{NormalizeStrings: &StringNormalization{MaxLength: 10}},
+			command(option(strings.Repeat("e\u0301", 6))), "", false, "at most 10 characters"},
+		{"combining characters truncated", ConnectionOptions{NormalizeStrings: &StringNormalization{MaxLength: 3, Truncate: true}},
+			command(option(strings.Repeat("e\u0301", 6))), "e\u0301e", true, ""},
+		{"nested options", ConnectionOptions{NormalizeStrings: &StringNormalization{Normalize: strings.ToUpper}}, command(nested(1)),
+			"DEEP", true, ""},
+		{"deeply nested options", ConnectionOptions{NormalizeStrings: &StringNormalization{Normalize: strings.ToUpper}}, command(nested(100)),
+			"DEEP", true, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := test.options
+			options.Logger = NopLogger
+
+			conn, sign := signedConnection(t, options)
+
+			var ran bool
+			var reason string
+
+			handler := func(ctx ConnectionContext) {
+				ran = true
+				reason, _ = ctx.StringOption("reason")
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"})
+			}
+
+			conn.Command("ban", handler)
+			conn.Command("config group", handler)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(test.body))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %.200s", w.Code, w.Body)
+			}
+
+			if ran != test.ran || reason != test.reason {
+				t.Errorf("handler ran = %v with %q, want %v with %q", ran, reason, test.ran, test.reason)
+			}
+
+			if !strings.Contains(w.Body.String(), test.reply) {
+				t.Errorf("reply %.200s, want it to contain %q", w.Body, test.reply)
+			}
+		})
+	}
+}
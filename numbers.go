@@ -0,0 +1,29 @@
+package httpcord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidSnowflake The number is not an unsigned 64 bits integer
+var ErrInvalidSnowflake = errors.New("httpcord: invalid snowflake")
+
+// DecodeJSON Decode the JSON keeping the numbers of interface{} values as json.Number, so IDs above 2^53
+// survive a map[string]interface{} round trip. Incoming interactions and RestClient.Do responses are decoded this way
+func DecodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+// NumberToSnowflake Snowflake of a number decoded by DecodeJSON, fractions, exponents and negative numbers are refused
+func NumberToSnowflake(n json.Number) (Snowflake, error) {
+	if _, err := strconv.ParseUint(n.String(), 10, 64); err != nil {
+		return "", ErrInvalidSnowflake
+	}
+
+	return Snowflake(n.String()), nil
+}
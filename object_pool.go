@@ -0,0 +1,210 @@
+package httpcord
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// poisonByte Fills the released buffers with ConnectionOptions.PoolPoison, 0xDB is not valid UTF-8 so a use after
+// release stands out in the logs and fails JSON decoding
+const poisonByte = 0xDB
+
+// PoisonedToken Token of the released decode targets with ConnectionOptions.PoolPoison
+const PoisonedToken = "httpcord: use after release"
+
+// requestObjects Request body and decode target of a request reused with ConnectionOptions.PoolObjects, released
+// once the handlers and the background continuations holding them are done
+type requestObjects struct {
+	body   bytes.Buffer
+	raw    APIInteraction
+	refs   int32
+	poison bool
+	// interaction shares the nested objects of the handlers Interaction, kept to poison them with PoolPoison
+	interaction Interaction
+}
+
+var (
+	requestObjectsPool = sync.Pool{New: func() interface{} { return &requestObjects{} }}
+	encodeBufferPool   = sync.Pool{New: func() interface{} { return &bytes.Buffer{} }}
+)
+
+// acquireRequestObjects Objects of a new request held once, nil without pooling
+func acquireRequestObjects(pool, poison bool) *requestObjects {
+	if !pool {
+		return nil
+	}
+
+	objects := requestObjectsPool.Get().(*requestObjects)
+	objects.refs = 1
+	objects.poison = poison
+
+	return objects
+}
+
+// readBody Read the request body, in the pooled buffer when objects is not nil
+func (o *requestObjects) readBody(r io.Reader) ([]byte, error) {
+	if o == nil {
+		return io.ReadAll(r)
+	}
+
+	if _, err := o.body.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	return o.body.Bytes(), nil
+}
+
+// decodeTarget APIInteraction the body is decoded into, zeroed when pooled
+func (o *requestObjects) decodeTarget() *APIInteraction {
+	if o == nil {
+		return &APIInteraction{}
+	}
+
+	return &o.raw
+}
+
+// track Keep the resolved interaction to poison its nested objects on release, only with PoolPoison
+func (o *requestObjects) track(interaction Interaction) {
+	if o != nil && o.poison {
+		o.interaction = interaction
+	}
+}
+
+// hold Keep the objects for a background continuation, release them when it is done
+func (o *requestObjects) hold() {
+	if o != nil {
+		atomic.AddInt32(&o.refs, 1)
+	}
+}
+
+// release Return the objects to the pool after the last holder, poisoned and dropped instead with PoolPoison
+func (o *requestObjects) release() {
+	if o == nil || atomic.AddInt32(&o.refs, -1) != 0 {
+		return
+	}
+
+	if o.poison {
+		body := o.body.Bytes()
+		for i := range body {
+			body[i] = poisonByte
+		}
+
+		o.raw = APIInteraction{ID: PoisonedToken, Token: PoisonedToken}
+		poisonInteraction(&o.interaction)
+		o.interaction = Interaction{}
+		return
+	}
+
+	o.body.Reset()
+	o.raw = APIInteraction{}
+	requestObjectsPool.Put(o)
+}
+
+// poisonInteraction Overwrite the objects the copies of the interaction share: the member, user, message, entitlements,
+// options and component values get PoisonedToken and the resolved maps are emptied. The resolved entities are not
+// overwritten since the EntityCache may keep them
+func poisonInteraction(interaction *Interaction) {
+	if member := interaction.Member; member != nil {
+		*member = Member{Nick: PoisonedToken, User: member.User}
+	}
+
+	if user := interaction.User; user != nil {
+		*user = User{ID: Snowflake(PoisonedToken), Username: PoisonedToken}
+	}
+
+	if message := interaction.Message; message != nil {
+		*message = Message{ID: Snowflake(PoisonedToken), Content: PoisonedToken}
+	}
+
+	for _, entitlement := range interaction.Entitlements {
+		if entitlement != nil {
+			*entitlement = Entitlement{ID: Snowflake(PoisonedToken)}
+		}
+	}
+
+	for i := range interaction.EntitlementSKUIDs {
+		interaction.EntitlementSKUIDs[i] = Snowflake(PoisonedToken)
+	}
+
+	switch data := interaction.Data.(type) {
+	case ApplicationCommandInteractionData:
+		poisonOptions(data.Options)
+		clearResolved(&data.Resolved)
+	case ComponentInteractionData:
+		for i := range data.Values {
+			data.Values[i] = PoisonedToken
+		}
+	case ModalSubmitInteractionData:
+		for _, row := range data.Components {
+			if row != nil {
+				*row = ActionRowComponent{}
+			}
+		}
+	}
+}
+
+func poisonOptions(options []ApplicationCommandOption) {
+	for i := range options {
+		poisonOptions(options[i].Options)
+		options[i] = ApplicationCommandOption{Name: PoisonedToken, Value: PoisonedToken, Options: options[i].Options}
+	}
+}
+
+func clearResolved(resolved *ResolvedData) {
+	for id := range resolved.Users {
+		delete(resolved.Users, id)
+	}
+
+	for id := range resolved.Members {
+		delete(resolved.Members, id)
+	}
+
+	for id := range resolved.Roles {
+		delete(resolved.Roles, id)
+	}
+
+	for id := range resolved.Channels {
+		delete(resolved.Channels, id)
+	}
+
+	for id := range resolved.Messages {
+		delete(resolved.Messages, id)
+	}
+
+	for id := range resolved.Attachments {
+		delete(resolved.Attachments, id)
+	}
+}
+
+// encodePooledResponse Like encodeInteractionResponse in a pooled buffer, done returns it once the body is written
+func encodePooledResponse(codec Codec, response *InteractionResponse, boundary BoundaryFunc, poison bool) (body []byte, contentType string, done func(), err error) {
+	if response.Data != nil && len(response.Data.Files) > 0 {
+		body, contentType, err = encodeInteractionResponse(codec, response, boundary)
+		return body, contentType, func() {}, err
+	}
+
+	buf := encodeBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	done = func() {
+		if poison {
+			b := buf.Bytes()
+			for i := range b {
+				b[i] = poisonByte
+			}
+
+			return
+		}
+
+		encodeBufferPool.Put(buf)
+	}
+
+	if err := encodeTo(codec, buf, response); err != nil {
+		done()
+		return nil, "", nil, err
+	}
+
+	return buf.Bytes(), "application/json", done, nil
+}
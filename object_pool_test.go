@@ -0,0 +1,134 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPoolPoisonInteraction(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+		body    []byte
+		check   func(interaction Interaction) []string
+	}{
+		{
+			name:    "command",
+			options: ConnectionOptions{PoolObjects: true, PoolPoison: true},
+			body:    commandBody(),
+			check: func(interaction Interaction) (unpoisoned []string) {
+				data := interaction.Data.(ApplicationCommandInteractionData)
+
+				if interaction.Member.Nick != PoisonedToken {
+					unpoisoned = append(unpoisoned, "member")
+				}
+
+				if interaction.User.Username != PoisonedToken {
+					unpoisoned = append(unpoisoned, "user")
+				}
+
+				if data.Options[0].Name != PoisonedToken {
+					unpoisoned = append(unpoisoned, "options")
+				}
+
+				if len(data.Resolved.Users) != 0 {
+					unpoisoned = append(unpoisoned, "resolved users")
+				}
+
+				return unpoisoned
+			},
+		},
+		{
+			name:    "component",
+			options: ConnectionOptions{PoolObjects: true, PoolPoison: true},
+			body: []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":3,"token":"token","version":1,"channel_id":"3",`+
+				`"user":{"id":"4","username":"mod"},"message":{"id":"7","content":"pick"},`+
+				`"data":{"custom_id":"pick","component_type":3,"values":["a"]}}`, nowSnowflake())),
+			check: func(interaction Interaction) (unpoisoned []string) {
+				if interaction.Message.Content != PoisonedToken {
+					unpoisoned = append(unpoisoned, "message")
+				}
+
+				if interaction.ComponentData().Values[0] != PoisonedToken {
+					unpoisoned = append(unpoisoned, "values")
+				}
+
+				return unpoisoned
+			},
+		},
+		{
+			name:    "pooled without poison",
+			options: ConnectionOptions{PoolObjects: true},
+			body:    commandBody(),
+			check: func(interaction Interaction) (poisoned []string) {
+				data := interaction.Data.(ApplicationCommandInteractionData)
+
+				if interaction.Member.Nick != "moderator" || data.Options[0].Name != "user" || len(data.Resolved.Users) != 1 {
+					poisoned = append(poisoned, "interaction")
+				}
+
+				return poisoned
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, test.options)
+
+			var kept Interaction
+			conn.AddInteractionHandler(func(ctx ConnectionContext) {
+				kept = ctx.Interaction
+			})
+
+			conn.ServeHTTP(httptest.NewRecorder(), sign(test.body))
+
+			if kept.Type == 0 {
+				t.Fatal("the handler did not run")
+			}
+
+			if wrong := test.check(kept); len(wrong) > 0 {
+				t.Errorf("after release: %v", wrong)
+			}
+		})
+	}
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		options ConnectionOptions
+	}{
+		{"unpooled", ConnectionOptions{}},
+		{"pooled", ConnectionOptions{PoolObjects: true}},
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			conn, sign := signedConnection(b, benchmark.options)
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			body := commandBody()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				// Signed outside the measure so the timestamps stay fresh
+				b.StopTimer()
+				r, w := sign(body), httptest.NewRecorder()
+				b.StartTimer()
+
+				conn.ServeHTTP(w, r)
+
+				if w.Code != http.StatusOK {
+					b.Fatalf("status %d: %s", w.Code, w.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package httpcord
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrMissingOption The option is missing from the interaction or its data was not resolved
+var ErrMissingOption = errors.New("httpcord: missing option")
+
+// commandOptionIndex Options of the interaction by name, built on first use
+type commandOptionIndex struct {
+	// path is the invoked subcommand group and subcommand names
+	path []string
+	leaf map[string]*ApplicationCommandOption
+	// tree has the options of every subcommand, the shallowest wins on duplicated names
+	tree map[string]*ApplicationCommandOption
+}
+
+type lazyOptionIndex struct {
+	once  sync.Once
+	index *commandOptionIndex
+}
+
+func newCommandOptionIndex(options []ApplicationCommandOption) *commandOptionIndex {
+	index := &commandOptionIndex{
+		leaf: make(map[string]*ApplicationCommandOption),
+		tree: make(map[string]*ApplicationCommandOption),
+	}
+
+	for len(options) == 1 && isSubCommandOption(options[0]) {
+		index.path = append(index.path, options[0].Name)
+		options = options[0].Options
+	}
+
+	for i := range options {
+		if !isSubCommandOption(options[i]) {
+			index.leaf[options[i].Name] = &options[i]
+		}
+	}
+
+	// Breadth first so options closer to the root win
+	level := options
+	for len(level) > 0 {
+		var next []ApplicationCommandOption
+
+		for i := range level {
+			if isSubCommandOption(level[i]) {
+				next = append(next, level[i].Options...)
+				continue
+			}
+
+			if _, ok := index.tree[level[i].Name]; !ok {
+				index.tree[level[i].Name] = &level[i]
+			}
+		}
+
+		level = next
+	}
+
+	return index
+}
+
+func isSubCommandOption(option ApplicationCommandOption) bool {
+	return option.Type == SubCommandApplicationCommandOptionType || option.Type == SubCommandGroupApplicationCommandOptionType
+}
+
+// optionIndex Index of the interaction options, nil for interactions without options
+func (ctx *ConnectionContext) optionIndex() *commandOptionIndex {
+	if ctx.Interaction.Type != ApplicationCommandInteraction && ctx.Interaction.Type != AutoCompleteInteraction {
+		return nil
+	}
+
+	lazy := &ctx.state.options
+	lazy.once.Do(func() {
+		lazy.index = newCommandOptionIndex(ctx.Interaction.ApplicationCommandData().Options)
+	})
+
+	return lazy.index
+}
+
+// Option Option of the invoked subcommand. Handlers not routed to a command, like AddInteractionHandler,
+// look the name up in every subcommand
+func (ctx *ConnectionContext) Option(name string) (*ApplicationCommandOption, bool) {
+	index := ctx.optionIndex()
+	if index == nil {
+		return nil, false
+	}
+
+	var option *ApplicationCommandOption
+	if ctx.routed {
+		option = index.leaf[name]
+	} else {
+		option = index.tree[name]
+	}
+
+	return option, option != nil
+}
+
+// OptionAt Option of the subcommand path like []string{"config", "set"}, false when the path was not invoked
+func (ctx *ConnectionContext) OptionAt(path []string, name string) (*ApplicationCommandOption, bool) {
+	index := ctx.optionIndex()
+	if index == nil || len(path) != len(index.path) {
+		return nil, false
+	}
+
+	for i := range path {
+		if path[i] != index.path[i] {
+			return nil, false
+		}
+	}
+
+	option, ok := index.leaf[name]
+	return option, ok
+}
+
+// StringOption Value of the string option, false when missing
+func (ctx *ConnectionContext) StringOption(name string) (string, bool) {
+	option, ok := ctx.Option(name)
+	if !ok {
+		return "", false
+	}
+
+	value, ok := option.Value.(string)
+	return value, ok
+}
+
+// IntOption Value of the integer option, false when missing
+func (ctx *ConnectionContext) IntOption(name string) (int, bool) {
+	value, ok := ctx.NumberOption(name)
+	return int(value), ok
+}
+
+// NumberOption Value of the number or integer option, false when missing
+func (ctx *ConnectionContext) NumberOption(name string) (float64, bool) {
+	option, ok := ctx.Option(name)
+	if !ok {
+		return 0, false
+	}
+
+	value, ok := option.Value.(float64)
+	return value, ok
+}
+
+// BoolOption Value of the boolean option, false when missing
+func (ctx *ConnectionContext) BoolOption(name string) (value bool, ok bool) {
+	option, found := ctx.Option(name)
+	if !found {
+		return false, false
+	}
+
+	value, ok = option.Value.(bool)
+	return value, ok
+}
+
+// snowflakeOption ID of the option and the resolved data of the interaction
+func (ctx *ConnectionContext) snowflakeOption(name string) (Snowflake, *ResolvedData, bool) {
+	option, ok := ctx.Option(name)
+	if !ok {
+		return "", nil, false
+	}
+
+	id, ok := option.Value.(string)
+	if !ok {
+		return "", nil, false
+	}
+
+	data := ctx.Interaction.ApplicationCommandData()
+	return Snowflake(id), &data.Resolved, true
+}
+
+// UserOption User of the user or mentionable option, false when missing or not resolved
+func (ctx *ConnectionContext) UserOption(name string) (*User, bool) {
+	id, resolved, ok := ctx.snowflakeOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	user, ok := resolved.Users[id]
+	return user, ok && user != nil
+}
+
+// MemberOption Member of the user option with its User, false outside guilds or when not resolved
+func (ctx *ConnectionContext) MemberOption(name string) (*Member, bool) {
+	id, resolved, ok := ctx.snowflakeOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	return resolvedMember(resolved, id)
+}
+
+// resolvedMember Resolved member of the ID with its User
+func resolvedMember(resolved *ResolvedData, id Snowflake) (*Member, bool) {
+	member, ok := resolved.Members[id]
+	if !ok || member == nil {
+		return nil, false
+	}
+
+	// Resolved members have no user, it is in the resolved users
+	if member.User == nil {
+		withUser := *member
+		withUser.User = resolved.Users[id]
+		member = &withUser
+	}
+
+	return member, true
+}
+
+// ChannelOption Partial channel of the channel option, false when missing or not resolved
+func (ctx *ConnectionContext) ChannelOption(name string) (*Channel, bool) {
+	id, resolved, ok := ctx.snowflakeOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	channel, ok := resolved.Channels[id]
+	return channel, ok && channel != nil
+}
+
+// RoleOption Role of the role or mentionable option, false when missing or not resolved
+func (ctx *ConnectionContext) RoleOption(name string) (*Role, bool) {
+	id, resolved, ok := ctx.snowflakeOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	role, ok := resolved.Roles[id]
+	return role, ok && role != nil
+}
+
+// AttachmentOption Uploaded file of the attachment option, false when missing or not resolved
+func (ctx *ConnectionContext) AttachmentOption(name string) (*Attachment, bool) {
+	id, resolved, ok := ctx.snowflakeOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	attachment, ok := resolved.Attachments[id]
+	return attachment, ok && attachment != nil
+}
@@ -0,0 +1,57 @@
+package httpcord_test
+
+import (
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestOptionAt(t *testing.T) {
+	set := httpcordtest.NewCommandInteraction("config", httpcordtest.Subcommand("set", httpcordtest.StringOption("key", "prefix")))
+	get := httpcordtest.NewCommandInteraction("config", httpcordtest.Subcommand("get", httpcordtest.StringOption("key", "locale")))
+	grouped := httpcordtest.NewCommandInteraction("admin",
+		httpcordtest.SubcommandGroup("config", httpcordtest.Subcommand("set", httpcordtest.StringOption("key", "channel"))))
+
+	tests := []struct {
+		name        string
+		interaction *httpcord.Interaction
+		path        []string
+		option      string
+		// value Value of the option, empty when OptionAt reports it missing
+		value string
+	}{
+		{"invoked subcommand", set, []string{"set"}, "key", "prefix"},
+		{"sibling of the invoked subcommand", set, []string{"get"}, "key", ""},
+		{"other invoked sibling", get, []string{"get"}, "key", "locale"},
+		{"sibling of the other invoked subcommand", get, []string{"set"}, "key", ""},
+		{"missing option of the invoked subcommand", set, []string{"set"}, "value", ""},
+		{"path of the command root", set, nil, "key", ""},
+		{"grouped subcommand", grouped, []string{"config", "set"}, "key", "channel"},
+		{"subcommand of a sibling group", grouped, []string{"roles", "set"}, "key", ""},
+		{"sibling subcommand in the group", grouped, []string{"config", "get"}, "key", ""},
+		{"group without its subcommand", grouped, []string{"config"}, "key", ""},
+		{"subcommand without its group", grouped, []string{"set"}, "key", ""},
+		{"path longer than the invoked one", grouped, []string{"config", "set", "key"}, "key", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, recorder := httpcordtest.NewContext(test.interaction)
+			defer recorder.Finish()
+
+			option, ok := ctx.OptionAt(test.path, test.option)
+			if test.value == "" {
+				if ok {
+					t.Errorf("OptionAt(%v, %q) = %+v, want it missing", test.path, test.option, option)
+				}
+
+				return
+			}
+
+			if !ok || option.Value != test.value {
+				t.Errorf("OptionAt(%v, %q) = %+v, %v, want %q", test.path, test.option, option, ok, test.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrOverflowFiles Responses with files are never split, the attachments could not be distributed reliably
+	ErrOverflowFiles = errors.New("httpcord: responses with files can not be split")
+	// ErrEmbedTooLarge A single embed exceeds MaxEmbedsCharacters, embeds are never split
+	ErrEmbedTooLarge = errors.New("httpcord: embed exceeds the message characters limit")
+	// ErrLineTooLong A content line exceeds MaxContentLength, content is only split on line boundaries
+	ErrLineTooLong = errors.New("httpcord: content line exceeds the message length limit")
+)
+
+type RespondOption func(o *respondOptions)
+
+type respondOptions struct {
+	overflow  bool
+	plainText func(ctx ConnectionContext) bool
+}
+
+// WithOverflow Split payloads over the message limits across the initial response and follow-ups (See SplitCallbackData)
+func WithOverflow() RespondOption {
+	return func(o *respondOptions) {
+		o.overflow = true
+	}
+}
+
+// WithPlainTextFallback Send the embeds as plain content (See Embed.PlainText) when predicate returns true,
+// for users or guilds preferring compact messages
+func WithPlainTextFallback(predicate func(ctx ConnectionContext) bool) RespondOption {
+	return func(o *respondOptions) {
+		o.plainText = predicate
+	}
+}
+
+// ResponseDelivery Messages created by Respond with WithOverflow, follow-ups are sent after the initial response is delivered
+type ResponseDelivery struct {
+	done     chan struct{}
+	messages []*Message
+	err      error
+}
+
+// Wait Block until every message is sent, must not be called from the handler goroutine
+func (d *ResponseDelivery) Wait() ([]*Message, error) {
+	<-d.done
+	return d.messages, d.err
+}
+
+// Respond Send the initial response, see WithOverflow to split oversized payloads. Returns the error of the initial
+// response, no follow-up is sent then
+func (ctx *ConnectionContext) Respond(data *InteractionCallbackData, opts ...RespondOption) (*ResponseDelivery, error) {
+	var o respondOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.plainText != nil && o.plainText(*ctx) {
+		data = plainTextFallback(data)
+	}
+
+	parts := []*InteractionCallbackData{data}
+
+	if o.overflow {
+		var err error
+		if parts, err = SplitCallbackData(data); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx.stopProgress()
+
+	if err := ctx.ReplyInteraction(parts[0]); err != nil {
+		return nil, err
+	}
+
+	delivery := &ResponseDelivery{done: make(chan struct{})}
+
+	if !o.overflow {
+		close(delivery.done)
+		return delivery, nil
+	}
+
+	ctx.afterResponse(func() {
+		defer close(delivery.done)
+
+		original, err := ctx.originalResponse(context.Background())
+		if err != nil {
+			delivery.err = err
+			return
+		}
+
+		delivery.messages = append(delivery.messages, original)
+
+		for _, part := range parts[1:] {
+			message, err := ctx.createFollowUp(context.Background(), part.WebhookEdit())
+			if err != nil {
+				delivery.err = err
+				return
+			}
+
+			delivery.messages = append(delivery.messages, message)
+		}
+	})
+
+	return delivery, nil
+}
+
+// originalResponse Fetch the initial response, retrying while Discord has not processed it yet
+func (ctx *ConnectionContext) originalResponse(c context.Context) (message *Message, err error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		message, err = ctx.webhooks.GetOriginalInteractionResponse(c, ctx.Interaction.ApplicationID, ctx.Interaction.Token)
+
+		var apiErr *DiscordAPIError
+		if err == nil || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return
+		}
+
+		time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
+	}
+
+	return
+}
+
+// WebhookEdit Convert the callback data to a follow-up or edit payload
+func (d *InteractionCallbackData) WebhookEdit() *WebhookEdit {
+	edit := &WebhookEdit{
+		Content:         d.Content,
+		Files:           d.Files,
+		Attachments:     d.Attachments,
+		AllowedMentions: d.AllowedMentions,
+		Flags:           d.Flags,
+		Poll:            d.Poll,
+	}
+
+	if len(d.Embeds) > 0 {
+		embeds := d.Embeds
+		edit.Embeds = &embeds
+	}
+
+	if len(d.Components) > 0 {
+		components := make([]AnyComponent, len(d.Components))
+		for i, row := range d.Components {
+			components[i] = row
+		}
+
+		edit.Components = &components
+	}
+
+	return edit
+}
+
+// SplitCallbackData Split the payload in messages respecting the content and embeds limits, keeping their order.
+// Content is split on line boundaries and goes first, every embed is kept whole and the components go in the last message.
+// Payloads with files are refused with ErrOverflowFiles
+func SplitCallbackData(data *InteractionCallbackData) ([]*InteractionCallbackData, error) {
+	if len(data.Files) > 0 {
+		return nil, ErrOverflowFiles
+	}
+
+	newPart := func(content string) *InteractionCallbackData {
+		return &InteractionCallbackData{
+			TTS:             data.TTS,
+			Content:         content,
+			AllowedMentions: data.AllowedMentions,
+			Flags:           data.Flags,
+		}
+	}
+
+	chunks, err := splitContent(data.Content, MaxContentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*InteractionCallbackData, 0, len(chunks)+1)
+	for _, chunk := range chunks {
+		parts = append(parts, newPart(chunk))
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, newPart(""))
+	}
+
+	last, characters := parts[len(parts)-1], 0
+
+	for _, embed := range data.Embeds {
+		length := embed.Length()
+
+		if length > MaxEmbedsCharacters {
+			return nil, ErrEmbedTooLarge
+		}
+
+		if len(last.Embeds) == MaxEmbedsPerMessage || characters+length > MaxEmbedsCharacters {
+			last, characters = newPart(""), 0
+			parts = append(parts, last)
+		}
+
+		last.Embeds = append(last.Embeds, embed)
+		characters += length
+	}
+
+	last.Components = data.Components
+	last.Poll = data.Poll
+	return parts, nil
+}
+
+// splitContent Group lines in chunks of at most limit characters
+func splitContent(content string, limit int) ([]string, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	var (
+		chunks  []string
+		current strings.Builder
+		length  int
+	)
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineLength := utf8.RuneCountInString(line)
+
+		if lineLength > limit && lineLength-1 == limit && strings.HasSuffix(line, "\n") {
+			line, lineLength = strings.TrimSuffix(line, "\n"), limit
+		}
+
+		if lineLength > limit {
+			return nil, ErrLineTooLong
+		}
+
+		if length+lineLength > limit {
+			chunks = append(chunks, strings.TrimSuffix(current.String(), "\n"))
+			current.Reset()
+			length = 0
+		}
+
+		current.WriteString(line)
+		length += lineLength
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, strings.TrimSuffix(current.String(), "\n"))
+	}
+
+	return chunks, nil
+}
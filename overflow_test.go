@@ -0,0 +1,198 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// overflowWebhooks Webhooks returning the initial response as the original message and recording the follow-ups
+type overflowWebhooks struct {
+	InteractionWebhooks
+
+	mu        sync.Mutex
+	original  *InteractionCallbackData
+	followUps []*WebhookEdit
+}
+
+func (w *overflowWebhooks) GetOriginalInteractionResponse(context.Context, Snowflake, string) (*Message, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return &Message{ID: "1", Content: w.original.Content}, nil
+}
+
+func (w *overflowWebhooks) CreateFollowUpMessage(_ context.Context, _ Snowflake, _ string, data *WebhookEdit) (*Message, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.followUps = append(w.followUps, data)
+	return &Message{ID: Snowflake(strings.Repeat("2", len(w.followUps))), Content: data.Content}, nil
+}
+
+// overflowContext Context recording its initial response in the webhooks
+func overflowContext(webhooks *overflowWebhooks) (*ConnectionContext, func()) {
+	interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, ApplicationID: "1", Token: "token"}
+
+	return NewContext(interaction, ContextConfig{
+		Respond: func(response *InteractionResponse) error {
+			webhooks.mu.Lock()
+			defer webhooks.mu.Unlock()
+
+			webhooks.original = response.Data
+			return nil
+		},
+		Webhooks: webhooks,
+	})
+}
+
+// lines Content of n lines of the width
+func lines(n, width int) string {
+	content := make([]string, n)
+	for i := range content {
+		content[i] = strings.Repeat("a", width)
+	}
+
+	return strings.Join(content, "\n")
+}
+
+func TestSplitCallbackData(t *testing.T) {
+	embed := func(length int) *Embed {
+		return &Embed{Description: strings.Repeat("e", length)}
+	}
+
+	rows := []*ActionRowComponent{{}}
+
+	tests := []struct {
+		name string
+		data *InteractionCallbackData
+		// contents Length of the content of each part
+		contents []int
+		// embeds Number of embeds of each part
+		embeds []int
+		err    error
+	}{
+		{"fits", &InteractionCallbackData{Content: "hello"}, []int{5}, []int{0}, nil},
+		{"exactly the limit", &InteractionCallbackData{Content: strings.Repeat("a", MaxContentLength)}, []int{MaxContentLength}, []int{0}, nil},
+		{"split on lines", &InteractionCallbackData{Content: lines(3, 999)}, []int{1999, 999}, []int{0, 0}, nil},
+		{"line too long", &InteractionCallbackData{Content: strings.Repeat("a", MaxContentLength+1)}, nil, nil, ErrLineTooLong},
+		{"too many embeds", &InteractionCallbackData{Embeds: []*Embed{embed(1), embed(1), embed(1), embed(1), embed(1), embed(1),
+			embed(1), embed(1), embed(1), embed(1), embed(1)}}, []int{0, 0}, []int{10, 1}, nil},
+		{"embed characters", &InteractionCallbackData{Content: "report", Embeds: []*Embed{embed(4000), embed(4000)}}, []int{6, 0}, []int{1, 1}, nil},
+		{"embed too large", &InteractionCallbackData{Embeds: []*Embed{embed(MaxEmbedsCharacters + 1)}}, nil, nil, ErrEmbedTooLarge},
+		{"files", &InteractionCallbackData{Content: "report", Files: []*DiscordFile{{Filename: "report.txt"}}}, nil, nil, ErrOverflowFiles},
+		{"components in the last part", &InteractionCallbackData{Content: lines(2, 1500), Components: rows}, []int{1500, 1500}, []int{0, 0}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := SplitCallbackData(test.data)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("SplitCallbackData() = %v, want %v", err, test.err)
+			}
+
+			if len(parts) != len(test.contents) {
+				t.Fatalf("%d parts, want %d", len(parts), len(test.contents))
+			}
+
+			for i, part := range parts {
+				if len(part.Content) != test.contents[i] || len(part.Embeds) != test.embeds[i] {
+					t.Errorf("part %d has %d characters and %d embeds, want %d and %d", i, len(part.Content), len(part.Embeds), test.contents[i], test.embeds[i])
+				}
+
+				if last := i == len(parts)-1; (part.Components != nil) != (last && test.data.Components != nil) {
+					t.Errorf("part %d has the components = %v", i, part.Components != nil)
+				}
+			}
+		})
+	}
+}
+
+func TestRespondOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		data *InteractionCallbackData
+		// finished The handler returned before calling Respond, like from a goroutine
+		finished bool
+		contents []int
+	}{
+		{"single message", &InteractionCallbackData{Content: "hello"}, false, []int{5}},
+		{"split in the handler", &InteractionCallbackData{Content: lines(5, 999)}, false, []int{1999, 1999, 999}},
+		{"split after the handler returned", &InteractionCallbackData{Content: lines(5, 999)}, true, []int{1999, 1999, 999}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webhooks := &overflowWebhooks{}
+			ctx, finish := overflowContext(webhooks)
+
+			if test.finished {
+				finish()
+			}
+
+			delivery, err := ctx.Respond(test.data, WithOverflow())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !test.finished {
+				finish()
+			}
+
+			done := make(chan struct{})
+
+			var messages []*Message
+			go func() {
+				defer close(done)
+				messages, err = delivery.Wait()
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Wait() blocked, the follow-ups were not sent")
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(messages) != len(test.contents) {
+				t.Fatalf("%d messages delivered, want %d", len(messages), len(test.contents))
+			}
+
+			for i, message := range messages {
+				if len(message.Content) != test.contents[i] {
+					t.Errorf("message %d has %d characters, want %d", i, len(message.Content), test.contents[i])
+				}
+			}
+
+			if messages[0].ID != "1" || len(webhooks.followUps) != len(test.contents)-1 {
+				t.Errorf("original %s and %d follow-ups, want the original first", messages[0].ID, len(webhooks.followUps))
+			}
+		})
+	}
+}
+
+func TestRespondFailure(t *testing.T) {
+	webhooks := &overflowWebhooks{}
+	ctx, finish := overflowContext(webhooks)
+
+	if err := ctx.ReplyInteraction(&InteractionCallbackData{Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, err := ctx.Respond(&InteractionCallbackData{Content: lines(3, 999)}, WithOverflow())
+	if !errors.Is(err, ErrAlreadyResponded) || delivery != nil {
+		t.Fatalf("Respond() = %v, %v, want ErrAlreadyResponded", delivery, err)
+	}
+
+	finish()
+
+	if len(webhooks.followUps) != 0 {
+		t.Errorf("%d follow-ups sent for a refused response", len(webhooks.followUps))
+	}
+}
@@ -0,0 +1,82 @@
+package httpcord
+
+import "context"
+
+// Paginator Pages of a REST list fetched one at a time, an empty page means there are no more entities
+type Paginator[T interface{}] interface {
+	// Next Fetch the next page
+	Next(ctx context.Context) ([]T, error)
+	// Done No page is left
+	Done() bool
+}
+
+// CursorPaginator Paginator of the lists ordered by ID, each page is fetched after the ID of the last entity
+type CursorPaginator[T interface{}] struct {
+	// fetch Page of at most limit entities after the ID
+	fetch func(ctx context.Context, after Snowflake, limit int) ([]T, error)
+	// id ID of the entity used as cursor, "" keeps the cursor
+	id    func(entity T) Snowflake
+	limit int
+	after Snowflake
+	done  bool
+}
+
+func newCursorPaginator[T interface{}](limit int, fetch func(ctx context.Context, after Snowflake, limit int) ([]T, error), id func(entity T) Snowflake) *CursorPaginator[T] {
+	return &CursorPaginator[T]{fetch: fetch, id: id, limit: limit, after: "0"}
+}
+
+// After Start after the ID instead of the first entity
+func (p *CursorPaginator[T]) After(id Snowflake) *CursorPaginator[T] {
+	p.after = id
+	return p
+}
+
+// Next Fetch the next page, a page shorter than the page size is the last one
+func (p *CursorPaginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	page, err := p.fetch(ctx, p.after, p.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page) < p.limit {
+		p.done = true
+	}
+
+	if len(page) > 0 {
+		if id := p.id(page[len(page)-1]); id != "" {
+			p.after = id
+		}
+	}
+
+	return page, nil
+}
+
+// Done No page is left
+func (p *CursorPaginator[T]) Done() bool {
+	return p.done
+}
+
+// All Fetch every remaining page
+func (p *CursorPaginator[T]) All(ctx context.Context) ([]T, error) {
+	return AllPages[T](ctx, p)
+}
+
+// AllPages Fetch every remaining page of the paginator, the entities fetched before an error are returned with it
+func AllPages[T interface{}](ctx context.Context, p Paginator[T]) ([]T, error) {
+	var all []T
+
+	for !p.Done() {
+		page, err := p.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+
+		all = append(all, page...)
+	}
+
+	return all, nil
+}
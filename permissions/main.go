@@ -1,7 +1,32 @@
 package permissions
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 type PermissionBit uint64
 
+// MarshalJSON Discord sends and expects permissions as strings
+func (p PermissionBit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(p), 10))
+}
+
+func (p *PermissionBit) UnmarshalJSON(data []byte) error {
+	var raw json.Number
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	bits, err := strconv.ParseUint(raw.String(), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*p = PermissionBit(bits)
+	return nil
+}
+
 func (p PermissionBit) Has(bits PermissionBit, checkAdmin bool) bool {
 	if checkAdmin {
 		return (p&bits) == bits || (p&Administrator) == Administrator
@@ -53,3 +78,6 @@ const (
 	StartEmbeddedActivities
 	ModerateMembers
 )
+
+// SendPolls Create polls, bits 41 to 48 are not listed
+const SendPolls PermissionBit = 1 << 49
@@ -0,0 +1,108 @@
+package httpcord
+
+type (
+	PluralCategory string
+	// PluralForms Message of every plural category of a locale
+	PluralForms map[PluralCategory]string
+	// PluralDictionary Plural forms per locale
+	PluralDictionary map[Locale]PluralForms
+)
+
+// Plural Categories
+
+const (
+	PluralOne   PluralCategory = "one"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// PluralRule Plural category of the integer n in the locale, following the CLDR rules for integers
+func PluralRule(locale Locale, n int) PluralCategory {
+	if n < 0 {
+		n = -n
+	}
+
+	mod10, mod100 := n%10, n%100
+
+	switch locale {
+	case RussianLocale, UkrainianLocale:
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case PolishLocale:
+		switch {
+		case n == 1:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case CroatianLocale:
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	case CzechLocale:
+		switch {
+		case n == 1:
+			return PluralOne
+		case n >= 2 && n <= 4:
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	case LithuanianLocale:
+		switch {
+		case mod10 == 1 && (mod100 < 11 || mod100 > 19):
+			return PluralOne
+		case mod10 >= 2 && (mod100 < 11 || mod100 > 19):
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	case RomanianLocale:
+		switch {
+		case n == 1:
+			return PluralOne
+		case n == 0 || (mod100 >= 1 && mod100 <= 19):
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	case FrenchLocale, PortugueseBRLocale, HindiLocale:
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+
+		return PluralOther
+	case ChineseCNLocale, ChineseTWLocale, JapaneseLocale, KoreanLocale, ThaiLocale, VietnameseLocale:
+		return PluralOther
+	}
+
+	if n == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// Get Form of the category, falling back to the other form
+func (f PluralForms) Get(category PluralCategory) (string, bool) {
+	if form, ok := f[category]; ok {
+		return form, true
+	}
+
+	form, ok := f[PluralOther]
+	return form, ok
+}
@@ -0,0 +1,86 @@
+package httpcord
+
+import "testing"
+
+func TestPluralRule(t *testing.T) {
+	values := []int{0, 1, 2, 5, 11, 12, 21, 22, 25, 101, 111, 112}
+
+	tests := []struct {
+		name    string
+		locales []Locale
+		want    []PluralCategory
+	}{
+		{"russian and ukrainian", []Locale{RussianLocale, UkrainianLocale},
+			[]PluralCategory{PluralMany, PluralOne, PluralFew, PluralMany, PluralMany, PluralMany, PluralOne, PluralFew, PluralMany, PluralOne, PluralMany, PluralMany}},
+		{"polish", []Locale{PolishLocale},
+			[]PluralCategory{PluralMany, PluralOne, PluralFew, PluralMany, PluralMany, PluralMany, PluralMany, PluralFew, PluralMany, PluralMany, PluralMany, PluralMany}},
+		{"czech", []Locale{CzechLocale},
+			[]PluralCategory{PluralOther, PluralOne, PluralFew, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther}},
+		{"croatian", []Locale{CroatianLocale},
+			[]PluralCategory{PluralOther, PluralOne, PluralFew, PluralOther, PluralOther, PluralOther, PluralOne, PluralFew, PluralOther, PluralOne, PluralOther, PluralOther}},
+		{"lithuanian", []Locale{LithuanianLocale},
+			[]PluralCategory{PluralOther, PluralOne, PluralFew, PluralFew, PluralOther, PluralOther, PluralOne, PluralFew, PluralFew, PluralOne, PluralOther, PluralOther}},
+		{"romanian", []Locale{RomanianLocale},
+			[]PluralCategory{PluralFew, PluralOne, PluralFew, PluralFew, PluralFew, PluralFew, PluralOther, PluralOther, PluralOther, PluralFew, PluralFew, PluralFew}},
+		{"zero is singular", []Locale{FrenchLocale, PortugueseBRLocale, HindiLocale},
+			[]PluralCategory{PluralOne, PluralOne, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther}},
+		{"two forms", []Locale{EnglishUSLocale, GermanLocale, DutchLocale, SpanishESLocale, ItalianLocale, SwedishLocale},
+			[]PluralCategory{PluralOther, PluralOne, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther}},
+		{"no plural", []Locale{JapaneseLocale, ChineseCNLocale, KoreanLocale},
+			[]PluralCategory{PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther, PluralOther}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, locale := range test.locales {
+				for i, n := range values {
+					if got := PluralRule(locale, n); got != test.want[i] {
+						t.Errorf("PluralRule(%s, %d) = %s, want %s", locale, n, got, test.want[i])
+					}
+
+					if got := PluralRule(locale, -n); got != test.want[i] {
+						t.Errorf("PluralRule(%s, %d) = %s, want %s", locale, -n, got, test.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTranslatorTPlural(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale).
+		AddPlural("deleted", PluralDictionary{
+			EnglishUSLocale: {PluralOne: "%d message deleted", PluralOther: "%d messages deleted"},
+			RussianLocale:   {PluralOne: "Удалено %d сообщение", PluralFew: "Удалено %d сообщения", PluralMany: "Удалено %d сообщений"},
+			PolishLocale:    {PluralOne: "Usunięto {n} wiadomość", PluralFew: "Usunięto {n} wiadomości", PluralMany: "Usunięto {n} wiadomości"},
+			CzechLocale:     {PluralOne: "Smazána %d zpráva", PluralOther: "Smazáno %d zpráv"},
+		})
+
+	tests := []struct {
+		name   string
+		key    string
+		n      int
+		locale Locale
+		args   []interface{}
+		want   string
+	}{
+		{"english one", "deleted", 1, EnglishUSLocale, []interface{}{1}, "1 message deleted"},
+		{"english other", "deleted", 5, EnglishUSLocale, []interface{}{5}, "5 messages deleted"},
+		{"russian one", "deleted", 21, RussianLocale, []interface{}{21}, "Удалено 21 сообщение"},
+		{"russian few", "deleted", 22, RussianLocale, []interface{}{22}, "Удалено 22 сообщения"},
+		{"russian many", "deleted", 11, RussianLocale, []interface{}{11}, "Удалено 11 сообщений"},
+		{"named arguments", "deleted", 25, PolishLocale, []interface{}{map[string]interface{}{"n": 25}}, "Usunięto 25 wiadomości"},
+		{"missing category uses other", "deleted", 2, CzechLocale, []interface{}{2}, "Smazáno 2 zpráv"},
+		{"missing locale uses the fallback rule", "deleted", 2, GermanLocale, []interface{}{2}, "2 messages deleted"},
+		{"fallback one", "deleted", 1, JapaneseLocale, []interface{}{1}, "1 message deleted"},
+		{"missing key", "banned", 2, EnglishUSLocale, []interface{}{2}, "banned"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := translator.TPlural(test.key, test.n, test.locale, test.args...); got != test.want {
+				t.Errorf("TPlural(%q, %d, %s) = %q, want %q", test.key, test.n, test.locale, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+
+	"httpcord/endpoints"
+)
+
+type PollLayoutType int
+
+// Poll Layout Types
+
+const (
+	DefaultPollLayout PollLayoutType = iota + 1
+)
+
+const (
+	// MaxPollAnswers Maximum answers of a poll
+	MaxPollAnswers = 10
+	// MaxPollDuration Maximum hours a poll is open
+	MaxPollDuration = 7 * 24
+	// MaxPollQuestionLength Maximum length of the question text
+	MaxPollQuestionLength = 300
+	// MaxPollAnswerTextLength Maximum length of the answer texts
+	MaxPollAnswerTextLength = 55
+	// MaxPollVotersLimit Maximum voters returned by a page of GetAnswerVoters
+	MaxPollVotersLimit = 100
+)
+
+var (
+	// ErrInvalidPoll The poll would be refused by Discord (See PollValidationError)
+	ErrInvalidPoll = errors.New("httpcord: invalid poll")
+	// ErrPollVotersLimit The limit is outside 1 to MaxPollVotersLimit
+	ErrPollVotersLimit = errors.New("httpcord: poll voters limit must be between 1 and 100")
+)
+
+// PollValidationError Field of a poll breaking the limits of Discord, see PollCreate.Validate
+type PollValidationError struct {
+	// Field is the path of the field like "question", "duration" or "answers.3.poll_media"
+	Field  string
+	Reason string
+}
+
+func (e *PollValidationError) Error() string {
+	return fmt.Sprintf("httpcord: poll %s %s", e.Field, e.Reason)
+}
+
+func (e *PollValidationError) Is(target error) bool {
+	return target == ErrInvalidPoll
+}
+
+// PollMedia Text of the question, text and emoji of the answers
+type PollMedia struct {
+	Text  string `json:"text,omitempty"`
+	Emoji *Emoji `json:"emoji,omitempty"`
+}
+
+type PollAnswer struct {
+	// AnswerID is set by Discord, from 1 in the order of the answers
+	AnswerID  int       `json:"answer_id,omitempty"`
+	PollMedia PollMedia `json:"poll_media"`
+}
+
+// PollCreate Poll sent with a message, see NewPoll
+type PollCreate struct {
+	Question PollMedia     `json:"question"`
+	Answers  []*PollAnswer `json:"answers"`
+	// Duration is the number of hours the poll is open, at most MaxPollDuration (Discord defaults to 24)
+	Duration         int            `json:"duration,omitempty"`
+	AllowMultiselect bool           `json:"allow_multiselect"`
+	LayoutType       PollLayoutType `json:"layout_type,omitempty"`
+}
+
+// Poll Poll of a received message
+type Poll struct {
+	Question         PollMedia      `json:"question"`
+	Answers          []*PollAnswer  `json:"answers"`
+	Expiry           *Time          `json:"expiry"`
+	AllowMultiselect bool           `json:"allow_multiselect"`
+	LayoutType       PollLayoutType `json:"layout_type"`
+	// Results are not sent by Discord in some messages, the counts are not exact until IsFinalized
+	Results *PollResults `json:"results,omitempty"`
+}
+
+type PollResults struct {
+	IsFinalized  bool               `json:"is_finalized"`
+	AnswerCounts []*PollAnswerCount `json:"answer_counts"`
+}
+
+type PollAnswerCount struct {
+	ID      int  `json:"id"`
+	Count   int  `json:"count"`
+	MeVoted bool `json:"me_voted"`
+}
+
+// NewPoll Poll open for the hours, add the answers with AddAnswer
+func NewPoll(question string, hours int) *PollCreate {
+	return &PollCreate{Question: PollMedia{Text: question}, Duration: hours, LayoutType: DefaultPollLayout}
+}
+
+// AddAnswer Add an answer, the emoji is optional
+func (p *PollCreate) AddAnswer(text string, emoji *Emoji) *PollCreate {
+	p.Answers = append(p.Answers, &PollAnswer{PollMedia: PollMedia{Text: text, Emoji: emoji}})
+	return p
+}
+
+// Multiselect Let the users vote for several answers
+func (p *PollCreate) Multiselect() *PollCreate {
+	p.AllowMultiselect = true
+	return p
+}
+
+// Validate Check the poll like Discord does: a question of at most MaxPollQuestionLength characters, 1 to
+// MaxPollAnswers answers with a text of at most MaxPollAnswerTextLength characters, emojis with an ID or a name and
+// a duration of at most MaxPollDuration hours. Returns the first PollValidationError
+func (p *PollCreate) Validate() error {
+	text := func(field, value string, max int) error {
+		switch length := utf8.RuneCountInString(value); {
+		case length == 0:
+			return &PollValidationError{Field: field, Reason: "is empty"}
+		case length > max:
+			return &PollValidationError{
+				Field:  field,
+				Reason: "is " + strconv.Itoa(length) + " characters, the limit is " + strconv.Itoa(max),
+			}
+		}
+
+		return nil
+	}
+
+	if err := text("question.text", p.Question.Text, MaxPollQuestionLength); err != nil {
+		return err
+	}
+
+	if len(p.Answers) == 0 || len(p.Answers) > MaxPollAnswers {
+		return &PollValidationError{
+			Field:  "answers",
+			Reason: "has " + strconv.Itoa(len(p.Answers)) + " answers, it must have 1 to " + strconv.Itoa(MaxPollAnswers),
+		}
+	}
+
+	for i, answer := range p.Answers {
+		field := "answers." + strconv.Itoa(i) + ".poll_media"
+
+		if answer == nil {
+			return &PollValidationError{Field: "answers." + strconv.Itoa(i), Reason: "is nil"}
+		}
+
+		if err := text(field+".text", answer.PollMedia.Text, MaxPollAnswerTextLength); err != nil {
+			return err
+		}
+
+		if emoji := answer.PollMedia.Emoji; emoji != nil && emoji.ID == "" && emoji.Name == "" {
+			return &PollValidationError{Field: field + ".emoji", Reason: "needs an ID or a name"}
+		}
+	}
+
+	if p.Duration < 0 || p.Duration > MaxPollDuration {
+		return &PollValidationError{
+			Field:  "duration",
+			Reason: "is " + strconv.Itoa(p.Duration) + " hours, the limit is " + strconv.Itoa(MaxPollDuration),
+		}
+	}
+
+	return nil
+}
+
+// GetAnswerVoters Users who voted for the answer, limit must be 1 to MaxPollVotersLimit. Pass the ID of the last
+// user as after for the next page, "" for the first one
+func (c *RestClient) GetAnswerVoters(ctx context.Context, channelID, messageID Snowflake, answerID int, after Snowflake, limit int) ([]*User, error) {
+	if limit < 1 || limit > MaxPollVotersLimit {
+		return nil, ErrPollVotersLimit
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(limit)}}
+	if after != "" {
+		query.Set("after", after.String())
+	}
+
+	var voters struct {
+		Users []*User `json:"users"`
+	}
+
+	err := c.Do(ctx, http.MethodGet, endpoints.PollAnswerVoters(channelID.String(), messageID.String(), strconv.Itoa(answerID)), nil, &voters,
+		WithQuery(query))
+	if err != nil {
+		return nil, err
+	}
+
+	return voters.Users, nil
+}
+
+// EndPoll Close the poll of a message sent by the application before its expiry, returns the message with the
+// final results
+func (c *RestClient) EndPoll(ctx context.Context, channelID, messageID Snowflake) (*Message, error) {
+	var message Message
+	if err := c.Do(ctx, http.MethodPost, endpoints.ExpirePoll(channelID.String(), messageID.String()), nil, &message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,204 @@
+package httpcord
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBackpressure The background workers are saturated, the work was not accepted
+var ErrBackpressure = errors.New("httpcord: background workers saturated")
+
+// GaugeCollector Optional MetricsCollector extension receiving gauges
+type GaugeCollector interface {
+	SetGauge(name string, labels map[string]string, value float64)
+}
+
+// ConnectionStats Background work of the connection, see Connection.Stats
+type ConnectionStats struct {
+	// Workers is ConnectionOptions.MaxWorkers, 0 when the work is not bounded
+	Workers int
+	Running int
+	Queued  int
+	// MaxQueueDepth is ConnectionOptions.MaxQueueDepth
+	MaxQueueDepth int
+	// Overdue work outlived its deadline without honouring its context, it no longer holds a worker but still runs
+	Overdue int
+	// Saturated every worker is busy, new work is queued
+	Saturated bool
+	// AverageTaskDuration moving average used to estimate when queued work starts
+	AverageTaskDuration time.Duration
+	// DispatchRunning and DispatchQueued are the interactions holding and waiting for a ConnectionOptions.DispatchWorkers
+	// worker, 0 when the dispatch is not bounded
+	DispatchRunning int
+	DispatchQueued  int
+}
+
+// workerPool Run the deferred work on at most workers goroutines, unbounded when workers is 0
+type workerPool struct {
+	mu       sync.Mutex
+	workers  int
+	maxQueue int
+	queue    []poolJob
+	running  int
+	overdue  int
+	average  time.Duration
+	now      func() time.Time
+	metrics  MetricsCollector
+}
+
+// poolJob Work holding its worker until it returns or its deadline, no deadline when zero
+type poolJob struct {
+	fn       func()
+	deadline time.Time
+}
+
+func newWorkerPool(workers, maxQueue int, metrics MetricsCollector) *workerPool {
+	return &workerPool{workers: workers, maxQueue: maxQueue, now: time.Now, metrics: metrics}
+}
+
+// submit Queue fn, refused with ErrBackpressure when the queue is full or fn would start after expiry.
+// The worker is released at deadline even if fn is still running
+func (p *workerPool) submit(fn func(), deadline, expiry time.Time) error {
+	job := poolJob{fn: fn, deadline: deadline}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.workers > 0 && p.running >= p.workers {
+		if p.maxQueue > 0 && len(p.queue) >= p.maxQueue {
+			return ErrBackpressure
+		}
+
+		if p.estimatedStart().After(expiry) {
+			return ErrBackpressure
+		}
+
+		p.queue = append(p.queue, job)
+		p.report()
+		return nil
+	}
+
+	p.running++
+	p.report()
+
+	go p.work(job)
+	return nil
+}
+
+// estimatedStart When work queued now would start, every worker takes queued work in order
+func (p *workerPool) estimatedStart() time.Time {
+	now := p.now()
+	if p.workers == 0 || p.running < p.workers {
+		return now
+	}
+
+	waves := len(p.queue)/p.workers + 1
+	return now.Add(time.Duration(waves) * p.average)
+}
+
+func (p *workerPool) work(job poolJob) {
+	for job.fn != nil {
+		started := p.now()
+		p.run(job)
+		elapsed := p.now().Sub(started)
+
+		p.mu.Lock()
+		p.record(elapsed)
+
+		job = poolJob{}
+		if len(p.queue) > 0 {
+			job, p.queue = p.queue[0], p.queue[1:]
+		} else {
+			p.running--
+		}
+
+		p.report()
+		p.mu.Unlock()
+	}
+}
+
+// run Panics are reported by the work itself, a worker never dies. Work still running at its deadline is counted
+// as overdue until it returns and the worker moves on
+func (p *workerPool) run(job poolJob) {
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		defer func() {
+			recover()
+		}()
+
+		job.fn()
+	}()
+
+	if job.deadline.IsZero() {
+		<-finished
+		return
+	}
+
+	timer := time.NewTimer(job.deadline.Sub(p.now()))
+	defer timer.Stop()
+
+	select {
+	case <-finished:
+		return
+	case <-timer.C:
+	}
+
+	p.mu.Lock()
+	p.overdue++
+	p.report()
+	p.mu.Unlock()
+
+	go func() {
+		<-finished
+
+		p.mu.Lock()
+		p.overdue--
+		p.report()
+		p.mu.Unlock()
+	}()
+}
+
+// record Exponential moving average of the task durations
+func (p *workerPool) record(elapsed time.Duration) {
+	if p.average == 0 {
+		p.average = elapsed
+		return
+	}
+
+	p.average = (p.average*4 + elapsed) / 5
+}
+
+func (p *workerPool) report() {
+	gauges, ok := p.metrics.(GaugeCollector)
+	if !ok {
+		return
+	}
+
+	gauges.SetGauge("httpcord_worker_queue_depth", nil, float64(len(p.queue)))
+	gauges.SetGauge("httpcord_workers_busy", nil, float64(p.running))
+	gauges.SetGauge("httpcord_workers_overdue", nil, float64(p.overdue))
+}
+
+func (p *workerPool) stats() ConnectionStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return ConnectionStats{
+		Workers:             p.workers,
+		Running:             p.running,
+		Queued:              len(p.queue),
+		Overdue:             p.overdue,
+		MaxQueueDepth:       p.maxQueue,
+		Saturated:           p.workers > 0 && p.running >= p.workers,
+		AverageTaskDuration: p.average,
+	}
+}
+
+// Stats Queue depth and saturation of the background workers running RunDeferred work and of the dispatch workers
+func (c *Connection) Stats() ConnectionStats {
+	stats := c.life.pool.stats()
+	stats.DispatchRunning, stats.DispatchQueued = c.life.dispatch.stats()
+	return stats
+}
@@ -0,0 +1,58 @@
+package httpcord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolReleasesOverdueWork(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		released bool
+	}{
+		{"no deadline", 0, false},
+		{"deadline", 10 * time.Millisecond, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool := newWorkerPool(1, 0, nil)
+
+			var deadline time.Time
+			if test.deadline > 0 {
+				deadline = time.Now().Add(test.deadline)
+			}
+
+			stuck := make(chan struct{})
+			defer close(stuck)
+
+			expiry := time.Now().Add(time.Minute)
+			if err := pool.submit(func() { <-stuck }, deadline, expiry); err != nil {
+				t.Fatal(err)
+			}
+
+			queued := make(chan struct{})
+			if err := pool.submit(func() { close(queued) }, time.Time{}, expiry); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case <-queued:
+				if !test.released {
+					t.Fatal("the queued work started while the worker was busy")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if test.released {
+					t.Fatal("the overdue work still holds the worker")
+				}
+
+				return
+			}
+
+			if overdue := pool.stats().Overdue; overdue != 1 {
+				t.Errorf("Overdue = %d, want 1", overdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package httpcord
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidSignature The request signature did not verify
+	ErrInvalidSignature = errors.New("httpcord: invalid request signature")
+	// ErrMissingSignature The X-Signature-Ed25519 or X-Signature-Timestamp header is missing
+	ErrMissingSignature = errors.New("httpcord: missing request signature")
+	// ErrStaleTimestamp The request was signed outside ConnectionOptions.MaxTimestampSkew, usually a replayed request
+	ErrStaleTimestamp = errors.New("httpcord: request timestamp outside the allowed skew")
+	// ErrRequiredHeader A header required by RequiredHeader is missing or has another value
+	ErrRequiredHeader = errors.New("httpcord: required header missing")
+	// ErrForbiddenHeader A header refused by ForbiddenHeader is present
+	ErrForbiddenHeader = errors.New("httpcord: forbidden header present")
+)
+
+// RequiredHeader Request predicate refusing requests without the header set to value, like a secret added by an edge proxy.
+// The value is compared in constant time
+func RequiredHeader(name, value string) func(r *http.Request) error {
+	return func(r *http.Request) error {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(name)), []byte(value)) != 1 {
+			return fmt.Errorf("%w: %s", ErrRequiredHeader, name)
+		}
+
+		return nil
+	}
+}
+
+// ForbiddenHeader Request predicate refusing requests with the header, like headers stripped by the CDN
+func ForbiddenHeader(name string) func(r *http.Request) error {
+	return func(r *http.Request) error {
+		if _, ok := r.Header[http.CanonicalHeaderKey(name)]; ok {
+			return fmt.Errorf("%w: %s", ErrForbiddenHeader, name)
+		}
+
+		return nil
+	}
+}
+
+// checkRequest Run the request predicates in order, the first error stops the checks
+func (o *ConnectionOptions) checkRequest(r *http.Request) error {
+	for _, predicate := range o.RequestPredicates {
+		if err := predicate(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *ConnectionOptions) verificationFailed(r *http.Request, err error) {
+	o.Logger.Info("interaction signature refused", "remote", r.RemoteAddr, "path", r.URL.Path, "error", err)
+
+	if o.OnVerificationFailure != nil {
+		o.OnVerificationFailure(r, err)
+	}
+}
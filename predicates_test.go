@@ -0,0 +1,122 @@
+package httpcord
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// watchedBody Body recording whether it was read
+type watchedBody struct {
+	io.Reader
+	read bool
+}
+
+func (b *watchedBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *watchedBody) Close() error {
+	return nil
+}
+
+func TestRequestPredicates(t *testing.T) {
+	failing := func(name string, calls *[]string) func(r *http.Request) error {
+		return func(r *http.Request) error {
+			*calls = append(*calls, name)
+			return errors.New(name + " refused")
+		}
+	}
+
+	passing := func(name string, calls *[]string) func(r *http.Request) error {
+		return func(r *http.Request) error {
+			*calls = append(*calls, name)
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name       string
+		predicates func(calls *[]string) []func(r *http.Request) error
+		header     http.Header
+		status     int
+		err        error
+		// message Text of the error when it is not a sentinel
+		message string
+		calls   string
+	}{
+		{"required header present", func(*[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{RequiredHeader("X-Edge-Secret", "s3cret")}
+		}, http.Header{"X-Edge-Secret": {"s3cret"}}, http.StatusOK, nil, "", ""},
+		{"required header missing", func(*[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{RequiredHeader("X-Edge-Secret", "s3cret")}
+		}, http.Header{}, http.StatusForbidden, ErrRequiredHeader, "", ""},
+		{"required header of another value", func(*[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{RequiredHeader("X-Edge-Secret", "s3cret")}
+		}, http.Header{"X-Edge-Secret": {"s3cre"}}, http.StatusForbidden, ErrRequiredHeader, "", ""},
+		{"forbidden header absent", func(*[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{ForbiddenHeader("x-forwarded-host")}
+		}, http.Header{}, http.StatusOK, nil, "", ""},
+		{"forbidden header present", func(*[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{ForbiddenHeader("x-forwarded-host")}
+		}, http.Header{"X-Forwarded-Host": {""}}, http.StatusForbidden, ErrForbiddenHeader, "", ""},
+		{"run in order", func(calls *[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{passing("first", calls), passing("second", calls)}
+		}, http.Header{}, http.StatusOK, nil, "", "first,second"},
+		{"first error stops the checks", func(calls *[]string) []func(r *http.Request) error {
+			return []func(r *http.Request) error{passing("first", calls), failing("second", calls), failing("third", calls)}
+		}, http.Header{}, http.StatusForbidden, nil, "second refused", "first,second"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []string
+			var failure error
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				Logger:                NopLogger,
+				RequestPredicates:     test.predicates(&calls),
+				OnVerificationFailure: func(r *http.Request, err error) { failure = err },
+			})
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			r := sign(commandBody())
+			for key, values := range test.header {
+				r.Header[key] = values
+			}
+
+			body := &watchedBody{Reader: r.Body}
+			r.Body = body
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, r)
+
+			if w.Code != test.status {
+				t.Fatalf("status %d, want %d", w.Code, test.status)
+			}
+
+			if test.status == http.StatusForbidden {
+				if body.read {
+					t.Error("body read before refusing the request")
+				}
+
+				if test.err != nil && !errors.Is(failure, test.err) || test.message != "" && (failure == nil || failure.Error() != test.message) {
+					t.Errorf("OnVerificationFailure got %v, want %v%s", failure, test.err, test.message)
+				}
+			} else if failure != nil {
+				t.Errorf("OnVerificationFailure got %v for an accepted request", failure)
+			}
+
+			if got := strings.Join(calls, ","); got != test.calls {
+				t.Errorf("predicates called %q, want %q", got, test.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package httpcord
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InteractionTokenLifetime Time the interaction token can be used for follow-ups and edits
+const InteractionTokenLifetime = 15 * time.Minute
+
+// DefaultProgressEditBudget Maximum edits made by a ProgressTicker unless ProgressEditBudget is used
+const DefaultProgressEditBudget = 30
+
+type ProgressOption func(t *ProgressTicker)
+
+// ProgressEditBudget Maximum edits the ticker makes before it stops editing
+func ProgressEditBudget(budget int) ProgressOption {
+	return func(t *ProgressTicker) {
+		t.budget = budget
+	}
+}
+
+// ProgressTicker Edit the deferred response cycling through frames until stopped
+type ProgressTicker struct {
+	ctx      *ConnectionContext
+	interval time.Duration
+	frames   []string
+	budget   int
+	edits    int
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// StartProgress Edit the original response every interval of ConnectionOptions.Clock with the next frame, meant to be
+// used after a defer. A failed edit is logged and spends the budget, the next frame is tried at the next interval.
+// The ticker stops on Stop, Respond, when the edit budget is spent, when the interaction token expires or once
+// Shutdown starts
+func (ctx *ConnectionContext) StartProgress(interval time.Duration, frames []string, opts ...ProgressOption) *ProgressTicker {
+	t := &ProgressTicker{
+		ctx:      ctx,
+		interval: interval,
+		frames:   frames,
+		budget:   DefaultProgressEditBudget,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	ctx.state.mu.Lock()
+	previous := ctx.state.progress
+	ctx.state.progress = t
+	ctx.state.mu.Unlock()
+
+	if previous != nil {
+		previous.Stop(nil)
+	}
+
+	ctx.life.background(t.run)
+	return t
+}
+
+func (t *ProgressTicker) run() {
+	defer close(t.done)
+
+	if len(t.frames) == 0 {
+		return
+	}
+
+	clock := t.ctx.options.Clock
+
+	// The ticks are armed one at a time on the Clock, a slow edit delays the next frame instead of queueing them
+	tick := make(chan struct{}, 1)
+	arm := func() func() bool {
+		return clock.AfterFunc(t.interval, func() {
+			select {
+			case tick <- struct{}{}:
+			default:
+			}
+		})
+	}
+
+	stopTick := arm()
+	defer func() { stopTick() }()
+
+	expired := make(chan struct{})
+	stopExpiry := clock.AfterFunc(t.ctx.Interaction.ID.CreatedAt().Add(InteractionTokenLifetime).Sub(clock.Now()), func() {
+		close(expired)
+	})
+	defer stopExpiry()
+
+	shutdown := t.ctx.life.done()
+
+	for t.edits < t.budget {
+		select {
+		case <-t.stop:
+			return
+		case <-expired:
+			return
+		case <-shutdown:
+			return
+		case <-tick:
+			frame := t.frames[t.edits%len(t.frames)]
+			t.edits++
+			stopTick = arm()
+
+			if _, err := t.ctx.editOriginal(context.Background(), &WebhookEdit{Content: frame}); err != nil {
+				t.ctx.options.Logger.Warn("progress edit failed", "interaction", t.ctx.Interaction.ID, "error", err)
+			}
+		}
+	}
+}
+
+// Stop Stop editing and wait for the pending edit, final replaces the progress when not nil
+func (t *ProgressTicker) Stop(final *WebhookEdit) (*Message, error) {
+	t.once.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+
+	t.ctx.state.mu.Lock()
+	if t.ctx.state.progress == t {
+		t.ctx.state.progress = nil
+	}
+	t.ctx.state.mu.Unlock()
+
+	if final == nil {
+		return nil, nil
+	}
+
+	return t.ctx.EditReply(final)
+}
+
+// Edits Number of progress edits made, waits for the ticker to stop
+func (t *ProgressTicker) Edits() int {
+	<-t.done
+	return t.edits
+}
+
+// stopProgress Stop the running ProgressTicker without a final edit
+func (ctx *ConnectionContext) stopProgress() {
+	ctx.state.mu.Lock()
+	t := ctx.state.progress
+	ctx.state.mu.Unlock()
+
+	if t != nil {
+		t.Stop(nil)
+	}
+}
@@ -0,0 +1,207 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownStopsProgressTicker(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		frames   []string
+	}{
+		{"ticking", 5 * time.Millisecond, []string{"⠋", "⠙", "⠹"}},
+		{"slow interval", time.Hour, []string{"working"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webhooks := &countingWebhooks{}
+			interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}
+
+			ctx, _ := NewContext(interaction, ContextConfig{Webhooks: webhooks, Respond: func(*InteractionResponse) error { return nil }})
+			conn := &Connection{life: ctx.life}
+
+			ticker := ctx.StartProgress(test.interval, test.frames, ProgressEditBudget(1000))
+			time.Sleep(20 * time.Millisecond)
+
+			shutdown, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			started := time.Now()
+			if err := conn.Shutdown(shutdown); err != nil {
+				t.Fatalf("Shutdown = %v after %s, the ticker held the drain", err, time.Since(started))
+			}
+
+			edits := ticker.Edits()
+			time.Sleep(20 * time.Millisecond)
+
+			if after := int(atomic.LoadInt32(&webhooks.edits)); after != edits {
+				t.Errorf("%d edits after Shutdown", after-edits)
+			}
+		})
+	}
+}
+
+// progressWebhooks Webhooks recording the edited contents, the edits fail while failing is set
+type progressWebhooks struct {
+	InteractionWebhooks
+
+	mu       sync.Mutex
+	contents []string
+	failing  bool
+}
+
+func (w *progressWebhooks) EditOriginalInteractionResponse(_ context.Context, _ Snowflake, _ string, data *WebhookEdit, _ ...EditOption) (*Message, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.contents = append(w.contents, data.Content)
+	if w.failing {
+		return nil, errors.New("edit refused")
+	}
+
+	return &Message{Content: data.Content}, nil
+}
+
+// waitEdits Contents edited once n edits were made, fails the test when they are not made in time
+func (w *progressWebhooks) waitEdits(t *testing.T, n int) []string {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		w.mu.Lock()
+		contents := append([]string(nil), w.contents...)
+		w.mu.Unlock()
+
+		if len(contents) >= n || time.Now().After(deadline) {
+			if len(contents) != n {
+				t.Fatalf("edits %q, want %d", contents, n)
+			}
+
+			return contents
+		}
+	}
+}
+
+func TestProgressTicker(t *testing.T) {
+	const interval = time.Second
+
+	frames := []string{"Working.", "Working..", "Working..."}
+
+	tests := []struct {
+		name   string
+		budget int
+		// ticks Intervals elapsed before the ticker is stopped
+		ticks int
+		// failing The edits are refused, every tick still tries the next frame
+		failing bool
+		final   *WebhookEdit
+		want    []string
+	}{
+		{"frames cycle", 10, 5, false, nil, []string{"Working.", "Working..", "Working...", "Working.", "Working.."}},
+		{"budget cap", 2, 5, false, nil, []string{"Working.", "Working.."}},
+		{"no tick before the interval", 10, 0, false, nil, nil},
+		{"final edit on stop", 10, 2, false, &WebhookEdit{Content: "Done"}, []string{"Working.", "Working..", "Done"}},
+		{"failed edits spend the budget", 3, 5, true, nil, []string{"Working.", "Working..", "Working..."}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			webhooks := &progressWebhooks{failing: test.failing}
+			interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}
+
+			ctx, finish := NewContext(interaction, ContextConfig{
+				Options:  ConnectionOptions{Clock: clock, Logger: NopLogger},
+				Webhooks: webhooks,
+				Respond:  func(*InteractionResponse) error { return nil },
+			})
+			defer finish()
+
+			ticker := ctx.StartProgress(interval, frames, ProgressEditBudget(test.budget))
+			defer ticker.Stop(nil)
+
+			// The tick and the token expiry
+			clock.waitTimers(t, 2)
+
+			for i := 0; i < test.ticks; i++ {
+				clock.Advance(interval / 2)
+				clock.Advance(interval / 2)
+
+				if i < test.budget {
+					webhooks.waitEdits(t, i+1)
+				}
+			}
+
+			ticker.Stop(test.final)
+
+			// Stopped tickers do not edit anymore
+			clock.Advance(10 * interval)
+
+			if got := webhooks.waitEdits(t, len(test.want)); fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("edits %q, want %q", got, test.want)
+			}
+
+			want := test.ticks
+			if want > test.budget {
+				want = test.budget
+			}
+
+			if ticker.Edits() != want {
+				t.Errorf("Edits() = %d, want %d", ticker.Edits(), want)
+			}
+		})
+	}
+
+	t.Run("token expiry", func(t *testing.T) {
+		clock := newFakeClock()
+		webhooks := &progressWebhooks{}
+		interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}
+
+		ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{Clock: clock}, Webhooks: webhooks,
+			Respond: func(*InteractionResponse) error { return nil }})
+		defer finish()
+
+		ticker := ctx.StartProgress(time.Hour, frames)
+		clock.waitTimers(t, 2)
+		clock.Advance(InteractionTokenLifetime)
+
+		if edits := ticker.Edits(); edits != 0 {
+			t.Errorf("%d edits, want the ticker stopped by the token expiry", edits)
+		}
+	})
+
+	t.Run("restarted", func(t *testing.T) {
+		clock := newFakeClock()
+		webhooks := &progressWebhooks{}
+		interaction := Interaction{ID: nowSnowflake(), Type: ApplicationCommandInteraction, Token: "token"}
+
+		ctx, finish := NewContext(interaction, ContextConfig{Options: ConnectionOptions{Clock: clock}, Webhooks: webhooks,
+			Respond: func(*InteractionResponse) error { return nil }})
+		defer finish()
+
+		first := ctx.StartProgress(interval, frames)
+		second := ctx.StartProgress(interval, []string{"Again"})
+
+		if first.Edits() != 0 {
+			t.Error("the first ticker kept running after StartProgress")
+		}
+
+		defer second.Stop(nil)
+
+		clock.waitTimers(t, 2)
+		clock.Advance(interval)
+		webhooks.waitEdits(t, 1)
+		second.Stop(nil)
+
+		if got := webhooks.waitEdits(t, 1); got[0] != "Again" {
+			t.Errorf("edited %q, want the frame of the second ticker", got)
+		}
+	})
+}
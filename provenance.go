@@ -0,0 +1,96 @@
+package httpcord
+
+type MessageProvenance int
+
+// Message Provenances
+
+const (
+	// UnknownProvenance The message has no application or webhook ID, like old messages
+	UnknownProvenance MessageProvenance = iota
+	OwnProvenance
+	ForeignProvenance
+)
+
+var ForeignComponentMessages = Dictionary{
+	EnglishUSLocale:    "This message belongs to another application.",
+	EnglishGBLocale:    "This message belongs to another application.",
+	PortugueseBRLocale: "Esta mensagem pertence a outro aplicativo.",
+	SpanishESLocale:    "Este mensaje pertenece a otra aplicación.",
+	FrenchLocale:       "Ce message appartient à une autre application.",
+	GermanLocale:       "Diese Nachricht gehört zu einer anderen Anwendung.",
+}
+
+// MessageProvenance Whether the message of the component interaction was sent by the application of
+// ConnectionOptions.ApplicationID, or the one receiving the interaction when it is not set. Interaction responses carry
+// the application ID in application_id and webhook_id, bot messages have the application as author and the messages
+// of the other bots are foreign
+func (ctx *ConnectionContext) MessageProvenance() MessageProvenance {
+	message := ctx.Interaction.Message
+	if message == nil {
+		return UnknownProvenance
+	}
+
+	own := ctx.ownApplicationID()
+
+	switch {
+	case message.ApplicationID != "":
+		if message.ApplicationID == own {
+			return OwnProvenance
+		}
+
+		return ForeignProvenance
+	case message.WebhookID != "":
+		if message.WebhookID == own {
+			return OwnProvenance
+		}
+
+		return ForeignProvenance
+	case message.Author != nil && message.Author.Bot:
+		if message.Author.ID == own {
+			return OwnProvenance
+		}
+
+		return ForeignProvenance
+	}
+
+	return UnknownProvenance
+}
+
+// ownApplicationID ConnectionOptions.ApplicationID, or the application of the interaction when it is one of
+// ConnectionOptions.Applications or no application is configured
+func (ctx *ConnectionContext) ownApplicationID() Snowflake {
+	for _, application := range ctx.options.Applications {
+		if application.ID == ctx.Interaction.ApplicationID {
+			return application.ID
+		}
+	}
+
+	if ctx.options.ApplicationID != "" {
+		return ctx.options.ApplicationID
+	}
+
+	return ctx.Interaction.ApplicationID
+}
+
+// IsOwnMessage Whether the message of the component interaction is known to be sent by the application
+func (ctx *ConnectionContext) IsOwnMessage() bool {
+	return ctx.MessageProvenance() == OwnProvenance
+}
+
+// RejectForeignComponents Refuse the component interactions on messages of other applications with an ephemeral
+// ForeignComponentMessages reply instead of running the component routes, unknown provenances are accepted
+func (c *Connection) RejectForeignComponents() {
+	c.router.mu.Lock()
+	defer c.router.mu.Unlock()
+
+	c.router.userComponents.rejectForeign = true
+}
+
+func (ctx *ConnectionContext) replyForeignComponent() {
+	locale := ctx.Locale()
+
+	ctx.ReplyInteraction(&InteractionCallbackData{
+		Content: ForeignComponentMessages.Get(locale, ForeignComponentMessages[EnglishUSLocale]),
+		Flags:   EphemeralMessageFlag,
+	})
+}
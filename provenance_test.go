@@ -0,0 +1,39 @@
+package httpcord
+
+import "testing"
+
+func TestMessageProvenance(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     ConnectionOptions
+		interaction Snowflake
+		message     *Message
+		want        MessageProvenance
+	}{
+		{"no message", ConnectionOptions{}, "1", nil, UnknownProvenance},
+		{"own response", ConnectionOptions{}, "1", &Message{ApplicationID: "1"}, OwnProvenance},
+		{"other application response", ConnectionOptions{}, "1", &Message{ApplicationID: "2"}, ForeignProvenance},
+		{"own webhook", ConnectionOptions{}, "1", &Message{WebhookID: "1"}, OwnProvenance},
+		{"own bot author", ConnectionOptions{}, "1", &Message{Author: &User{ID: "1", Bot: true}}, OwnProvenance},
+		{"other bot author", ConnectionOptions{}, "1", &Message{Author: &User{ID: "2", Bot: true}}, ForeignProvenance},
+		{"user author", ConnectionOptions{}, "1", &Message{Author: &User{ID: "3"}}, UnknownProvenance},
+		{"configured application", ConnectionOptions{ApplicationID: "5"}, "1", &Message{ApplicationID: "1"}, ForeignProvenance},
+		{"configured application author", ConnectionOptions{ApplicationID: "5"}, "5", &Message{Author: &User{ID: "5", Bot: true}}, OwnProvenance},
+		{"served application", ConnectionOptions{ApplicationID: "5", Applications: []ApplicationCredentials{{ID: "6"}}}, "6",
+			&Message{ApplicationID: "6"}, OwnProvenance},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := test.options
+			ctx := ConnectionContext{
+				Interaction: Interaction{ApplicationID: test.interaction, Type: MessageComponentInteraction, Message: test.message},
+				options:     &options,
+			}
+
+			if got := ctx.MessageProvenance(); got != test.want {
+				t.Errorf("MessageProvenance() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
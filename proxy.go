@@ -0,0 +1,94 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultFallbackProxyTimeout Time the fallback endpoint has to answer, Discord waits 3 seconds for the initial response
+const DefaultFallbackProxyTimeout = 2500 * time.Millisecond
+
+// ProxyError The interaction could not be forwarded to ConnectionOptions.FallbackProxy or to its replica
+type ProxyError struct {
+	URL *url.URL
+	Err error
+}
+
+func (e *ProxyError) Error() string {
+	return fmt.Sprintf("httpcord: forwarding interaction to %s: %v", e.URL.Redacted(), e.Err)
+}
+
+func (e *ProxyError) Unwrap() error {
+	return e.Err
+}
+
+// forward Replay the request to the fallback endpoint and copy its response to Discord, the ErrorReply is sent when it
+// could not be reached
+func (ctx *ConnectionContext) forward(w http.ResponseWriter, r *http.Request, body []byte) {
+	res, upstream, err := proxyRequest(r, body, ctx.options.FallbackProxy, ctx.options.FallbackProxyTimeout, nil)
+	if err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	// A defer written while waiting for the proxy already answered Discord
+	ctx.state.mu.Lock()
+	responded := ctx.state.responded
+	ctx.state.responded = true
+	ctx.state.mu.Unlock()
+
+	if responded {
+		return
+	}
+
+	writeProxied(w, res, upstream)
+}
+
+// proxyRequest Replay the request to the target with its original headers and body, so the signature stays valid,
+// and the header added. Returns the upstream response and its body, errors are ProxyError
+func proxyRequest(r *http.Request, body []byte, target *url.URL, timeout time.Duration, header http.Header) (*http.Response, []byte, error) {
+	c, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(c, r.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, &ProxyError{URL: target, Err: err}
+	}
+
+	req.Header = r.Header.Clone()
+	req.Header.Del("Content-Length")
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, &ProxyError{URL: target, Err: err}
+	}
+	defer res.Body.Close()
+
+	upstream, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, &ProxyError{URL: target, Err: err}
+	}
+
+	return res, upstream, nil
+}
+
+// writeProxied Copy the upstream status, headers and body to the Discord response
+func writeProxied(w http.ResponseWriter, res *http.Response, upstream []byte) {
+	for key, values := range res.Header {
+		w.Header()[key] = values
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(upstream)))
+	w.WriteHeader(res.StatusCode)
+	w.Write(upstream)
+}
@@ -0,0 +1,123 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFallbackProxy(t *testing.T) {
+	tests := []struct {
+		name string
+		// command Name of the command sent, only "ban" has a route
+		command  string
+		upstream http.HandlerFunc
+		// closed The legacy service is not listening
+		closed bool
+		status int
+		body   string
+		// proxied The legacy service received the request
+		proxied bool
+		err     error
+	}{
+		{"success", "kick", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Legacy", "1")
+			w.Write([]byte(`{"type":4,"data":{"content":"legacy kick"}}`))
+		}, false, http.StatusOK, `{"type":4,"data":{"content":"legacy kick"}}`, true, nil},
+		{"upstream 500 relayed", "kick", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "legacy failed", http.StatusInternalServerError)
+		}, false, http.StatusInternalServerError, "legacy failed\n", true, nil},
+		{"timeout", "kick", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}, false, http.StatusOK, GenericErrorMessages[EnglishUSLocale], true, context.DeadlineExceeded},
+		{"unreachable", "kick", nil, true, http.StatusOK, GenericErrorMessages[EnglishUSLocale], false, &ProxyError{}},
+		{"handled commands not proxied", "ban", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type":4,"data":{"content":"legacy ban"}}`))
+		}, false, http.StatusOK, "banned", false, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var received *http.Request
+			var receivedBody []byte
+
+			legacy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				receivedBody, _ = ioutil.ReadAll(r.Body)
+				test.upstream(w, r)
+			}))
+			defer legacy.Close()
+
+			target, _ := url.Parse(legacy.URL + "/interactions")
+			if test.closed {
+				legacy.Close()
+			}
+
+			var reported error
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				Logger:               NopLogger,
+				FallbackProxy:        target,
+				FallbackProxyTimeout: 50 * time.Millisecond,
+				OnError:              func(ctx ConnectionContext, err error) { reported = err },
+			})
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			body := []byte(strings.Replace(string(commandBody()), `"name":"ban"`, `"name":"`+test.command+`"`, 1))
+			r := sign(body)
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, r)
+
+			// Waits for the legacy handler before reading what it received
+			legacy.Close()
+
+			if w.Code != test.status || !strings.Contains(w.Body.String(), test.body) {
+				t.Errorf("response %d %s, want %d %s", w.Code, w.Body, test.status, test.body)
+			}
+
+			if (received != nil) != test.proxied {
+				t.Fatalf("proxied = %v, want %v", received != nil, test.proxied)
+			}
+
+			if received != nil {
+				if string(receivedBody) != string(body) || received.URL.Path != "/interactions" {
+					t.Errorf("legacy service got %s %s, want the original body", received.URL.Path, receivedBody)
+				}
+
+				for _, header := range []string{"X-Signature-Ed25519", "X-Signature-Timestamp"} {
+					if received.Header.Get(header) != r.Header.Get(header) {
+						t.Errorf("%s = %q, want %q", header, received.Header.Get(header), r.Header.Get(header))
+					}
+				}
+			}
+
+			if test.status == http.StatusOK && test.proxied && test.err == nil && w.Header().Get("X-Legacy") != "1" {
+				t.Errorf("headers %v, want the upstream headers", w.Header())
+			}
+
+			var proxyErr *ProxyError
+			switch {
+			case test.err == nil && reported != nil:
+				t.Errorf("OnError got %v", reported)
+			case test.err != nil && !errors.As(reported, &proxyErr):
+				t.Errorf("OnError got %v, want a ProxyError", reported)
+			case errors.Is(test.err, context.DeadlineExceeded) && !errors.Is(reported, context.DeadlineExceeded):
+				t.Errorf("OnError got %v, want the timeout", reported)
+			}
+		})
+	}
+}
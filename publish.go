@@ -0,0 +1,231 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"httpcord/permissions"
+)
+
+const (
+	publishComponentPrefix = "httpcord:publish:"
+	publishStatePrefix     = "publish:"
+)
+
+// ErrPublishFiles Previews with files can not be published, the files are not kept in the state store
+var ErrPublishFiles = errors.New("httpcord: previews with files can not be published")
+
+var (
+	PublishButtonMessages = Dictionary{
+		EnglishUSLocale:    "Publish",
+		EnglishGBLocale:    "Publish",
+		PortugueseBRLocale: "Publicar",
+		SpanishESLocale:    "Publicar",
+		FrenchLocale:       "Publier",
+		GermanLocale:       "Veröffentlichen",
+	}
+	PublishCancelButtonMessages = Dictionary{
+		EnglishUSLocale:    "Cancel",
+		EnglishGBLocale:    "Cancel",
+		PortugueseBRLocale: "Cancelar",
+		SpanishESLocale:    "Cancelar",
+		FrenchLocale:       "Annuler",
+		GermanLocale:       "Abbrechen",
+	}
+	PublishedMessages = Dictionary{
+		EnglishUSLocale:    "Published ✔",
+		EnglishGBLocale:    "Published ✔",
+		PortugueseBRLocale: "Publicado ✔",
+		SpanishESLocale:    "Publicado ✔",
+		FrenchLocale:       "Publié ✔",
+		GermanLocale:       "Veröffentlicht ✔",
+	}
+	PublishCancelledMessages = Dictionary{
+		EnglishUSLocale:    "Publishing cancelled.",
+		EnglishGBLocale:    "Publishing cancelled.",
+		PortugueseBRLocale: "Publicação cancelada.",
+		SpanishESLocale:    "Publicación cancelada.",
+		FrenchLocale:       "Publication annulée.",
+		GermanLocale:       "Veröffentlichung abgebrochen.",
+	}
+	PublishExpiredMessages = Dictionary{
+		EnglishUSLocale:    "This preview expired, run the command again.",
+		EnglishGBLocale:    "This preview expired, run the command again.",
+		PortugueseBRLocale: "Esta prévia expirou, execute o comando novamente.",
+		SpanishESLocale:    "Esta vista previa expiró, ejecuta el comando de nuevo.",
+		FrenchLocale:       "Cet aperçu a expiré, relancez la commande.",
+		GermanLocale:       "Diese Vorschau ist abgelaufen, führe den Befehl erneut aus.",
+	}
+)
+
+type PublishOption func(o *publishOptions)
+
+type publishOptions struct {
+	ttl time.Duration
+}
+
+// PublishTTL Time the preview can be published (Defaults to InteractionTokenLifetime)
+func PublishTTL(ttl time.Duration) PublishOption {
+	return func(o *publishOptions) {
+		o.ttl = ttl
+	}
+}
+
+type publishState struct {
+	ChannelID Snowflake                `json:"channel_id"`
+	Data      *InteractionCallbackData `json:"data"`
+	// ExpiresAt restores the state with the remaining ttl when the publication fails
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// publishing Previews being published by this process, a second click waits for the first one to finish
+var publishing sync.Map
+
+// takePublishState Remove the stored preview while reading it, through StateTaker when the store implements it
+func takePublishState(store ComponentStateStore, key string) ([]byte, bool, error) {
+	if taker, ok := store.(StateTaker); ok {
+		return taker.Take(key)
+	}
+
+	value, ok, err := store.Get(key)
+	if err != nil || !ok {
+		return value, ok, err
+	}
+
+	return value, true, store.Delete(key)
+}
+
+// ReplyEphemeralWithPublish Reply with an ephemeral preview of data followed by publish and cancel buttons.
+// Publishing sends data without the ephemeral flag to targetChannel and replaces the preview with PublishedMessages.
+// When targetChannel is the interaction channel the application permissions are checked before the preview is sent
+func (ctx *ConnectionContext) ReplyEphemeralWithPublish(data *InteractionCallbackData, targetChannel Snowflake, opts ...PublishOption) error {
+	o := publishOptions{ttl: InteractionTokenLifetime}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(data.Files) > 0 {
+		return ErrPublishFiles
+	}
+
+	if ctx.Interaction.ChannelID == targetChannel {
+		if err := ctx.checkSendPermissions(data); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(publishState{ChannelID: targetChannel, Data: data, ExpiresAt: time.Now().Add(o.ttl)})
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.options.StateStore.Set(publishStatePrefix+ctx.requestID, value, o.ttl); err != nil {
+		return err
+	}
+
+	locale := Locale(ctx.Interaction.Locale)
+	preview := *data
+	preview.Flags |= EphemeralMessageFlag
+	preview.Components = append(append([]*ActionRowComponent{}, data.Components...), NewActionRowComponentBuilder().SetComponents(
+		NewButtonComponentBuilder().
+			SetStyle(SuccessButtonStyle).
+			SetLabel(PublishButtonMessages.Get(locale, PublishButtonMessages[EnglishUSLocale])).
+			SetCustomID(publishComponentPrefix+"publish:"+ctx.requestID),
+		NewButtonComponentBuilder().
+			SetStyle(SecondaryButtonStyle).
+			SetLabel(PublishCancelButtonMessages.Get(locale, PublishCancelButtonMessages[EnglishUSLocale])).
+			SetCustomID(publishComponentPrefix+"cancel:"+ctx.requestID),
+	))
+
+	ctx.ReplyInteraction(&preview)
+	return nil
+}
+
+// checkSendPermissions Permissions needed to send data in the interaction channel
+func (ctx *ConnectionContext) checkSendPermissions(data *InteractionCallbackData) error {
+	granted, ok := ctx.AppPermissions()
+	if !ok {
+		return nil
+	}
+
+	if !granted.Has(permissions.ViewChannel, true) {
+		return &MissingAppPermissionError{Permission: permissions.ViewChannel, Name: "View Channel"}
+	}
+
+	if !granted.Has(permissions.SendMessages, true) {
+		return &MissingAppPermissionError{Permission: permissions.SendMessages, Name: "Send Messages"}
+	}
+
+	if len(data.Embeds) > 0 && !granted.Has(permissions.EmbedLinks, true) {
+		return &MissingAppPermissionError{Permission: permissions.EmbedLinks, Name: "Embed Links"}
+	}
+
+	return nil
+}
+
+// handlePublish Publish or discard the stored preview of the clicked button
+func handlePublish(ctx ConnectionContext) {
+	locale := Locale(ctx.Interaction.Locale)
+	action, id, _ := strings.Cut(strings.TrimPrefix(ctx.Interaction.ComponentData().CustomID, publishComponentPrefix), ":")
+	key := publishStatePrefix + id
+
+	update := func(messages Dictionary) {
+		ctx.UpdateMessage(&InteractionCallbackData{
+			Content:    messages.Get(locale, messages[EnglishUSLocale]),
+			Components: []*ActionRowComponent{},
+		})
+	}
+
+	if action == "cancel" {
+		ctx.options.StateStore.Delete(key)
+		update(PublishCancelledMessages)
+		return
+	}
+
+	// A double click publishes once, the preview is updated by the first click
+	if _, busy := publishing.LoadOrStore(key, struct{}{}); busy {
+		ctx.DeferUpdateInteraction()
+		return
+	}
+	defer publishing.Delete(key)
+
+	value, ok, err := takePublishState(ctx.options.StateStore, key)
+	if err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	var state publishState
+	if !ok || json.Unmarshal(value, &state) != nil {
+		update(PublishExpiredMessages)
+		return
+	}
+
+	message := state.Data.MessageCreate()
+	message.Flags &^= EphemeralMessageFlag
+
+	c, cancel := context.WithTimeout(ctx.Context(), 2*time.Second)
+	defer cancel()
+
+	if _, err := ctx.client.CreateMessage(c, state.ChannelID, message); err != nil {
+		// Kept for another try until it expires
+		if ttl := time.Until(state.ExpiresAt); ttl > 0 {
+			ctx.options.StateStore.Set(key, value, ttl)
+		}
+
+		var apiErr *DiscordAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			err = &MissingAppPermissionError{Permission: permissions.SendMessages, Name: "Send Messages"}
+		}
+
+		ctx.handleError(err)
+		return
+	}
+
+	update(PublishedMessages)
+}
@@ -0,0 +1,94 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// getDeleteStore ComponentStateStore without StateTaker
+type getDeleteStore struct {
+	store *MemoryStateStore
+}
+
+func (s getDeleteStore) Set(key string, value []byte, ttl time.Duration) error {
+	return s.store.Set(key, value, ttl)
+}
+
+func (s getDeleteStore) Get(key string) ([]byte, bool, error) {
+	return s.store.Get(key)
+}
+
+func (s getDeleteStore) Delete(key string) error {
+	return s.store.Delete(key)
+}
+
+func TestPublishOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  func() ComponentStateStore
+		status int
+		clicks int
+		posts  int32
+		kept   bool
+	}{
+		{"state taker", func() ComponentStateStore { return NewMemoryStateStore() }, http.StatusOK, 5, 1, false},
+		{"get and delete", func() ComponentStateStore { return getDeleteStore{NewMemoryStateStore()} }, http.StatusOK, 5, 1, false},
+		{"failed publication is kept", func() ComponentStateStore { return NewMemoryStateStore() }, http.StatusBadRequest, 1, 1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&posts, 1)
+				time.Sleep(20 * time.Millisecond)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				w.Write([]byte(`{"id":"9","code":50035,"message":"Invalid Form Body"}`))
+			}))
+			defer server.Close()
+
+			client := NewRestClient(StaticToken("token"))
+			client.BaseURL = server.URL
+			client.MaxRetries = 0
+
+			store := test.store()
+			value, _ := json.Marshal(publishState{ChannelID: "7", Data: &InteractionCallbackData{Content: "news"}, ExpiresAt: time.Now().Add(time.Minute)})
+			store.Set(publishStatePrefix+"draft", value, time.Minute)
+
+			var wg sync.WaitGroup
+			for i := 0; i < test.clicks; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					interaction := Interaction{Type: MessageComponentInteraction, Data: ComponentInteractionData{CustomID: publishComponentPrefix + "publish:draft"}}
+					ctx, finish := NewContext(interaction, ContextConfig{
+						Options:  ConnectionOptions{StateStore: store, ErrorReply: func(ConnectionContext, error) *InteractionCallbackData { return nil }},
+						Client:   client,
+						Webhooks: &countingWebhooks{},
+					})
+
+					handlePublish(*ctx)
+					finish()
+				}()
+			}
+
+			wg.Wait()
+
+			if got := atomic.LoadInt32(&posts); got != test.posts {
+				t.Errorf("%d messages created, want %d", got, test.posts)
+			}
+
+			if _, ok, _ := store.Get(publishStatePrefix + "draft"); ok != test.kept {
+				t.Errorf("state kept = %v, want %v", ok, test.kept)
+			}
+		})
+	}
+}
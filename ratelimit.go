@@ -0,0 +1,222 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter Buckets of the API routes, requests of a bucket are sent one at a time
+type rateLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*rateLimitBucket
+	globalUntil time.Time
+	lastSweep   time.Time
+}
+
+// rateLimitBucket The limits are written with the limiter lock held so BucketState can read them
+// while a request of the bucket is in flight
+type rateLimitBucket struct {
+	mu        sync.Mutex
+	key       string
+	limit     int
+	remaining int
+	reset     time.Time
+	lastUsed  time.Time
+	// known The limits were sent by the API at least once
+	known bool
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*rateLimitBucket)}
+}
+
+// majorParameters Route segments followed by an ID that gets its own rate limit
+var majorParameters = map[string]bool{
+	"channels": true,
+	"guilds":   true,
+	"webhooks": true,
+}
+
+// BucketKey Rate limit key of a request, IDs are replaced except the major parameters
+// (channel, guild and webhook with its token) so routes of different resources and interactions do not share a bucket
+func BucketKey(method, path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+
+	for i := 1; i < len(segments); i++ {
+		previous := segments[i-1]
+
+		switch {
+		case previous == "reactions":
+			// Every emoji and user of the reactions route share the bucket
+			segments = append(segments[:i], "{emoji}")
+			return method + " " + strings.Join(segments, "/")
+		case majorParameters[previous]:
+			// Webhook tokens are part of the major parameter
+			if previous == "webhooks" && i+1 < len(segments) && segments[i+1] != "messages" {
+				i++
+			}
+		case isNumeric(segments[i]):
+			segments[i] = "{id}"
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *rateLimiter) bucket(key string) *rateLimitBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Interaction tokens make most webhook buckets single use
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, b := range l.buckets {
+			if b.mu.TryLock() {
+				if now.Sub(b.lastUsed) > 5*time.Minute && now.After(b.reset) {
+					delete(l.buckets, k)
+				}
+
+				b.mu.Unlock()
+			}
+		}
+
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateLimitBucket{key: key, remaining: 1}
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+// wait Block until the global limit and the bucket allow a request, notify is called before sleeping
+func (l *rateLimiter) wait(ctx context.Context, b *rateLimitBucket, notify func(retryAfter time.Duration, global bool)) error {
+	l.mu.Lock()
+	until := l.globalUntil
+	global := true
+
+	if b.remaining <= 0 && b.reset.After(until) {
+		until, global = b.reset, false
+	}
+	l.mu.Unlock()
+
+	if delay := time.Until(until); delay > 0 {
+		if notify != nil {
+			notify(delay, global)
+		}
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil
+}
+
+// update Track the limits sent in the response headers
+func (l *rateLimiter) update(b *rateLimitBucket, header http.Header, global bool, retryAfter time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b.lastUsed = now
+
+	if global {
+		l.globalUntil = now.Add(retryAfter)
+		return
+	}
+
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		b.limit = limit
+	}
+
+	// A 429 of the route is its limit even when it is the first response of the bucket
+	if retryAfter > 0 {
+		b.remaining, b.reset, b.known = 0, now.Add(retryAfter), true
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		b.remaining = 1
+		return
+	}
+
+	b.remaining, b.known = remaining, true
+
+	if after, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		b.reset = now.Add(time.Duration(after * float64(time.Second)))
+	}
+}
+
+// state Requests the bucket allows before its reset, the bucket is refilled once the reset passed
+func (l *rateLimiter) state(key string) (remaining int, resetAt time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, found := l.buckets[key]
+	if !found || !b.known {
+		return 0, time.Time{}, false
+	}
+
+	now := time.Now()
+	remaining, resetAt = b.remaining, b.reset
+
+	if now.After(resetAt) && b.limit > 0 {
+		remaining, resetAt = b.limit, time.Time{}
+	}
+
+	if l.globalUntil.After(now) {
+		remaining, resetAt = 0, l.globalUntil
+	}
+
+	return remaining, resetAt, true
+}
+
+// BucketState Requests the route allows right now without waiting and when its bucket resets.
+// The route is the method and path of the request like "PATCH /channels/1/messages/2", normalized with BucketKeyFunc.
+// ok is false until the API sent the limits of the bucket
+func (c *RestClient) BucketState(route string) (remaining int, resetAt time.Time, ok bool) {
+	if c.limiter == nil {
+		return 0, time.Time{}, false
+	}
+
+	method, path := "", route
+	if i := strings.IndexByte(route, ' '); i >= 0 {
+		method, path = route[:i], route[i+1:]
+	}
+
+	return c.limiter.state(c.bucketKey(method, path))
+}
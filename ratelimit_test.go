@@ -0,0 +1,258 @@
+package httpcord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBucketKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"follow-up", http.MethodPost, "/webhooks/1/tokenA", "POST /webhooks/1/tokenA"},
+		{"follow-up with query", http.MethodPost, "/webhooks/1/tokenA?wait=true", "POST /webhooks/1/tokenA"},
+		{"follow-up edit", http.MethodPatch, "/webhooks/1/tokenA/messages/2", "PATCH /webhooks/1/tokenA/messages/{id}"},
+		{"original edit", http.MethodPatch, "/webhooks/1/tokenA/messages/@original", "PATCH /webhooks/1/tokenA/messages/@original"},
+		{"webhook without token", http.MethodGet, "/webhooks/1/messages/2", "GET /webhooks/1/messages/{id}"},
+		{"channel message", http.MethodPatch, "/channels/3/messages/4", "PATCH /channels/3/messages/{id}"},
+		{"guild member", http.MethodGet, "/guilds/5/members/6", "GET /guilds/5/members/{id}"},
+		{"reactions", http.MethodPut, "/channels/3/messages/4/reactions/%F0%9F%91%8D/@me", "PUT /channels/3/messages/{id}/reactions/{emoji}"},
+		{"application commands", http.MethodPatch, "/applications/7/commands/8", "PATCH /applications/{id}/commands/{id}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := BucketKey(test.method, test.path); got != test.want {
+				t.Errorf("BucketKey(%s, %s) = %q, want %q", test.method, test.path, got, test.want)
+			}
+		})
+	}
+
+	if BucketKey(http.MethodPost, "/webhooks/1/tokenA") == BucketKey(http.MethodPost, "/webhooks/1/tokenB") {
+		t.Error("the follow-ups of two interactions share a bucket")
+	}
+}
+
+func TestFollowUpBuckets(t *testing.T) {
+	const reset = 300 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		bucketKey func(method, path string) string
+		// delayed The follow-up of the other interaction waits for the exhausted bucket
+		delayed bool
+	}{
+		{"per interaction", nil, false},
+		{"overridden to one bucket", func(method, path string) string { return "webhooks" }, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Only the bucket of the first interaction is exhausted
+				if strings.HasSuffix(r.URL.Path, "/limited") {
+					w.Header().Set("X-RateLimit-Limit", "1")
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset-After", strconv.FormatFloat(reset.Seconds(), 'f', -1, 64))
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":"9"}`))
+			}))
+			defer server.Close()
+
+			client := NewRestClient(StaticToken("token"))
+			client.BaseURL = server.URL
+			client.BucketKeyFunc = test.bucketKey
+
+			send := func(token string) time.Duration {
+				start := time.Now()
+				if _, err := client.CreateFollowUpMessage(context.Background(), "1", token, &WebhookEdit{Content: "done"}); err != nil {
+					t.Error(err)
+				}
+
+				return time.Since(start)
+			}
+
+			// Exhaust the bucket of the limited interaction
+			send("limited")
+
+			var wg sync.WaitGroup
+			var limited, free time.Duration
+
+			wg.Add(2)
+			go func() { defer wg.Done(); limited = send("limited") }()
+			go func() { defer wg.Done(); free = send("free") }()
+			wg.Wait()
+
+			if limited < reset/2 {
+				t.Errorf("the limited follow-up took %s, it did not wait for the reset", limited)
+			}
+
+			if delayed := free >= reset/2; delayed != test.delayed {
+				t.Errorf("the other follow-up took %s, delayed = %v, want %v", free, delayed, test.delayed)
+			}
+		})
+	}
+}
+
+// rateLimitEvent Arguments of a RestClient.OnRateLimit call
+type rateLimitEvent struct {
+	route  string
+	global bool
+}
+
+func TestBucketState(t *testing.T) {
+	const route = "PATCH /channels/3/messages/4"
+
+	limited := func(limit, remaining string, resetAfter float64) http.Header {
+		return http.Header{
+			"X-Ratelimit-Limit":       {limit},
+			"X-Ratelimit-Remaining":   {remaining},
+			"X-Ratelimit-Reset-After": {strconv.FormatFloat(resetAfter, 'f', -1, 64)},
+		}
+	}
+
+	tests := []struct {
+		name string
+		// headers Headers of the scripted responses, one request is sent per response
+		headers []http.Header
+		// status Status of the last response, the 429 body names the retry delay
+		status int
+		global bool
+		// wait Time waited after the requests
+		wait  time.Duration
+		query string
+		ok    bool
+		// remaining and reset are the expected state, reset is relative to the last request
+		remaining int
+		reset     time.Duration
+		events    []rateLimitEvent
+	}{
+		{"unknown before the API sent the limits", nil, http.StatusOK, false, 0, route, false, 0, 0, nil},
+		{"no limit headers", []http.Header{{}}, http.StatusOK, false, 0, route, false, 0, 0, nil},
+		{"limits from the headers", []http.Header{limited("5", "3", 10)}, http.StatusOK, false, 0, route, true, 3, 10 * time.Second, nil},
+		{"other message of the channel shares the bucket", []http.Header{limited("5", "3", 10)}, http.StatusOK, false, 0,
+			"PATCH /channels/3/messages/99", true, 3, 10 * time.Second, nil},
+		{"other channel", []http.Header{limited("5", "3", 10)}, http.StatusOK, false, 0, "PATCH /channels/30/messages/4", false, 0, 0, nil},
+		{"other method", []http.Header{limited("5", "3", 10)}, http.StatusOK, false, 0, "DELETE /channels/3/messages/4", false, 0, 0, nil},
+		{"refilled after the reset", []http.Header{limited("5", "0", 0.05)}, http.StatusOK, false, 80 * time.Millisecond, route, true, 5, 0, nil},
+		{"waits for the exhausted bucket", []http.Header{limited("2", "0", 0.05), limited("2", "1", 10)}, http.StatusOK, false, 0, route, true, 1, 10 * time.Second,
+			[]rateLimitEvent{{"PATCH /channels/3/messages/{id}", false}}},
+		{"rate limited", []http.Header{limited("5", "1", 10)}, http.StatusTooManyRequests, false, 0, route, true, 0, 50 * time.Millisecond,
+			[]rateLimitEvent{{"PATCH /channels/3/messages/{id}", false}}},
+		{"global rate limit", []http.Header{limited("5", "1", 10)}, http.StatusTooManyRequests, true, 0, "DELETE /guilds/2/members/4", false, 0, 0,
+			[]rateLimitEvent{{"PATCH /channels/3/messages/{id}", true}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requests := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				header := test.headers[requests]
+				requests++
+				last := requests == len(test.headers)
+				mu.Unlock()
+
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+
+				if last && test.status == http.StatusTooManyRequests {
+					w.WriteHeader(http.StatusTooManyRequests)
+					w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.05,"global":` + strconv.FormatBool(test.global) + `}`))
+					return
+				}
+
+				w.Write([]byte(`{"id":"4"}`))
+			}))
+			defer server.Close()
+
+			var events []rateLimitEvent
+
+			client := NewRestClient(StaticToken("token"))
+			client.BaseURL = server.URL
+			client.MaxRetries = 0
+			client.OnRateLimit = func(route string, retryAfter time.Duration, global bool) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if retryAfter <= 0 || retryAfter > 50*time.Millisecond {
+					t.Errorf("OnRateLimit(%s) retry after %s, want up to 50ms", route, retryAfter)
+				}
+
+				events = append(events, rateLimitEvent{route, global})
+			}
+
+			var sent time.Time
+			for range test.headers {
+				sent = time.Now()
+				client.Do(context.Background(), http.MethodPatch, "/channels/3/messages/4", &MessageEdit{}, nil)
+			}
+
+			time.Sleep(test.wait)
+
+			remaining, resetAt, ok := client.BucketState(test.query)
+			if ok != test.ok || remaining != test.remaining {
+				t.Errorf("BucketState(%s) = %d, %v, want %d, %v", test.query, remaining, ok, test.remaining, test.ok)
+			}
+
+			if test.reset == 0 && !resetAt.IsZero() {
+				t.Errorf("reset at %v, want none", resetAt)
+			} else if want := sent.Add(test.reset); test.reset > 0 && (resetAt.Before(want.Add(-time.Second)) || resetAt.After(want.Add(time.Second))) {
+				t.Errorf("reset at %v, want about %v", resetAt, want)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if fmt.Sprint(events) != fmt.Sprint(test.events) {
+				t.Errorf("OnRateLimit events %v, want %v", events, test.events)
+			}
+		})
+	}
+
+	t.Run("global limit blocks every bucket", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Limit", "5")
+			w.Header().Set("X-RateLimit-Remaining", "4")
+			w.Header().Set("X-RateLimit-Reset-After", "10")
+			w.Header().Set("Content-Type", "application/json")
+
+			if r.Method == http.MethodPatch {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"retry_after":1,"global":true}`))
+				return
+			}
+
+			w.Write([]byte(`{"id":"4"}`))
+		}))
+		defer server.Close()
+
+		client := NewRestClient(StaticToken("token"))
+		client.BaseURL = server.URL
+		client.MaxRetries = 0
+
+		client.Do(context.Background(), http.MethodGet, "/channels/3/messages/4", nil, nil)
+		client.Do(context.Background(), http.MethodPatch, "/channels/3/messages/4", &MessageEdit{}, nil)
+
+		if remaining, resetAt, ok := client.BucketState("GET /channels/3/messages/4"); !ok || remaining != 0 || time.Until(resetAt) < 500*time.Millisecond {
+			t.Errorf("BucketState() = %d, %v, %v, want the global limit", remaining, resetAt, ok)
+		}
+	})
+}
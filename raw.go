@@ -0,0 +1,57 @@
+package httpcord
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// rawResponseWriter In memory ResponseWriter used by HandleRawRequest
+type rawResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *rawResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *rawResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *rawResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
+
+func (w *rawResponseWriter) Flush() {}
+
+// HandleRawRequest Verify and dispatch an interaction without an HTTP server, for environments like js/wasm workers
+// where the runtime provides the request. Header names are case insensitive.
+// The whole package builds for js/wasm and wasip1, fasthttp and net/http included, so the servers and the REST
+// client are not split behind build tags: they are only unused there (See TestWasmBuild)
+func (c *Connection) HandleRawRequest(headers map[string]string, body []byte) (status int, contentType string, respBody []byte) {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return http.StatusBadRequest, "", nil
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := &rawResponseWriter{header: make(http.Header)}
+	c.handler(w, req)
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.status, w.header.Get("Content-Type"), w.body.Bytes()
+}
@@ -0,0 +1,98 @@
+package httpcord
+
+import (
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHandleRawRequest(t *testing.T) {
+	conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger})
+
+	conn.Command("ban", func(ctx ConnectionContext) {
+		ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+	}).SetAutocomplete(func(ctx ConnectionContext) {
+		ctx.RespondAutocomplete([]ApplicationCommandOptionChoice{{Name: "spam", Value: "spam"}})
+	})
+
+	conn.Component("pick", func(ctx ConnectionContext) {
+		ctx.UpdateMessage(&InteractionCallbackData{Content: "picked"})
+	})
+
+	// headers Signed headers of the body, with lowercase names like the ones of the workers runtimes
+	headers := func(body []byte) map[string]string {
+		flat := make(map[string]string)
+		for key, values := range sign(body).Header {
+			flat[strings.ToLower(key)] = values[0]
+		}
+
+		return flat
+	}
+
+	command, component := commandBody(), componentBody()
+	autocomplete := interactionBody(AutoCompleteInteraction, `{"id":"5","name":"ban","type":1,"options":[{"type":3,"name":"reason","value":"sp","focused":true}]}`)
+	ping := []byte(`{"id":"` + nowSnowflake().String() + `","application_id":"1","type":1,"token":"token","version":1}`)
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		body        []byte
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"ping", headers(ping), ping, http.StatusOK, "application/json", `{"type":1}`},
+		{"command", headers(command), command, http.StatusOK, "application/json", `"content":"banned"`},
+		{"component", headers(component), component, http.StatusOK, "application/json", `"type":7`},
+		{"autocomplete", headers(autocomplete), autocomplete, http.StatusOK, "application/json", `"name":"spam"`},
+		{"signature of another body", headers(command), ping, http.StatusUnauthorized, "", ""},
+		{"unsigned", map[string]string{"content-type": "application/json"}, ping, http.StatusUnauthorized, "", ""},
+		{"malformed", headers([]byte(`{"type":`)), []byte(`{"type":`), http.StatusBadRequest, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, contentType, respBody := conn.HandleRawRequest(test.headers, test.body)
+
+			if status != test.status {
+				t.Errorf("status %d, want %d: %s", status, test.status, respBody)
+			}
+
+			if test.contentType != "" && contentType != test.contentType {
+				t.Errorf("content type %q, want %q", contentType, test.contentType)
+			}
+
+			if !strings.Contains(string(respBody), test.contains) {
+				t.Errorf("response %s, want %s", respBody, test.contains)
+			}
+		})
+	}
+}
+
+// TestWasmBuild The package builds for the js/wasm and wasip1 workers runtimes, go vet does not cover other platforms
+func TestWasmBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the package for wasm")
+	}
+
+	goBin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	for _, goos := range []string{"js", "wasip1"} {
+		cmd := exec.Command(goBin, "build", "-o", os.DevNull, ".")
+		cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH=wasm", "CGO_ENABLED=0")
+
+		out, err := cmd.CombinedOutput()
+		if err != nil && strings.Contains(string(out), "unsupported GOOS/GOARCH pair") {
+			t.Logf("GOOS=%s GOARCH=wasm is not supported by this toolchain", goos)
+		} else if err != nil {
+			t.Errorf("GOOS=%s GOARCH=wasm go build: %v\n%s", goos, err, out)
+		}
+	}
+}
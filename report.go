@@ -0,0 +1,199 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	reportComponentPrefix = "httpcord:report:"
+	reportStatePrefix     = "report:"
+	// DefaultErrorReportTTL Time users have to report an error
+	DefaultErrorReportTTL = time.Hour
+)
+
+var (
+	ErrorReportButtonMessages = Dictionary{
+		EnglishUSLocale:    "Report this",
+		EnglishGBLocale:    "Report this",
+		PortugueseBRLocale: "Reportar",
+		SpanishESLocale:    "Reportar",
+		FrenchLocale:       "Signaler",
+		GermanLocale:       "Melden",
+	}
+	ErrorReportSentMessages = Dictionary{
+		EnglishUSLocale:    "Thanks, the error was reported.",
+		EnglishGBLocale:    "Thanks, the error was reported.",
+		PortugueseBRLocale: "Obrigado, o erro foi reportado.",
+		SpanishESLocale:    "Gracias, el error fue reportado.",
+		FrenchLocale:       "Merci, l'erreur a été signalée.",
+		GermanLocale:       "Danke, der Fehler wurde gemeldet.",
+	}
+	ErrorReportExpiredMessages = Dictionary{
+		EnglishUSLocale:    "This error can no longer be reported.",
+		EnglishGBLocale:    "This error can no longer be reported.",
+		PortugueseBRLocale: "Este erro não pode mais ser reportado.",
+		SpanishESLocale:    "Este error ya no se puede reportar.",
+		FrenchLocale:       "Cette erreur ne peut plus être signalée.",
+		GermanLocale:       "Dieser Fehler kann nicht mehr gemeldet werden.",
+	}
+)
+
+// ErrorReport Context of an error reported by a user
+type ErrorReport struct {
+	RequestID string    `json:"request_id"`
+	TraceCode string    `json:"trace_code"`
+	Command   string    `json:"command,omitempty"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+	UserID    Snowflake `json:"user_id,omitempty"`
+	GuildID   Snowflake `json:"guild_id,omitempty"`
+	// ReporterID is the user who clicked the report button
+	ReporterID Snowflake `json:"reporter_id,omitempty"`
+}
+
+// ErrorReportOptions Attach a report button to the error replies, see ConnectionOptions.ErrorReport
+type ErrorReportOptions struct {
+	// WebhookURL Channel webhook receiving the reports
+	WebhookURL string
+	// Callback Called with every report, used instead of WebhookURL when set
+	Callback func(ctx ConnectionContext, report ErrorReport) error
+	// TTL Time the report button works after the error (Defaults to DefaultErrorReportTTL)
+	TTL time.Duration
+}
+
+// ParseWebhookURL Extract the ID and token of a webhook URL like https://discord.com/api/webhooks/{id}/{token}
+func ParseWebhookURL(rawURL string) (Snowflake, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "webhooks" {
+			if id := Snowflake(parts[i+1]); validSnowflake(id) {
+				return id, parts[i+2], nil
+			}
+		}
+	}
+
+	return "", "", errors.New("httpcord: invalid webhook url " + rawURL)
+}
+
+// attachReport Stash the error context and add the report button to the error reply
+func (ctx *ConnectionContext) attachReport(data *InteractionCallbackData, err error) {
+	report := ErrorReport{
+		RequestID: ctx.requestID,
+		TraceCode: ctx.TraceCode(),
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+		GuildID:   ctx.Interaction.GuildID,
+	}
+
+	if ctx.Interaction.User != nil {
+		report.UserID = ctx.Interaction.User.ID
+	}
+
+	if ctx.Interaction.Type == ApplicationCommandInteraction {
+		report.Command = ctx.Interaction.ApplicationCommandData().Name
+	}
+
+	value, _ := json.Marshal(report)
+
+	if err := ctx.options.StateStore.Set(reportStatePrefix+ctx.requestID, value, ctx.options.ErrorReport.TTL); err != nil {
+		ctx.options.Logger.Warn("storing error report failed", "request", ctx.requestID, "error", err)
+		return
+	}
+
+	locale := Locale(ctx.Interaction.Locale)
+
+	data.Components = append(data.Components, NewActionRowComponentBuilder().SetComponents(
+		NewButtonComponentBuilder().
+			SetStyle(SecondaryButtonStyle).
+			SetLabel(ErrorReportButtonMessages.Get(locale, ErrorReportButtonMessages[EnglishUSLocale])).
+			SetCustomID(reportComponentPrefix+ctx.requestID),
+	))
+}
+
+// handleReport Forward the stored error context of the clicked report button
+func handleReport(ctx ConnectionContext) {
+	locale := Locale(ctx.Interaction.Locale)
+	key := reportStatePrefix + strings.TrimPrefix(ctx.Interaction.ComponentData().CustomID, reportComponentPrefix)
+
+	value, ok, err := ctx.options.StateStore.Get(key)
+	if err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	var report ErrorReport
+	if !ok || json.Unmarshal(value, &report) != nil {
+		ctx.ReplyInteraction(&InteractionCallbackData{
+			Content: ErrorReportExpiredMessages.Get(locale, ErrorReportExpiredMessages[EnglishUSLocale]),
+			Flags:   EphemeralMessageFlag,
+		})
+		return
+	}
+
+	if ctx.Interaction.User != nil {
+		report.ReporterID = ctx.Interaction.User.ID
+	}
+
+	if err := ctx.forwardReport(report); err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	// Reported once, following clicks get the expired message
+	ctx.options.StateStore.Delete(key)
+
+	ctx.ReplyInteraction(&InteractionCallbackData{
+		Content: ErrorReportSentMessages.Get(locale, ErrorReportSentMessages[EnglishUSLocale]),
+		Flags:   EphemeralMessageFlag,
+	})
+}
+
+func (ctx *ConnectionContext) forwardReport(report ErrorReport) error {
+	options := ctx.options.ErrorReport
+
+	if options.Callback != nil {
+		return options.Callback(*ctx, report)
+	}
+
+	id, token, err := ParseWebhookURL(options.WebhookURL)
+	if err != nil {
+		return err
+	}
+
+	embed := NewEmbedBuilder().
+		SetTitle("Error report " + report.TraceCode).
+		SetDescription("```\n" + report.Error + "\n```").
+		SetTimestamp(Time{report.Timestamp}).
+		AddField(&EmbedField{Name: "Request", Value: report.RequestID, Inline: true}).
+		AddField(&EmbedField{Name: "Reporter", Value: fmt.Sprintf("<@%s>", report.ReporterID), Inline: true})
+
+	if report.Command != "" {
+		embed.AddField(&EmbedField{Name: "Command", Value: "/" + report.Command, Inline: true})
+	}
+
+	if report.GuildID != "" {
+		embed.AddField(&EmbedField{Name: "Guild", Value: report.GuildID.String(), Inline: true})
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err = ctx.client.ExecuteWebhook(c, id, token, &WebhookEdit{
+		Embeds:          &[]*Embed{embed},
+		AllowedMentions: &AllowedMentions{Parse: []string{}},
+	})
+
+	return err
+}
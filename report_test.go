@@ -0,0 +1,190 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// reportClickBody Click on the report button of the error reply by the member "8"
+func reportClickBody(customID string) []byte {
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":3,"token":"token","version":1,"guild_id":"2","channel_id":"3","locale":"fr",`+
+		`"member":{"user":{"id":"8","username":"reporter"},"roles":[],"permissions":"0"},"data":{"custom_id":"%s","component_type":2}}`, nowSnowflake(), customID))
+}
+
+func TestErrorReport(t *testing.T) {
+	tests := []struct {
+		name string
+		// webhook The report is sent to the channel webhook rather than the callback
+		webhook bool
+		ttl     time.Duration
+		// clicks Number of clicks on the report button
+		clicks int
+		// callbackErr Error of the report callback
+		callbackErr error
+		// replies Content of the reply to each click
+		replies []string
+		// forwarded Number of reports forwarded
+		forwarded int
+	}{
+		{"callback", false, 0, 1, nil, []string{ErrorReportSentMessages[FrenchLocale]}, 1},
+		{"webhook", true, 0, 1, nil, []string{ErrorReportSentMessages[FrenchLocale]}, 1},
+		{"reported once", false, 0, 2, nil, []string{ErrorReportSentMessages[FrenchLocale], ErrorReportExpiredMessages[FrenchLocale]}, 1},
+		{"expired", false, time.Millisecond, 1, nil, []string{ErrorReportExpiredMessages[FrenchLocale]}, 0},
+		{"forwarding failed", false, 0, 2, errors.New("report channel gone"),
+			[]string{GenericErrorMessages[FrenchLocale], GenericErrorMessages[FrenchLocale]}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var reports []ErrorReport
+			var embeds []*Embed
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				var edit struct {
+					Embeds          []*Embed         `json:"embeds"`
+					AllowedMentions *AllowedMentions `json:"allowed_mentions"`
+				}
+				json.Unmarshal(body, &edit)
+
+				if r.URL.Path != "/webhooks/10/secret" {
+					t.Errorf("report sent to %s, want the webhook", r.URL.Path)
+				}
+
+				if edit.AllowedMentions == nil || len(edit.AllowedMentions.Parse) != 0 {
+					t.Errorf("report mentions %s, want none allowed", body)
+				}
+
+				mu.Lock()
+				embeds = append(embeds, edit.Embeds...)
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":"11"}`))
+			}))
+			defer server.Close()
+
+			options := &ErrorReportOptions{TTL: test.ttl}
+			if test.webhook {
+				options.WebhookURL = "https://discord.com/api/webhooks/10/secret"
+			} else {
+				options.Callback = func(ctx ConnectionContext, report ErrorReport) error {
+					mu.Lock()
+					reports = append(reports, report)
+					mu.Unlock()
+
+					return test.callbackErr
+				}
+			}
+
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, ErrorReport: options})
+			conn.Client.BaseURL = server.URL
+			conn.Client.MaxRetries = 0
+
+			var traceCode string
+			conn.Command("ban", func(ctx ConnectionContext) {
+				traceCode = ctx.TraceCode()
+				panic("boom")
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(commandBody()))
+
+			var response struct {
+				Data struct {
+					Components []struct {
+						Components []ButtonComponent `json:"components"`
+					} `json:"components"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%v: %s", err, w.Body)
+			}
+
+			if len(response.Data.Components) != 1 || len(response.Data.Components[0].Components) != 1 {
+				t.Fatalf("error reply %s, want the report button", w.Body)
+			}
+
+			button := response.Data.Components[0].Components[0]
+			if !strings.HasPrefix(button.CustomID, reportComponentPrefix) || button.Label != ErrorReportButtonMessages[EnglishUSLocale] {
+				t.Fatalf("error reply %s, want the report button", w.Body)
+			}
+
+			time.Sleep(2 * test.ttl)
+
+			for i := 0; i < test.clicks; i++ {
+				w := httptest.NewRecorder()
+				conn.ServeHTTP(w, sign(reportClickBody(button.CustomID)))
+
+				var reply InteractionResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+					t.Fatalf("%v: %s", err, w.Body)
+				}
+
+				if reply.Data.Content != test.replies[i] || reply.Data.Flags != EphemeralMessageFlag {
+					t.Errorf("click %d replied %s, want the ephemeral %q", i, w.Body, test.replies[i])
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if test.webhook {
+				if len(embeds) != test.forwarded {
+					t.Fatalf("%d embeds sent to the webhook, want %d", len(embeds), test.forwarded)
+				}
+
+				embed := embeds[0]
+				if embed.Title != "Error report "+traceCode || !strings.Contains(embed.Description, "boom") {
+					t.Errorf("report embed %+v, want the trace code %s and the error", embed, traceCode)
+				}
+
+				return
+			}
+
+			if len(reports) != test.forwarded {
+				t.Fatalf("%d reports forwarded, want %d", len(reports), test.forwarded)
+			}
+
+			for _, report := range reports {
+				if report.TraceCode != traceCode || report.Command != "ban" || report.GuildID != "2" || report.ReporterID != "8" ||
+					!strings.Contains(report.Error, "boom") || time.Since(report.Timestamp) > time.Minute {
+					t.Errorf("report %+v, want the context of the error with trace code %s", report, traceCode)
+				}
+			}
+		})
+	}
+}
+
+func TestParseWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		id    Snowflake
+		token string
+		fails bool
+	}{
+		{"https://discord.com/api/webhooks/10/secret", "10", "secret", false},
+		{"https://discord.com/api/v10/webhooks/10/secret/", "10", "secret", false},
+		{"https://discord.com/api/webhooks/10/secret?wait=true", "10", "secret", false},
+		{"https://discord.com/api/webhooks/abc/secret", "", "", true},
+		{"https://discord.com/api/webhooks/10", "", "", true},
+		{"://", "", "", true},
+	}
+
+	for _, test := range tests {
+		id, token, err := ParseWebhookURL(test.url)
+		if (err != nil) != test.fails || id != test.id || token != test.token {
+			t.Errorf("ParseWebhookURL(%q) = %q, %q, %v, want %q, %q", test.url, id, token, err, test.id, test.token)
+		}
+	}
+}
@@ -19,7 +19,14 @@ const (
 var DefaultUserAgent = fmt.Sprintf("HttpInteractionsBot (http-cord, %s)", VERSION)
 
 // Request Create a request
+//
+// Deprecated: requests bypass the rate limiter, use RestClient.Do instead
 func Request(URI, method string, body interface{}, clientToken string, headers map[string]string) []byte {
+	deprecated("Request", "RestClient.Do", 0)
+	return request(URI, method, body, clientToken, headers)
+}
+
+func request(URI, method string, body interface{}, clientToken string, headers map[string]string) []byte {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
 	req.SetRequestURI(URI)
@@ -62,41 +69,65 @@ func Request(URI, method string, body interface{}, clientToken string, headers m
 
 // ApplicationCommandsBulkOverwrite Overwrite all application commands
 // (In case guild id is not provided to edit commands globally)
+//
+// Deprecated: use RestClient.Do instead
 func ApplicationCommandsBulkOverwrite(applicationID string, commands []*ApplicationCommand, guildID, clientToken string) (createdCommands []*ApplicationCommand, err error) {
+	deprecated("ApplicationCommandsBulkOverwrite", "RestClient.Do", 0)
+
 	uri := endpoints.FormatAPIURI(endpoints.ApplicationCommandsGlobal(applicationID))
 
 	if guildID != "" {
 		uri = endpoints.FormatAPIURI(endpoints.ApplicationCommandsGuild(applicationID, guildID))
 	}
 
-	res := Request(uri, fasthttp.MethodPut, commands, clientToken, nil)
+	res := request(uri, fasthttp.MethodPut, commands, clientToken, nil)
 	err = json.Unmarshal(res, &createdCommands)
 	return
 }
 
 // CreateMessage Send a message to a channel
+//
+// Deprecated: use RestClient.Do instead
 func CreateMessage(clientToken string, channelID string, messageData *Message) {
-	Request(endpoints.Messages(channelID), fasthttp.MethodPost, messageData, clientToken, nil)
+	deprecated("CreateMessage", "RestClient.Do", 0)
+
+	request(endpoints.Messages(channelID), fasthttp.MethodPost, messageData, clientToken, nil)
 }
 
 // FetchMessage Fetch a message in channel
+//
+// Deprecated: use RestClient.Do instead
 func FetchMessage(clientToken string, channelID, messageID string) {
-	Request(endpoints.Message(channelID, messageID), fasthttp.MethodGet, nil, clientToken, nil)
+	deprecated("FetchMessage", "RestClient.Do", 0)
+
+	request(endpoints.Message(channelID, messageID), fasthttp.MethodGet, nil, clientToken, nil)
 }
 
 // DeleteMessage Delete a message in channel
+//
+// Deprecated: use RestClient.Do instead
 func DeleteMessage(clientToken, channelID, messageID string) {
-	Request(endpoints.Message(channelID, messageID), fasthttp.MethodDelete, nil, clientToken, nil)
+	deprecated("DeleteMessage", "RestClient.Do", 0)
+
+	request(endpoints.Message(channelID, messageID), fasthttp.MethodDelete, nil, clientToken, nil)
 }
 
 // EditMessage Edit a message in channel
+//
+// Deprecated: use RestClient.Do instead
 func EditMessage(clientToken, channelID, messageID string, messageData *Message) {
-	Request(endpoints.Message(channelID, messageID), fasthttp.MethodPatch, messageData, clientToken, nil)
+	deprecated("EditMessage", "RestClient.Do", 0)
+
+	request(endpoints.Message(channelID, messageID), fasthttp.MethodPatch, messageData, clientToken, nil)
 }
 
 // CreateReaction Create a reaction in the message
+//
+// Deprecated: use RestClient.Do instead
 func CreateReaction(clientToken, channelID, messageID, reaction string) {
-	Request(
+	deprecated("CreateReaction", "RestClient.Do", 0)
+
+	request(
 		endpoints.UserReaction(channelID, messageID, reaction, "@me"),
 		fasthttp.MethodPut,
 		nil, clientToken,
@@ -105,8 +136,12 @@ func CreateReaction(clientToken, channelID, messageID, reaction string) {
 }
 
 // RemoveReaction Remove a reaction from message
+//
+// Deprecated: use RestClient.Do instead
 func RemoveReaction(clientToken, channelID, messageID, reaction, userID string) {
-	Request(
+	deprecated("RemoveReaction", "RestClient.Do", 0)
+
+	request(
 		endpoints.UserReaction(channelID, messageID, reaction, userID),
 		fasthttp.MethodDelete,
 		nil, clientToken,
@@ -115,8 +150,12 @@ func RemoveReaction(clientToken, channelID, messageID, reaction, userID string)
 }
 
 // RemoveAllReactions Remove all reactions from message
+//
+// Deprecated: use RestClient.Do instead
 func RemoveAllReactions(clientToken, channelID, messageID string) {
-	Request(
+	deprecated("RemoveAllReactions", "RestClient.Do", 0)
+
+	request(
 		endpoints.Reactions(channelID, messageID),
 		fasthttp.MethodDelete,
 		nil, clientToken,
@@ -125,7 +164,11 @@ func RemoveAllReactions(clientToken, channelID, messageID string) {
 }
 
 // MessageReactions Get all reactions from message
+//
+// Deprecated: use RestClient.Do instead
 func MessageReactions(clientToken, channelID, messageID string, options SearchQueryParams) {
+	deprecated("MessageReactions", "RestClient.Do", 0)
+
 	var query string
 
 	if options.Limit != 0 {
@@ -146,7 +189,7 @@ func MessageReactions(clientToken, channelID, messageID string, options SearchQu
 		uri += query
 	}
 
-	Request(
+	request(
 		uri,
 		fasthttp.MethodGet,
 		nil, clientToken,
@@ -154,24 +197,33 @@ func MessageReactions(clientToken, channelID, messageID string, options SearchQu
 	)
 }
 
+// Deprecated: use RestClient.EditOriginalInteractionResponse instead
 func EditOriginalInteractionResponse(applicationID, interactionToken string, data *WebhookEdit) {
-	Request(
+	deprecated("EditOriginalInteractionResponse", "RestClient.EditOriginalInteractionResponse", 0)
+
+	request(
 		endpoints.FormatAPIURI(endpoints.WebhookMessage(applicationID, interactionToken, "@original")),
 		fasthttp.MethodPatch,
 		data, "", nil,
 	)
 }
 
+// Deprecated: use RestClient.DeleteOriginalInteractionResponse instead
 func DeleteOriginalInteractionResponse(applicationID, interactionToken string) {
-	Request(
+	deprecated("DeleteOriginalInteractionResponse", "RestClient.DeleteOriginalInteractionResponse", 0)
+
+	request(
 		endpoints.FormatAPIURI(endpoints.WebhookMessage(applicationID, interactionToken, "@original")),
 		fasthttp.MethodDelete,
 		nil, "", nil,
 	)
 }
 
+// Deprecated: use RestClient.CreateFollowUpMessage instead
 func FollowUpInteractionResponse(applicationID, interactionToken string, data *WebhookEdit) {
-	Request(
+	deprecated("FollowUpInteractionResponse", "RestClient.CreateFollowUpMessage", 0)
+
+	request(
 		endpoints.FormatAPIURI(endpoints.WebhookExecute(applicationID, interactionToken)),
 		fasthttp.MethodDelete,
 		data, "", nil,
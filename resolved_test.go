@@ -0,0 +1,117 @@
+package httpcord
+
+import (
+	"strconv"
+	"testing"
+)
+
+// resolvedCommandBody "admin audit" invoked with an option of every type and their resolved entities, as Discord sends it
+const resolvedCommandBody = `{"id":"5","name":"admin","type":1,"options":[{"type":2,"name":"moderation","options":[` +
+	`{"type":1,"name":"audit","options":[` +
+	`{"type":3,"name":"reason","value":"spam"},` +
+	`{"type":4,"name":"count","value":25},` +
+	`{"type":10,"name":"ratio","value":0.75},` +
+	`{"type":5,"name":"ephemeral","value":true},` +
+	`{"type":6,"name":"target","value":"6"},` +
+	`{"type":6,"name":"outsider","value":"7"},` +
+	`{"type":7,"name":"channel","value":"10"},` +
+	`{"type":8,"name":"role","value":"11"},` +
+	`{"type":9,"name":"mentionable","value":"11"},` +
+	`{"type":11,"name":"file","value":"12"}]}]}],` +
+	`"resolved":{` +
+	`"users":{"6":{"id":"6","username":"target"},"7":{"id":"7","username":"outsider"}},` +
+	`"members":{"6":{"nick":"suspect","roles":["11"],"joined_at":"2024-01-02T03:04:05+00:00","permissions":"1024"}},` +
+	`"channels":{"10":{"id":"10","name":"reports","type":0,"permissions":"1024"}},` +
+	`"roles":{"11":{"id":"11","name":"mods","color":0,"position":3,"permissions":"8"}},` +
+	`"attachments":{"12":{"id":"12","filename":"log.txt","size":2048,"url":"https://cdn.discordapp.com/attachments/1/12/log.txt",` +
+	`"proxy_url":"https://media.discordapp.net/attachments/1/12/log.txt","content_type":"text/plain"}}}}`
+
+func TestTypedOptions(t *testing.T) {
+	ctx := bodyContext(t, interactionBody(ApplicationCommandInteraction, resolvedCommandBody))
+
+	tests := []struct {
+		name string
+		get  func() (interface{}, bool)
+		want interface{}
+		ok   bool
+	}{
+		{"string", func() (interface{}, bool) { return ctx.StringOption("reason") }, "spam", true},
+		{"integer", func() (interface{}, bool) { return ctx.IntOption("count") }, 25, true},
+		{"number", func() (interface{}, bool) { return ctx.NumberOption("ratio") }, 0.75, true},
+		{"integer as a number", func() (interface{}, bool) { return ctx.NumberOption("count") }, 25.0, true},
+		{"boolean", func() (interface{}, bool) { return ctx.BoolOption("ephemeral") }, true, true},
+		{"string of another type", func() (interface{}, bool) { return ctx.StringOption("count") }, "", false},
+		{"integer of another type", func() (interface{}, bool) { return ctx.IntOption("reason") }, 0, false},
+		{"boolean of another type", func() (interface{}, bool) { return ctx.BoolOption("reason") }, false, false},
+		{"missing", func() (interface{}, bool) { return ctx.StringOption("note") }, "", false},
+		{"user", func() (interface{}, bool) {
+			user, ok := ctx.UserOption("target")
+			return user.Username, ok
+		}, "target", true},
+		{"member with its user", func() (interface{}, bool) {
+			member, ok := ctx.MemberOption("target")
+			return member.Nick + " " + member.User.Username + " " + strconv.FormatUint(uint64(member.Permissions), 10), ok
+		}, "suspect target 1024", true},
+		{"user outside the guild", func() (interface{}, bool) {
+			user, ok := ctx.UserOption("outsider")
+			return user.Username, ok
+		}, "outsider", true},
+		{"member outside the guild", func() (interface{}, bool) {
+			member, ok := ctx.MemberOption("outsider")
+			return member == nil, ok
+		}, true, false},
+		{"channel", func() (interface{}, bool) {
+			channel, ok := ctx.ChannelOption("channel")
+			return channel.Name, ok
+		}, "reports", true},
+		{"role", func() (interface{}, bool) {
+			role, ok := ctx.RoleOption("role")
+			return role.Name, ok
+		}, "mods", true},
+		{"role of a mentionable", func() (interface{}, bool) {
+			role, ok := ctx.RoleOption("mentionable")
+			return role.Name, ok
+		}, "mods", true},
+		{"user of a mentionable role", func() (interface{}, bool) {
+			user, ok := ctx.UserOption("mentionable")
+			return user == nil, ok
+		}, true, false},
+		{"attachment", func() (interface{}, bool) {
+			attachment, ok := ctx.AttachmentOption("file")
+			return attachment.Filename + " " + attachment.ContentType, ok
+		}, "log.txt text/plain", true},
+		{"channel of a string", func() (interface{}, bool) {
+			channel, ok := ctx.ChannelOption("reason")
+			return channel == nil, ok
+		}, true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.get()
+			if ok != test.ok || ok && got != test.want {
+				t.Errorf("got %v, %v, want %v, %v", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestResolvedData(t *testing.T) {
+	ctx := bodyContext(t, interactionBody(ApplicationCommandInteraction, resolvedCommandBody))
+	resolved := ctx.Interaction.ApplicationCommandData().Resolved
+
+	// Every entity is keyed by its ID, the attachments included
+	if len(resolved.Users) != 2 || len(resolved.Members) != 1 || len(resolved.Channels) != 1 || len(resolved.Roles) != 1 ||
+		len(resolved.Attachments) != 1 {
+		t.Fatalf("resolved %+v, want 2 users and one member, channel, role and attachment", resolved)
+	}
+
+	if attachment := resolved.Attachments["12"]; attachment == nil || attachment.Size != 2048 || attachment.URL == "" {
+		t.Errorf("resolved attachment %+v, want log.txt", attachment)
+	}
+
+	// Resolved members come without their user, MemberOption adds it without changing the payload
+	if member := resolved.Members["6"]; member == nil || member.User != nil {
+		t.Errorf("resolved member %+v, want it without its user", member)
+	}
+}
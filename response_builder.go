@@ -0,0 +1,143 @@
+package httpcord
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResponseBuilder Message sent by Send as the reply of the interaction, see ConnectionContext.Reply
+type ResponseBuilder struct {
+	ctx    *ConnectionContext
+	data   InteractionCallbackData
+	update bool
+}
+
+// Reply Build the reply of the interaction:
+//
+//	ctx.Reply().Content("Banned %s", user.Username).Ephemeral().Components(row).Send()
+//
+// Send picks the call matching the state of the interaction, see ResponseBuilder.Send
+func (ctx *ConnectionContext) Reply() *ResponseBuilder {
+	return &ResponseBuilder{ctx: ctx}
+}
+
+// Content Set the content, formatted with fmt.Sprintf when args are given
+func (b *ResponseBuilder) Content(content string, args ...interface{}) *ResponseBuilder {
+	if len(args) > 0 {
+		content = fmt.Sprintf(content, args...)
+	}
+
+	b.data.Content = content
+	return b
+}
+
+func (b *ResponseBuilder) Embed(embeds ...*Embed) *ResponseBuilder {
+	b.data.Embeds = append(b.data.Embeds, embeds...)
+	return b
+}
+
+func (b *ResponseBuilder) Components(rows ...*ActionRowComponent) *ResponseBuilder {
+	b.data.Components = append(b.data.Components, rows...)
+	return b
+}
+
+func (b *ResponseBuilder) Files(files ...*DiscordFile) *ResponseBuilder {
+	b.data.Files = append(b.data.Files, files...)
+	return b
+}
+
+func (b *ResponseBuilder) AllowedMentions(mentions *AllowedMentions) *ResponseBuilder {
+	b.data.AllowedMentions = mentions
+	return b
+}
+
+// Poll Send the poll with the message, see PollCreate.Validate for the limits
+func (b *ResponseBuilder) Poll(poll *PollCreate) *ResponseBuilder {
+	b.data.Poll = poll
+	return b
+}
+
+// Ephemeral Only show the message to the user of the interaction, edits of a deferred reply keep the visibility
+// chosen by the defer (See DeferReplyWithFlags)
+func (b *ResponseBuilder) Ephemeral() *ResponseBuilder {
+	return b.Flags(EphemeralMessageFlag)
+}
+
+// SuppressEmbeds Do not include the embeds of the links in the content
+func (b *ResponseBuilder) SuppressEmbeds() *ResponseBuilder {
+	return b.Flags(SuppressEmbedsMessageFlag)
+}
+
+// Silent Send the message without push and desktop notifications
+func (b *ResponseBuilder) Silent() *ResponseBuilder {
+	return b.Flags(SuppressNotificationsMessageFlag)
+}
+
+// TTS Read the message aloud to the users viewing the channel
+func (b *ResponseBuilder) TTS() *ResponseBuilder {
+	b.data.TTS = true
+	return b
+}
+
+// Flags Add the message flags
+func (b *ResponseBuilder) Flags(flags MessageFlag) *ResponseBuilder {
+	b.data.Flags |= flags
+	return b
+}
+
+// Update Edit the message of the component interaction instead of sending a new one
+func (b *ResponseBuilder) Update() *ResponseBuilder {
+	b.update = true
+	return b
+}
+
+// Data Message built so far
+func (b *ResponseBuilder) Data() *InteractionCallbackData {
+	data := b.data
+	return &data
+}
+
+// Send Send the message as the initial response, or edit the original response once it was deferred, or send it as a
+// follow-up once the interaction has another response. Edits and follow-ups are not bound to Context
+func (b *ResponseBuilder) Send() error {
+	_, err := b.send(false)
+	return err
+}
+
+// SendMessage Like Send and return the message, initial responses are sent like ReplyInteractionMessage
+func (b *ResponseBuilder) SendMessage() (*Message, error) {
+	return b.send(true)
+}
+
+func (b *ResponseBuilder) send(withResponse bool) (*Message, error) {
+	data := b.Data()
+
+	responded, deferred := b.ctx.state.initialResponse()
+	switch {
+	case deferred:
+		return b.ctx.editOriginal(context.Background(), data.WebhookEdit())
+	case responded:
+		return b.ctx.createFollowUp(context.Background(), data.WebhookEdit())
+	}
+
+	response := &InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: data}
+	if b.update {
+		response.Type = UpdateMessageResponse
+	}
+
+	if withResponse {
+		return b.ctx.respondMessage(response)
+	}
+
+	return nil, b.ctx.SendRes(response)
+}
+
+// initialResponse Whether the interaction has its initial response and whether it was deferred
+func (s *interactionState) initialResponse() (responded, deferred bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	primary := s.primaryResponse
+	deferred = primary != nil && (primary.Type == DeferredChannelMessageWithSourceResponse || primary.Type == DeferredUpdateResponse)
+	return s.responded, deferred
+}
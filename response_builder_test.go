@@ -0,0 +1,156 @@
+package httpcord_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestResponseBuilderData(t *testing.T) {
+	embed := &httpcord.Embed{Title: "Banned"}
+	row := &httpcord.ActionRowComponent{Components: []httpcord.AnyComponent{&httpcord.ButtonComponent{CustomID: "undo"}}}
+	file := &httpcord.DiscordFile{Buffer: bytes.NewBufferString("log"), Filename: "ban.log"}
+	mentions := &httpcord.AllowedMentions{Parse: []string{}}
+	poll := &httpcord.PollCreate{Question: httpcord.PollMedia{Text: "Unban?"}}
+
+	tests := []struct {
+		name  string
+		build func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder
+		want  httpcord.InteractionCallbackData
+	}{
+		{"formatted content", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.Content("Banned %s for %d days", "target", 7)
+		}, httpcord.InteractionCallbackData{Content: "Banned target for 7 days"}},
+		{"content without args kept verbatim", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.Content("100% banned")
+		}, httpcord.InteractionCallbackData{Content: "100% banned"}},
+		{"last content wins", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.Content("first").Content("second")
+		}, httpcord.InteractionCallbackData{Content: "second"}},
+		{"flags combined", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.Ephemeral().Silent().SuppressEmbeds().Ephemeral()
+		}, httpcord.InteractionCallbackData{Flags: httpcord.EphemeralMessageFlag | httpcord.SuppressNotificationsMessageFlag |
+			httpcord.SuppressEmbedsMessageFlag}},
+		{"appended embeds, components and files", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.Embed(embed).Embed(embed).Components(row).Files(file)
+		}, httpcord.InteractionCallbackData{Embeds: []*httpcord.Embed{embed, embed}, Components: []*httpcord.ActionRowComponent{row},
+			Files: []*httpcord.DiscordFile{file}}},
+		{"mentions, poll and tts", func(b *httpcord.ResponseBuilder) *httpcord.ResponseBuilder {
+			return b.AllowedMentions(mentions).Poll(poll).TTS()
+		}, httpcord.InteractionCallbackData{AllowedMentions: mentions, Poll: poll, TTS: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, recorder := httpcordtest.NewContext(httpcordtest.NewCommandInteraction("ban"))
+			defer recorder.Finish()
+
+			if got := test.build(ctx.Reply()).Data(); !reflect.DeepEqual(*got, test.want) {
+				t.Errorf("Data() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResponseBuilderDataCopy(t *testing.T) {
+	ctx, recorder := httpcordtest.NewContext(httpcordtest.NewCommandInteraction("ban"))
+	defer recorder.Finish()
+
+	b := ctx.Reply().Content("banned")
+	b.Data().Content = "changed"
+
+	if err := b.Send(); err != nil {
+		t.Fatal(err)
+	}
+
+	if response := recorder.LastResponse(); response == nil || response.Data.Content != "banned" {
+		t.Errorf("response %+v, want the content of the builder", response)
+	}
+}
+
+func TestResponseBuilderSend(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *httpcord.Interaction
+		// before Response sent before the builder
+		before func(ctx *httpcord.ConnectionContext) error
+		update bool
+		// response Type of the initial response sent by the builder, 0 when it edits or follows up
+		response httpcord.InteractionCallbackType
+		edited   bool
+		followUp bool
+	}{
+		{"initial reply", httpcordtest.NewCommandInteraction("ban"), nil, false, httpcord.ChannelMessageWithSourceResponse, false, false},
+		{"update of the component message", httpcordtest.NewComponentInteraction("undo"), nil, true, httpcord.UpdateMessageResponse, false, false},
+		{"after a deferred reply", httpcordtest.NewCommandInteraction("ban"), (*httpcord.ConnectionContext).DeferReplyInteraction, false, 0,
+			true, false},
+		{"after a deferred update", httpcordtest.NewComponentInteraction("undo"), (*httpcord.ConnectionContext).DeferUpdateInteraction, true, 0,
+			true, false},
+		{"after a reply", httpcordtest.NewCommandInteraction("ban"), func(ctx *httpcord.ConnectionContext) error {
+			return ctx.ReplyInteraction(&httpcord.InteractionCallbackData{Content: "first"})
+		}, false, 0, false, true},
+	}
+
+	for _, test := range tests {
+		for _, withMessage := range []bool{false, true} {
+			name := test.name
+			if withMessage {
+				name += " with the message"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				ctx, recorder := httpcordtest.NewContext(test.interaction)
+				defer recorder.Finish()
+
+				if test.before != nil {
+					if err := test.before(ctx); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				b := ctx.Reply().Content("banned").Ephemeral()
+				if test.update {
+					b.Update()
+				}
+
+				var message *httpcord.Message
+				var err error
+				if withMessage {
+					message, err = b.SendMessage()
+				} else {
+					err = b.Send()
+				}
+
+				if err != nil {
+					t.Fatalf("Send() = %v", err)
+				}
+
+				if withMessage && (message == nil || message.Content != "banned") {
+					t.Errorf("SendMessage() = %+v, want the sent message", message)
+				}
+
+				responses := recorder.Responses()
+				if test.response != 0 {
+					if len(responses) != 1 || responses[0].Type != test.response || responses[0].Data.Content != "banned" ||
+						responses[0].Data.Flags != httpcord.EphemeralMessageFlag {
+						t.Errorf("responses %+v, want a %d response with the message", responses, test.response)
+					}
+				} else if len(responses) != 1 || responses[0].Data != nil && responses[0].Data.Content == "banned" {
+					t.Errorf("responses %+v, want only the first response", responses)
+				}
+
+				if edit := recorder.LastEdit(); (edit != nil && edit.Content == "banned") != test.edited {
+					t.Errorf("edit %+v, want edited %v", edit, test.edited)
+				}
+
+				followUps := recorder.FollowUps()
+				if (len(followUps) == 1 && followUps[0].Content == "banned" && followUps[0].Flags == httpcord.EphemeralMessageFlag) != test.followUp {
+					t.Errorf("follow-ups %+v, want followed up %v", followUps, test.followUp)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package httpcord
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type ResponseWarningKind int
+
+// Response Warning Kinds
+
+const (
+	// UnroutedComponentWarning An ephemeral message has a component no route, fallback or interaction handler answers.
+	// Ephemeral messages cannot be fixed by the app later, so the component stays dead
+	UnroutedComponentWarning ResponseWarningKind = iota + 1
+	// URLSchemeWarning A link button URL is not http, https or discord, Discord refuses or ignores it
+	URLSchemeWarning
+	// SelectMinValuesWarning A select menu requires more values than it has options, it can never be submitted
+	SelectMinValuesWarning
+)
+
+// ResponseWarning Dead end found in a response by the lint of ConnectionOptions.ValidateResponses, logged as a warning
+// and sent anyway
+type ResponseWarning struct {
+	Kind ResponseWarningKind
+	// Component is the custom_id of the component, or the URL of the link button
+	Component string
+}
+
+func (w ResponseWarning) String() string {
+	switch w.Kind {
+	case UnroutedComponentWarning:
+		return fmt.Sprintf("httpcord: ephemeral component %q has no handler", w.Component)
+	case URLSchemeWarning:
+		return fmt.Sprintf("httpcord: link button URL %q is not http, https or discord", w.Component)
+	default:
+		return fmt.Sprintf("httpcord: select menu %q requires more values than it has options", w.Component)
+	}
+}
+
+// linkSchemes URL schemes accepted for the link buttons
+var linkSchemes = map[string]bool{"http": true, "https": true, "discord": true}
+
+// lintResponse Warnings of the message components of the response, handled reports whether a component interaction
+// of the custom_id would be answered
+func lintResponse(response *InteractionResponse, handled func(customID string) bool) []ResponseWarning {
+	if response.Data == nil || response.Type == ModalResponse {
+		return nil
+	}
+
+	ephemeral := response.Data.Flags.Has(EphemeralMessageFlag)
+
+	var warnings []ResponseWarning
+
+	for _, row := range response.Data.Components {
+		if row == nil {
+			continue
+		}
+
+		for _, component := range row.Components {
+			switch c := component.(type) {
+			case *ButtonComponent:
+				warnings = append(warnings, lintButton(c, ephemeral, handled)...)
+			case ButtonComponent:
+				warnings = append(warnings, lintButton(&c, ephemeral, handled)...)
+			case *SelectMenuComponent:
+				warnings = append(warnings, lintSelectMenu(c, ephemeral, handled)...)
+			case SelectMenuComponent:
+				warnings = append(warnings, lintSelectMenu(&c, ephemeral, handled)...)
+			}
+		}
+	}
+
+	return warnings
+}
+
+func lintButton(c *ButtonComponent, ephemeral bool, handled func(customID string) bool) []ResponseWarning {
+	if c.URL != "" {
+		if u, err := url.Parse(c.URL); err != nil || !linkSchemes[u.Scheme] {
+			return []ResponseWarning{{Kind: URLSchemeWarning, Component: c.URL}}
+		}
+	} else if c.CustomID != "" && ephemeral && !handled(c.CustomID) {
+		return []ResponseWarning{{Kind: UnroutedComponentWarning, Component: c.CustomID}}
+	}
+
+	return nil
+}
+
+func lintSelectMenu(c *SelectMenuComponent, ephemeral bool, handled func(customID string) bool) []ResponseWarning {
+	var warnings []ResponseWarning
+
+	if c.Type == SelectMenuComponentType && c.MinValues != nil && *c.MinValues > len(c.Options) {
+		warnings = append(warnings, ResponseWarning{Kind: SelectMinValuesWarning, Component: c.CustomID})
+	}
+
+	if ephemeral && !handled(c.CustomID) {
+		warnings = append(warnings, ResponseWarning{Kind: UnroutedComponentWarning, Component: c.CustomID})
+	}
+
+	return warnings
+}
+
+// handlesComponent Whether a component interaction of the custom_id reaches a handler
+func (r *commandRouter) handlesComponent(customID string) bool {
+	if r.component(customID) != nil {
+		return true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, _, _ := r.userComponents.match(customID)
+	return route != nil || r.userComponents.fallback != nil
+}
+
+// LintResponse Dead ends of the response components, like ephemeral components without a route.
+// ConnectionOptions.ValidateResponses logs them for every response
+func (c *Connection) LintResponse(response *InteractionResponse) []ResponseWarning {
+	return lintResponse(response, c.componentHandled)
+}
+
+// componentHandled Whether a component interaction of the custom_id is answered by a route, an interaction handler
+// or the FallbackProxy
+func (c *Connection) componentHandled(customID string) bool {
+	return componentHandled(c.router, c.handlers, c.proxied, customID)
+}
+
+func componentHandled(router *commandRouter, handlers *interactionHandlers, proxy bool, customID string) bool {
+	return proxy || len(handlers.all()) > 0 || router.handlesComponent(customID)
+}
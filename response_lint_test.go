@@ -0,0 +1,50 @@
+package httpcord
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintResponse(t *testing.T) {
+	two := 2
+	handled := func(customID string) bool { return customID == "routed" }
+
+	tests := []struct {
+		name      string
+		ephemeral bool
+		component AnyComponent
+		want      []ResponseWarningKind
+	}{
+		{"link button", false, &ButtonComponent{URL: "https://discord.com"}, nil},
+		{"bad scheme", false, &ButtonComponent{URL: "ftp://example.com"}, []ResponseWarningKind{URLSchemeWarning}},
+		{"bad scheme value", false, ButtonComponent{URL: "ftp://example.com"}, []ResponseWarningKind{URLSchemeWarning}},
+		{"unrouted button", true, &ButtonComponent{CustomID: "lost"}, []ResponseWarningKind{UnroutedComponentWarning}},
+		{"unrouted button value", true, ButtonComponent{CustomID: "lost"}, []ResponseWarningKind{UnroutedComponentWarning}},
+		{"routed button value", true, ButtonComponent{CustomID: "routed"}, nil},
+		{"unrouted public button", false, ButtonComponent{CustomID: "lost"}, nil},
+		{"min values", false, &SelectMenuComponent{Type: SelectMenuComponentType, CustomID: "routed", MinValues: &two},
+			[]ResponseWarningKind{SelectMinValuesWarning}},
+		{"min values value", false, SelectMenuComponent{Type: SelectMenuComponentType, CustomID: "routed", MinValues: &two},
+			[]ResponseWarningKind{SelectMinValuesWarning}},
+		{"unrouted select value", true, SelectMenuComponent{Type: SelectMenuComponentType, CustomID: "lost"},
+			[]ResponseWarningKind{UnroutedComponentWarning}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := &InteractionCallbackData{Components: []*ActionRowComponent{{Components: []AnyComponent{test.component}}}}
+			if test.ephemeral {
+				data.Flags = EphemeralMessageFlag
+			}
+
+			var kinds []ResponseWarningKind
+			for _, warning := range lintResponse(&InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: data}, handled) {
+				kinds = append(kinds, warning.Kind)
+			}
+
+			if !reflect.DeepEqual(kinds, test.want) {
+				t.Errorf("lintResponse() = %v, want %v", kinds, test.want)
+			}
+		})
+	}
+}
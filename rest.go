@@ -0,0 +1,525 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"httpcord/endpoints"
+)
+
+var (
+	// ErrTokenResolution Returned when the TokenProvider fails to resolve a token
+	ErrTokenResolution = errors.New("httpcord: could not resolve token")
+	// ErrNilPayload The message payload passed to a RestClient method is nil
+	ErrNilPayload = errors.New("httpcord: nil message payload")
+)
+
+// TokenProvider Resolve the bot token used for REST calls of an application
+type TokenProvider interface {
+	Token(ctx context.Context, applicationID Snowflake) (string, error)
+}
+
+// StaticToken TokenProvider returning the same token for every application
+type StaticToken string
+
+func (t StaticToken) Token(context.Context, Snowflake) (string, error) {
+	return string(t), nil
+}
+
+// TokenProviderFunc Use a function as TokenProvider
+type TokenProviderFunc func(ctx context.Context, applicationID Snowflake) (string, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context, applicationID Snowflake) (string, error) {
+	return f(ctx, applicationID)
+}
+
+// DiscordAPIError Error response of the Discord API
+type DiscordAPIError struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	// Errors are the details of the validation errors keyed by the path of the invalid fields
+	Errors json.RawMessage `json:"errors,omitempty"`
+	// Fields are the validation errors of Errors flattened with their path
+	Fields []*FieldError `json:"-"`
+	// RetryAfter and Global are the rate limit of the 429 errors
+	RetryAfter time.Duration `json:"-"`
+	Global     bool          `json:"-"`
+}
+
+func (e *DiscordAPIError) Error() string {
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("discord api error %d (status %d): %s: %s", e.Code, e.StatusCode, e.Message, fieldErrorsText(e.Fields))
+	}
+
+	return fmt.Sprintf("discord api error %d (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Discord API Error Codes
+
+const (
+	UnknownMessageErrorCode     = 10008
+	UnknownWebhookErrorCode     = 10015
+	UnknownInteractionErrorCode = 10062
+	MissingAccessErrorCode      = 50001
+	CannotSendToUserErrorCode   = 50007
+	MissingPermissionsErrorCode = 50013
+	InvalidFormBodyErrorCode    = 50035
+)
+
+// isUnknownWebhook Whether the error is a 404 for an interaction webhook not processed yet
+func isUnknownWebhook(err error) bool {
+	var apiErr *DiscordAPIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound &&
+		(apiErr.Code == UnknownWebhookErrorCode || apiErr.Code == UnknownInteractionErrorCode)
+}
+
+// RestClient Send requests to the Discord API on behalf of an application
+type RestClient struct {
+	// BaseURL API URL the routes are appended to
+	BaseURL       string
+	HTTPClient    *http.Client
+	Tokens        TokenProvider
+	ApplicationID Snowflake
+	UserAgent     string
+	// EditMemo Skip original response edits identical to the last one sent for the token (Disabled when nil)
+	EditMemo *WebhookEditMemo
+	// MaxRetries Retries of rate limited and unavailable requests, POST requests are only retried when rate limited
+	MaxRetries int
+	// OnRateLimit Called with the bucket key of the route when a request is rate limited or waits for a bucket reset
+	OnRateLimit func(route string, retryAfter time.Duration, global bool)
+	// MultipartBoundary Boundary of the bodies with files (Random when nil)
+	MultipartBoundary BoundaryFunc
+	// Cache Channels and roles read by GetChannel and GetRole, fed by the resolved data of the interactions (Disabled when nil)
+	Cache *EntityCache
+	// BucketKeyFunc Rate limit bucket of a request, requests of a bucket are sent one at a time (Defaults to BucketKey)
+	BucketKeyFunc func(method, path string) string
+	// Logger Receive the requests at debug level and the rate limit waits at info level (Disabled when nil)
+	Logger Logger
+	// Metrics Receive httpcord_rest_requests_total and httpcord_rest_rate_limited_total labeled by route, with
+	// httpcord_rest_request_duration_seconds for a HistogramCollector (Disabled when nil)
+	Metrics MetricsCollector
+	// Tracer Start a RestSpan around each call, retries and rate limit waits included (Disabled when nil)
+	Tracer Tracer
+	// Codec Encode the payloads and decode the responses (Defaults to StdCodec)
+	Codec   Codec
+	limiter *rateLimiter
+}
+
+func (c *RestClient) logger() Logger {
+	if c.Logger == nil {
+		return NopLogger
+	}
+
+	return c.Logger
+}
+
+func (c *RestClient) metrics() MetricsCollector {
+	if c.Metrics == nil {
+		return NopMetrics
+	}
+
+	return c.Metrics
+}
+
+func (c *RestClient) codec() Codec {
+	if c.Codec == nil {
+		return StdCodec
+	}
+
+	return c.Codec
+}
+
+func (c *RestClient) tracer() Tracer {
+	if c.Tracer == nil {
+		return NopTracer
+	}
+
+	return c.Tracer
+}
+
+func (c *RestClient) bucketKey(method, path string) string {
+	if c.BucketKeyFunc != nil {
+		return c.BucketKeyFunc(method, path)
+	}
+
+	return BucketKey(method, path)
+}
+
+func NewRestClient(tokens TokenProvider) *RestClient {
+	return &RestClient{
+		BaseURL:    endpoints.FormatAPIURI(""),
+		HTTPClient: http.DefaultClient,
+		Tokens:     tokens,
+		UserAgent:  DefaultUserAgent,
+		MaxRetries: 3,
+		limiter:    newRateLimiter(),
+	}
+}
+
+// WithApplication Copy of the client resolving tokens for the application
+func (c *RestClient) WithApplication(applicationID Snowflake) *RestClient {
+	client := *c
+	client.ApplicationID = applicationID
+	return &client
+}
+
+// Do Send a request to the API route through the rate limiter, retries and authentication of the client.
+// The route may contain placeholders like {channel.id} filled with RouteParam, the response is decoded into out when not nil
+// with the numbers of interface{} values kept as json.Number (See DecodeJSON)
+func (c *RestClient) Do(ctx context.Context, method, route string, body, out interface{}, opts ...CallOption) error {
+	ctx, span := c.tracer().Start(ctx, RestSpan, map[string]string{"http.method": method, "http.route": metricRoute(route)})
+	err := c.do(ctx, span, method, route, body, out, opts)
+	endSpan(span, err)
+	return err
+}
+
+func (c *RestClient) do(ctx context.Context, span Span, method, route string, body, out interface{}, opts []CallOption) error {
+	o := callOptions{auth: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	path := route
+	for name, value := range o.params {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	if len(o.query) > 0 {
+		path += "?" + o.query.Encode()
+	}
+
+	payload, contentType, err := encodeBody(c.codec(), body, o.files, c.MultipartBoundary)
+	if err != nil {
+		return err
+	}
+
+	key := c.bucketKey(method, path)
+
+	logger, metrics := c.logger(), c.metrics()
+	labelRoute := metricRoute(route)
+
+	notify := func(retryAfter time.Duration, global bool) {
+		logger.Info("waiting for the rate limit", "route", logRoute(key), "retry_after", retryAfter, "global", global)
+
+		if c.OnRateLimit != nil {
+			c.OnRateLimit(key, retryAfter, global)
+		}
+	}
+
+	var bucket *rateLimitBucket
+	if c.limiter != nil {
+		bucket = c.limiter.bucket(key)
+		bucket.mu.Lock()
+		defer bucket.mu.Unlock()
+	}
+
+	retries := c.MaxRetries
+
+	for attempt := 0; ; attempt++ {
+		if bucket != nil {
+			if err := c.limiter.wait(ctx, bucket, notify); err != nil {
+				return err
+			}
+		}
+
+		start := time.Now()
+
+		res, resBody, err := c.send(ctx, method, path, payload, contentType, &o)
+		if err != nil {
+			// The transport errors repeat the URL with the interaction token
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				urlErr.URL = redactURL(urlErr.URL)
+			}
+
+			logger.Debug("rest request failed", "method", method, "route", logRoute(path), "attempt", attempt, "duration", time.Since(start), "error", err)
+			return err
+		}
+
+		elapsed := time.Since(start)
+		logger.Debug("rest request", "method", method, "route", logRoute(path), "status", res.StatusCode, "attempt", attempt, "duration", elapsed)
+
+		labels := map[string]string{"method": method, "route": labelRoute, "status": strconv.Itoa(res.StatusCode)}
+		metrics.IncCounter("httpcord_rest_requests_total", labels)
+		observeHistogram(metrics, "httpcord_rest_request_duration_seconds", labels, elapsed.Seconds())
+		span.SetAttribute("http.status_code", strconv.Itoa(res.StatusCode))
+
+		var retryAfter time.Duration
+		global := false
+
+		if res.StatusCode == http.StatusTooManyRequests {
+			var limited struct {
+				RetryAfter float64 `json:"retry_after"`
+				Global     bool    `json:"global"`
+			}
+
+			if json.Unmarshal(resBody, &limited) != nil || limited.RetryAfter == 0 {
+				limited.RetryAfter, _ = strconv.ParseFloat(res.Header.Get("Retry-After"), 64)
+			}
+
+			retryAfter = time.Duration(limited.RetryAfter * float64(time.Second))
+			global = limited.Global || res.Header.Get("X-RateLimit-Global") == "true"
+
+			metrics.IncCounter("httpcord_rest_rate_limited_total", map[string]string{"method": method, "route": labelRoute, "global": strconv.FormatBool(global)})
+
+			notify(retryAfter, global)
+		}
+
+		if bucket != nil {
+			c.limiter.update(bucket, res.Header, global, retryAfter)
+		}
+
+		retry := res.StatusCode == http.StatusTooManyRequests ||
+			(method != http.MethodPost && (res.StatusCode == http.StatusBadGateway || res.StatusCode == http.StatusServiceUnavailable || res.StatusCode == http.StatusGatewayTimeout))
+
+		if retry && attempt < retries {
+			if res.StatusCode != http.StatusTooManyRequests {
+				// Without a rate limit the backoff doubles every attempt
+				retryAfter = time.Duration(100<<attempt) * time.Millisecond
+			}
+
+			if bucket == nil || res.StatusCode != http.StatusTooManyRequests {
+				timer := time.NewTimer(retryAfter)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+
+			continue
+		}
+
+		if res.StatusCode >= http.StatusBadRequest {
+			apiErr := &DiscordAPIError{StatusCode: res.StatusCode, RetryAfter: retryAfter, Global: global}
+			if json.Unmarshal(resBody, apiErr) != nil || apiErr.Message == "" {
+				apiErr.Message = http.StatusText(res.StatusCode)
+			}
+
+			apiErr.Fields = parseFieldErrors(apiErr.Errors)
+
+			return apiErr
+		}
+
+		if out != nil && len(resBody) > 0 {
+			return c.codec().Unmarshal(resBody, out)
+		}
+
+		return nil
+	}
+}
+
+// send Perform a single HTTP request and read the whole response
+func (c *RestClient) send(ctx context.Context, method, path string, payload []byte, contentType string, o *callOptions) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set(UserAgentHeaderKey, c.UserAgent)
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if o.reason != "" {
+		req.Header.Set(ReasonHeaderKey, url.PathEscape(o.reason))
+	}
+
+	switch {
+	case o.bearer != "":
+		req.Header.Set(AuthorizationHeaderKey, "Bearer "+o.bearer)
+	case o.auth:
+		token, err := c.Tokens.Token(ctx, c.ApplicationID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrTokenResolution, err)
+		}
+
+		req.Header.Set(AuthorizationHeaderKey, "Bot "+token)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, resBody, nil
+}
+
+// logRoute Path of the logs without its query and with the tokens of the webhook and interaction callback routes
+// redacted, metricRoute templates it further
+func logRoute(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 2; i < len(segments); i++ {
+		switch segments[i-2] {
+		case "webhooks":
+			if segments[i] != "messages" {
+				segments[i] = "{token}"
+			}
+		case "interactions":
+			segments[i] = "{token}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// encodeBody JSON body, or a multipart body with the JSON in payload_json when there are files.
+// The body is encoded once so retries send the same bytes
+func encodeBody(codec Codec, body interface{}, files []*DiscordFile, boundary BoundaryFunc) ([]byte, string, error) {
+	if len(files) == 0 {
+		if body == nil {
+			return nil, "", nil
+		}
+
+		b, err := codec.Marshal(body)
+		return b, "application/json", err
+	}
+
+	var buf bytes.Buffer
+
+	contentType, err := writeMultipart(&buf, codec, boundary, body, files)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), contentType, nil
+}
+
+// CreateMessage Send a message in the channel as the application
+func (c *RestClient) CreateMessage(ctx context.Context, channelID Snowflake, data *MessageCreate) (*Message, error) {
+	var message Message
+	err := c.Do(ctx, http.MethodPost, endpoints.Messages(channelID.String()), data.withAttachments(), &message, WithFiles(data.Files...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// GetOriginalInteractionResponse Fetch the initial response message of an interaction
+func (c *RestClient) GetOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string) (*Message, error) {
+	var message Message
+	err := c.Do(ctx, http.MethodGet, endpoints.WebhookMessage(applicationID.String(), token, "@original"), nil, &message, withoutAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// EditOriginalInteractionResponse Edit the initial response message of an interaction.
+// With EditMemo an edit identical to the previous one returns the cached message, see ForceEdit
+func (c *RestClient) EditOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string, data *WebhookEdit, opts ...EditOption) (*Message, error) {
+	if data == nil {
+		return nil, ErrNilPayload
+	}
+
+	var o editOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var hash [sha256.Size]byte
+
+	if c.EditMemo != nil {
+		var err error
+		if hash, err = hashWebhookEdit(data); err != nil {
+			return nil, err
+		}
+
+		if message, ok := c.EditMemo.lookup(token, hash); ok && !o.force {
+			return message, nil
+		}
+	}
+
+	var message Message
+	err := c.Do(ctx, http.MethodPatch, endpoints.WebhookMessage(applicationID.String(), token, "@original"), data.withAttachments(), &message, withoutAuth(), WithFiles(data.Files...))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.EditMemo != nil {
+		c.EditMemo.store(token, hash, &message)
+	}
+
+	return &message, nil
+}
+
+// DeleteOriginalInteractionResponse Delete the initial response message of an interaction
+func (c *RestClient) DeleteOriginalInteractionResponse(ctx context.Context, applicationID Snowflake, token string) error {
+	if c.EditMemo != nil {
+		c.EditMemo.forget(token)
+	}
+
+	return c.Do(ctx, http.MethodDelete, endpoints.WebhookMessage(applicationID.String(), token, "@original"), nil, nil, withoutAuth())
+}
+
+// CreateFollowUpMessage Send a follow-up message for an interaction, the thread fields are validated like ExecuteWebhook
+func (c *RestClient) CreateFollowUpMessage(ctx context.Context, applicationID Snowflake, token string, data *WebhookEdit) (*Message, error) {
+	if data == nil {
+		return nil, ErrNilPayload
+	}
+
+	if err := data.validateThread(); err != nil {
+		return nil, err
+	}
+
+	var message Message
+	err := c.Do(ctx, http.MethodPost, endpoints.WebhookExecute(applicationID.String(), token), data.withAttachments(), &message,
+		withoutAuth(), WithFiles(data.Files...), WithQuery(threadQuery(data.ThreadID, url.Values{})))
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// ExecuteWebhook Send a message through a channel webhook and wait for it to be created.
+// ThreadID targets an existing thread and ThreadName creates a forum post, setting both is a WebhookThreadError
+func (c *RestClient) ExecuteWebhook(ctx context.Context, webhookID Snowflake, token string, data *WebhookEdit) (*Message, error) {
+	if data == nil {
+		return nil, ErrNilPayload
+	}
+
+	if err := data.validateThread(); err != nil {
+		return nil, err
+	}
+
+	var message Message
+	err := c.Do(ctx, http.MethodPost, endpoints.WebhookExecute(webhookID.String(), token), data.withAttachments(), &message,
+		withoutAuth(), WithFiles(data.Files...), WithQuery(threadQuery(data.ThreadID, url.Values{"wait": {"true"}})))
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,181 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNilPayload(t *testing.T) {
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = "http://127.0.0.1:0"
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() (*Message, error)
+	}{
+		{"EditOriginalInteractionResponse", func() (*Message, error) {
+			return client.EditOriginalInteractionResponse(ctx, "1", "token", nil)
+		}},
+		{"CreateFollowUpMessage", func() (*Message, error) {
+			return client.CreateFollowUpMessage(ctx, "1", "token", nil)
+		}},
+		{"EditFollowUpMessage", func() (*Message, error) {
+			return client.EditFollowUpMessage(ctx, "1", "token", "2", nil)
+		}},
+		{"ExecuteWebhook", func() (*Message, error) {
+			return client.ExecuteWebhook(ctx, "1", "token", nil)
+		}},
+		{"EditMessage", func() (*Message, error) {
+			return client.EditMessage(ctx, "1", "2", nil)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, err := test.call()
+			if !errors.Is(err, ErrNilPayload) {
+				t.Errorf("err = %v, want ErrNilPayload", err)
+			}
+
+			if message != nil {
+				t.Errorf("message = %v, want nil", message)
+			}
+		})
+	}
+}
+
+func TestTransportErrorRedaction(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(client *RestClient) error
+	}{
+		{"follow-up", func(client *RestClient) error {
+			_, err := client.CreateFollowUpMessage(context.Background(), "1", "secret-token", &WebhookEdit{Content: "done"})
+			return err
+		}},
+		{"original edit", func(client *RestClient) error {
+			_, err := client.EditOriginalInteractionResponse(context.Background(), "1", "secret-token", &WebhookEdit{Content: "done"})
+			return err
+		}},
+		{"original fetch", func(client *RestClient) error {
+			_, err := client.GetOriginalInteractionResponse(context.Background(), "1", "secret-token")
+			return err
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			client := NewRestClient(StaticToken("token"))
+			// Nothing listens on the port, the request fails in the transport
+			client.BaseURL = "http://127.0.0.1:1"
+			client.MaxRetries = 0
+			client.Logger = NewLogger(&logs, LogLevelDebug)
+
+			err := test.call(client)
+
+			var urlErr *url.Error
+			if !errors.As(err, &urlErr) {
+				t.Fatalf("err = %v, want a transport error", err)
+			}
+
+			if strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("the error %q has the token", err)
+			}
+
+			if !strings.Contains(logs.String(), "rest request failed") || strings.Contains(logs.String(), "secret-token") {
+				t.Errorf("logs %q, want the failure without the token", logs.String())
+			}
+		})
+	}
+}
+
+func TestTokenProvider(t *testing.T) {
+	var mu sync.Mutex
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get(AuthorizationHeaderKey))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"9"}`))
+	}))
+	defer server.Close()
+
+	perApplication := TokenProviderFunc(func(_ context.Context, applicationID Snowflake) (string, error) {
+		if applicationID == "3" {
+			return "", errors.New("unknown customer")
+		}
+
+		return "token-" + applicationID.String(), nil
+	})
+
+	tests := []struct {
+		name    string
+		options ConnectionOptions
+		// applications Application IDs of the interactions sent in order
+		applications []string
+		want         []string
+		err          error
+	}{
+		{"per application", ConnectionOptions{TokenProvider: perApplication}, []string{"1", "2"}, []string{"Bot token-1", "Bot token-2"}, nil},
+		{"resolved per call", ConnectionOptions{TokenProvider: perApplication}, []string{"2", "1", "2"}, []string{"Bot token-2", "Bot token-1", "Bot token-2"}, nil},
+		{"provider failure", ConnectionOptions{TokenProvider: perApplication}, []string{"3"}, nil, ErrTokenResolution},
+		{"static token", ConnectionOptions{Token: "static"}, []string{"1", "2"}, []string{"Bot static", "Bot static"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mu.Lock()
+			authorizations = nil
+			mu.Unlock()
+
+			options := test.options
+			options.Logger = NopLogger
+
+			conn, sign := signedConnection(t, options)
+			conn.Client.BaseURL = server.URL
+			conn.Client.MaxRetries = 0
+
+			var errs []error
+			conn.Command("ban", func(ctx ConnectionContext) {
+				_, err := ctx.Client().GetMessage(context.Background(), "3", "4")
+				errs = append(errs, err)
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "done"})
+			})
+
+			for _, application := range test.applications {
+				body := strings.Replace(string(commandBody()), `"application_id":"1"`, `"application_id":"`+application+`"`, 1)
+				conn.ServeHTTP(httptest.NewRecorder(), sign([]byte(body)))
+			}
+
+			for _, err := range errs {
+				if !errors.Is(err, test.err) {
+					t.Errorf("GetMessage() = %v, want %v", err, test.err)
+				}
+			}
+
+			if len(errs) != len(test.applications) {
+				t.Errorf("%d calls made, want %d", len(errs), len(test.applications))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if strings.Join(authorizations, ",") != strings.Join(test.want, ",") {
+				t.Errorf("authorizations %q, want %q", authorizations, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package httpcord
+
+import "errors"
+
+type RetentionFlag uint
+
+// Retention Flags
+
+const (
+	// KeepRawBody Keep the request body, see ConnectionContext.RawBody
+	KeepRawBody RetentionFlag = 1 << iota
+	// KeepResolved Keep the resolved users, members, roles, channels, messages and attachments of commands
+	KeepResolved
+	// KeepMessage Keep the message of component interactions
+	KeepMessage
+	// RetainNothing Drop every optional category, other flags are ignored
+	RetainNothing
+
+	DefaultRetention = KeepRawBody | KeepResolved | KeepMessage
+)
+
+// ErrNotRetained The data was dropped after parsing because of ConnectionOptions.Retention
+var ErrNotRetained = errors.New("httpcord: interaction data not retained")
+
+func (f RetentionFlag) Has(flag RetentionFlag) bool {
+	return f&RetainNothing == 0 && f&flag == flag
+}
+
+// retain Drop the interaction categories not kept by the flags
+func (f RetentionFlag) retain(interaction *Interaction) {
+	if !f.Has(KeepMessage) {
+		interaction.Message = nil
+	}
+
+	if data, ok := interaction.Data.(ApplicationCommandInteractionData); ok && !f.Has(KeepResolved) {
+		data.Resolved = ResolvedData{}
+		interaction.Data = data
+	}
+}
+
+// RawBody Body of the interaction request, a copy with ConnectionOptions.PoolObjects since the body is reused
+func (ctx *ConnectionContext) RawBody() ([]byte, error) {
+	if !ctx.options.Retention.Has(KeepRawBody) {
+		return nil, ErrNotRetained
+	}
+
+	if ctx.options.PoolObjects {
+		return append([]byte(nil), ctx.rawBody...), nil
+	}
+
+	return ctx.rawBody, nil
+}
+
+// Resolved Resolved data of the command interaction
+func (ctx *ConnectionContext) Resolved() (*ResolvedData, error) {
+	if !ctx.options.Retention.Has(KeepResolved) {
+		return nil, ErrNotRetained
+	}
+
+	data, ok := ctx.Interaction.Data.(ApplicationCommandInteractionData)
+	if !ok {
+		return nil, nil
+	}
+
+	return &data.Resolved, nil
+}
+
+// Message Message of the component interaction
+func (ctx *ConnectionContext) Message() (*Message, error) {
+	if !ctx.options.Retention.Has(KeepMessage) {
+		return nil, ErrNotRetained
+	}
+
+	return ctx.Interaction.Message, nil
+}
@@ -0,0 +1,138 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// heavyCommandBody Command interaction resolving n members with their users
+func heavyCommandBody(n int) []byte {
+	users := make([]string, n)
+	members := make([]string, n)
+
+	for i := range users {
+		users[i] = fmt.Sprintf(`"%d":{"id":"%d","username":"user %d","global_name":"%s"}`, 100+i, 100+i, i, strings.Repeat("x", 32))
+		members[i] = fmt.Sprintf(`"%d":{"nick":"member %d","roles":["10","11","12"],"joined_at":"2021-03-04T05:06:07.123456+00:00","permissions":"8"}`, 100+i, i)
+	}
+
+	return []byte(fmt.Sprintf(`{"id":"%s","application_id":"1","type":2,"token":"token","version":1,"guild_id":"2","channel_id":"3",`+
+		`"member":{"user":{"id":"4","username":"mod"},"roles":[],"permissions":"8"},`+
+		`"data":{"id":"5","name":"ban","type":1,"options":[{"type":6,"name":"user","value":"100"}],`+
+		`"resolved":{"users":{%s},"members":{%s}}}}`, nowSnowflake(), strings.Join(users, ","), strings.Join(members, ",")))
+}
+
+func TestRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention RetentionFlag
+		rawBody   error
+		resolved  error
+		message   error
+	}{
+		{"default", 0, nil, nil, nil},
+		{"everything", DefaultRetention, nil, nil, nil},
+		{"no raw body", KeepResolved | KeepMessage, ErrNotRetained, nil, nil},
+		{"no resolved", KeepRawBody | KeepMessage, nil, ErrNotRetained, nil},
+		{"no message", KeepRawBody | KeepResolved, nil, nil, ErrNotRetained},
+		{"nothing", RetainNothing, ErrNotRetained, ErrNotRetained, ErrNotRetained},
+		{"nothing overrides the other flags", RetainNothing | DefaultRetention, ErrNotRetained, ErrNotRetained, ErrNotRetained},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NopLogger, Retention: test.retention})
+
+			var ran int
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ran++
+
+				body, err := ctx.RawBody()
+				if !errors.Is(err, test.rawBody) || (err == nil && !strings.Contains(string(body), `"name":"ban"`)) {
+					t.Errorf("RawBody() = %.20s, %v, want %v", body, err, test.rawBody)
+				}
+
+				resolved, err := ctx.Resolved()
+				if !errors.Is(err, test.resolved) || (err == nil && (resolved == nil || resolved.Users["6"] == nil)) {
+					t.Errorf("Resolved() = %+v, %v, want %v", resolved, err, test.resolved)
+				}
+
+				if err != nil && len(ctx.Interaction.ApplicationCommandData().Resolved.Users) != 0 {
+					t.Error("the resolved data was kept on the interaction")
+				}
+
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			conn.Component("pick", func(ctx ConnectionContext) {
+				ran++
+
+				message, err := ctx.Message()
+				if !errors.Is(err, test.message) || (err == nil && (message == nil || message.Content != "pick one")) {
+					t.Errorf("Message() = %+v, %v, want %v", message, err, test.message)
+				}
+
+				if err != nil && ctx.Interaction.Message != nil {
+					t.Error("the message was kept on the interaction")
+				}
+
+				ctx.UpdateMessage(&InteractionCallbackData{Content: "picked"})
+			})
+
+			for _, body := range [][]byte{commandBody(), componentBody()} {
+				conn.ServeHTTP(httptest.NewRecorder(), sign(body))
+			}
+
+			if ran != 2 {
+				t.Errorf("%d handlers ran, want 2", ran)
+			}
+		})
+	}
+}
+
+func BenchmarkRetention(b *testing.B) {
+	body := heavyCommandBody(100)
+
+	tests := []struct {
+		name      string
+		retention RetentionFlag
+	}{
+		{"default", DefaultRetention},
+		{"nothing", RetainNothing},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			conn, sign := signedConnection(b, ConnectionOptions{Logger: NopLogger, Retention: test.retention})
+
+			// Handlers keeping the context alive, as the ones answering later do
+			retained := make([]ConnectionContext, 0, b.N)
+			conn.Command("ban", func(ctx ConnectionContext) {
+				retained = append(retained, ctx)
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				conn.ServeHTTP(httptest.NewRecorder(), sign(body))
+			}
+
+			b.StopTimer()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+
+			b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc))/float64(b.N), "retained-B/op")
+			runtime.KeepAlive(retained)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package httpcord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"httpcord/endpoints"
+)
+
+type RoleConnectionMetadataType int
+
+// Role Connection Metadata Types
+
+const (
+	IntegerLessThanOrEqualMetadataType RoleConnectionMetadataType = iota + 1
+	IntegerGreaterThanOrEqualMetadataType
+	IntegerEqualMetadataType
+	IntegerNotEqualMetadataType
+	DatetimeLessThanOrEqualMetadataType
+	DatetimeGreaterThanOrEqualMetadataType
+	BooleanEqualMetadataType
+	BooleanNotEqualMetadataType
+)
+
+const (
+	// MaxRoleConnectionMetadata Maximum metadata records of an application
+	MaxRoleConnectionMetadata = 5
+	// MaxRoleConnectionKeyLength Maximum length of the metadata keys
+	MaxRoleConnectionKeyLength = 50
+)
+
+// ErrInvalidRoleConnectionMetadata The metadata records would be refused by Discord
+var ErrInvalidRoleConnectionMetadata = errors.New("httpcord: invalid role connection metadata")
+
+// RoleConnectionMetadata Requirement the server admins can pick for a linked role, compared to the Metadata value
+// of the same key in the ApplicationRoleConnection of the user
+type RoleConnectionMetadata struct {
+	Type RoleConnectionMetadataType `json:"type"`
+	// Key is made of a-z, 0-9 and _ characters
+	Key                      string     `json:"key"`
+	Name                     string     `json:"name"`
+	NameLocalizations        Dictionary `json:"name_localizations,omitempty"`
+	Description              string     `json:"description"`
+	DescriptionLocalizations Dictionary `json:"description_localizations,omitempty"`
+}
+
+// ApplicationRoleConnection Connection of a user to the application, shown on the profile of the user
+type ApplicationRoleConnection struct {
+	PlatformName     string `json:"platform_name,omitempty"`
+	PlatformUsername string `json:"platform_username,omitempty"`
+	// Metadata values are keyed by RoleConnectionMetadata.Key, see SetInteger, SetDatetime and SetBoolean
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (c *ApplicationRoleConnection) set(key, value string) *ApplicationRoleConnection {
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string)
+	}
+
+	c.Metadata[key] = value
+	return c
+}
+
+// SetInteger Value of an integer metadata
+func (c *ApplicationRoleConnection) SetInteger(key string, value int64) *ApplicationRoleConnection {
+	return c.set(key, strconv.FormatInt(value, 10))
+}
+
+// SetDatetime Value of a datetime metadata, the requirements of the admins are a number of days since it
+func (c *ApplicationRoleConnection) SetDatetime(key string, value time.Time) *ApplicationRoleConnection {
+	return c.set(key, value.UTC().Format(time.RFC3339))
+}
+
+// SetBoolean Value of a boolean metadata
+func (c *ApplicationRoleConnection) SetBoolean(key string, value bool) *ApplicationRoleConnection {
+	if value {
+		return c.set(key, "1")
+	}
+
+	return c.set(key, "0")
+}
+
+// validateRoleConnectionMetadata At most MaxRoleConnectionMetadata records with unique keys of a-z, 0-9 and _
+func validateRoleConnectionMetadata(records []*RoleConnectionMetadata) error {
+	if len(records) > MaxRoleConnectionMetadata {
+		return fmt.Errorf("%w: %d records, the limit is %d", ErrInvalidRoleConnectionMetadata, len(records), MaxRoleConnectionMetadata)
+	}
+
+	keys := make(map[string]bool, len(records))
+	for _, record := range records {
+		if len(record.Key) == 0 || len(record.Key) > MaxRoleConnectionKeyLength {
+			return fmt.Errorf("%w: key %q must be 1 to %d characters", ErrInvalidRoleConnectionMetadata, record.Key, MaxRoleConnectionKeyLength)
+		}
+
+		for _, r := range record.Key {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+				return fmt.Errorf("%w: key %q can only have a-z, 0-9 and _", ErrInvalidRoleConnectionMetadata, record.Key)
+			}
+		}
+
+		if keys[record.Key] {
+			return fmt.Errorf("%w: key %q is repeated", ErrInvalidRoleConnectionMetadata, record.Key)
+		}
+
+		if record.Type < IntegerLessThanOrEqualMetadataType || record.Type > BooleanNotEqualMetadataType {
+			return fmt.Errorf("%w: key %q has the unknown type %d", ErrInvalidRoleConnectionMetadata, record.Key, record.Type)
+		}
+
+		keys[record.Key] = true
+	}
+
+	return nil
+}
+
+// GetRoleConnectionMetadata Metadata records of the application
+func (c *RestClient) GetRoleConnectionMetadata(ctx context.Context, applicationID Snowflake) ([]*RoleConnectionMetadata, error) {
+	var records []*RoleConnectionMetadata
+	if err := c.Do(ctx, http.MethodGet, endpoints.RoleConnectionMetadata(applicationID.String()), nil, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// RegisterRoleConnectionMetadata Replace the metadata records of the application, usually once at startup. The
+// records are checked first and refused with ErrInvalidRoleConnectionMetadata
+func (c *RestClient) RegisterRoleConnectionMetadata(ctx context.Context, applicationID Snowflake, records []*RoleConnectionMetadata) ([]*RoleConnectionMetadata, error) {
+	if err := validateRoleConnectionMetadata(records); err != nil {
+		return nil, err
+	}
+
+	if records == nil {
+		records = []*RoleConnectionMetadata{}
+	}
+
+	var registered []*RoleConnectionMetadata
+	if err := c.Do(ctx, http.MethodPut, endpoints.RoleConnectionMetadata(applicationID.String()), records, &registered); err != nil {
+		return nil, err
+	}
+
+	return registered, nil
+}
+
+// GetUserRoleConnection Role connection of the user of the OAuth2 access token, authorized with the
+// role_connections.write scope
+func (c *RestClient) GetUserRoleConnection(ctx context.Context, accessToken string, applicationID Snowflake) (*ApplicationRoleConnection, error) {
+	var connection ApplicationRoleConnection
+	if err := c.Do(ctx, http.MethodGet, endpoints.UserRoleConnection(applicationID.String()), nil, &connection, WithBearer(accessToken)); err != nil {
+		return nil, err
+	}
+
+	return &connection, nil
+}
+
+// UpdateUserRoleConnection Replace the role connection of the user of the OAuth2 access token, authorized with the
+// role_connections.write scope. Discord updates the linked roles of the user in every server
+func (c *RestClient) UpdateUserRoleConnection(ctx context.Context, accessToken string, applicationID Snowflake, connection *ApplicationRoleConnection) (*ApplicationRoleConnection, error) {
+	var updated ApplicationRoleConnection
+	if err := c.Do(ctx, http.MethodPut, endpoints.UserRoleConnection(applicationID.String()), connection, &updated, WithBearer(accessToken)); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,165 @@
+package httpcord_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestRegisterRoleConnectionMetadata(t *testing.T) {
+	record := func(key string, kind httpcord.RoleConnectionMetadataType) *httpcord.RoleConnectionMetadata {
+		return &httpcord.RoleConnectionMetadata{Type: kind, Key: key, Name: key, Description: "Requirement " + key}
+	}
+
+	tests := []struct {
+		name    string
+		records []*httpcord.RoleConnectionMetadata
+		// err Part of the ErrInvalidRoleConnectionMetadata error, empty when the records are sent
+		err string
+	}{
+		{"valid records", []*httpcord.RoleConnectionMetadata{
+			record("level", httpcord.IntegerGreaterThanOrEqualMetadataType),
+			record("joined_at", httpcord.DatetimeLessThanOrEqualMetadataType),
+			record("verified", httpcord.BooleanEqualMetadataType),
+		}, ""},
+		{"no records clear the metadata", nil, ""},
+		{"too many records", []*httpcord.RoleConnectionMetadata{
+			record("a", httpcord.BooleanEqualMetadataType), record("b", httpcord.BooleanEqualMetadataType),
+			record("c", httpcord.BooleanEqualMetadataType), record("d", httpcord.BooleanEqualMetadataType),
+			record("e", httpcord.BooleanEqualMetadataType), record("f", httpcord.BooleanEqualMetadataType),
+		}, "6 records, the limit is 5"},
+		{"empty key", []*httpcord.RoleConnectionMetadata{record("", httpcord.BooleanEqualMetadataType)}, `key "" must be 1 to 50 characters`},
+		{"long key", []*httpcord.RoleConnectionMetadata{record(strings.Repeat("k", 51), httpcord.BooleanEqualMetadataType)},
+			"must be 1 to 50 characters"},
+		{"uppercase key", []*httpcord.RoleConnectionMetadata{record("Level", httpcord.IntegerEqualMetadataType)},
+			`key "Level" can only have a-z, 0-9 and _`},
+		{"dashed key", []*httpcord.RoleConnectionMetadata{record("joined-at", httpcord.IntegerEqualMetadataType)}, "can only have a-z, 0-9 and _"},
+		{"repeated key", []*httpcord.RoleConnectionMetadata{
+			record("level", httpcord.IntegerGreaterThanOrEqualMetadataType),
+			record("level", httpcord.IntegerLessThanOrEqualMetadataType),
+		}, `key "level" is repeated`},
+		{"unknown type", []*httpcord.RoleConnectionMetadata{record("level", 9)}, `key "level" has the unknown type 9`},
+		{"missing type", []*httpcord.RoleConnectionMetadata{record("level", 0)}, "has the unknown type 0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t)
+			fake.Allow(http.MethodPut, "/applications/5/role-connections/metadata").RespondWith(test.records)
+
+			registered, err := fake.Client().RegisterRoleConnectionMetadata(context.Background(), "5", test.records)
+
+			if test.err != "" {
+				if !errors.Is(err, httpcord.ErrInvalidRoleConnectionMetadata) || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("RegisterRoleConnectionMetadata() = %v, want %q", err, test.err)
+				}
+
+				if requests := fake.Requests(); len(requests) != 0 {
+					t.Errorf("%d requests sent, want the records refused first", len(requests))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requests := fake.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("%d requests sent, want 1", len(requests))
+			}
+
+			// The records replace the registered ones, an empty array clears them
+			var sent []*httpcord.RoleConnectionMetadata
+			if err := requests[0].Decode(&sent); err != nil || sent == nil || len(sent) != len(test.records) {
+				t.Fatalf("body %s, %v, want the %d records", requests[0].Body, err, len(test.records))
+			}
+
+			for i, record := range test.records {
+				if !reflect.DeepEqual(sent[i], record) {
+					t.Errorf("record %d = %+v, want %+v", i, sent[i], record)
+				}
+			}
+
+			if len(registered) != len(test.records) {
+				t.Errorf("RegisterRoleConnectionMetadata() = %v, want the registered records", registered)
+			}
+		})
+	}
+}
+
+func TestGetRoleConnectionMetadata(t *testing.T) {
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodGet, "/applications/5/role-connections/metadata").RespondStatus(http.StatusOK, []byte(`[
+		{"type":2,"key":"level","name":"Level","name_localizations":{"fr":"Niveau"},"description":"Minimum level"}
+	]`))
+
+	records, err := fake.Client().GetRoleConnectionMetadata(context.Background(), "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*httpcord.RoleConnectionMetadata{{Type: httpcord.IntegerGreaterThanOrEqualMetadataType, Key: "level", Name: "Level",
+		NameLocalizations: httpcord.Dictionary{httpcord.FrenchLocale: "Niveau"}, Description: "Minimum level"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("GetRoleConnectionMetadata() = %+v, want %+v", records, want)
+	}
+
+	if auth := fake.Requests()[0].Header.Get("Authorization"); auth != "Bot fake-token" {
+		t.Errorf("Authorization %q, want the bot token", auth)
+	}
+}
+
+func TestUserRoleConnection(t *testing.T) {
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodPut, "/users/@me/applications/5/role-connection").RespondStatus(http.StatusOK,
+		[]byte(`{"platform_name":"Arena","platform_username":"ana","metadata":{"level":"12","verified":"1"}}`))
+	fake.Expect(http.MethodGet, "/users/@me/applications/5/role-connection").RespondStatus(http.StatusOK,
+		[]byte(`{"platform_name":"Arena","metadata":{"level":"12"}}`))
+
+	joined := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	connection := (&httpcord.ApplicationRoleConnection{PlatformName: "Arena", PlatformUsername: "ana"}).
+		SetInteger("level", 12).
+		SetDatetime("joined_at", joined).
+		SetBoolean("verified", true).
+		SetBoolean("banned", false)
+
+	updated, err := fake.Client().UpdateUserRoleConnection(context.Background(), "access", "5", connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.PlatformUsername != "ana" || updated.Metadata["level"] != "12" {
+		t.Errorf("UpdateUserRoleConnection() = %+v, want the updated connection", updated)
+	}
+
+	var sent httpcord.ApplicationRoleConnection
+	if err := fake.Requests()[0].Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"level": "12", "joined_at": "2024-03-01T11:30:00Z", "verified": "1", "banned": "0"}
+	if sent.PlatformName != "Arena" || !reflect.DeepEqual(sent.Metadata, want) {
+		t.Errorf("body %+v, want the metadata %v", sent, want)
+	}
+
+	current, err := fake.Client().GetUserRoleConnection(context.Background(), "access", "5")
+	if err != nil || current.PlatformName != "Arena" || current.Metadata["level"] != "12" {
+		t.Errorf("GetUserRoleConnection() = %+v, %v, want the connection", current, err)
+	}
+
+	// The calls are authorized by the user, not the bot
+	for _, request := range fake.Requests() {
+		if auth := request.Header.Get("Authorization"); auth != "Bearer access" {
+			t.Errorf("%s %s Authorization %q, want the access token", request.Method, request.Path, auth)
+		}
+	}
+}
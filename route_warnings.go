@@ -0,0 +1,206 @@
+package httpcord
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CustomIDError A component or modal custom_id is over MaxCustomIDLength
+type CustomIDError struct {
+	CustomID string
+	Length   int
+}
+
+func (e *CustomIDError) Error() string {
+	return fmt.Sprintf("httpcord: custom_id %q has %d characters, the limit is %d", e.CustomID, e.Length, MaxCustomIDLength)
+}
+
+func (e *CustomIDError) Is(target error) bool {
+	return target == ErrCustomIDTooLong
+}
+
+// checkCustomID CustomIDError when the custom_id is over MaxCustomIDLength
+func checkCustomID(customID string) error {
+	if length := utf8.RuneCountInString(customID); length > MaxCustomIDLength {
+		return &CustomIDError{CustomID: customID, Length: length}
+	}
+
+	return nil
+}
+
+func (b *ButtonComponent) Validate() error {
+	return checkCustomID(b.CustomID)
+}
+
+func (s *SelectMenuComponent) Validate() error {
+	return checkCustomID(s.CustomID)
+}
+
+func (t *TextInputComponent) Validate() error {
+	return checkCustomID(t.CustomID)
+}
+
+// Validate Check the custom_id of every component of the row
+func (a *ActionRowComponent) Validate() error {
+	return ValidateComponents(a.Components...)
+}
+
+// Validate Check the custom_id of the modal and its text inputs
+func (m *Modal) Validate() error {
+	if err := checkCustomID(m.CustomID); err != nil {
+		return err
+	}
+
+	for _, row := range m.Components {
+		if row == nil {
+			continue
+		}
+
+		if err := row.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateComponents Check the custom_id of the components built with the builders, rows included.
+// Returns the first CustomIDError
+func ValidateComponents(components ...AnyComponent) error {
+	for _, component := range components {
+		validator, ok := component.(interface{ Validate() error })
+		if !ok {
+			continue
+		}
+
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type RouteWarningKind int
+
+// Route Warning Kinds
+
+const (
+	// CommandPrefixOverlap A component or modal custom_id or prefix is named like a command, like "close:*" and /close
+	CommandPrefixOverlap RouteWarningKind = iota + 1
+	// CaseOnlyOverlap Two component or modal custom_ids only differ by case
+	CaseOnlyOverlap
+)
+
+// RouteWarning Confusing routes that work, unlike the errors of ValidateRoutes (See Connection.RouteWarnings)
+type RouteWarning struct {
+	Kind RouteWarningKind
+	// Routes are the overlapping command names and custom_id patterns
+	Routes []string
+	// Sources are the file:line the routes were registered at, in the order of Routes
+	Sources []string
+}
+
+func (w RouteWarning) String() string {
+	routes := make([]string, len(w.Routes))
+	for i, route := range w.Routes {
+		routes[i] = fmt.Sprintf("%q (%s)", route, w.Sources[i])
+	}
+
+	if w.Kind == CommandPrefixOverlap {
+		return "httpcord: custom_id " + routes[1] + " is named like the command " + routes[0]
+	}
+
+	return "httpcord: custom_ids " + strings.Join(routes, ", ") + " only differ by case"
+}
+
+// customIDSeparators Characters ending the prefix of the custom_id patterns like "close:*"
+const customIDSeparators = ":_-/|.*"
+
+// warnings Collect the confusing overlaps of the routes
+func (r *commandRouter) warnings() []RouteWarning {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	commands := make(map[string]*CommandRoute)
+	for name, route := range r.commands {
+		command := strings.ToLower(topCommand(name))
+		if command == "" {
+			continue
+		}
+
+		if existing, ok := commands[command]; !ok || len(route.Name) < len(existing.Name) {
+			commands[command] = route
+		}
+	}
+
+	var warnings []RouteWarning
+
+	for _, routes := range []*componentRoutes{&r.userComponents, &r.modals} {
+		patterns := routes.all()
+		cases := make(map[string][]*ComponentRoute)
+
+		for _, route := range patterns {
+			name := strings.ToLower(strings.TrimRight(route.Pattern, customIDSeparators))
+
+			if command, ok := commands[name]; ok && name != "" {
+				warnings = append(warnings, RouteWarning{
+					Kind:    CommandPrefixOverlap,
+					Routes:  []string{topCommand(command.Name), route.Pattern},
+					Sources: []string{command.Source, route.Source},
+				})
+			}
+
+			key := strings.ToLower(route.Pattern)
+			cases[key] = append(cases[key], route)
+		}
+
+		for _, group := range cases {
+			if len(group) < 2 {
+				continue
+			}
+
+			warning := RouteWarning{Kind: CaseOnlyOverlap}
+			for _, route := range group {
+				warning.Routes = append(warning.Routes, route.Pattern)
+				warning.Sources = append(warning.Sources, route.Source)
+			}
+
+			warnings = append(warnings, warning)
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].String() < warnings[j].String()
+	})
+
+	return warnings
+}
+
+// all Routes sorted by pattern
+func (r *componentRoutes) all() []*ComponentRoute {
+	routes := make([]*ComponentRoute, 0, len(r.exact)+len(r.templates)+len(r.prefixes))
+	for _, route := range r.exact {
+		routes = append(routes, route)
+	}
+
+	for _, template := range r.templates {
+		routes = append(routes, template.route)
+	}
+
+	routes = append(routes, r.prefixes...)
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern < routes[j].Pattern
+	})
+
+	return routes
+}
+
+// RouteWarnings Confusing overlaps of the routes, like a component prefix named like a command or custom_ids only
+// differing by case. They do not fail ValidateRoutes, MustValidateRoutes logs them
+func (c *Connection) RouteWarnings() []RouteWarning {
+	return c.router.warnings()
+}
@@ -0,0 +1,138 @@
+package httpcord
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouteWarnings(t *testing.T) {
+	handler := func(ConnectionContext) {}
+
+	tests := []struct {
+		name     string
+		register func(c *Connection)
+		// warnings Kind and routes of each warning, in the order of RouteWarnings
+		warnings []string
+	}{
+		{"component prefix named like a command", func(c *Connection) {
+			c.Command("close", handler)
+			c.Component("close:*", handler)
+		}, []string{"1 close close:*"}},
+		{"exact component named like a command", func(c *Connection) {
+			c.Command("close", handler)
+			c.Component("close", handler)
+		}, []string{"1 close close"}},
+		{"modal named like a subcommand parent", func(c *Connection) {
+			c.Command("ticket open", handler)
+			c.Modal("Ticket_", handler)
+		}, []string{"1 ticket Ticket_"}},
+		{"component under another name", func(c *Connection) {
+			c.Command("close", handler)
+			c.Component("closed:*", handler)
+			c.Component("close:{id}", handler)
+		}, nil},
+		{"components only differing by case", func(c *Connection) {
+			c.Component("Confirm", handler)
+			c.Component("confirm", handler)
+			c.Component("cancel", handler)
+		}, []string{"2 Confirm confirm"}},
+		{"modals only differing by case", func(c *Connection) {
+			c.Modal("feedback:*", handler)
+			c.Modal("Feedback:*", handler)
+		}, []string{"2 Feedback:* feedback:*"}},
+		{"component and modal differing by case", func(c *Connection) {
+			c.Component("report", handler)
+			c.Modal("Report", handler)
+		}, nil},
+		{"both overlaps", func(c *Connection) {
+			c.Command("poll", handler)
+			c.Component("Poll:*", handler)
+			c.Component("poll:*", handler)
+		}, []string{"1 poll Poll:*", "1 poll poll:*", "2 Poll:* poll:*"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{Logger: NopLogger})
+			test.register(conn)
+
+			var got []string
+			for _, warning := range conn.RouteWarnings() {
+				got = append(got, strings.Join(append([]string{string(rune('0' + warning.Kind))}, warning.Routes...), " "))
+
+				for _, source := range warning.Sources {
+					if !strings.HasPrefix(source, "route_warnings_test.go:") {
+						t.Errorf("warning %v registered at %q, want this file", warning.Routes, source)
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.warnings) {
+				t.Errorf("RouteWarnings() = %q, want %q", got, test.warnings)
+			}
+
+			if err := conn.ValidateRoutes(); err != nil {
+				t.Errorf("ValidateRoutes() = %v, want the warnings apart from the errors", err)
+			}
+		})
+	}
+}
+
+func TestMustValidateRoutesLogsWarnings(t *testing.T) {
+	var buf bytes.Buffer
+
+	conn := newTestConnection(t, ConnectionOptions{Logger: NewLogger(&buf, LogLevelDebug)})
+	conn.Command("close", func(ConnectionContext) {})
+	conn.Component("close:*", func(ConnectionContext) {})
+
+	conn.MustValidateRoutes()
+
+	if !strings.Contains(buf.String(), `custom_id "close:*"`) || !strings.Contains(buf.String(), `the command "close"`) {
+		t.Errorf("logged %q, want the overlap of close:* with /close", buf.String())
+	}
+}
+
+func TestValidateComponentsCustomID(t *testing.T) {
+	long := strings.Repeat("x", MaxCustomIDLength+1)
+	limit := strings.Repeat("é", MaxCustomIDLength)
+
+	tests := []struct {
+		name     string
+		validate func() error
+		customID string
+		tooLong  bool
+	}{
+		{"button at the limit in runes", func() error { return ValidateComponents(&ButtonComponent{CustomID: limit}) }, "", false},
+		{"button", func() error { return ValidateComponents(&ButtonComponent{CustomID: long}) }, long, true},
+		{"select in a row", func() error {
+			return ValidateComponents(&ActionRowComponent{Components: []AnyComponent{&ButtonComponent{CustomID: "ok"}, &SelectMenuComponent{CustomID: long}}})
+		}, long, true},
+		{"modal", func() error { return (&Modal{CustomID: long}).Validate() }, long, true},
+		{"text input of a modal", func() error {
+			return (&Modal{CustomID: "feedback", Components: []*ActionRowComponent{nil, {Components: []AnyComponent{&TextInputComponent{CustomID: long}}}}}).Validate()
+		}, long, true},
+		{"raw component left unchecked", func() error { return ValidateComponents(map[string]interface{}{"custom_id": long}) }, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.validate()
+			if !test.tooLong {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var customIDErr *CustomIDError
+			if !errors.Is(err, ErrCustomIDTooLong) || !errors.As(err, &customIDErr) || customIDErr.CustomID != test.customID ||
+				customIDErr.Length != MaxCustomIDLength+1 {
+				t.Errorf("Validate() = %v, want a CustomIDError of %d characters", err, MaxCustomIDLength+1)
+			}
+		})
+	}
+}
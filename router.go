@@ -0,0 +1,633 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	Handler              func(ctx ConnectionContext)
+	OptionConstraintKind int
+)
+
+// Option Constraint Kinds
+
+const (
+	// MutuallyExclusiveConstraint At most one of the options can be provided
+	MutuallyExclusiveConstraint OptionConstraintKind = iota + 1
+	// RequireOneOfConstraint At least one of the options must be provided
+	RequireOneOfConstraint
+)
+
+type OptionConstraint struct {
+	Kind    OptionConstraintKind
+	Options []string
+}
+
+// OptionConstraintError Returned when an interaction does not satisfy a command OptionConstraint
+type OptionConstraintError struct {
+	Constraint OptionConstraint
+	// Provided are the constrained options present in the interaction
+	Provided []string
+}
+
+func (e *OptionConstraintError) Error() string {
+	if e.Constraint.Kind == MutuallyExclusiveConstraint {
+		return "options " + strings.Join(e.Provided, ", ") + " are mutually exclusive"
+	}
+
+	return "one of the options " + strings.Join(e.Constraint.Options, ", ") + " is required"
+}
+
+// CommandRoute Handler and metadata of a registered command
+type CommandRoute struct {
+	Name string
+	// Type is UserApplicationCommandType or MessageApplicationCommandType for the context menu commands
+	Type        ApplicationCommandType
+	Handler     Handler
+	Definition  *ApplicationCommand
+	Constraints []OptionConstraint
+	// Autocomplete handles the autocomplete interactions of the command
+	Autocomplete Handler
+	// OptionAutocomplete handles the autocomplete interactions of the focused option by name, before Autocomplete
+	OptionAutocomplete map[string]Handler
+	// Group is used to group commands in listings like the help command
+	Group string
+	// FeatureName gates the command with ConnectionOptions.FeatureGate
+	FeatureName string
+	// FeatureDeniedReply is sent where the feature is disabled, the interaction is left unhandled when nil
+	FeatureDeniedReply *InteractionCallbackData
+	// Normalization cleans the string options before the handler runs (Defaults to ConnectionOptions.NormalizeStrings)
+	Normalization *StringNormalization
+	// Source is the file:line the route was registered at
+	Source string
+	// MaxConcurrency is the number of interactions of the command handled at once, the others are refused with a
+	// DispatchBusyError (Unlimited when 0)
+	MaxConcurrency int
+	running        int32
+	// autocompleteSources are the file:line the autocomplete handlers were set at, "" for Autocomplete and the
+	// option name for OptionAutocomplete
+	autocompleteSources map[string]string
+}
+
+// RouteConflictError Two routes registered for the same key
+type RouteConflictError struct {
+	Kind     string
+	Key      string
+	Source   string
+	Existing string
+}
+
+func (e *RouteConflictError) Error() string {
+	return fmt.Sprintf("httpcord: %s %q registered at %s is already registered at %s", e.Kind, e.Key, e.Source, e.Existing)
+}
+
+type commandRouter struct {
+	mu       sync.RWMutex
+	commands map[string]*CommandRoute
+	// contextMenus are the routes of Connection.UserCommand and Connection.MessageCommand
+	contextMenus map[contextMenuKey]*CommandRoute
+	// components are internal component handlers keyed by custom_id prefix
+	components map[string]*ComponentRoute
+	// fallback handles the application commands without a route
+	fallback       Handler
+	fallbackSource string
+	// userComponents are the routes of Connection.Component
+	userComponents componentRoutes
+	// modals are the routes of Connection.Modal
+	modals componentRoutes
+}
+
+func newCommandRouter() *commandRouter {
+	return &commandRouter{
+		commands:       make(map[string]*CommandRoute),
+		contextMenus:   make(map[contextMenuKey]*CommandRoute),
+		components:     make(map[string]*ComponentRoute),
+		userComponents: componentRoutes{exact: make(map[string]*ComponentRoute)},
+		modals:         componentRoutes{exact: make(map[string]*ComponentRoute)},
+	}
+}
+
+func (r *commandRouter) add(route *CommandRoute) *CommandRoute {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.commands[route.Name]; ok {
+		panic(&RouteConflictError{Kind: "command", Key: route.Name, Source: route.Source, Existing: existing.Source})
+	}
+
+	r.commands[route.Name] = route
+	return route
+}
+
+// validate Collect the problems of every registered route
+func (r *commandRouter) validate() []error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var errs []error
+
+	for name, route := range r.commands {
+		if route.Handler == nil {
+			errs = append(errs, fmt.Errorf("httpcord: command %q registered at %s has no handler", name, route.Source))
+		}
+
+		command := topCommand(name)
+		if command == "" {
+			errs = append(errs, fmt.Errorf("httpcord: command registered at %s has an empty name", route.Source))
+			continue
+		}
+
+		// Subcommand routes are defined by their top level command
+		if route.Definition != nil && route.Definition.Name != "" && route.Definition.Name != command {
+			errs = append(errs, fmt.Errorf("httpcord: command %q registered at %s is defined as %q", name, route.Source, route.Definition.Name))
+		}
+
+		if route.Definition != nil {
+			if err := route.Definition.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%w (registered at %s)", err, route.Source))
+			}
+
+			for option := range route.OptionAutocomplete {
+				if !definesOption(route.Definition.Options, option) {
+					errs = append(errs, fmt.Errorf("httpcord: autocomplete of command %q set at %s references unknown option %q", name, route.autocompleteSource(option), option))
+				}
+			}
+		}
+	}
+
+	for _, route := range r.contextMenus {
+		if route.Handler == nil {
+			errs = append(errs, fmt.Errorf("httpcord: context menu command %q registered at %s has no handler", route.Name, route.Source))
+		}
+
+		if definition := route.Definition; definition != nil && (definition.Name != "" && definition.Name != route.Name || definition.Type != nil && *definition.Type != route.Type) {
+			errs = append(errs, fmt.Errorf("httpcord: context menu command %q registered at %s does not match its definition %q", route.Name, route.Source, definition.Name))
+		}
+
+		if route.Definition != nil {
+			if err := route.Definition.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%w (registered at %s)", err, route.Source))
+			}
+		}
+	}
+
+	for kind, routes := range map[string]*componentRoutes{"component": &r.userComponents, "modal": &r.modals} {
+		for _, route := range routes.all() {
+			if err := checkCustomID(strings.TrimSuffix(route.Pattern, "*")); err != nil {
+				errs = append(errs, fmt.Errorf("httpcord: %s %q registered at %s can never match: %w", kind, route.Pattern, route.Source, err))
+			}
+
+			if kind != "component" {
+				continue
+			}
+
+			// The internal components are matched first
+			for prefix, internal := range r.components {
+				if strings.HasPrefix(route.Pattern, prefix) {
+					errs = append(errs, &RouteConflictError{Kind: kind, Key: route.Pattern, Source: route.Source, Existing: internal.Source})
+				}
+			}
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errs
+}
+
+// callerSite file:line of the caller skip frames above the caller of callerSite
+func callerSite(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
+
+func (r *commandRouter) get(name string) *CommandRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.commands[name]
+}
+
+// routes Registered command routes sorted by name
+func (r *commandRouter) routes() []*CommandRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]*CommandRoute, 0, len(r.commands))
+	for _, route := range r.commands {
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Name < routes[j].Name
+	})
+
+	return routes
+}
+
+// addComponent Register an internal component handler, panics when a registered prefix overlaps the prefix
+func (r *commandRouter) addComponent(prefix string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source := callerSite(2)
+	for existing, route := range r.components {
+		if strings.HasPrefix(prefix, existing) || strings.HasPrefix(existing, prefix) {
+			panic(&RouteConflictError{Kind: "component prefix", Key: prefix, Source: source, Existing: route.Source})
+		}
+	}
+
+	r.components[prefix] = &ComponentRoute{Pattern: prefix + "*", Handler: handler, Source: source}
+}
+
+func (r *commandRouter) component(customID string) Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for prefix, route := range r.components {
+		if strings.HasPrefix(customID, prefix) {
+			return route.Handler
+		}
+	}
+
+	return nil
+}
+
+// Command Register a handler for application commands with the given name, panics when the name is already registered.
+// Subcommands and subcommand groups are routed with their path like "config set language", the longest
+// registered path handles the interaction so "config" receives the subcommands without their own route.
+// The guards run in order before the handler, see Guarded
+func (c *Connection) Command(name string, handler Handler, guards ...Guard) *CommandRoute {
+	return c.router.add(&CommandRoute{Name: strings.Join(strings.Fields(name), " "), Handler: guardHandler(handler, guards), Source: callerSite(1)})
+}
+
+// FallbackCommand Handle the application commands and autocompletes without a route, before the FallbackProxy.
+// Panics when a fallback is already registered
+func (c *Connection) FallbackCommand(handler Handler) {
+	c.router.mu.Lock()
+	defer c.router.mu.Unlock()
+
+	source := callerSite(1)
+	if c.router.fallback != nil {
+		panic(&RouteConflictError{Kind: "fallback command", Key: "*", Source: source, Existing: c.router.fallbackSource})
+	}
+
+	c.router.fallback, c.router.fallbackSource = handler, source
+}
+
+// lookup Route of the longest registered prefix of the command path
+func (r *commandRouter) lookup(data *ApplicationCommandInteractionData) *CommandRoute {
+	path := routePath(data)
+
+	for i := len(path); i > 0; i-- {
+		if route := r.get(strings.Join(path[:i], " ")); route != nil {
+			return route
+		}
+	}
+
+	return nil
+}
+
+func (r *commandRouter) fallbackHandler() Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.fallback
+}
+
+// routePath Command name followed by the invoked subcommand group and subcommand
+func routePath(data *ApplicationCommandInteractionData) []string {
+	path := []string{data.Name}
+	options := data.Options
+
+	for len(options) == 1 && (options[0].Type == SubCommandApplicationCommandOptionType || options[0].Type == SubCommandGroupApplicationCommandOptionType) {
+		path = append(path, options[0].Name)
+		options = options[0].Options
+	}
+
+	return path
+}
+
+// ValidateRoutes Scan every registered route for conflicts and invalid metadata
+func (c *Connection) ValidateRoutes() error {
+	errs := c.router.validate()
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return errors.New(strings.Join(messages, "\n"))
+}
+
+// MustValidateRoutes Like ValidateRoutes but panics, meant to be called before Connect. The RouteWarnings are logged
+func (c *Connection) MustValidateRoutes() {
+	if err := c.ValidateRoutes(); err != nil {
+		panic(err)
+	}
+
+	for _, warning := range c.RouteWarnings() {
+		c.logger.Warn(warning.String())
+	}
+}
+
+// SetAutocomplete Set the handler of the command autocomplete interactions, panics when one is already set
+func (r *CommandRoute) SetAutocomplete(handler Handler) *CommandRoute {
+	r.setAutocomplete(handler, callerSite(1))
+	return r
+}
+
+func (r *CommandRoute) setAutocomplete(handler Handler, source string) {
+	if r.Autocomplete != nil {
+		panic(&RouteConflictError{Kind: "autocomplete", Key: r.Name, Source: source, Existing: r.autocompleteSource("")})
+	}
+
+	r.Autocomplete = handler
+	r.recordAutocomplete("", source)
+}
+
+// SetOptionAutocomplete Set the handler of the autocomplete interactions focusing the option, the other options go
+// to the Autocomplete handler. Panics when the option already has one
+func (r *CommandRoute) SetOptionAutocomplete(option string, handler Handler) *CommandRoute {
+	source := callerSite(1)
+	if _, ok := r.OptionAutocomplete[option]; ok {
+		panic(&RouteConflictError{Kind: "option autocomplete", Key: r.Name + " " + option, Source: source, Existing: r.autocompleteSource(option)})
+	}
+
+	if r.OptionAutocomplete == nil {
+		r.OptionAutocomplete = make(map[string]Handler)
+	}
+
+	r.OptionAutocomplete[option] = handler
+	r.recordAutocomplete(option, source)
+	return r
+}
+
+func (r *CommandRoute) recordAutocomplete(option, source string) {
+	if r.autocompleteSources == nil {
+		r.autocompleteSources = make(map[string]string)
+	}
+
+	r.autocompleteSources[option] = source
+}
+
+// autocompleteSource Where the autocomplete of the option was set, "" for Autocomplete. Unknown when the field
+// was assigned directly
+func (r *CommandRoute) autocompleteSource(option string) string {
+	if source, ok := r.autocompleteSources[option]; ok {
+		return source
+	}
+
+	return "unknown"
+}
+
+// autocomplete Handler of the autocomplete interaction, the one of the focused option first
+func (r *CommandRoute) autocomplete(ctx *ConnectionContext) Handler {
+	if focused, ok := ctx.FocusedOption(); ok {
+		if handler, ok := r.OptionAutocomplete[focused.Name()]; ok {
+			return handler
+		}
+	}
+
+	return r.Autocomplete
+}
+
+// SetGroup Set the group the command is listed under
+func (r *CommandRoute) SetGroup(group string) *CommandRoute {
+	r.Group = group
+	return r
+}
+
+// Feature Only run the command where the FeatureGate enables the feature, elsewhere the interaction
+// is left to the interaction handlers and FallbackProxy as if the command was not registered
+func (r *CommandRoute) Feature(feature string) *CommandRoute {
+	r.FeatureName = feature
+	return r
+}
+
+// FeatureDenied Reply sent instead of leaving the interaction unhandled where the feature is disabled
+func (r *CommandRoute) FeatureDenied(data *InteractionCallbackData) *CommandRoute {
+	r.FeatureDeniedReply = data
+	return r
+}
+
+// SetDefinition Attach the command definition, used to validate the route metadata
+func (r *CommandRoute) SetDefinition(command *ApplicationCommand) *CommandRoute {
+	checkOptionOrder(r.Name, command.Options)
+
+	r.Definition = command
+
+	for _, constraint := range r.Constraints {
+		r.checkOptions(constraint.Options)
+	}
+
+	return r
+}
+
+// checkOptionOrder Panic when a required option follows an optional one, the options of the subcommands and groups
+// are checked under their full name like "mod user ban"
+func checkOptionOrder(path string, options []ApplicationCommandOption) {
+	optional := ""
+
+	for _, option := range options {
+		switch {
+		case option.Type == SubCommandApplicationCommandOptionType || option.Type == SubCommandGroupApplicationCommandOptionType:
+			checkOptionOrder(path+" "+option.Name, option.Options)
+		case !option.Required:
+			optional = option.Name
+		case optional != "":
+			panic(fmt.Sprintf("command %s: required option %s must be placed before optional option %s", path, option.Name, optional))
+		}
+	}
+}
+
+// MutuallyExclusive Reject interactions providing more than one of the options
+func (r *CommandRoute) MutuallyExclusive(options ...string) *CommandRoute {
+	return r.addConstraint(MutuallyExclusiveConstraint, options)
+}
+
+// RequireOneOf Reject interactions providing none of the options
+func (r *CommandRoute) RequireOneOf(options ...string) *CommandRoute {
+	return r.addConstraint(RequireOneOfConstraint, options)
+}
+
+func (r *CommandRoute) addConstraint(kind OptionConstraintKind, options []string) *CommandRoute {
+	if len(options) < 2 && kind == MutuallyExclusiveConstraint {
+		panic("command " + r.Name + ": MutuallyExclusive needs at least two options")
+	}
+
+	r.checkOptions(options)
+	r.Constraints = append(r.Constraints, OptionConstraint{Kind: kind, Options: options})
+	return r
+}
+
+// checkOptions Panic when a constrained option does not exist in the definition
+func (r *CommandRoute) checkOptions(names []string) {
+	if r.Definition == nil {
+		return
+	}
+
+	for _, name := range names {
+		if !definesOption(r.Definition.Options, name) {
+			panic(fmt.Sprintf("command %s: constraint references unknown option %q", r.Name, name))
+		}
+	}
+}
+
+func definesOption(options []ApplicationCommandOption, name string) bool {
+	for _, option := range options {
+		if option.Name == name || definesOption(option.Options, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate Check the interaction options against the route constraints
+func (r *CommandRoute) Validate(data *ApplicationCommandInteractionData) error {
+	provided := make(map[string]bool)
+
+	for _, option := range leafOptions(data.Options) {
+		provided[option.Name] = true
+	}
+
+	for _, constraint := range r.Constraints {
+		var present []string
+
+		for _, name := range constraint.Options {
+			if provided[name] {
+				present = append(present, name)
+			}
+		}
+
+		if (constraint.Kind == MutuallyExclusiveConstraint && len(present) > 1) ||
+			(constraint.Kind == RequireOneOfConstraint && len(present) == 0) {
+			return &OptionConstraintError{Constraint: constraint, Provided: present}
+		}
+	}
+
+	return nil
+}
+
+// topCommand Top level command of the route name like "config" for "config set", empty for a blank name
+func topCommand(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+// leafOptions Options of the invoked subcommand, or the top level options
+func leafOptions(options []ApplicationCommandOption) []ApplicationCommandOption {
+	for len(options) == 1 && (options[0].Type == SubCommandApplicationCommandOptionType || options[0].Type == SubCommandGroupApplicationCommandOptionType) {
+		options = options[0].Options
+	}
+
+	return options
+}
+
+// dispatch Run the matching command route, returns false when nothing matched
+func (r *commandRouter) dispatch(ctx ConnectionContext) bool {
+	ctx.routed = true
+
+	switch ctx.Interaction.Type {
+	case MessageComponentInteraction:
+		return r.dispatchComponent(ctx)
+	case ModalSubmitInteraction:
+		return r.dispatchModal(ctx)
+	case AutoCompleteInteraction:
+		data := ctx.Interaction.ApplicationCommandData()
+		if route := r.lookup(&data); route != nil {
+			if handler := route.autocomplete(&ctx); handler != nil && ctx.featureEnabled(route) {
+				handler(ctx)
+				return true
+			}
+		}
+
+		if fallback := r.fallbackHandler(); fallback != nil {
+			fallback(ctx)
+			return true
+		}
+
+		return false
+	case ApplicationCommandInteraction:
+	default:
+		return false
+	}
+
+	data := ctx.Interaction.ApplicationCommandData()
+
+	var route *CommandRoute
+	if data.Type == UserApplicationCommandType || data.Type == MessageApplicationCommandType {
+		route = r.contextMenu(data.Type, data.Name)
+	} else {
+		route = r.lookup(&data)
+	}
+
+	if route == nil {
+		if fallback := r.fallbackHandler(); fallback != nil {
+			fallback(ctx)
+			return true
+		}
+
+		return false
+	}
+
+	if !ctx.featureEnabled(route) {
+		if route.FeatureDeniedReply == nil {
+			return false
+		}
+
+		ctx.ReplyInteraction(route.FeatureDeniedReply)
+		return true
+	}
+
+	if !route.acquire() {
+		ctx.options.Metrics.IncCounter("httpcord_dispatch_rejected_total", map[string]string{"command": route.Name})
+		ctx.handleError(&DispatchBusyError{Command: route.Name})
+		return true
+	}
+	defer route.release()
+
+	// Normalized first so Validate checks the values the handler reads
+	normalization := route.Normalization
+	if normalization == nil {
+		normalization = ctx.options.NormalizeStrings
+	}
+
+	if normalization != nil {
+		if err := ctx.normalizeStrings(normalization); err != nil {
+			ctx.handleError(err)
+			return true
+		}
+
+		data = ctx.Interaction.ApplicationCommandData()
+	}
+
+	if err := route.Validate(&data); err != nil {
+		ctx.handleError(err)
+		return true
+	}
+
+	route.Handler(ctx)
+	return true
+}
@@ -0,0 +1,228 @@
+package httpcord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRouterValidateCommandNames(t *testing.T) {
+	handler := func(ConnectionContext) {}
+
+	tests := []struct {
+		name   string
+		routes []*CommandRoute
+		errors []string
+	}{
+		{"valid", []*CommandRoute{{Name: "ban", Handler: handler}, {Name: "config set", Handler: handler}}, nil},
+		{"empty name", []*CommandRoute{{Name: "", Handler: handler, Source: "main.go:10"}}, []string{"empty name"}},
+		{"blank name", []*CommandRoute{{Name: "   ", Handler: handler, Source: "main.go:11"}}, []string{"empty name"}},
+		{"defined as another command", []*CommandRoute{{Name: "config set", Handler: handler, Definition: &ApplicationCommand{Name: "settings"}}},
+			[]string{`is defined as "settings"`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newCommandRouter()
+			for _, route := range test.routes {
+				router.add(route)
+			}
+
+			errs := router.validate()
+			router.warnings()
+
+			for _, want := range test.errors {
+				found := false
+				for _, err := range errs {
+					found = found || strings.Contains(err.Error(), want)
+				}
+
+				if !found {
+					t.Errorf("validate() = %v, missing %q", errs, want)
+				}
+			}
+
+			if len(test.errors) == 0 && len(errs) > 0 {
+				t.Errorf("validate() = %v, want no error", errs)
+			}
+		})
+	}
+}
+
+func TestRouteConflicts(t *testing.T) {
+	handler := func(ConnectionContext) {}
+
+	tests := []struct {
+		name     string
+		register func(c *Connection)
+		kind     string
+	}{
+		{"command", func(c *Connection) {
+			c.Command("ban", handler)
+			c.Command("ban", handler)
+		}, "command"},
+		{"context menu", func(c *Connection) {
+			c.UserCommand("Inspect", handler)
+			c.UserCommand("Inspect", handler)
+		}, "context menu command"},
+		{"component", func(c *Connection) {
+			c.Component("confirm:*", handler)
+			c.Component("confirm:*", handler)
+		}, "component"},
+		{"modal", func(c *Connection) {
+			c.Modal("report", handler)
+			c.Modal("report", handler)
+		}, "modal"},
+		{"autocomplete", func(c *Connection) {
+			route := c.Command("ban", handler).SetAutocomplete(handler)
+			route.SetAutocomplete(handler)
+		}, "autocomplete"},
+		{"option autocomplete", func(c *Connection) {
+			route := c.Command("ban", handler).SetOptionAutocomplete("user", handler)
+			route.SetOptionAutocomplete("user", handler)
+		}, "option autocomplete"},
+		{"fallback command", func(c *Connection) {
+			c.FallbackCommand(handler)
+			c.FallbackCommand(handler)
+		}, "fallback command"},
+		{"fallback component", func(c *Connection) {
+			c.FallbackComponent(handler)
+			c.FallbackComponent(handler)
+		}, "fallback component"},
+		{"internal component", func(c *Connection) {
+			c.router.addComponent(publishComponentPrefix, handler)
+		}, "component prefix"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{})
+
+			defer func() {
+				conflict, ok := recover().(*RouteConflictError)
+				if !ok {
+					t.Fatal("the conflict was not detected")
+				}
+
+				if conflict.Kind != test.kind || conflict.Source == conflict.Existing {
+					t.Errorf("conflict = %+v, want a %s conflict naming both sites", conflict, test.kind)
+				}
+			}()
+
+			test.register(conn)
+		})
+	}
+}
+
+func TestValidateRouteShadowing(t *testing.T) {
+	handler := func(ConnectionContext) {}
+
+	tests := []struct {
+		name     string
+		register func(c *Connection)
+		error    string
+	}{
+		{"no conflict", func(c *Connection) {
+			c.Component("confirm:*", handler)
+		}, ""},
+		{"component shadowed by an internal one", func(c *Connection) {
+			c.Component(publishComponentPrefix+"*", handler)
+		}, "component " + `"` + publishComponentPrefix + `*"`},
+		{"autocomplete of an unknown option", func(c *Connection) {
+			c.Command("ban", handler).SetDefinition(&ApplicationCommand{Name: "ban", Description: "Ban a member"}).
+				SetOptionAutocomplete("user", handler)
+		}, `unknown option "user"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{})
+			test.register(conn)
+
+			err := conn.ValidateRoutes()
+			switch {
+			case test.error == "" && err != nil:
+				t.Errorf("ValidateRoutes() = %v, want nil", err)
+			case test.error != "" && (err == nil || !strings.Contains(err.Error(), test.error)):
+				t.Errorf("ValidateRoutes() = %v, want %q", err, test.error)
+			}
+		})
+	}
+}
+
+func TestOptionConstraintsRegistration(t *testing.T) {
+	handler := func(ConnectionContext) {}
+
+	definition := func() *ApplicationCommand {
+		return &ApplicationCommand{Name: "prune", Description: "Delete messages", Options: []ApplicationCommandOption{
+			{Type: IntApplicationCommandOptionType, Name: "count", Description: "Messages"},
+			{Type: StringApplicationCommandOptionType, Name: "before_id", Description: "Last message"},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		register func(c *Connection)
+		// panic Part of the registration panic, empty when the registration is valid
+		panic string
+	}{
+		{"known options", func(c *Connection) {
+			c.Command("prune", handler).SetDefinition(definition()).MutuallyExclusive("count", "before_id")
+		}, ""},
+		{"typo after the definition", func(c *Connection) {
+			c.Command("prune", handler).SetDefinition(definition()).RequireOneOf("count", "befor_id")
+		}, `unknown option "befor_id"`},
+		{"typo before the definition", func(c *Connection) {
+			c.Command("prune", handler).MutuallyExclusive("cuont", "before_id").SetDefinition(definition())
+		}, `unknown option "cuont"`},
+		{"no definition to check", func(c *Connection) {
+			c.Command("prune", handler).MutuallyExclusive("cuont", "before_id")
+		}, ""},
+		{"single exclusive option", func(c *Connection) {
+			c.Command("prune", handler).MutuallyExclusive("count")
+		}, "at least two options"},
+		{"required after optional", func(c *Connection) {
+			command := definition()
+			command.Options[1].Required = true
+			c.Command("prune", handler).SetDefinition(command)
+		}, "must be placed before optional option count"},
+		{"required after optional in a subcommand", func(c *Connection) {
+			c.Command("mod", handler).SetDefinition(&ApplicationCommand{Name: "mod", Description: "Moderation", Options: []ApplicationCommandOption{
+				{Type: SubCommandApplicationCommandOptionType, Name: "warn", Description: "Warn a member", Options: []ApplicationCommandOption{
+					{Type: UserApplicationCommandOptionType, Name: "user", Description: "Member", Required: true},
+				}},
+				{Type: SubCommandGroupApplicationCommandOptionType, Name: "user", Description: "Members", Options: []ApplicationCommandOption{
+					{Type: SubCommandApplicationCommandOptionType, Name: "ban", Description: "Ban a member", Options: []ApplicationCommandOption{
+						{Type: IntApplicationCommandOptionType, Name: "days", Description: "Days of messages"},
+						{Type: UserApplicationCommandOptionType, Name: "user", Description: "Member", Required: true},
+					}},
+				}},
+			}})
+		}, "command mod user ban: required option user must be placed before optional option days"},
+		{"ordered subcommands", func(c *Connection) {
+			c.Command("mod", handler).SetDefinition(&ApplicationCommand{Name: "mod", Description: "Moderation", Options: []ApplicationCommandOption{
+				{Type: SubCommandApplicationCommandOptionType, Name: "warn", Description: "Warn a member", Options: []ApplicationCommandOption{
+					{Type: StringApplicationCommandOptionType, Name: "reason", Description: "Reason"},
+				}},
+				{Type: SubCommandApplicationCommandOptionType, Name: "kick", Description: "Kick a member", Options: []ApplicationCommandOption{
+					{Type: UserApplicationCommandOptionType, Name: "user", Description: "Member", Required: true},
+				}},
+			}})
+		}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConnection(t, ConnectionOptions{})
+
+			defer func() {
+				v := recover()
+				if message := fmt.Sprint(v); (v != nil) != (test.panic != "") || !strings.Contains(message, test.panic) {
+					t.Errorf("registration panic %v, want %q", v, test.panic)
+				}
+			}()
+
+			test.register(conn)
+		})
+	}
+}
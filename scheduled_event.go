@@ -0,0 +1,234 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"httpcord/endpoints"
+)
+
+type (
+	ScheduledEventEntityType   int
+	ScheduledEventPrivacyLevel int
+	ScheduledEventStatus       int
+)
+
+// Scheduled Event Entity Types
+
+const (
+	StageInstanceScheduledEventEntityType ScheduledEventEntityType = iota + 1
+	VoiceScheduledEventEntityType
+	ExternalScheduledEventEntityType
+)
+
+// Scheduled Event Privacy Levels
+
+const (
+	GuildOnlyScheduledEventPrivacyLevel ScheduledEventPrivacyLevel = 2
+)
+
+// Scheduled Event Statuses
+
+const (
+	ScheduledScheduledEventStatus ScheduledEventStatus = iota + 1
+	ActiveScheduledEventStatus
+	CompletedScheduledEventStatus
+	CanceledScheduledEventStatus
+)
+
+var (
+	// ErrScheduledEventLocation External events need EntityMetadata.Location
+	ErrScheduledEventLocation = errors.New("httpcord: external scheduled events need a location")
+	// ErrScheduledEventEndTime External events need a ScheduledEndTime
+	ErrScheduledEventEndTime = errors.New("httpcord: external scheduled events need an end time")
+	// ErrScheduledEventChannel Stage and voice events need a ChannelID, external events must not have one
+	ErrScheduledEventChannel = errors.New("httpcord: stage and voice scheduled events need a channel, external events can not have one")
+	// ErrScheduledEventTimes The end time is not after the start time
+	ErrScheduledEventTimes = errors.New("httpcord: scheduled event ends before it starts")
+)
+
+type ScheduledEventEntityMetadata struct {
+	Location string `json:"location,omitempty"`
+}
+
+type ScheduledEvent struct {
+	ID                 Snowflake                     `json:"id"`
+	GuildID            Snowflake                     `json:"guild_id"`
+	ChannelID          Snowflake                     `json:"channel_id,omitempty"`
+	CreatorID          Snowflake                     `json:"creator_id,omitempty"`
+	Name               string                        `json:"name"`
+	Description        string                        `json:"description,omitempty"`
+	ScheduledStartTime Time                          `json:"scheduled_start_time"`
+	ScheduledEndTime   *Time                         `json:"scheduled_end_time,omitempty"`
+	PrivacyLevel       ScheduledEventPrivacyLevel    `json:"privacy_level"`
+	Status             ScheduledEventStatus          `json:"status"`
+	EntityType         ScheduledEventEntityType      `json:"entity_type"`
+	EntityID           Snowflake                     `json:"entity_id,omitempty"`
+	EntityMetadata     *ScheduledEventEntityMetadata `json:"entity_metadata,omitempty"`
+	Creator            *User                         `json:"creator,omitempty"`
+	UserCount          int                           `json:"user_count,omitempty"`
+	Image              string                        `json:"image,omitempty"`
+}
+
+type ScheduledEventCreate struct {
+	ChannelID          Snowflake                     `json:"channel_id,omitempty"`
+	EntityMetadata     *ScheduledEventEntityMetadata `json:"entity_metadata,omitempty"`
+	Name               string                        `json:"name"`
+	PrivacyLevel       ScheduledEventPrivacyLevel    `json:"privacy_level"`
+	ScheduledStartTime Time                          `json:"scheduled_start_time"`
+	ScheduledEndTime   *Time                         `json:"scheduled_end_time,omitempty"`
+	Description        string                        `json:"description,omitempty"`
+	EntityType         ScheduledEventEntityType      `json:"entity_type"`
+	// Image Cover image as a data URI, see SetImage
+	Image string `json:"image,omitempty"`
+}
+
+// ScheduledEventModify Fields left nil are not changed
+type ScheduledEventModify struct {
+	ChannelID          *Snowflake                    `json:"channel_id,omitempty"`
+	EntityMetadata     *ScheduledEventEntityMetadata `json:"entity_metadata,omitempty"`
+	Name               *string                       `json:"name,omitempty"`
+	PrivacyLevel       *ScheduledEventPrivacyLevel   `json:"privacy_level,omitempty"`
+	ScheduledStartTime *Time                         `json:"scheduled_start_time,omitempty"`
+	ScheduledEndTime   *Time                         `json:"scheduled_end_time,omitempty"`
+	Description        *string                       `json:"description,omitempty"`
+	EntityType         *ScheduledEventEntityType     `json:"entity_type,omitempty"`
+	Status             *ScheduledEventStatus         `json:"status,omitempty"`
+	Image              *string                       `json:"image,omitempty"`
+}
+
+// ImageDataURI Encode the file as a data URI, the content type is detected when not set
+func ImageDataURI(file *DiscordFile) string {
+	contentType := file.ContentType
+
+	if contentType == "" {
+		contentType = http.DetectContentType(file.Bytes())
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(file.Bytes())
+}
+
+func NewScheduledEventCreateBuilder() *ScheduledEventCreate {
+	return &ScheduledEventCreate{PrivacyLevel: GuildOnlyScheduledEventPrivacyLevel}
+}
+
+func (e *ScheduledEventCreate) SetName(name string) *ScheduledEventCreate {
+	e.Name = name
+	return e
+}
+
+func (e *ScheduledEventCreate) SetDescription(description string) *ScheduledEventCreate {
+	e.Description = description
+	return e
+}
+
+// SetStage Host the event in a stage channel
+func (e *ScheduledEventCreate) SetStage(channelID Snowflake) *ScheduledEventCreate {
+	e.EntityType, e.ChannelID, e.EntityMetadata = StageInstanceScheduledEventEntityType, channelID, nil
+	return e
+}
+
+// SetVoice Host the event in a voice channel
+func (e *ScheduledEventCreate) SetVoice(channelID Snowflake) *ScheduledEventCreate {
+	e.EntityType, e.ChannelID, e.EntityMetadata = VoiceScheduledEventEntityType, channelID, nil
+	return e
+}
+
+// SetExternal Host the event outside Discord, external events need an end time
+func (e *ScheduledEventCreate) SetExternal(location string) *ScheduledEventCreate {
+	e.EntityType, e.ChannelID = ExternalScheduledEventEntityType, ""
+	e.EntityMetadata = &ScheduledEventEntityMetadata{Location: location}
+	return e
+}
+
+func (e *ScheduledEventCreate) SetTimes(start Time, end *Time) *ScheduledEventCreate {
+	e.ScheduledStartTime, e.ScheduledEndTime = start, end
+	return e
+}
+
+// SetImage Use the file as the cover image
+func (e *ScheduledEventCreate) SetImage(file *DiscordFile) *ScheduledEventCreate {
+	e.Image = ImageDataURI(file)
+	return e
+}
+
+// Validate Check the entity type invariants before the event is sent
+func (e *ScheduledEventCreate) Validate() error {
+	return validateScheduledEvent(e.EntityType, e.ChannelID, e.EntityMetadata, &e.ScheduledStartTime, e.ScheduledEndTime)
+}
+
+// Validate Check the entity type invariants when the entity type is changed
+func (e *ScheduledEventModify) Validate() error {
+	if e.EntityType == nil {
+		if e.ScheduledStartTime != nil && e.ScheduledEndTime != nil && !e.ScheduledEndTime.After(e.ScheduledStartTime.Time) {
+			return ErrScheduledEventTimes
+		}
+
+		return nil
+	}
+
+	var channelID Snowflake
+	if e.ChannelID != nil {
+		channelID = *e.ChannelID
+	}
+
+	return validateScheduledEvent(*e.EntityType, channelID, e.EntityMetadata, e.ScheduledStartTime, e.ScheduledEndTime)
+}
+
+func validateScheduledEvent(entityType ScheduledEventEntityType, channelID Snowflake, metadata *ScheduledEventEntityMetadata, start, end *Time) error {
+	if entityType == ExternalScheduledEventEntityType {
+		if channelID != "" {
+			return ErrScheduledEventChannel
+		}
+
+		if metadata == nil || metadata.Location == "" {
+			return ErrScheduledEventLocation
+		}
+
+		if end == nil {
+			return ErrScheduledEventEndTime
+		}
+	} else if channelID == "" {
+		return ErrScheduledEventChannel
+	}
+
+	if start != nil && end != nil && !end.After(start.Time) {
+		return ErrScheduledEventTimes
+	}
+
+	return nil
+}
+
+// CreateGuildScheduledEvent Create a scheduled event in the guild, the payload is validated first
+func (c *RestClient) CreateGuildScheduledEvent(ctx context.Context, guildID Snowflake, data *ScheduledEventCreate) (*ScheduledEvent, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	var event ScheduledEvent
+	if err := c.Do(ctx, http.MethodPost, endpoints.GuildScheduledEvents(guildID.String()), data, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// ModifyGuildScheduledEvent Update the scheduled event, the payload is validated first
+func (c *RestClient) ModifyGuildScheduledEvent(ctx context.Context, guildID, eventID Snowflake, data *ScheduledEventModify) (*ScheduledEvent, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	var event ScheduledEvent
+	if err := c.Do(ctx, http.MethodPatch, endpoints.GuildScheduledEvent(guildID.String(), eventID.String()), data, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (c *RestClient) DeleteGuildScheduledEvent(ctx context.Context, guildID, eventID Snowflake) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.GuildScheduledEvent(guildID.String(), eventID.String()), nil, nil)
+}
@@ -0,0 +1,177 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduledEventValidate(t *testing.T) {
+	start := Time{Time: time.Date(2021, 3, 4, 18, 0, 0, 0, time.UTC)}
+	end := Time{Time: start.Add(2 * time.Hour)}
+	before := Time{Time: start.Add(-time.Hour)}
+
+	tests := []struct {
+		name  string
+		event *ScheduledEventCreate
+		err   error
+	}{
+		{"stage", NewScheduledEventCreateBuilder().SetName("AMA").SetStage("5").SetTimes(start, nil), nil},
+		{"voice", NewScheduledEventCreateBuilder().SetName("Game night").SetVoice("5").SetTimes(start, &end), nil},
+		{"external", NewScheduledEventCreateBuilder().SetName("Meetup").SetExternal("Paris").SetTimes(start, &end), nil},
+		{"external without location", NewScheduledEventCreateBuilder().SetName("Meetup").SetExternal("").SetTimes(start, &end), ErrScheduledEventLocation},
+		{"external without end time", NewScheduledEventCreateBuilder().SetName("Meetup").SetExternal("Paris").SetTimes(start, nil), ErrScheduledEventEndTime},
+		{"external with a channel", &ScheduledEventCreate{EntityType: ExternalScheduledEventEntityType, ChannelID: "5",
+			EntityMetadata: &ScheduledEventEntityMetadata{Location: "Paris"}, ScheduledStartTime: start, ScheduledEndTime: &end}, ErrScheduledEventChannel},
+		{"voice without a channel", NewScheduledEventCreateBuilder().SetName("Game night").SetVoice("").SetTimes(start, nil), ErrScheduledEventChannel},
+		{"external after voice drops the channel", NewScheduledEventCreateBuilder().SetVoice("5").SetExternal("Paris").SetTimes(start, &end), nil},
+		{"ends before it starts", NewScheduledEventCreateBuilder().SetName("AMA").SetStage("5").SetTimes(start, &before), ErrScheduledEventTimes},
+		{"ends when it starts", NewScheduledEventCreateBuilder().SetName("AMA").SetStage("5").SetTimes(start, &start), ErrScheduledEventTimes},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.event.Validate(); !errors.Is(err, test.err) {
+				t.Errorf("Validate() = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestScheduledEventModifyValidate(t *testing.T) {
+	start := Time{Time: time.Date(2021, 3, 4, 18, 0, 0, 0, time.UTC)}
+	before := Time{Time: start.Add(-time.Hour)}
+	external := ExternalScheduledEventEntityType
+	voice := VoiceScheduledEventEntityType
+	channel := Snowflake("5")
+	name := "Renamed"
+
+	tests := []struct {
+		name   string
+		modify *ScheduledEventModify
+		err    error
+	}{
+		{"name only", &ScheduledEventModify{Name: &name}, nil},
+		{"times swapped", &ScheduledEventModify{ScheduledStartTime: &start, ScheduledEndTime: &before}, ErrScheduledEventTimes},
+		{"to external", &ScheduledEventModify{EntityType: &external, EntityMetadata: &ScheduledEventEntityMetadata{Location: "Paris"},
+			ScheduledEndTime: &Time{Time: start.Add(time.Hour)}}, nil},
+		{"to external without location", &ScheduledEventModify{EntityType: &external, ScheduledEndTime: &start}, ErrScheduledEventLocation},
+		{"to external without end time", &ScheduledEventModify{EntityType: &external, EntityMetadata: &ScheduledEventEntityMetadata{Location: "Paris"}}, ErrScheduledEventEndTime},
+		{"to voice", &ScheduledEventModify{EntityType: &voice, ChannelID: &channel}, nil},
+		{"to voice without a channel", &ScheduledEventModify{EntityType: &voice}, ErrScheduledEventChannel},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.modify.Validate(); !errors.Is(err, test.err) {
+				t.Errorf("Validate() = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestGuildScheduledEvents(t *testing.T) {
+	var method, path, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Write([]byte(`{"id":"9","guild_id":"2","name":"Meetup","scheduled_start_time":"2021-03-04T18:00:00+00:00",` +
+			`"scheduled_end_time":"2021-03-04T20:00:00+00:00","privacy_level":2,"status":1,"entity_type":3,"entity_metadata":{"location":"Paris"}}`))
+	}))
+	defer server.Close()
+
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.MaxRetries = 0
+
+	start := Time{Time: time.Date(2021, 3, 4, 18, 0, 0, 0, time.UTC)}
+	end := Time{Time: start.Add(2 * time.Hour)}
+	name := "Renamed"
+	png := &DiscordFile{Buffer: bytes.NewBuffer([]byte("\x89PNG\r\n\x1a\n"))}
+
+	tests := []struct {
+		name   string
+		call   func() (*ScheduledEvent, error)
+		method string
+		path   string
+		body   string
+	}{
+		{"create", func() (*ScheduledEvent, error) {
+			return client.CreateGuildScheduledEvent(context.Background(), "2", NewScheduledEventCreateBuilder().
+				SetName("Meetup").SetExternal("Paris").SetTimes(start, &end).SetImage(png))
+		}, http.MethodPost, "/guilds/2/scheduled-events",
+			`{"entity_metadata":{"location":"Paris"},"name":"Meetup","privacy_level":2,"scheduled_start_time":"2021-03-04T18:00:00Z",` +
+				`"scheduled_end_time":"2021-03-04T20:00:00Z","entity_type":3,"image":"data:image/png;base64,iVBORw0KGgo="}`},
+		{"modify", func() (*ScheduledEvent, error) {
+			return client.ModifyGuildScheduledEvent(context.Background(), "2", "9", &ScheduledEventModify{Name: &name})
+		}, http.MethodPatch, "/guilds/2/scheduled-events/9", `{"name":"Renamed"}`},
+		{"delete", func() (*ScheduledEvent, error) {
+			return nil, client.DeleteGuildScheduledEvent(context.Background(), "2", "9")
+		}, http.MethodDelete, "/guilds/2/scheduled-events/9", ``},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event, err := test.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if method != test.method || path != test.path {
+				t.Errorf("sent %s %s, want %s %s", method, path, test.method, test.path)
+			}
+
+			if strings.TrimSpace(body) != test.body {
+				t.Errorf("payload\n%s\nwant\n%s", body, test.body)
+			}
+
+			if event != nil && (event.ID != "9" || event.ScheduledEndTime == nil || !event.ScheduledEndTime.Equal(end.Time) || event.EntityMetadata.Location != "Paris") {
+				t.Errorf("event %+v, want the decoded response", event)
+			}
+		})
+	}
+
+	t.Run("invalid events not sent", func(t *testing.T) {
+		method = ""
+
+		_, err := client.CreateGuildScheduledEvent(context.Background(), "2", NewScheduledEventCreateBuilder().SetName("Meetup").SetExternal("Paris"))
+		if !errors.Is(err, ErrScheduledEventEndTime) || method != "" {
+			t.Errorf("CreateGuildScheduledEvent() = %v after sending %s, want ErrScheduledEventEndTime and no request", err, method)
+		}
+	})
+}
+
+func TestImageDataURI(t *testing.T) {
+	tests := []struct {
+		name string
+		file *DiscordFile
+		want string
+	}{
+		{"detected PNG", &DiscordFile{Buffer: bytes.NewBuffer([]byte("\x89PNG\r\n\x1a\n"))}, "data:image/png;base64,iVBORw0KGgo="},
+		{"declared type", &DiscordFile{Buffer: bytes.NewBufferString("GIF89a"), ContentType: "image/webp"}, "data:image/webp;base64,R0lGODlh"},
+		{"detected GIF", &DiscordFile{Buffer: bytes.NewBufferString("GIF89a")}, "data:image/gif;base64,R0lGODlh"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ImageDataURI(test.file); got != test.want {
+				t.Errorf("ImageDataURI() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"httpcord/endpoints"
+)
+
+var (
+	// ErrScheduleExpired The interaction token expires before the follow-up fire time
+	ErrScheduleExpired = errors.New("httpcord: interaction token expired before the scheduled follow-up")
+	// ErrScheduledFiles Scheduled follow-ups are stored as JSON, files can not be scheduled
+	ErrScheduledFiles = errors.New("httpcord: scheduled follow-ups can not have files")
+	// ErrScheduleNotFound No follow-up is pending with the ID, it already fired or was cancelled
+	ErrScheduleNotFound = errors.New("httpcord: scheduled follow-up not found")
+)
+
+// ScheduledID Identifier of a follow-up scheduled with ScheduleFollowUp
+type ScheduledID string
+
+// ScheduledFollowUp Follow-up waiting for its fire time, stored by the ScheduleStore
+type ScheduledFollowUp struct {
+	ID            ScheduledID `json:"id"`
+	ApplicationID Snowflake   `json:"application_id"`
+	Token         string      `json:"token"`
+	// ExpiresAt is when the interaction token stops being valid
+	ExpiresAt time.Time `json:"expires_at"`
+	FireAt    time.Time `json:"fire_at"`
+	// Payload is the JSON encoded WebhookEdit
+	Payload json.RawMessage `json:"payload"`
+	// ThreadID is the WebhookEdit.ThreadID sent as query parameter
+	ThreadID Snowflake `json:"thread_id,omitempty"`
+}
+
+// ScheduleStore Store of the pending follow-ups, entries are deleted once fired, skipped or cancelled.
+// Implementations must be safe for concurrent use
+type ScheduleStore interface {
+	Save(entry ScheduledFollowUp) error
+	Delete(id ScheduledID) error
+	// Load Every entry stored, called when the connection is created to resume them
+	Load() ([]ScheduledFollowUp, error)
+}
+
+// Clock Time source of the Scheduler, replaced in tests to fire the follow-ups without waiting
+type Clock interface {
+	Now() time.Time
+	// AfterFunc Call fn in its own goroutine after d, stop cancels the call and is false when it already happened
+	AfterFunc(d time.Duration, fn func()) (stop func() bool)
+}
+
+type systemClock struct{}
+
+// SystemClock Clock of the time package
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, fn func()) func() bool {
+	return time.AfterFunc(d, fn).Stop
+}
+
+// MemoryScheduleStore In-memory ScheduleStore, pending follow-ups are lost on restart
+type MemoryScheduleStore struct {
+	mu      sync.Mutex
+	entries map[ScheduledID]ScheduledFollowUp
+}
+
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{entries: make(map[ScheduledID]ScheduledFollowUp)}
+}
+
+func (s *MemoryScheduleStore) Save(entry ScheduledFollowUp) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.ID] = entry
+	return nil
+}
+
+func (s *MemoryScheduleStore) Delete(id ScheduledID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *MemoryScheduleStore) Load() ([]ScheduledFollowUp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]ScheduledFollowUp, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// FileScheduleStore ScheduleStore keeping the pending follow-ups in a JSON file so they survive restarts.
+// The file is rewritten on every change, it suits the few entries a token window holds
+type FileScheduleStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileScheduleStore(path string) *FileScheduleStore {
+	return &FileScheduleStore{path: path}
+}
+
+func (s *FileScheduleStore) Save(entry ScheduledFollowUp) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	entries[entry.ID] = entry
+	return s.write(entries)
+}
+
+func (s *FileScheduleStore) Delete(id ScheduledID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := entries[id]; !ok {
+		return nil
+	}
+
+	delete(entries, id)
+	return s.write(entries)
+}
+
+func (s *FileScheduleStore) Load() ([]ScheduledFollowUp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]ScheduledFollowUp, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].FireAt.Before(list[j].FireAt)
+	})
+
+	return list, nil
+}
+
+func (s *FileScheduleStore) read() (map[ScheduledID]ScheduledFollowUp, error) {
+	entries := make(map[ScheduledID]ScheduledFollowUp)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var list []ScheduledFollowUp
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range list {
+		entries[entry.ID] = entry
+	}
+
+	return entries, nil
+}
+
+// write Replace the file through a rename so a crash never leaves it half written
+func (s *FileScheduleStore) write(entries map[ScheduledID]ScheduledFollowUp) error {
+	list := make([]ScheduledFollowUp, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Scheduler Sends the follow-ups scheduled with ScheduleFollowUp at their fire time, shared by the copies of a Connection
+type Scheduler struct {
+	mu      sync.Mutex
+	store   ScheduleStore
+	clock   Clock
+	client  *RestClient
+	life    *lifecycle
+	options *ConnectionOptions
+	timers  map[ScheduledID]func() bool
+	stopped bool
+}
+
+func newScheduler(options *ConnectionOptions, client *RestClient, life *lifecycle) *Scheduler {
+	return &Scheduler{
+		store:   options.ScheduleStore,
+		clock:   options.Clock,
+		client:  client,
+		life:    life,
+		options: options,
+		timers:  make(map[ScheduledID]func() bool),
+	}
+}
+
+// resume Arm the entries of the store, the ones expired while stopped are skipped when they fire
+func (s *Scheduler) resume() error {
+	entries, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		s.arm(entry)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) schedule(entry ScheduledFollowUp) error {
+	if err := s.store.Save(entry); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.arm(entry)
+	return nil
+}
+
+// arm Start the timer of the entry, the lock must be held
+func (s *Scheduler) arm(entry ScheduledFollowUp) {
+	if s.stopped {
+		return
+	}
+
+	delay := entry.FireAt.Sub(s.clock.Now())
+	if delay < 0 {
+		delay = 0
+	}
+
+	s.timers[entry.ID] = s.clock.AfterFunc(delay, func() {
+		s.fire(entry)
+	})
+}
+
+// fire Send the follow-up, entries fired while shutting down stay stored for the next start
+func (s *Scheduler) fire(entry ScheduledFollowUp) {
+	s.mu.Lock()
+	_, pending := s.timers[entry.ID]
+	delete(s.timers, entry.ID)
+	s.mu.Unlock()
+
+	if !pending || !s.life.begin() {
+		return
+	}
+
+	defer s.life.end()
+
+	remaining := entry.ExpiresAt.Sub(s.clock.Now())
+
+	var err error
+	if remaining <= 0 {
+		err = ErrScheduleExpired
+	} else {
+		c, cancel := context.WithTimeout(context.Background(), remaining)
+		err = s.client.Do(c, http.MethodPost, endpoints.WebhookExecute(entry.ApplicationID.String(), entry.Token), entry.Payload, nil,
+			withoutAuth(), WithQuery(threadQuery(entry.ThreadID, url.Values{})))
+		cancel()
+	}
+
+	if deleteErr := s.store.Delete(entry.ID); deleteErr != nil {
+		s.options.Logger.Error("could not delete the scheduled follow-up", "id", entry.ID, "error", deleteErr)
+	}
+
+	if err != nil {
+		s.fail(entry, err)
+	}
+}
+
+func (s *Scheduler) fail(entry ScheduledFollowUp, err error) {
+	if s.options.OnScheduleError != nil {
+		s.options.OnScheduleError(entry, err)
+		return
+	}
+
+	s.options.Logger.Error("scheduled follow-up failed", "id", entry.ID, "error", err)
+}
+
+// Cancel Stop the pending follow-up and remove it from the store, ErrScheduleNotFound when it already fired
+func (s *Scheduler) Cancel(id ScheduledID) error {
+	s.mu.Lock()
+	stop, ok := s.timers[id]
+	delete(s.timers, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	stop()
+	return s.store.Delete(id)
+}
+
+// Pending Number of follow-ups waiting for their fire time
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.timers)
+}
+
+// stop Stop every timer, the entries stay stored and resume on the next start
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+
+	for id, stop := range s.timers {
+		stop()
+		delete(s.timers, id)
+	}
+}
+
+// Scheduler Scheduler of the follow-ups created with ScheduleFollowUp, see Scheduler.Cancel
+func (c *Connection) Scheduler() *Scheduler {
+	return c.life.scheduler
+}
+
+// ScheduleFollowUp Send the follow-up after delay, within the interaction token lifetime (See InteractionTokenLifetime).
+// The entry is kept in ConnectionOptions.ScheduleStore so a persistent store resumes it after a restart,
+// failures and tokens expired while stopped are reported to ConnectionOptions.OnScheduleError.
+// Returns ErrScheduleExpired when the token expires before the fire time and ErrScheduledFiles for payloads with files
+func (ctx *ConnectionContext) ScheduleFollowUp(delay time.Duration, data *WebhookEdit) (ScheduledID, error) {
+	if data == nil {
+		return "", ErrNilPayload
+	}
+
+	if len(data.Files) > 0 {
+		return "", ErrScheduledFiles
+	}
+
+	if err := data.validateThread(); err != nil {
+		return "", err
+	}
+
+	scheduler := ctx.life.scheduler
+	expiry := ctx.Interaction.ID.CreatedAt().Add(InteractionTokenLifetime)
+	fireAt := scheduler.clock.Now().Add(delay)
+
+	if !fireAt.Before(expiry) {
+		return "", ErrScheduleExpired
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	entry := ScheduledFollowUp{
+		ID:            ScheduledID(newRequestID()),
+		ApplicationID: ctx.Interaction.ApplicationID,
+		Token:         ctx.Interaction.Token,
+		ExpiresAt:     expiry,
+		FireAt:        fireAt,
+		Payload:       payload,
+		ThreadID:      data.ThreadID,
+	}
+
+	if err := scheduler.schedule(entry); err != nil {
+		return "", err
+	}
+
+	return entry.ID, nil
+}
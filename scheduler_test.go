@@ -0,0 +1,143 @@
+package httpcord
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScheduleFollowUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		delay  time.Duration
+		data   *WebhookEdit
+		cancel bool
+		err    error
+		sent   int
+		// refused The Discord error of the follow-up is reported to OnScheduleError
+		refused bool
+	}{
+		{"fires at the due time", "token", 10 * time.Minute, &WebhookEdit{Content: "reminder"}, false, nil, 1, false},
+		{"cancelled", "token", 10 * time.Minute, &WebhookEdit{Content: "reminder"}, true, nil, 0, false},
+		{"refused by discord", "refused", 10 * time.Minute, &WebhookEdit{Content: "reminder"}, false, nil, 0, true},
+		{"after the token lifetime", "token", InteractionTokenLifetime, &WebhookEdit{Content: "reminder"}, false, ErrScheduleExpired, 0, false},
+		{"files", "token", time.Minute, &WebhookEdit{Files: []*DiscordFile{{Buffer: bytes.NewBufferString("PNG"), Filename: "chart.png"}}},
+			false, ErrScheduledFiles, 0, false},
+		{"nil payload", "token", time.Minute, nil, false, ErrNilPayload, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, sent := followUpServer(t)
+			clock := newFakeClock()
+			store := NewMemoryScheduleStore()
+
+			var reported error
+			options := ConnectionOptions{ScheduleStore: store, Clock: clock, OnScheduleError: func(_ ScheduledFollowUp, err error) { reported = err }}
+
+			interaction := Interaction{ID: nowSnowflake(), ApplicationID: "1", Type: ApplicationCommandInteraction, Token: test.token}
+			ctx, finish := NewContext(interaction, ContextConfig{Options: options, Client: client})
+			defer finish()
+
+			id, err := ctx.ScheduleFollowUp(test.delay, test.data)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ScheduleFollowUp() = %v, want %v", err, test.err)
+			}
+
+			if test.cancel {
+				if err := ctx.life.scheduler.Cancel(id); err != nil {
+					t.Fatalf("Cancel() = %v", err)
+				}
+
+				if err := ctx.life.scheduler.Cancel(id); !errors.Is(err, ErrScheduleNotFound) {
+					t.Errorf("second Cancel() = %v, want ErrScheduleNotFound", err)
+				}
+			}
+
+			clock.Advance(test.delay - time.Second)
+			if got := len(sent()); got != 0 {
+				t.Fatalf("%d follow-ups sent before the due time", got)
+			}
+
+			clock.Advance(time.Second)
+			if got := len(sent()); got != test.sent {
+				t.Errorf("%d follow-ups sent, want %d", got, test.sent)
+			}
+
+			var apiErr *DiscordAPIError
+			if refused := errors.As(reported, &apiErr); refused != test.refused || (!refused && reported != nil) {
+				t.Errorf("reported %v, refused = %v, want %v", reported, refused, test.refused)
+			}
+
+			if entries, _ := store.Load(); len(entries) != 0 || ctx.life.scheduler.Pending() != 0 {
+				t.Errorf("%d entries stored and %d pending after the due time", len(entries), ctx.life.scheduler.Pending())
+			}
+		})
+	}
+}
+
+func TestScheduleResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		advance  time.Duration
+		sent     int
+		reported error
+	}{
+		{"resumed after a restart", 5 * time.Minute, 1, nil},
+		{"token expired while stopped", InteractionTokenLifetime + time.Minute, 0, ErrScheduleExpired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, sent := followUpServer(t)
+			clock := newFakeClock()
+			path := filepath.Join(t.TempDir(), "scheduled.json")
+
+			// Scheduled by the previous process, stopped before the fire time
+			interaction := Interaction{ID: nowSnowflake(), ApplicationID: "1", Type: ApplicationCommandInteraction, Token: "token"}
+			ctx, finish := NewContext(interaction, ContextConfig{
+				Options: ConnectionOptions{ScheduleStore: NewFileScheduleStore(path), Clock: clock},
+				Client:  client,
+			})
+
+			if _, err := ctx.ScheduleFollowUp(5*time.Minute, &WebhookEdit{Content: "reminder"}); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx.life.scheduler.stop()
+			finish()
+
+			clock.Advance(test.advance - 5*time.Minute)
+
+			var reported error
+			store := NewFileScheduleStore(path)
+			conn := newTestConnection(t, ConnectionOptions{
+				ScheduleStore:   store,
+				Clock:           clock,
+				OnScheduleError: func(_ ScheduledFollowUp, err error) { reported = err },
+			})
+			conn.Client.BaseURL = client.BaseURL
+
+			if pending := conn.Scheduler().Pending(); pending != 1 {
+				t.Fatalf("%d follow-ups resumed, want 1", pending)
+			}
+
+			clock.Advance(5 * time.Minute)
+
+			if got := len(sent()); got != test.sent {
+				t.Errorf("%d follow-ups sent, want %d", got, test.sent)
+			}
+
+			if !errors.Is(reported, test.reported) {
+				t.Errorf("reported %v, want %v", reported, test.reported)
+			}
+
+			if entries, _ := store.Load(); len(entries) != 0 {
+				t.Errorf("%d entries left in the file", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package httpcord
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// APIGatewayProxyRequest Event of an API Gateway REST API, HTTP API or Lambda function URL invocation, in the payload
+// format 1.0 or 2.0. It decodes the JSON of aws-lambda-go events.APIGatewayProxyRequest and
+// events.APIGatewayV2HTTPRequest without depending on the AWS SDK
+type APIGatewayProxyRequest struct {
+	// HTTPMethod and Path are set by the payload format 1.0
+	HTTPMethod        string              `json:"httpMethod"`
+	Path              string              `json:"path"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	// RawPath, RawQueryString and RequestContext.HTTP are set by the payload format 2.0
+	RawPath         string `json:"rawPath"`
+	RawQueryString  string `json:"rawQueryString"`
+	Body            string `json:"body"`
+	IsBase64Encoded bool   `json:"isBase64Encoded"`
+	RequestContext  struct {
+		HTTP struct {
+			Method   string `json:"method"`
+			SourceIP string `json:"sourceIp"`
+		} `json:"http"`
+		Identity struct {
+			SourceIP string `json:"sourceIp"`
+		} `json:"identity"`
+	} `json:"requestContext"`
+}
+
+// APIGatewayProxyResponse Response of the Lambda function, accepted by both payload formats
+type APIGatewayProxyResponse struct {
+	StatusCode      int               `json:"statusCode"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded,omitempty"`
+}
+
+// LambdaHandler Lambda handler running the invocations through the connection like ServeHTTP, signature checks
+// included, for lambda.Start(conn.LambdaHandler()). The response is returned once the handlers return, the work
+// started after it, like RunDeferred, only runs while the execution environment is not frozen so handlers should
+// answer without it. Cloud Functions and Cloud Run serve the connection itself, it is an http.Handler
+func (c *Connection) LambdaHandler() func(ctx context.Context, event APIGatewayProxyRequest) (APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, event APIGatewayProxyRequest) (APIGatewayProxyResponse, error) {
+		r, err := event.request(ctx)
+		if err != nil {
+			return APIGatewayProxyResponse{StatusCode: http.StatusBadRequest}, nil
+		}
+
+		w := &lambdaResponseWriter{header: http.Header{}}
+		c.ServeHTTP(w, r)
+
+		return w.response(), nil
+	}
+}
+
+// request HTTP request of the event
+func (e *APIGatewayProxyRequest) request(ctx context.Context) (*http.Request, error) {
+	body := []byte(e.Body)
+	if e.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		body = decoded
+	}
+
+	method, path, remote := e.HTTPMethod, e.Path, e.RequestContext.Identity.SourceIP
+	if method == "" {
+		method, path, remote = e.RequestContext.HTTP.Method, e.RawPath, e.RequestContext.HTTP.SourceIP
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	target := &url.URL{Path: path, RawQuery: e.RawQueryString}
+
+	r, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range e.MultiValueHeaders {
+		for _, value := range values {
+			r.Header.Add(name, value)
+		}
+	}
+
+	for name, value := range e.Headers {
+		if r.Header.Get(name) == "" {
+			r.Header.Set(name, value)
+		}
+	}
+
+	r.RemoteAddr = remote
+	return r, nil
+}
+
+// lambdaResponseWriter http.ResponseWriter buffering the response of an invocation
+type lambdaResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *lambdaResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *lambdaResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *lambdaResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// response Lambda response of the buffered one, binary bodies like the multipart ones with files are base64 encoded
+func (w *lambdaResponseWriter) response() APIGatewayProxyResponse {
+	res := APIGatewayProxyResponse{StatusCode: w.status, Headers: make(map[string]string, len(w.header))}
+	if res.StatusCode == 0 {
+		res.StatusCode = http.StatusOK
+	}
+
+	for name, values := range w.header {
+		res.Headers[name] = strings.Join(values, ",")
+	}
+
+	if utf8.Valid(w.body.Bytes()) {
+		res.Body = w.body.String()
+	} else {
+		res.Body = base64.StdEncoding.EncodeToString(w.body.Bytes())
+		res.IsBase64Encoded = true
+	}
+
+	return res
+}
@@ -0,0 +1,436 @@
+package httpcord
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"httpcord/permissions"
+)
+
+const settingsComponentPrefix = "httpcord:settings:"
+
+// settingsRowsPerPage Field rows of a paginated panel, the last row holds the page buttons
+const settingsRowsPerPage = 4
+
+type SettingType int
+
+// Setting Types
+
+const (
+	// BoolSetting Toggled by a button, stored as "true" or "false"
+	BoolSetting SettingType = iota + 1
+	// ChoiceSetting One of the Choices in a select menu, stored as the choice value
+	ChoiceSetting
+	// ChannelSetting Channel select menu, stored as the channel ID
+	ChannelSetting
+	// RoleSetting Role select menu, stored as the role ID
+	RoleSetting
+)
+
+var (
+	SettingsTitleMessages = Dictionary{
+		EnglishUSLocale:    "Settings",
+		EnglishGBLocale:    "Settings",
+		PortugueseBRLocale: "Configurações",
+		SpanishESLocale:    "Configuración",
+		FrenchLocale:       "Paramètres",
+		GermanLocale:       "Einstellungen",
+	}
+	SettingsOnMessages = Dictionary{
+		EnglishUSLocale:    "On",
+		EnglishGBLocale:    "On",
+		PortugueseBRLocale: "Ativado",
+		SpanishESLocale:    "Activado",
+		FrenchLocale:       "Activé",
+		GermanLocale:       "An",
+	}
+	SettingsOffMessages = Dictionary{
+		EnglishUSLocale:    "Off",
+		EnglishGBLocale:    "Off",
+		PortugueseBRLocale: "Desativado",
+		SpanishESLocale:    "Desactivado",
+		FrenchLocale:       "Désactivé",
+		GermanLocale:       "Aus",
+	}
+	SettingsUnsetMessages = Dictionary{
+		EnglishUSLocale:    "Not set",
+		EnglishGBLocale:    "Not set",
+		PortugueseBRLocale: "Não definido",
+		SpanishESLocale:    "Sin definir",
+		FrenchLocale:       "Non défini",
+		GermanLocale:       "Nicht festgelegt",
+	}
+	SettingsForbiddenMessages = Dictionary{
+		EnglishUSLocale:    "You are not allowed to change the settings of this server.",
+		EnglishGBLocale:    "You are not allowed to change the settings of this server.",
+		PortugueseBRLocale: "Você não tem permissão para alterar as configurações deste servidor.",
+		SpanishESLocale:    "No tienes permiso para cambiar la configuración de este servidor.",
+		FrenchLocale:       "Vous n'êtes pas autorisé à modifier les paramètres de ce serveur.",
+		GermanLocale:       "Du darfst die Einstellungen dieses Servers nicht ändern.",
+	}
+)
+
+// SettingChoice Option of a ChoiceSetting
+type SettingChoice struct {
+	Label  string
+	Labels Dictionary
+	Value  string
+}
+
+// SettingField Setting of the panel, stored under Key
+type SettingField struct {
+	Key    string
+	Type   SettingType
+	Label  string
+	Labels Dictionary
+	// Default is the value shown while the store has none
+	Default string
+	// Choices of a ChoiceSetting, at most 25
+	Choices []SettingChoice
+	// ChannelTypes restricts the channels of a ChannelSetting
+	ChannelTypes []ChannelType
+}
+
+// SettingsStore Storage of the guild settings keyed by SettingField.Key, implementations must be safe for concurrent use
+type SettingsStore interface {
+	Load(ctx context.Context, guildID Snowflake) (map[string]string, error)
+	Save(ctx context.Context, guildID Snowflake, key, value string) error
+}
+
+// SettingsSchema Fields of a settings panel and their storage
+type SettingsSchema struct {
+	// Name of the command showing the panel (Defaults to "settings")
+	Name        string
+	Description string
+	Title       string
+	Titles      Dictionary
+	Fields      []SettingField
+	Store       SettingsStore
+	// Permission the member needs to see and change the settings (Defaults to ManageGuild)
+	Permission *permissions.PermissionBit
+	// Color of the panel embed, no color when 0
+	Color Color
+}
+
+// settingsPanel Fields of the schema laid out in action rows
+type settingsPanel struct {
+	schema     SettingsSchema
+	prefix     string
+	permission permissions.PermissionBit
+	// rows are the field indexes of each action row, bool fields share rows of up to 5 buttons
+	rows [][]int
+}
+
+// EnableSettingsPanel Register a guild only command showing the schema fields as buttons and select menus, every change
+// is rendered at once and saved in the Store after the response. Panels with more fields than a message holds are paginated.
+// Panics when the schema is invalid, the returned route definition must still be registered in Discord
+func (c *Connection) EnableSettingsPanel(schema SettingsSchema) *CommandRoute {
+	if schema.Name == "" {
+		schema.Name = "settings"
+	}
+
+	if schema.Description == "" {
+		schema.Description = "Show the settings of the server"
+	}
+
+	p := &settingsPanel{schema: schema, prefix: settingsComponentPrefix + schema.Name + ":", permission: permissions.ManageGuild}
+
+	if schema.Permission != nil {
+		p.permission = *schema.Permission
+	}
+
+	if schema.Store == nil {
+		panic("settings " + schema.Name + ": Store is required")
+	}
+
+	for i, field := range schema.Fields {
+		switch {
+		case field.Key == "":
+			panic(fmt.Sprintf("settings %s: field %d has no key", schema.Name, i))
+		case field.Type == ChoiceSetting && (len(field.Choices) == 0 || len(field.Choices) > MaxAutocompleteChoices):
+			panic(fmt.Sprintf("settings %s: field %s needs 1 to %d choices", schema.Name, field.Key, MaxAutocompleteChoices))
+		case field.Type < BoolSetting || field.Type > RoleSetting:
+			panic(fmt.Sprintf("settings %s: field %s has an unknown type", schema.Name, field.Key))
+		}
+
+		last := len(p.rows) - 1
+		if field.Type == BoolSetting && last >= 0 && schema.Fields[p.rows[last][0]].Type == BoolSetting && len(p.rows[last]) < 5 {
+			p.rows[last] = append(p.rows[last], i)
+			continue
+		}
+
+		p.rows = append(p.rows, []int{i})
+	}
+
+	guildOnly := false
+	definition := NewCommandBuilder().
+		SetName(schema.Name).
+		SetDescription(schema.Description).
+		SetDefaultPermissions(&p.permission)
+	definition.AllowUseInDMs = &guildOnly
+
+	c.router.addComponent(p.prefix, p.component)
+
+	return c.router.add(&CommandRoute{Name: schema.Name, Handler: p.command, Source: callerSite(1)}).SetDefinition(definition)
+}
+
+// pages Rows of every page, a single page holds 5 rows and paginated ones settingsRowsPerPage
+func (p *settingsPanel) pages() [][][]int {
+	if len(p.rows) <= 5 {
+		return [][][]int{p.rows}
+	}
+
+	var pages [][][]int
+	for start := 0; start < len(p.rows); start += settingsRowsPerPage {
+		end := start + settingsRowsPerPage
+		if end > len(p.rows) {
+			end = len(p.rows)
+		}
+
+		pages = append(pages, p.rows[start:end])
+	}
+
+	return pages
+}
+
+// allowed Whether the member can use the panel, replies with SettingsForbiddenMessages otherwise
+func (p *settingsPanel) allowed(ctx ConnectionContext) bool {
+	member := ctx.Interaction.Member
+	if ctx.Interaction.GuildID != "" && member != nil && member.Permissions.Has(p.permission, true) {
+		return true
+	}
+
+	locale := Locale(ctx.Interaction.Locale)
+	ctx.ReplyInteraction(&InteractionCallbackData{
+		Content: SettingsForbiddenMessages.Get(locale, SettingsForbiddenMessages[EnglishUSLocale]),
+		Flags:   EphemeralMessageFlag,
+	})
+
+	return false
+}
+
+func (p *settingsPanel) load(ctx ConnectionContext) (map[string]string, error) {
+	stored, err := p.schema.Store.Load(ctx.Context(), ctx.Interaction.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(p.schema.Fields))
+	for _, field := range p.schema.Fields {
+		values[field.Key] = field.Default
+
+		if value, ok := stored[field.Key]; ok {
+			values[field.Key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (p *settingsPanel) command(ctx ConnectionContext) {
+	if !p.allowed(ctx) {
+		return
+	}
+
+	values, err := p.load(ctx)
+	if err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	ctx.ReplyInteraction(p.render(ctx, 0, values))
+}
+
+// component Page buttons "p:<page>", toggles "t:<page>:<field>" and menus "s:<page>:<field>"
+func (p *settingsPanel) component(ctx ConnectionContext) {
+	if !p.allowed(ctx) {
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(ctx.Interaction.ComponentData().CustomID, p.prefix), ":")
+	if len(parts) < 2 {
+		return
+	}
+
+	page, _ := strconv.Atoi(parts[1])
+
+	values, err := p.load(ctx)
+	if err != nil {
+		ctx.handleError(err)
+		return
+	}
+
+	if len(parts) != 3 {
+		ctx.UpdateMessage(p.render(ctx, page, values))
+		return
+	}
+
+	index, err := strconv.Atoi(parts[2])
+	if err != nil || index < 0 || index >= len(p.schema.Fields) {
+		return
+	}
+
+	field := p.schema.Fields[index]
+	previous := values[field.Key]
+
+	switch field.Type {
+	case BoolSetting:
+		values[field.Key] = strconv.FormatBool(previous != "true")
+	default:
+		values[field.Key] = ""
+
+		if selected := ctx.SelectedValues(); len(selected) > 0 {
+			values[field.Key] = selected[0]
+		}
+	}
+
+	ctx.UpdateMessage(p.render(ctx, page, values))
+
+	guildID, value := ctx.Interaction.GuildID, values[field.Key]
+
+	ctx.afterResponse(func() {
+		if err := p.schema.Store.Save(context.Background(), guildID, field.Key, value); err != nil {
+			values[field.Key] = previous
+			ctx.EditReply(p.render(ctx, page, values).WebhookEdit())
+			ctx.handleError(err)
+		}
+	})
+}
+
+func (p *settingsPanel) label(field SettingField, locale Locale) string {
+	return field.Labels.Get(locale, field.Label)
+}
+
+// display Value of the field as shown in the panel embed
+func (p *settingsPanel) display(field SettingField, value string, locale Locale) string {
+	if value == "" && field.Type != BoolSetting {
+		return SettingsUnsetMessages.Get(locale, SettingsUnsetMessages[EnglishUSLocale])
+	}
+
+	switch field.Type {
+	case BoolSetting:
+		if value == "true" {
+			return SettingsOnMessages.Get(locale, SettingsOnMessages[EnglishUSLocale])
+		}
+
+		return SettingsOffMessages.Get(locale, SettingsOffMessages[EnglishUSLocale])
+	case ChoiceSetting:
+		for _, choice := range field.Choices {
+			if choice.Value == value {
+				return choice.Labels.Get(locale, choice.Label)
+			}
+		}
+	case ChannelSetting:
+		return "<#" + value + ">"
+	case RoleSetting:
+		return "<@&" + value + ">"
+	}
+
+	return value
+}
+
+// render Panel message of the page with the values
+func (p *settingsPanel) render(ctx ConnectionContext, page int, values map[string]string) *InteractionCallbackData {
+	locale := Locale(ctx.Interaction.Locale)
+	pages := p.pages()
+
+	if page < 0 || page >= len(pages) {
+		page = 0
+	}
+
+	title := SettingsTitleMessages.Get(locale, SettingsTitleMessages[EnglishUSLocale])
+	if p.schema.Title != "" {
+		title = p.schema.Titles.Get(locale, p.schema.Title)
+	}
+
+	embed := NewEmbedBuilder().SetTitle(title)
+	if p.schema.Color != 0 {
+		embed.SetColor(p.schema.Color)
+	}
+
+	data := &InteractionCallbackData{Embeds: []*Embed{embed}, Flags: EphemeralMessageFlag}
+
+	var lines []string
+
+	for _, row := range pages[page] {
+		components := make([]AnyComponent, 0, len(row))
+
+		for _, index := range row {
+			field := p.schema.Fields[index]
+			value := values[field.Key]
+			lines = append(lines, fmt.Sprintf("**%s**: %s", p.label(field, locale), p.display(field, value, locale)))
+			components = append(components, p.fieldComponent(field, p.customID(page, index, field), value, locale))
+		}
+
+		data.Components = append(data.Components, NewActionRowComponentBuilder().SetComponents(components...))
+	}
+
+	embed.SetDescription(strings.Join(lines, "\n"))
+
+	if len(pages) > 1 {
+		embed.SetFooter(&EmbedFooter{Text: fmt.Sprintf(HelpPageMessages.Get(locale, HelpPageMessages[EnglishUSLocale]), page+1, len(pages))})
+
+		data.Components = append(data.Components, NewActionRowComponentBuilder().SetComponents(
+			NewButtonComponentBuilder().SetStyle(SecondaryButtonStyle).SetLabel("◀").
+				SetCustomID(p.prefix+"p:"+strconv.Itoa(page-1)).IsDisabled(page == 0),
+			NewButtonComponentBuilder().SetStyle(SecondaryButtonStyle).SetLabel("▶").
+				SetCustomID(p.prefix+"p:"+strconv.Itoa(page+1)).IsDisabled(page == len(pages)-1),
+		))
+	}
+
+	return data
+}
+
+func (p *settingsPanel) customID(page, index int, field SettingField) string {
+	kind := "s"
+	if field.Type == BoolSetting {
+		kind = "t"
+	}
+
+	return p.prefix + kind + ":" + strconv.Itoa(page) + ":" + strconv.Itoa(index)
+}
+
+// fieldComponent Toggle button of bool fields, select menu of the others showing the current value
+func (p *settingsPanel) fieldComponent(field SettingField, customID, value string, locale Locale) AnyComponent {
+	label := p.label(field, locale)
+
+	if field.Type == BoolSetting {
+		style := SecondaryButtonStyle
+		if value == "true" {
+			style = SuccessButtonStyle
+		}
+
+		return NewButtonComponentBuilder().SetStyle(style).SetLabel(label).SetCustomID(customID)
+	}
+
+	menu := NewSelectMenuComponentBuilder().SetCustomID(customID).SetPlaceholder(label)
+
+	switch field.Type {
+	case ChoiceSetting:
+		for _, choice := range field.Choices {
+			menu.AddOption(&ComponentOption{Label: choice.Labels.Get(locale, choice.Label), Value: choice.Value, Default: choice.Value == value})
+		}
+	case ChannelSetting:
+		menu.SetType(ChannelSelectMenuComponentType).SetChannelTypes(field.ChannelTypes...)
+	case RoleSetting:
+		menu.SetType(RoleSelectMenuComponentType)
+	}
+
+	if field.Type != ChoiceSetting {
+		minValues := 0
+		menu.SetMinValues(&minValues)
+
+		if value != "" {
+			kind := "channel"
+			if field.Type == RoleSetting {
+				kind = "role"
+			}
+
+			menu.SetDefaultValues(&SelectDefaultValue{ID: Snowflake(value), Type: kind})
+		}
+	}
+
+	return menu
+}
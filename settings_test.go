@@ -0,0 +1,263 @@
+package httpcord_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+	"httpcord/permissions"
+)
+
+// settingsSave Call of SettingsStore.Save
+type settingsSave struct {
+	guild      httpcord.Snowflake
+	key, value string
+}
+
+// memorySettings SettingsStore of one guild reporting every save, saves fail while err is set
+type memorySettings struct {
+	mu     sync.Mutex
+	values map[string]string
+	err    error
+	saves  chan settingsSave
+}
+
+func (s *memorySettings) Load(ctx context.Context, guildID httpcord.Snowflake) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]string, len(s.values))
+	for key, value := range s.values {
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+func (s *memorySettings) Save(ctx context.Context, guildID httpcord.Snowflake, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saves <- settingsSave{guildID, key, value}
+	if s.err != nil {
+		return s.err
+	}
+
+	s.values[key] = value
+	return nil
+}
+
+func (s *memorySettings) saved(t *testing.T) settingsSave {
+	t.Helper()
+
+	select {
+	case save := <-s.saves:
+		return save
+	case <-time.After(time.Second):
+		t.Fatal("nothing saved")
+		return settingsSave{}
+	}
+}
+
+// settingsMessage Panel as sent to Discord
+type settingsMessage struct {
+	Type httpcord.InteractionCallbackType `json:"type"`
+	Data struct {
+		Flags  httpcord.MessageFlag `json:"flags"`
+		Embeds []struct {
+			Description string `json:"description"`
+		} `json:"embeds"`
+		Components []struct {
+			Components []struct {
+				Type        httpcord.ComponentType `json:"type"`
+				CustomID    string                 `json:"custom_id"`
+				Style       httpcord.ButtonStyle   `json:"style"`
+				Label       string                 `json:"label"`
+				Placeholder string                 `json:"placeholder"`
+				Options     []struct {
+					Value   string `json:"value"`
+					Default bool   `json:"default"`
+				} `json:"options"`
+			} `json:"components"`
+		} `json:"components"`
+		Content string `json:"content"`
+	} `json:"data"`
+}
+
+// customID custom_id of the button or menu labelled name
+func (m *settingsMessage) customID(t *testing.T, name string) string {
+	t.Helper()
+
+	for _, row := range m.Data.Components {
+		for _, component := range row.Components {
+			if component.Label == name || component.Placeholder == name {
+				return component.CustomID
+			}
+		}
+	}
+
+	t.Fatalf("no component %s in the panel", name)
+	return ""
+}
+
+// description Lines of the panel embed
+func (m *settingsMessage) description() string {
+	if len(m.Data.Embeds) == 0 {
+		return ""
+	}
+
+	return m.Data.Embeds[0].Description
+}
+
+func TestSettingsPanel(t *testing.T) {
+	store := &memorySettings{values: map[string]string{"audit": "true"}, saves: make(chan settingsSave, 8)}
+
+	conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+	fake := httpcordtest.NewFakeDiscord(t)
+	fake.Use(conn)
+
+	conn.EnableSettingsPanel(httpcord.SettingsSchema{
+		Fields: []httpcord.SettingField{
+			{Key: "welcome", Type: httpcord.BoolSetting, Label: "Welcome"},
+			{Key: "audit", Type: httpcord.BoolSetting, Label: "Audit"},
+			{Key: "language", Type: httpcord.ChoiceSetting, Label: "Language", Default: "en", Choices: []httpcord.SettingChoice{
+				{Label: "English", Value: "en"},
+				{Label: "French", Labels: httpcord.Dictionary{httpcord.FrenchLocale: "Français"}, Value: "fr"},
+			}},
+			{Key: "logs", Type: httpcord.ChannelSetting, Label: "Logs"},
+		},
+		Store: store,
+	})
+
+	admin := &httpcord.Member{User: &httpcord.User{ID: "4", Username: "admin"}, Permissions: permissions.ManageGuild}
+
+	send := func(interaction *httpcord.Interaction) *settingsMessage {
+		t.Helper()
+
+		res := httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, httpcordtest.InGuild(interaction, "2", admin)))
+
+		var message settingsMessage
+		if err := json.Unmarshal(res.Body, &message); err != nil {
+			t.Fatalf("%d %s: %v", res.Status, res.Body, err)
+		}
+
+		return &message
+	}
+
+	// Render
+	panel := send(httpcordtest.NewCommandInteraction("settings"))
+	if panel.Type != httpcord.ChannelMessageWithSourceResponse || !panel.Data.Flags.Has(httpcord.EphemeralMessageFlag) {
+		t.Fatalf("panel sent as %d with the flags %d, want an ephemeral message", panel.Type, panel.Data.Flags)
+	}
+
+	if want := "**Welcome**: Off\n**Audit**: On\n**Language**: English\n**Logs**: Not set"; panel.description() != want {
+		t.Errorf("panel %q, want %q", panel.description(), want)
+	}
+
+	if rows := panel.Data.Components; len(rows) != 3 || len(rows[0].Components) != 2 || rows[0].Components[1].Style != httpcord.SuccessButtonStyle ||
+		rows[2].Components[0].Type != httpcord.ChannelSelectMenuComponentType {
+		t.Fatalf("panel rows %+v, want the 2 toggles with audit on, the language menu and the logs channel menu", rows)
+	}
+
+	// Select a choice
+	updated := send(httpcordtest.NewComponentInteraction(panel.customID(t, "Language"), "fr"))
+	if updated.Type != httpcord.UpdateMessageResponse || !strings.Contains(updated.description(), "**Language**: French") {
+		t.Errorf("select answered %d with %q, want the panel updated to French", updated.Type, updated.description())
+	}
+
+	if options := updated.Data.Components[1].Components[0].Options; len(options) != 2 || options[0].Default || !options[1].Default {
+		t.Errorf("language options %+v, want French selected", options)
+	}
+
+	if save := store.saved(t); save != (settingsSave{"2", "language", "fr"}) {
+		t.Errorf("saved %+v, want the French language of the guild 2", save)
+	}
+
+	// Toggle
+	updated = send(httpcordtest.NewComponentInteraction(panel.customID(t, "Welcome")))
+	if !strings.Contains(updated.description(), "**Welcome**: On") || updated.Data.Components[0].Components[0].Style != httpcord.SuccessButtonStyle {
+		t.Errorf("toggle answered %q, want the welcome toggle on", updated.description())
+	}
+
+	if save := store.saved(t); save != (settingsSave{"2", "welcome", "true"}) {
+		t.Errorf("saved %+v, want welcome on", save)
+	}
+
+	// Saved values are rendered by the next panel
+	interaction := httpcordtest.NewCommandInteraction("settings")
+	interaction.Locale = string(httpcord.FrenchLocale)
+
+	if panel := send(interaction); !strings.Contains(panel.description(), "**Welcome**: Activé") || !strings.Contains(panel.description(), "**Language**: Français") {
+		t.Errorf("next panel %q, want the saved values", panel.description())
+	}
+
+	// A failed save renders the previous value again
+	store.mu.Lock()
+	store.err = errors.New("database unavailable")
+	store.mu.Unlock()
+
+	fake.ExpectEditOriginal(httpcordtest.TestApplicationID, httpcordtest.TestToken).RespondWith(httpcord.Message{ID: "9"})
+
+	updated = send(httpcordtest.NewComponentInteraction(panel.customID(t, "Logs"), "10"))
+	if !strings.Contains(updated.description(), "**Logs**: <#10>") {
+		t.Errorf("channel select answered %q, want the logs channel shown at once", updated.description())
+	}
+
+	if save := store.saved(t); save != (settingsSave{"2", "logs", "10"}) {
+		t.Errorf("saved %+v, want the logs channel", save)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(fake.RequestsTo(http.MethodPatch, "/webhooks/*/*/messages/@original")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	edits := fake.RequestsTo(http.MethodPatch, "/webhooks/*/*/messages/@original")
+	if len(edits) != 1 {
+		t.Fatalf("%d edits after the failed save, want the panel restored once", len(edits))
+	}
+
+	var restored settingsMessage
+	if err := json.Unmarshal(edits[0].Payload, &restored.Data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(restored.description(), "**Logs**: Not set") {
+		t.Errorf("restored panel %q, want the logs unset again", restored.description())
+	}
+}
+
+func TestSettingsPanelPermission(t *testing.T) {
+	store := &memorySettings{values: map[string]string{}, saves: make(chan settingsSave, 1)}
+
+	conn, signer := newSignedConnection(t, httpcord.ConnectionOptions{Logger: httpcord.NopLogger})
+	conn.EnableSettingsPanel(httpcord.SettingsSchema{
+		Fields: []httpcord.SettingField{{Key: "welcome", Type: httpcord.BoolSetting, Label: "Welcome"}},
+		Store:  store,
+	})
+
+	member := &httpcord.Member{User: &httpcord.User{ID: "5", Username: "member"}, Permissions: permissions.SendMessages}
+
+	for _, interaction := range []*httpcord.Interaction{
+		httpcordtest.NewCommandInteraction("settings"),
+		httpcordtest.NewComponentInteraction("httpcord:settings:settings:t:0:0"),
+	} {
+		res := httpcordtest.Serve(t, conn, signer.NewInteractionRequest(t, httpcordtest.InGuild(interaction, "2", member)))
+		if res.Response == nil || res.Response.Data == nil || res.Response.Data.Content != httpcord.SettingsForbiddenMessages[httpcord.EnglishUSLocale] {
+			t.Errorf("%d %s, want the forbidden reply", res.Status, res.Body)
+		}
+	}
+
+	select {
+	case save := <-store.saves:
+		t.Errorf("saved %+v for a member without ManageGuild", save)
+	default:
+	}
+}
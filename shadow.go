@@ -0,0 +1,80 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+)
+
+// ShadowDispatcher Second dispatch path of ConnectionOptions.ShadowDispatcher, like the handlers of another library version.
+// It receives its own copy of the interaction and returns the initial response it would send, nil when it sends none
+type ShadowDispatcher func(interaction Interaction) (*InteractionResponse, error)
+
+// ShadowMismatch Initial responses of the primary and shadow dispatch that differ, Err is set when the shadow failed
+type ShadowMismatch struct {
+	Interaction Interaction
+	// Primary and Shadow are the JSON encoded responses, null when nothing was sent
+	Primary     json.RawMessage
+	Shadow      json.RawMessage
+	Differences []JSONDifference
+	Err         error
+}
+
+// shadowDispatch Run the shadow with a fresh copy of the interaction and compare its response with the primary one
+func (ctx *ConnectionContext) shadowDispatch(body []byte) {
+	options := ctx.options
+
+	ctx.state.mu.Lock()
+	primary := ctx.state.primaryResponse
+	ctx.state.mu.Unlock()
+
+	mismatch := &ShadowMismatch{Interaction: ctx.Interaction}
+
+	var err error
+	if mismatch.Primary, err = json.Marshal(primary); err != nil {
+		options.Logger.Error("could not encode the primary response", "request", ctx.requestID, "error", err)
+		return
+	}
+
+	shadow, err := runShadow(options.ShadowDispatcher, options.Codec, body)
+	if err != nil {
+		mismatch.Err = err
+		options.OnShadowMismatch(*ctx, mismatch)
+		return
+	}
+
+	if mismatch.Shadow, err = json.Marshal(shadow); err != nil {
+		mismatch.Err = err
+		options.OnShadowMismatch(*ctx, mismatch)
+		return
+	}
+
+	if mismatch.Differences, err = DiffJSON(mismatch.Primary, mismatch.Shadow, options.ShadowIgnore...); err != nil {
+		mismatch.Err = err
+	}
+
+	if len(mismatch.Differences) > 0 || mismatch.Err != nil {
+		options.OnShadowMismatch(*ctx, mismatch)
+	}
+}
+
+// runShadow Dispatch a copy decoded from the body so the shadow never shares state with the primary handlers
+func runShadow(shadow ShadowDispatcher, codec Codec, body []byte) (response *InteractionResponse, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+
+	var raw APIInteraction
+	if err := codec.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("httpcord: could not copy the interaction: %w", err)
+	}
+
+	interaction, err := resolveInteraction(codec, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return shadow(interaction)
+}
@@ -0,0 +1,101 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestShadowDispatch(t *testing.T) {
+	reply := func(content string) ShadowDispatcher {
+		return func(interaction Interaction) (*InteractionResponse, error) {
+			return &InteractionResponse{Type: ChannelMessageWithSourceResponse, Data: &InteractionCallbackData{Content: content}}, nil
+		}
+	}
+
+	tests := []struct {
+		name   string
+		shadow ShadowDispatcher
+		ignore []string
+		// paths of the differences reported, nil when no mismatch is reported
+		paths []string
+		err   bool
+	}{
+		{"same response", reply("banned"), nil, nil, false},
+		{"divergent response", reply("kicked"), nil, []string{"data.content"}, false},
+		{"divergent ignored path", reply("kicked"), []string{"data.content"}, nil, false},
+		{"no response", func(Interaction) (*InteractionResponse, error) { return nil, nil }, nil, []string{""}, false},
+		{"shadow error", func(Interaction) (*InteractionResponse, error) { return nil, errors.New("unknown command") }, nil, []string{}, true},
+		{"shadow panic", func(Interaction) (*InteractionResponse, error) { panic("handler") }, nil, []string{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var mismatches []*ShadowMismatch
+
+			conn, sign := signedConnection(t, ConnectionOptions{
+				ShadowDispatcher: test.shadow,
+				ShadowIgnore:     test.ignore,
+				OnShadowMismatch: func(_ ConnectionContext, mismatch *ShadowMismatch) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					mismatches = append(mismatches, mismatch)
+				},
+			})
+
+			conn.Command("ban", func(ctx ConnectionContext) {
+				ctx.ReplyInteraction(&InteractionCallbackData{Content: "banned"})
+			})
+
+			w := httptest.NewRecorder()
+			conn.ServeHTTP(w, sign(commandBody()))
+
+			// Waits for the shadow dispatch running in the background
+			if err := conn.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			var sent InteractionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &sent); err != nil || w.Code != http.StatusOK || sent.Data.Content != "banned" {
+				t.Fatalf("sent %d %s, want the primary response", w.Code, w.Body)
+			}
+
+			if test.paths == nil {
+				if len(mismatches) != 0 {
+					t.Errorf("mismatches %+v, want none", mismatches[0])
+				}
+
+				return
+			}
+
+			if len(mismatches) != 1 {
+				t.Fatalf("%d mismatches reported, want 1", len(mismatches))
+			}
+
+			mismatch := mismatches[0]
+			if (mismatch.Err != nil) != test.err {
+				t.Errorf("mismatch error %v, want an error = %v", mismatch.Err, test.err)
+			}
+
+			if len(mismatch.Differences) != len(test.paths) {
+				t.Fatalf("differences %+v, want the paths %q", mismatch.Differences, test.paths)
+			}
+
+			for i, difference := range mismatch.Differences {
+				if difference.Path != test.paths[i] {
+					t.Errorf("difference at %q, want %q", difference.Path, test.paths[i])
+				}
+			}
+
+			if mismatch.Interaction.Token != "token" {
+				t.Errorf("mismatch of the interaction %+v", mismatch.Interaction)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package httpcord
+
+import (
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ShardHopHeader Header of the interactions forwarded to their replica, they are dispatched where they arrive
+// instead of being forwarded again
+const ShardHopHeader = "X-Httpcord-Shard-Hop"
+
+// ShardingOptions Split the interactions between replicas behind a load balancer, every interaction is dispatched by
+// the replica owning it and the others forward it there with its signature headers. Pings are answered where they
+// arrive. When the owner can not be reached the interaction is dispatched locally, logged as a warning
+type ShardingOptions struct {
+	// Replicas is the number of replicas
+	Replicas int
+	// Self is the replica of this connection, between 0 and Replicas-1
+	Self int
+	// OwnerFunc Replica owning the interaction, between 0 and replicas-1 (Defaults to HashOwner)
+	OwnerFunc func(interaction *Interaction, replicas int) int
+	// PeerResolver Interaction endpoint of the replica
+	PeerResolver func(replica int) (*url.URL, error)
+	// Timeout Time the owner has to answer (Defaults to DefaultFallbackProxyTimeout)
+	Timeout time.Duration
+}
+
+// HashOwner Default OwnerFunc, the FNV hash of the message ID for the interactions of a message like components,
+// so collectors stay on one replica, and of the interaction ID otherwise
+func HashOwner(interaction *Interaction, replicas int) int {
+	key := interaction.ID
+	if interaction.Message != nil && interaction.Message.ID != "" {
+		key = interaction.Message.ID
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(replicas))
+}
+
+// validate Error of the options NewConnection fails with
+func (s *ShardingOptions) validate() error {
+	if s.Replicas < 1 {
+		return errors.New("httpcord: sharding needs at least 1 replica")
+	}
+
+	if s.Self < 0 || s.Self >= s.Replicas {
+		return errors.New("httpcord: sharding Self " + strconv.Itoa(s.Self) + " is not a replica of " + strconv.Itoa(s.Replicas))
+	}
+
+	if s.PeerResolver == nil {
+		return errors.New("httpcord: sharding needs a PeerResolver")
+	}
+
+	return nil
+}
+
+func (s *ShardingOptions) setDefaults() {
+	if s.OwnerFunc == nil {
+		s.OwnerFunc = HashOwner
+	}
+
+	if s.Timeout == 0 {
+		s.Timeout = DefaultFallbackProxyTimeout
+	}
+}
+
+// owner Replica of the interaction, Self for the interactions already forwarded
+func (s *ShardingOptions) owner(r *http.Request, interaction *Interaction) int {
+	if r.Header.Get(ShardHopHeader) != "" {
+		return s.Self
+	}
+
+	owner := s.OwnerFunc(interaction, s.Replicas)
+	if owner < 0 || owner >= s.Replicas {
+		return s.Self
+	}
+
+	return owner
+}
+
+// forward Forward the request to the owner and copy its response, on errors nothing is written
+func (s *ShardingOptions) forward(w http.ResponseWriter, r *http.Request, body []byte, owner int) error {
+	target, err := s.PeerResolver(owner)
+	if err != nil {
+		return err
+	}
+
+	hop := http.Header{}
+	hop.Set(ShardHopHeader, strconv.Itoa(s.Self))
+
+	res, upstream, err := proxyRequest(r, body, target, s.Timeout, hop)
+	if err != nil {
+		return err
+	}
+
+	writeProxied(w, res, upstream)
+	return nil
+}
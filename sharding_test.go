@@ -0,0 +1,200 @@
+package httpcord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// shardedReplicas Connections sharing the key of the application, each one behind its own server with its own routes
+type shardedReplicas struct {
+	conns   []*Connection
+	servers []*httptest.Server
+	private ed25519.PrivateKey
+
+	mu sync.Mutex
+	// dispatched Replicas each interaction ID was dispatched on
+	dispatched map[Snowflake][]int
+}
+
+func newShardedReplicas(t *testing.T, replicas int, owner func(interaction *Interaction, replicas int) int) *shardedReplicas {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &shardedReplicas{private: private, dispatched: make(map[Snowflake][]int)}
+
+	handlers := make([]http.Handler, replicas)
+	for i := 0; i < replicas; i++ {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { handlers[i].ServeHTTP(w, r) }))
+		t.Cleanup(server.Close)
+
+		s.servers = append(s.servers, server)
+	}
+
+	for i := 0; i < replicas; i++ {
+		i := i
+
+		conn, err := NewConnection(ConnectionOptions{
+			PublicKey:  hex.EncodeToString(public),
+			Logger:     NopLogger,
+			MaxWorkers: i + 1,
+			Sharding: &ShardingOptions{
+				Replicas:  replicas,
+				Self:      i,
+				OwnerFunc: owner,
+				PeerResolver: func(replica int) (*url.URL, error) {
+					return url.Parse(s.servers[replica].URL)
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		handler := func(ctx ConnectionContext) {
+			s.mu.Lock()
+			s.dispatched[ctx.Interaction.ID] = append(s.dispatched[ctx.Interaction.ID], i)
+			s.mu.Unlock()
+
+			ctx.ReplyInteraction(&InteractionCallbackData{Content: "replica " + strconv.Itoa(i)})
+		}
+
+		conn.Command("ban", handler)
+		conn.Component("pick", handler)
+
+		handlers[i] = conn
+		s.conns = append(s.conns, conn)
+	}
+
+	return s
+}
+
+// send Post the signed body to the replica and return the content of the reply
+func (s *shardedReplicas) send(t *testing.T, replica int, body []byte, header http.Header) string {
+	t.Helper()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, s.servers[replica].URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(s.private, append([]byte(timestamp), body...))))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var response InteractionResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil || response.Data == nil {
+		t.Fatalf("status %d, want a reply: %v", res.StatusCode, err)
+	}
+
+	return response.Data.Content
+}
+
+// withID Body with the ID of the interaction replaced
+func withID(body []byte, id Snowflake) []byte {
+	var interaction struct {
+		ID Snowflake `json:"id"`
+	}
+
+	json.Unmarshal(body, &interaction)
+	return bytes.Replace(body, []byte(`{"id":"`+string(interaction.ID)+`"`), []byte(`{"id":"`+string(id)+`"`), 1)
+}
+
+func TestShardingReplicas(t *testing.T) {
+	// modulo Owner of the numeric interaction ID, every other ID belongs to each replica
+	modulo := func(interaction *Interaction, replicas int) int {
+		return int(interaction.ID.Uint64() % uint64(replicas))
+	}
+
+	s := newShardedReplicas(t, 2, modulo)
+
+	const interactions = 20
+	base := nowSnowflake().Uint64()
+
+	for i := 0; i < interactions; i++ {
+		id := SnowflakeFromUint64(base + uint64(i))
+		body := withID(commandBody(), id)
+
+		owner := int(id.Uint64() % 2)
+		if content := s.send(t, i%2, body, nil); content != "replica "+strconv.Itoa(owner) {
+			t.Errorf("interaction %s sent to replica %d answered %q, want replica %d", id, i%2, content, owner)
+		}
+	}
+
+	if len(s.dispatched) != interactions {
+		t.Fatalf("%d interactions dispatched, want %d", len(s.dispatched), interactions)
+	}
+
+	for id, replicas := range s.dispatched {
+		if len(replicas) != 1 || replicas[0] != int(id.Uint64()%2) {
+			t.Errorf("interaction %s dispatched on the replicas %v, want only %d", id, replicas, id.Uint64()%2)
+		}
+	}
+
+	// Each connection keeps its own pool
+	for i, conn := range s.conns {
+		if workers := conn.Stats().Workers; workers != i+1 {
+			t.Errorf("replica %d has %d workers, want its own %d", i, workers, i+1)
+		}
+	}
+}
+
+func TestShardingComponentsStayOnTheirMessage(t *testing.T) {
+	s := newShardedReplicas(t, 2, nil)
+
+	var answers []string
+	for i := 0; i < 6; i++ {
+		answers = append(answers, s.send(t, i%2, componentBody(), nil))
+	}
+
+	for _, answer := range answers {
+		if answer != answers[0] {
+			t.Fatalf("components of one message answered by %v, want a single replica", answers)
+		}
+	}
+}
+
+func TestShardingHopIsDispatchedLocally(t *testing.T) {
+	// Replica 1 owns every interaction, the hop header keeps the forwarded one on replica 0
+	other := func(interaction *Interaction, replicas int) int {
+		return 1
+	}
+
+	s := newShardedReplicas(t, 2, other)
+
+	header := http.Header{}
+	header.Set(ShardHopHeader, "1")
+
+	if content := s.send(t, 0, commandBody(), header); content != "replica 0" {
+		t.Errorf("forwarded interaction answered by %q, want replica 0", content)
+	}
+
+	if len(s.dispatched) != 1 {
+		t.Errorf("%d interactions dispatched, want 1", len(s.dispatched))
+	}
+}
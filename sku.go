@@ -0,0 +1,159 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"httpcord/endpoints"
+)
+
+type (
+	SKUType int
+	SKUFlag int
+)
+
+// SKU Types
+
+const (
+	DurableSKUType           SKUType = 2
+	ConsumableSKUType        SKUType = 3
+	SubscriptionSKUType      SKUType = 5
+	SubscriptionGroupSKUType SKUType = 6
+)
+
+// SKU Flags
+
+const (
+	AvailableSKUFlag         SKUFlag = 1 << 2
+	GuildSubscriptionSKUFlag SKUFlag = 1 << 7
+	UserSubscriptionSKUFlag  SKUFlag = 1 << 8
+)
+
+// SKU Premium offering of an application
+type SKU struct {
+	ID            Snowflake `json:"id"`
+	Type          SKUType   `json:"type"`
+	ApplicationID Snowflake `json:"application_id"`
+	Name          string    `json:"name"`
+	Slug          string    `json:"slug"`
+	Flags         SKUFlag   `json:"flags"`
+}
+
+// ListSKUs SKUs of the application, to map the SKU names to their IDs at startup
+func (c *RestClient) ListSKUs(ctx context.Context, applicationID Snowflake) ([]*SKU, error) {
+	var skus []*SKU
+	if err := c.Do(ctx, http.MethodGet, endpoints.SKUs(applicationID.String()), nil, &skus); err != nil {
+		return nil, err
+	}
+
+	return skus, nil
+}
+
+// ConsumeEntitlement Mark the entitlement of a consumable SKU as consumed
+func (c *RestClient) ConsumeEntitlement(ctx context.Context, applicationID, entitlementID Snowflake) error {
+	return c.Do(ctx, http.MethodPost, endpoints.ConsumeEntitlement(applicationID.String(), entitlementID.String()), nil, nil)
+}
+
+// EntitlementFilter Entitlements listed by ListEntitlements, zero fields do not filter
+type EntitlementFilter struct {
+	UserID  Snowflake
+	GuildID Snowflake
+	SKUIDs  []Snowflake
+	// Before and After page the entitlements by ID
+	Before Snowflake
+	After  Snowflake
+	// Limit Entitlements returned, 1 to 100 (Defaults to 100)
+	Limit int
+	// ExcludeEnded Leave out the entitlements that ended
+	ExcludeEnded bool
+}
+
+func (f *EntitlementFilter) query() url.Values {
+	query := url.Values{}
+
+	if f.UserID != "" {
+		query.Set("user_id", f.UserID.String())
+	}
+
+	if f.GuildID != "" {
+		query.Set("guild_id", f.GuildID.String())
+	}
+
+	if len(f.SKUIDs) > 0 {
+		ids := make([]string, len(f.SKUIDs))
+		for i, id := range f.SKUIDs {
+			ids[i] = id.String()
+		}
+
+		query.Set("sku_ids", strings.Join(ids, ","))
+	}
+
+	if f.Before != "" {
+		query.Set("before", f.Before.String())
+	}
+
+	if f.After != "" {
+		query.Set("after", f.After.String())
+	}
+
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+
+	if f.ExcludeEnded {
+		query.Set("exclude_ended", "true")
+	}
+
+	return query
+}
+
+// ListEntitlements Entitlements of the application matching the filter, all of them when it is nil
+func (c *RestClient) ListEntitlements(ctx context.Context, applicationID Snowflake, filter *EntitlementFilter) ([]*Entitlement, error) {
+	if filter == nil {
+		filter = &EntitlementFilter{}
+	}
+
+	var entitlements []*Entitlement
+	if err := c.Do(ctx, http.MethodGet, endpoints.Entitlements(applicationID.String()), nil, &entitlements, WithQuery(filter.query())); err != nil {
+		return nil, err
+	}
+
+	return entitlements, nil
+}
+
+// EntitlementOwnerType Owner of a test entitlement
+type EntitlementOwnerType int
+
+// Entitlement Owner Types
+
+const (
+	GuildEntitlementOwnerType EntitlementOwnerType = iota + 1
+	UserEntitlementOwnerType
+)
+
+type testEntitlementCreate struct {
+	SKUID     Snowflake            `json:"sku_id"`
+	OwnerID   Snowflake            `json:"owner_id"`
+	OwnerType EntitlementOwnerType `json:"owner_type"`
+}
+
+// CreateTestEntitlement Grant the SKU to the guild or user without a payment, to test premium features. The test
+// entitlements never end, DeleteTestEntitlement removes them
+func (c *RestClient) CreateTestEntitlement(ctx context.Context, applicationID, skuID, ownerID Snowflake, ownerType EntitlementOwnerType) (*Entitlement, error) {
+	var entitlement Entitlement
+	data := &testEntitlementCreate{SKUID: skuID, OwnerID: ownerID, OwnerType: ownerType}
+
+	if err := c.Do(ctx, http.MethodPost, endpoints.Entitlements(applicationID.String()), data, &entitlement); err != nil {
+		return nil, err
+	}
+
+	return &entitlement, nil
+}
+
+// DeleteTestEntitlement Remove an entitlement created by CreateTestEntitlement
+func (c *RestClient) DeleteTestEntitlement(ctx context.Context, applicationID, entitlementID Snowflake) error {
+	return c.Do(ctx, http.MethodDelete, endpoints.Entitlement(applicationID.String(), entitlementID.String()), nil, nil)
+}
@@ -0,0 +1,81 @@
+package httpcord_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestListSKUs(t *testing.T) {
+	fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+	fake.Expect(http.MethodGet, "/applications/1/skus").RespondStatus(http.StatusOK, []byte(`[`+
+		`{"id":"10","type":5,"application_id":"1","name":"Premium","slug":"premium","flags":384,"dependent_sku_id":null},`+
+		`{"id":"11","type":6,"application_id":"1","name":"Premium","slug":"premium","flags":128},`+
+		`{"id":"12","type":3,"application_id":"1","name":"Coins","slug":"coins","flags":4}]`))
+
+	skus, err := fake.Client().ListSKUs(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*httpcord.SKU{
+		{ID: "10", Type: httpcord.SubscriptionSKUType, ApplicationID: "1", Name: "Premium", Slug: "premium",
+			Flags: httpcord.GuildSubscriptionSKUFlag | httpcord.UserSubscriptionSKUFlag},
+		{ID: "11", Type: httpcord.SubscriptionGroupSKUType, ApplicationID: "1", Name: "Premium", Slug: "premium", Flags: httpcord.GuildSubscriptionSKUFlag},
+		{ID: "12", Type: httpcord.ConsumableSKUType, ApplicationID: "1", Name: "Coins", Slug: "coins", Flags: httpcord.AvailableSKUFlag},
+	}
+
+	if !reflect.DeepEqual(skus, want) {
+		for i, sku := range skus {
+			t.Errorf("SKU %d = %+v", i, sku)
+		}
+
+		t.Fatalf("ListSKUs() = %d SKUs, want %d", len(skus), len(want))
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+		fake.Expect(http.MethodGet, "/applications/1/skus").RespondError(http.StatusForbidden, httpcord.MissingAccessErrorCode, "Missing Access")
+
+		if skus, err := fake.Client().ListSKUs(context.Background(), "1"); skus != nil || !httpcord.HasErrorCode(err, httpcord.MissingAccessErrorCode) {
+			t.Errorf("ListSKUs() = %v, %v, want the Missing Access error", skus, err)
+		}
+	})
+}
+
+func TestConsumeEntitlement(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(route *httpcordtest.FakeRoute)
+		// code Code of the error returned, 0 when the entitlement is consumed
+		code int
+	}{
+		{"consumed", func(route *httpcordtest.FakeRoute) {}, 0},
+		{"unknown entitlement", func(route *httpcordtest.FakeRoute) {
+			route.RespondError(http.StatusNotFound, 10070, "Unknown Entitlement")
+		}, 10070},
+		{"already consumed", func(route *httpcordtest.FakeRoute) {
+			route.RespondError(http.StatusBadRequest, 40074, "This entitlement has already been consumed")
+		}, 40074},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t, httpcordtest.Strict())
+			test.script(fake.Expect(http.MethodPost, "/applications/1/entitlements/9/consume"))
+
+			err := fake.Client().ConsumeEntitlement(context.Background(), "1", "9")
+			if test.code == 0 && err != nil || test.code != 0 && !httpcord.HasErrorCode(err, test.code) {
+				t.Errorf("ConsumeEntitlement() = %v, want the code %d", err, test.code)
+			}
+
+			if request := fake.Requests()[0]; len(request.Body) != 0 {
+				t.Errorf("consume sent the body %s, want none", request.Body)
+			}
+		})
+	}
+}
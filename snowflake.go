@@ -1,18 +1,87 @@
 package httpcord
 
 import (
-	"math"
+	"bytes"
+	"encoding/json"
 	"strconv"
 	"time"
 )
 
+// Snowflake Discord ID, the empty string is the zero value so optional IDs tagged omitempty are left out of the payloads
 type Snowflake string
 
 const DiscordEpoch = 1420070400000
 
+// ParseSnowflake Snowflake of a decimal ID, ErrInvalidSnowflake when it is not an unsigned 64 bits integer. The ID is
+// normalized like SnowflakeFromUint64, "0" is the zero Snowflake
+func ParseSnowflake(id string) (Snowflake, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSnowflake
+	}
+
+	return SnowflakeFromUint64(n), nil
+}
+
 func (s Snowflake) CreatedAt() Time {
-	timestamp := (int64(s.Uint64()) >> 22) + DiscordEpoch
-	return Time{time.Unix(0, timestamp*int64(time.Millisecond))}
+	return Time{s.Timestamp()}
+}
+
+// Timestamp Creation time encoded in the ID
+func (s Snowflake) Timestamp() time.Time {
+	timestamp := int64(s.Uint64()>>22) + DiscordEpoch
+	return time.Unix(0, timestamp*int64(time.Millisecond))
+}
+
+// Compare -1, 0 or 1 when s is lower than, equal to or greater than other as numbers, which orders the IDs by
+// creation time. The zero Snowflake is the lowest
+func (s Snowflake) Compare(other Snowflake) int {
+	a, b := s.Uint64(), other.Uint64()
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+// Before Whether s was created before other
+func (s Snowflake) Before(other Snowflake) bool {
+	return s.Compare(other) < 0
+}
+
+// After Whether s was created after other
+func (s Snowflake) After(other Snowflake) bool {
+	return s.Compare(other) > 0
+}
+
+// UnmarshalJSON Decode the IDs sent as strings or numbers, null is the zero Snowflake
+func (s *Snowflake) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = ""
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		id, err := ParseSnowflake(string(data))
+		if err != nil {
+			return err
+		}
+
+		*s = id
+		return nil
+	}
+
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+
+	*s = Snowflake(id)
+	return nil
 }
 
 func (s Snowflake) Uint64() uint64 {
@@ -25,6 +94,20 @@ func (s Snowflake) String() string {
 	return string(s)
 }
 
+// IsZero Whether the ID is unset, "0" is never a Discord ID and is treated as unset
+func (s Snowflake) IsZero() bool {
+	return s == "" || s == "0"
+}
+
+// SnowflakeFromUint64 Snowflake of a numeric ID, 0 is the zero Snowflake and not "0"
+func SnowflakeFromUint64(id uint64) Snowflake {
+	if id == 0 {
+		return ""
+	}
+
+	return Snowflake(strconv.FormatUint(id, 10))
+}
+
 func (s Snowflake) Valid(id Snowflake) bool {
 	return validSnowflake(id)
 }
@@ -35,12 +118,11 @@ func Epoch(id Snowflake) float64 {
 		panic("Invalid snowflake for Epoch")
 	}
 
-	r, _ := strconv.ParseFloat(string(id), 64)
-	return math.Floor(r / 4194304)
+	return float64(id.Uint64() >> 22)
 }
 
 func validSnowflake(snowflake Snowflake) bool {
-	if _, err := strconv.ParseUint(string(snowflake), 10, 0); err != nil {
+	if _, err := strconv.ParseUint(string(snowflake), 10, 64); err != nil {
 		return false
 	}
 
@@ -0,0 +1,248 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Snowflake
+		err  error
+		// fails The value is refused with an error other than ErrInvalidSnowflake
+		fails bool
+	}{
+		{"quoted", `"175928847299117063"`, "175928847299117063", nil, false},
+		{"bare number", `175928847299117063`, "175928847299117063", nil, false},
+		{"above 2^53", `9007199254740993`, "9007199254740993", nil, false},
+		{"quoted above 2^53", `"9007199254740993"`, "9007199254740993", nil, false},
+		{"max uint64", `18446744073709551615`, "18446744073709551615", nil, false},
+		{"null", `null`, "", nil, false},
+		{"empty", `""`, "", nil, false},
+		{"bare zero", `0`, "", nil, false},
+		{"overflow", `18446744073709551616`, "", ErrInvalidSnowflake, false},
+		{"negative", `-1`, "", ErrInvalidSnowflake, false},
+		{"fraction", `1.5`, "", ErrInvalidSnowflake, false},
+		{"exponent", `1e18`, "", ErrInvalidSnowflake, false},
+		{"bool", `true`, "", ErrInvalidSnowflake, false},
+		{"object", `{}`, "", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id := Snowflake("1")
+
+			err := json.Unmarshal([]byte(test.json), &id)
+			if test.fails {
+				if err == nil {
+					t.Fatalf("Unmarshal(%s) = %q, want an error", test.json, id)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Unmarshal(%s) = %v, want %v", test.json, err, test.err)
+			}
+
+			if err == nil && id != test.want {
+				t.Errorf("Unmarshal(%s) = %q, want %q", test.json, id, test.want)
+			}
+		})
+	}
+}
+
+func TestSnowflakeMarshal(t *testing.T) {
+	type payload struct {
+		ID      Snowflake `json:"id"`
+		GuildID Snowflake `json:"guild_id,omitempty"`
+	}
+
+	tests := []struct {
+		name  string
+		value payload
+		want  string
+	}{
+		{"quoted", payload{ID: "175928847299117063"}, `{"id":"175928847299117063"}`},
+		{"above 2^53 keeps every digit", payload{ID: "9007199254740993", GuildID: "18446744073709551615"},
+			`{"id":"9007199254740993","guild_id":"18446744073709551615"}`},
+		{"zero guild ID left out with omitempty", payload{}, `{"id":""}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != test.want {
+				t.Errorf("Marshal() = %s, want %s", b, test.want)
+			}
+
+			var decoded payload
+			if err := json.Unmarshal(b, &decoded); err != nil || decoded != test.value {
+				t.Errorf("round trip = %+v, %v, want %+v", decoded, err, test.value)
+			}
+		})
+	}
+}
+
+func TestSnowflakeFromUint64(t *testing.T) {
+	tests := []struct {
+		id   uint64
+		want Snowflake
+	}{
+		{0, ""},
+		{1 << 53, "9007199254740992"},
+		{1<<53 + 1, "9007199254740993"},
+		{1<<64 - 1, "18446744073709551615"},
+	}
+
+	for _, test := range tests {
+		id := SnowflakeFromUint64(test.id)
+		if id != test.want || (test.id != 0 && id.Uint64() != test.id) {
+			t.Errorf("SnowflakeFromUint64(%d) = %q, want %q", test.id, id, test.want)
+		}
+	}
+}
+
+func TestParseSnowflake(t *testing.T) {
+	tests := []struct {
+		id   string
+		want Snowflake
+		err  error
+	}{
+		{"175928847299117063", "175928847299117063", nil},
+		{"0175928847299117063", "175928847299117063", nil},
+		{"18446744073709551615", "18446744073709551615", nil},
+		{"0", "", nil},
+		{"18446744073709551616", "", ErrInvalidSnowflake},
+		{"99999999999999999999", "", ErrInvalidSnowflake},
+		{"-1", "", ErrInvalidSnowflake},
+		{"+1", "", ErrInvalidSnowflake},
+		{" 1", "", ErrInvalidSnowflake},
+		{"1e3", "", ErrInvalidSnowflake},
+		{"0x10", "", ErrInvalidSnowflake},
+		{"", "", ErrInvalidSnowflake},
+	}
+
+	for _, test := range tests {
+		id, err := ParseSnowflake(test.id)
+		if id != test.want || !errors.Is(err, test.err) {
+			t.Errorf("ParseSnowflake(%q) = %q, %v, want %q, %v", test.id, id, err, test.want, test.err)
+		}
+	}
+}
+
+func TestSnowflakeNumericJSON(t *testing.T) {
+	var payload struct {
+		ID        Snowflake   `json:"id"`
+		ChannelID Snowflake   `json:"channel_id"`
+		Roles     []Snowflake `json:"roles"`
+	}
+
+	body := []byte(`{"id":1100000000000000001,"channel_id":"1100000000000000002","roles":[18446744073709551615,"3",null]}`)
+
+	if err := DecodeJSON(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.ID != "1100000000000000001" || payload.ChannelID != "1100000000000000002" ||
+		len(payload.Roles) != 3 || payload.Roles[0] != "18446744073709551615" || payload.Roles[1] != "3" || payload.Roles[2] != "" {
+		t.Errorf("DecodeJSON() = %+v, want every ID with its digits", payload)
+	}
+
+	if err := DecodeJSON([]byte(`{"id":18446744073709551616}`), &payload); !errors.Is(err, ErrInvalidSnowflake) {
+		t.Errorf("DecodeJSON() of an overflowing ID = %v, want ErrInvalidSnowflake", err)
+	}
+
+	// Numbers decoded into interface{} keep their digits as json.Number
+	var raw map[string]interface{}
+	if err := DecodeJSON(body, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := NumberToSnowflake(raw["id"].(json.Number)); id != "1100000000000000001" || err != nil {
+		t.Errorf("NumberToSnowflake() = %q, %v, want 1100000000000000001", id, err)
+	}
+
+	if _, err := NumberToSnowflake("1.5"); !errors.Is(err, ErrInvalidSnowflake) {
+		t.Errorf("NumberToSnowflake(1.5) = %v, want ErrInvalidSnowflake", err)
+	}
+}
+
+func TestSnowflakeTimestamp(t *testing.T) {
+	tests := []struct {
+		id   Snowflake
+		want time.Time
+	}{
+		// The example of the Discord documentation
+		{"175928847299117063", time.Date(2016, 4, 30, 11, 18, 25, 796*int(time.Millisecond), time.UTC)},
+		{"4194304", time.UnixMilli(DiscordEpoch + 1)},
+		{"1", time.UnixMilli(DiscordEpoch)},
+		{"", time.UnixMilli(DiscordEpoch)},
+		{"18446744073709551615", time.UnixMilli(DiscordEpoch + 1<<42 - 1)},
+	}
+
+	for _, test := range tests {
+		if got := test.id.Timestamp(); !got.Equal(test.want) {
+			t.Errorf("Snowflake(%q).Timestamp() = %v, want %v", test.id, got.UTC(), test.want.UTC())
+		}
+
+		if got := test.id.CreatedAt(); !got.Time.Equal(test.want) {
+			t.Errorf("Snowflake(%q).CreatedAt() = %v, want %v", test.id, got.Time.UTC(), test.want.UTC())
+		}
+	}
+
+	if now := nowSnowflake().Timestamp(); time.Since(now) > time.Second || time.Since(now) < 0 {
+		t.Errorf("Timestamp() of a new ID = %v, want now", now)
+	}
+}
+
+func TestSnowflakeCompare(t *testing.T) {
+	tests := []struct {
+		a, b Snowflake
+		want int
+	}{
+		{"175928847299117063", "175928847299117063", 0},
+		{"175928847299117063", "175928847299117064", -1},
+		// Shorter IDs are lower, unlike the string order
+		{"99999999999999999", "100000000000000000", -1},
+		{"9", "10", -1},
+		{"1100000000000000000", "999999999999999999", 1},
+		{"18446744073709551615", "9223372036854775808", 1},
+		{"", "1", -1},
+		{"", "", 0},
+		{"1", "", 1},
+	}
+
+	for _, test := range tests {
+		if got := test.a.Compare(test.b); got != test.want {
+			t.Errorf("%q.Compare(%q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+
+		if got := test.b.Compare(test.a); got != -test.want {
+			t.Errorf("%q.Compare(%q) = %d, want %d", test.b, test.a, got, -test.want)
+		}
+
+		if test.a.Before(test.b) != (test.want < 0) || test.a.After(test.b) != (test.want > 0) {
+			t.Errorf("%q.Before(%q) = %v, After = %v, want the order %d", test.a, test.b, test.a.Before(test.b), test.a.After(test.b), test.want)
+		}
+	}
+
+	ids := []Snowflake{"1100000000000000000", "9", "", "999999999999999999", "10", "18446744073709551615"}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Before(ids[j]) })
+
+	want := []Snowflake{"", "9", "10", "999999999999999999", "1100000000000000000", "18446744073709551615"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("sorted IDs %q, want %q", ids, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package httpcord
+
+import (
+	"sync"
+	"time"
+)
+
+// ComponentStateStore Store for the state components and modals need after the interaction that created them.
+// Values expire after their TTL, implementations must be safe for concurrent use
+type ComponentStateStore interface {
+	// Set Store the value under key until the ttl elapses
+	Set(key string, value []byte, ttl time.Duration) error
+	// Get Value stored under key, ok is false when missing or expired
+	Get(key string) (value []byte, ok bool, err error)
+	Delete(key string) error
+}
+
+// StateTaker Optional ComponentStateStore extension returning the value and removing it at once, so concurrent
+// interactions can not both read it. Shared stores implement it with an atomic get and delete like GETDEL of Redis
+type StateTaker interface {
+	Take(key string) (value []byte, ok bool, err error)
+}
+
+type memoryStateEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStateStore In-memory ComponentStateStore, expired entries are swept while new ones are stored
+type MemoryStateStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryStateEntry
+	lastSweep time.Time
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{entries: make(map[string]memoryStateEntry)}
+}
+
+func (s *MemoryStateStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+
+		s.lastSweep = now
+	}
+
+	s.entries[key] = memoryStateEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStateStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+func (s *MemoryStateStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStateStore) Take(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	delete(s.entries, key)
+
+	if time.Now().After(entry.expiresAt) {
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Len Number of entries stored, including the expired ones not swept yet
+func (s *MemoryStateStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
@@ -0,0 +1,282 @@
+package httpcord
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrStateCorrupt A sealed state record was tampered with, truncated or not written by a SealedStateStore
+	ErrStateCorrupt = errors.New("httpcord: state record is corrupt")
+	// ErrStateKeyUnknown A sealed state record was encrypted with a key the KeyProvider does not have anymore
+	ErrStateKeyUnknown = errors.New("httpcord: state record key is unknown")
+)
+
+// KeyProvider AES keys of the SealedStateStore records, 16, 24 or 32 bytes for AES-128, AES-192 or AES-256.
+// Keeping the previous keys after a rotation lets the records written before it be read until they expire
+type KeyProvider interface {
+	// CurrentKey Key encrypting the new records and its ID, stored in the records
+	CurrentKey() (id string, key []byte, err error)
+	// Key Key of the ID, ok is false when it is unknown
+	Key(id string) (key []byte, ok bool, err error)
+}
+
+// KeyRing KeyProvider of keys held in memory, safe for concurrent use
+type KeyRing struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyRing KeyRing encrypting with the key of the ID, panics when the key is not an AES key
+func NewKeyRing(id string, key []byte) *KeyRing {
+	r := &KeyRing{keys: make(map[string][]byte)}
+	r.Rotate(id, key)
+	return r
+}
+
+// Rotate Encrypt the new records with the key of the ID, the previous keys still decrypt their records.
+// Panics when the key is not an AES key
+func (r *KeyRing) Rotate(id string, key []byte) {
+	if _, err := aes.NewCipher(key); err != nil {
+		panic(fmt.Sprintf("httpcord: state key %q: %v", id, err))
+	}
+
+	if len(id) > 255 {
+		panic(fmt.Sprintf("httpcord: state key ID %q is over 255 bytes", id))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[id] = key
+	r.current = id
+}
+
+// Retire Forget the key of the ID, its records fail with ErrStateKeyUnknown. The current key can not be retired
+func (r *KeyRing) Retire(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id != r.current {
+		delete(r.keys, id)
+	}
+}
+
+func (r *KeyRing) CurrentKey() (string, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current, r.keys[r.current], nil
+}
+
+func (r *KeyRing) Key(id string) ([]byte, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	return key, ok, nil
+}
+
+type SealedStoreOption func(o *sealedStoreOptions)
+
+type sealedStoreOptions struct {
+	compressAbove int
+	keys          KeyProvider
+}
+
+// SealCompressAbove Gzip the values over the size in bytes, kept as they are when it does not make them smaller
+func SealCompressAbove(size int) SealedStoreOption {
+	return func(o *sealedStoreOptions) {
+		o.compressAbove = size
+	}
+}
+
+// SealKeys Encrypt the values with AES-GCM under the current key of the provider
+func SealKeys(keys KeyProvider) SealedStoreOption {
+	return func(o *sealedStoreOptions) {
+		o.keys = keys
+	}
+}
+
+// sealedRecordVersion First byte of the sealed records
+const sealedRecordVersion = 1
+
+// Sealed record flags
+const (
+	sealedCompressed byte = 1 << iota
+	sealedEncrypted
+)
+
+// SealedStateStore ComponentStateStore compressing and encrypting the values of the next store, see
+// ConnectionOptions.StateKeys. Records are a version byte, a flags byte and for encrypted records the key ID and
+// nonce before the ciphertext. The store key is authenticated along the value, so records can not be swapped.
+// Values written before the store was sealed fail with ErrStateCorrupt
+type SealedStateStore struct {
+	next    ComponentStateStore
+	options sealedStoreOptions
+}
+
+func NewSealedStateStore(next ComponentStateStore, opts ...SealedStoreOption) *SealedStateStore {
+	s := &SealedStateStore{next: next}
+	for _, opt := range opts {
+		opt(&s.options)
+	}
+
+	return s
+}
+
+func (s *SealedStateStore) Set(key string, value []byte, ttl time.Duration) error {
+	record, err := s.seal(key, value)
+	if err != nil {
+		return err
+	}
+
+	return s.next.Set(key, record, ttl)
+}
+
+func (s *SealedStateStore) Get(key string) ([]byte, bool, error) {
+	record, ok, err := s.next.Get(key)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	value, err := s.open(key, record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (s *SealedStateStore) Delete(key string) error {
+	return s.next.Delete(key)
+}
+
+// seal Record of the value stored under key
+func (s *SealedStateStore) seal(key string, value []byte) ([]byte, error) {
+	var flags byte
+
+	if s.options.compressAbove > 0 && len(value) > s.options.compressAbove {
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+
+		if _, err := w.Write(value); err != nil {
+			return nil, err
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+
+		if compressed.Len() < len(value) {
+			value = compressed.Bytes()
+			flags |= sealedCompressed
+		}
+	}
+
+	if s.options.keys == nil {
+		return append([]byte{sealedRecordVersion, flags}, value...), nil
+	}
+
+	id, secret, err := s.options.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newStateAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{sealedRecordVersion, flags | sealedEncrypted, byte(len(id))}, id...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	record := append(header, nonce...)
+	return aead.Seal(record, nonce, value, sealedAdditionalData(header, key)), nil
+}
+
+// open Value of the record stored under key
+func (s *SealedStateStore) open(key string, record []byte) ([]byte, error) {
+	if len(record) < 2 || record[0] != sealedRecordVersion {
+		return nil, ErrStateCorrupt
+	}
+
+	flags, value := record[1], record[2:]
+
+	if flags&sealedEncrypted != 0 {
+		if s.options.keys == nil || len(value) < 1 || len(value) < 1+int(value[0]) {
+			return nil, ErrStateCorrupt
+		}
+
+		id := string(value[1 : 1+int(value[0])])
+		header := record[:3+len(id)]
+		value = value[1+len(id):]
+
+		secret, ok, err := s.options.keys.Key(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrStateKeyUnknown, id)
+		}
+
+		aead, err := newStateAEAD(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(value) < aead.NonceSize() {
+			return nil, ErrStateCorrupt
+		}
+
+		value, err = aead.Open(nil, value[:aead.NonceSize()], value[aead.NonceSize():], sealedAdditionalData(header, key))
+		if err != nil {
+			return nil, ErrStateCorrupt
+		}
+	} else if s.options.keys != nil {
+		// Plain records would let anyone with access to the next store forge state
+		return nil, ErrStateCorrupt
+	}
+
+	if flags&sealedCompressed != 0 {
+		r, err := gzip.NewReader(bytes.NewReader(value))
+		if err != nil {
+			return nil, ErrStateCorrupt
+		}
+
+		value, err = io.ReadAll(r)
+		if err != nil {
+			return nil, ErrStateCorrupt
+		}
+	}
+
+	return value, nil
+}
+
+func newStateAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealedAdditionalData Authenticated data of an encrypted record: its header and the store key
+func sealedAdditionalData(header []byte, key string) []byte {
+	return append(append([]byte{}, header...), key...)
+}
@@ -0,0 +1,185 @@
+package httpcord
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func stateKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+// rawRecord Record the sealed store wrote in the next store
+func rawRecord(t *testing.T, next ComponentStateStore, key string) []byte {
+	t.Helper()
+
+	record, ok, err := next.Get(key)
+	if err != nil || !ok {
+		t.Fatalf("no record of %q: %v", key, err)
+	}
+
+	return record
+}
+
+func TestSealedStateStoreRotation(t *testing.T) {
+	next := NewMemoryStateStore()
+	keys := NewKeyRing("k1", stateKey(1))
+	store := NewSealedStateStore(next, SealKeys(keys))
+
+	if err := store.Set("old", []byte("written under k1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	keys.Rotate("k2", stateKey(2))
+
+	if err := store.Set("new", []byte("written under k2"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"old": "written under k1", "new": "written under k2"} {
+		if value, ok, err := store.Get(key); err != nil || !ok || string(value) != want {
+			t.Errorf("Get(%s) = %q, %v, %v, want %q", key, value, ok, err, want)
+		}
+	}
+
+	if record := rawRecord(t, next, "new"); !bytes.Equal(record[:5], []byte{sealedRecordVersion, sealedEncrypted, 2, 'k', '2'}) {
+		t.Errorf("record header %v, want the version, the flags and the key ID k2", record[:5])
+	}
+
+	if record := rawRecord(t, next, "old"); bytes.Contains(record, []byte("written")) {
+		t.Errorf("record %q holds the value in clear", record)
+	}
+
+	keys.Retire("k1")
+
+	if _, ok, err := store.Get("old"); ok || !errors.Is(err, ErrStateKeyUnknown) {
+		t.Errorf("Get(old) after Retire = %v, %v, want ErrStateKeyUnknown", ok, err)
+	}
+
+	// The current key can not be retired
+	keys.Retire("k2")
+
+	if value, ok, err := store.Get("new"); err != nil || !ok || string(value) != "written under k2" {
+		t.Errorf("Get(new) after retiring the current key = %q, %v, %v", value, ok, err)
+	}
+}
+
+func TestSealedStateStoreTampering(t *testing.T) {
+	const id = "k1"
+	// Offsets of the record: version, flags, key ID length, key ID, nonce, ciphertext
+	nonce := 3 + len(id)
+
+	tests := []struct {
+		name   string
+		tamper func(record []byte) []byte
+		want   error
+	}{
+		{"version", func(r []byte) []byte { r[0] ^= 0xff; return r }, ErrStateCorrupt},
+		{"compressed flag", func(r []byte) []byte { r[1] ^= sealedCompressed; return r }, ErrStateCorrupt},
+		{"encrypted flag", func(r []byte) []byte { r[1] ^= sealedEncrypted; return r }, ErrStateCorrupt},
+		{"nonce", func(r []byte) []byte { r[nonce] ^= 1; return r }, ErrStateCorrupt},
+		{"ciphertext", func(r []byte) []byte { r[nonce+12] ^= 1; return r }, ErrStateCorrupt},
+		{"tag", func(r []byte) []byte { r[len(r)-1] ^= 1; return r }, ErrStateCorrupt},
+		{"truncated", func(r []byte) []byte { return r[:nonce+4] }, ErrStateCorrupt},
+		{"only the version", func(r []byte) []byte { return r[:1] }, ErrStateCorrupt},
+		{"key ID", func(r []byte) []byte { r[3] ^= 1; return r }, ErrStateKeyUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := NewMemoryStateStore()
+			store := NewSealedStateStore(next, SealKeys(NewKeyRing(id, stateKey(1))))
+
+			if err := store.Set("state", []byte("page 3 of the ban list"), time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			record := append([]byte(nil), rawRecord(t, next, "state")...)
+			next.Set("state", test.tamper(record), time.Minute)
+
+			if value, ok, err := store.Get("state"); ok || !errors.Is(err, test.want) {
+				t.Errorf("Get() = %q, %v, %v, want %v", value, ok, err, test.want)
+			}
+		})
+	}
+
+	t.Run("record moved to another key", func(t *testing.T) {
+		next := NewMemoryStateStore()
+		store := NewSealedStateStore(next, SealKeys(NewKeyRing(id, stateKey(1))))
+
+		store.Set("admin", []byte("admin panel"), time.Minute)
+		next.Set("guest", rawRecord(t, next, "admin"), time.Minute)
+
+		if _, ok, err := store.Get("guest"); ok || !errors.Is(err, ErrStateCorrupt) {
+			t.Errorf("Get(guest) = %v, %v, want ErrStateCorrupt", ok, err)
+		}
+	})
+}
+
+func TestSealedStateStorePlainRecords(t *testing.T) {
+	next := NewMemoryStateStore()
+
+	// Records of a store without keys and values written before the store was sealed
+	NewSealedStateStore(next).Set("unencrypted", []byte("state"), time.Minute)
+	next.Set("unsealed", []byte("state"), time.Minute)
+
+	store := NewSealedStateStore(next, SealKeys(NewKeyRing("k1", stateKey(1))))
+
+	for _, key := range []string{"unencrypted", "unsealed"} {
+		if _, ok, err := store.Get(key); ok || !errors.Is(err, ErrStateCorrupt) {
+			t.Errorf("Get(%s) = %v, %v, want ErrStateCorrupt", key, ok, err)
+		}
+	}
+
+	if _, ok, err := store.Get("missing"); ok || err != nil {
+		t.Errorf("Get(missing) = %v, %v, want not found", ok, err)
+	}
+}
+
+func TestSealedStateStoreCompression(t *testing.T) {
+	const threshold = 256
+
+	random := make([]byte, 2*threshold)
+	rand.Read(random)
+
+	tests := []struct {
+		name       string
+		value      []byte
+		keys       bool
+		compressed bool
+	}{
+		{"at the threshold", bytes.Repeat([]byte("a"), threshold), false, false},
+		{"over the threshold", bytes.Repeat([]byte("a"), threshold+1), false, true},
+		{"over the threshold but incompressible", random, false, false},
+		{"encrypted at the threshold", bytes.Repeat([]byte("a"), threshold), true, false},
+		{"encrypted over the threshold", bytes.Repeat([]byte("a"), threshold+1), true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := NewMemoryStateStore()
+
+			opts := []SealedStoreOption{SealCompressAbove(threshold)}
+			if test.keys {
+				opts = append(opts, SealKeys(NewKeyRing("k1", stateKey(1))))
+			}
+
+			store := NewSealedStateStore(next, opts...)
+
+			if err := store.Set("state", test.value, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			if compressed := rawRecord(t, next, "state")[1]&sealedCompressed != 0; compressed != test.compressed {
+				t.Errorf("compressed %v, want %v", compressed, test.compressed)
+			}
+
+			if value, ok, err := store.Get("state"); err != nil || !ok || !bytes.Equal(value, test.value) {
+				t.Errorf("Get() = %q, %v, %v, want the value back", value, ok, err)
+			}
+		})
+	}
+}
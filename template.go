@@ -0,0 +1,280 @@
+package httpcord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTemplate The template has a placeholder ValidateTemplate does not accept
+var ErrInvalidTemplate = errors.New("httpcord: invalid template")
+
+// TemplateError Returned by ValidateTemplate for the first invalid placeholder
+type TemplateError struct {
+	// Placeholder is the placeholder as written, braces included
+	Placeholder string
+	// Offset is the byte offset of the placeholder in the template
+	Offset int
+	Reason string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("httpcord: template placeholder %s at %d: %s", e.Placeholder, e.Offset, e.Reason)
+}
+
+func (e *TemplateError) Is(target error) bool {
+	return target == ErrInvalidTemplate
+}
+
+// Template fields, the part after the dot of {name.field}
+const (
+	MentionTemplateField = "mention"
+	IDTemplateField      = "id"
+	NameTemplateField    = "name"
+	RoleTemplateField    = "role"
+	ChannelTemplateField = "channel"
+)
+
+// templateFields Fields ValidateTemplate accepts, the timestamp styles included
+var templateFields = map[string]bool{
+	MentionTemplateField:       true,
+	IDTemplateField:            true,
+	NameTemplateField:          true,
+	RoleTemplateField:          true,
+	ChannelTemplateField:       true,
+	string(ShortTimeStyle):     true,
+	string(LongTimeStyle):      true,
+	string(ShortDateStyle):     true,
+	string(LongDateStyle):      true,
+	string(ShortDateTimeStyle): true,
+	string(LongDateTimeStyle):  true,
+	string(RelativeStyle):      true,
+}
+
+type TemplateOption func(o *templateOptions)
+
+type templateOptions struct {
+	raw map[string]bool
+}
+
+// TemplateRaw Substitute the variables as they are instead of escaping them with Sanitize, for trusted values
+// already formatted as markdown
+func TemplateRaw(names ...string) TemplateOption {
+	return func(o *templateOptions) {
+		for _, name := range names {
+			o.raw[name] = true
+		}
+	}
+}
+
+// templatePlaceholder {name} or {name.field} of a template
+type templatePlaceholder struct {
+	name, field string
+	// start and end are the byte offsets of the braces
+	start, end int
+}
+
+// scanTemplate Call literal with the text between the placeholders, the doubled braces {{ and }} unescaped, and
+// placeholder with the placeholders. Braces that do not make a placeholder are passed to invalid and kept as text
+func scanTemplate(tmpl string, literal func(s string), placeholder func(p templatePlaceholder), invalid func(offset int, text, reason string)) {
+	for i := 0; i < len(tmpl); {
+		switch {
+		case strings.HasPrefix(tmpl[i:], "{{"), strings.HasPrefix(tmpl[i:], "}}"):
+			literal(tmpl[i : i+1])
+			i += 2
+		case tmpl[i] == '}':
+			invalid(i, "}", "unmatched closing brace, write }} for a literal brace")
+			literal("}")
+			i++
+		case tmpl[i] == '{':
+			end := strings.IndexAny(tmpl[i+1:], "{}")
+			if end < 0 || tmpl[i+1+end] != '}' {
+				invalid(i, "{", "unclosed placeholder, write {{ for a literal brace")
+				literal("{")
+				i++
+				continue
+			}
+
+			end += i + 1
+			name, field := tmpl[i+1:end], ""
+			if dot := strings.IndexByte(name, '.'); dot >= 0 {
+				name, field = name[:dot], name[dot+1:]
+			}
+
+			if !validTemplateName(name) {
+				invalid(i, tmpl[i:end+1], "invalid variable name")
+				literal(tmpl[i : end+1])
+			} else {
+				placeholder(templatePlaceholder{name: name, field: field, start: i, end: end})
+			}
+
+			i = end + 1
+		default:
+			next := strings.IndexAny(tmpl[i:], "{}")
+			if next < 0 {
+				next = len(tmpl) - i
+			}
+
+			literal(tmpl[i : i+next])
+			i += next
+		}
+	}
+}
+
+func validTemplateName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RenderTemplate Substitute the {name} and {name.field} placeholders of the template with the variables, escaped
+// with Sanitize unless TemplateRaw is used. {{ and }} are literal braces. Users, members, channels and roles render
+// as their mention, Time and time.Time as a short date time timestamp and the other values with fmt.
+//
+// Fields select what is rendered: mention, id and name of users, members, channels and roles, mention, role and
+// channel of a Snowflake for the user, role and channel mention of the ID, and a TimestampStyle like R of times.
+// Placeholders of unknown variables and fields are kept as written, use ValidateTemplate to catch them in tests:
+//
+//	RenderTemplate("{user.mention} was warned for {reason}, {until.R}", map[string]interface{}{
+//		"user": ctx.User(), "reason": reason, "until": until,
+//	})
+func RenderTemplate(tmpl string, vars map[string]interface{}, opts ...TemplateOption) string {
+	o := templateOptions{raw: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var b strings.Builder
+	b.Grow(len(tmpl))
+
+	scanTemplate(tmpl, func(s string) {
+		b.WriteString(s)
+	}, func(p templatePlaceholder) {
+		value, ok := vars[p.name]
+		if !ok {
+			b.WriteString(tmpl[p.start : p.end+1])
+			return
+		}
+
+		rendered, content, ok := renderTemplateValue(value, p.field)
+		if !ok {
+			b.WriteString(tmpl[p.start : p.end+1])
+			return
+		}
+
+		if content && !o.raw[p.name] {
+			rendered = Sanitize(rendered)
+		}
+
+		b.WriteString(rendered)
+	}, func(int, string, string) {})
+
+	return b.String()
+}
+
+// ValidateTemplate Check that every placeholder of the template is one of the allowed variables with a known field and
+// that the braces are balanced, for checking the templates in CI. Returns a TemplateError for the first invalid one
+func ValidateTemplate(tmpl string, allowedVars []string) error {
+	allowed := make(map[string]bool, len(allowedVars))
+	for _, name := range allowedVars {
+		allowed[name] = true
+	}
+
+	var err error
+	fail := func(offset int, text, reason string) {
+		if err == nil {
+			err = &TemplateError{Placeholder: text, Offset: offset, Reason: reason}
+		}
+	}
+
+	scanTemplate(tmpl, func(string) {}, func(p templatePlaceholder) {
+		switch {
+		case !allowed[p.name]:
+			fail(p.start, tmpl[p.start:p.end+1], "unknown variable "+p.name)
+		case p.field != "" && !templateFields[p.field]:
+			fail(p.start, tmpl[p.start:p.end+1], "unknown field "+p.field)
+		}
+	}, fail)
+
+	return err
+}
+
+// renderTemplateValue Text of the value for the field, content reports whether it is user content escaped by the
+// template. ok is false when the value has no such field
+func renderTemplateValue(value interface{}, field string) (text string, content bool, ok bool) {
+	switch v := value.(type) {
+	case *User:
+		switch field {
+		case "", MentionTemplateField:
+			return v.Mention(), false, true
+		case IDTemplateField:
+			return v.ID.String(), false, true
+		case NameTemplateField:
+			return v.Username, true, true
+		}
+	case *Member:
+		if v.User == nil {
+			return "", false, false
+		}
+
+		if field == NameTemplateField && v.Nick != "" {
+			return v.Nick, true, true
+		}
+
+		return renderTemplateValue(v.User, field)
+	case *Channel:
+		switch field {
+		case "", MentionTemplateField:
+			return v.Mention(), false, true
+		case IDTemplateField:
+			return v.ID.String(), false, true
+		case NameTemplateField:
+			return v.Name, true, true
+		}
+	case *Role:
+		switch field {
+		case "", MentionTemplateField:
+			return "<@&" + v.ID.String() + ">", false, true
+		case IDTemplateField:
+			return v.ID.String(), false, true
+		case NameTemplateField:
+			return v.Name, true, true
+		}
+	case Snowflake:
+		switch field {
+		case "", IDTemplateField:
+			return v.String(), false, true
+		case MentionTemplateField:
+			return "<@" + v.String() + ">", false, true
+		case RoleTemplateField:
+			return "<@&" + v.String() + ">", false, true
+		case ChannelTemplateField:
+			return "<#" + v.String() + ">", false, true
+		}
+	case Time:
+		if field == "" {
+			field = string(ShortDateTimeStyle)
+		}
+
+		if templateFields[field] && len(field) == 1 {
+			return v.Format(TimestampStyle(field)), false, true
+		}
+	case time.Time:
+		return renderTemplateValue(Time{Time: v}, field)
+	default:
+		if field == "" {
+			return fmt.Sprint(value), true, true
+		}
+	}
+
+	return "", false, false
+}
@@ -0,0 +1,89 @@
+package httpcord
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateHostileValues(t *testing.T) {
+	user := &User{ID: "6", Username: "@everyone **boss**"}
+	long := strings.Repeat("a", 100000)
+
+	tests := []struct {
+		name string
+		tmpl string
+		vars map[string]interface{}
+		opts []TemplateOption
+		want string
+	}{
+		{"everyone", "reason: {reason}", map[string]interface{}{"reason": "@everyone"}, nil, "reason: @" + zeroWidthSpace + "everyone"},
+		{"here in a name", "{user.name} joined", map[string]interface{}{"user": &User{ID: "6", Username: "@here"}}, nil, "@" + zeroWidthSpace + "here joined"},
+		{"user mention", "{reason}", map[string]interface{}{"reason": "<@123> <@&4> <#5>"}, nil, `\<@` + zeroWidthSpace + `123> \<@` + zeroWidthSpace + `&4> \<` + zeroWidthSpace + `#5>`},
+		{"markdown", "{reason}", map[string]interface{}{"reason": "**bold** ||spoiler|| [link](https://evil.example)"}, nil,
+			`\*\*bold\*\* \|\|spoiler\|\| \[link\]\(https://evil.example\)`},
+		{"heading and quote", "{reason}", map[string]interface{}{"reason": "# big\n> quoted"}, nil, "\\# big\n\\> quoted"},
+		{"code block escape", "`{reason}`", map[string]interface{}{"reason": "` @everyone `"}, nil, "`\\` @" + zeroWidthSpace + "everyone \\``"},
+		{"mention of the user kept", "{user}", map[string]interface{}{"user": user}, nil, "<@6>"},
+		{"name of the user escaped", "{user.name}", map[string]interface{}{"user": user}, nil, "@" + zeroWidthSpace + "everyone \\*\\*boss\\*\\*"},
+		{"placeholder in a value", "{reason}", map[string]interface{}{"reason": "{secret}", "secret": "token"}, nil, "{secret}"},
+		{"braces in a value", "{reason} {{x}}", map[string]interface{}{"reason": "}}{{"}, nil, "}}{{ {x}"},
+		{"raw value", "{reason}", map[string]interface{}{"reason": "**@everyone**"}, []TemplateOption{TemplateRaw("reason")}, "**@everyone**"},
+		{"long value", "{reason}!", map[string]interface{}{"reason": long}, nil, long + "!"},
+		{"unclosed placeholder", "{reason", map[string]interface{}{"reason": "@everyone"}, nil, "{reason"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := RenderTemplate(test.tmpl, test.vars, test.opts...); got != test.want {
+				t.Errorf("RenderTemplate() = %.200q, want %.200q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	allowed := []string{"user", "reason", "until"}
+
+	tests := []struct {
+		name string
+		tmpl string
+		// placeholder and offset of the error, empty when the template is valid
+		placeholder string
+		offset      int
+	}{
+		{"valid", "{user.mention} warned for {reason} until {until.R}, {{literal}}", "", 0},
+		{"unknown variable", "{user} {token}", "{token}", 7},
+		{"unknown field", "{user.password}", "{user.password}", 0},
+		{"mention as a name", "{@everyone}", "{@everyone}", 0},
+		{"markdown in a name", "{user} {**reason**}", "{**reason**}", 7},
+		{"unmatched closing brace", "{user}}", "}", 6},
+		{"unclosed placeholder", "{user", "{", 0},
+		{"nested placeholder", "{user{reason}}", "{", 0},
+		{"error after a long text", strings.Repeat("x", 10000) + "{token}", "{token}", 10000},
+		{"long unclosed placeholder", "{" + strings.Repeat("x", 10000), "{", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTemplate(test.tmpl, allowed)
+
+			if test.placeholder == "" {
+				if err != nil {
+					t.Errorf("ValidateTemplate() = %v", err)
+				}
+
+				return
+			}
+
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) || !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("ValidateTemplate() = %v, want a TemplateError", err)
+			}
+
+			if templateErr.Placeholder != test.placeholder || templateErr.Offset != test.offset {
+				t.Errorf("error of %q at %d, want %q at %d", templateErr.Placeholder, templateErr.Offset, test.placeholder, test.offset)
+			}
+		})
+	}
+}
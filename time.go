@@ -1,17 +1,72 @@
 package httpcord
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
 type (
+	// Time ISO8601 timestamp of the Discord payloads, null and empty strings decode to the zero time
+	// and the zero time encodes to null so unset fields are not sent as year 1. omitempty does not apply to a struct,
+	// an unset Time is sent as null which clears the field, optional fields of the requests are *Time
 	Time struct {
 		time.Time
 	}
 	TimestampStyle string
 )
 
+// timeLayouts Layouts of the timestamps sent by Discord, RFC3339Nano also parses the timestamps without fraction.
+// Some payloads omit the offset of UTC timestamps
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+// optionalTime Time of an optional field, nil for the zero time
+func optionalTime(t Time) *Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("httpcord: timestamp %s is not a string", data)
+	}
+
+	if value == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("httpcord: invalid timestamp %q", value)
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
 const (
 	ShortTimeStyle     TimestampStyle = "t"
 	LongTimeStyle      TimestampStyle = "T"
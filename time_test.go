@@ -0,0 +1,118 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+		// fails The timestamp is refused
+		fails bool
+	}{
+		{"six digit fraction", `"2021-03-04T05:06:07.123456+00:00"`, time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.UTC), false},
+		{"no fraction", `"2021-03-04T05:06:07+00:00"`, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), false},
+		{"Z offset", `"2021-03-04T05:06:07.123456Z"`, time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.UTC), false},
+		{"other offset", `"2021-03-04T07:06:07.5+02:00"`, time.Date(2021, 3, 4, 5, 6, 7, 500000000, time.UTC), false},
+		{"no offset", `"2021-03-04T05:06:07.123456"`, time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.UTC), false},
+		{"null", `null`, time.Time{}, false},
+		{"empty", `""`, time.Time{}, false},
+		{"number", `1614834367`, time.Time{}, true},
+		{"date only", `"2021-03-04"`, time.Time{}, true},
+		{"garbage", `"yesterday"`, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded := Time{Time: time.Now()}
+
+			err := json.Unmarshal([]byte(test.json), &decoded)
+			if (err != nil) != test.fails {
+				t.Fatalf("Unmarshal(%s) = %v, want an error = %v", test.json, err, test.fails)
+			}
+
+			if !test.fails && !decoded.Equal(test.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", test.json, decoded.Time, test.want)
+			}
+		})
+	}
+}
+
+func TestTimeMarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		time Time
+		want string
+	}{
+		{"zero is null", Time{}, `null`},
+		{"UTC", Time{Time: time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.UTC)}, `"2021-03-04T05:06:07.123456Z"`},
+		{"converted to UTC", Time{Time: time.Date(2021, 3, 4, 7, 6, 7, 0, time.FixedZone("", 2*60*60))}, `"2021-03-04T05:06:07Z"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != test.want {
+				t.Errorf("Marshal() = %s, want %s", b, test.want)
+			}
+
+			var decoded Time
+			if err := json.Unmarshal(b, &decoded); err != nil || !decoded.Equal(test.time.Time) {
+				t.Errorf("round trip = %v, %v, want %v", decoded.Time, err, test.time.Time)
+			}
+		})
+	}
+}
+
+func TestTimeFields(t *testing.T) {
+	until := Time{Time: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		// contains Encoding of the field, empty when it must be left out
+		contains string
+		field    string
+	}{
+		{"unset Time is null", Embed{}, `"timestamp":null`, "timestamp"},
+		{"unset timeout is left out", Member{}, "", "communication_disabled_until"},
+		{"timeout", Member{CommunicationDisabledUntil: &until}, `"communication_disabled_until":"2021-03-04T05:06:07Z"`, "communication_disabled_until"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.contains == "" && strings.Contains(string(b), test.field) {
+				t.Errorf("%s has %s, it must be left out", b, test.field)
+			} else if !strings.Contains(string(b), test.contains) {
+				t.Errorf("%s is missing %s", b, test.contains)
+			}
+		})
+	}
+
+	t.Run("resolved timeout", func(t *testing.T) {
+		for _, wire := range []Time{{}, until} {
+			member, err := resolveMember(&APIMember{User: &APIUser{ID: "4"}, Permissions: "8", CommunicationDisabledUntil: wire})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := member.CommunicationDisabledUntil; (got == nil) != wire.IsZero() || (got != nil && !got.Equal(wire.Time)) {
+				t.Errorf("resolved timeout %v, want %v", got, wire.Time)
+			}
+		}
+	})
+}
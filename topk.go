@@ -0,0 +1,111 @@
+package httpcord
+
+import (
+	"sort"
+	"sync"
+)
+
+// TopK Track the k most frequent keys of a stream in bounded memory, like the command labels of MetricsGuard.
+// Keys past the k first ones wait as candidates and replace the least frequent tracked key once they are seen more
+// often. Safe for concurrent use
+type TopK struct {
+	mu         sync.Mutex
+	k          int
+	tracked    map[string]uint64
+	candidates map[string]uint64
+}
+
+func NewTopK(k int) *TopK {
+	return &TopK{k: k, tracked: make(map[string]uint64, k), candidates: make(map[string]uint64, k)}
+}
+
+// Add Count an occurrence of the key, returns whether the key is tracked after it
+func (t *TopK) Add(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.tracked[key]; ok {
+		t.tracked[key]++
+		return true
+	}
+
+	if len(t.tracked) < t.k {
+		t.tracked[key] = 1
+		return true
+	}
+
+	count := t.candidates[key] + 1
+	least, leastCount := t.least(t.tracked)
+
+	if t.k > 0 && count > leastCount {
+		delete(t.candidates, key)
+		delete(t.tracked, least)
+		t.tracked[key] = count
+		t.remember(least, leastCount)
+		return true
+	}
+
+	t.remember(key, count)
+	return false
+}
+
+// remember Keep the candidate count, the least frequent candidate is forgotten past k candidates
+func (t *TopK) remember(key string, count uint64) {
+	if _, ok := t.candidates[key]; !ok && len(t.candidates) >= t.k {
+		least, leastCount := t.least(t.candidates)
+		if leastCount > count {
+			return
+		}
+
+		delete(t.candidates, least)
+	}
+
+	t.candidates[key] = count
+}
+
+// least Least frequent key of the counts, the smallest key on ties so the order does not depend on the map
+func (t *TopK) least(counts map[string]uint64) (string, uint64) {
+	var (
+		least      string
+		leastCount uint64
+		found      bool
+	)
+
+	for key, count := range counts {
+		if !found || count < leastCount || count == leastCount && key < least {
+			least, leastCount, found = key, count, true
+		}
+	}
+
+	return least, leastCount
+}
+
+// Contains Whether the key is tracked
+func (t *TopK) Contains(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.tracked[key]
+	return ok
+}
+
+// Keys Tracked keys from the most to the least frequent
+func (t *TopK) Keys() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]string, 0, len(t.tracked))
+	for key := range t.tracked {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if t.tracked[keys[i]] != t.tracked[keys[j]] {
+			return t.tracked[keys[i]] > t.tracked[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
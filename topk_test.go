@@ -0,0 +1,69 @@
+package httpcord
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		// stream Keys added in order, separated by spaces
+		stream string
+		keys   []string
+		// last Add of the last key returned true
+		last bool
+	}{
+		{"fills up to k", 2, "a b", []string{"a", "b"}, true},
+		{"candidate as frequent as the least", 2, "a a b c", []string{"a", "b"}, false},
+		{"candidate evicting the least", 2, "a a b c c", []string{"a", "c"}, true},
+		{"evicted key coming back", 2, "a a b c c b b b", []string{"b", "c"}, true},
+		{"tracked keys keep counting", 2, "a b b c", []string{"b", "a"}, false},
+		{"nothing tracked", 0, "a a a", []string{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			top := NewTopK(test.k)
+
+			var last bool
+			for _, key := range strings.Fields(test.stream) {
+				last = top.Add(key)
+			}
+
+			if keys := top.Keys(); !reflect.DeepEqual(keys, test.keys) {
+				t.Errorf("Keys() = %v, want %v", keys, test.keys)
+			}
+
+			if last != test.last {
+				t.Errorf("last Add() = %v, want %v", last, test.last)
+			}
+
+			for _, key := range test.keys {
+				if !top.Contains(key) {
+					t.Errorf("Contains(%q) = false", key)
+				}
+			}
+		})
+	}
+}
+
+func TestTopKBoundedMemory(t *testing.T) {
+	top := NewTopK(3)
+
+	for i := 0; i < 1000; i++ {
+		top.Add("hot")
+		top.Add("key-" + strconv.Itoa(i))
+	}
+
+	if len(top.tracked) != 3 || len(top.candidates) > 3 {
+		t.Errorf("%d tracked and %d candidates, want at most 3 each", len(top.tracked), len(top.candidates))
+	}
+
+	if keys := top.Keys(); keys[0] != "hot" {
+		t.Errorf("Keys() = %v, want hot first", keys)
+	}
+}
@@ -0,0 +1,79 @@
+package httpcord
+
+import (
+	"context"
+	"strings"
+)
+
+// Tracer Start the spans of the library, adapted to OpenTelemetry or another tracing system:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, httpcord.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		for key, value := range attributes {
+//			span.SetAttributes(attribute.String(key, value))
+//		}
+//
+//		return ctx, otelSpan{span}
+//	}
+//
+// The context returned is the parent of the spans started with it, like the REST calls of a handler through
+// ConnectionContext.Context
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span Operation traced by a Tracer, ended once
+type Span interface {
+	SetAttribute(key, value string)
+	RecordError(err error)
+	End()
+}
+
+// Span Names
+
+const (
+	VerifySpan   = "httpcord.verify"
+	DispatchSpan = "httpcord.dispatch"
+	RestSpan     = "httpcord.rest"
+)
+
+type nopTracer struct{}
+
+type nopSpan struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ map[string]string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+func (nopSpan) SetAttribute(string, string) {}
+
+func (nopSpan) RecordError(error) {}
+
+func (nopSpan) End() {}
+
+// NopTracer Default Tracer starting no span
+var NopTracer Tracer = nopTracer{}
+
+// endSpan End the span with the error recorded when not nil
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// metricRoute Route label of the REST metrics and spans, logRoute with the IDs replaced so the routes of every
+// resource share a label
+func metricRoute(path string) string {
+	segments := strings.Split(logRoute(path), "/")
+	for i, segment := range segments {
+		if isNumeric(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
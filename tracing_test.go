@@ -0,0 +1,102 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"httpcord/endpoints"
+)
+
+const testInteractionToken = "aW50ZXJhY3Rpb246MTIzNDU2Nzg5OmFiY2RlZg"
+
+func TestMetricRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		route string
+	}{
+		{"interaction callback", endpoints.InteractionCallback("1234567890", testInteractionToken), "/interactions/{id}/{token}/callback"},
+		{"interaction callback with query", endpoints.InteractionCallback("1234567890", testInteractionToken) + "?with_response=true", "/interactions/{id}/{token}/callback"},
+		{"webhook message", endpoints.WebhookMessage("1234567890", testInteractionToken, "@original"), "/webhooks/{id}/{token}/messages/@original"},
+		{"webhook followup", endpoints.WebhookMessage("1234567890", testInteractionToken, "987654321"), "/webhooks/{id}/{token}/messages/{id}"},
+		{"webhook execute", endpoints.WebhookExecute("1234567890", testInteractionToken), "/webhooks/{id}/{token}"},
+		{"channel", endpoints.Channel("1234567890"), "/channels/{id}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if route := metricRoute(test.path); route != test.route {
+				t.Errorf("metricRoute(%q) = %q, want %q", test.path, route, test.route)
+			}
+
+			if route := logRoute(test.path); strings.Contains(route, testInteractionToken) {
+				t.Errorf("logRoute(%q) = %q leaks the token", test.path, route)
+			}
+		})
+	}
+}
+
+type recordingTracer struct {
+	mu         sync.Mutex
+	attributes []map[string]string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, _ string, attributes map[string]string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.attributes = append(t.attributes, attributes)
+	return ctx, &recordingSpan{tracer: t}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+}
+
+func (s *recordingSpan) SetAttribute(key, value string) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.tracer.attributes = append(s.tracer.attributes, map[string]string{key: value})
+}
+
+func (s *recordingSpan) RecordError(error) {}
+
+func (s *recordingSpan) End() {}
+
+func TestRestSpanRedactsCallbackToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewRestClient(StaticToken("token"))
+	client.BaseURL = server.URL
+	client.Tracer = tracer
+
+	route := endpoints.InteractionCallback("1234567890", testInteractionToken)
+	if err := client.Do(context.Background(), http.MethodPost, route, map[string]int{"type": 1}, nil, withoutAuth()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.attributes) == 0 {
+		t.Fatal("no span was started")
+	}
+
+	for _, attributes := range tracer.attributes {
+		for key, value := range attributes {
+			if strings.Contains(value, testInteractionToken) {
+				t.Errorf("span attribute %s = %q leaks the token", key, value)
+			}
+		}
+	}
+
+	if route := tracer.attributes[0]["http.route"]; route != "/interactions/{id}/{token}/callback" {
+		t.Errorf("http.route = %q", route)
+	}
+}
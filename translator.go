@@ -0,0 +1,94 @@
+package httpcord
+
+import "fmt"
+
+// Translator Messages identified by key in every locale, missing locales use the fallback locale
+type Translator struct {
+	Messages map[string]Dictionary
+	Plurals  map[string]PluralDictionary
+	// Fallback is the locale used when a message is missing in the requested locale
+	Fallback Locale
+}
+
+func NewTranslator(fallback Locale) *Translator {
+	return &Translator{
+		Messages: make(map[string]Dictionary),
+		Plurals:  make(map[string]PluralDictionary),
+		Fallback: fallback,
+	}
+}
+
+func (t *Translator) Add(key string, messages Dictionary) *Translator {
+	t.Messages[key] = messages
+	return t
+}
+
+func (t *Translator) AddPlural(key string, forms PluralDictionary) *Translator {
+	t.Plurals[key] = forms
+	return t
+}
+
+// T Message of the key formatted with args, the key itself when missing
+func (t *Translator) T(key string, locale Locale, args ...interface{}) string {
+	message, ok := t.lookup(key, locale)
+	if !ok {
+		return key
+	}
+
+	return formatMessage(message, args)
+}
+
+// lookup Message of the key in the locale or the fallback locale
+func (t *Translator) lookup(key string, locale Locale) (string, bool) {
+	return t.lookupChain(key, []Locale{locale})
+}
+
+// lookupChain Message of the key in the first locale of the chain providing it, or the fallback locale
+func (t *Translator) lookupChain(key string, chain []Locale) (string, bool) {
+	messages := t.Messages[key]
+
+	for _, locale := range append(append([]Locale(nil), chain...), t.Fallback) {
+		if message, ok := messages[locale]; ok {
+			return message, true
+		}
+	}
+
+	return "", false
+}
+
+// TPlural Plural form of the key for n formatted with args, the rule of the fallback locale is used when it provides the message
+func (t *Translator) TPlural(key string, n int, locale Locale, args ...interface{}) string {
+	message, ok := t.pluralChain(key, n, []Locale{locale})
+	if !ok {
+		return key
+	}
+
+	return formatMessage(message, args)
+}
+
+// pluralChain Plural form of the key for n in the first locale of the chain providing it, with the rule of that locale
+func (t *Translator) pluralChain(key string, n int, chain []Locale) (string, bool) {
+	forms := t.Plurals[key]
+
+	for _, locale := range append(append([]Locale(nil), chain...), t.Fallback) {
+		if message, ok := forms[locale].Get(PluralRule(locale, n)); ok {
+			return message, true
+		}
+	}
+
+	return "", false
+}
+
+// formatMessage Message formatted with fmt.Sprintf, or with its {name} placeholders replaced when args is a single
+// map[string]interface{}
+func formatMessage(message string, args []interface{}) string {
+	if len(args) == 0 {
+		return message
+	}
+
+	if named, ok := args[0].(map[string]interface{}); ok && len(args) == 1 {
+		return replaceArgs(message, named)
+	}
+
+	return fmt.Sprintf(message, args...)
+}
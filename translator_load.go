@@ -0,0 +1,262 @@
+package httpcord
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pluralCategories Keys of the objects and tables loaded as plural forms
+var pluralCategories = map[string]bool{
+	string(PluralOne):   true,
+	string(PluralFew):   true,
+	string(PluralMany):  true,
+	string(PluralOther): true,
+}
+
+// LoadJSON Add the messages of the locale from a JSON bundle. Nested objects join their keys with dots and objects
+// of plural categories are plural forms:
+//
+//	{
+//		"greeting": "Hello {name}!",
+//		"ban": {"success": "Banned {user}"},
+//		"warnings": {"one": "{count} warning", "other": "{count} warnings"}
+//	}
+func (t *Translator) LoadJSON(locale Locale, r io.Reader) error {
+	var bundle map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return fmt.Errorf("httpcord: invalid %s bundle: %w", locale, err)
+	}
+
+	return t.loadBundle(locale, "", bundle)
+}
+
+// LoadTOML Add the messages of the locale from a TOML bundle, tables are the nested objects of LoadJSON:
+//
+//	greeting = "Hello {name}!"
+//
+//	[warnings]
+//	one = "{count} warning"
+//	other = "{count} warnings"
+//
+// Only string values are read, the multi-line strings, arrays and inline tables of TOML are refused
+func (t *Translator) LoadTOML(locale Locale, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	bundle := make(map[string]interface{})
+	table := bundle
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fail := func(reason string) error {
+			return fmt.Errorf("httpcord: invalid %s bundle: line %d %s", locale, i+1, reason)
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return fail("is not a table header")
+			}
+
+			table = bundle
+			for _, name := range strings.Split(line[1:len(line)-1], ".") {
+				key, err := tomlKey(strings.TrimSpace(name))
+				if err != nil {
+					return fail(err.Error())
+				}
+
+				nested, ok := table[key].(map[string]interface{})
+				if !ok {
+					nested = make(map[string]interface{})
+					table[key] = nested
+				}
+
+				table = nested
+			}
+
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return fail("is not a key/value pair")
+		}
+
+		key, err := tomlKey(strings.TrimSpace(line[:eq]))
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		value, err := tomlString(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		table[key] = value
+	}
+
+	return t.loadBundle(locale, "", bundle)
+}
+
+func tomlKey(key string) (string, error) {
+	if strings.HasPrefix(key, `"`) || strings.HasPrefix(key, "'") {
+		return tomlString(key)
+	}
+
+	if key == "" || strings.ContainsAny(key, " \t\"'") {
+		return "", fmt.Errorf("has an invalid key %q", key)
+	}
+
+	return key, nil
+}
+
+// tomlString Basic or literal string, followed by an optional comment
+func tomlString(value string) (string, error) {
+	if strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''") {
+		return "", fmt.Errorf("has a multi-line string")
+	}
+
+	if strings.HasPrefix(value, "'") {
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 || !tomlComment(value[end+2:]) {
+			return "", fmt.Errorf("has an invalid literal string")
+		}
+
+		return value[1 : end+1], nil
+	}
+
+	if !strings.HasPrefix(value, `"`) {
+		return "", fmt.Errorf("has a value that is not a string")
+	}
+
+	for end := 1; end < len(value); end++ {
+		switch value[end] {
+		case '\\':
+			end++
+		case '"':
+			if !tomlComment(value[end+1:]) {
+				return "", fmt.Errorf("has text after the string")
+			}
+
+			unquoted, err := strconv.Unquote(value[:end+1])
+			if err != nil {
+				return "", fmt.Errorf("has an invalid string escape")
+			}
+
+			return unquoted, nil
+		}
+	}
+
+	return "", fmt.Errorf("has an unterminated string")
+}
+
+func tomlComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || rest[0] == '#'
+}
+
+// LoadFS Add the bundles of the files matching the pattern, like "locales/*.json" in an embed.FS. The locale is the
+// file name without its extension, like "pt-BR.toml", and the format its extension
+func (t *Translator) LoadFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		ext := path.Ext(file)
+		locale := Locale(strings.TrimSuffix(path.Base(file), ext))
+
+		f, err := fsys.Open(file)
+		if err != nil {
+			return err
+		}
+
+		switch ext {
+		case ".json":
+			err = t.LoadJSON(locale, f)
+		case ".toml":
+			err = t.LoadTOML(locale, f)
+		default:
+			err = fmt.Errorf("httpcord: bundle %s is not a .json or .toml file", file)
+		}
+
+		f.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadBundle Add the messages of the bundle under the key prefix, in key order so the errors are reproducible
+func (t *Translator) loadBundle(locale Locale, prefix string, bundle map[string]interface{}) error {
+	keys := make([]string, 0, len(bundle))
+	for key := range bundle {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		key := prefix + name
+
+		switch value := bundle[name].(type) {
+		case string:
+			if t.Messages[key] == nil {
+				t.Messages[key] = make(Dictionary)
+			}
+
+			t.Messages[key][locale] = value
+		case map[string]interface{}:
+			if forms, ok := pluralForms(value); ok {
+				if t.Plurals[key] == nil {
+					t.Plurals[key] = make(PluralDictionary)
+				}
+
+				t.Plurals[key][locale] = forms
+				continue
+			}
+
+			if err := t.loadBundle(locale, key+".", value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("httpcord: invalid %s bundle: %s is not a string or an object", locale, key)
+		}
+	}
+
+	return nil
+}
+
+// pluralForms Forms of the object when every key is a plural category with a string
+func pluralForms(object map[string]interface{}) (PluralForms, bool) {
+	if len(object) == 0 {
+		return nil, false
+	}
+
+	forms := make(PluralForms, len(object))
+	for category, value := range object {
+		form, ok := value.(string)
+		if !ok || !pluralCategories[category] {
+			return nil, false
+		}
+
+		forms[PluralCategory(category)] = form
+	}
+
+	return forms, true
+}
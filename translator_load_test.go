@@ -0,0 +1,158 @@
+package httpcord
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// localeFiles Bundles of en-US with every message and of fr with some of them
+func localeFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"locales/en-US.json": {Data: []byte(`{
+			"greeting": "Hello {name}!",
+			"ban": {"success": "Banned {user}", "reason": {"none": "No reason"}},
+			"warnings": {"one": "{count} warning", "other": "{count} warnings"}
+		}`)},
+		"locales/fr.toml": {Data: []byte(`# French
+greeting = "Bonjour {name} !" # inline comment
+
+[ban]
+success = 'Banni {user}'
+
+[warnings]
+one = "{count} avertissement"
+other = "{count} avertissements"
+`)},
+	}
+}
+
+func TestTranslatorLoadFS(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale)
+	if err := translator.LoadFS(localeFiles(), "locales/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key    string
+		locale Locale
+		want   string
+	}{
+		{"greeting", EnglishUSLocale, "Hello ana!"},
+		{"greeting", FrenchLocale, "Bonjour ana !"},
+		{"ban.success", FrenchLocale, "Banni ana"},
+		{"ban.reason.none", EnglishUSLocale, "No reason"},
+		// Missing in the fr bundle
+		{"ban.reason.none", FrenchLocale, "No reason"},
+		// Without a bundle
+		{"greeting", GermanLocale, "Hello ana!"},
+		{"ban.missing", FrenchLocale, "ban.missing"},
+		{"ban", EnglishUSLocale, "ban"},
+	}
+
+	for _, test := range tests {
+		if got := translator.T(test.key, test.locale, map[string]interface{}{"name": "ana", "user": "ana"}); got != test.want {
+			t.Errorf("T(%s, %s) = %q, want %q", test.key, test.locale, got, test.want)
+		}
+	}
+
+	if got := translator.TPlural("warnings", 2, FrenchLocale, map[string]interface{}{"count": 2}); got != "2 avertissements" {
+		t.Errorf("TPlural(warnings, 2, fr) = %q", got)
+	}
+
+	if got := translator.TPlural("warnings", 1, GermanLocale, map[string]interface{}{"count": 1}); got != "1 warning" {
+		t.Errorf("TPlural(warnings, 1, de) = %q, want the fallback", got)
+	}
+}
+
+func TestTranslatorLoadFSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// want Part of the error
+		want string
+	}{
+		{"json syntax", "fr.json", `{"greeting": "Bonjour",}`, "invalid fr bundle"},
+		{"json truncated", "fr.json", `{"greeting": "Bonjour"`, "invalid fr bundle"},
+		{"json array bundle", "fr.json", `["Bonjour"]`, "invalid fr bundle"},
+		{"json number", "fr.json", `{"ban": {"days": 7}}`, "ban.days is not a string or an object"},
+		{"json array", "fr.json", `{"choices": ["a", "b"]}`, "choices is not a string or an object"},
+		{"json null", "fr.json", `{"greeting": null}`, "greeting is not a string or an object"},
+		{"toml without a value", "pt-BR.toml", "greeting\n", "invalid pt-BR bundle: line 1 is not a key/value pair"},
+		{"toml number", "pt-BR.toml", "# days\ndays = 7\n", "line 2 has a value that is not a string"},
+		{"toml multi-line string", "pt-BR.toml", `greeting = """Olá"""`, "line 1 has a multi-line string"},
+		{"toml unterminated string", "pt-BR.toml", `greeting = "Olá`, "line 1 has an unterminated string"},
+		{"toml text after the string", "pt-BR.toml", `greeting = "Olá" !`, "line 1 has text after the string"},
+		{"toml invalid escape", "pt-BR.toml", `greeting = "Ol\q"`, "line 1 has an invalid string escape"},
+		{"toml unterminated literal string", "pt-BR.toml", `greeting = 'Olá`, "line 1 has an invalid literal string"},
+		{"toml array of tables", "pt-BR.toml", "[[ban]]\n", "line 1 is not a table header"},
+		{"toml unterminated table", "pt-BR.toml", "[ban\n", "line 1 is not a table header"},
+		{"toml invalid key", "pt-BR.toml", "ban reason = \"x\"\n", `line 1 has an invalid key "ban reason"`},
+		{"toml empty table name", "pt-BR.toml", "[ban.]\n", `line 1 has an invalid key ""`},
+		{"other extension", "fr.yaml", "greeting: Bonjour", "bundle locales/fr.yaml is not a .json or .toml file"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files := localeFiles()
+			files[path.Join("locales", test.file)] = &fstest.MapFile{Data: []byte(test.content)}
+
+			err := NewTranslator(EnglishUSLocale).LoadFS(files, "locales/*")
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("LoadFS() = %v, want %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestTranslatorLoadFSMissing(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale)
+
+	// No bundle matches, the messages added in code are kept
+	translator.Add("greeting", Dictionary{EnglishUSLocale: "Hello"})
+	if err := translator.LoadFS(localeFiles(), "i18n/*.json"); err != nil {
+		t.Fatalf("LoadFS() without files = %v", err)
+	}
+
+	if got := translator.T("greeting", FrenchLocale); got != "Hello" {
+		t.Errorf("T(greeting, fr) = %q, want the fallback", got)
+	}
+
+	if err := translator.LoadFS(localeFiles(), "locales/[.json"); err == nil {
+		t.Error("LoadFS() of a malformed pattern succeeded")
+	}
+
+	// The bundle of the fallback locale is missing, the keys are sent as they are
+	translator = NewTranslator(GermanLocale)
+	if err := translator.LoadFS(localeFiles(), "locales/fr.toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := translator.T("greeting", SpanishESLocale); got != "greeting" {
+		t.Errorf("T(greeting, es-ES) = %q, want the key", got)
+	}
+}
+
+func TestTranslatorLoadJSONReader(t *testing.T) {
+	translator := NewTranslator(EnglishUSLocale)
+
+	err := translator.LoadJSON(FrenchLocale, errorReader{})
+	if err == nil || !strings.Contains(err.Error(), "invalid fr bundle") {
+		t.Errorf("LoadJSON() = %v, want the read error of the bundle", err)
+	}
+
+	if err := translator.LoadTOML(FrenchLocale, errorReader{}); !errors.Is(err, errRead) {
+		t.Errorf("LoadTOML() = %v, want the read error", err)
+	}
+}
+
+var errRead = errors.New("read failed")
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
@@ -1,9 +1,93 @@
 package httpcord
 
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrWebhookThread The thread fields of a webhook message conflict
+var ErrWebhookThread = errors.New("httpcord: invalid webhook thread fields")
+
+// WebhookThreadError Thread fields of a webhook message that cannot be sent together, or a field missing its thread_name
+type WebhookThreadError struct {
+	// Fields are the JSON names of the conflicting fields, like "thread_id" and "thread_name"
+	Fields []string
+	// Requires is set when the fields need it, like "thread_name" for "applied_tags"
+	Requires string
+}
+
+func (e *WebhookThreadError) Error() string {
+	if e.Requires != "" {
+		return "httpcord: webhook field " + strings.Join(e.Fields, ", ") + " requires " + e.Requires
+	}
+
+	return "httpcord: webhook fields " + strings.Join(e.Fields, " and ") + " are mutually exclusive"
+}
+
+func (e *WebhookThreadError) Is(target error) bool {
+	return target == ErrWebhookThread
+}
+
 type WebhookEdit struct {
-	Content         string          `json:"content,omitempty"`
-	Components      *[]AnyComponent     `json:"components,omitempty"`
-	Embeds          *[]*Embed        `json:"embeds,omitempty"`
-	Files           []*DiscordFile   `json:"-"`
+	Content    string          `json:"content,omitempty"`
+	Components *[]AnyComponent `json:"components,omitempty"`
+	Embeds     *[]*Embed       `json:"embeds,omitempty"`
+	Files      []*DiscordFile  `json:"-"`
+	// Attachments are the existing attachments kept by an edit, the edit removes the others. The attachments of Files
+	// are added when sent, unless listed with their index as ID
+	Attachments     []*Attachment    `json:"attachments,omitempty"`
 	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           MessageFlag      `json:"flags,omitempty"`
+	// ThreadID sends the message in the existing thread or forum post, it is the thread_id query parameter
+	ThreadID Snowflake `json:"-"`
+	// ThreadName creates a forum post of that name with the message, only in forum and media channels
+	ThreadName string `json:"thread_name,omitempty"`
+	// AppliedTags are the tags of the forum post created with ThreadName
+	AppliedTags []Snowflake `json:"applied_tags,omitempty"`
+	// Poll is only sent with new messages, the poll of a message can not be edited
+	Poll *PollCreate `json:"poll,omitempty"`
+}
+
+// KeepAttachments Keep the existing attachments of the edited message, like the Attachments of the message returned
+// by GetOriginalInteractionResponse, next to the new Files
+func (d *WebhookEdit) KeepAttachments(attachments ...*Attachment) *WebhookEdit {
+	for _, attachment := range attachments {
+		d.Attachments = append(d.Attachments, &Attachment{ID: attachment.ID})
+	}
+
+	return d
+}
+
+// withAttachments Copy listing the attachments of the files, the data itself when there are no files
+func (d *WebhookEdit) withAttachments() *WebhookEdit {
+	if len(d.Files) == 0 {
+		return d
+	}
+
+	data := *d
+	data.Attachments = listAttachments(d.Attachments, d.Files)
+	return &data
+}
+
+// validateThread WebhookThreadError when both ThreadID and ThreadName are set, or AppliedTags without ThreadName
+func (d *WebhookEdit) validateThread() error {
+	if d.ThreadID != "" && d.ThreadName != "" {
+		return &WebhookThreadError{Fields: []string{"thread_id", "thread_name"}}
+	}
+
+	if len(d.AppliedTags) > 0 && d.ThreadName == "" {
+		return &WebhookThreadError{Fields: []string{"applied_tags"}, Requires: "thread_name"}
+	}
+
+	return nil
+}
+
+// threadQuery Query of a webhook execution with the thread_id parameter when the message targets a thread
+func threadQuery(threadID Snowflake, query url.Values) url.Values {
+	if threadID != "" {
+		query.Set("thread_id", threadID.String())
+	}
+
+	return query
 }
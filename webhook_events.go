@@ -0,0 +1,239 @@
+package httpcord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"sync"
+)
+
+// WebhookEventType Type of the events sent to the webhook events endpoint
+type WebhookEventType string
+
+// Webhook Event Types
+
+const (
+	ApplicationAuthorizedEvent   WebhookEventType = "APPLICATION_AUTHORIZED"
+	ApplicationDeauthorizedEvent WebhookEventType = "APPLICATION_DEAUTHORIZED"
+	EntitlementCreateEvent       WebhookEventType = "ENTITLEMENT_CREATE"
+	QuestUserEnrollmentEvent     WebhookEventType = "QUEST_USER_ENROLLMENT"
+)
+
+// maxWebhookEventBytes Largest body read by WebhookEventsHandler
+const maxWebhookEventBytes = 1 << 20
+
+// webhookEvent Type of the payloads holding an event, the pings of type 0 are only answered
+const webhookEvent = 1
+
+// WebhookEvent Event received by WebhookEventsHandler, Data is decoded by the accessors of its type
+type WebhookEvent struct {
+	ApplicationID Snowflake
+	Type          WebhookEventType
+	Timestamp     Time
+	Data          json.RawMessage
+}
+
+// ApplicationAuthorized Application installed to a user or a guild, Guild is set for the guild installs
+type ApplicationAuthorized struct {
+	IntegrationType ApplicationIntegrationType
+	User            *User
+	Scopes          []string
+	Guild           *Guild
+}
+
+// ApplicationDeauthorized Application removed by the user
+type ApplicationDeauthorized struct {
+	User *User
+}
+
+type webhookPayload struct {
+	Version       int       `json:"version"`
+	ApplicationID Snowflake `json:"application_id"`
+	Type          int       `json:"type"`
+	Event         *struct {
+		Type      WebhookEventType `json:"type"`
+		Timestamp Time             `json:"timestamp"`
+		Data      json.RawMessage  `json:"data"`
+	} `json:"event"`
+}
+
+// ApplicationAuthorized Data of the APPLICATION_AUTHORIZED event, false for other events or when it can not be decoded
+func (e *WebhookEvent) ApplicationAuthorized() (*ApplicationAuthorized, bool) {
+	var data struct {
+		IntegrationType ApplicationIntegrationType `json:"integration_type"`
+		User            *APIUser                   `json:"user"`
+		Scopes          []string                   `json:"scopes"`
+		Guild           *Guild                     `json:"guild"`
+	}
+
+	if e.Type != ApplicationAuthorizedEvent || json.Unmarshal(e.Data, &data) != nil || data.User == nil {
+		return nil, false
+	}
+
+	return &ApplicationAuthorized{IntegrationType: data.IntegrationType, User: ResolveUser(data.User), Scopes: data.Scopes, Guild: data.Guild}, true
+}
+
+// ApplicationDeauthorized Data of the APPLICATION_DEAUTHORIZED event, false for other events or when it can not be decoded
+func (e *WebhookEvent) ApplicationDeauthorized() (*ApplicationDeauthorized, bool) {
+	var data struct {
+		User *APIUser `json:"user"`
+	}
+
+	if e.Type != ApplicationDeauthorizedEvent || json.Unmarshal(e.Data, &data) != nil || data.User == nil {
+		return nil, false
+	}
+
+	return &ApplicationDeauthorized{User: ResolveUser(data.User)}, true
+}
+
+// Entitlement Entitlement of the ENTITLEMENT_CREATE event, false for other events or when it can not be decoded
+func (e *WebhookEvent) Entitlement() (*Entitlement, bool) {
+	var entitlement Entitlement
+
+	if e.Type != EntitlementCreateEvent || json.Unmarshal(e.Data, &entitlement) != nil {
+		return nil, false
+	}
+
+	return &entitlement, true
+}
+
+// WebhookEventHandler Handler of the webhook events, ctx is not bound to the request answered before it runs and is
+// canceled once Shutdown starts
+type WebhookEventHandler func(ctx context.Context, event *WebhookEvent)
+
+// webhookEventHandlers Handlers of OnWebhookEvent, shared by the copies of the connection
+type webhookEventHandlers struct {
+	mu     sync.RWMutex
+	byType map[WebhookEventType][]WebhookEventHandler
+}
+
+func (h *webhookEventHandlers) add(eventType WebhookEventType, handler WebhookEventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.byType == nil {
+		h.byType = make(map[WebhookEventType][]WebhookEventHandler)
+	}
+
+	h.byType[eventType] = append(h.byType[eventType], handler)
+}
+
+func (h *webhookEventHandlers) get(eventType WebhookEventType) []WebhookEventHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]WebhookEventHandler(nil), h.byType[eventType]...)
+}
+
+// OnWebhookEvent Run the handler for the events of the type received by WebhookEventsHandler
+func (c *Connection) OnWebhookEvent(eventType WebhookEventType, handler WebhookEventHandler) {
+	c.events.add(eventType, handler)
+}
+
+// OnApplicationAuthorized Run the handler when the application is installed to a user or a guild
+func (c *Connection) OnApplicationAuthorized(handler func(ctx context.Context, event *ApplicationAuthorized)) {
+	c.OnWebhookEvent(ApplicationAuthorizedEvent, func(ctx context.Context, event *WebhookEvent) {
+		if data, ok := event.ApplicationAuthorized(); ok {
+			handler(ctx, data)
+		}
+	})
+}
+
+// OnApplicationDeauthorized Run the handler when a user removes the application
+func (c *Connection) OnApplicationDeauthorized(handler func(ctx context.Context, event *ApplicationDeauthorized)) {
+	c.OnWebhookEvent(ApplicationDeauthorizedEvent, func(ctx context.Context, event *WebhookEvent) {
+		if data, ok := event.ApplicationDeauthorized(); ok {
+			handler(ctx, data)
+		}
+	})
+}
+
+// OnEntitlementCreate Run the handler when a user or a guild gets an entitlement, like a purchased SKU
+func (c *Connection) OnEntitlementCreate(handler func(ctx context.Context, entitlement *Entitlement)) {
+	c.OnWebhookEvent(EntitlementCreateEvent, func(ctx context.Context, event *WebhookEvent) {
+		if entitlement, ok := event.Entitlement(); ok {
+			handler(ctx, entitlement)
+		}
+	})
+}
+
+// WebhookEventsHandler Handler of the webhook events endpoint of the application, mounted on another path than the
+// interactions endpoint. Requests are verified with the public keys of the connection and answered with 204 before
+// the handlers of OnWebhookEvent run in the background, tracked by Shutdown. Panics of the handlers are logged
+func (c *Connection) WebhookEventsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !c.life.begin() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		defer c.life.end()
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookEventBytes))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := c.verifier.verify(r, body); err != nil {
+			c.logger.Info("webhook event signature refused", "remote", r.RemoteAddr, "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			c.logger.Warn("malformed webhook event refused", "remote", r.RemoteAddr, "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if payload.Type == webhookEvent && payload.Event == nil {
+			c.logger.Warn("malformed webhook event refused", "remote", r.RemoteAddr, "error", "missing event")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		if payload.Type != webhookEvent {
+			return
+		}
+
+		event := &WebhookEvent{
+			ApplicationID: payload.ApplicationID,
+			Type:          payload.Event.Type,
+			Timestamp:     payload.Event.Timestamp,
+			Data:          payload.Event.Data,
+		}
+
+		handlers := c.events.get(event.Type)
+		if len(handlers) == 0 {
+			c.logger.Debug("webhook event without handlers", "type", event.Type)
+			return
+		}
+
+		c.life.background(func() {
+			for _, handler := range handlers {
+				c.runWebhookEvent(handler, event)
+			}
+		})
+	}
+}
+
+func (c *Connection) runWebhookEvent(handler WebhookEventHandler, event *WebhookEvent) {
+	defer func() {
+		if v := recover(); v != nil {
+			c.logger.Error("webhook event handler panicked", "type", event.Type, "panic", fmt.Sprint(v), "stack", string(debug.Stack()))
+		}
+	}()
+
+	handler(c.life.context(), event)
+}
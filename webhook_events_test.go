@@ -0,0 +1,134 @@
+package httpcord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// webhookEventBody Payload of the event of the type with the data, as Discord sends it
+func webhookEventBody(eventType WebhookEventType, data string) []byte {
+	return []byte(`{"version":1,"application_id":"1","type":1,"event":{"type":"` + string(eventType) +
+		`","timestamp":"2024-10-18T14:42:53.064834","data":` + data + `}}`)
+}
+
+func TestWebhookEventsHandler(t *testing.T) {
+	authorized := webhookEventBody(ApplicationAuthorizedEvent, `{"integration_type":0,"scopes":["applications.commands","bot"],`+
+		`"user":{"id":"4","username":"owner"},"guild":{"id":"2","name":"Guild"}}`)
+	entitlement := webhookEventBody(EntitlementCreateEvent, `{"id":"9","sku_id":"8","application_id":"1","user_id":"4","type":8,`+
+		`"deleted":false,"starts_at":null,"ends_at":null}`)
+
+	tests := []struct {
+		name   string
+		req    func(sign func(body []byte) *http.Request) *http.Request
+		status int
+		// received Handlers run with what they received, in their order
+		received string
+		logged   string
+	}{
+		{"application authorized", func(sign func([]byte) *http.Request) *http.Request { return sign(authorized) }, http.StatusNoContent,
+			"any APPLICATION_AUTHORIZED 1,authorized 4 2 applications.commands bot", ""},
+		{"application deauthorized", func(sign func([]byte) *http.Request) *http.Request {
+			return sign(webhookEventBody(ApplicationDeauthorizedEvent, `{"user":{"id":"4","username":"owner"}}`))
+		}, http.StatusNoContent, "deauthorized 4", ""},
+		{"entitlement", func(sign func([]byte) *http.Request) *http.Request { return sign(entitlement) }, http.StatusNoContent,
+			"entitlement 9 8 4", ""},
+		{"unknown event", func(sign func([]byte) *http.Request) *http.Request {
+			return sign(webhookEventBody("LOBBY_MESSAGE_CREATE", `{"id":"5"}`))
+		}, http.StatusNoContent, "", "webhook event without handlers"},
+		{"event without handlers", func(sign func([]byte) *http.Request) *http.Request {
+			return sign(webhookEventBody(QuestUserEnrollmentEvent, `{}`))
+		}, http.StatusNoContent, "", "webhook event without handlers"},
+		{"ping", func(sign func([]byte) *http.Request) *http.Request {
+			return sign([]byte(`{"version":1,"application_id":"1","type":0}`))
+		}, http.StatusNoContent, "", ""},
+		{"event missing", func(sign func([]byte) *http.Request) *http.Request {
+			return sign([]byte(`{"version":1,"application_id":"1","type":1}`))
+		}, http.StatusBadRequest, "", "missing event"},
+		{"malformed", func(sign func([]byte) *http.Request) *http.Request { return sign([]byte(`{"type":`)) }, http.StatusBadRequest, "",
+			"malformed webhook event refused"},
+		{"bad signature", func(sign func([]byte) *http.Request) *http.Request {
+			r := sign(authorized)
+			r.Header.Set("X-Signature-Ed25519", strings.Repeat("0", 128))
+			return r
+		}, http.StatusUnauthorized, "", "webhook event signature refused"},
+		{"body changed after signing", func(sign func([]byte) *http.Request) *http.Request {
+			r := sign(authorized)
+			r.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(entitlement))).Body
+			return r
+		}, http.StatusUnauthorized, "", "webhook event signature refused"},
+		{"not a POST", func(sign func([]byte) *http.Request) *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/events", nil)
+		}, http.StatusMethodNotAllowed, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logs := &syncBuffer{}
+			conn, sign := signedConnection(t, ConnectionOptions{Logger: NewLogger(logs, LogLevelDebug)})
+
+			var received []string
+			conn.OnWebhookEvent(ApplicationAuthorizedEvent, func(ctx context.Context, event *WebhookEvent) {
+				received = append(received, "any "+string(event.Type)+" "+string(event.ApplicationID))
+			})
+
+			conn.OnApplicationAuthorized(func(ctx context.Context, event *ApplicationAuthorized) {
+				received = append(received, "authorized "+string(event.User.ID)+" "+string(event.Guild.ID)+" "+strings.Join(event.Scopes, " "))
+			})
+
+			conn.OnApplicationDeauthorized(func(ctx context.Context, event *ApplicationDeauthorized) {
+				received = append(received, "deauthorized "+string(event.User.ID))
+			})
+
+			conn.OnEntitlementCreate(func(ctx context.Context, entitlement *Entitlement) {
+				received = append(received, "entitlement "+string(entitlement.ID)+" "+string(entitlement.SkuID)+" "+string(entitlement.UserID))
+			})
+
+			w := httptest.NewRecorder()
+			conn.WebhookEventsHandler()(w, test.req(sign))
+
+			// Shutdown waits for the handlers running in the background
+			if err := conn.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if w.Code != test.status {
+				t.Errorf("status %d, want %d", w.Code, test.status)
+			}
+
+			if got := strings.Join(received, ","); got != test.received {
+				t.Errorf("handlers received %q, want %q", got, test.received)
+			}
+
+			if !strings.Contains(logs.String(), test.logged) {
+				t.Errorf("logged %q, want %q", logs.String(), test.logged)
+			}
+		})
+	}
+}
+
+func TestWebhookEventHandlerPanic(t *testing.T) {
+	logs := &syncBuffer{}
+	conn, sign := signedConnection(t, ConnectionOptions{Logger: NewLogger(logs, LogLevelDebug)})
+
+	var ran bool
+	conn.OnWebhookEvent(EntitlementCreateEvent, func(context.Context, *WebhookEvent) { panic("boom") })
+	conn.OnWebhookEvent(EntitlementCreateEvent, func(context.Context, *WebhookEvent) { ran = true })
+
+	w := httptest.NewRecorder()
+	conn.WebhookEventsHandler()(w, sign(webhookEventBody(EntitlementCreateEvent, `{"id":"9"}`)))
+
+	if err := conn.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusNoContent || !ran {
+		t.Errorf("status %d, next handler ran %v, want 204 and the handlers after the panic run", w.Code, ran)
+	}
+
+	if !strings.Contains(logs.String(), "webhook event handler panicked") {
+		t.Errorf("logged %q, want the panic", logs.String())
+	}
+}
@@ -0,0 +1,103 @@
+package httpcord_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"httpcord"
+	"httpcord/httpcordtest"
+)
+
+func TestWebhookThreads(t *testing.T) {
+	execute := func(client *httpcord.RestClient, data *httpcord.WebhookEdit) error {
+		_, err := client.ExecuteWebhook(context.Background(), "8", "webhook-token", data)
+		return err
+	}
+
+	followUp := func(client *httpcord.RestClient, data *httpcord.WebhookEdit) error {
+		_, err := client.CreateFollowUpMessage(context.Background(), "1", "token", data)
+		return err
+	}
+
+	tests := []struct {
+		name string
+		send func(client *httpcord.RestClient, data *httpcord.WebhookEdit) error
+		data *httpcord.WebhookEdit
+		// query Query of the request, nil when the request is refused before being sent
+		query url.Values
+		// body Thread fields of the JSON body
+		body map[string]interface{}
+		err  *httpcord.WebhookThreadError
+	}{
+		{"execution in a thread", execute, &httpcord.WebhookEdit{Content: "hi", ThreadID: "77"},
+			url.Values{"wait": {"true"}, "thread_id": {"77"}}, map[string]interface{}{}, nil},
+		{"execution creating a forum post", execute, &httpcord.WebhookEdit{Content: "hi", ThreadName: "Bug report", AppliedTags: []httpcord.Snowflake{"5", "6"}},
+			url.Values{"wait": {"true"}}, map[string]interface{}{"thread_name": "Bug report", "applied_tags": []interface{}{"5", "6"}}, nil},
+		{"execution in the channel", execute, &httpcord.WebhookEdit{Content: "hi"}, url.Values{"wait": {"true"}}, map[string]interface{}{}, nil},
+		{"follow-up in a thread", followUp, &httpcord.WebhookEdit{Content: "hi", ThreadID: "77"}, url.Values{"thread_id": {"77"}},
+			map[string]interface{}{}, nil},
+		{"follow-up creating a forum post", followUp, &httpcord.WebhookEdit{Content: "hi", ThreadName: "Bug report"}, url.Values{},
+			map[string]interface{}{"thread_name": "Bug report"}, nil},
+		{"thread and forum post", execute, &httpcord.WebhookEdit{Content: "hi", ThreadID: "77", ThreadName: "Bug report"}, nil, nil,
+			&httpcord.WebhookThreadError{Fields: []string{"thread_id", "thread_name"}}},
+		{"follow-up in a thread and a forum post", followUp, &httpcord.WebhookEdit{Content: "hi", ThreadID: "77", ThreadName: "Bug report"}, nil, nil,
+			&httpcord.WebhookThreadError{Fields: []string{"thread_id", "thread_name"}}},
+		{"tags without a forum post", execute, &httpcord.WebhookEdit{Content: "hi", ThreadID: "77", AppliedTags: []httpcord.Snowflake{"5"}}, nil, nil,
+			&httpcord.WebhookThreadError{Fields: []string{"applied_tags"}, Requires: "thread_name"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := httpcordtest.NewFakeDiscord(t)
+			fake.Allow(http.MethodPost, "/webhooks/*/*").RespondWith(httpcord.Message{ID: "9"})
+
+			err := test.send(fake.Client(), test.data)
+
+			requests := fake.Requests()
+			if test.err != nil {
+				var threadErr *httpcord.WebhookThreadError
+				if !errors.Is(err, httpcord.ErrWebhookThread) || !errors.As(err, &threadErr) || !reflect.DeepEqual(threadErr, test.err) {
+					t.Errorf("error %v, want %v", err, test.err)
+				}
+
+				if len(requests) != 0 {
+					t.Errorf("%d requests sent, want the message refused before", len(requests))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(requests) != 1 {
+				t.Fatalf("%d requests sent, want 1", len(requests))
+			}
+
+			if query := requests[0].Query; !reflect.DeepEqual(query, test.query) && (len(query) > 0 || len(test.query) > 0) {
+				t.Errorf("query %v, want %v", query, test.query)
+			}
+
+			var body map[string]interface{}
+			if err := requests[0].Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			thread := make(map[string]interface{})
+			for _, field := range []string{"thread_id", "thread_name", "applied_tags"} {
+				if value, ok := body[field]; ok {
+					thread[field] = value
+				}
+			}
+
+			if !reflect.DeepEqual(thread, test.body) {
+				t.Errorf("thread fields of the body %v, want %v", thread, test.body)
+			}
+		})
+	}
+}